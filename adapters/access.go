@@ -20,13 +20,20 @@ package adapters
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
 	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/flow-emulator/emulator"
 	"github.com/onflow/flow-emulator/types"
 	"github.com/onflow/flow-emulator/utils"
+	"github.com/onflow/flow-emulator/utils/eventencoding"
+	"github.com/onflow/flow-emulator/utils/pending"
+	"github.com/onflow/flow-emulator/utils/requestid"
+	"github.com/onflow/flow-emulator/utils/timetravel"
 	"github.com/rs/zerolog"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -41,6 +48,8 @@ var _ access.API = &AccessAdapter{}
 type AccessAdapter struct {
 	logger   *zerolog.Logger
 	emulator emulator.Emulator
+	// sessions are the heights at which client sessions query the latest state
+	sessions *timetravel.Sessions
 }
 
 // NewAccessAdapter returns a new AccessAdapter.
@@ -48,16 +57,36 @@ func NewAccessAdapter(logger *zerolog.Logger, emulator emulator.Emulator) *Acces
 	return &AccessAdapter{
 		logger:   logger,
 		emulator: emulator,
+		sessions: timetravel.NewSessions(),
 	}
 }
 
+// Sessions returns the heights chosen by client sessions. Queries of the latest
+// block, accounts and scripts of a session are served at the height it chose.
+func (a *AccessAdapter) Sessions() *timetravel.Sessions {
+	return a.sessions
+}
+
+// latestBlock returns the latest block, or the block at the height chosen by
+// the session of the context.
+func (a *AccessAdapter) latestBlock(ctx context.Context) (*flowgo.Block, error) {
+	if height, ok := a.sessions.HeightFromContext(ctx); ok {
+		return a.emulator.GetBlockByHeight(ctx, height)
+	}
+	return a.emulator.GetLatestBlock(ctx)
+}
+
 func convertError(err error) error {
 	if err != nil {
-		switch err.(type) {
+		switch typedErr := err.(type) {
 		case types.InvalidArgumentError:
 			return status.Error(codes.InvalidArgument, err.Error())
 		case types.NotFoundError:
 			return status.Error(codes.NotFound, err.Error())
+		case *types.ReadOnlyError:
+			return status.Error(codes.FailedPrecondition, err.Error())
+		case *types.InvalidSequenceNumberError:
+			return sequenceNumberError(typedErr)
 		default:
 			return status.Error(codes.Internal, err.Error())
 		}
@@ -65,21 +94,42 @@ func convertError(err error) error {
 	return nil
 }
 
-func (a *AccessAdapter) Ping(_ context.Context) error {
+// sequenceNumberError converts an invalid sequence number error into a status
+// error, detailing the expected and provided sequence numbers so that clients
+// can retry without fetching the account.
+func sequenceNumberError(err *types.InvalidSequenceNumberError) error {
+	st, detailsErr := status.New(codes.InvalidArgument, err.Error()).
+		WithDetails(&errdetails.ErrorInfo{
+			Reason: string(types.ErrorCodeInvalidSequenceNumber),
+			Domain: "flow-emulator",
+			Metadata: map[string]string{
+				"address":                err.Address.HexWithPrefix(),
+				"keyIndex":               strconv.FormatUint(err.KeyIndex, 10),
+				"expectedSequenceNumber": strconv.FormatUint(err.Expected, 10),
+				"providedSequenceNumber": strconv.FormatUint(err.Provided, 10),
+			},
+		})
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
+
+func (a *AccessAdapter) Ping(ctx context.Context) error {
 	return convertError(a.emulator.Ping())
 }
 
-func (a *AccessAdapter) GetNetworkParameters(_ context.Context) access.NetworkParameters {
+func (a *AccessAdapter) GetNetworkParameters(ctx context.Context) access.NetworkParameters {
 	return a.emulator.GetNetworkParameters()
 }
 
-func (a *AccessAdapter) GetLatestBlockHeader(_ context.Context, _ bool) (*flowgo.Header, flowgo.BlockStatus, error) {
-	block, err := a.emulator.GetLatestBlock()
+func (a *AccessAdapter) GetLatestBlockHeader(ctx context.Context, _ bool) (*flowgo.Header, flowgo.BlockStatus, error) {
+	block, err := a.latestBlock(ctx)
 	if err != nil {
 		return nil, flowgo.BlockStatusUnknown, convertError(err)
 	}
 
-	a.logger.Debug().Fields(map[string]any{
+	requestid.Logger(ctx, a.logger).Debug().Fields(map[string]any{
 		"blockHeight": block.Header.Height,
 		"blockID":     block.ID().String(),
 	}).Msg("🎁  GetLatestBlockHeader called")
@@ -87,13 +137,13 @@ func (a *AccessAdapter) GetLatestBlockHeader(_ context.Context, _ bool) (*flowgo
 	return block.Header, flowgo.BlockStatusSealed, nil
 }
 
-func (a *AccessAdapter) GetBlockHeaderByHeight(_ context.Context, height uint64) (*flowgo.Header, flowgo.BlockStatus, error) {
-	block, err := a.emulator.GetBlockByHeight(height)
+func (a *AccessAdapter) GetBlockHeaderByHeight(ctx context.Context, height uint64) (*flowgo.Header, flowgo.BlockStatus, error) {
+	block, err := a.emulator.GetBlockByHeight(ctx, height)
 	if err != nil {
 		return nil, flowgo.BlockStatusUnknown, convertError(err)
 	}
 
-	a.logger.Debug().Fields(map[string]any{
+	requestid.Logger(ctx, a.logger).Debug().Fields(map[string]any{
 		"blockHeight": block.Header.Height,
 		"blockID":     block.ID().String(),
 	}).Msg("🎁  GetBlockHeaderByHeight called")
@@ -101,13 +151,13 @@ func (a *AccessAdapter) GetBlockHeaderByHeight(_ context.Context, height uint64)
 	return block.Header, flowgo.BlockStatusSealed, nil
 }
 
-func (a *AccessAdapter) GetBlockHeaderByID(_ context.Context, id flowgo.Identifier) (*flowgo.Header, flowgo.BlockStatus, error) {
-	block, err := a.emulator.GetBlockByID(id)
+func (a *AccessAdapter) GetBlockHeaderByID(ctx context.Context, id flowgo.Identifier) (*flowgo.Header, flowgo.BlockStatus, error) {
+	block, err := a.emulator.GetBlockByID(ctx, id)
 	if err != nil {
 		return nil, flowgo.BlockStatusUnknown, convertError(err)
 	}
 
-	a.logger.Debug().Fields(map[string]any{
+	requestid.Logger(ctx, a.logger).Debug().Fields(map[string]any{
 		"blockHeight": block.Header.Height,
 		"blockID":     block.ID().String(),
 	}).Msg("🎁  GetBlockHeaderByID called")
@@ -115,13 +165,13 @@ func (a *AccessAdapter) GetBlockHeaderByID(_ context.Context, id flowgo.Identifi
 	return block.Header, flowgo.BlockStatusSealed, nil
 }
 
-func (a *AccessAdapter) GetLatestBlock(_ context.Context, _ bool) (*flowgo.Block, flowgo.BlockStatus, error) {
-	block, err := a.emulator.GetLatestBlock()
+func (a *AccessAdapter) GetLatestBlock(ctx context.Context, _ bool) (*flowgo.Block, flowgo.BlockStatus, error) {
+	block, err := a.latestBlock(ctx)
 	if err != nil {
 		return nil, flowgo.BlockStatusUnknown, convertError(err)
 	}
 
-	a.logger.Debug().Fields(map[string]any{
+	requestid.Logger(ctx, a.logger).Debug().Fields(map[string]any{
 		"blockHeight": block.Header.Height,
 		"blockID":     block.ID().String(),
 	}).Msg("🎁  GetLatestBlock called")
@@ -129,13 +179,13 @@ func (a *AccessAdapter) GetLatestBlock(_ context.Context, _ bool) (*flowgo.Block
 	return block, flowgo.BlockStatusSealed, nil
 }
 
-func (a *AccessAdapter) GetBlockByHeight(_ context.Context, height uint64) (*flowgo.Block, flowgo.BlockStatus, error) {
-	block, err := a.emulator.GetBlockByHeight(height)
+func (a *AccessAdapter) GetBlockByHeight(ctx context.Context, height uint64) (*flowgo.Block, flowgo.BlockStatus, error) {
+	block, err := a.emulator.GetBlockByHeight(ctx, height)
 	if err != nil {
 		return nil, flowgo.BlockStatusUnknown, convertError(err)
 	}
 
-	a.logger.Debug().Fields(map[string]any{
+	requestid.Logger(ctx, a.logger).Debug().Fields(map[string]any{
 		"blockHeight": block.Header.Height,
 		"blockID":     block.ID().String(),
 	}).Msg("🎁  GetBlockByHeight called")
@@ -143,13 +193,13 @@ func (a *AccessAdapter) GetBlockByHeight(_ context.Context, height uint64) (*flo
 	return block, flowgo.BlockStatusSealed, nil
 }
 
-func (a *AccessAdapter) GetBlockByID(_ context.Context, id flowgo.Identifier) (*flowgo.Block, flowgo.BlockStatus, error) {
-	block, err := a.emulator.GetBlockByID(id)
+func (a *AccessAdapter) GetBlockByID(ctx context.Context, id flowgo.Identifier) (*flowgo.Block, flowgo.BlockStatus, error) {
+	block, err := a.emulator.GetBlockByID(ctx, id)
 	if err != nil {
 		return nil, flowgo.BlockStatusUnknown, convertError(err)
 	}
 
-	a.logger.Debug().Fields(map[string]any{
+	requestid.Logger(ctx, a.logger).Debug().Fields(map[string]any{
 		"blockHeight": block.Header.Height,
 		"blockID":     block.ID().String(),
 	}).Msg("🎁  GetBlockByID called")
@@ -157,26 +207,26 @@ func (a *AccessAdapter) GetBlockByID(_ context.Context, id flowgo.Identifier) (*
 	return block, flowgo.BlockStatusSealed, nil
 }
 
-func (a *AccessAdapter) GetCollectionByID(_ context.Context, id flowgo.Identifier) (*flowgo.LightCollection, error) {
-	collection, err := a.emulator.GetCollectionByID(id)
+func (a *AccessAdapter) GetCollectionByID(ctx context.Context, id flowgo.Identifier) (*flowgo.LightCollection, error) {
+	collection, err := a.emulator.GetCollectionByID(ctx, id)
 	if err != nil {
 		return nil, convertError(err)
 	}
 
-	a.logger.Debug().
+	requestid.Logger(ctx, a.logger).Debug().
 		Str("colID", id.String()).
 		Msg("📚  GetCollectionByID called")
 
 	return collection, nil
 }
 
-func (a *AccessAdapter) GetTransaction(_ context.Context, id flowgo.Identifier) (*flowgo.TransactionBody, error) {
-	tx, err := a.emulator.GetTransaction(id)
+func (a *AccessAdapter) GetTransaction(ctx context.Context, id flowgo.Identifier) (*flowgo.TransactionBody, error) {
+	tx, err := a.emulator.GetTransaction(ctx, id)
 	if err != nil {
 		return nil, convertError(err)
 	}
 
-	a.logger.Debug().
+	requestid.Logger(ctx, a.logger).Debug().
 		Str("txID", id.String()).
 		Msg("💵  GetTransaction called")
 
@@ -184,7 +234,7 @@ func (a *AccessAdapter) GetTransaction(_ context.Context, id flowgo.Identifier)
 }
 
 func (a *AccessAdapter) GetTransactionResult(
-	_ context.Context,
+	ctx context.Context,
 	id flowgo.Identifier,
 	_ flowgo.Identifier,
 	_ flowgo.Identifier,
@@ -192,30 +242,52 @@ func (a *AccessAdapter) GetTransactionResult(
 	*access.TransactionResult,
 	error,
 ) {
-	result, err := a.emulator.GetTransactionResult(id)
+	result, err := a.emulator.GetTransactionResult(ctx, id)
 	if err != nil {
 		return nil, convertError(err)
 	}
 
-	result.Events, err = ConvertCCFEventsToJsonEvents(result.Events)
+	result.Events, err = encodeEvents(ctx, result.Events)
 	if err != nil {
 		return nil, convertError(err)
 	}
 
-	a.logger.Debug().
+	requestid.Logger(ctx, a.logger).Debug().
 		Str("txID", id.String()).
 		Msg("📝  GetTransactionResult called")
 
 	return result, nil
 }
 
-func (a *AccessAdapter) GetAccount(_ context.Context, address flowgo.Address) (*flowgo.Account, error) {
-	account, err := a.emulator.GetAccount(address)
+// GetTransactionLogs returns the Cadence logs of a committed transaction.
+func (a *AccessAdapter) GetTransactionLogs(ctx context.Context, id flowgo.Identifier) ([]string, error) {
+	logs, err := a.emulator.GetTransactionLogs(ctx, id)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	requestid.Logger(ctx, a.logger).Debug().
+		Str("txID", id.String()).
+		Msg("📝  GetTransactionLogs called")
+
+	return logs, nil
+}
+
+func (a *AccessAdapter) GetAccount(ctx context.Context, address flowgo.Address) (*flowgo.Account, error) {
+	if pending.FromContext(ctx) {
+		return a.GetAccountAtPendingBlock(ctx, address)
+	}
+
+	if height, ok := a.sessions.HeightFromContext(ctx); ok {
+		return a.GetAccountAtBlockHeight(ctx, address, height)
+	}
+
+	account, err := a.emulator.GetAccount(ctx, address)
 	if err != nil {
 		return nil, convertError(err)
 	}
 
-	a.logger.Debug().
+	requestid.Logger(ctx, a.logger).Debug().
 		Stringer("address", address).
 		Msg("👤  GetAccount called")
 
@@ -228,7 +300,7 @@ func (a *AccessAdapter) GetAccountAtLatestBlock(ctx context.Context, address flo
 		return nil, convertError(err)
 	}
 
-	a.logger.Debug().
+	requestid.Logger(ctx, a.logger).Debug().
 		Stringer("address", address).
 		Msg("👤  GetAccountAtLatestBlock called")
 
@@ -236,17 +308,62 @@ func (a *AccessAdapter) GetAccountAtLatestBlock(ctx context.Context, address flo
 }
 
 func (a *AccessAdapter) GetAccountAtBlockHeight(
-	_ context.Context,
+	ctx context.Context,
 	address flowgo.Address,
 	height uint64,
 ) (*flowgo.Account, error) {
+	// the REST API resolves the latest block before querying accounts
+	if pending.FromContext(ctx) {
+		latestBlock, err := a.emulator.GetLatestBlock(ctx)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		if latestBlock.Header.Height == height {
+			return a.GetAccountAtPendingBlock(ctx, address)
+		}
+	}
 
-	a.logger.Debug().
+	requestid.Logger(ctx, a.logger).Debug().
 		Stringer("address", address).
 		Uint64("height", height).
 		Msg("👤  GetAccountAtBlockHeight called")
 
-	account, err := a.emulator.GetAccountAtBlockHeight(address, height)
+	account, err := a.emulator.GetAccountAtBlockHeight(ctx, address, height)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return account, nil
+}
+
+// GetRegisterValues returns the raw values of the given registers at the given block height.
+// The Access API has no method for it, so it is served by the emulator specific
+// registers route over REST and the registers service over gRPC.
+func (a *AccessAdapter) GetRegisterValues(
+	ctx context.Context,
+	registerIDs flowgo.RegisterIDs,
+	height uint64,
+) ([]flowgo.RegisterValue, error) {
+	requestid.Logger(ctx, a.logger).Debug().
+		Int("registers", len(registerIDs)).
+		Uint64("height", height).
+		Msg("🗃  GetRegisterValues called")
+
+	values, err := a.emulator.GetRegisterValues(ctx, registerIDs, height)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return values, nil
+}
+
+// GetAccountAtPendingBlock returns the account as it is once the transactions
+// of the pending block are executed.
+func (a *AccessAdapter) GetAccountAtPendingBlock(ctx context.Context, address flowgo.Address) (*flowgo.Account, error) {
+
+	requestid.Logger(ctx, a.logger).Debug().
+		Stringer("address", address).
+		Msg("👤  GetAccountAtPendingBlock called")
+
+	account, err := a.emulator.GetAccountAtPendingBlock(ctx, address)
 	if err != nil {
 		return nil, convertError(err)
 	}
@@ -254,6 +371,14 @@ func (a *AccessAdapter) GetAccountAtBlockHeight(
 }
 
 func convertScriptResult(result *types.ScriptResult, err error) ([]byte, error) {
+	var interruptedErr *types.ScriptInterruptedError
+	if errors.As(err, &interruptedErr) {
+		if errors.Is(interruptedErr, context.Canceled) {
+			return nil, status.Error(codes.Canceled, err.Error())
+		}
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
@@ -271,76 +396,101 @@ func convertScriptResult(result *types.ScriptResult, err error) ([]byte, error)
 }
 
 func (a *AccessAdapter) ExecuteScriptAtLatestBlock(
-	_ context.Context,
+	ctx context.Context,
+	script []byte,
+	arguments [][]byte,
+) ([]byte, error) {
+	if pending.FromContext(ctx) {
+		return a.ExecuteScriptAtPendingBlock(ctx, script, arguments)
+	}
+
+	if height, ok := a.sessions.HeightFromContext(ctx); ok {
+		return a.ExecuteScriptAtBlockHeight(ctx, height, script, arguments)
+	}
+
+	requestid.Logger(ctx, a.logger).Debug().Msg("👤  ExecuteScriptAtLatestBlock called")
+	result, err := a.emulator.ExecuteScript(ctx, script, arguments)
+	if err == nil {
+		utils.PrintScriptResult(requestid.Logger(ctx, a.logger), result)
+	}
+	return convertScriptResult(result, err)
+}
+
+// ExecuteScriptAtPendingBlock executes a script against the state as it is once
+// the transactions of the pending block are executed.
+func (a *AccessAdapter) ExecuteScriptAtPendingBlock(
+	ctx context.Context,
 	script []byte,
 	arguments [][]byte,
 ) ([]byte, error) {
-	a.logger.Debug().Msg("👤  ExecuteScriptAtLatestBlock called")
-	result, err := a.emulator.ExecuteScript(script, arguments)
+
+	requestid.Logger(ctx, a.logger).Debug().Msg("👤  ExecuteScriptAtPendingBlock called")
+
+	result, err := a.emulator.ExecuteScriptAtPendingBlock(ctx, script, arguments)
 	if err == nil {
-		utils.PrintScriptResult(a.logger, result)
+		utils.PrintScriptResult(requestid.Logger(ctx, a.logger), result)
 	}
 	return convertScriptResult(result, err)
 }
 
 func (a *AccessAdapter) ExecuteScriptAtBlockHeight(
-	_ context.Context,
+	ctx context.Context,
 	blockHeight uint64,
 	script []byte,
 	arguments [][]byte,
 ) ([]byte, error) {
 
-	a.logger.Debug().
+	requestid.Logger(ctx, a.logger).Debug().
 		Uint64("blockHeight", blockHeight).
 		Msg("👤  ExecuteScriptAtBlockHeight called")
 
-	result, err := a.emulator.ExecuteScriptAtBlockHeight(script, arguments, blockHeight)
+	result, err := a.emulator.ExecuteScriptAtBlockHeight(ctx, script, arguments, blockHeight)
 	if err == nil {
-		utils.PrintScriptResult(a.logger, result)
+		utils.PrintScriptResult(requestid.Logger(ctx, a.logger), result)
 	}
 	return convertScriptResult(result, err)
 }
 
 func (a *AccessAdapter) ExecuteScriptAtBlockID(
-	_ context.Context,
+	ctx context.Context,
 	blockID flowgo.Identifier,
 	script []byte,
 	arguments [][]byte,
 ) ([]byte, error) {
 
-	a.logger.Debug().
+	requestid.Logger(ctx, a.logger).Debug().
 		Stringer("blockID", blockID).
 		Msg("👤  ExecuteScriptAtBlockID called")
 
-	result, err := a.emulator.ExecuteScriptAtBlockID(script, arguments, blockID)
+	result, err := a.emulator.ExecuteScriptAtBlockID(ctx, script, arguments, blockID)
 	if err == nil {
-		utils.PrintScriptResult(a.logger, result)
+		utils.PrintScriptResult(requestid.Logger(ctx, a.logger), result)
 	}
 	return convertScriptResult(result, err)
 }
 
 func (a *AccessAdapter) GetEventsForHeightRange(
-	_ context.Context,
+	ctx context.Context,
 	eventType string,
 	startHeight, endHeight uint64,
 ) ([]flowgo.BlockEvents, error) {
-	events, err := a.emulator.GetEventsForHeightRange(eventType, startHeight, endHeight)
+	events, err := a.emulator.GetEventsForHeightRange(ctx, eventType, startHeight, endHeight)
 	if err != nil {
 		return nil, convertError(err)
 	}
 
 	eventCount := 0
 
-	// Convert CCF events to JSON events
+	// Convert CCF events to the encoding chosen by the request
 	for i := range events {
-		events[i].Events, err = ConvertCCFEventsToJsonEvents(events[i].Events)
+		events[i].Events, err = encodeEvents(ctx, events[i].Events)
 		eventCount = eventCount + len(events[i].Events)
 		if err != nil {
 			return nil, convertError(err)
 		}
 	}
 
-	a.logger.Debug().Fields(map[string]any{
+	requestid.Logger(ctx, a.logger).Debug().Fields(map[string]any{
 		"eventType":   eventType,
 		"startHeight": startHeight,
 		"endHeight":   endHeight,
@@ -351,27 +501,27 @@ func (a *AccessAdapter) GetEventsForHeightRange(
 }
 
 func (a *AccessAdapter) GetEventsForBlockIDs(
-	_ context.Context,
+	ctx context.Context,
 	eventType string,
 	blockIDs []flowgo.Identifier,
 ) ([]flowgo.BlockEvents, error) {
-	events, err := a.emulator.GetEventsForBlockIDs(eventType, blockIDs)
+	events, err := a.emulator.GetEventsForBlockIDs(ctx, eventType, blockIDs)
 	if err != nil {
 		return nil, convertError(err)
 	}
 
 	eventCount := 0
 
-	// Convert CCF events to JSON events
+	// Convert CCF events to the encoding chosen by the request
 	for i := range events {
-		events[i].Events, err = ConvertCCFEventsToJsonEvents(events[i].Events)
+		events[i].Events, err = encodeEvents(ctx, events[i].Events)
 		eventCount = eventCount + len(events[i].Events)
 		if err != nil {
 			return nil, convertError(err)
 		}
 	}
 
-	a.logger.Debug().Fields(map[string]any{
+	requestid.Logger(ctx, a.logger).Debug().Fields(map[string]any{
 		"eventType":  eventType,
 		"eventCount": eventCount,
 	}).Msg("🎁  GetEventsForBlockIDs called")
@@ -379,55 +529,50 @@ func (a *AccessAdapter) GetEventsForBlockIDs(
 	return events, nil
 }
 
-func (a *AccessAdapter) GetLatestProtocolStateSnapshot(_ context.Context) ([]byte, error) {
+func (a *AccessAdapter) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
 	return nil, nil
 }
 
-func (a *AccessAdapter) GetExecutionResultForBlockID(_ context.Context, _ flowgo.Identifier) (*flowgo.ExecutionResult, error) {
+func (a *AccessAdapter) GetExecutionResultForBlockID(ctx context.Context, _ flowgo.Identifier) (*flowgo.ExecutionResult, error) {
 	return nil, nil
 }
 
-func (a *AccessAdapter) GetExecutionResultByID(_ context.Context, _ flowgo.Identifier) (*flowgo.ExecutionResult, error) {
+func (a *AccessAdapter) GetExecutionResultByID(ctx context.Context, _ flowgo.Identifier) (*flowgo.ExecutionResult, error) {
 	return nil, nil
 }
 
-func (a *AccessAdapter) GetTransactionResultByIndex(_ context.Context, blockID flowgo.Identifier, index uint32) (*access.TransactionResult, error) {
-	results, err := a.emulator.GetTransactionResultsByBlockID(blockID)
+func (a *AccessAdapter) GetTransactionResultByIndex(ctx context.Context, blockID flowgo.Identifier, index uint32) (*access.TransactionResult, error) {
+	result, err := a.emulator.GetTransactionResultByIndex(ctx, blockID, index)
 	if err != nil {
 		return nil, convertError(err)
 	}
-	if len(results) <= int(index) {
-		return nil, convertError(&types.TransactionNotFoundError{ID: flowgo.Identifier{}})
-	}
 
-	// Convert CCF events to JSON events
-	for i := range results {
-		results[i].Events, err = ConvertCCFEventsToJsonEvents(results[i].Events)
-		if err != nil {
-			return nil, convertError(err)
-		}
+	// Convert CCF events to the encoding chosen by the request
+	result.Events, err = encodeEvents(ctx, result.Events)
+	if err != nil {
+		return nil, convertError(err)
 	}
 
-	return results[index], nil
+	return result, nil
 }
 
-func (a *AccessAdapter) GetTransactionsByBlockID(_ context.Context, blockID flowgo.Identifier) ([]*flowgo.TransactionBody, error) {
-	result, err := a.emulator.GetTransactionsByBlockID(blockID)
+func (a *AccessAdapter) GetTransactionsByBlockID(ctx context.Context, blockID flowgo.Identifier) ([]*flowgo.TransactionBody, error) {
+	result, err := a.emulator.GetTransactionsByBlockID(ctx, blockID)
 	if err != nil {
 		return nil, convertError(err)
 	}
 	return result, nil
 }
 
-func (a *AccessAdapter) GetTransactionResultsByBlockID(_ context.Context, blockID flowgo.Identifier) ([]*access.TransactionResult, error) {
-	result, err := a.emulator.GetTransactionResultsByBlockID(blockID)
+func (a *AccessAdapter) GetTransactionResultsByBlockID(ctx context.Context, blockID flowgo.Identifier) ([]*access.TransactionResult, error) {
+	result, err := a.emulator.GetTransactionResultsByBlockID(ctx, blockID)
 	if err != nil {
 		return nil, convertError(err)
 	}
 
-	// Convert CCF events to JSON events
+	// Convert CCF events to the encoding chosen by the request
 	for i := range result {
-		result[i].Events, err = ConvertCCFEventsToJsonEvents(result[i].Events)
+		result[i].Events, err = encodeEvents(ctx, result[i].Events)
 		if err != nil {
 			return nil, convertError(err)
 		}
@@ -436,16 +581,16 @@ func (a *AccessAdapter) GetTransactionResultsByBlockID(_ context.Context, blockI
 	return result, nil
 }
 
-func (a *AccessAdapter) SendTransaction(_ context.Context, tx *flowgo.TransactionBody) error {
-	a.logger.Debug().
+func (a *AccessAdapter) SendTransaction(ctx context.Context, tx *flowgo.TransactionBody) error {
+	requestid.Logger(ctx, a.logger).Debug().
 		Str("txID", tx.ID().String()).
 		Msg(`✉️   Transaction submitted`)
 
-	return convertError(a.emulator.SendTransaction(tx))
+	return convertError(a.emulator.SendCheckedTransaction(ctx, tx))
 }
 
 func (a *AccessAdapter) GetNodeVersionInfo(
-	_ context.Context,
+	ctx context.Context,
 ) (
 	*access.NodeVersionInfo,
 	error,
@@ -453,6 +598,108 @@ func (a *AccessAdapter) GetNodeVersionInfo(
 	return nil, fmt.Errorf("not supported")
 }
 
+// SubscribeBlocks calls handle with the header of every block committed from now on,
+// until the context is done or handle returns an error.
+//
+// See SubscribeBlocksFromHeight for the delivery guarantees.
+func (a *AccessAdapter) SubscribeBlocks(ctx context.Context, handle func(header *flowgo.Header) error) error {
+	latest, err := a.emulator.GetLatestBlock(ctx)
+	if err != nil {
+		return convertError(err)
+	}
+
+	return a.SubscribeBlocksFromHeight(ctx, latest.Header.Height+1, handle)
+}
+
+// SubscribeBlocksFromHeight calls handle with the header of every block from the given
+// height on, first of the blocks already committed, then of the blocks committed
+// from now on, until the context is done or handle returns an error.
+//
+// Headers are delivered in height order, without gaps, however slow the subscriber.
+// After a rollback, delivery continues at the height rolled back to.
+func (a *AccessAdapter) SubscribeBlocksFromHeight(
+	ctx context.Context,
+	startHeight uint64,
+	handle func(header *flowgo.Header) error,
+) error {
+	// commit notifications only wake the subscriber up, blocks are read from storage,
+	// so notifications dropped while the subscriber is busy are not missed
+	commits := make(chan emulator.BlockEvent, 1)
+	a.emulator.SubscribeBlockCommitted(commits)
+	defer a.emulator.UnsubscribeBlockCommitted(commits)
+
+	latest, err := a.emulator.GetLatestBlock(ctx)
+	if err != nil {
+		return convertError(err)
+	}
+	if startHeight > latest.Header.Height+1 {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"start height %d is beyond the next block height %d",
+			startHeight,
+			latest.Header.Height+1,
+		)
+	}
+
+	requestid.Logger(ctx, a.logger).Debug().
+		Uint64("blockHeight", startHeight).
+		Msg("📡  Block subscription started")
+
+	next := startHeight
+	var lastID flowgo.Identifier
+
+	catchUp := func() error {
+		latest, err := a.emulator.GetLatestBlock(ctx)
+		if err != nil {
+			return convertError(err)
+		}
+		for ; next <= latest.Header.Height; next++ {
+			block, err := a.emulator.GetBlockByHeight(ctx, next)
+			if err != nil {
+				return convertError(err)
+			}
+			if err := handle(block.Header); err != nil {
+				return err
+			}
+			lastID = block.ID()
+		}
+		return nil
+	}
+
+	if err := catchUp(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-commits:
+			// a block below the next height was either delivered already,
+			// or committed after a rollback replaced the delivered blocks
+			height := event.Block.Header.Height
+			if height < next {
+				last, err := a.emulator.GetBlockByHeight(ctx, next-1)
+				if err != nil || last.ID() != lastID {
+					next = height
+				}
+			}
+
+			if err := catchUp(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// encodeEvents returns the events, stored in CCF, in the encoding chosen by the request.
+func encodeEvents(ctx context.Context, events []flowgo.Event) ([]flowgo.Event, error) {
+	if eventencoding.FromContext(ctx) == eventencoding.CCFV0 {
+		return events, nil
+	}
+	return ConvertCCFEventsToJsonEvents(events)
+}
+
 func ConvertCCFEventsToJsonEvents(events []flowgo.Event) ([]flowgo.Event, error) {
 	converted := make([]flowgo.Event, 0, len(events))
 
@@ -30,11 +30,16 @@ import (
 	"github.com/onflow/cadence/encoding/ccf"
 	"github.com/onflow/flow-emulator/emulator/mocks"
 	"github.com/onflow/flow-emulator/types"
+	"github.com/onflow/flow-emulator/utils/eventencoding"
+	"github.com/onflow/flow-emulator/utils/pending"
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func accessTest(f func(t *testing.T, adapter *AccessAdapter, emu *mocks.MockEmulator)) func(t *testing.T) {
@@ -85,7 +90,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(&expected, nil).
 			Times(1)
 
@@ -96,7 +101,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -116,7 +121,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetBlockByHeight(uint64(42)).
+			GetBlockByHeight(gomock.Any(), uint64(42)).
 			Return(&expected, nil).
 			Times(1)
 
@@ -127,7 +132,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetBlockByHeight(uint64(42)).
+			GetBlockByHeight(gomock.Any(), uint64(42)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -148,7 +153,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetBlockByID(id).
+			GetBlockByID(gomock.Any(), id).
 			Return(&expected, nil).
 			Times(1)
 
@@ -159,7 +164,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetBlockByID(id).
+			GetBlockByID(gomock.Any(), id).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -179,7 +184,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(&expected, nil).
 			Times(1)
 
@@ -190,7 +195,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -210,7 +215,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetBlockByHeight(uint64(42)).
+			GetBlockByHeight(gomock.Any(), uint64(42)).
 			Return(&expected, nil).
 			Times(1)
 
@@ -221,7 +226,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetBlockByHeight(uint64(42)).
+			GetBlockByHeight(gomock.Any(), uint64(42)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -242,7 +247,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetBlockByID(id).
+			GetBlockByID(gomock.Any(), id).
 			Return(&expected, nil).
 			Times(1)
 
@@ -253,7 +258,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetBlockByID(id).
+			GetBlockByID(gomock.Any(), id).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -271,7 +276,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetCollectionByID(id).
+			GetCollectionByID(gomock.Any(), id).
 			Return(&expected, nil).
 			Times(1)
 
@@ -281,7 +286,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetCollectionByID(id).
+			GetCollectionByID(gomock.Any(), id).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -298,7 +303,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetTransaction(id).
+			GetTransaction(gomock.Any(), id).
 			Return(&expected, nil).
 			Times(1)
 
@@ -308,7 +313,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetTransaction(id).
+			GetTransaction(gomock.Any(), id).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -337,7 +342,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetTransactionResult(txID).
+			GetTransactionResult(gomock.Any(), txID).
 			Return(&emuResult, nil).
 			Times(1)
 
@@ -347,7 +352,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetTransactionResult(txID).
+			GetTransactionResult(gomock.Any(), txID).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -357,6 +362,32 @@ func TestAccess(t *testing.T) {
 
 	}))
 
+	t.Run("GetTransactionLogs", accessTest(func(t *testing.T, adapter *AccessAdapter, emu *mocks.MockEmulator) {
+
+		txID := flowgo.Identifier{}
+
+		//success
+		emu.EXPECT().
+			GetTransactionLogs(gomock.Any(), txID).
+			Return([]string{`"hello"`}, nil).
+			Times(1)
+
+		logs, err := adapter.GetTransactionLogs(context.Background(), txID)
+		assert.Equal(t, []string{`"hello"`}, logs)
+		assert.NoError(t, err)
+
+		//not found
+		emu.EXPECT().
+			GetTransactionLogs(gomock.Any(), txID).
+			Return(nil, &types.TransactionNotFoundError{ID: txID}).
+			Times(1)
+
+		logs, err = adapter.GetTransactionLogs(context.Background(), txID)
+		assert.Nil(t, logs)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+	}))
+
 	t.Run("GetAccount", accessTest(func(t *testing.T, adapter *AccessAdapter, emu *mocks.MockEmulator) {
 
 		address := flowgo.Address{}
@@ -364,7 +395,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetAccount(address).
+			GetAccount(gomock.Any(), address).
 			Return(&expected, nil).
 			Times(1)
 
@@ -374,7 +405,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetAccount(address).
+			GetAccount(gomock.Any(), address).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -391,7 +422,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetAccount(address).
+			GetAccount(gomock.Any(), address).
 			Return(&expected, nil).
 			Times(1)
 
@@ -401,7 +432,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetAccount(address).
+			GetAccount(gomock.Any(), address).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -420,7 +451,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetAccountAtBlockHeight(address, height).
+			GetAccountAtBlockHeight(gomock.Any(), address, height).
 			Return(&expected, nil).
 			Times(1)
 
@@ -430,7 +461,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetAccountAtBlockHeight(address, height).
+			GetAccountAtBlockHeight(gomock.Any(), address, height).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -440,6 +471,43 @@ func TestAccess(t *testing.T) {
 
 	}))
 
+	t.Run("GetAccountAtPendingBlock", accessTest(func(t *testing.T, adapter *AccessAdapter, emu *mocks.MockEmulator) {
+
+		address := flowgo.Address{}
+		expected := flowgo.Account{}
+
+		//success
+		emu.EXPECT().
+			GetAccountAtPendingBlock(gomock.Any(), address).
+			Return(&expected, nil).
+			Times(1)
+
+		result, err := adapter.GetAccountAtPendingBlock(context.Background(), address)
+		assert.Equal(t, expected, *result)
+		assert.NoError(t, err)
+
+		//pending queries of the latest block
+		emu.EXPECT().
+			GetAccountAtPendingBlock(gomock.Any(), address).
+			Return(&expected, nil).
+			Times(1)
+
+		result, err = adapter.GetAccountAtLatestBlock(pending.WithContext(context.Background()), address)
+		assert.Equal(t, expected, *result)
+		assert.NoError(t, err)
+
+		//fail
+		emu.EXPECT().
+			GetAccountAtPendingBlock(gomock.Any(), address).
+			Return(nil, fmt.Errorf("some error")).
+			Times(1)
+
+		result, err = adapter.GetAccountAtPendingBlock(context.Background(), address)
+		assert.Nil(t, result)
+		assert.Error(t, err)
+
+	}))
+
 	t.Run("ExecuteScriptAtLatestBlock", accessTest(func(t *testing.T, adapter *AccessAdapter, emu *mocks.MockEmulator) {
 
 		script := []byte("some cadence code here")
@@ -451,7 +519,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			ExecuteScript(script, arguments).
+			ExecuteScript(gomock.Any(), script, arguments).
 			Return(&emulatorResult, nil).
 			Times(1)
 
@@ -461,7 +529,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			ExecuteScript(script, arguments).
+			ExecuteScript(gomock.Any(), script, arguments).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -483,7 +551,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			ExecuteScriptAtBlockHeight(script, arguments, height).
+			ExecuteScriptAtBlockHeight(gomock.Any(), script, arguments, height).
 			Return(&emulatorResult, nil).
 			Times(1)
 
@@ -493,7 +561,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			ExecuteScriptAtBlockHeight(script, arguments, height).
+			ExecuteScriptAtBlockHeight(gomock.Any(), script, arguments, height).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -515,7 +583,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			ExecuteScriptAtBlockID(script, arguments, id).
+			ExecuteScriptAtBlockID(gomock.Any(), script, arguments, id).
 			Return(&emulatorResult, nil).
 			Times(1)
 
@@ -525,7 +593,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			ExecuteScriptAtBlockID(script, arguments, id).
+			ExecuteScriptAtBlockID(gomock.Any(), script, arguments, id).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -535,6 +603,47 @@ func TestAccess(t *testing.T) {
 
 	}))
 
+	t.Run("ExecuteScriptAtPendingBlock", accessTest(func(t *testing.T, adapter *AccessAdapter, emu *mocks.MockEmulator) {
+
+		script := []byte("some cadence code here")
+		var arguments [][]byte
+
+		stringValue, _ := cadence.NewString("42")
+		emulatorResult := types.ScriptResult{Value: stringValue}
+		expected, _ := convertScriptResult(&emulatorResult, nil)
+
+		//success
+		emu.EXPECT().
+			ExecuteScriptAtPendingBlock(gomock.Any(), script, arguments).
+			Return(&emulatorResult, nil).
+			Times(1)
+
+		result, err := adapter.ExecuteScriptAtPendingBlock(context.Background(), script, arguments)
+		assert.Equal(t, expected, result)
+		assert.NoError(t, err)
+
+		//pending queries of the latest block
+		emu.EXPECT().
+			ExecuteScriptAtPendingBlock(gomock.Any(), script, arguments).
+			Return(&emulatorResult, nil).
+			Times(1)
+
+		result, err = adapter.ExecuteScriptAtLatestBlock(pending.WithContext(context.Background()), script, arguments)
+		assert.Equal(t, expected, result)
+		assert.NoError(t, err)
+
+		//fail
+		emu.EXPECT().
+			ExecuteScriptAtPendingBlock(gomock.Any(), script, arguments).
+			Return(nil, fmt.Errorf("some error")).
+			Times(1)
+
+		result, err = adapter.ExecuteScriptAtPendingBlock(context.Background(), script, arguments)
+		assert.Nil(t, result)
+		assert.Error(t, err)
+
+	}))
+
 	t.Run("GetEventsForHeightRange", accessTest(func(t *testing.T, adapter *AccessAdapter, emu *mocks.MockEmulator) {
 
 		eventType := "testEvent"
@@ -558,7 +667,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetEventsForHeightRange(eventType, startHeight, endHeight).
+			GetEventsForHeightRange(gomock.Any(), eventType, startHeight, endHeight).
 			Return(blockEvents, nil).
 			Times(1)
 
@@ -566,9 +675,20 @@ func TestAccess(t *testing.T) {
 		assert.Equal(t, expected, result)
 		assert.NoError(t, err)
 
+		// CCF is returned as stored
+		emu.EXPECT().
+			GetEventsForHeightRange(gomock.Any(), eventType, startHeight, endHeight).
+			Return([]flowgo.BlockEvents{{Events: []flowgo.Event{ccfEventFixture(t)}}}, nil).
+			Times(1)
+
+		ccfContext := eventencoding.WithContext(context.Background(), eventencoding.CCFV0)
+		result, err = adapter.GetEventsForHeightRange(ccfContext, eventType, startHeight, endHeight)
+		assert.Equal(t, []flowgo.BlockEvents{{Events: []flowgo.Event{ccfEventFixture(t)}}}, result)
+		assert.NoError(t, err)
+
 		//fail
 		emu.EXPECT().
-			GetEventsForHeightRange(eventType, startHeight, endHeight).
+			GetEventsForHeightRange(gomock.Any(), eventType, startHeight, endHeight).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -600,7 +720,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetEventsForBlockIDs(eventType, blockIDs).
+			GetEventsForBlockIDs(gomock.Any(), eventType, blockIDs).
 			Return(blockEvents, nil).
 			Times(1)
 
@@ -610,7 +730,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetEventsForBlockIDs(eventType, blockIDs).
+			GetEventsForBlockIDs(gomock.Any(), eventType, blockIDs).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -630,7 +750,6 @@ func TestAccess(t *testing.T) {
 				ccfEventFixture(t),
 			},
 		}
-		results := []*access.TransactionResult{txResult}
 		convertedTXResult := &access.TransactionResult{
 			Events: []flowgo.Event{
 				jsonCDCEventFixture(t),
@@ -639,17 +758,28 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetTransactionResultsByBlockID(blockID).
-			Return(results, nil).
+			GetTransactionResultByIndex(gomock.Any(), blockID, index).
+			Return(txResult, nil).
 			Times(1)
 
 		result, err := adapter.GetTransactionResultByIndex(context.Background(), blockID, index)
 		assert.Equal(t, convertedTXResult, result)
 		assert.NoError(t, err)
 
+		// CCF is returned as stored
+		emu.EXPECT().
+			GetTransactionResultByIndex(gomock.Any(), blockID, index).
+			Return(&access.TransactionResult{Events: []flowgo.Event{ccfEventFixture(t)}}, nil).
+			Times(1)
+
+		ccfContext := eventencoding.WithContext(context.Background(), eventencoding.CCFV0)
+		result, err = adapter.GetTransactionResultByIndex(ccfContext, blockID, index)
+		assert.Equal(t, &access.TransactionResult{Events: []flowgo.Event{ccfEventFixture(t)}}, result)
+		assert.NoError(t, err)
+
 		//fail
 		emu.EXPECT().
-			GetTransactionResultsByBlockID(blockID).
+			GetTransactionResultByIndex(gomock.Any(), blockID, index).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -667,7 +797,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetTransactionsByBlockID(blockID).
+			GetTransactionsByBlockID(gomock.Any(), blockID).
 			Return(expected, nil).
 			Times(1)
 
@@ -677,7 +807,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetTransactionsByBlockID(blockID).
+			GetTransactionsByBlockID(gomock.Any(), blockID).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -708,7 +838,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetTransactionResultsByBlockID(blockID).
+			GetTransactionResultsByBlockID(gomock.Any(), blockID).
 			Return(results, nil).
 			Times(1)
 
@@ -718,7 +848,7 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetTransactionResultsByBlockID(blockID).
+			GetTransactionResultsByBlockID(gomock.Any(), blockID).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -734,7 +864,7 @@ func TestAccess(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			SendTransaction(&transaction).
+			SendCheckedTransaction(gomock.Any(), &transaction).
 			Return(nil).
 			Times(1)
 
@@ -743,13 +873,40 @@ func TestAccess(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			SendTransaction(&transaction).
+			SendCheckedTransaction(gomock.Any(), &transaction).
 			Return(fmt.Errorf("some error")).
 			Times(1)
 
 		err = adapter.SendTransaction(context.Background(), &transaction)
 		assert.Error(t, err)
 
+		//invalid sequence number
+		emu.EXPECT().
+			SendCheckedTransaction(gomock.Any(), &transaction).
+			Return(&types.InvalidSequenceNumberError{
+				Address:  flowgo.HexToAddress("0x01"),
+				KeyIndex: 1,
+				Expected: 5,
+				Provided: 3,
+			}).
+			Times(1)
+
+		err = adapter.SendTransaction(context.Background(), &transaction)
+		require.Error(t, err)
+
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+
+		require.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, string(types.ErrorCodeInvalidSequenceNumber), info.Reason)
+		assert.Equal(t, "0x0000000000000001", info.Metadata["address"])
+		assert.Equal(t, "1", info.Metadata["keyIndex"])
+		assert.Equal(t, "5", info.Metadata["expectedSequenceNumber"])
+		assert.Equal(t, "3", info.Metadata["providedSequenceNumber"])
+
 	}))
 
 	t.Run("GetNodeVersionInfo", accessTest(func(t *testing.T, adapter *AccessAdapter, emu *mocks.MockEmulator) {
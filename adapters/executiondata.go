@@ -0,0 +1,164 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapters
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-emulator/utils/requestid"
+)
+
+var _ state_stream.API = &AccessAdapter{}
+
+// GetExecutionDataByBlockID returns the execution data of a committed block.
+func (a *AccessAdapter) GetExecutionDataByBlockID(
+	ctx context.Context,
+	blockID flowgo.Identifier,
+) (*execution_data.BlockExecutionData, error) {
+	data, err := a.emulator.GetExecutionDataByBlockID(ctx, blockID)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	requestid.Logger(ctx, a.logger).Debug().
+		Str("blockID", blockID.String()).
+		Msg("🎁  GetExecutionDataByBlockID called")
+
+	return data, nil
+}
+
+// SubscribeExecutionData streams the execution data of every block from the start block on,
+// until the context is done.
+//
+// Without a start block ID or height, the subscription starts at the latest block.
+func (a *AccessAdapter) SubscribeExecutionData(
+	ctx context.Context,
+	startBlockID flowgo.Identifier,
+	startHeight uint64,
+) state_stream.Subscription {
+	return a.subscribe(ctx, startBlockID, startHeight, func(header *flowgo.Header) (any, error) {
+		data, err := a.emulator.GetExecutionDataByBlockID(ctx, header.ID())
+		if err != nil {
+			return nil, convertError(err)
+		}
+
+		return &state_stream.ExecutionDataResponse{
+			Height:        header.Height,
+			ExecutionData: data,
+		}, nil
+	})
+}
+
+// SubscribeEvents streams the events matching the filter of every block from the start block on,
+// until the context is done. Blocks without matching events are streamed without events.
+//
+// Without a start block ID or height, the subscription starts at the latest block.
+func (a *AccessAdapter) SubscribeEvents(
+	ctx context.Context,
+	startBlockID flowgo.Identifier,
+	startHeight uint64,
+	filter state_stream.EventFilter,
+) state_stream.Subscription {
+	return a.subscribe(ctx, startBlockID, startHeight, func(header *flowgo.Header) (any, error) {
+		events, err := a.emulator.GetEventsByHeight(ctx, header.Height, "")
+		if err != nil {
+			return nil, convertError(err)
+		}
+
+		events, err = encodeEvents(ctx, filter.Filter(events))
+		if err != nil {
+			return nil, convertError(err)
+		}
+
+		return &state_stream.EventsResponse{
+			BlockID: header.ID(),
+			Height:  header.Height,
+			Events:  events,
+		}, nil
+	})
+}
+
+// subscribe sends the response for every block from the start block on to a subscription,
+// until the context is done.
+func (a *AccessAdapter) subscribe(
+	ctx context.Context,
+	startBlockID flowgo.Identifier,
+	startHeight uint64,
+	response func(header *flowgo.Header) (any, error),
+) state_stream.Subscription {
+	height, err := a.subscriptionStartHeight(ctx, startBlockID, startHeight)
+	if err != nil {
+		return state_stream.NewFailedSubscription(err, "could not get start height")
+	}
+
+	sub := state_stream.NewSubscription(state_stream.DefaultSendBufferSize)
+
+	go func() {
+		err := a.SubscribeBlocksFromHeight(ctx, height, func(header *flowgo.Header) error {
+			value, err := response(header)
+			if err != nil {
+				return err
+			}
+
+			return sub.Send(ctx, value, state_stream.DefaultSendTimeout)
+		})
+		if err != nil {
+			sub.Fail(err)
+			return
+		}
+
+		sub.Close()
+	}()
+
+	return sub
+}
+
+func (a *AccessAdapter) subscriptionStartHeight(
+	ctx context.Context,
+	startBlockID flowgo.Identifier,
+	startHeight uint64,
+) (uint64, error) {
+	if startBlockID != flowgo.ZeroID && startHeight > 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "only one of start block ID and start height may be provided")
+	}
+
+	if startBlockID != flowgo.ZeroID {
+		block, err := a.emulator.GetBlockByID(ctx, startBlockID)
+		if err != nil {
+			return 0, convertError(err)
+		}
+		return block.Header.Height, nil
+	}
+
+	if startHeight > 0 {
+		return startHeight, nil
+	}
+
+	latest, err := a.emulator.GetLatestBlock(ctx)
+	if err != nil {
+		return 0, convertError(err)
+	}
+	return latest.Header.Height, nil
+}
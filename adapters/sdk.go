@@ -26,8 +26,10 @@ import (
 	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/flow-emulator/convert"
 	emulator "github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
 	sdk "github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/templates"
+	"github.com/onflow/flow-go/fvm"
 	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
@@ -70,14 +72,14 @@ func (b *SDKAdapter) GetChainID(ctx context.Context) sdk.ChainID {
 
 // GetLatestBlockHeader gets the latest sealed block header.
 func (b *SDKAdapter) GetLatestBlockHeader(
-	_ context.Context,
+	ctx context.Context,
 	_ bool,
 ) (
 	*sdk.BlockHeader,
 	sdk.BlockStatus,
 	error,
 ) {
-	block, err := b.emulator.GetLatestBlock()
+	block, err := b.emulator.GetLatestBlock(ctx)
 	if err != nil {
 		return nil, sdk.BlockStatusUnknown, status.Error(codes.Internal, err.Error())
 	}
@@ -92,14 +94,14 @@ func (b *SDKAdapter) GetLatestBlockHeader(
 
 // GetBlockHeaderByHeight gets a block header by height.
 func (b *SDKAdapter) GetBlockHeaderByHeight(
-	_ context.Context,
+	ctx context.Context,
 	height uint64,
 ) (
 	*sdk.BlockHeader,
 	sdk.BlockStatus,
 	error,
 ) {
-	block, err := b.emulator.GetBlockByHeight(height)
+	block, err := b.emulator.GetBlockByHeight(ctx, height)
 	if err != nil {
 		return nil, sdk.BlockStatusUnknown, status.Error(codes.Internal, err.Error())
 	}
@@ -114,14 +116,14 @@ func (b *SDKAdapter) GetBlockHeaderByHeight(
 
 // GetBlockHeaderByID gets a block header by ID.
 func (b *SDKAdapter) GetBlockHeaderByID(
-	_ context.Context,
+	ctx context.Context,
 	id sdk.Identifier,
 ) (
 	*sdk.BlockHeader,
 	sdk.BlockStatus,
 	error,
 ) {
-	block, err := b.emulator.GetBlockByID(convert.SDKIdentifierToFlow(id))
+	block, err := b.emulator.GetBlockByID(ctx, convert.SDKIdentifierToFlow(id))
 	if err != nil {
 		return nil, sdk.BlockStatusUnknown, err
 	}
@@ -136,14 +138,14 @@ func (b *SDKAdapter) GetBlockHeaderByID(
 
 // GetLatestBlock gets the latest sealed block.
 func (b *SDKAdapter) GetLatestBlock(
-	_ context.Context,
+	ctx context.Context,
 	_ bool,
 ) (
 	*sdk.Block,
 	sdk.BlockStatus,
 	error,
 ) {
-	flowBlock, err := b.emulator.GetLatestBlock()
+	flowBlock, err := b.emulator.GetLatestBlock(ctx)
 	if err != nil {
 		return nil, sdk.BlockStatusUnknown, status.Error(codes.Internal, err.Error())
 	}
@@ -168,7 +170,7 @@ func (b *SDKAdapter) GetBlockByHeight(
 	sdk.BlockStatus,
 	error,
 ) {
-	flowBlock, err := b.emulator.GetBlockByHeight(height)
+	flowBlock, err := b.emulator.GetBlockByHeight(ctx, height)
 	if err != nil {
 		return nil, sdk.BlockStatusUnknown, status.Error(codes.Internal, err.Error())
 	}
@@ -186,14 +188,14 @@ func (b *SDKAdapter) GetBlockByHeight(
 
 // GetBlockByID gets a block by ID.
 func (b *SDKAdapter) GetBlockByID(
-	_ context.Context,
+	ctx context.Context,
 	id sdk.Identifier,
 ) (
 	*sdk.Block,
 	sdk.BlockStatus,
 	error,
 ) {
-	flowBlock, err := b.emulator.GetBlockByID(convert.SDKIdentifierToFlow(id))
+	flowBlock, err := b.emulator.GetBlockByID(ctx, convert.SDKIdentifierToFlow(id))
 	if err != nil {
 		return nil, sdk.BlockStatusUnknown, status.Error(codes.Internal, err.Error())
 	}
@@ -211,10 +213,10 @@ func (b *SDKAdapter) GetBlockByID(
 
 // GetCollectionByID gets a collection by ID.
 func (b *SDKAdapter) GetCollectionByID(
-	_ context.Context,
+	ctx context.Context,
 	id sdk.Identifier,
 ) (*sdk.Collection, error) {
-	flowCollection, err := b.emulator.GetCollectionByID(convert.SDKIdentifierToFlow(id))
+	flowCollection, err := b.emulator.GetCollectionByID(ctx, convert.SDKIdentifierToFlow(id))
 	if err != nil {
 		return nil, err
 	}
@@ -224,7 +226,7 @@ func (b *SDKAdapter) GetCollectionByID(
 
 func (b *SDKAdapter) SendTransaction(ctx context.Context, tx sdk.Transaction) error {
 	flowTx := convert.SDKTransactionToFlow(tx)
-	return b.emulator.SendTransaction(flowTx)
+	return b.emulator.SendTransaction(ctx, flowTx)
 }
 
 // GetTransaction gets a transaction by ID.
@@ -232,7 +234,7 @@ func (b *SDKAdapter) GetTransaction(
 	ctx context.Context,
 	id sdk.Identifier,
 ) (*sdk.Transaction, error) {
-	tx, err := b.emulator.GetTransaction(convert.SDKIdentifierToFlow(id))
+	tx, err := b.emulator.GetTransaction(ctx, convert.SDKIdentifierToFlow(id))
 	if err != nil {
 		return nil, err
 	}
@@ -245,7 +247,7 @@ func (b *SDKAdapter) GetTransactionResult(
 	ctx context.Context,
 	id sdk.Identifier,
 ) (*sdk.TransactionResult, error) {
-	flowResult, err := b.emulator.GetTransactionResult(convert.SDKIdentifierToFlow(id))
+	flowResult, err := b.emulator.GetTransactionResult(ctx, convert.SDKIdentifierToFlow(id))
 	if err != nil {
 		return nil, err
 	}
@@ -257,7 +259,7 @@ func (b *SDKAdapter) GetAccount(
 	ctx context.Context,
 	address sdk.Address,
 ) (*sdk.Account, error) {
-	account, err := b.getAccount(address)
+	account, err := b.getAccount(ctx, address)
 	if err != nil {
 		return nil, err
 	}
@@ -269,15 +271,15 @@ func (b *SDKAdapter) GetAccountAtLatestBlock(
 	ctx context.Context,
 	address sdk.Address,
 ) (*sdk.Account, error) {
-	account, err := b.getAccount(address)
+	account, err := b.getAccount(ctx, address)
 	if err != nil {
 		return nil, err
 	}
 	return account, nil
 }
 
-func (b *SDKAdapter) getAccount(address sdk.Address) (*sdk.Account, error) {
-	account, err := b.emulator.GetAccount(convert.SDKAddressToFlow(address))
+func (b *SDKAdapter) getAccount(ctx context.Context, address sdk.Address) (*sdk.Account, error) {
+	account, err := b.emulator.GetAccount(ctx, convert.SDKAddressToFlow(address))
 	if err != nil {
 		return nil, err
 	}
@@ -289,7 +291,7 @@ func (b *SDKAdapter) GetAccountAtBlockHeight(
 	address sdk.Address,
 	height uint64,
 ) (*sdk.Account, error) {
-	account, err := b.emulator.GetAccountAtBlockHeight(convert.SDKAddressToFlow(address), height)
+	account, err := b.emulator.GetAccountAtBlockHeight(ctx, convert.SDKAddressToFlow(address), height)
 	if err != nil {
 		return nil, err
 	}
@@ -302,11 +304,11 @@ func (b *SDKAdapter) ExecuteScriptAtLatestBlock(
 	script []byte,
 	arguments [][]byte,
 ) ([]byte, error) {
-	block, err := b.emulator.GetLatestBlock()
+	block, err := b.emulator.GetLatestBlock(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return b.executeScriptAtBlock(script, arguments, block.Header.Height)
+	return b.executeScriptAtBlock(ctx, script, arguments, block.Header.Height)
 }
 
 // ExecuteScriptAtBlockHeight executes a script at a specific block height
@@ -316,7 +318,7 @@ func (b *SDKAdapter) ExecuteScriptAtBlockHeight(
 	script []byte,
 	arguments [][]byte,
 ) ([]byte, error) {
-	return b.executeScriptAtBlock(script, arguments, blockHeight)
+	return b.executeScriptAtBlock(ctx, script, arguments, blockHeight)
 }
 
 // ExecuteScriptAtBlockID executes a script at a specific block ID
@@ -326,16 +328,30 @@ func (b *SDKAdapter) ExecuteScriptAtBlockID(
 	script []byte,
 	arguments [][]byte,
 ) ([]byte, error) {
-	block, err := b.emulator.GetBlockByID(convert.SDKIdentifierToFlow(blockID))
+	block, err := b.emulator.GetBlockByID(ctx, convert.SDKIdentifierToFlow(blockID))
 	if err != nil {
 		return nil, err
 	}
-	return b.executeScriptAtBlock(script, arguments, block.Header.Height)
+	return b.executeScriptAtBlock(ctx, script, arguments, block.Header.Height)
+}
+
+// ExecuteScriptAtPendingBlock executes a script against the state as it is once
+// the transactions of the pending block are executed, before they are committed
+func (b *SDKAdapter) ExecuteScriptAtPendingBlock(
+	ctx context.Context,
+	script []byte,
+	arguments [][]byte,
+) ([]byte, error) {
+	return scriptResultValue(b.emulator.ExecuteScriptAtPendingBlock(ctx, script, arguments))
 }
 
 // executeScriptAtBlock is a helper for executing a script at a specific block
-func (b *SDKAdapter) executeScriptAtBlock(script []byte, arguments [][]byte, blockHeight uint64) ([]byte, error) {
-	result, err := b.emulator.ExecuteScriptAtBlockHeight(script, arguments, blockHeight)
+func (b *SDKAdapter) executeScriptAtBlock(ctx context.Context, script []byte, arguments [][]byte, blockHeight uint64) ([]byte, error) {
+	return scriptResultValue(b.emulator.ExecuteScriptAtBlockHeight(ctx, script, arguments, blockHeight))
+}
+
+// scriptResultValue returns the JSON-CDC encoded value of a successful script execution
+func scriptResultValue(result *types.ScriptResult, err error) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
@@ -349,17 +365,17 @@ func (b *SDKAdapter) executeScriptAtBlock(script []byte, arguments [][]byte, blo
 	return valueBytes, nil
 }
 
-func (b *SDKAdapter) GetLatestProtocolStateSnapshot(_ context.Context) ([]byte, error) {
+func (b *SDKAdapter) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
 	return nil, nil
 }
 
-func (b *SDKAdapter) GetExecutionResultForBlockID(_ context.Context, _ sdk.Identifier) (*sdk.ExecutionResult, error) {
+func (b *SDKAdapter) GetExecutionResultForBlockID(ctx context.Context, _ sdk.Identifier) (*sdk.ExecutionResult, error) {
 	return nil, nil
 }
 
 func (b *SDKAdapter) GetTransactionsByBlockID(ctx context.Context, id sdk.Identifier) ([]*sdk.Transaction, error) {
 	result := []*sdk.Transaction{}
-	transactions, err := b.emulator.GetTransactionsByBlockID(convert.SDKIdentifierToFlow(id))
+	transactions, err := b.emulator.GetTransactionsByBlockID(ctx, convert.SDKIdentifierToFlow(id))
 	if err != nil {
 		return nil, err
 	}
@@ -373,7 +389,7 @@ func (b *SDKAdapter) GetTransactionsByBlockID(ctx context.Context, id sdk.Identi
 
 func (b *SDKAdapter) GetTransactionResultsByBlockID(ctx context.Context, id sdk.Identifier) ([]*sdk.TransactionResult, error) {
 	result := []*sdk.TransactionResult{}
-	transactionResults, err := b.emulator.GetTransactionResultsByBlockID(convert.SDKIdentifierToFlow(id))
+	transactionResults, err := b.emulator.GetTransactionResultsByBlockID(ctx, convert.SDKIdentifierToFlow(id))
 	if err != nil {
 		return nil, err
 	}
@@ -389,7 +405,7 @@ func (b *SDKAdapter) GetTransactionResultsByBlockID(ctx context.Context, id sdk.
 
 func (b *SDKAdapter) GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []sdk.Identifier) ([]*sdk.BlockEvents, error) {
 	result := []*sdk.BlockEvents{}
-	flowBlockEvents, err := b.emulator.GetEventsForBlockIDs(eventType, convert.SDKIdentifiersToFlow(blockIDs))
+	flowBlockEvents, err := b.emulator.GetEventsForBlockIDs(ctx, eventType, convert.SDKIdentifiersToFlow(blockIDs))
 	if err != nil {
 		return nil, err
 	}
@@ -417,7 +433,7 @@ func (b *SDKAdapter) GetEventsForBlockIDs(ctx context.Context, eventType string,
 func (b *SDKAdapter) GetEventsForHeightRange(ctx context.Context, eventType string, startHeight, endHeight uint64) ([]*sdk.BlockEvents, error) {
 	result := []*sdk.BlockEvents{}
 
-	flowBlockEvents, err := b.emulator.GetEventsForHeightRange(eventType, startHeight, endHeight)
+	flowBlockEvents, err := b.emulator.GetEventsForHeightRange(ctx, eventType, startHeight, endHeight)
 	if err != nil {
 		return nil, err
 	}
@@ -443,12 +459,48 @@ func (b *SDKAdapter) GetEventsForHeightRange(ctx context.Context, eventType stri
 	return result, nil
 }
 
+// createAndFundAccountTransaction creates an account with the given keys and contracts,
+// and transfers the initial balance to it from the service account.
+const createAndFundAccountTransaction = `
+import Crypto
+import FungibleToken from %s
+import FlowToken from %s
+
+transaction(publicKeys: [Crypto.KeyListEntry], contracts: {String: String}, initialBalance: UFix64) {
+	prepare(signer: AuthAccount) {
+		let account = AuthAccount(payer: signer)
+
+		for key in publicKeys {
+			account.keys.add(publicKey: key.publicKey, hashAlgorithm: key.hashAlgorithm, weight: key.weight)
+		}
+
+		for contract in contracts.keys {
+			account.contracts.add(name: contract, code: contracts[contract]!.decodeHex())
+		}
+
+		let vault = signer.borrow<&FlowToken.Vault>(from: /storage/flowTokenVault)
+			?? panic("could not borrow the FLOW vault of the service account")
+
+		account.getCapability(/public/flowTokenReceiver)
+			.borrow<&{FungibleToken.Receiver}>()!
+			.deposit(from: <- vault.withdraw(amount: initialBalance))
+	}
+}
+`
+
 // CreateAccount submits a transaction to create a new account with the given
-// account keys and contracts. The transaction is paid by the service account.
-func (b *SDKAdapter) CreateAccount(ctx context.Context, publicKeys []*sdk.AccountKey, contracts []templates.Contract) (sdk.Address, error) {
+// account keys and contracts, funded with the given initial balance of FLOW on
+// top of the minimum storage reservation. The transaction and the initial
+// balance are paid by the service account.
+func (b *SDKAdapter) CreateAccount(
+	ctx context.Context,
+	publicKeys []*sdk.AccountKey,
+	contracts []templates.Contract,
+	initialBalance cadence.UFix64,
+) (sdk.Address, error) {
 
 	serviceKey := b.emulator.ServiceKey()
-	latestBlock, err := b.emulator.GetLatestBlock()
+	latestBlock, err := b.emulator.GetLatestBlock(ctx)
 
 	if err != nil {
 		return sdk.Address{}, err
@@ -462,6 +514,21 @@ func (b *SDKAdapter) CreateAccount(ctx context.Context, publicKeys []*sdk.Accoun
 		return sdk.Address{}, err
 	}
 
+	// funding the account in the same transaction saves a round trip
+	if initialBalance > 0 {
+		chain := b.emulator.GetNetworkParameters().ChainID.Chain()
+		tx.SetScript([]byte(fmt.Sprintf(
+			createAndFundAccountTransaction,
+			fvm.FungibleTokenAddress(chain).HexWithPrefix(),
+			fvm.FlowTokenAddress(chain).HexWithPrefix(),
+		)))
+
+		err = tx.AddArgument(initialBalance)
+		if err != nil {
+			return sdk.Address{}, err
+		}
+	}
+
 	tx.SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
 		SetReferenceBlockID(sdk.Identifier(latestBlock.ID())).
 		SetProposalKey(serviceKey.Address, serviceKey.Index, serviceKey.SequenceNumber).
@@ -76,7 +76,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(flowBlock, nil).
 			Times(1)
 
@@ -87,7 +87,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -115,7 +115,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetBlockByHeight(uint64(42)).
+			GetBlockByHeight(gomock.Any(), uint64(42)).
 			Return(flowBlock, nil).
 			Times(1)
 
@@ -126,7 +126,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetBlockByHeight(uint64(42)).
+			GetBlockByHeight(gomock.Any(), uint64(42)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -155,7 +155,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetBlockByID(convert.SDKIdentifierToFlow(id)).
+			GetBlockByID(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(flowBlock, nil).
 			Times(1)
 
@@ -166,7 +166,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetBlockByID(convert.SDKIdentifierToFlow(id)).
+			GetBlockByID(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -194,7 +194,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(flowBlock, nil).
 			Times(1)
 
@@ -205,7 +205,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -233,7 +233,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetBlockByHeight(uint64(42)).
+			GetBlockByHeight(gomock.Any(), uint64(42)).
 			Return(flowBlock, nil).
 			Times(1)
 
@@ -244,7 +244,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetBlockByHeight(uint64(42)).
+			GetBlockByHeight(gomock.Any(), uint64(42)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -273,7 +273,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetBlockByID(convert.SDKIdentifierToFlow(id)).
+			GetBlockByID(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(flowBlock, nil).
 			Times(1)
 
@@ -284,7 +284,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetBlockByID(convert.SDKIdentifierToFlow(id)).
+			GetBlockByID(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -303,7 +303,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetCollectionByID(convert.SDKIdentifierToFlow(id)).
+			GetCollectionByID(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(&flowCollection, nil).
 			Times(1)
 
@@ -313,7 +313,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetCollectionByID(convert.SDKIdentifierToFlow(id)).
+			GetCollectionByID(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -331,7 +331,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetTransaction(convert.SDKIdentifierToFlow(id)).
+			GetTransaction(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(&transaction, nil).
 			Times(1)
 
@@ -341,7 +341,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetTransaction(convert.SDKIdentifierToFlow(id)).
+			GetTransaction(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -362,7 +362,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetTransactionResult(convert.SDKIdentifierToFlow(txID)).
+			GetTransactionResult(gomock.Any(), convert.SDKIdentifierToFlow(txID)).
 			Return(&flowResult, nil).
 			Times(1)
 
@@ -372,7 +372,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetTransactionResult(convert.SDKIdentifierToFlow(txID)).
+			GetTransactionResult(gomock.Any(), convert.SDKIdentifierToFlow(txID)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -392,7 +392,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetAccount(convert.SDKAddressToFlow(address)).
+			GetAccount(gomock.Any(), convert.SDKAddressToFlow(address)).
 			Return(&account, nil).
 			Times(1)
 
@@ -402,7 +402,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetAccount(convert.SDKAddressToFlow(address)).
+			GetAccount(gomock.Any(), convert.SDKAddressToFlow(address)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -422,7 +422,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetAccount(convert.SDKAddressToFlow(address)).
+			GetAccount(gomock.Any(), convert.SDKAddressToFlow(address)).
 			Return(&account, nil).
 			Times(1)
 
@@ -432,7 +432,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetAccount(convert.SDKAddressToFlow(address)).
+			GetAccount(gomock.Any(), convert.SDKAddressToFlow(address)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -454,7 +454,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetAccountAtBlockHeight(convert.SDKAddressToFlow(address), height).
+			GetAccountAtBlockHeight(gomock.Any(), convert.SDKAddressToFlow(address), height).
 			Return(&account, nil).
 			Times(1)
 
@@ -464,7 +464,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetAccountAtBlockHeight(convert.SDKAddressToFlow(address), height).
+			GetAccountAtBlockHeight(gomock.Any(), convert.SDKAddressToFlow(address), height).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -491,12 +491,12 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(flowBlock, nil).
 			Times(1)
 
 		emu.EXPECT().
-			ExecuteScriptAtBlockHeight(script, arguments, uint64(42)).
+			ExecuteScriptAtBlockHeight(gomock.Any(), script, arguments, uint64(42)).
 			Return(&emulatorResult, nil).
 			Times(1)
 
@@ -506,12 +506,12 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetLatestBlock().
+			GetLatestBlock(gomock.Any()).
 			Return(flowBlock, nil).
 			Times(1)
 
 		emu.EXPECT().
-			ExecuteScriptAtBlockHeight(script, arguments, uint64(42)).
+			ExecuteScriptAtBlockHeight(gomock.Any(), script, arguments, uint64(42)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -533,7 +533,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			ExecuteScriptAtBlockHeight(script, arguments, height).
+			ExecuteScriptAtBlockHeight(gomock.Any(), script, arguments, height).
 			Return(&emulatorResult, nil).
 			Times(1)
 
@@ -543,7 +543,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			ExecuteScriptAtBlockHeight(script, arguments, height).
+			ExecuteScriptAtBlockHeight(gomock.Any(), script, arguments, height).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -553,6 +553,37 @@ func TestSDK(t *testing.T) {
 
 	}))
 
+	t.Run("ExecuteScriptAtPendingBlock", sdkTest(func(t *testing.T, adapter *SDKAdapter, emu *mocks.MockEmulator) {
+
+		script := []byte("some cadence code here")
+		var arguments [][]byte
+
+		stringValue, _ := cadence.NewString("42")
+		emulatorResult := types.ScriptResult{Value: stringValue}
+		expected, _ := convertScriptResult(&emulatorResult, nil)
+
+		//success
+		emu.EXPECT().
+			ExecuteScriptAtPendingBlock(gomock.Any(), script, arguments).
+			Return(&emulatorResult, nil).
+			Times(1)
+
+		result, err := adapter.ExecuteScriptAtPendingBlock(context.Background(), script, arguments)
+		assert.Equal(t, expected, result)
+		assert.NoError(t, err)
+
+		//fail
+		emu.EXPECT().
+			ExecuteScriptAtPendingBlock(gomock.Any(), script, arguments).
+			Return(nil, fmt.Errorf("some error")).
+			Times(1)
+
+		result, err = adapter.ExecuteScriptAtPendingBlock(context.Background(), script, arguments)
+		assert.Nil(t, result)
+		assert.Error(t, err)
+
+	}))
+
 	t.Run("ExecuteScriptAtBlockID", sdkTest(func(t *testing.T, adapter *SDKAdapter, emu *mocks.MockEmulator) {
 
 		script := []byte("some cadence code here")
@@ -571,12 +602,12 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetBlockByID(convert.SDKIdentifierToFlow(id)).
+			GetBlockByID(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(flowBlock, nil).
 			Times(1)
 
 		emu.EXPECT().
-			ExecuteScriptAtBlockHeight(script, arguments, uint64(42)).
+			ExecuteScriptAtBlockHeight(gomock.Any(), script, arguments, uint64(42)).
 			Return(&emulatorResult, nil).
 			Times(1)
 
@@ -586,12 +617,12 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetBlockByID(convert.SDKIdentifierToFlow(id)).
+			GetBlockByID(gomock.Any(), convert.SDKIdentifierToFlow(id)).
 			Return(flowBlock, nil).
 			Times(1)
 
 		emu.EXPECT().
-			ExecuteScriptAtBlockHeight(script, arguments, uint64(42)).
+			ExecuteScriptAtBlockHeight(gomock.Any(), script, arguments, uint64(42)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -612,7 +643,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetEventsForHeightRange(eventType, startHeight, endHeight).
+			GetEventsForHeightRange(gomock.Any(), eventType, startHeight, endHeight).
 			Return(flowEvents, nil).
 			Times(1)
 
@@ -622,7 +653,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetEventsForHeightRange(eventType, startHeight, endHeight).
+			GetEventsForHeightRange(gomock.Any(), eventType, startHeight, endHeight).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -642,7 +673,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetEventsForBlockIDs(eventType, convert.SDKIdentifiersToFlow(blockIDs)).
+			GetEventsForBlockIDs(gomock.Any(), eventType, convert.SDKIdentifiersToFlow(blockIDs)).
 			Return(flowEvents, nil).
 			Times(1)
 
@@ -652,7 +683,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetEventsForBlockIDs(eventType, convert.SDKIdentifiersToFlow(blockIDs)).
+			GetEventsForBlockIDs(gomock.Any(), eventType, convert.SDKIdentifiersToFlow(blockIDs)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -672,7 +703,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetTransactionsByBlockID(convert.SDKIdentifierToFlow(blockID)).
+			GetTransactionsByBlockID(gomock.Any(), convert.SDKIdentifierToFlow(blockID)).
 			Return(flowTransactions, nil).
 			Times(1)
 
@@ -682,7 +713,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetTransactionsByBlockID(convert.SDKIdentifierToFlow(blockID)).
+			GetTransactionsByBlockID(gomock.Any(), convert.SDKIdentifierToFlow(blockID)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -701,7 +732,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			GetTransactionResultsByBlockID(convert.SDKIdentifierToFlow(blockID)).
+			GetTransactionResultsByBlockID(gomock.Any(), convert.SDKIdentifierToFlow(blockID)).
 			Return(flowTransactionResults, nil).
 			Times(1)
 
@@ -711,7 +742,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			GetTransactionResultsByBlockID(convert.SDKIdentifierToFlow(blockID)).
+			GetTransactionResultsByBlockID(gomock.Any(), convert.SDKIdentifierToFlow(blockID)).
 			Return(nil, fmt.Errorf("some error")).
 			Times(1)
 
@@ -728,7 +759,7 @@ func TestSDK(t *testing.T) {
 
 		//success
 		emu.EXPECT().
-			SendTransaction(flowTransaction).
+			SendTransaction(gomock.Any(), flowTransaction).
 			Return(nil).
 			Times(1)
 
@@ -737,7 +768,7 @@ func TestSDK(t *testing.T) {
 
 		//fail
 		emu.EXPECT().
-			SendTransaction(flowTransaction).
+			SendTransaction(gomock.Any(), flowTransaction).
 			Return(fmt.Errorf("some error")).
 			Times(1)
 
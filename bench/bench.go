@@ -0,0 +1,401 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bench measures the throughput of the emulator for common workloads,
+// against each storage backend, so performance can be compared between releases.
+//
+// The benchmarks run with `go test -bench . ./bench`. Throughput can also be
+// checked against a baseline, see Compare.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	sdkcrypto "github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/templates"
+	"github.com/onflow/flow-go/fvm"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/memstore"
+	"github.com/onflow/flow-emulator/storage/redis"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+// RedisURLEnv is the environment variable with the URL of a Redis server to benchmark.
+// The Redis backend is only benchmarked if it is set. Use an empty database, as
+// the state of the emulator is kept in it.
+const RedisURLEnv = "FLOW_EMULATOR_BENCH_REDIS_URL"
+
+// Backend is a storage backend the workloads run against.
+type Backend struct {
+	Name string
+	// Open opens an empty store, keeping its files in the given directory.
+	Open func(dir string) (storage.Store, error)
+}
+
+// Backends returns the storage backends to benchmark.
+func Backends() []Backend {
+	backends := []Backend{
+		{
+			Name: "memstore",
+			Open: func(string) (storage.Store, error) {
+				return memstore.New(), nil
+			},
+		},
+		{
+			Name: "sqlite-memory",
+			Open: func(string) (storage.Store, error) {
+				return sqlite.New(sqlite.InMemory)
+			},
+		},
+		{
+			Name: "sqlite",
+			Open: func(dir string) (storage.Store, error) {
+				return sqlite.New(dir)
+			},
+		},
+	}
+
+	if url := os.Getenv(RedisURLEnv); url != "" {
+		backends = append(backends, Backend{
+			Name: "redis",
+			Open: func(string) (storage.Store, error) {
+				return redis.New(url)
+			},
+		})
+	}
+
+	return backends
+}
+
+// Workload is an operation whose throughput is measured.
+type Workload struct {
+	Name string
+	// Unit is the unit of the throughput, per second.
+	Unit string
+	Run  func(h *Harness) error
+}
+
+// Workloads returns the workloads to benchmark.
+func Workloads() []Workload {
+	return []Workload{
+		{Name: "transfers", Unit: "tx/s", Run: (*Harness).TransferTokens},
+		{Name: "deploys", Unit: "tx/s", Run: (*Harness).DeployContract},
+		{Name: "scripts", Unit: "scripts/s", Run: (*Harness).ExecuteScript},
+	}
+}
+
+const transferTokensTransaction = `
+import FungibleToken from %s
+import FlowToken from %s
+
+transaction(amount: UFix64, to: Address) {
+	let vault: @FungibleToken.Vault
+
+	prepare(signer: AuthAccount) {
+		let source = signer.borrow<&FlowToken.Vault>(from: /storage/flowTokenVault)
+			?? panic("Could not borrow the FLOW vault")
+		self.vault <- source.withdraw(amount: amount)
+	}
+
+	execute {
+		getAccount(to)
+			.getCapability(/public/flowTokenReceiver)
+			.borrow<&{FungibleToken.Receiver}>()!
+			.deposit(from: <-self.vault)
+	}
+}
+`
+
+const balanceScript = `
+import FungibleToken from %s
+
+pub fun main(address: Address): UFix64 {
+	return getAccount(address)
+		.getCapability(/public/flowTokenBalance)
+		.borrow<&{FungibleToken.Balance}>()!
+		.balance
+}
+`
+
+const benchContract = `
+pub contract Bench%d {
+	pub var count: UInt64
+
+	pub fun increment() {
+		self.count = self.count + 1
+	}
+
+	init() {
+		self.count = 0
+	}
+}
+`
+
+// Harness runs the workloads against an emulator.
+type Harness struct {
+	blockchain *emulator.Blockchain
+	adapter    *adapters.SDKAdapter
+	signer     sdkcrypto.Signer
+	serviceKey emulator.ServiceKey
+	recipient  flowsdk.Address
+
+	transferScript   []byte
+	balanceScript    []byte
+	balanceArguments [][]byte
+	deployed         int
+}
+
+// NewHarness returns a harness running the workloads against an emulator using the given store.
+// Blocks are committed after each transaction, like with auto-mining.
+func NewHarness(store storage.Store) (*Harness, error) {
+	blockchain, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithServerLogger(zerolog.Nop()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, blockchain)
+
+	serviceKey := blockchain.ServiceKey()
+	signer, err := serviceKey.Signer()
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err := adapter.CreateAccount(context.Background(), nil, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the recipient account: %w", err)
+	}
+
+	balanceArgument, err := jsoncdc.Encode(cadence.NewAddress(recipient))
+	if err != nil {
+		return nil, err
+	}
+
+	chain := blockchain.GetChain()
+
+	return &Harness{
+		blockchain: blockchain,
+		adapter:    adapter,
+		signer:     signer,
+		serviceKey: blockchain.ServiceKey(),
+		recipient:  recipient,
+		transferScript: []byte(fmt.Sprintf(
+			transferTokensTransaction,
+			fvm.FungibleTokenAddress(chain).HexWithPrefix(),
+			fvm.FlowTokenAddress(chain).HexWithPrefix(),
+		)),
+		balanceScript: []byte(fmt.Sprintf(
+			balanceScript,
+			fvm.FungibleTokenAddress(chain).HexWithPrefix(),
+		)),
+		balanceArguments: [][]byte{balanceArgument},
+	}, nil
+}
+
+// Blockchain returns the emulator the workloads run against.
+func (h *Harness) Blockchain() *emulator.Blockchain {
+	return h.blockchain
+}
+
+// TransferTokens transfers FLOW from the service account to another account,
+// in a block of its own.
+func (h *Harness) TransferTokens() error {
+	tx := flowsdk.NewTransaction().
+		SetScript(h.transferScript).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(h.serviceKey.Address, h.serviceKey.Index, h.serviceKey.SequenceNumber).
+		SetPayer(h.serviceKey.Address).
+		AddAuthorizer(h.serviceKey.Address)
+
+	err := tx.AddArgument(cadence.UFix64(100_000))
+	if err != nil {
+		return err
+	}
+	err = tx.AddArgument(cadence.NewAddress(h.recipient))
+	if err != nil {
+		return err
+	}
+
+	err = tx.SignEnvelope(h.serviceKey.Address, h.serviceKey.Index, h.signer)
+	if err != nil {
+		return err
+	}
+
+	err = h.adapter.SendTransaction(context.Background(), *tx)
+	if err != nil {
+		return err
+	}
+
+	_, results, err := h.blockchain.ExecuteAndCommitBlock()
+	if err != nil {
+		return err
+	}
+	if results[0].Error != nil {
+		return fmt.Errorf("transfer failed: %w", results[0].Error)
+	}
+
+	h.serviceKey.SequenceNumber++
+
+	return nil
+}
+
+// DeployContract creates an account with a new contract, in a block of its own.
+func (h *Harness) DeployContract() error {
+	name := fmt.Sprintf("Bench%d", h.deployed)
+
+	_, err := h.adapter.CreateAccount(
+		context.Background(),
+		nil,
+		[]templates.Contract{{
+			Name:   name,
+			Source: fmt.Sprintf(benchContract, h.deployed),
+		}},
+		0,
+	)
+	if err != nil {
+		return err
+	}
+
+	h.deployed++
+	h.serviceKey.SequenceNumber++
+
+	return nil
+}
+
+// ExecuteScript reads the FLOW balance of an account at the latest block.
+// It is safe to call concurrently.
+func (h *Harness) ExecuteScript() error {
+	result, err := h.blockchain.ExecuteScript(context.Background(), h.balanceScript, h.balanceArguments)
+	if err != nil {
+		return err
+	}
+
+	return result.Error
+}
+
+// Close closes the store of the emulator, if it needs closing.
+func (h *Harness) Close() error {
+	if closer, ok := h.blockchain.Storage().(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Result is the throughput of a workload against a backend.
+type Result struct {
+	Workload   string  `json:"workload"`
+	Backend    string  `json:"backend"`
+	Unit       string  `json:"unit"`
+	Throughput float64 `json:"throughput"`
+}
+
+// Key identifies the workload and the backend of the result in a baseline.
+func (r Result) Key() string {
+	return r.Workload + "/" + r.Backend
+}
+
+// Measure runs the workload the given number of times against a new emulator using
+// the backend, and returns its throughput. The setup of the emulator is not measured.
+func Measure(backend Backend, workload Workload, operations int) (Result, error) {
+	dir, err := os.MkdirTemp("", "flow-emulator-bench-")
+	if err != nil {
+		return Result{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := backend.Open(filepath.Join(dir, backend.Name))
+	if err != nil {
+		return Result{}, err
+	}
+
+	harness, err := NewHarness(store)
+	if err != nil {
+		return Result{}, err
+	}
+	defer harness.Close()
+
+	start := time.Now()
+	for i := 0; i < operations; i++ {
+		err := workload.Run(harness)
+		if err != nil {
+			return Result{}, fmt.Errorf("%s against %s: %w", workload.Name, backend.Name, err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	return Result{
+		Workload:   workload.Name,
+		Backend:    backend.Name,
+		Unit:       workload.Unit,
+		Throughput: float64(operations) / elapsed.Seconds(),
+	}, nil
+}
+
+// Regression is a result whose throughput dropped below the tolerance of its baseline.
+type Regression struct {
+	Result
+	Baseline float64 `json:"baseline"`
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf(
+		"%s: %.1f %s, %.0f%% below the baseline of %.1f %s",
+		r.Key(),
+		r.Throughput,
+		r.Unit,
+		100*(1-r.Throughput/r.Baseline),
+		r.Baseline,
+		r.Unit,
+	)
+}
+
+// Compare returns the results whose throughput is lower than their baseline by more
+// than the tolerance, a fraction of the baseline. Results without a baseline are ignored.
+//
+// The baseline maps the keys of results to their throughput, e.g. as measured by a
+// previous release on the same machine.
+func Compare(baseline map[string]float64, results []Result, tolerance float64) []Regression {
+	var regressions []Regression
+	for _, result := range results {
+		expected, ok := baseline[result.Key()]
+		if !ok || expected <= 0 {
+			continue
+		}
+		if result.Throughput < expected*(1-tolerance) {
+			regressions = append(regressions, Regression{Result: result, Baseline: expected})
+		}
+	}
+	return regressions
+}
@@ -0,0 +1,180 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bench_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/bench"
+)
+
+var (
+	baselinePath = flag.String("bench.baseline", "", "check the throughput against the baseline in the given file")
+	update       = flag.Bool("bench.update", false, "write the measured throughput to the baseline file instead")
+	tolerance    = flag.Float64("bench.tolerance", 0.2, "fraction of the baseline the throughput may drop by")
+	operations   = flag.Int("bench.operations", 100, "number of operations measured per workload and backend")
+)
+
+func workload(tb testing.TB, name string) bench.Workload {
+	for _, workload := range bench.Workloads() {
+		if workload.Name == name {
+			return workload
+		}
+	}
+	tb.Fatalf("unknown workload %s", name)
+	return bench.Workload{}
+}
+
+func newHarness(b *testing.B, backend bench.Backend) *bench.Harness {
+	store, err := backend.Open(filepath.Join(b.TempDir(), backend.Name))
+	require.NoError(b, err)
+
+	harness, err := bench.NewHarness(store)
+	require.NoError(b, err)
+	b.Cleanup(func() {
+		_ = harness.Close()
+	})
+
+	return harness
+}
+
+// benchmarkSequential runs the workload sequentially against each backend.
+func benchmarkSequential(b *testing.B, workload bench.Workload) {
+	for _, backend := range bench.Backends() {
+		backend := backend
+		b.Run(backend.Name, func(b *testing.B) {
+			harness := newHarness(b, backend)
+
+			b.ResetTimer()
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				err := workload.Run(harness)
+				require.NoError(b, err)
+			}
+			b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), workload.Unit)
+		})
+	}
+}
+
+func BenchmarkTokenTransfers(b *testing.B) {
+	benchmarkSequential(b, workload(b, "transfers"))
+}
+
+func BenchmarkContractDeploys(b *testing.B) {
+	benchmarkSequential(b, workload(b, "deploys"))
+}
+
+func BenchmarkScripts(b *testing.B) {
+	scripts := workload(b, "scripts")
+
+	for _, backend := range bench.Backends() {
+		backend := backend
+		b.Run(backend.Name, func(b *testing.B) {
+			harness := newHarness(b, backend)
+
+			b.ResetTimer()
+			start := time.Now()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					err := scripts.Run(harness)
+					if err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), scripts.Unit)
+		})
+	}
+}
+
+// TestThroughputRegression measures every workload against every backend, and fails
+// if the throughput dropped below the baseline given with -bench.baseline. With
+// -bench.update, the measured throughput is written to the baseline instead, e.g.
+//
+//	go test ./bench -run TestThroughputRegression -bench.baseline baseline.json -bench.update
+func TestThroughputRegression(t *testing.T) {
+	if *baselinePath == "" {
+		t.Skip("no baseline given with -bench.baseline")
+	}
+
+	var results []bench.Result
+	for _, backend := range bench.Backends() {
+		for _, workload := range bench.Workloads() {
+			result, err := bench.Measure(backend, workload, *operations)
+			require.NoError(t, err)
+
+			t.Logf("%s: %.1f %s", result.Key(), result.Throughput, result.Unit)
+			results = append(results, result)
+		}
+	}
+
+	if *update {
+		baseline := make(map[string]float64, len(results))
+		for _, result := range results {
+			baseline[result.Key()] = result.Throughput
+		}
+
+		data, err := json.MarshalIndent(baseline, "", "  ")
+		require.NoError(t, err)
+
+		err = os.WriteFile(*baselinePath, data, 0644)
+		require.NoError(t, err)
+		return
+	}
+
+	data, err := os.ReadFile(*baselinePath)
+	require.NoError(t, err)
+
+	var baseline map[string]float64
+	err = json.Unmarshal(data, &baseline)
+	require.NoError(t, err)
+
+	for _, regression := range bench.Compare(baseline, results, *tolerance) {
+		t.Errorf("throughput regression: %s", regression)
+	}
+}
+
+func TestCompare(t *testing.T) {
+
+	t.Parallel()
+
+	results := []bench.Result{
+		{Workload: "transfers", Backend: "memstore", Unit: "tx/s", Throughput: 70},
+		{Workload: "transfers", Backend: "sqlite", Unit: "tx/s", Throughput: 85},
+		{Workload: "scripts", Backend: "memstore", Unit: "scripts/s", Throughput: 10},
+	}
+
+	regressions := bench.Compare(map[string]float64{
+		"transfers/memstore": 100,
+		"transfers/sqlite":   100,
+	}, results, 0.2)
+
+	require.Len(t, regressions, 1)
+	require.Equal(t, "transfers/memstore", regressions[0].Key())
+	require.Equal(t, float64(100), regressions[0].Baseline)
+	require.Equal(t, "transfers/memstore: 70.0 tx/s, 30% below the baseline of 100.0 tx/s", regressions[0].String())
+}
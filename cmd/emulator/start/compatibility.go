@@ -0,0 +1,108 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package start
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/util"
+)
+
+// checkStorageCmd reports whether the current emulator can execute against
+// persisted state, selected with the same storage flags used to start it.
+func checkStorageCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check-storage",
+		Short: "Checks that persisted contracts and stored values can be loaded by this emulator version",
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := initLogger(conf.Verbose)
+
+			store, err := openPersistedStorage()
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
+			chainID, err := getSDKChainID(conf.ChainID)
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
+			options := []emulator.Option{
+				emulator.WithStore(store),
+				emulator.WithChainID(chainID),
+				emulator.WithReadOnly(true),
+				emulator.WithAttachmentsEnabled(conf.Attachments),
+				emulator.WithAccountLinkingEnabled(conf.AccountLinking),
+				emulator.WithCapabilityControllersEnabled(conf.CapabilityControllers),
+			}
+			if conf.SimpleAddresses {
+				options = append(options, emulator.WithSimpleAddresses())
+			}
+
+			blockchain, err := emulator.New(options...)
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
+			report, err := blockchain.CheckCompatibility(context.Background())
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
+			for _, issue := range report.Issues {
+				logger.Error().Msgf("❗  %s", issue)
+			}
+
+			summary := logger.Info().
+				Int("accounts", report.Accounts).
+				Int("contracts", report.Contracts).
+				Int("storedValues", report.StoredValues)
+
+			if !report.Compatible() {
+				summary.Msgf("Found %d incompatible contracts and stored values", len(report.Issues))
+				Exit(1, "")
+			}
+
+			summary.Msg("✅  Persisted state is compatible with this emulator version")
+		},
+	}
+}
+
+// openPersistedStorage opens the store selected by --redis-url, --sqlite-url,
+// --storage-provider or --persist/--dbpath.
+func openPersistedStorage() (storage.Store, error) {
+	switch {
+	case conf.RedisURL != "":
+		return util.NewRedisStorage(conf.RedisURL)
+	case conf.SqliteURL != "":
+		return util.NewSqliteStorage(conf.SqliteURL, storage.DurabilityDefault)
+	case conf.StorageProvider != "":
+		name, dsn := parseStorageProvider(conf.StorageProvider)
+		return storage.Open(name, dsn)
+	case conf.Persist:
+		return util.NewSqliteStorage(conf.DBPath, storage.DurabilityDefault)
+	default:
+		return nil, fmt.Errorf("❗  a persisted storage is required, use --redis-url, --sqlite-url, --storage-provider or --persist")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package start
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const configFileFlag = "config"
+
+// applyConfigFile sets flags from a YAML configuration file, which maps flag
+// names to values. Flags given on the command line or through their
+// environment variable take precedence over the file.
+func applyConfigFile(flags *pflag.FlagSet, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var values map[string]yaml.Node
+	err = yaml.Unmarshal(content, &values)
+	if err != nil {
+		return fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	envVars := flagEnvVars()
+
+	for _, name := range names {
+		flag := flags.Lookup(name)
+		if flag == nil || name == configFileFlag {
+			return fmt.Errorf("unknown option %q in configuration file %s", name, path)
+		}
+
+		if flag.Changed {
+			continue
+		}
+		if _, ok := os.LookupEnv(envVars[name]); ok {
+			continue
+		}
+
+		node := values[name]
+		value, err := configValue(&node)
+		if err != nil {
+			return fmt.Errorf("invalid value for %q in configuration file %s: %w", name, path, err)
+		}
+
+		err = flags.Set(name, value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %q in configuration file %s: %w", name, path, err)
+		}
+	}
+
+	return nil
+}
+
+// configValue returns the flag value of a configuration file entry. Values are
+// kept as written, so they are parsed the same way as on the command line.
+// Lists are joined with commas.
+func configValue(node *yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value, nil
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return "", fmt.Errorf("lists may only contain plain values")
+			}
+			items = append(items, item.Value)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("expected a value or a list of values")
+	}
+}
+
+// flagEnvVars maps the name of every configuration flag to its environment variable.
+func flagEnvVars() map[string]string {
+	envVars := make(map[string]string)
+
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		flag, ok := field.Tag.Lookup("flag")
+		if !ok {
+			continue
+		}
+		name := strings.Split(flag, ",")[0]
+		envVars[name] = fmt.Sprintf("%s_%s", EnvPrefix, strings.ToUpper(field.Name))
+	}
+
+	return envVars
+}
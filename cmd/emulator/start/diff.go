@@ -0,0 +1,177 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package start
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/util"
+)
+
+// diffStorageCmd compares the execution state of two persisted sqlite stores,
+// or of snapshots of them, for example to verify that a migration or an
+// emulator upgrade did not alter the state.
+func diffStorageCmd() *cobra.Command {
+	var fromSnapshot, toSnapshot string
+
+	cmd := &cobra.Command{
+		Use:   "diff-storage <from> <to>",
+		Short: "Lists the accounts, contracts and registers which differ between two persisted stores",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := initLogger(conf.Verbose)
+
+			from, err := openDiffedStorage(args[0], fromSnapshot)
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
+			to, err := openDiffedStorage(args[1], toSnapshot)
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
+			diff, err := storage.DiffState(context.Background(), from, to)
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
+			if diff.Empty() {
+				logger.Info().Msg("✅  Both stores hold the same state")
+				return
+			}
+
+			printStateDiff(cmd, diff)
+
+			logger.Warn().
+				Int("accounts", len(diff.Accounts())).
+				Int("registers", len(diff.Registers)).
+				Msg("❗  The stores hold different states")
+			Exit(1, "")
+		},
+	}
+
+	cmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "", "compare the named snapshot of the first store")
+	cmd.Flags().StringVar(&toSnapshot, "to-snapshot", "", "compare the named snapshot of the second store")
+
+	return cmd
+}
+
+// openDiffedStorage opens a sqlite store, and loads the given snapshot of it if any.
+func openDiffedStorage(path string, snapshot string) (storage.Store, error) {
+	store, err := util.NewSqliteStorage(path, storage.DurabilityDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot == "" {
+		return store, nil
+	}
+
+	snapshotProvider, ok := store.(storage.SnapshotProvider)
+	if !ok || !snapshotProvider.SupportSnapshotsWithCurrentConfig() {
+		return nil, fmt.Errorf("❗  storage at %s does not support snapshots", path)
+	}
+
+	err = snapshotProvider.LoadSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// printStateDiff writes the differences to stdout, grouped by account. Values
+// only in the first store are marked with -, values only in the second one
+// with +, and changed values with ~.
+func printStateDiff(cmd *cobra.Command, diff *storage.StateDiff) {
+	out := cmd.OutOrStdout()
+
+	// the registers are sorted by owner, so the registers of an account are consecutive
+	registers := diff.Registers
+	for len(registers) > 0 {
+		owner := registers[0].ID.Owner
+		end := 1
+		for end < len(registers) && registers[end].ID.Owner == owner {
+			end++
+		}
+		accountRegisters := registers[:end]
+		registers = registers[end:]
+
+		if owner == "" {
+			_, _ = fmt.Fprintf(out, "global registers\n")
+		} else {
+			address := flowgo.BytesToAddress([]byte(owner))
+			_, _ = fmt.Fprintf(out, "%s account %s\n", accountMark(accountRegisters), address.HexWithPrefix())
+		}
+
+		for _, register := range accountRegisters {
+			if name, ok := register.ContractName(); ok {
+				_, _ = fmt.Fprintf(out, "  %s contract %s\n", registerMark(register), name)
+				continue
+			}
+			_, _ = fmt.Fprintf(out, "  %s register %s\n", registerMark(register), registerKey(register.ID))
+		}
+	}
+}
+
+// registerKey formats the key of a register readably: slab keys by their
+// index, printable keys as is, and other keys as hex.
+func registerKey(id flowgo.RegisterID) string {
+	key := []byte(id.Key)
+	if len(key) == 9 && key[0] == '$' {
+		return fmt.Sprintf("$%d", binary.BigEndian.Uint64(key[1:]))
+	}
+
+	for _, c := range key {
+		if c < 0x20 || c > 0x7e {
+			return hex.EncodeToString(key)
+		}
+	}
+	return id.Key
+}
+
+func registerMark(register storage.RegisterDiff) string {
+	switch {
+	case register.From == nil:
+		return "+"
+	case register.To == nil:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// accountMark marks an account as added or removed if all its registers are.
+func accountMark(registers []storage.RegisterDiff) string {
+	mark := registerMark(registers[0])
+	for _, register := range registers[1:] {
+		if registerMark(register) != mark {
+			return "~"
+		}
+	}
+	return mark
+}
@@ -0,0 +1,338 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package start
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/util"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// inspectConfig holds the flags of the inspect subcommands.
+type inspectConfig struct {
+	db        string
+	start     uint64
+	end       uint64
+	limit     uint64
+	eventType string
+}
+
+// inspectCmd reads persisted state without starting any servers, so the state
+// of a stopped or crashed emulator can be examined. Each result is written to
+// stdout as a JSON object per line.
+func inspectCmd() *cobra.Command {
+	inspectConf := &inspectConfig{}
+
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspects persisted emulator state without starting the emulator",
+	}
+
+	cmd.PersistentFlags().StringVar(
+		&inspectConf.db,
+		"db",
+		"",
+		"path to a sqlite database directory to inspect, instead of the storage flags used to start the emulator",
+	)
+
+	blocks := &cobra.Command{
+		Use:   "blocks",
+		Short: "Lists the committed blocks",
+		Run: func(cmd *cobra.Command, args []string) {
+			runInspect(inspectConf, inspectBlocks)
+		},
+	}
+	addHeightRangeFlags(blocks, inspectConf)
+
+	accounts := &cobra.Command{
+		Use:   "accounts",
+		Short: "Lists the accounts with their balances, keys and contracts",
+		Run: func(cmd *cobra.Command, args []string) {
+			runInspect(inspectConf, inspectAccounts)
+		},
+	}
+	accounts.Flags().Uint64Var(&inspectConf.limit, "limit", 0, "maximum number of accounts to list (0 lists all)")
+
+	events := &cobra.Command{
+		Use:   "events",
+		Short: "Lists the events emitted by committed blocks",
+		Run: func(cmd *cobra.Command, args []string) {
+			runInspect(inspectConf, inspectEvents)
+		},
+	}
+	addHeightRangeFlags(events, inspectConf)
+	events.Flags().StringVar(&inspectConf.eventType, "type", "", "only list events of this type")
+
+	cmd.AddCommand(blocks, accounts, events)
+
+	return cmd
+}
+
+func addHeightRangeFlags(cmd *cobra.Command, inspectConf *inspectConfig) {
+	cmd.Flags().Uint64Var(&inspectConf.start, "start", 0, "first block height to inspect")
+	cmd.Flags().Uint64Var(&inspectConf.end, "end", 0, "last block height to inspect (defaults to the latest block)")
+}
+
+type inspectFunc func(
+	ctx context.Context,
+	blockchain *emulator.Blockchain,
+	inspectConf *inspectConfig,
+	encoder *json.Encoder,
+) error
+
+func runInspect(inspectConf *inspectConfig, inspect inspectFunc) {
+	var (
+		store storage.Store
+		err   error
+	)
+	if inspectConf.db != "" {
+		store, err = util.NewSqliteStorage(inspectConf.db, storage.DurabilityDefault)
+	} else {
+		store, err = openPersistedStorage()
+	}
+	if err != nil {
+		Exit(1, err.Error())
+	}
+
+	chainID, err := getSDKChainID(conf.ChainID)
+	if err != nil {
+		Exit(1, err.Error())
+	}
+
+	options := []emulator.Option{
+		emulator.WithStore(store),
+		emulator.WithChainID(chainID),
+		emulator.WithReadOnly(true),
+	}
+	if conf.SimpleAddresses {
+		options = append(options, emulator.WithSimpleAddresses())
+	}
+
+	blockchain, err := emulator.New(options...)
+	if err != nil {
+		Exit(1, err.Error())
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+
+	err = inspect(context.Background(), blockchain, inspectConf, encoder)
+	if err != nil {
+		Exit(1, err.Error())
+	}
+}
+
+// heightRange returns the block heights selected with --start and --end.
+func heightRange(
+	ctx context.Context,
+	blockchain *emulator.Blockchain,
+	inspectConf *inspectConfig,
+) (uint64, uint64, error) {
+	latest, err := blockchain.GetLatestBlock(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end := inspectConf.end
+	if end == 0 || end > latest.Header.Height {
+		end = latest.Header.Height
+	}
+
+	if inspectConf.start > end {
+		return 0, 0, fmt.Errorf("❗  --start %d is after the last block height %d", inspectConf.start, end)
+	}
+
+	return inspectConf.start, end, nil
+}
+
+// inspectBatchSize is the number of blocks read from storage at once.
+const inspectBatchSize = 100
+
+type inspectedBlock struct {
+	Height       uint64    `json:"height"`
+	ID           string    `json:"id"`
+	ParentID     string    `json:"parentId"`
+	Timestamp    time.Time `json:"timestamp"`
+	Collections  int       `json:"collections"`
+	Transactions int       `json:"transactions"`
+}
+
+func inspectBlocks(
+	ctx context.Context,
+	blockchain *emulator.Blockchain,
+	inspectConf *inspectConfig,
+	encoder *json.Encoder,
+) error {
+	start, end, err := heightRange(ctx, blockchain, inspectConf)
+	if err != nil {
+		return err
+	}
+
+	for batchStart := start; batchStart <= end; batchStart += inspectBatchSize {
+		batchEnd := batchStart + inspectBatchSize - 1
+		if batchEnd > end {
+			batchEnd = end
+		}
+
+		blocks, err := blockchain.GetBlocksByHeightRange(ctx, batchStart, batchEnd)
+		if err != nil {
+			return err
+		}
+
+		for _, block := range blocks {
+			transactions := 0
+			for _, guarantee := range block.Payload.Guarantees {
+				collection, err := blockchain.GetCollectionByID(ctx, guarantee.CollectionID)
+				if err != nil {
+					return err
+				}
+				transactions += len(collection.Transactions)
+			}
+
+			err = encoder.Encode(inspectedBlock{
+				Height:       block.Header.Height,
+				ID:           block.ID().String(),
+				ParentID:     block.Header.ParentID.String(),
+				Timestamp:    block.Header.Timestamp,
+				Collections:  len(block.Payload.Guarantees),
+				Transactions: transactions,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type inspectedAccount struct {
+	Address   string   `json:"address"`
+	Balance   uint64   `json:"balance"`
+	Keys      int      `json:"keys"`
+	Contracts []string `json:"contracts"`
+}
+
+func inspectAccounts(
+	ctx context.Context,
+	blockchain *emulator.Blockchain,
+	inspectConf *inspectConfig,
+	encoder *json.Encoder,
+) error {
+	for index := uint(1); inspectConf.limit == 0 || uint64(index) <= inspectConf.limit; index++ {
+		account, err := blockchain.GetAccountByIndex(ctx, index)
+		var notFoundErr *types.AccountNotFoundError
+		if errors.As(err, &notFoundErr) || (err == nil && account == nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		contracts := make([]string, 0, len(account.Contracts))
+		for name := range account.Contracts {
+			contracts = append(contracts, name)
+		}
+		sort.Strings(contracts)
+
+		err = encoder.Encode(inspectedAccount{
+			Address:   account.Address.HexWithPrefix(),
+			Balance:   account.Balance,
+			Keys:      len(account.Keys),
+			Contracts: contracts,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type inspectedEvent struct {
+	BlockHeight      uint64          `json:"blockHeight"`
+	TransactionID    string          `json:"transactionId"`
+	TransactionIndex uint32          `json:"transactionIndex"`
+	EventIndex       uint32          `json:"eventIndex"`
+	Type             string          `json:"type"`
+	Payload          json.RawMessage `json:"payload"`
+}
+
+func inspectEvents(
+	ctx context.Context,
+	blockchain *emulator.Blockchain,
+	inspectConf *inspectConfig,
+	encoder *json.Encoder,
+) error {
+	start, end, err := heightRange(ctx, blockchain, inspectConf)
+	if err != nil {
+		return err
+	}
+
+	for height := start; height <= end; height++ {
+		events, err := blockchain.GetEventsByHeight(ctx, height, inspectConf.eventType)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			payload, err := eventPayloadJSON(event)
+			if err != nil {
+				return err
+			}
+
+			err = encoder.Encode(inspectedEvent{
+				BlockHeight:      height,
+				TransactionID:    event.TransactionID.String(),
+				TransactionIndex: event.TransactionIndex,
+				EventIndex:       event.EventIndex,
+				Type:             string(event.Type),
+				Payload:          payload,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// eventPayloadJSON re-encodes the stored event payload as JSON-Cadence.
+func eventPayloadJSON(event flowgo.Event) (json.RawMessage, error) {
+	sdkEvent, err := convert.FlowEventToSDK(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsoncdc.Encode(sdkEvent.Value)
+}
@@ -0,0 +1,65 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package start
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+// migrateStorageCmd upgrades persisted sqlite storage, selected with the same
+// --sqlite-url or --persist/--dbpath flags used to start the emulator.
+func migrateStorageCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate-storage",
+		Short: "Migrates persisted emulator storage to the current schema version",
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := initLogger(conf.Verbose)
+
+			url := conf.SqliteURL
+			if url == "" {
+				if !conf.Persist {
+					Exit(1, "❗  migrate-storage requires --sqlite-url or --persist")
+				}
+				url = conf.DBPath
+			}
+
+			migrations, err := sqlite.Migrate(url)
+			for _, migration := range migrations {
+				if migration.From == migration.To {
+					logger.Info().
+						Str("path", migration.Path).
+						Uint64("version", migration.To).
+						Msg("✅  Storage schema is up to date")
+					continue
+				}
+
+				logger.Info().
+					Str("path", migration.Path).
+					Uint64("from", migration.From).
+					Uint64("to", migration.To).
+					Msg("✅  Migrated storage schema")
+			}
+			if err != nil {
+				Exit(1, err.Error())
+			}
+		},
+	}
+}
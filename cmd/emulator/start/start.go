@@ -21,6 +21,7 @@ package start
 import (
 	"encoding/hex"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -36,44 +37,86 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/onflow/flow-emulator/server"
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/chaos"
 )
 
 type Config struct {
-	Port                     int           `default:"3569" flag:"port,p" info:"port to run RPC server"`
-	DebuggerPort             int           `default:"2345" flag:"debugger-port" info:"port to run the Debugger (Debug Adapter Protocol)"`
-	RestPort                 int           `default:"8888" flag:"rest-port" info:"port to run the REST API"`
-	AdminPort                int           `default:"8080" flag:"admin-port" info:"port to run the admin API"`
+	Port                     int           `default:"3569" flag:"port,p" info:"port to run RPC server, 0 to pick a free port"`
+	DebuggerPort             int           `default:"2345" flag:"debugger-port" info:"port to run the Debugger (Debug Adapter Protocol), 0 to pick a free port"`
+	RestPort                 int           `default:"8888" flag:"rest-port" info:"port to run the REST API, 0 to pick a free port"`
+	AdminPort                int           `default:"8080" flag:"admin-port" info:"port to run the admin API, 0 to pick a free port"`
 	Verbose                  bool          `default:"false" flag:"verbose,v" info:"enable verbose logging"`
 	LogFormat                string        `default:"text" flag:"log-format" info:"logging output format. Valid values (text, JSON)"`
 	BlockTime                time.Duration `flag:"block-time,b" info:"time between sealed blocks, e.g. '300ms', '-1.5h' or '2h45m'. Valid units are 'ns', 'us' (or 'µs'), 'ms', 's', 'm', 'h'"`
+	ConsensusDelay           time.Duration `flag:"consensus-delay" info:"delay between a transaction being accepted and its inclusion in a block when auto-mining, e.g. '3s'. Transactions sent during the delay share a block"`
+	DeterministicTime        string        `default:"" flag:"deterministic-time" info:"timestamp of the first block in RFC 3339 format, e.g. '2023-01-01T00:00:00Z'. Blocks are timestamped --deterministic-time-step apart and views increment by one, so block IDs are the same across runs"`
+	DeterministicTimeStep    time.Duration `flag:"deterministic-time-step" info:"time between the timestamps of blocks with --deterministic-time, e.g. '10s'. Defaults to '1s'"`
 	ServicePrivateKey        string        `flag:"service-priv-key" info:"service account private key"`
 	ServicePublicKey         string        `flag:"service-pub-key" info:"service account public key"`
 	ServiceKeySigAlgo        string        `default:"ECDSA_P256" flag:"service-sig-algo" info:"service account key signature algorithm"`
 	ServiceKeyHashAlgo       string        `default:"SHA3_256" flag:"service-hash-algo" info:"service account key hash algorithm"`
 	Init                     bool          `default:"false" flag:"init" info:"whether to initialize a new account profile"`
 	GRPCDebug                bool          `default:"false" flag:"grpc-debug" info:"enable gRPC server reflection for debugging with grpc_cli"`
+	GRPCMaxRecvMsgSize       int           `default:"20971520" flag:"grpc-max-recv-msg-size" info:"maximum size of gRPC requests in bytes, e.g. of large scripts"`
+	GRPCMaxSendMsgSize       int           `default:"20971520" flag:"grpc-max-send-msg-size" info:"maximum size of gRPC responses in bytes, e.g. of large event result sets. Clients may need to raise their receive limit too"`
+	GRPCMessageSizeErrors    bool          `default:"false" flag:"grpc-message-size-errors" info:"measure every gRPC response, so responses larger than the maximum size fail with an actionable error"`
 	RESTDebug                bool          `default:"false" flag:"rest-debug" info:"enable REST API debugging output"`
 	Persist                  bool          `default:"false" flag:"persist" info:"enable persistent storage"`
 	Snapshot                 bool          `default:"false" flag:"snapshot" info:"enable snapshots for emulator (this setting also automatically turns on persistent storage)"`
+	SnapshotInterval         uint64        `default:"0" flag:"snapshot-interval" info:"create a snapshot every given number of blocks, named after the block height (e.g. 'auto-100'). 0 disables automatic snapshots"`
+	SnapshotKeep             int           `default:"10" flag:"snapshot-keep" info:"number of the latest automatic snapshots to keep, older ones are deleted"`
+	StateHistory             uint64        `default:"0" flag:"state-history" info:"number of the latest blocks whose state is kept, the state of older blocks is pruned. 0 keeps the state of all blocks"`
+	Standby                  bool          `default:"false" flag:"standby" info:"keep the state once the emulator is bootstrapped as a standby state, which DELETE /emulator/state returns to in milliseconds. Blocks committed afterwards are kept in memory"`
 	DBPath                   string        `default:"./flowdb" flag:"dbpath" info:"path to database directory"`
 	SimpleAddresses          bool          `default:"false" flag:"simple-addresses" info:"use sequential addresses starting with 0x01"`
 	TokenSupply              string        `default:"1000000000.0" flag:"token-supply" info:"initial FLOW token supply"`
 	TransactionExpiry        int           `default:"10" flag:"transaction-expiry" info:"transaction expiry, measured in blocks"`
+	TransactionExpiryBuffer  int           `default:"0" flag:"transaction-expiry-buffer" info:"number of blocks before their expiry transactions are rejected"`
+	BootstrapAccounts        int           `default:"0" flag:"bootstrap-accounts" info:"number of pre-funded test accounts to create on start, with deterministic keys listed by the admin API config endpoint"`
+	BootstrapAccountBalance  string        `default:"1000.0" flag:"bootstrap-account-balance" info:"initial FLOW balance of each test account created with --bootstrap-accounts"`
 	StorageLimitEnabled      bool          `default:"true" flag:"storage-limit" info:"enable account storage limit"`
 	StorageMBPerFLOW         string        `flag:"storage-per-flow" info:"the MB amount of storage capacity an account has per 1 FLOW token it has. e.g. '100.0'. The default is taken from the current version of flow-go"`
 	MinimumAccountBalance    string        `flag:"min-account-balance" info:"The minimum account balance of an account. This is also the cost of creating one account. e.g. '0.001'. The default is taken from the current version of flow-go"`
 	TransactionFeesEnabled   bool          `default:"false" flag:"transaction-fees" info:"enable transaction fees"`
 	TransactionMaxGasLimit   int           `default:"9999" flag:"transaction-max-gas-limit" info:"maximum gas limit for transactions"`
+	TransactionMaxSize       uint64        `default:"1500000" flag:"transaction-max-size" info:"maximum size of transactions in bytes, e.g. of transactions with large arguments. Transactions larger than --grpc-max-recv-msg-size can be uploaded in chunks with the admin API"`
 	ScriptGasLimit           int           `default:"100000" flag:"script-gas-limit" info:"gas limit for scripts"`
+	BlockGasLimit            int           `default:"0" flag:"block-gas-limit" info:"total gas limit of the transactions in a block, transactions exceeding it are deferred to the next block. 0 disables the limit"`
+	MaxCollectionSize        int           `default:"0" flag:"max-collection-size" info:"maximum number of transactions in a collection, blocks with more transactions include several collections. 0 includes all transactions in a single collection"`
+	ScriptTimeout            time.Duration `flag:"script-timeout" info:"maximum time a script may run before it is interrupted, e.g. '10s'. Scripts are always interrupted when the client cancels the request or its deadline passes"`
+	ErrorMessageMaxLength    int           `default:"0" flag:"error-message-max-length" info:"maximum length of transaction error messages returned by the Access API, longer messages are truncated like on mainnet (e.g. 1000). The full messages remain available from the admin API. 0 disables truncation"`
+	ScriptWorkers            int           `flag:"script-workers" info:"maximum number of scripts executed concurrently, further scripts wait for a free worker. The default is the number of CPUs"`
 	Contracts                bool          `default:"false" flag:"contracts" info:"deploy common contracts when emulator starts"`
 	ContractRemovalEnabled   bool          `default:"true" flag:"contract-removal" info:"allow removal of already deployed contracts, used for updating during development"`
+	Attachments              bool          `default:"true" flag:"attachments" info:"enable Cadence attachments"`
+	AccountLinking           bool          `default:"true" flag:"account-linking" info:"enable Cadence account linking"`
+	CapabilityControllers    bool          `default:"true" flag:"capability-controllers" info:"enable Cadence capability controllers"`
 	SkipTxValidation         bool          `default:"false" flag:"skip-tx-validation" info:"skip verification of transaction signatures and sequence numbers"`
 	Host                     string        `default:"" flag:"host" info:"host to listen on for emulator GRPC/REST/Admin servers (default: all interfaces)"`
 	ChainID                  string        `default:"emulator" flag:"chain-id" info:"chain to emulate for address generation. Valid values are: 'emulator', 'testnet', 'mainnet'"`
 	RedisURL                 string        `default:"" flag:"redis-url" info:"redis-server URL for persisting redis storage backend ( redis://[[username:]password@]host[:port][/database] ) "`
 	SqliteURL                string        `default:"" flag:"sqlite-url" info:"sqlite db URL for persisting sqlite storage backend "`
+	Durability               string        `default:"" flag:"durability" info:"durability mode, only supported by the sqlite storage backend. Valid values are: 'safe' (fsync every block commit and its journal), 'fast' (batch writes). Uses the backend default if unset"`
+	StorageCompression       string        `default:"none" flag:"storage-compression" info:"compression of the stored events and transaction results. Valid values are: 'none', 'zstd' (smallest), 'snappy' (fastest)"`
+	StorageProvider          string        `default:"" flag:"storage-provider" info:"registered storage backend to use and its data source name, as 'name,dsn' (e.g. 'sqlite,./flowdb/emulator.sqlite'). Backends are registered with storage.Register"`
+	StorageChaos             string        `default:"" flag:"storage-chaos" info:"inject random latency and transient errors into storage operations, to test retry behaviour, as comma separated settings (e.g. 'latency=50ms,error-rate=0.05')"`
 	CoverageReportingEnabled bool          `default:"false" flag:"coverage-reporting" info:"enable Cadence code coverage reporting"`
+	ComputationReporting     bool          `default:"false" flag:"computation-reporting" info:"record the computation used by every transaction, broken down by computation kind (statements, loops, function invocations, storage reads and writes, ...)"`
 	StartBlockHeight         uint64        `default:"0" flag:"start-block-height" info:"block height to start the emulator at. only valid when forking Mainnet or Testnet"`
+	Follow                   string        `default:"" flag:"follow" info:"admin API address (host:port) of an emulator to follow. The emulator runs as a read-only replica of the followed instance"`
+	Replica                  bool          `default:"false" flag:"replica" info:"serve queries and scripts read-only from storage shared with another emulator which mints blocks. Requires --redis-url, --sqlite-url, --storage-provider or --persist"`
+	InteractionTemplates     string        `default:"" flag:"interaction-templates" info:"directory of interaction templates (FLIX) to serve with dependencies resolved to contracts deployed on the emulator"`
+	SecondaryChainID         string        `default:"" flag:"secondary-chain-id" info:"chain of a second emulator to run in the same process, e.g. to test client code against two address formats. Valid values are: 'emulator', 'testnet', 'mainnet'"`
+	SecondaryPort            int           `default:"3570" flag:"secondary-port" info:"port to run the RPC server of the secondary emulator, 0 to pick a free port"`
+	TestVectors              bool          `default:"false" flag:"test-vectors" info:"record the canonical payload, envelope and signatures of successful transactions as test vectors for signing implementations, exported by the admin API"`
+	ContractsWatch           string        `default:"" flag:"contracts-watch" info:"directory of Cadence contracts to redeploy to the service account whenever their files change"`
+	Tracing                  bool          `default:"false" flag:"tracing" info:"export OpenTelemetry spans of transaction and script execution, including the FVM, ledger reads and Cadence interpretation. The exporter is configured with the OTEL_EXPORTER_OTLP_* environment variables"`
+	ImportState              string        `default:"" flag:"import-state" info:"state archive to load into empty storage on startup, as written by --export-state"`
+	ExportState              string        `default:"" flag:"export-state" info:"file to archive the emulator state (blocks, registers and events) to on shutdown, to share a reproducible state"`
+	BlobStoreSize            int           `default:"268435456" flag:"blob-store-size" info:"total size in bytes of the blobs kept by the admin API, the least recently used blobs are removed beyond it"`
+	ConfigFile               string        `default:"" flag:"config" info:"YAML configuration file setting any of these flags by name, e.g. 'port: 3569'. Flags and environment variables take precedence over the file"`
 }
 
 const EnvPrefix = "FLOW"
@@ -90,6 +133,16 @@ func Cmd(getServiceKey serviceKeyFunc) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "Starts the Flow emulator server",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			if conf.ConfigFile == "" {
+				return
+			}
+
+			err := applyConfigFile(cmd.Flags(), conf.ConfigFile)
+			if err != nil {
+				Exit(1, fmt.Sprintf("❗  %s", err.Error()))
+			}
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			var (
 				servicePrivateKey  crypto.PrivateKey
@@ -102,7 +155,9 @@ func Cmd(getServiceKey serviceKeyFunc) *cobra.Command {
 			serviceKeySigAlgo = crypto.StringToSignatureAlgorithm(conf.ServiceKeySigAlgo)
 			serviceKeyHashAlgo = crypto.StringToHashAlgorithm(conf.ServiceKeyHashAlgo)
 
-			logger := initLogger(conf.Verbose)
+			// the logs are also streamed by the admin API
+			logStream := utils.NewLogStream()
+			logger := initLogger(conf.Verbose, logStream)
 
 			if conf.ServicePublicKey != "" {
 				logger.Warn().Msg("❗  Providing '--public-key' is deprecated, provide the '--private-key' only.")
@@ -135,6 +190,22 @@ func Cmd(getServiceKey serviceKeyFunc) *cobra.Command {
 				Exit(1, "❗  --start-block-height is only valid when forking Mainnet or Testnet")
 			}
 
+			var deterministicTimeStart time.Time
+			if conf.DeterministicTime != "" {
+				deterministicTimeStart, err = time.Parse(time.RFC3339, conf.DeterministicTime)
+				if err != nil {
+					Exit(1, "❗  --deterministic-time must be in RFC 3339 format, e.g. '2023-01-01T00:00:00Z'")
+				}
+			}
+
+			var secondaryChainID flowgo.ChainID
+			if conf.SecondaryChainID != "" {
+				secondaryChainID, err = getSDKChainID(conf.SecondaryChainID)
+				if err != nil {
+					Exit(1, err.Error())
+				}
+			}
+
 			serviceAddress := sdk.ServiceAddress(sdk.ChainID(flowChainID))
 			if conf.SimpleAddresses {
 				serviceAddress = sdk.HexToAddress("0x1")
@@ -163,41 +234,100 @@ func Cmd(getServiceKey serviceKeyFunc) *cobra.Command {
 				storageMBPerFLOW = parseCadenceUFix64(conf.StorageMBPerFLOW, "storage-per-flow")
 			}
 
+			durability, err := storage.ParseDurability(conf.Durability)
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
+			storageCompression, err := storage.ParseCompression(conf.StorageCompression)
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
+			storageProvider, storageDSN := parseStorageProvider(conf.StorageProvider)
+
+			storageChaos, err := chaos.ParseConfig(conf.StorageChaos)
+			if err != nil {
+				Exit(1, err.Error())
+			}
+
 			serverConf := &server.Config{
-				GRPCPort:     conf.Port,
-				GRPCDebug:    conf.GRPCDebug,
-				AdminPort:    conf.AdminPort,
-				DebuggerPort: conf.DebuggerPort,
-				RESTPort:     conf.RestPort,
-				RESTDebug:    conf.RESTDebug,
+				GRPCPort:              conf.Port,
+				GRPCDebug:             conf.GRPCDebug,
+				GRPCMaxRecvMsgSize:    conf.GRPCMaxRecvMsgSize,
+				GRPCMaxSendMsgSize:    conf.GRPCMaxSendMsgSize,
+				GRPCMessageSizeErrors: conf.GRPCMessageSizeErrors,
+				AdminPort:             conf.AdminPort,
+				DebuggerPort:          conf.DebuggerPort,
+				RESTPort:              conf.RestPort,
+				RESTDebug:             conf.RESTDebug,
+				// ports are only zero if explicitly requested
+				EphemeralPorts: true,
 				// TODO: allow headers to be parsed from environment
-				HTTPHeaders:               nil,
-				BlockTime:                 conf.BlockTime,
-				ServicePublicKey:          servicePublicKey,
-				ServicePrivateKey:         servicePrivateKey,
-				ServiceKeySigAlgo:         serviceKeySigAlgo,
-				ServiceKeyHashAlgo:        serviceKeyHashAlgo,
-				Persist:                   conf.Persist,
-				Snapshot:                  conf.Snapshot,
-				DBPath:                    conf.DBPath,
-				GenesisTokenSupply:        parseCadenceUFix64(conf.TokenSupply, "token-supply"),
-				TransactionMaxGasLimit:    uint64(conf.TransactionMaxGasLimit),
-				ScriptGasLimit:            uint64(conf.ScriptGasLimit),
-				TransactionExpiry:         uint(conf.TransactionExpiry),
-				StorageLimitEnabled:       conf.StorageLimitEnabled,
-				StorageMBPerFLOW:          storageMBPerFLOW,
-				MinimumStorageReservation: minimumStorageReservation,
-				TransactionFeesEnabled:    conf.TransactionFeesEnabled,
-				WithContracts:             conf.Contracts,
-				SkipTransactionValidation: conf.SkipTxValidation,
-				SimpleAddressesEnabled:    conf.SimpleAddresses,
-				Host:                      conf.Host,
-				ChainID:                   flowChainID,
-				RedisURL:                  conf.RedisURL,
-				ContractRemovalEnabled:    conf.ContractRemovalEnabled,
-				SqliteURL:                 conf.SqliteURL,
-				CoverageReportingEnabled:  conf.CoverageReportingEnabled,
-				StartBlockHeight:          conf.StartBlockHeight,
+				HTTPHeaders:                  nil,
+				BlockTime:                    conf.BlockTime,
+				ConsensusDelay:               conf.ConsensusDelay,
+				DeterministicTimeStart:       deterministicTimeStart,
+				DeterministicTimeStep:        conf.DeterministicTimeStep,
+				ServicePublicKey:             servicePublicKey,
+				ServicePrivateKey:            servicePrivateKey,
+				ServiceKeySigAlgo:            serviceKeySigAlgo,
+				ServiceKeyHashAlgo:           serviceKeyHashAlgo,
+				Persist:                      conf.Persist,
+				Snapshot:                     conf.Snapshot,
+				AutoSnapshotInterval:         conf.SnapshotInterval,
+				AutoSnapshotKeep:             conf.SnapshotKeep,
+				StateHistory:                 conf.StateHistory,
+				Standby:                      conf.Standby,
+				DBPath:                       conf.DBPath,
+				GenesisTokenSupply:           parseCadenceUFix64(conf.TokenSupply, "token-supply"),
+				BootstrapAccounts:            conf.BootstrapAccounts,
+				BootstrapAccountBalance:      parseCadenceUFix64(conf.BootstrapAccountBalance, "bootstrap-account-balance"),
+				TransactionMaxGasLimit:       uint64(conf.TransactionMaxGasLimit),
+				TransactionMaxByteSize:       conf.TransactionMaxSize,
+				ScriptGasLimit:               uint64(conf.ScriptGasLimit),
+				BlockGasLimit:                uint64(conf.BlockGasLimit),
+				MaxCollectionSize:            conf.MaxCollectionSize,
+				ScriptTimeout:                conf.ScriptTimeout,
+				ErrorMessageMaxLength:        conf.ErrorMessageMaxLength,
+				ScriptWorkers:                conf.ScriptWorkers,
+				TransactionExpiry:            uint(conf.TransactionExpiry),
+				TransactionExpiryBuffer:      uint(conf.TransactionExpiryBuffer),
+				StorageLimitEnabled:          conf.StorageLimitEnabled,
+				StorageMBPerFLOW:             storageMBPerFLOW,
+				MinimumStorageReservation:    minimumStorageReservation,
+				TransactionFeesEnabled:       conf.TransactionFeesEnabled,
+				WithContracts:                conf.Contracts,
+				SkipTransactionValidation:    conf.SkipTxValidation,
+				SimpleAddressesEnabled:       conf.SimpleAddresses,
+				Host:                         conf.Host,
+				ChainID:                      flowChainID,
+				RedisURL:                     conf.RedisURL,
+				ContractRemovalEnabled:       conf.ContractRemovalEnabled,
+				AttachmentsEnabled:           conf.Attachments,
+				AccountLinkingEnabled:        conf.AccountLinking,
+				CapabilityControllersEnabled: conf.CapabilityControllers,
+				SqliteURL:                    conf.SqliteURL,
+				Durability:                   durability,
+				StorageCompression:           storageCompression,
+				StorageProvider:              storageProvider,
+				StorageDSN:                   storageDSN,
+				StorageChaos:                 storageChaos,
+				CoverageReportingEnabled:     conf.CoverageReportingEnabled,
+				ComputationReportingEnabled:  conf.ComputationReporting,
+				StartBlockHeight:             conf.StartBlockHeight,
+				Follow:                       conf.Follow,
+				Replica:                      conf.Replica,
+				InteractionTemplatesPath:     conf.InteractionTemplates,
+				SecondaryChainID:             secondaryChainID,
+				SecondaryGRPCPort:            conf.SecondaryPort,
+				TestVectorsEnabled:           conf.TestVectors,
+				ContractsWatchPath:           conf.ContractsWatch,
+				TracingEnabled:               conf.Tracing,
+				ImportStatePath:              conf.ImportState,
+				ExportStatePath:              conf.ExportState,
+				LogStream:                    logStream,
+				BlobStoreByteSize:            conf.BlobStoreSize,
 			}
 
 			emu := server.NewEmulatorServer(logger, serverConf)
@@ -211,10 +341,16 @@ func Cmd(getServiceKey serviceKeyFunc) *cobra.Command {
 
 	initConfig(cmd)
 
+	cmd.AddCommand(migrateStorageCmd())
+	cmd.AddCommand(checkStorageCmd())
+	cmd.AddCommand(inspectCmd())
+	cmd.AddCommand(diffStorageCmd())
+
 	return cmd
 }
 
-func initLogger(verbose bool) *zerolog.Logger {
+// initLogger creates the logger writing to stdout, and to the given additional writers as JSON.
+func initLogger(verbose bool, writers ...io.Writer) *zerolog.Logger {
 
 	level := zerolog.InfoLevel
 	if verbose {
@@ -224,7 +360,8 @@ func initLogger(verbose bool) *zerolog.Logger {
 
 	switch strings.ToLower(conf.LogFormat) {
 	case "json":
-		logger := zerolog.New(os.Stdout).With().Timestamp().Logger().Level(level)
+		output := zerolog.MultiLevelWriter(append([]io.Writer{os.Stdout}, writers...)...)
+		logger := zerolog.New(output).With().Timestamp().Logger().Level(level)
 		return &logger
 	default:
 		writer := zerolog.ConsoleWriter{Out: os.Stdout}
@@ -234,7 +371,8 @@ func initLogger(verbose bool) *zerolog.Logger {
 			}
 			return fmt.Sprintf("%-44s", i)
 		}
-		logger := zerolog.New(writer).With().Timestamp().Logger().Level(level)
+		output := zerolog.MultiLevelWriter(append([]io.Writer{writer}, writers...)...)
+		logger := zerolog.New(output).With().Timestamp().Logger().Level(level)
 		return &logger
 	}
 
@@ -284,6 +422,13 @@ func getSDKChainID(chainID string) (flowgo.ChainID, error) {
 	}
 }
 
+// parseStorageProvider splits a --storage-provider value into the provider
+// name and the data source name, which may itself contain commas.
+func parseStorageProvider(value string) (string, string) {
+	name, dsn, _ := strings.Cut(value, ",")
+	return name, dsn
+}
+
 func checkKeyAlgorithms(sigAlgo crypto.SignatureAlgorithm, hashAlgo crypto.HashAlgorithm) {
 	if sigAlgo == crypto.UnknownSignatureAlgorithm {
 		Exit(1, "Must specify service key signature algorithm (e.g. --service-sig-algo=ECDSA_P256)")
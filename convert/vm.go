@@ -41,12 +41,14 @@ func VMTransactionResultToEmulator(
 	}
 
 	return &types.TransactionResult{
-		TransactionID:   txID,
-		ComputationUsed: output.ComputationUsed,
-		MemoryEstimate:  output.MemoryEstimate,
-		Error:           VMErrorToEmulator(output.Err),
-		Logs:            output.Logs,
-		Events:          sdkEvents,
+		TransactionID:          txID,
+		ComputationUsed:        output.ComputationUsed,
+		ComputationIntensities: types.ComputationIntensities(output.ComputationIntensities),
+		MemoryEstimate:         output.MemoryEstimate,
+		Error:                  VMErrorToEmulator(output.Err),
+		ErrorCode:              types.ClassifyError(output.Err),
+		Logs:                   output.Logs,
+		Events:                 sdkEvents,
 	}, nil
 }
 
@@ -0,0 +1,217 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package emuerrors exports the errors returned by the emulator, so that applications
+// embedding it can handle failures with errors.Is and errors.As instead of matching messages.
+//
+// The error kinds match all the errors of the kind with errors.Is:
+//
+//	if errors.Is(err, emuerrors.ErrNotFound) {
+//		...
+//	}
+//
+// The error types give the details of an error with errors.As:
+//
+//	var expiredErr *emuerrors.ExpiredTransactionError
+//	if errors.As(err, &expiredErr) {
+//		...
+//	}
+package emuerrors
+
+import (
+	"github.com/onflow/flow-go-sdk/crypto"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// Kinds of errors.
+var (
+	ErrInvalidArgument    = types.ErrInvalidArgument
+	ErrInternal           = types.ErrInternal
+	ErrNotFound           = types.ErrNotFound
+	ErrBlockNotFound      = types.ErrBlockNotFound
+	ErrInvalidTransaction = types.ErrInvalidTransaction
+	ErrPendingBlock       = types.ErrPendingBlock
+	ErrReadOnly           = types.ErrReadOnly
+	ErrStorage            = types.ErrStorage
+)
+
+// Interfaces implemented by the errors of a kind.
+type (
+	NotFoundError              = types.NotFoundError
+	BlockNotFoundError         = types.BlockNotFoundError
+	TransactionValidationError = types.TransactionValidationError
+)
+
+// Errors of the ErrInvalidArgument and ErrInternal kinds.
+type (
+	InvalidArgumentError = types.InvalidArgumentError
+	InternalError        = types.InternalError
+)
+
+// Errors of the ErrNotFound kind.
+type (
+	BlockNotFoundByHeightError     = types.BlockNotFoundByHeightError
+	BlockNotFoundByIDError         = types.BlockNotFoundByIDError
+	StatePrunedError               = types.StatePrunedError
+	BlockDependenciesNotFoundError = types.BlockDependenciesNotFoundError
+	ComputationReportNotFoundError = types.ComputationReportNotFoundError
+	CollectionNotFoundError        = types.CollectionNotFoundError
+	TransactionNotFoundError       = types.TransactionNotFoundError
+	AccountNotFoundError           = types.AccountNotFoundError
+)
+
+// Errors of the ErrInvalidTransaction kind.
+type (
+	DuplicateTransactionError       = types.DuplicateTransactionError
+	IncompleteTransactionError      = types.IncompleteTransactionError
+	ExpiredTransactionError         = types.ExpiredTransactionError
+	InvalidTransactionScriptError   = types.InvalidTransactionScriptError
+	InvalidTransactionGasLimitError = types.InvalidTransactionGasLimitError
+	InvalidSequenceNumberError      = types.InvalidSequenceNumberError
+)
+
+// Errors of the ErrPendingBlock kind.
+type (
+	PendingBlockCommitBeforeExecutionError = types.PendingBlockCommitBeforeExecutionError
+	PendingBlockMidExecutionError          = types.PendingBlockMidExecutionError
+	PendingBlockNotEmptyError              = types.PendingBlockNotEmptyError
+	PendingBlockTransactionsExhaustedError = types.PendingBlockTransactionsExhaustedError
+)
+
+// Other errors.
+type (
+	ReadOnlyError            = types.ReadOnlyError
+	StorageError             = types.StorageError
+	ScriptInterruptedError   = types.ScriptInterruptedError
+	InvalidStateVersionError = types.InvalidStateVersionError
+	ExecutionError           = types.ExecutionError
+	FVMError                 = types.FVMError
+)
+
+func NewInvalidArgumentError(msg string) *InvalidArgumentError {
+	return types.NewInvalidArgumentError(msg)
+}
+
+func NewInternalError(msg string) *InternalError {
+	return types.NewInternalError(msg)
+}
+
+func NewBlockNotFoundByHeightError(height uint64) *BlockNotFoundByHeightError {
+	return &BlockNotFoundByHeightError{Height: height}
+}
+
+func NewBlockNotFoundByIDError(id flowgo.Identifier) *BlockNotFoundByIDError {
+	return &BlockNotFoundByIDError{ID: id}
+}
+
+func NewStatePrunedError(height uint64, oldestHeight uint64) *StatePrunedError {
+	return &StatePrunedError{Height: height, OldestHeight: oldestHeight}
+}
+
+func NewBlockDependenciesNotFoundError(blockID flowgo.Identifier) *BlockDependenciesNotFoundError {
+	return &BlockDependenciesNotFoundError{BlockID: blockID}
+}
+
+func NewComputationReportNotFoundError(txID flowgo.Identifier) *ComputationReportNotFoundError {
+	return &ComputationReportNotFoundError{TransactionID: txID}
+}
+
+func NewCollectionNotFoundError(id flowgo.Identifier) *CollectionNotFoundError {
+	return &CollectionNotFoundError{ID: id}
+}
+
+func NewTransactionNotFoundError(id flowgo.Identifier) *TransactionNotFoundError {
+	return &TransactionNotFoundError{ID: id}
+}
+
+func NewAccountNotFoundError(address flowgo.Address) *AccountNotFoundError {
+	return &AccountNotFoundError{Address: address}
+}
+
+func NewDuplicateTransactionError(txID flowgo.Identifier) *DuplicateTransactionError {
+	return &DuplicateTransactionError{TxID: txID}
+}
+
+func NewIncompleteTransactionError(missingFields ...string) *IncompleteTransactionError {
+	return &IncompleteTransactionError{MissingFields: missingFields}
+}
+
+func NewExpiredTransactionError(refHeight uint64, finalHeight uint64) *ExpiredTransactionError {
+	return &ExpiredTransactionError{RefHeight: refHeight, FinalHeight: finalHeight}
+}
+
+func NewInvalidTransactionScriptError(parserErr error) *InvalidTransactionScriptError {
+	return &InvalidTransactionScriptError{ParserErr: parserErr}
+}
+
+func NewInvalidTransactionGasLimitError(maximum uint64, actual uint64) *InvalidTransactionGasLimitError {
+	return &InvalidTransactionGasLimitError{Maximum: maximum, Actual: actual}
+}
+
+func NewInvalidSequenceNumberError(
+	address flowgo.Address,
+	keyIndex uint64,
+	expected uint64,
+	provided uint64,
+) *InvalidSequenceNumberError {
+	return &InvalidSequenceNumberError{
+		Address:  address,
+		KeyIndex: keyIndex,
+		Expected: expected,
+		Provided: provided,
+	}
+}
+
+func NewPendingBlockCommitBeforeExecutionError(blockID flowgo.Identifier) *PendingBlockCommitBeforeExecutionError {
+	return &PendingBlockCommitBeforeExecutionError{BlockID: blockID}
+}
+
+func NewPendingBlockMidExecutionError(blockID flowgo.Identifier) *PendingBlockMidExecutionError {
+	return &PendingBlockMidExecutionError{BlockID: blockID}
+}
+
+func NewPendingBlockNotEmptyError(blockID flowgo.Identifier) *PendingBlockNotEmptyError {
+	return &PendingBlockNotEmptyError{BlockID: blockID}
+}
+
+func NewPendingBlockTransactionsExhaustedError(blockID flowgo.Identifier) *PendingBlockTransactionsExhaustedError {
+	return &PendingBlockTransactionsExhaustedError{BlockID: blockID}
+}
+
+func NewReadOnlyError() *ReadOnlyError {
+	return &ReadOnlyError{}
+}
+
+// NewStorageError wraps an error of the storage provider.
+func NewStorageError(err error) *StorageError {
+	return types.NewStorageError(err)
+}
+
+func NewScriptInterruptedError(scriptID flowgo.Identifier, err error) *ScriptInterruptedError {
+	return &ScriptInterruptedError{ScriptID: scriptID, Err: err}
+}
+
+func NewInvalidStateVersionError(version crypto.Hash) *InvalidStateVersionError {
+	return &InvalidStateVersionError{Version: version}
+}
+
+func NewExecutionError(code int, message string) *ExecutionError {
+	return &ExecutionError{Code: code, Message: message}
+}
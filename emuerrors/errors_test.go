@@ -0,0 +1,131 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emuerrors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emuerrors"
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+func TestErrorKinds(t *testing.T) {
+
+	t.Parallel()
+
+	kinds := []error{
+		emuerrors.ErrInvalidArgument,
+		emuerrors.ErrInternal,
+		emuerrors.ErrNotFound,
+		emuerrors.ErrBlockNotFound,
+		emuerrors.ErrInvalidTransaction,
+		emuerrors.ErrPendingBlock,
+		emuerrors.ErrReadOnly,
+		emuerrors.ErrStorage,
+	}
+
+	tests := []struct {
+		err   error
+		kinds []error
+	}{
+		{emuerrors.NewInvalidArgumentError("height"), []error{emuerrors.ErrInvalidArgument}},
+		{emuerrors.NewInternalError("failure"), []error{emuerrors.ErrInternal}},
+		{emuerrors.NewBlockNotFoundByHeightError(1), []error{emuerrors.ErrNotFound, emuerrors.ErrBlockNotFound}},
+		{emuerrors.NewBlockNotFoundByIDError(flowgo.ZeroID), []error{emuerrors.ErrNotFound, emuerrors.ErrBlockNotFound}},
+		{emuerrors.NewStatePrunedError(1, 2), []error{emuerrors.ErrNotFound}},
+		{emuerrors.NewBlockDependenciesNotFoundError(flowgo.ZeroID), []error{emuerrors.ErrNotFound}},
+		{emuerrors.NewComputationReportNotFoundError(flowgo.ZeroID), []error{emuerrors.ErrNotFound}},
+		{emuerrors.NewCollectionNotFoundError(flowgo.ZeroID), []error{emuerrors.ErrNotFound}},
+		{emuerrors.NewTransactionNotFoundError(flowgo.ZeroID), []error{emuerrors.ErrNotFound}},
+		{emuerrors.NewAccountNotFoundError(flowgo.EmptyAddress), []error{emuerrors.ErrNotFound}},
+		{emuerrors.NewDuplicateTransactionError(flowgo.ZeroID), []error{emuerrors.ErrInvalidTransaction}},
+		{emuerrors.NewIncompleteTransactionError("payer"), []error{emuerrors.ErrInvalidTransaction}},
+		{emuerrors.NewExpiredTransactionError(1, 20), []error{emuerrors.ErrInvalidTransaction}},
+		{emuerrors.NewInvalidTransactionScriptError(errors.New("parse")), []error{emuerrors.ErrInvalidTransaction}},
+		{emuerrors.NewInvalidTransactionGasLimitError(10, 20), []error{emuerrors.ErrInvalidTransaction}},
+		{emuerrors.NewInvalidSequenceNumberError(flowgo.EmptyAddress, 0, 1, 2), []error{emuerrors.ErrInvalidTransaction}},
+		{emuerrors.NewPendingBlockCommitBeforeExecutionError(flowgo.ZeroID), []error{emuerrors.ErrPendingBlock}},
+		{emuerrors.NewPendingBlockMidExecutionError(flowgo.ZeroID), []error{emuerrors.ErrPendingBlock}},
+		{emuerrors.NewPendingBlockNotEmptyError(flowgo.ZeroID), []error{emuerrors.ErrPendingBlock}},
+		{emuerrors.NewPendingBlockTransactionsExhaustedError(flowgo.ZeroID), []error{emuerrors.ErrPendingBlock}},
+		{emuerrors.NewReadOnlyError(), []error{emuerrors.ErrReadOnly}},
+		{emuerrors.NewStorageError(errors.New("disk full")), []error{emuerrors.ErrStorage}},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(fmt.Sprintf("%T", test.err), func(t *testing.T) {
+			t.Parallel()
+
+			// the kind is matched through wrapping errors too
+			err := fmt.Errorf("wrapped: %w", test.err)
+
+			for _, kind := range kinds {
+				expected := false
+				for _, testKind := range test.kinds {
+					expected = expected || testKind == kind
+				}
+				assert.Equal(t, expected, errors.Is(err, kind), kind.Error())
+			}
+		})
+	}
+}
+
+func TestStorageErrorUnwraps(t *testing.T) {
+
+	t.Parallel()
+
+	inner := errors.New("disk full")
+	err := fmt.Errorf("commit: %w", emuerrors.NewStorageError(inner))
+
+	assert.ErrorIs(t, err, inner)
+
+	var storageErr *emuerrors.StorageError
+	require.ErrorAs(t, err, &storageErr)
+	assert.ErrorIs(t, storageErr, emuerrors.ErrStorage)
+}
+
+func TestEmulatorErrors(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	_, err = b.GetBlockByHeight(context.Background(), 100)
+	assert.ErrorIs(t, err, emuerrors.ErrBlockNotFound)
+
+	var heightErr *emuerrors.BlockNotFoundByHeightError
+	require.ErrorAs(t, err, &heightErr)
+	assert.Equal(t, uint64(100), heightErr.Height)
+
+	_, err = b.GetTransaction(context.Background(), flowgo.ZeroID)
+	assert.ErrorIs(t, err, emuerrors.ErrNotFound)
+
+	var notFoundErr emuerrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
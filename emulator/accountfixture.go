@@ -0,0 +1,237 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package emulator
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/onflow/atree"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	"github.com/onflow/flow-go/fvm/storage/state"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// AccountFixture is the state of an account, exported as the registers it owns:
+// its status, keys, contracts, storage domains and the slabs of its stored values.
+//
+// Importing the fixture into another emulator recreates the account with equivalent
+// storage, including resources, which a transaction could only create through
+// the contracts defining them.
+type AccountFixture struct {
+	Address flowgo.Address `json:"address"`
+	ChainID flowgo.ChainID `json:"chainId"`
+	// Height is the height of the block the account was exported at.
+	Height    uint64                   `json:"height"`
+	Registers []AccountFixtureRegister `json:"registers"`
+}
+
+// AccountFixtureRegister is a register owned by the account of a fixture.
+//
+// Register keys are raw bytes rather than text, e.g. the keys of storage slabs,
+// so they are kept as byte slices, encoded in base64 in JSON.
+type AccountFixtureRegister struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// ExportAccountFixture exports the registers of the given account at the latest block.
+func (b *Blockchain) ExportAccountFixture(ctx context.Context, address flowgo.Address) (*AccountFixture, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger, err := b.storage.LedgerByHeight(ctx, latestBlock.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	b.fvmStats.ledgerViewCreated()
+	registerIDs, err := accountRegisterIDs(ledger, address)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &AccountFixture{
+		Address:   address,
+		ChainID:   b.conf.GetChainID(),
+		Height:    latestBlock.Header.Height,
+		Registers: make([]AccountFixtureRegister, 0, len(registerIDs)),
+	}
+
+	for _, registerID := range registerIDs {
+		value, err := ledger.Get(registerID)
+		if err != nil {
+			return nil, err
+		}
+		// removed slabs leave empty registers behind
+		if len(value) == 0 {
+			continue
+		}
+
+		fixture.Registers = append(fixture.Registers, AccountFixtureRegister{
+			Key:   []byte(registerID.Key),
+			Value: value,
+		})
+	}
+
+	return fixture, nil
+}
+
+// accountRegisterIDs returns the IDs of the registers owned by the given account.
+func accountRegisterIDs(ledger snapshot.StorageSnapshot, address flowgo.Address) ([]flowgo.RegisterID, error) {
+	accounts := environment.NewAccounts(state.NewTransactionState(ledger, state.DefaultParameters()))
+
+	exists, err := accounts.Exists(address)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &types.AccountNotFoundError{Address: address}
+	}
+
+	statusValue, err := ledger.Get(flowgo.AccountStatusRegisterID(address))
+	if err != nil {
+		return nil, err
+	}
+	status, err := environment.AccountStatusFromBytes(statusValue)
+	if err != nil {
+		return nil, err
+	}
+
+	registerIDs := []flowgo.RegisterID{
+		flowgo.AccountStatusRegisterID(address),
+		flowgo.ContractNamesRegisterID(address),
+	}
+
+	for index := uint64(0); index < status.PublicKeyCount(); index++ {
+		registerIDs = append(registerIDs, flowgo.PublicKeyRegisterID(address, index))
+	}
+
+	contractNames, err := accounts.GetContractNames(address)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range contractNames {
+		registerIDs = append(registerIDs, flowgo.ContractRegisterID(address, name))
+	}
+
+	for _, domain := range accountStorageDomains {
+		registerIDs = append(registerIDs, flowgo.NewRegisterID(string(address.Bytes()), domain))
+	}
+
+	// slabs are allocated sequentially, the storage index is the index of the next slab
+	storageIndex := status.StorageIndex()
+	for index := uint64(1); index < binary.BigEndian.Uint64(storageIndex[:]); index++ {
+		var slabIndex atree.StorageIndex
+		binary.BigEndian.PutUint64(slabIndex[:], index)
+		registerIDs = append(registerIDs, flowgo.NewRegisterID(
+			string(address.Bytes()),
+			string(atree.SlabIndexToLedgerKey(slabIndex)),
+		))
+	}
+
+	return registerIDs, nil
+}
+
+// ImportAccountFixture recreates the account of the given fixture, at the same address,
+// in a new block. The fixture must be exported on the same chain, the account must not
+// exist yet, and the pending block must be empty.
+//
+// The contracts imported by the contracts and values of the account are not imported,
+// they must be deployed already. Addresses of accounts created afterwards follow the
+// address of the imported account.
+func (b *Blockchain) ImportAccountFixture(ctx context.Context, fixture *AccountFixture) (*flowgo.Block, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.conf.ReadOnly {
+		return nil, &types.ReadOnlyError{}
+	}
+
+	if !b.pendingBlock.Empty() {
+		return nil, &types.PendingBlockNotEmptyError{BlockID: b.pendingBlock.ID()}
+	}
+
+	if fixture.ChainID != b.conf.GetChainID() {
+		return nil, types.NewInvalidArgumentError(
+			fmt.Sprintf("fixture was exported on chain %s, not %s", fixture.ChainID, b.conf.GetChainID()),
+		)
+	}
+
+	chain := b.conf.GetChainID().Chain()
+	if !chain.IsValid(fixture.Address) {
+		return nil, types.NewInvalidArgumentError(
+			fmt.Sprintf("address %s is not valid on chain %s", fixture.Address, chain),
+		)
+	}
+
+	ledger := b.pendingBlock.ledgerSnapshot
+
+	b.fvmStats.ledgerViewCreated()
+	exists, err := environment.NewAccounts(state.NewTransactionState(ledger, state.DefaultParameters())).
+		Exists(fixture.Address)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, types.NewInvalidArgumentError(fmt.Sprintf("account %s already exists", fixture.Address))
+	}
+
+	executionSnapshot := &snapshot.ExecutionSnapshot{
+		WriteSet: make(map[flowgo.RegisterID]flowgo.RegisterValue, len(fixture.Registers)+1),
+	}
+	for _, register := range fixture.Registers {
+		registerID := flowgo.NewRegisterID(string(fixture.Address.Bytes()), string(register.Key))
+		executionSnapshot.WriteSet[registerID] = register.Value
+	}
+
+	if _, ok := executionSnapshot.WriteSet[flowgo.AccountStatusRegisterID(fixture.Address)]; !ok {
+		return nil, types.NewInvalidArgumentError("account fixture has no account status register")
+	}
+
+	// accounts created afterwards must not be assigned the address of the imported account
+	addressState, err := ledger.Get(flowgo.AddressStateRegisterID)
+	if err != nil {
+		return nil, err
+	}
+	index, err := chain.IndexFromAddress(fixture.Address)
+	if err != nil {
+		return nil, err
+	}
+	if index > chain.BytesToAddressGenerator(addressState).AddressCount() {
+		state := make([]byte, 8)
+		binary.BigEndian.PutUint64(state, index)
+		executionSnapshot.WriteSet[flowgo.AddressStateRegisterID] = state
+	}
+
+	err = b.pendingBlock.ApplyWrites(executionSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.commitBlock()
+}
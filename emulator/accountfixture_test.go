@@ -0,0 +1,178 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package emulator_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/onflow/cadence"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/test"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestAccountFixture(t *testing.T) {
+
+	t.Parallel()
+
+	accountKeys := test.AccountKeyGenerator()
+
+	source, err := emulator.New(
+		emulator.WithStorageLimitEnabled(false),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	sourceAdapter := adapters.NewSDKAdapter(&logger, source)
+
+	addTwoScript, counterAddress := DeployAndGenerateAddTwoScript(t, sourceAdapter)
+
+	accountKey, signer := accountKeys.NewWithSigner()
+	accountAddress, err := sourceAdapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKey}, nil, 0)
+	require.NoError(t, err)
+
+	addTwoAsAccount(t, source, sourceAdapter, addTwoScript, accountAddress, signer)
+
+	counterFixture, err := source.ExportAccountFixture(context.Background(), flowgo.Address(counterAddress))
+	require.NoError(t, err)
+	assert.Equal(t, flowgo.Address(counterAddress), counterFixture.Address)
+
+	accountFixture, err := source.ExportAccountFixture(context.Background(), flowgo.Address(accountAddress))
+	require.NoError(t, err)
+
+	// fixtures are shared as JSON
+	encoded, err := json.Marshal(accountFixture)
+	require.NoError(t, err)
+	accountFixture = &emulator.AccountFixture{}
+	err = json.Unmarshal(encoded, accountFixture)
+	require.NoError(t, err)
+
+	target, err := emulator.New(
+		emulator.WithStorageLimitEnabled(false),
+	)
+	require.NoError(t, err)
+	targetAdapter := adapters.NewSDKAdapter(&logger, target)
+
+	latestBlock, err := target.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	block, err := target.ImportAccountFixture(context.Background(), counterFixture)
+	require.NoError(t, err)
+	assert.Equal(t, latestBlock.Header.Height+1, block.Header.Height)
+
+	_, err = target.ImportAccountFixture(context.Background(), accountFixture)
+	require.NoError(t, err)
+
+	t.Run("storage is recreated", func(t *testing.T) {
+		result, err := target.ExecuteScript(
+			context.Background(),
+			[]byte(GenerateGetCounterCountScript(counterAddress, accountAddress)),
+			nil,
+		)
+		require.NoError(t, err)
+		require.NoError(t, result.Error)
+		assert.Equal(t, cadence.NewInt(2), result.Value)
+	})
+
+	t.Run("keys and contracts are recreated", func(t *testing.T) {
+		addTwoAsAccount(t, target, targetAdapter, addTwoScript, accountAddress, signer)
+
+		result, err := target.ExecuteScript(
+			context.Background(),
+			[]byte(GenerateGetCounterCountScript(counterAddress, accountAddress)),
+			nil,
+		)
+		require.NoError(t, err)
+		require.NoError(t, result.Error)
+		assert.Equal(t, cadence.NewInt(4), result.Value)
+	})
+
+	t.Run("created accounts follow imported accounts", func(t *testing.T) {
+		address, err := targetAdapter.CreateAccount(context.Background(), nil, nil, 0)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, counterAddress, address)
+		assert.NotEqual(t, accountAddress, address)
+	})
+
+	t.Run("existing account", func(t *testing.T) {
+		_, err := target.ImportAccountFixture(context.Background(), counterFixture)
+		var invalidErr *types.InvalidArgumentError
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+
+	t.Run("other chain", func(t *testing.T) {
+		fixture := *counterFixture
+		fixture.ChainID = flowgo.Testnet
+
+		_, err := target.ImportAccountFixture(context.Background(), &fixture)
+		var invalidErr *types.InvalidArgumentError
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+
+	t.Run("missing account", func(t *testing.T) {
+		_, err := source.ExportAccountFixture(context.Background(), flowgo.HexToAddress("ff"))
+		var notFoundErr *types.AccountNotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+}
+
+func addTwoAsAccount(
+	t *testing.T,
+	b *emulator.Blockchain,
+	adapter *adapters.SDKAdapter,
+	addTwoScript string,
+	address flowsdk.Address,
+	signer crypto.Signer,
+) {
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(addTwoScript)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address).
+		AddAuthorizer(address)
+
+	err := tx.SignPayload(address, 0, signer)
+	require.NoError(t, err)
+
+	serviceSigner, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, serviceSigner)
+	require.NoError(t, err)
+
+	err = adapter.SendTransaction(context.Background(), *tx)
+	require.NoError(t, err)
+
+	result, err := b.ExecuteNextTransaction()
+	require.NoError(t, err)
+	AssertTransactionSucceeded(t, result)
+
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+}
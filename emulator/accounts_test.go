@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/onflow/cadence"
 	"github.com/onflow/flow-emulator/adapters"
 	"github.com/onflow/flow-emulator/emulator"
 	"github.com/rs/zerolog"
@@ -78,7 +79,7 @@ func TestGetAccount(t *testing.T) {
 
 		assert.Equal(t, uint64(0), acc.Keys[0].SequenceNumber)
 
-		flowAccount, err := b.GetAccountByIndex(1) //service account
+		flowAccount, err := b.GetAccountByIndex(context.Background(), 1) //service account
 		assert.NoError(t, err)
 
 		assert.Equal(t, uint64(0), flowAccount.Keys[0].SeqNumber)
@@ -114,7 +115,7 @@ func TestGetAccount(t *testing.T) {
 
 		assert.Equal(t, uint64(0), acc.Keys[0].SequenceNumber)
 
-		flowAccount, err := b.GetAccountByIndex(1) //service account
+		flowAccount, err := b.GetAccountByIndex(context.Background(), 1) //service account
 		assert.NoError(t, err)
 
 		assert.Equal(t, uint64(0), flowAccount.Keys[0].SeqNumber)
@@ -536,6 +537,46 @@ func TestCreateAccount(t *testing.T) {
 		)
 	})
 
+	t.Run("Initial balance", func(t *testing.T) {
+		_, adapter := setupAccountTests(t)
+
+		contracts := []templates.Contract{
+			{
+				Name:   "Test",
+				Source: testContract,
+			},
+		}
+
+		unfunded, err := adapter.CreateAccount(
+			context.Background(),
+			[]*flowsdk.AccountKey{accountKeys.New()},
+			contracts,
+			0,
+		)
+		require.NoError(t, err)
+
+		initialBalance, err := cadence.NewUFix64("10.5")
+		require.NoError(t, err)
+
+		funded, err := adapter.CreateAccount(
+			context.Background(),
+			[]*flowsdk.AccountKey{accountKeys.New()},
+			contracts,
+			initialBalance,
+		)
+		require.NoError(t, err)
+
+		unfundedAccount, err := adapter.GetAccount(context.Background(), unfunded)
+		require.NoError(t, err)
+
+		fundedAccount, err := adapter.GetAccount(context.Background(), funded)
+		require.NoError(t, err)
+
+		assert.Equal(t, unfundedAccount.Balance+uint64(initialBalance), fundedAccount.Balance)
+		assert.Len(t, fundedAccount.Keys, 1)
+		assert.Contains(t, fundedAccount.Contracts, "Test")
+	})
+
 	t.Run("Invalid hash algorithm", func(t *testing.T) {
 		b, adapter := setupAccountTests(t)
 
@@ -908,6 +949,7 @@ func TestUpdateAccountCode(t *testing.T) {
 			context.Background(),
 			[]*flowsdk.AccountKey{accountKeyB},
 			contracts,
+			0,
 		)
 		require.NoError(t, err)
 
@@ -972,6 +1014,7 @@ func TestUpdateAccountCode(t *testing.T) {
 			context.Background(),
 			[]*flowsdk.AccountKey{accountKeyB},
 			contracts,
+			0,
 		)
 		require.NoError(t, err)
 
@@ -1036,7 +1079,7 @@ func TestImportAccountCode(t *testing.T) {
 		},
 	}
 
-	address, err := adapter.CreateAccount(context.Background(), nil, accountContracts)
+	address, err := adapter.CreateAccount(context.Background(), nil, accountContracts, 0)
 	assert.NoError(t, err)
 
 	script := []byte(fmt.Sprintf(`
@@ -1106,6 +1149,7 @@ func TestAccountAccess(t *testing.T) {
 		context.Background(),
 		[]*flowsdk.AccountKey{accountKey1},
 		accountContracts,
+		0,
 	)
 	assert.NoError(t, err)
 
@@ -1162,6 +1206,7 @@ func TestAccountAccess(t *testing.T) {
 		context.Background(),
 		[]*flowsdk.AccountKey{accountKey2},
 		nil,
+		0,
 	)
 	assert.NoError(t, err)
 
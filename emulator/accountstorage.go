@@ -0,0 +1,367 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/atree"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/fvm/storage/state"
+	"github.com/onflow/flow-go/fvm/tracing"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// DefaultAccountStorageLimit is the number of stored values returned
+// by GetAccountStorage when the query does not set a limit.
+const DefaultAccountStorageLimit = 100
+
+// accountStorageDomains lists the storage domains of an account,
+// in the order they are listed by GetAccountStorage.
+var accountStorageDomains = []string{
+	common.PathDomainStorage.Identifier(),
+	common.PathDomainPrivate.Identifier(),
+	common.PathDomainPublic.Identifier(),
+	runtime.StorageDomainContract,
+	stdlib.InboxStorageDomain,
+	stdlib.CapabilityControllerStorageDomain,
+	stdlib.CapabilityControllerTagStorageDomain,
+	stdlib.PathCapabilityStorageDomain,
+	stdlib.AccountCapabilityStorageDomain,
+}
+
+// AccountStorageQuery selects a page of the values stored in an account.
+//
+// Domains and Type filter the listed values, empty filters match all values.
+// Cursor is the NextCursor of the previous page, empty for the first page.
+type AccountStorageQuery struct {
+	Domains []string
+	Type    string
+	Limit   int
+	Cursor  string
+}
+
+// StorageItem is a value stored in an account, identified by its domain and key.
+// Size is the number of bytes the encoded value takes up in storage,
+// including the slabs of nested arrays, dictionaries and composites.
+type StorageItem struct {
+	Domain string `json:"domain"`
+	Key    string `json:"key"`
+	Type   string `json:"type"`
+	Size   uint64 `json:"size"`
+}
+
+// AccountStorage is a page of the values stored in an account.
+// NextCursor is empty when there are no further values.
+type AccountStorage struct {
+	Items      []StorageItem `json:"items"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+type storageKey struct {
+	domain string
+	key    string
+	mapKey interpreter.StorageMapKey
+}
+
+// GetAccountStorage returns a page of the values stored in the given account
+// at the latest block, ordered by domain and key.
+//
+// Only keys are read to order and page the values,
+// values are only decoded for the items of the returned page
+// and when filtering by type, and are never exported to Cadence values.
+func (b *Blockchain) GetAccountStorage(
+	ctx context.Context,
+	address flowgo.Address,
+	query AccountStorageQuery,
+) (*AccountStorage, error) {
+	domains, err := storageDomains(query.Domains)
+	if err != nil {
+		return nil, err
+	}
+
+	cursorDomain, cursorKey, err := parseStorageCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultAccountStorageLimit
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// also ensures the account exists
+	_, err = b.getAccount(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger, err := b.storage.LedgerByHeight(ctx, latestBlock.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	b.fvmStats.ledgerViewCreated()
+	txnState := state.NewTransactionState(ledger, state.DefaultParameters())
+	valueStore := environment.NewValueStore(
+		tracing.NewTracerSpan(),
+		environment.NewMeter(txnState),
+		environment.NewAccounts(txnState),
+	)
+	storage := runtime.NewStorage(valueStore, nil)
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		common.AddressLocation{Address: common.Address(address)},
+		&interpreter.Config{Storage: storage},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AccountStorage{
+		Items: make([]StorageItem, 0),
+	}
+
+	// the cursor of the last value scanned, matching or not,
+	// so the next page starts after the values filtered out of this one
+	var scanned string
+
+	for _, domain := range domains {
+		if cursorDomain != "" && domainIndex(domain) < domainIndex(cursorDomain) {
+			continue
+		}
+
+		storageMap := storage.GetStorageMap(common.Address(address), domain, false)
+		if storageMap == nil {
+			continue
+		}
+
+		keys := storageKeys(storageMap, domain)
+		for _, key := range keys {
+			if domain == cursorDomain && key.key <= cursorKey {
+				continue
+			}
+
+			value := storageMap.ReadValue(nil, key.mapKey)
+			if value == nil {
+				scanned = storageCursor(domain, key.key)
+				continue
+			}
+
+			typ := storedValueType(inter, value)
+			if query.Type != "" && typ != query.Type {
+				scanned = storageCursor(domain, key.key)
+				continue
+			}
+
+			// a further value matches, so there is a next page
+			if len(result.Items) == limit {
+				result.NextCursor = scanned
+				return result, nil
+			}
+
+			size, err := storedValueSize(storage, common.Address(address), value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute size of %s/%s: %w", domain, key.key, err)
+			}
+
+			result.Items = append(result.Items, StorageItem{
+				Domain: domain,
+				Key:    key.key,
+				Type:   typ,
+				Size:   size,
+			})
+			scanned = storageCursor(domain, key.key)
+		}
+	}
+
+	return result, nil
+}
+
+// storageDomains returns the domains matching the given filter,
+// in listing order.
+func storageDomains(filter []string) ([]string, error) {
+	if len(filter) == 0 {
+		return accountStorageDomains, nil
+	}
+
+	for _, domain := range filter {
+		if domainIndex(domain) < 0 {
+			return nil, types.NewInvalidArgumentError(
+				fmt.Sprintf("unknown storage domain %q, expected one of %s",
+					domain,
+					strings.Join(accountStorageDomains, ", "),
+				),
+			)
+		}
+	}
+
+	domains := make([]string, 0, len(filter))
+	for _, domain := range accountStorageDomains {
+		for _, selected := range filter {
+			if domain == selected {
+				domains = append(domains, domain)
+				break
+			}
+		}
+	}
+
+	return domains, nil
+}
+
+func domainIndex(domain string) int {
+	for i, d := range accountStorageDomains {
+		if d == domain {
+			return i
+		}
+	}
+	return -1
+}
+
+// storageKeys returns the keys of the given storage map, sorted,
+// without decoding the stored values.
+func storageKeys(storageMap *interpreter.StorageMap, domain string) []storageKey {
+	keys := make([]storageKey, 0, storageMap.Count())
+
+	iterator := storageMap.Iterator(nil)
+	for key := iterator.NextKey(); key != nil; key = iterator.NextKey() {
+		switch key := key.(type) {
+		case interpreter.StringAtreeValue:
+			keys = append(keys, storageKey{
+				domain: domain,
+				key:    string(key),
+				mapKey: interpreter.StringStorageMapKey(key),
+			})
+		case interpreter.Uint64AtreeValue:
+			keys = append(keys, storageKey{
+				domain: domain,
+				key:    strconv.FormatUint(uint64(key), 10),
+				mapKey: interpreter.Uint64StorageMapKey(key),
+			})
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].key < keys[j].key
+	})
+
+	return keys
+}
+
+func storageCursor(domain string, key string) string {
+	return domain + "/" + key
+}
+
+func parseStorageCursor(cursor string) (domain string, key string, err error) {
+	if cursor == "" {
+		return "", "", nil
+	}
+
+	domain, key, ok := strings.Cut(cursor, "/")
+	if !ok || domainIndex(domain) < 0 {
+		return "", "", types.NewInvalidArgumentError(fmt.Sprintf("invalid storage cursor %q", cursor))
+	}
+
+	return domain, key, nil
+}
+
+// storedValueType returns the type of the given stored value.
+// Published capabilities are typed without resolving them.
+func storedValueType(inter *interpreter.Interpreter, value interpreter.Value) string {
+	if published, ok := value.(*interpreter.PublishedValue); ok {
+		return capabilityType(published.Value).String()
+	}
+
+	return value.StaticType(inter).String()
+}
+
+// storedValueSize returns the number of bytes the given stored value takes up,
+// following the references to the slabs of nested containers.
+func storedValueSize(storage atree.SlabStorage, address common.Address, value interpreter.Value) (uint64, error) {
+	storable, err := value.Storable(storage, atree.Address(address), math.MaxUint64)
+	if err != nil {
+		return 0, err
+	}
+
+	return storableSize(storage, storable)
+}
+
+func storableSize(storage atree.SlabStorage, storable atree.Storable) (uint64, error) {
+	if id, ok := storable.(atree.StorageIDStorable); ok {
+		slab, found, err := storage.Retrieve(atree.StorageID(id))
+		if err != nil {
+			return 0, err
+		}
+		if !found {
+			return 0, fmt.Errorf("slab %s not found", atree.StorageID(id))
+		}
+		storable = slab
+	}
+
+	size := uint64(storable.ByteSize())
+
+	for _, child := range storable.ChildStorables() {
+		// inlined children are already accounted for by their parent
+		childSize, err := referencedSize(storage, child)
+		if err != nil {
+			return 0, err
+		}
+		size += childSize
+	}
+
+	return size, nil
+}
+
+// referencedSize returns the size of the slabs referenced by the given storable.
+func referencedSize(storage atree.SlabStorage, storable atree.Storable) (uint64, error) {
+	if _, ok := storable.(atree.StorageIDStorable); ok {
+		return storableSize(storage, storable)
+	}
+
+	var size uint64
+	for _, child := range storable.ChildStorables() {
+		childSize, err := referencedSize(storage, child)
+		if err != nil {
+			return 0, err
+		}
+		size += childSize
+	}
+
+	return size, nil
+}
@@ -0,0 +1,118 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/parser"
+)
+
+// ArgumentValidation is the outcome of validating entry point arguments.
+type ArgumentValidation struct {
+	Valid bool `json:"valid"`
+	// Index is the index of the first invalid argument, if the error concerns
+	// a single argument.
+	Index *int `json:"index,omitempty"`
+	// Error describes why the arguments or the code are invalid.
+	Error string `json:"error,omitempty"`
+}
+
+// ValidateArguments checks JSON-CDC encoded arguments against the parameters
+// declared by a script or transaction, without executing it.
+//
+// The code is reduced to an empty script with the same imports, declarations
+// and parameters, which the runtime checks and imports the arguments for, so
+// types declared in deployed contracts are validated against the latest block.
+func (b *Blockchain) ValidateArguments(
+	ctx context.Context,
+	code []byte,
+	arguments [][]byte,
+) (*ArgumentValidation, error) {
+	stub, err := entryPointStub(code)
+	if err != nil {
+		return &ArgumentValidation{Error: err.Error()}, nil
+	}
+
+	result, err := b.ExecuteScript(ctx, stub, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Error == nil {
+		return &ArgumentValidation{Valid: true}, nil
+	}
+
+	var argumentErr *runtime.InvalidEntryPointArgumentError
+	if errors.As(result.Error, &argumentErr) {
+		index := argumentErr.Index
+		return &ArgumentValidation{
+			Index: &index,
+			Error: argumentErr.Error(),
+		}, nil
+	}
+
+	var countErr runtime.InvalidEntryPointParameterCountError
+	if errors.As(result.Error, &countErr) {
+		return &ArgumentValidation{Error: countErr.Error()}, nil
+	}
+
+	return &ArgumentValidation{Error: result.Error.Error()}, nil
+}
+
+// entryPointStub replaces the entry point of a script or transaction with an
+// empty script function taking the same parameters.
+func entryPointStub(code []byte) ([]byte, error) {
+	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := func(list *ast.ParameterList) []byte {
+		if list == nil || len(list.Parameters) == 0 {
+			return []byte("()")
+		}
+		return code[list.StartPos.Offset : list.EndPos.Offset+1]
+	}
+
+	stub := func(start, end int, list *ast.ParameterList) []byte {
+		result := make([]byte, 0, len(code))
+		result = append(result, code[:start]...)
+		result = append(result, "pub fun main"...)
+		result = append(result, parameters(list)...)
+		result = append(result, " {}"...)
+		result = append(result, code[end+1:]...)
+		return result
+	}
+
+	transaction, main, err := findEntryPoint(program)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction != nil {
+		return stub(transaction.StartPos.Offset, transaction.EndPos.Offset, transaction.ParameterList), nil
+	}
+
+	end := main.EndPosition(nil)
+	return stub(main.StartPos.Offset, end.Offset, main.ParameterList), nil
+}
@@ -0,0 +1,157 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+func TestValidateArguments(t *testing.T) {
+
+	t.Parallel()
+
+	serviceAddress := flowsdk.Address(flowgo.Emulator.Chain().ServiceAddress())
+
+	b, err := emulator.New(
+		emulator.Contracts([]emulator.ContractDescription{
+			{
+				Name:    "Shapes",
+				Address: serviceAddress,
+				Source: []byte(`
+					pub contract Shapes {
+						pub struct Point {
+							pub let x: Int
+							pub let y: Int
+							init(x: Int, y: Int) { self.x = x; self.y = y }
+						}
+					}`),
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	script := `
+		pub fun main(a: Int, b: String): Int {
+			panic("not executed")
+		}
+	`
+
+	pointType := fmt.Sprintf("A.%s.Shapes.Point", serviceAddress.Hex())
+	point := fmt.Sprintf(
+		`{"type":"Struct","value":{"id":"%s","fields":[
+			{"name":"x","value":{"type":"Int","value":"1"}},
+			{"name":"y","value":{"type":"Int","value":"2"}}
+		]}}`,
+		pointType,
+	)
+
+	transaction := fmt.Sprintf(`
+		import Shapes from 0x%s
+
+		transaction(point: Shapes.Point, to: Address) {
+			prepare(signer: AuthAccount) {
+				panic("not executed")
+			}
+		}`,
+		serviceAddress.Hex(),
+	)
+
+	validate := func(t *testing.T, code string, arguments ...string) *emulator.ArgumentValidation {
+		encoded := make([][]byte, len(arguments))
+		for i, argument := range arguments {
+			encoded[i] = []byte(argument)
+		}
+
+		result, err := b.ValidateArguments(context.Background(), []byte(code), encoded)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("valid script arguments", func(t *testing.T) {
+		t.Parallel()
+
+		result := validate(t, script,
+			`{"type":"Int","value":"1"}`,
+			`{"type":"String","value":"x"}`,
+		)
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.Error)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		result := validate(t, script,
+			`{"type":"Int","value":"1"}`,
+			`{"type":"Int","value":"2"}`,
+		)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.Index)
+		assert.Equal(t, 1, *result.Index)
+		assert.Contains(t, result.Error, "String")
+	})
+
+	t.Run("argument count mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		result := validate(t, script, `{"type":"Int","value":"1"}`)
+		assert.False(t, result.Valid)
+		assert.Nil(t, result.Index)
+		assert.Contains(t, result.Error, "expected 2, got 1")
+	})
+
+	t.Run("malformed argument", func(t *testing.T) {
+		t.Parallel()
+
+		result := validate(t, script, `{"type":"Int","value":"one"}`, `{"type":"String","value":"x"}`)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.Index)
+		assert.Equal(t, 0, *result.Index)
+	})
+
+	t.Run("transaction with imported type", func(t *testing.T) {
+		t.Parallel()
+
+		result := validate(t, transaction, point, `{"type":"Address","value":"0x01"}`)
+		assert.True(t, result.Valid, result.Error)
+
+		result = validate(t, transaction, `{"type":"Int","value":"1"}`, `{"type":"Address","value":"0x01"}`)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.Index)
+		assert.Equal(t, 0, *result.Index)
+		assert.Contains(t, result.Error, "Shapes.Point")
+	})
+
+	t.Run("code without entry point", func(t *testing.T) {
+		t.Parallel()
+
+		result := validate(t, `pub struct S {}`)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Error, "neither a transaction nor a main function")
+	})
+}
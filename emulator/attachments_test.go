@@ -19,6 +19,7 @@
 package emulator_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/onflow/flow-emulator/emulator"
@@ -45,6 +46,33 @@ func TestAttachments(t *testing.T) {
 		}
 	`
 
-	_, err = b.ExecuteScript([]byte(script), nil)
+	_, err = b.ExecuteScript(context.Background(), []byte(script), nil)
 	require.NoError(t, err)
 }
+
+func TestAttachmentsDisabled(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithAttachmentsEnabled(false),
+	)
+	require.NoError(t, err)
+
+	script := `
+		pub resource R {}
+
+		pub attachment A for R {}
+
+		pub fun main() {
+			let r <- create R()
+			r[A]
+			destroy r
+		}
+	`
+
+	result, err := b.ExecuteScript(context.Background(), []byte(script), nil)
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+	require.Contains(t, result.Error.Error(), "attachments are not enabled")
+}
@@ -0,0 +1,107 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+// AutoSnapshotPrefix prefixes the names of the snapshots created every few blocks,
+// which are followed by the height of the block, e.g. auto-100.
+const AutoSnapshotPrefix = "auto-"
+
+// DefaultAutoSnapshotKeep is the number of automatic snapshots kept by default.
+const DefaultAutoSnapshotKeep = 10
+
+// autoSnapshot creates a snapshot of the state after the block at the given height,
+// if it is a multiple of the snapshot interval, and deletes the oldest automatic
+// snapshots beyond the ones to keep. Failures are logged, and do not fail the commit.
+//
+// Unlike CreateSnapshot, the blockchain is not reloaded, which would drop the transactions
+// added to the new pending block.
+func (b *Blockchain) autoSnapshot(height uint64) {
+	interval := b.conf.AutoSnapshotInterval
+	if interval == 0 || height%interval != 0 {
+		return
+	}
+
+	snapshotProvider, err := b.snapshotProvider()
+	if err != nil {
+		b.conf.ServerLogger.Warn().Err(err).Msg("❗  Failed to create automatic snapshot")
+		return
+	}
+
+	name := fmt.Sprintf("%s%d", AutoSnapshotPrefix, height)
+
+	infos, err := snapshotProvider.SnapshotInfos()
+	if err != nil {
+		b.conf.ServerLogger.Warn().Err(err).Msg("❗  Failed to create automatic snapshot")
+		return
+	}
+
+	// the height may be reached again after a rollback, or loading an older snapshot
+	for _, info := range infos {
+		if info.Name == name {
+			err = snapshotProvider.DeleteSnapshot(name)
+			if err != nil {
+				b.conf.ServerLogger.Warn().Err(err).Msgf("❗  Failed to replace automatic snapshot %s", name)
+				return
+			}
+		}
+	}
+
+	err = snapshotProvider.CreateSnapshot(name, fmt.Sprintf("automatic snapshot at block %d", height))
+	if err != nil {
+		b.conf.ServerLogger.Warn().Err(err).Msgf("❗  Failed to create automatic snapshot %s", name)
+		return
+	}
+
+	b.conf.ServerLogger.Debug().Msgf("📸  Automatic snapshot %s created", name)
+
+	b.pruneAutoSnapshots(snapshotProvider)
+}
+
+// pruneAutoSnapshots deletes the oldest automatic snapshots beyond the ones to keep.
+// The loaded snapshot is never deleted.
+func (b *Blockchain) pruneAutoSnapshots(snapshotProvider storage.SnapshotProvider) {
+	infos, err := snapshotProvider.SnapshotInfos()
+	if err != nil {
+		b.conf.ServerLogger.Warn().Err(err).Msg("❗  Failed to delete old automatic snapshots")
+		return
+	}
+
+	var names []string
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name, AutoSnapshotPrefix) {
+			names = append(names, info.Name)
+		}
+	}
+
+	for len(names) > b.conf.AutoSnapshotKeep {
+		err := snapshotProvider.DeleteSnapshot(names[0])
+		if err != nil && !errors.Is(err, storage.ErrSnapshotLoaded) {
+			b.conf.ServerLogger.Warn().Err(err).Msgf("❗  Failed to delete automatic snapshot %s", names[0])
+		}
+		names = names[1:]
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+func TestAutoSnapshots(t *testing.T) {
+
+	t.Parallel()
+
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	b, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithAutoSnapshots(2, 2),
+	)
+	require.NoError(t, err)
+
+	snapshotNames := func(t *testing.T) []string {
+		infos, err := b.SnapshotInfos()
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(infos))
+		for _, info := range infos {
+			names = append(names, info.Name)
+		}
+		return names
+	}
+
+	commitBlocks := func(t *testing.T, count int) {
+		for i := 0; i < count; i++ {
+			_, err := b.CommitBlock()
+			require.NoError(t, err)
+		}
+	}
+
+	commitBlocks(t, 7)
+	assert.Equal(t, []string{"auto-4", "auto-6"}, snapshotNames(t))
+
+	infos, err := b.SnapshotInfos()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), infos[1].BlockHeight)
+
+	t.Run("load", func(t *testing.T) {
+		err := b.LoadSnapshot("auto-4")
+		require.NoError(t, err)
+
+		block, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), block.Header.Height)
+
+		// the loaded snapshot is kept, and the snapshot of a height reached again is replaced
+		commitBlocks(t, 4)
+		assert.Equal(t, []string{"auto-4", "auto-6", "auto-8"}, snapshotNames(t))
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		_, err := emulator.New(emulator.WithAutoSnapshots(2, 0))
+		assert.Error(t, err)
+	})
+}
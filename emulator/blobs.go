@@ -0,0 +1,64 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"fmt"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+const blobsContract = `
+import MetadataViews from 0x%[1]s
+
+/// EmulatorBlobs resolves the hashes of blobs stored in the admin API of the emulator
+/// to URLs, simulating off-chain storage pointers, e.g. in the metadata of NFTs.
+access(all) contract EmulatorBlobs {
+
+    /// The URL the hashes of blobs are resolved against.
+    access(all) let baseURL: String
+
+    /// Returns the URL of the blob with the given hash.
+    access(all) fun url(_ hash: String): String {
+        return self.baseURL.concat(hash)
+    }
+
+    /// Returns the blob with the given hash as a file of NFT metadata.
+    access(all) fun file(_ hash: String): MetadataViews.HTTPFile {
+        return MetadataViews.HTTPFile(url: self.url(hash))
+    }
+
+    init() {
+        self.baseURL = "%[2]s"
+    }
+}
+`
+
+// NewBlobsContract returns the EmulatorBlobs helper contract, which resolves the hashes
+// of blobs stored in the admin API to URLs under the given base URL.
+func NewBlobsContract(chain flowgo.Chain, baseURL string) ContractDescription {
+	serviceAddress := flowsdk.HexToAddress(chain.ServiceAddress().HexWithPrefix())
+	return ContractDescription{
+		Name:        "EmulatorBlobs",
+		Address:     serviceAddress,
+		Description: "✨  Emulator blobs contract",
+		Source:      []byte(fmt.Sprintf(blobsContract, serviceAddress.Hex(), baseURL)),
+	}
+}
@@ -40,6 +40,8 @@ func NewBlocksTicker(
 }
 
 func (t *BlocksTicker) Start() error {
+	defer t.ticker.Stop()
+
 	for {
 		select {
 		case <-t.ticker.C:
@@ -97,7 +97,7 @@ func TestBlockInfo(t *testing.T) {
 			}
 		`)
 
-		result, err := b.ExecuteScript(script, nil)
+		result, err := b.ExecuteScript(context.Background(), script, nil)
 		assert.NoError(t, err)
 
 		assert.True(t, result.Succeeded())
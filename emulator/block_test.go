@@ -22,8 +22,10 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
 	"github.com/onflow/flow-emulator/emulator"
 	"github.com/rs/zerolog"
 
@@ -31,6 +33,8 @@ import (
 	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/types"
 )
 
 func TestCommitBlock(t *testing.T) {
@@ -129,7 +133,7 @@ func TestBlockView(t *testing.T) {
 	adapter := adapters.NewSDKAdapter(&logger, b)
 
 	t.Run("genesis should have 0 view", func(t *testing.T) {
-		block, err := b.GetBlockByHeight(0)
+		block, err := b.GetBlockByHeight(context.Background(), 0)
 		require.NoError(t, err)
 		assert.Equal(t, uint64(0), block.Header.Height)
 		assert.Equal(t, uint64(0), block.Header.View)
@@ -164,7 +168,7 @@ func TestBlockView(t *testing.T) {
 	const MaxViewIncrease = 3
 
 	for height := uint64(1); height <= nBlocks+1; height++ {
-		block, err := b.GetBlockByHeight(height)
+		block, err := b.GetBlockByHeight(context.Background(), height)
 		require.NoError(t, err)
 
 		maxView := height * MaxViewIncrease
@@ -174,3 +178,293 @@ func TestBlockView(t *testing.T) {
 		})
 	}
 }
+
+func TestDeterministicTime(t *testing.T) {
+
+	t.Parallel()
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newBlockchain := func(t *testing.T) *emulator.Blockchain {
+		b, err := emulator.New(
+			emulator.WithDeterministicTime(start, time.Second),
+		)
+		require.NoError(t, err)
+		return b
+	}
+
+	commitBlocks := func(t *testing.T, b *emulator.Blockchain, count int) []*flowgo.Block {
+		blocks := make([]*flowgo.Block, count)
+		for i := range blocks {
+			block, err := b.CommitBlock()
+			require.NoError(t, err)
+			blocks[i] = block
+		}
+		return blocks
+	}
+
+	t.Run("timestamps and views", func(t *testing.T) {
+		blocks := commitBlocks(t, newBlockchain(t), 3)
+
+		for i, block := range blocks {
+			assert.Equal(t, start.Add(time.Duration(i)*time.Second), block.Header.Timestamp)
+			assert.Equal(t, uint64(i+1), block.Header.View)
+		}
+	})
+
+	t.Run("same block IDs across runs", func(t *testing.T) {
+		first := commitBlocks(t, newBlockchain(t), 3)
+		second := commitBlocks(t, newBlockchain(t), 3)
+
+		for i := range first {
+			assert.Equal(t, first[i].ID(), second[i].ID())
+		}
+	})
+
+	t.Run("same block IDs after rollback", func(t *testing.T) {
+		b := newBlockchain(t)
+		blocks := commitBlocks(t, b, 3)
+
+		err := b.RollbackToBlockHeight(1)
+		require.NoError(t, err)
+
+		replayed := commitBlocks(t, b, 2)
+		assert.Equal(t, blocks[1].ID(), replayed[0].ID())
+		assert.Equal(t, blocks[2].ID(), replayed[1].ID())
+	})
+
+	t.Run("advance time", func(t *testing.T) {
+		b := newBlockchain(t)
+
+		_, err := b.AdvanceTime(time.Hour)
+		require.NoError(t, err)
+
+		blocks := commitBlocks(t, b, 2)
+		assert.Equal(t, start.Add(time.Hour), blocks[0].Header.Timestamp)
+		assert.Equal(t, start.Add(time.Hour+time.Second), blocks[1].Header.Timestamp)
+	})
+
+	t.Run("invalid step", func(t *testing.T) {
+		_, err := emulator.New(
+			emulator.WithDeterministicTime(start, 0),
+		)
+		assert.Error(t, err)
+	})
+}
+
+func TestConsensusDelay(t *testing.T) {
+
+	t.Parallel()
+
+	const delay = time.Second
+
+	b, err := emulator.New(
+		emulator.WithConsensusDelay(delay),
+	)
+	require.NoError(t, err)
+
+	b.EnableAutoMine()
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	startBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	var txIDs []flowsdk.Identifier
+	for i := uint64(0); i < 2; i++ {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { execute { log("hello") } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber+i).
+			SetPayer(b.ServiceKey().Address)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = adapter.SendTransaction(context.Background(), *tx)
+		require.NoError(t, err)
+
+		txIDs = append(txIDs, tx.ID())
+	}
+
+	// transactions are accepted, but not yet included in a block
+	for _, txID := range txIDs {
+		result, err := adapter.GetTransactionResult(context.Background(), txID)
+		require.NoError(t, err)
+		assert.Equal(t, flowsdk.TransactionStatusPending, result.Status)
+	}
+
+	require.Eventually(t, func() bool {
+		block, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		return block.Header.Height > startBlock.Header.Height
+	}, 10*delay, delay/10)
+
+	// both transactions were included in the same block
+	block, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, startBlock.Header.Height+1, block.Header.Height)
+
+	for _, txID := range txIDs {
+		result, err := adapter.GetTransactionResult(context.Background(), txID)
+		require.NoError(t, err)
+		assert.Equal(t, flowsdk.TransactionStatusSealed, result.Status)
+	}
+}
+
+func TestBlockTime(t *testing.T) {
+
+	t.Parallel()
+
+	const blockTime = 100 * time.Millisecond
+
+	b, err := emulator.New(
+		emulator.WithBlockTime(blockTime),
+	)
+	require.NoError(t, err)
+	defer b.StopBlockProduction()
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	startBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	// blocks are committed without any transactions
+	require.Eventually(t, func() bool {
+		block, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		return block.Header.Height >= startBlock.Header.Height+3
+	}, 50*blockTime, blockTime/10)
+
+	// transactions are included in the next block
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(`transaction { execute { log("hello") } }`)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = adapter.SendTransaction(context.Background(), *tx)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		result, err := adapter.GetTransactionResult(context.Background(), tx.ID())
+		require.NoError(t, err)
+		return result.Status == flowsdk.TransactionStatusSealed
+	}, 50*blockTime, blockTime/10)
+
+	// no blocks are committed once the blockchain is closed
+	b.Close()
+
+	stoppedBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	time.Sleep(3 * blockTime)
+
+	block, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, stoppedBlock.Header.Height, block.Header.Height)
+}
+
+func TestBlockGasLimit(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithBlockGasLimit(2 * flowgo.DefaultMaxTransactionGasLimit),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	startBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	var txIDs []flowsdk.Identifier
+	for i := uint64(0); i < 3; i++ {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { execute { log("hello") } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber+i).
+			SetPayer(b.ServiceKey().Address)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		txIDs = append(txIDs, tx.ID())
+	}
+
+	// the first two transactions fill the block
+	block, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, startBlock.Header.Height+1, block.Header.Height)
+
+	for _, txID := range txIDs[:2] {
+		result, err := adapter.GetTransactionResult(context.Background(), txID)
+		require.NoError(t, err)
+		assert.Equal(t, flowsdk.TransactionStatusSealed, result.Status)
+	}
+
+	// the last transaction is deferred to the next block
+	result, err := adapter.GetTransactionResult(context.Background(), txIDs[2])
+	require.NoError(t, err)
+	assert.Equal(t, flowsdk.TransactionStatusPending, result.Status)
+
+	block, results, err = b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, txIDs[2].String(), results[0].TransactionID.String())
+	assert.Equal(t, startBlock.Header.Height+2, block.Header.Height)
+
+	result, err = adapter.GetTransactionResult(context.Background(), txIDs[2])
+	require.NoError(t, err)
+	assert.Equal(t, flowsdk.TransactionStatusSealed, result.Status)
+}
+
+func TestGetBlocksByHeightRange(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := b.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	blocks, err := b.GetBlocksByHeightRange(context.Background(), 1, 3)
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+	for i, block := range blocks {
+		expected, err := b.GetBlockByHeight(context.Background(), uint64(i+1))
+		require.NoError(t, err)
+		assert.Equal(t, expected.ID(), block.ID())
+	}
+
+	_, err = b.GetBlocksByHeightRange(context.Background(), 2, 4)
+	var notFoundErr *types.BlockNotFoundByHeightError
+	assert.ErrorAs(t, err, &notFoundErr)
+
+	_, err = b.GetBlocksByHeightRange(context.Background(), 3, 2)
+	var invalidErr *types.InvalidArgumentError
+	assert.ErrorAs(t, err, &invalidErr)
+}
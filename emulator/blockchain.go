@@ -31,9 +31,11 @@ package emulator
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"math/rand"
 	"strings"
 	"sync"
 	"time"
@@ -48,6 +50,7 @@ import (
 	"github.com/onflow/flow-emulator/storage/util"
 	"github.com/onflow/flow-emulator/types"
 	"github.com/onflow/flow-emulator/utils"
+	"github.com/onflow/flow-emulator/utils/requestid"
 	flowsdk "github.com/onflow/flow-go-sdk"
 	sdkcrypto "github.com/onflow/flow-go-sdk/crypto"
 	"github.com/onflow/flow-go/access"
@@ -61,7 +64,10 @@ import (
 	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
 	"github.com/onflow/flow-go/fvm/storage/snapshot"
 	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var _ Emulator = &Blockchain{}
@@ -75,6 +81,11 @@ func New(opts ...Option) (*Blockchain, error) {
 		opt(&conf)
 	}
 
+	return newBlockchain(conf)
+}
+
+// newBlockchain instantiates a new emulated emulator with the provided config.
+func newBlockchain(conf config) (*Blockchain, error) {
 	b := &Blockchain{
 		storage:                conf.GetStore(),
 		serviceKey:             conf.GetServiceKey(),
@@ -83,17 +94,75 @@ func New(opts ...Option) (*Blockchain, error) {
 		conf:                   conf,
 		clock:                  NewSystemClock(),
 		sourceFileMap:          make(map[common.Location]string),
+		subscriptions:          &subscriptions{},
+		profiler:               newContractProfiler(),
+		dependencies:           newDependencyGraphs(),
+		computationReports:     newComputationReports(),
+		fvmStats:               &fvmStats{},
+		scriptPool:             newScriptPool(conf.ScriptWorkers),
+		faults:                 &faults{},
+	}
+	if conf.TransactionExpiry > 0 && conf.TransactionExpiryBuffer >= conf.TransactionExpiry {
+		return nil, fmt.Errorf("transaction expiry: the buffer must be lower than the expiry")
+	}
+	if conf.DeterministicTime {
+		if conf.DeterministicTimeStep <= 0 {
+			return nil, fmt.Errorf("deterministic time: the step between blocks must be positive")
+		}
+		b.deterministicClock = NewDeterministicClock(conf.DeterministicTimeStart, conf.DeterministicTimeStep)
+		b.clock = b.deterministicClock
 	}
 	err := b.ReloadBlockchain()
 	if err != nil {
 		return nil, err
 	}
+	if conf.AutoSnapshotInterval > 0 {
+		if conf.AutoSnapshotKeep < 1 {
+			return nil, fmt.Errorf("automatic snapshots: at least one snapshot must be kept")
+		}
+		_, err := b.snapshotProvider()
+		if err != nil {
+			return nil, fmt.Errorf("automatic snapshots: %w", err)
+		}
+	}
+	if conf.StateHistory > 0 {
+		_, ok := b.storage.(storage.LedgerPruner)
+		if !ok {
+			return nil, fmt.Errorf("state history: storage doesn't support pruning")
+		}
+		b.statePruner = newStatePruner(&b.conf.ServerLogger)
+	}
+	err = b.loadCoverageReport()
+	if err != nil {
+		return nil, err
+	}
+	if conf.MetricsRegisterer != nil {
+		b.unregisterMetrics, err = b.fvmStats.register(conf.MetricsRegisterer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	if conf.BootstrapAccounts > 0 {
+		err := b.bootstrapTestAccounts()
+		if err != nil {
+			return nil, err
+		}
+	}
 	if len(conf.Contracts) > 0 {
 		err := DeployContracts(b, conf.Contracts)
 		if err != nil {
 			return nil, err
 		}
 	}
+	if conf.BlockTime > 0 {
+		b.DisableAutoMine()
+		b.blockProducer = NewBlocksTicker(b, conf.BlockTime)
+		b.blockProducerDone = make(chan struct{})
+		go func(producer *BlocksTicker, done chan struct{}) {
+			_ = producer.Start()
+			close(done)
+		}(b.blockProducer, b.blockProducerDone)
+	}
 	return b, nil
 
 }
@@ -184,6 +253,16 @@ func WithTransactionMaxGasLimit(maxLimit uint64) Option {
 	}
 }
 
+// WithTransactionMaxByteSize sets the maximum size of transactions in bytes,
+// e.g. to submit transactions with multi-megabyte arguments.
+//
+// The default is the limit of access nodes, flowgo.DefaultMaxTransactionByteSize.
+func WithTransactionMaxByteSize(size uint64) Option {
+	return func(c *config) {
+		c.TransactionMaxByteSize = size
+	}
+}
+
 // WithScriptGasLimit sets the gas limit for scripts.
 //
 // This limit does not affect transactions, which declare their own limit.
@@ -194,13 +273,147 @@ func WithScriptGasLimit(limit uint64) Option {
 	}
 }
 
-// WithTransactionExpiry sets the transaction expiry measured in blocks.
+// WithErrorMessageMaxLength sets the maximum length of the error messages of
+// transaction results returned by the Access API, like mainnet nodes do.
+//
+// Longer messages are truncated around types.TruncatedMarker. The full messages
+// remain available with GetTransactionError. If set to zero, messages are not truncated.
+func WithErrorMessageMaxLength(maxLength int) Option {
+	return func(c *config) {
+		c.ErrorMessageMaxLength = maxLength
+	}
+}
+
+// WithComputationReporting records the computation used by every transaction,
+// broken down by computation kind, for GetComputationReport.
+//
+// The default is false.
+func WithComputationReporting(enabled bool) Option {
+	return func(c *config) {
+		c.ComputationReportingEnabled = enabled
+	}
+}
+
+// WithMetricsRegisterer registers the FVM statistics of the blockchain, see FVMStats,
+// as Prometheus metrics with the given registerer. Blockchains sharing a registerer
+// must wrap it with distinct labels, e.g. with prometheus.WrapRegistererWith.
+//
+// The default is nil, the statistics are not registered.
+func WithMetricsRegisterer(registerer prometheus.Registerer) Option {
+	return func(c *config) {
+		c.MetricsRegisterer = registerer
+	}
+}
+
+// WithScriptTimeout sets the maximum wall-clock time a script may run.
+//
+// Scripts running longer than the timeout are interrupted and return a
+// ScriptInterruptedError. If set to zero, scripts are only bounded by
+// their gas limit and the context passed by the caller.
+func WithScriptTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.ScriptTimeout = timeout
+	}
+}
+
+// WithScriptWorkers sets the maximum number of scripts executed concurrently.
+//
+// Scripts submitted while all workers are busy wait for a free worker, or
+// return a ScriptInterruptedError if their context ends first. The default,
+// also used if workers is less than one, is the number of CPUs.
+func WithScriptWorkers(workers int) Option {
+	return func(c *config) {
+		c.ScriptWorkers = workers
+	}
+}
+
+// WithInteractiveExecutionTimeout sets how long an interactive execution may stay
+// paused before it is aborted, unlocking the emulator. If set to zero,
+// DefaultInteractiveExecutionTimeout is used.
+func WithInteractiveExecutionTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.InteractiveExecutionTimeout = timeout
+	}
+}
+
+// WithConsensusDelay sets an artificial delay between a transaction being
+// accepted and its inclusion in a block when auto-mining.
+//
+// Transactions submitted during the delay are included in the same block,
+// mimicking the sealing latency of a live network. If set to zero, blocks are
+// committed as soon as a transaction is submitted.
+func WithConsensusDelay(delay time.Duration) Option {
+	return func(c *config) {
+		c.ConsensusDelay = delay
+	}
+}
+
+// WithBlockTime commits a block at a fixed interval, whether or not
+// transactions arrive, matching the cadence of a live network.
+//
+// Auto-mining is disabled while blocks are produced, so transactions are
+// included in the next block. Block production runs until
+// StopBlockProduction or Close is called.
+func WithBlockTime(blockTime time.Duration) Option {
+	return func(c *config) {
+		c.BlockTime = blockTime
+	}
+}
+
+// WithBlockGasLimit sets the total gas limit of the transactions included in a block.
+//
+// When the gas limits declared by the pending transactions exceed it, the remaining
+// transactions are deferred to the next block, like collections on a live network
+// are bounded in size. A block always includes at least one transaction.
+// If set to zero, blocks include all pending transactions.
+func WithBlockGasLimit(limit uint64) Option {
+	return func(c *config) {
+		c.BlockGasLimit = limit
+	}
+}
+
+// WithMaxCollectionSize sets the maximum number of transactions in a collection.
+//
+// Blocks with more transactions include several collection guarantees, like blocks
+// of a live network. If set to zero, blocks include all transactions in a single collection.
+func WithMaxCollectionSize(size int) Option {
+	return func(c *config) {
+		c.MaxCollectionSize = size
+	}
+}
+
+// WithReadOnly rejects transactions and local block commits.
+//
+// Read-only emulators only serve queries and scripts; blocks are added by
+// applying blocks committed elsewhere, see ApplyCommittedBlock.
+func WithReadOnly(readOnly bool) Option {
+	return func(c *config) {
+		c.ReadOnly = readOnly
+	}
+}
+
+// WithTransactionExpiry sets the transaction expiry measured in blocks,
+// and requires transactions to reference a block.
 //
 // If set to zero, transaction expiry is disabled and the reference block ID field
 // is not required.
+//
+// By default, transactions expire after flow.DefaultTransactionExpiry blocks,
+// but transactions without a reference block are accepted.
 func WithTransactionExpiry(expiry uint) Option {
 	return func(c *config) {
 		c.TransactionExpiry = expiry
+		c.ReferenceBlockRequired = expiry > 0
+	}
+}
+
+// WithTransactionExpiryBuffer sets the number of blocks before their expiry transactions are
+// already rejected, like access nodes do so transactions have time to be included in a block.
+//
+// The buffer must be lower than the transaction expiry.
+func WithTransactionExpiryBuffer(buffer uint) Option {
+	return func(c *config) {
+		c.TransactionExpiryBuffer = buffer
 	}
 }
 
@@ -244,6 +457,90 @@ func WithTransactionFeesEnabled(enabled bool) Option {
 	}
 }
 
+// WithFeeParameters sets the transaction fee parameters of new emulators.
+//
+// Transactions are only charged fees if transaction fees are enabled.
+// The default is DefaultFeeParameters.
+func WithFeeParameters(parameters FeeParameters) Option {
+	return func(c *config) {
+		c.FeeParameters = parameters
+	}
+}
+
+// WithExecutionEffortWeights sets the execution effort weights of new emulators,
+// by kind of computation. The weights replace the default weights,
+// kinds of computation without a weight do not add to the execution effort.
+//
+// The default is DefaultExecutionEffortWeights.
+func WithExecutionEffortWeights(weights map[common.ComputationKind]uint64) Option {
+	return func(c *config) {
+		c.ExecutionEffortWeights = weights
+	}
+}
+
+// WithBootstrapAccounts creates the given number of test accounts when the emulator starts,
+// each funded with the initial balance from the service account.
+//
+// The keys of the accounts are deterministic, and the accounts are reused
+// when the emulator restarts on the same storage. See Blockchain.TestAccounts.
+//
+// The default is no test accounts.
+func WithBootstrapAccounts(n int, initialBalance cadence.UFix64) Option {
+	return func(c *config) {
+		c.BootstrapAccounts = n
+		c.BootstrapAccountBalance = initialBalance
+	}
+}
+
+// WithAutoSnapshots creates a snapshot every interval blocks, named after the height
+// of the block, e.g. auto-100, keeping the given number of the latest ones.
+// The storage must support snapshots.
+//
+// The default is no automatic snapshots, keeping 10 if enabled.
+func WithAutoSnapshots(interval uint64, keep int) Option {
+	return func(c *config) {
+		c.AutoSnapshotInterval = interval
+		c.AutoSnapshotKeep = keep
+	}
+}
+
+// WithStateHistory keeps the ledger state of the last n blocks only. The register
+// versions only needed to read the state of older blocks are garbage-collected in the
+// background, which bounds the disk usage of long-running emulators. Reading the state
+// of older blocks, e.g. executing scripts at their height, fails. The storage must
+// support pruning.
+//
+// The default, zero, keeps the state of all blocks.
+func WithStateHistory(n uint64) Option {
+	return func(c *config) {
+		c.StateHistory = n
+	}
+}
+
+// WithTracer records the execution of transactions and scripts as spans with the given tracer,
+// including the spans of the FVM, ledger reads and Cadence interpretation.
+//
+// The default is no tracing.
+func WithTracer(tracer module.Tracer) Option {
+	return func(c *config) {
+		c.Tracer = tracer
+	}
+}
+
+// WithDeterministicTime timestamps the block at every height with a fixed time, the start
+// time advanced by the step for every block after the first one, and increments views
+// by one, so that block IDs are the same across runs. Advancing time still moves the
+// timestamps of the following blocks forward.
+//
+// The default is the system clock, and views incrementing by a random amount.
+func WithDeterministicTime(start time.Time, step time.Duration) Option {
+	return func(c *config) {
+		c.DeterministicTime = true
+		c.DeterministicTimeStart = start
+		c.DeterministicTimeStep = step
+	}
+}
+
 // WithContractRemovalEnabled restricts/allows removal of already deployed contracts.
 //
 // The default is provided by on-chain value.
@@ -264,6 +561,33 @@ func WithTransactionValidationEnabled(enabled bool) Option {
 	}
 }
 
+// WithAttachmentsEnabled enables/disables Cadence attachments.
+//
+// The default is true.
+func WithAttachmentsEnabled(enabled bool) Option {
+	return func(c *config) {
+		c.AttachmentsEnabled = enabled
+	}
+}
+
+// WithAccountLinkingEnabled enables/disables Cadence account linking.
+//
+// The default is true.
+func WithAccountLinkingEnabled(enabled bool) Option {
+	return func(c *config) {
+		c.AccountLinkingEnabled = enabled
+	}
+}
+
+// WithCapabilityControllersEnabled enables/disables Cadence capability controllers.
+//
+// The default is true.
+func WithCapabilityControllersEnabled(enabled bool) Option {
+	return func(c *config) {
+		c.CapabilityControllersEnabled = enabled
+	}
+}
+
 // WithChainID sets chain type for address generation
 // The default is emulator.
 func WithChainID(chainID flowgo.ChainID) Option {
@@ -303,6 +627,8 @@ type Blockchain struct {
 	// pending block containing block info, register state, pending transactions
 	pendingBlock *pendingBlock
 	clock        Clock
+	// moved to the time of every new pending block, if time is deterministic
+	deterministicClock *DeterministicClock
 
 	// used to execute transactions and scripts
 	vm    *fvm.VirtualMachine
@@ -314,14 +640,66 @@ type Blockchain struct {
 
 	debugger               *interpreter.Debugger
 	activeDebuggingSession bool
-	currentCode            string
-	currentScriptID        string
+	// code of the transactions and scripts being executed, by ID, for the debugger
+	running sync.Map
 
 	conf config
 
 	coverageReportedRuntime *CoverageReportedRuntime
+	runtimeConfig           runtime.Config
 
+	sourceFileMu  sync.RWMutex
 	sourceFileMap map[common.Location]string
+
+	subscriptions *subscriptions
+	profiler      *contractProfiler
+	// dependency graphs of the most recent blocks committed since the emulator started
+	dependencies *dependencyGraphs
+	// computation reports of the most recently executed transactions, if computation reporting is enabled
+	computationReports *computationReports
+	// cache, runtime pool and ledger view usage of the FVM
+	fvmStats *fvmStats
+	// unregisters the FVM statistics, if they are registered as metrics
+	unregisterMetrics func()
+
+	// events expected in the pending block, checked when it is committed
+	expectations     []EventExpectation
+	expectationCount uint64
+	// expectations reported as errors which were not matched, until cleared
+	unmatchedExpectations []UnmatchedEventExpectation
+
+	// rules injecting faults into the transactions and scripts they match
+	faults *faults
+
+	// pre-funded accounts created during bootstrap
+	testAccounts []TestAccount
+
+	// transactions waiting for the block they are scheduled in, by height
+	scheduled []scheduledTransaction
+	// scheduled transactions added to the pending block, scheduled again if it is recreated
+	includedScheduled []scheduledTransaction
+
+	// set while an auto-mined block is waiting for the consensus delay
+	delayedCommitScheduled bool
+
+	// bounds the number of scripts executing concurrently
+	scriptPool *scriptPool
+
+	// commits blocks at a fixed interval, if a block time is configured
+	blockProducer *BlocksTicker
+	// closed once the block producer stopped
+	blockProducerDone chan struct{}
+
+	// the oldest block height whose ledger state is kept
+	prunedHeight uint64
+	// pinnedHeights are the heights whose ledger state is not pruned
+	pinnedHeights map[uint64]struct{}
+	// garbage-collects the state of pruned blocks, if the state history is bounded
+	statePruner *statePruner
+
+	// state the blockchain returns to when its state is reset, if standby is enabled
+	standby       storage.Store
+	standbyHeight uint64
 }
 
 // config is a set of configuration options for an emulated emulator.
@@ -331,20 +709,48 @@ type config struct {
 	SimpleAddresses              bool
 	GenesisTokenSupply           cadence.UFix64
 	TransactionMaxGasLimit       uint64
+	TransactionMaxByteSize       uint64
 	ScriptGasLimit               uint64
+	ErrorMessageMaxLength        int
+	ComputationReportingEnabled  bool
+	MetricsRegisterer            prometheus.Registerer
 	TransactionExpiry            uint
+	TransactionExpiryBuffer      uint
+	ReferenceBlockRequired       bool
 	StorageLimitEnabled          bool
 	TransactionFeesEnabled       bool
+	FeeParameters                FeeParameters
+	ExecutionEffortWeights       map[common.ComputationKind]uint64
 	ContractRemovalEnabled       bool
 	MinimumStorageReservation    cadence.UFix64
 	StorageMBPerFLOW             cadence.UFix64
 	Logger                       zerolog.Logger
 	ServerLogger                 zerolog.Logger
 	TransactionValidationEnabled bool
+	AttachmentsEnabled           bool
+	AccountLinkingEnabled        bool
+	CapabilityControllersEnabled bool
 	ChainID                      flowgo.ChainID
 	CoverageReport               *runtime.CoverageReport
 	AutoMine                     bool
 	Contracts                    []ContractDescription
+	ScriptTimeout                time.Duration
+	ScriptWorkers                int
+	InteractiveExecutionTimeout  time.Duration
+	ConsensusDelay               time.Duration
+	BlockTime                    time.Duration
+	BlockGasLimit                uint64
+	MaxCollectionSize            int
+	ReadOnly                     bool
+	BootstrapAccounts            int
+	BootstrapAccountBalance      cadence.UFix64
+	AutoSnapshotInterval         uint64
+	AutoSnapshotKeep             int
+	StateHistory                 uint64
+	Tracer                       module.Tracer
+	DeterministicTime            bool
+	DeterministicTimeStart       time.Time
+	DeterministicTimeStep        time.Duration
 }
 
 func (conf config) GetStore() storage.Store {
@@ -392,20 +798,44 @@ var defaultConfig = func() config {
 		GenesisTokenSupply:           genesisTokenSupply,
 		ScriptGasLimit:               defaultScriptGasLimit,
 		TransactionMaxGasLimit:       defaultTransactionMaxGasLimit,
+		TransactionMaxByteSize:       flowgo.DefaultMaxTransactionByteSize,
 		MinimumStorageReservation:    fvm.DefaultMinimumStorageReservation,
 		StorageMBPerFLOW:             fvm.DefaultStorageMBPerFLOW,
-		TransactionExpiry:            0, // TODO: replace with sensible default
+		TransactionExpiry:            flowgo.DefaultTransactionExpiry,
 		StorageLimitEnabled:          true,
+		FeeParameters:                DefaultFeeParameters,
+		ExecutionEffortWeights:       DefaultExecutionEffortWeights,
 		Logger:                       zerolog.Nop(),
 		ServerLogger:                 zerolog.Nop(),
 		TransactionValidationEnabled: true,
+		AttachmentsEnabled:           true,
+		AccountLinkingEnabled:        true,
+		CapabilityControllersEnabled: true,
 		ChainID:                      flowgo.Emulator,
 		CoverageReport:               nil,
 		AutoMine:                     false,
+		ScriptWorkers:                defaultScriptWorkers,
+		AutoSnapshotKeep:             DefaultAutoSnapshotKeep,
 	}
 }()
 
+// ReloadBlockchain reloads the blockchain from the storage, and recreates the pending
+// block on top of the latest block, with the transactions scheduled up to its height.
 func (b *Blockchain) ReloadBlockchain() error {
+	b.rescheduleIncludedTransactions()
+
+	err := b.reloadBlockchain()
+	if err != nil {
+		return err
+	}
+
+	b.includeScheduledTransactions()
+
+	return nil
+}
+
+// reloadBlockchain reloads the blockchain from the storage, and recreates an empty pending block.
+func (b *Blockchain) reloadBlockchain() error {
 	var err error
 
 	blocks := newBlocks(b)
@@ -424,12 +854,50 @@ func (b *Blockchain) ReloadBlockchain() error {
 		return err
 	}
 
-	b.pendingBlock = newPendingBlock(latestBlock, latestLedger, b.clock)
+	b.prunedHeight, err = loadPrunedHeight(b.storage)
+	if err != nil {
+		return err
+	}
+
+	b.pinnedHeights, err = loadPinnedHeights(b.storage)
+	if err != nil {
+		return err
+	}
+
+	b.pendingBlock = b.newPendingBlock(latestBlock, latestLedger)
 	b.transactionValidator = configureTransactionValidator(b.conf, blocks)
 
 	return nil
 }
 
+// discardBlockState drops what is kept about the committed and pending blocks besides
+// the storage, when the blocks are replaced: the scheduled transactions, the event
+// expectations, the dependency graphs and computation reports, and the state pruning
+// in progress.
+func (b *Blockchain) discardBlockState() {
+	b.scheduled = nil
+	b.includedScheduled = nil
+	b.expectations = nil
+	b.unmatchedExpectations = nil
+	b.dependencies = newDependencyGraphs()
+	b.computationReports = newComputationReports()
+	b.statePruner.reset()
+}
+
+// newPendingBlock creates the pending block following the given block.
+func (b *Blockchain) newPendingBlock(prevBlock *flowgo.Block, ledger snapshot.StorageSnapshot) *pendingBlock {
+	if b.deterministicClock != nil {
+		b.deterministicClock.SetHeight(prevBlock.Header.Height + 1)
+		return newPendingBlock(prevBlock, ledger, b.clock, 1, b.conf.MaxCollectionSize)
+	}
+
+	// the view increments by between 1 and MaxViewIncrease to match
+	// behaviour on a real network, where views are not consecutive
+	viewIncrease := uint64(rand.Intn(MaxViewIncrease) + 1)
+
+	return newPendingBlock(prevBlock, ledger, b.clock, viewIncrease, b.conf.MaxCollectionSize)
+}
+
 func (b *Blockchain) EnableAutoMine() {
 	b.conf.AutoMine = true
 }
@@ -438,6 +906,39 @@ func (b *Blockchain) DisableAutoMine() {
 	b.conf.AutoMine = false
 }
 
+// StopBlockProduction stops committing blocks at the interval set with
+// WithBlockTime, and waits for a block being committed. Blocks are then only
+// committed when requested.
+func (b *Blockchain) StopBlockProduction() {
+	b.mu.Lock()
+	producer, done := b.blockProducer, b.blockProducerDone
+	b.blockProducer, b.blockProducerDone = nil, nil
+	b.mu.Unlock()
+
+	if producer != nil {
+		producer.Stop()
+		<-done
+	}
+}
+
+// Close stops the block production and the state pruning running in the
+// background, interrupting the pruning, persists the coverage report and
+// unregisters the metrics of the blockchain. The storage is not closed, as it is
+// owned by whoever created it.
+func (b *Blockchain) Close() {
+	b.StopBlockProduction()
+	b.statePruner.stop()
+
+	b.mu.RLock()
+	encodedCoverageReport := b.encodeCoverageReport()
+	b.mu.RUnlock()
+	b.persistCoverageReport(encodedCoverageReport)
+
+	if b.unregisterMetrics != nil {
+		b.unregisterMetrics()
+	}
+}
+
 func (b *Blockchain) Ping() error {
 	return nil
 }
@@ -460,18 +961,35 @@ func (b *Blockchain) rollbackProvider() (storage.RollbackProvider, error) {
 	return rollbackProvider, nil
 }
 
+// RollbackToBlockHeight removes the blocks above the given height and
+// reloads the blockchain on top of the block at that height.
 func (b *Blockchain) RollbackToBlockHeight(height uint64) error {
+	b.mu.Lock()
+	defer b.unlock()
 
 	rollbackProvider, err := b.rollbackProvider()
 	if err != nil {
 		return err
 	}
 
+	err = b.checkStateAvailable(height)
+	if err != nil {
+		return err
+	}
+
 	err = rollbackProvider.RollbackToBlockHeight(height)
 	if err != nil {
 		return err
 	}
 
+	err = b.rollbackPrunedState(height)
+	if err != nil {
+		return err
+	}
+
+	// the reports of the transactions rolled back are dropped
+	b.computationReports = newComputationReports()
+
 	return b.ReloadBlockchain()
 }
 
@@ -483,7 +1001,11 @@ func (b *Blockchain) snapshotProvider() (storage.SnapshotProvider, error) {
 	return snapshotProvider, nil
 }
 
+// Snapshots returns the names of the snapshots, oldest first.
 func (b *Blockchain) Snapshots() ([]string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	snapshotProvider, err := b.snapshotProvider()
 	if err != nil {
 		return []string{}, err
@@ -491,19 +1013,60 @@ func (b *Blockchain) Snapshots() ([]string, error) {
 	return snapshotProvider.Snapshots()
 }
 
-func (b *Blockchain) CreateSnapshot(name string) error {
+// SnapshotInfos returns the metadata of the snapshots, oldest first.
+func (b *Blockchain) SnapshotInfos() ([]storage.SnapshotInfo, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	snapshotProvider, err := b.snapshotProvider()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = snapshotProvider.CreateSnapshot(name)
+	return snapshotProvider.SnapshotInfos()
+}
+
+// CreateSnapshot saves the current state under the given name, with an
+// optional description of what it contains.
+func (b *Blockchain) CreateSnapshot(name string, description string) error {
+	encodedCoverageReport, err := b.createSnapshot(name, description)
 	if err != nil {
 		return err
 	}
-	return b.ReloadBlockchain()
+
+	// the coverage report is persisted outside the lock, as it may be large
+	b.persistCoverageReport(encodedCoverageReport)
+
+	return nil
+}
+
+// createSnapshot creates the snapshot and returns the encoded coverage report,
+// as of the snapshot.
+func (b *Blockchain) createSnapshot(name string, description string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	snapshotProvider, err := b.snapshotProvider()
+	if err != nil {
+		return nil, err
+	}
+	err = snapshotProvider.CreateSnapshot(name, description)
+	if err != nil {
+		return nil, err
+	}
+	err = b.ReloadBlockchain()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.encodeCoverageReport(), nil
 }
 
+// LoadSnapshot replaces the current state with the snapshot with the given name
+// and reloads the blockchain on top of it.
 func (b *Blockchain) LoadSnapshot(name string) error {
+	b.mu.Lock()
+	defer b.unlock()
+
 	snapshotProvider, err := b.snapshotProvider()
 	if err != nil {
 		return err
@@ -515,14 +1078,36 @@ func (b *Blockchain) LoadSnapshot(name string) error {
 	return b.ReloadBlockchain()
 }
 
+// DeleteSnapshot removes the snapshot with the given name.
+// The snapshot the emulator runs on can not be deleted.
+func (b *Blockchain) DeleteSnapshot(name string) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	snapshotProvider, err := b.snapshotProvider()
+	if err != nil {
+		return err
+	}
+	return snapshotProvider.DeleteSnapshot(name)
+}
+
 type CadenceHook struct {
 	MainLogger *zerolog.Logger
+	// CurrentID optionally returns the log field and the ID of the
+	// transaction or script being executed, to tag its logs with.
+	CurrentID func() (string, string)
 }
 
 func (h CadenceHook) Run(_ *zerolog.Event, level zerolog.Level, msg string) {
 	const logPrefix = "Cadence log:"
 	if level != zerolog.NoLevel && strings.HasPrefix(msg, logPrefix) {
-		h.MainLogger.Info().Msg(
+		event := h.MainLogger.Info()
+		if h.CurrentID != nil {
+			if field, id := h.CurrentID(); id != "" {
+				event = event.Str(field, id)
+			}
+		}
+		event.Msg(
 			strings.Replace(msg,
 				logPrefix,
 				aurora.Colorize("LOG:", aurora.BlueFg|aurora.BoldFm).String(),
@@ -530,16 +1115,24 @@ func (h CadenceHook) Run(_ *zerolog.Event, level zerolog.Level, msg string) {
 	}
 }
 
+// newCadenceLogger returns the FVM logger, forwarding Cadence logs to the server logger.
+func newCadenceLogger(conf config, serverLogger zerolog.Logger, currentID func() (string, string)) zerolog.Logger {
+	return conf.Logger.Hook(CadenceHook{
+		MainLogger: &serverLogger,
+		CurrentID:  currentID,
+	}).Level(zerolog.DebugLevel)
+}
+
 func configureFVM(blockchain *Blockchain, conf config, blocks *blocks) (*fvm.VirtualMachine, fvm.Context, error) {
 	vm := fvm.NewVirtualMachine()
 
-	cadenceLogger := conf.Logger.Hook(CadenceHook{MainLogger: &conf.ServerLogger}).Level(zerolog.DebugLevel)
+	cadenceLogger := newCadenceLogger(conf, conf.ServerLogger, nil)
 
 	config := runtime.Config{
 		Debugger:                     blockchain.debugger,
-		AccountLinkingEnabled:        true,
-		AttachmentsEnabled:           true,
-		CapabilityControllersEnabled: true,
+		AccountLinkingEnabled:        conf.AccountLinkingEnabled,
+		AttachmentsEnabled:           conf.AttachmentsEnabled,
+		CapabilityControllersEnabled: conf.CapabilityControllersEnabled,
 		CoverageReport:               conf.CoverageReport,
 	}
 	coverageReportedRuntime := &CoverageReportedRuntime{
@@ -548,9 +1141,11 @@ func configureFVM(blockchain *Blockchain, conf config, blocks *blocks) (*fvm.Vir
 		Environment:    runtime.NewBaseInterpreterEnvironment(config),
 	}
 	customRuntimePool := reusableRuntime.NewCustomReusableCadenceRuntimePool(
-		1,
+		transactionRuntimePoolSize,
 		config,
 		func(config runtime.Config) runtime.Runtime {
+			// the pool only constructs a runtime when it has none to reuse
+			blockchain.fvmStats.runtimePoolMiss()
 			return coverageReportedRuntime
 		},
 	)
@@ -566,6 +1161,16 @@ func configureFVM(blockchain *Blockchain, conf config, blocks *blocks) (*fvm.Vir
 		fvm.WithAccountStorageLimit(conf.StorageLimitEnabled),
 		fvm.WithTransactionFeesEnabled(conf.TransactionFeesEnabled),
 		fvm.WithReusableCadenceRuntimePool(customRuntimePool),
+		fvm.WithMetricsReporter(blockchain.fvmStats),
+	}
+
+	// ledger reads and Cadence interpretation are only traced with extensive tracing
+	if conf.Tracer != nil {
+		fvmOptions = append(
+			fvmOptions,
+			fvm.WithTracer(conf.Tracer),
+			fvm.WithExtensiveTracing(),
+		)
 	}
 
 	if !conf.TransactionValidationEnabled {
@@ -580,6 +1185,7 @@ func configureFVM(blockchain *Blockchain, conf config, blocks *blocks) (*fvm.Vir
 	)
 
 	blockchain.coverageReportedRuntime = coverageReportedRuntime
+	blockchain.runtimeConfig = config
 
 	return vm, ctx, nil
 }
@@ -597,6 +1203,10 @@ func configureLedger(
 	latestBlock, err := store.LatestBlock(context.Background())
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
+			if conf.ReadOnly {
+				return nil, nil, fmt.Errorf("read-only emulator cannot bootstrap empty storage")
+			}
+
 			// storage is empty, bootstrap new ledger state
 			return configureNewLedger(conf, store, vm, ctx)
 		}
@@ -605,7 +1215,13 @@ func configureLedger(
 		return nil, nil, err
 	}
 
-	// storage contains data, load state from storage
+	// storage contains data, refuse it if it was created with an incompatible configuration
+	err = checkStoreConfig(conf, store)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// load state from storage
 	return configureExistingLedger(&latestBlock, store)
 }
 
@@ -629,18 +1245,18 @@ func configureNewLedger(
 		return nil, nil, fmt.Errorf("failed to bootstrap execution state: %w", err)
 	}
 
+	err = saveStoreConfig(conf, store)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// commit the genesis block to storage
 	genesis := flowgo.Genesis(conf.GetChainID())
 
-	err = store.CommitBlock(
-		context.Background(),
-		*genesis,
-		nil,
-		nil,
-		nil,
-		genesisExecutionSnapshot,
-		nil,
-	)
+	err = store.CommitBlock(context.Background(), storage.BlockCommit{
+		Block:             *genesis,
+		ExecutionSnapshot: genesisExecutionSnapshot,
+	})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -715,17 +1331,10 @@ func configureBootstrapProcedure(conf config, flowAccountKey flowgo.AccountPubli
 	options = append(options,
 		fvm.WithInitialTokenSupply(supply),
 		fvm.WithRestrictedAccountCreationEnabled(false),
-		fvm.WithTransactionFee(fvm.DefaultTransactionFees),
+		fvm.WithTransactionFee(fvm.BootstrapProcedureFeeParameters(conf.FeeParameters)),
 		fvm.WithExecutionMemoryLimit(math.MaxUint32),
 		fvm.WithExecutionMemoryWeights(meter.DefaultMemoryWeights),
-		fvm.WithExecutionEffortWeights(map[common.ComputationKind]uint64{
-			common.ComputationKindStatement:          1569,
-			common.ComputationKindLoop:               1569,
-			common.ComputationKindFunctionInvocation: 1569,
-			environment.ComputationKindGetValue:      808,
-			environment.ComputationKindCreateAccount: 2837670,
-			environment.ComputationKindSetValue:      765,
-		}),
+		fvm.WithExecutionEffortWeights(conf.ExecutionEffortWeights),
 	)
 	if conf.StorageLimitEnabled {
 		options = append(options,
@@ -741,36 +1350,72 @@ func configureBootstrapProcedure(conf config, flowAccountKey flowgo.AccountPubli
 }
 
 func configureTransactionValidator(conf config, blocks *blocks) *access.TransactionValidator {
+	expiry, expiryBuffer := conf.TransactionExpiry, conf.TransactionExpiryBuffer
+	if expiry == 0 {
+		// transactions never expire
+		expiry, expiryBuffer = math.MaxUint, 0
+	}
+
 	return access.NewTransactionValidator(
 		blocks,
 		conf.GetChainID().Chain(),
 		access.TransactionValidationOptions{
-			Expiry:                       conf.TransactionExpiry,
-			ExpiryBuffer:                 0,
-			AllowEmptyReferenceBlockID:   conf.TransactionExpiry == 0,
+			Expiry:                       expiry,
+			ExpiryBuffer:                 expiryBuffer,
+			AllowEmptyReferenceBlockID:   !conf.ReferenceBlockRequired,
 			AllowUnknownReferenceBlockID: false,
 			MaxGasLimit:                  conf.TransactionMaxGasLimit,
 			CheckScriptsParse:            true,
-			MaxTransactionByteSize:       flowgo.DefaultMaxTransactionByteSize,
-			MaxCollectionByteSize:        flowgo.DefaultMaxCollectionByteSize,
+			MaxTransactionByteSize:       conf.TransactionMaxByteSize,
+			MaxCollectionByteSize:        maxCollectionByteSize(conf.TransactionMaxByteSize),
 		},
 	)
 }
 
-func (b *Blockchain) newFVMContextFromHeader(header *flowgo.Header) fvm.Context {
-	return fvm.NewContextFromParent(
+// maxCollectionByteSize returns the maximum size of collections, which must
+// hold a transaction of the maximum size.
+func maxCollectionByteSize(transactionMaxByteSize uint64) uint64 {
+	if transactionMaxByteSize < flowgo.DefaultMaxCollectionByteSize {
+		return flowgo.DefaultMaxCollectionByteSize
+	}
+	return transactionMaxByteSize + 1
+}
+
+func (b *Blockchain) newFVMContextFromHeader(header *flowgo.Header) fvm.Context {
+	return fvm.NewContextFromParent(
 		b.vmCtx,
 		fvm.WithBlockHeader(header),
 	)
 }
 
-func (b *Blockchain) CurrentScript() (string, string) {
-	return b.currentScriptID, b.currentCode
+// RunningCode returns the code of the transaction or script with the given ID,
+// if it is being executed.
+func (b *Blockchain) RunningCode(id string) (string, bool) {
+	code, ok := b.running.Load(id)
+	if !ok {
+		return "", false
+	}
+	return code.(string), true
+}
+
+// executionLogger returns the FVM logger of an execution, which tags the Cadence logs
+// with the ID of the transaction or script, under the given log field.
+func (b *Blockchain) executionLogger(serverLogger zerolog.Logger, field string, id string) zerolog.Logger {
+	return newCadenceLogger(b.conf, serverLogger, func() (string, string) {
+		return field, id
+	})
+}
+
+func (b *Blockchain) addSourceFile(location common.Location, sourceFile string) {
+	b.sourceFileMu.Lock()
+	defer b.sourceFileMu.Unlock()
+
+	b.sourceFileMap[location] = sourceFile
 }
 
 // ServiceKey returns the service private key for this emulator.
 func (b *Blockchain) ServiceKey() ServiceKey {
-	serviceAccount, err := b.getAccount(flowgo.Address(b.serviceKey.Address))
+	serviceAccount, err := b.getAccount(context.Background(), flowgo.Address(b.serviceKey.Address))
 	if err != nil {
 		return b.serviceKey
 	}
@@ -800,14 +1445,14 @@ func (b *Blockchain) PendingBlockTimestamp() time.Time {
 }
 
 // GetLatestBlock gets the latest sealed block.
-func (b *Blockchain) GetLatestBlock() (*flowgo.Block, error) {
+func (b *Blockchain) GetLatestBlock(ctx context.Context) (*flowgo.Block, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	return b.getLatestBlock()
+	return b.getLatestBlock(ctx)
 }
 
-func (b *Blockchain) getLatestBlock() (*flowgo.Block, error) {
-	block, err := b.storage.LatestBlock(context.Background())
+func (b *Blockchain) getLatestBlock(ctx context.Context) (*flowgo.Block, error) {
+	block, err := b.storage.LatestBlock(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -816,14 +1461,14 @@ func (b *Blockchain) getLatestBlock() (*flowgo.Block, error) {
 }
 
 // GetBlockByID gets a block by ID.
-func (b *Blockchain) GetBlockByID(id flowgo.Identifier) (*flowgo.Block, error) {
+func (b *Blockchain) GetBlockByID(ctx context.Context, id flowgo.Identifier) (*flowgo.Block, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	return b.getBlockByID(id)
+	return b.getBlockByID(ctx, id)
 }
 
-func (b *Blockchain) getBlockByID(id flowgo.Identifier) (*flowgo.Block, error) {
-	block, err := b.storage.BlockByID(context.Background(), id)
+func (b *Blockchain) getBlockByID(ctx context.Context, id flowgo.Identifier) (*flowgo.Block, error) {
+	block, err := b.storage.BlockByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return nil, &types.BlockNotFoundByIDError{ID: id}
@@ -836,11 +1481,11 @@ func (b *Blockchain) getBlockByID(id flowgo.Identifier) (*flowgo.Block, error) {
 }
 
 // GetBlockByHeight gets a block by height.
-func (b *Blockchain) GetBlockByHeight(height uint64) (*flowgo.Block, error) {
+func (b *Blockchain) GetBlockByHeight(ctx context.Context, height uint64) (*flowgo.Block, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	block, err := b.getBlockByHeight(height)
+	block, err := b.getBlockByHeight(ctx, height)
 	if err != nil {
 		return nil, err
 	}
@@ -848,8 +1493,38 @@ func (b *Blockchain) GetBlockByHeight(height uint64) (*flowgo.Block, error) {
 	return block, nil
 }
 
-func (b *Blockchain) getBlockByHeight(height uint64) (*flowgo.Block, error) {
-	block, err := b.storage.BlockByHeight(context.Background(), height)
+// GetBlocksByHeightRange gets the blocks from the start to the end height, inclusive,
+// reading them from storage in a batch.
+func (b *Blockchain) GetBlocksByHeightRange(ctx context.Context, startHeight, endHeight uint64) ([]*flowgo.Block, error) {
+	if startHeight > endHeight {
+		return nil, types.NewInvalidArgumentError(fmt.Sprintf(
+			"start height %d is after end height %d",
+			startHeight,
+			endHeight,
+		))
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	latestHeight, err := b.storage.LatestBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if endHeight > latestHeight {
+		return nil, &types.BlockNotFoundByHeightError{Height: endHeight}
+	}
+
+	heights := make([]uint64, 0, endHeight-startHeight+1)
+	for height := startHeight; height <= endHeight; height++ {
+		heights = append(heights, height)
+	}
+
+	return b.storage.GetBlocks(ctx, heights)
+}
+
+func (b *Blockchain) getBlockByHeight(ctx context.Context, height uint64) (*flowgo.Block, error) {
+	block, err := b.storage.BlockByHeight(ctx, height)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return nil, &types.BlockNotFoundByHeightError{Height: height}
@@ -860,14 +1535,14 @@ func (b *Blockchain) getBlockByHeight(height uint64) (*flowgo.Block, error) {
 	return block, nil
 }
 
-func (b *Blockchain) GetCollectionByID(colID flowgo.Identifier) (*flowgo.LightCollection, error) {
+func (b *Blockchain) GetCollectionByID(ctx context.Context, colID flowgo.Identifier) (*flowgo.LightCollection, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	return b.getCollectionByID(colID)
+	return b.getCollectionByID(ctx, colID)
 }
 
-func (b *Blockchain) getCollectionByID(colID flowgo.Identifier) (*flowgo.LightCollection, error) {
-	col, err := b.storage.CollectionByID(context.Background(), colID)
+func (b *Blockchain) getCollectionByID(ctx context.Context, colID flowgo.Identifier) (*flowgo.LightCollection, error) {
+	col, err := b.storage.CollectionByID(ctx, colID)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return nil, &types.CollectionNotFoundError{ID: colID}
@@ -881,19 +1556,24 @@ func (b *Blockchain) getCollectionByID(colID flowgo.Identifier) (*flowgo.LightCo
 // GetTransaction gets an existing transaction by ID.
 //
 // The function first looks in the pending block, then the current emulator state.
-func (b *Blockchain) GetTransaction(txID flowgo.Identifier) (*flowgo.TransactionBody, error) {
+func (b *Blockchain) GetTransaction(ctx context.Context, txID flowgo.Identifier) (*flowgo.TransactionBody, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	return b.getTransaction(txID)
+	return b.getTransaction(ctx, txID)
 }
 
-func (b *Blockchain) getTransaction(txID flowgo.Identifier) (*flowgo.TransactionBody, error) {
+func (b *Blockchain) getTransaction(ctx context.Context, txID flowgo.Identifier) (*flowgo.TransactionBody, error) {
 	pendingTx := b.pendingBlock.GetTransaction(txID)
 	if pendingTx != nil {
 		return pendingTx, nil
 	}
 
-	tx, err := b.storage.TransactionByID(context.Background(), txID)
+	scheduledTx := b.getScheduledTransaction(txID)
+	if scheduledTx != nil {
+		return scheduledTx, nil
+	}
+
+	tx, err := b.storage.TransactionByID(ctx, txID)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return nil, &types.TransactionNotFoundError{ID: txID}
@@ -904,21 +1584,21 @@ func (b *Blockchain) getTransaction(txID flowgo.Identifier) (*flowgo.Transaction
 	return &tx, nil
 }
 
-func (b *Blockchain) GetTransactionResult(txID flowgo.Identifier) (*access.TransactionResult, error) {
+func (b *Blockchain) GetTransactionResult(ctx context.Context, txID flowgo.Identifier) (*access.TransactionResult, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	return b.getTransactionResult(txID)
+	return b.getTransactionResult(ctx, txID)
 }
 
-func (b *Blockchain) getTransactionResult(txID flowgo.Identifier) (*access.TransactionResult, error) {
-	if b.pendingBlock.ContainsTransaction(txID) {
+func (b *Blockchain) getTransactionResult(ctx context.Context, txID flowgo.Identifier) (*access.TransactionResult, error) {
+	if b.pendingBlock.ContainsTransaction(txID) || b.getScheduledTransaction(txID) != nil {
 		return &access.TransactionResult{
 			Status: flowgo.TransactionStatusPending,
 		}, nil
 	}
 
-	storedResult, err := b.storage.TransactionResultByID(context.Background(), txID)
+	storedResult, err := b.storage.TransactionResultByID(ctx, txID)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return &access.TransactionResult{
@@ -928,6 +1608,15 @@ func (b *Blockchain) getTransactionResult(txID flowgo.Identifier) (*access.Trans
 		return nil, err
 	}
 
+	return b.sealedTransactionResult(ctx, txID, storedResult)
+}
+
+// sealedTransactionResult converts the stored result of a committed transaction.
+func (b *Blockchain) sealedTransactionResult(
+	ctx context.Context,
+	txID flowgo.Identifier,
+	storedResult types.StorableTransactionResult,
+) (*access.TransactionResult, error) {
 	statusCode := 0
 	if storedResult.ErrorCode > 0 {
 		statusCode = 1
@@ -935,24 +1624,39 @@ func (b *Blockchain) getTransactionResult(txID flowgo.Identifier) (*access.Trans
 	result := access.TransactionResult{
 		Status:        flowgo.TransactionStatusSealed,
 		StatusCode:    uint(statusCode),
-		ErrorMessage:  storedResult.ErrorMessage,
+		ErrorMessage:  types.TruncateErrorMessage(storedResult.ErrorMessage, b.conf.ErrorMessageMaxLength),
 		Events:        storedResult.Events,
 		TransactionID: txID,
+		CollectionID:  storedResult.CollectionID,
 		BlockHeight:   storedResult.BlockHeight,
 		BlockID:       storedResult.BlockID,
 	}
 
+	// results stored by earlier versions do not record their collection
+	if result.CollectionID == flowgo.ZeroID && storedResult.BlockID != flowgo.ZeroID {
+		block, err := b.storage.BlockByID(ctx, storedResult.BlockID)
+		if err != nil {
+			return nil, err
+		}
+		if len(block.Payload.Guarantees) > 0 {
+			result.CollectionID = block.Payload.Guarantees[0].CollectionID
+		}
+	}
+
 	return &result, nil
 }
 
 // GetAccountByIndex returns the account for the given address.
-func (b *Blockchain) GetAccountByIndex(index uint) (*flowgo.Account, error) {
+func (b *Blockchain) GetAccountByIndex(ctx context.Context, index uint) (*flowgo.Account, error) {
 
 	generator := flowsdk.NewAddressGenerator(flowsdk.ChainID(b.vmCtx.Chain.ChainID()))
 
 	generator.SetIndex(index)
 
-	account, err := b.GetAccountUnsafe(convert.SDKAddressToFlow(generator.Address()))
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	account, err := b.getAccount(ctx, convert.SDKAddressToFlow(generator.Address()))
 	if err != nil {
 		return nil, err
 	}
@@ -963,35 +1667,31 @@ func (b *Blockchain) GetAccountByIndex(index uint) (*flowgo.Account, error) {
 // Deprecated: Needed for the debugger right now, do NOT use for other purposes.
 // TODO: refactor
 func (b *Blockchain) GetAccountUnsafe(address flowgo.Address) (*flowgo.Account, error) {
-	latestBlock, err := b.getLatestBlock()
-	if err != nil {
-		return nil, err
-	}
-	return b.getAccountAtBlock(address, latestBlock.Header.Height)
+	return b.getAccount(context.Background(), address)
 }
 
 // GetAccount returns the account for the given address.
-func (b *Blockchain) GetAccount(address flowgo.Address) (*flowgo.Account, error) {
+func (b *Blockchain) GetAccount(ctx context.Context, address flowgo.Address) (*flowgo.Account, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	return b.getAccount(address)
+	return b.getAccount(ctx, address)
 }
 
 // getAccount returns the account for the given address.
-func (b *Blockchain) getAccount(address flowgo.Address) (*flowgo.Account, error) {
-	latestBlock, err := b.getLatestBlock()
+func (b *Blockchain) getAccount(ctx context.Context, address flowgo.Address) (*flowgo.Account, error) {
+	latestBlock, err := b.getLatestBlock(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return b.getAccountAtBlock(address, latestBlock.Header.Height)
+	return b.getAccountAtBlock(ctx, address, latestBlock.Header.Height)
 }
 
 // GetAccountAtBlockHeight  returns the account for the given address at specified block height.
-func (b *Blockchain) GetAccountAtBlockHeight(address flowgo.Address, blockHeight uint64) (*flowgo.Account, error) {
+func (b *Blockchain) GetAccountAtBlockHeight(ctx context.Context, address flowgo.Address, blockHeight uint64) (*flowgo.Account, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	account, err := b.getAccountAtBlock(address, blockHeight)
+	account, err := b.getAccountAtBlock(ctx, address, blockHeight)
 	if err != nil {
 		return nil, err
 	}
@@ -999,13 +1699,65 @@ func (b *Blockchain) GetAccountAtBlockHeight(address flowgo.Address, blockHeight
 	return account, nil
 }
 
+// GetAccountAtPendingBlock returns the account for the given address as it is
+// once the transactions of the pending block are executed.
+func (b *Blockchain) GetAccountAtPendingBlock(ctx context.Context, address flowgo.Address) (*flowgo.Account, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	_, ledger, err := b.pendingLedger()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.getAccountOnLedger(address, ledger)
+}
+
+// GetRegisterValues returns the values of the given registers at the given block height.
+// The value of a register which is not set is empty.
+func (b *Blockchain) GetRegisterValues(
+	ctx context.Context,
+	registerIDs flowgo.RegisterIDs,
+	height uint64,
+) ([]flowgo.RegisterValue, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// the ledger of a height not committed yet is the latest one
+	_, err := b.getBlockByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger, err := b.ledgerByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]flowgo.RegisterValue, len(registerIDs))
+	for i, id := range registerIDs {
+		values[i], err = ledger.Get(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
 // GetAccountAtBlock returns the account for the given address at specified block height.
-func (b *Blockchain) getAccountAtBlock(address flowgo.Address, blockHeight uint64) (*flowgo.Account, error) {
-	ledger, err := b.storage.LedgerByHeight(context.Background(), blockHeight)
+func (b *Blockchain) getAccountAtBlock(ctx context.Context, address flowgo.Address, blockHeight uint64) (*flowgo.Account, error) {
+	ledger, err := b.ledgerByHeight(ctx, blockHeight)
 	if err != nil {
 		return nil, err
 	}
 
+	return b.getAccountOnLedger(address, ledger)
+}
+
+// getAccountOnLedger returns the account for the given address in the given ledger.
+func (b *Blockchain) getAccountOnLedger(address flowgo.Address, ledger snapshot.StorageSnapshot) (*flowgo.Account, error) {
+	b.fvmStats.ledgerViewCreated()
 	account, err := b.vm.GetAccount(b.vmCtx, address, ledger)
 	if fvmerrors.IsAccountNotFoundError(err) {
 		return nil, &types.AccountNotFoundError{Address: address}
@@ -1014,16 +1766,20 @@ func (b *Blockchain) getAccountAtBlock(address flowgo.Address, blockHeight uint6
 	return account, nil
 }
 
-func (b *Blockchain) GetEventsForBlockIDs(eventType string, blockIDs []flowgo.Identifier) (result []flowgo.BlockEvents, err error) {
+// GetEventsForBlockIDs returns the events in the given blocks, optionally filtered by type.
+//
+// Blocks are returned in the requested order, and the events of each block in
+// canonical order: by transaction index, then by event index.
+func (b *Blockchain) GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []flowgo.Identifier) (result []flowgo.BlockEvents, err error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
 	for _, blockID := range blockIDs {
-		block, err := b.storage.BlockByID(context.Background(), blockID)
+		block, err := b.storage.BlockByID(ctx, blockID)
 		if err != nil {
 			break
 		}
-		events, err := b.storage.EventsByHeight(context.Background(), block.Header.Height, eventType)
+		events, err := b.storage.EventsByHeight(ctx, block.Header.Height, eventType)
 		if err != nil {
 			break
 		}
@@ -1038,80 +1794,234 @@ func (b *Blockchain) GetEventsForBlockIDs(eventType string, blockIDs []flowgo.Id
 	return result, err
 }
 
-func (b *Blockchain) GetEventsForHeightRange(eventType string, startHeight, endHeight uint64) (result []flowgo.BlockEvents, err error) {
+// GetEventsForHeightRange returns the events in the given range of blocks, optionally filtered by type.
+//
+// Blocks are returned by ascending height, and the events of each block in
+// canonical order: by transaction index, then by event index.
+func (b *Blockchain) GetEventsForHeightRange(ctx context.Context, eventType string, startHeight, endHeight uint64) (result []flowgo.BlockEvents, err error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	for blockHeight := startHeight; blockHeight <= endHeight; blockHeight++ {
-		block, err := b.storage.BlockByHeight(context.Background(), blockHeight)
-		if err != nil {
-			break
+	latestBlock, err := b.storage.LatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if endHeight > latestBlock.Header.Height {
+		endHeight = latestBlock.Header.Height
+	}
+	if startHeight > endHeight {
+		return nil, nil
+	}
+
+	// fetch the blocks in parallel, then return them up to the first block
+	// which could not be fetched
+	count := endHeight - startHeight + 1
+	blockEvents := make([]flowgo.BlockEvents, count)
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, eventsFetchConcurrency)
+
+	for i := uint64(0); i < count; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
 		}
 
-		events, err := b.storage.EventsByHeight(context.Background(), blockHeight, eventType)
-		if err != nil {
+		wg.Add(1)
+		go func(i uint64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			blockEvents[i], errs[i] = b.getEventsForHeight(ctx, startHeight+i, eventType)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i := range blockEvents {
+		if errs[i] != nil {
 			break
 		}
+		result = append(result, blockEvents[i])
+	}
 
-		result = append(result, flowgo.BlockEvents{
-			BlockID:        block.ID(),
-			BlockHeight:    block.Header.Height,
-			BlockTimestamp: block.Header.Timestamp,
-			Events:         events,
-		})
+	return result, nil
+}
+
+// eventsFetchConcurrency is the maximum number of blocks GetEventsForHeightRange
+// fetches concurrently.
+const eventsFetchConcurrency = 16
+
+func (b *Blockchain) getEventsForHeight(ctx context.Context, blockHeight uint64, eventType string) (flowgo.BlockEvents, error) {
+	block, err := b.storage.BlockByHeight(ctx, blockHeight)
+	if err != nil {
+		return flowgo.BlockEvents{}, err
 	}
 
-	return result, err
+	events, err := b.storage.EventsByHeight(ctx, blockHeight, eventType)
+	if err != nil {
+		return flowgo.BlockEvents{}, err
+	}
+
+	return flowgo.BlockEvents{
+		BlockID:        block.ID(),
+		BlockHeight:    block.Header.Height,
+		BlockTimestamp: block.Header.Timestamp,
+		Events:         events,
+	}, nil
 }
 
 // GetEventsByHeight returns the events in the block at the given height, optionally filtered by type.
-func (b *Blockchain) GetEventsByHeight(blockHeight uint64, eventType string) ([]flowgo.Event, error) {
+//
+// Events are returned in canonical order: by transaction index, then by event index.
+func (b *Blockchain) GetEventsByHeight(ctx context.Context, blockHeight uint64, eventType string) ([]flowgo.Event, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	return b.storage.EventsByHeight(context.Background(), blockHeight, eventType)
+	return b.storage.EventsByHeight(ctx, blockHeight, eventType)
 }
 
 // SendTransaction submits a transaction to the network.
-func (b *Blockchain) SendTransaction(flowTx *flowgo.TransactionBody) error {
+func (b *Blockchain) SendTransaction(ctx context.Context, flowTx *flowgo.TransactionBody) error {
+	return b.SendTransactionWithPriority(ctx, flowTx, 0)
+}
+
+// SendTransactionWithPriority submits a transaction to the network like SendTransaction.
+//
+// Pending transactions are ordered by priority, highest first, then in the order they
+// were submitted. Transactions submitted with SendTransaction have priority zero.
+func (b *Blockchain) SendTransactionWithPriority(ctx context.Context, flowTx *flowgo.TransactionBody, priority int) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	return b.sendTransaction(ctx, flowTx, priority, false)
+}
+
+// SendCheckedTransaction submits a transaction to the network like SendTransaction,
+// but rejects it right away if the sequence number of its proposal key is not the one
+// the next transaction proposed with the key must use, see CheckSequenceNumber.
+//
+// The sequence number is not checked if transaction validation is disabled.
+func (b *Blockchain) SendCheckedTransaction(ctx context.Context, flowTx *flowgo.TransactionBody) error {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	defer b.unlock()
 
-	err := b.addTransaction(*flowTx)
+	return b.sendTransaction(ctx, flowTx, 0, b.conf.TransactionValidationEnabled)
+}
+
+func (b *Blockchain) sendTransaction(
+	ctx context.Context,
+	flowTx *flowgo.TransactionBody,
+	priority int,
+	checkSequenceNumber bool,
+) error {
+	err := b.addTransaction(ctx, *flowTx, priority, checkSequenceNumber)
 	if err != nil {
 		return err
 	}
 
 	if b.conf.AutoMine {
-		_, _, err := b.executeAndCommitBlock()
-		if err != nil {
-			return err
+		if b.conf.ConsensusDelay > 0 {
+			b.scheduleDelayedCommit()
+			return nil
+		}
+
+		// transactions deferred over the block gas limit are committed in the following blocks
+		for !b.pendingBlock.Empty() {
+			_, _, err := b.executeAndCommitBlock()
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// scheduleDelayedCommit commits the pending block once the consensus delay
+// has passed. At most one commit is scheduled at a time, so transactions sent
+// in the meantime are included in the same block.
+func (b *Blockchain) scheduleDelayedCommit() {
+	if b.delayedCommitScheduled {
+		return
+	}
+	b.delayedCommitScheduled = true
+
+	time.AfterFunc(b.conf.ConsensusDelay, func() {
+		b.mu.Lock()
+		defer b.unlock()
+
+		b.delayedCommitScheduled = false
+
+		// the block may have been committed manually in the meantime
+		if b.pendingBlock.Empty() {
+			return
+		}
+
+		_, _, err := b.executeAndCommitBlock()
+		if err != nil {
+			b.conf.ServerLogger.Error().Err(err).Msg("Failed to commit delayed block")
+			return
+		}
+
+		// transactions deferred over the block gas limit wait for another delay
+		if !b.pendingBlock.Empty() {
+			b.scheduleDelayedCommit()
+		}
+	})
+}
+
 // AddTransaction validates a transaction and adds it to the current pending block.
-func (b *Blockchain) AddTransaction(tx flowgo.TransactionBody) error {
+func (b *Blockchain) AddTransaction(ctx context.Context, tx flowgo.TransactionBody) error {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	defer b.unlock()
 
-	return b.addTransaction(tx)
+	return b.addTransaction(ctx, tx, 0, false)
 }
 
-func (b *Blockchain) addTransaction(tx flowgo.TransactionBody) error {
+func (b *Blockchain) addTransaction(
+	ctx context.Context,
+	tx flowgo.TransactionBody,
+	priority int,
+	checkSequenceNumber bool,
+) error {
+	if b.conf.ReadOnly {
+		return &types.ReadOnlyError{}
+	}
 
 	// If index > 0, pending block has begun execution (cannot add more transactions)
 	if b.pendingBlock.ExecutionStarted() {
 		return &types.PendingBlockMidExecutionError{BlockID: b.pendingBlock.ID()}
 	}
 
-	if b.pendingBlock.ContainsTransaction(tx.ID()) {
+	err := b.validateNewTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if checkSequenceNumber {
+		err = b.checkSequenceNumber(ctx, &tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// add transaction to pending block
+	b.pendingBlock.AddTransaction(tx, requestid.FromContext(ctx), priority)
+
+	return nil
+}
+
+// validateNewTransaction validates a transaction which was not submitted before.
+func (b *Blockchain) validateNewTransaction(ctx context.Context, tx flowgo.TransactionBody) error {
+	if b.pendingBlock.ContainsTransaction(tx.ID()) || b.getScheduledTransaction(tx.ID()) != nil {
 		return &types.DuplicateTransactionError{TxID: tx.ID()}
 	}
 
-	_, err := b.storage.TransactionByID(context.Background(), tx.ID())
+	_, err := b.storage.TransactionByID(ctx, tx.ID())
 	if err == nil {
 		// Found the transaction, this is a duplicate
 		return &types.DuplicateTransactionError{TxID: tx.ID()}
@@ -1125,16 +2035,74 @@ func (b *Blockchain) addTransaction(tx flowgo.TransactionBody) error {
 		return types.ConvertAccessError(err)
 	}
 
-	// add transaction to pending block
-	b.pendingBlock.AddTransaction(tx)
+	return nil
+}
+
+// CheckSequenceNumber checks that the sequence number of the proposal key of a
+// transaction is the one the next transaction proposed with the key must use,
+// counting the transactions proposed with the key which are still pending or scheduled.
+//
+// Transactions with unknown proposers or keys are not checked, they fail
+// when executed.
+func (b *Blockchain) CheckSequenceNumber(ctx context.Context, tx *flowgo.TransactionBody) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.checkSequenceNumber(ctx, tx)
+}
+
+func (b *Blockchain) checkSequenceNumber(ctx context.Context, tx *flowgo.TransactionBody) error {
+	proposalKey := tx.ProposalKey
+
+	account, err := b.getAccount(ctx, proposalKey.Address)
+	if err != nil {
+		var notFoundErr *types.AccountNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil
+		}
+		return err
+	}
+	if account == nil || proposalKey.KeyIndex >= uint64(len(account.Keys)) {
+		return nil
+	}
+
+	expected := account.Keys[proposalKey.KeyIndex].SeqNumber
+	for _, pendingTx := range b.pendingBlock.Transactions() {
+		if sameProposalKey(pendingTx.ProposalKey, proposalKey) {
+			expected++
+		}
+	}
+	for _, deferred := range b.pendingBlock.Deferred() {
+		if sameProposalKey(deferred.tx.ProposalKey, proposalKey) {
+			expected++
+		}
+	}
+	for _, scheduled := range b.scheduled {
+		if sameProposalKey(scheduled.tx.ProposalKey, proposalKey) {
+			expected++
+		}
+	}
+
+	if proposalKey.SequenceNumber != expected {
+		return &types.InvalidSequenceNumberError{
+			Address:  proposalKey.Address,
+			KeyIndex: proposalKey.KeyIndex,
+			Expected: expected,
+			Provided: proposalKey.SequenceNumber,
+		}
+	}
 
 	return nil
 }
 
+func sameProposalKey(a, b flowgo.ProposalKey) bool {
+	return a.Address == b.Address && a.KeyIndex == b.KeyIndex
+}
+
 // ExecuteBlock executes the remaining transactions in pending block.
 func (b *Blockchain) ExecuteBlock() ([]*types.TransactionResult, error) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	defer b.unlock()
 
 	return b.executeBlock()
 }
@@ -1173,7 +2141,7 @@ func (b *Blockchain) executeBlock() ([]*types.TransactionResult, error) {
 // ExecuteNextTransaction executes the next indexed transaction in pending block.
 func (b *Blockchain) ExecuteNextTransaction() (*types.TransactionResult, error) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	defer b.unlock()
 
 	header := b.pendingBlock.Block().Header
 	blockContext := b.newFVMContextFromHeader(header)
@@ -1190,20 +2158,61 @@ func (b *Blockchain) executeNextTransaction(ctx fvm.Context) (*types.Transaction
 		}
 	}
 
+	if !b.pendingBlock.ExecutionStarted() && b.conf.BlockGasLimit > 0 {
+		deferred := b.pendingBlock.DeferOverGasLimit(b.conf.BlockGasLimit)
+		if deferred > 0 {
+			b.conf.ServerLogger.Debug().Msgf(
+				"⏭️  %d transactions exceeding the block gas limit deferred to the next block",
+				deferred,
+			)
+		}
+	}
+
 	txnBody := b.pendingBlock.NextTransaction()
 	txnId := txnBody.ID()
 
-	b.currentCode = string(txnBody.Script)
-	b.currentScriptID = txnId.String()
+	code := string(txnBody.Script)
+	b.running.Store(txnId.String(), code)
+	defer b.running.Delete(txnId.String())
 
-	pragmas := ExtractPragmas(b.currentCode)
+	pragmas := ExtractPragmas(code)
 
 	if b.activeDebuggingSession && pragmas.Contains(PragmaDebug) {
 		b.debugger.RequestPause()
 	}
 
-	// use the computer to execute the next transaction
-	output, err := b.pendingBlock.ExecuteNextTransaction(b.vm, ctx)
+	// tag the logs of the execution with the transaction, and the request which submitted it
+	serverLogger := b.conf.ServerLogger
+	requestID := b.pendingBlock.RequestID(txnId)
+	if requestID != "" {
+		serverLogger = serverLogger.With().Str(requestid.LogField, requestID).Logger()
+	}
+	ctx = fvm.NewContextFromParent(ctx, fvm.WithLogger(b.executionLogger(serverLogger, "txID", txnId.String())))
+
+	ctx, span := b.startExecutionSpan(
+		context.Background(),
+		ctx,
+		SpanExecuteTransaction,
+		attribute.String("transaction.id", txnId.String()),
+		attribute.Int64("block.height", int64(b.pendingBlock.height)),
+	)
+	defer span.End()
+
+	var output fvm.ProcedureOutput
+	var err error
+	fault := b.faults.match(&txnId, txnBody.Script)
+	if fault != nil {
+		// the transaction body is not executed, the transaction only fails with the fault
+		faultErr := faultError(fault)
+		b.fvmStats.ledgerViewCreated()
+		output, err = b.pendingBlock.SkipNextTransaction(b.vm, b.faultedContext(ctx, faultErr), faultErr)
+	} else {
+		// use the computer to execute the next transaction
+		b.fvmStats.transactionExecuted()
+		b.fvmStats.ledgerViewCreated()
+
+		output, err = b.pendingBlock.ExecuteNextTransaction(b.vm, ctx)
+	}
 	if err != nil {
 		// fail fast if fatal error occurs
 		return nil, err
@@ -1215,6 +2224,8 @@ func (b *Blockchain) executeNextTransaction(ctx fvm.Context) (*types.Transaction
 		return nil, err
 	}
 
+	tr.RequestID = requestID
+
 	// if transaction error exist try to further debug what was the problem
 	if tr.Error != nil {
 		tr.Debug = b.debugSignatureError(tr.Error, txnBody)
@@ -1224,9 +2235,23 @@ func (b *Blockchain) executeNextTransaction(ctx fvm.Context) (*types.Transaction
 	if pragmas.Contains(PragmaSourceFile) {
 		location := common.NewTransactionLocation(nil, tr.TransactionID.Bytes())
 		sourceFile := pragmas.FilterByName(PragmaSourceFile).First().Argument()
-		b.sourceFileMap[location] = sourceFile
+		b.addSourceFile(location, sourceFile)
+	}
+
+	if fault == nil {
+		b.profiler.record(txnBody.Script, output.ComputationUsed, output.ComputationIntensities)
+	}
+
+	if b.conf.ComputationReportingEnabled {
+		b.computationReports.set(txnId, &ComputationReport{
+			TransactionID:   txnId,
+			ComputationUsed: tr.ComputationUsed,
+			Intensities:     tr.ComputationIntensities,
+		})
 	}
 
+	b.subscriptions.notifyTransactionExecuted(txnBody, tr)
+
 	return tr, nil
 }
 
@@ -1235,17 +2260,16 @@ func (b *Blockchain) executeNextTransaction(ctx fvm.Context) (*types.Transaction
 // This function clears the pending transaction pool and resets the pending block.
 func (b *Blockchain) CommitBlock() (*flowgo.Block, error) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	block, err := b.commitBlock()
-	if err != nil {
-		return nil, err
-	}
+	defer b.unlock()
 
-	return block, nil
+	return b.commitBlock()
 }
 
 func (b *Blockchain) commitBlock() (*flowgo.Block, error) {
+	if b.conf.ReadOnly {
+		return nil, &types.ReadOnlyError{}
+	}
+
 	// pending block cannot be committed before execution starts (unless empty)
 	if !b.pendingBlock.ExecutionStarted() && !b.pendingBlock.Empty() {
 		return nil, &types.PendingBlockCommitBeforeExecutionError{BlockID: b.pendingBlock.ID()}
@@ -1259,22 +2283,29 @@ func (b *Blockchain) commitBlock() (*flowgo.Block, error) {
 	block := b.pendingBlock.Block()
 	collections := b.pendingBlock.Collections()
 	transactions := b.pendingBlock.Transactions()
-	transactionResults, err := convertToSealedResults(b.pendingBlock.TransactionResults(), b.pendingBlock.ID(), b.pendingBlock.height)
+	transactionResults, err := convertToSealedResults(
+		b.pendingBlock.TransactionResults(),
+		collections,
+		b.pendingBlock.ID(),
+		b.pendingBlock.height,
+	)
 	if err != nil {
 		return nil, err
 	}
 	executionSnapshot := b.pendingBlock.Finalize()
 	events := b.pendingBlock.Events()
+	dependencies := b.pendingBlock.Dependencies()
+	deferred := b.pendingBlock.Deferred()
 
 	// commit the pending block to storage
-	err = b.storage.CommitBlock(
-		context.Background(),
-		*block,
-		collections,
-		transactions,
-		transactionResults,
-		executionSnapshot,
-		events)
+	err = b.storage.CommitBlock(context.Background(), storage.BlockCommit{
+		Block:              *block,
+		Collections:        collections,
+		Transactions:       transactions,
+		TransactionResults: transactionResults,
+		ExecutionSnapshot:  executionSnapshot,
+		Events:             events,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1287,8 +2318,29 @@ func (b *Blockchain) commitBlock() (*flowgo.Block, error) {
 		return nil, err
 	}
 
+	b.dependencies.set(block.ID(), dependencies)
+
+	b.autoSnapshot(block.Header.Height)
+
+	b.pruneState(block.Header.Height)
+
 	// reset pending block using current block and ledger state
-	b.pendingBlock = newPendingBlock(block, ledger, b.clock)
+	b.pendingBlock = b.newPendingBlock(block, ledger)
+
+	for _, transaction := range deferred {
+		b.pendingBlock.AddTransaction(transaction.tx, transaction.requestID, transaction.priority)
+	}
+
+	// the scheduled transactions added to the committed block are executed
+	b.includedScheduled = nil
+	b.includeScheduledTransactions()
+
+	b.subscriptions.notifyBlockCommitted(BlockEvent{
+		Block:  block,
+		Events: events,
+	})
+
+	b.checkEventExpectations(block, events)
 
 	return block, nil
 }
@@ -1296,7 +2348,7 @@ func (b *Blockchain) commitBlock() (*flowgo.Block, error) {
 // ExecuteAndCommitBlock is a utility that combines ExecuteBlock with CommitBlock.
 func (b *Blockchain) ExecuteAndCommitBlock() (*flowgo.Block, []*types.TransactionResult, error) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	defer b.unlock()
 
 	return b.executeAndCommitBlock()
 }
@@ -1327,10 +2379,49 @@ func (b *Blockchain) executeAndCommitBlock() (*flowgo.Block, []*types.Transactio
 	return block, results, nil
 }
 
+// ExecuteAndCommitBlockAt executes the pending block and commits it with the given timestamp,
+// which must not be before the timestamp of the latest block.
+//
+// If the timestamp is ahead of the clock of the emulator, the clock is moved forward to it,
+// so following blocks have later timestamps.
+func (b *Blockchain) ExecuteAndCommitBlockAt(timestamp time.Time) (*flowgo.Block, []*types.TransactionResult, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	// executed transactions already observed the timestamp of the pending block
+	if b.pendingBlock.ExecutionStarted() {
+		return nil, nil, &types.PendingBlockMidExecutionError{BlockID: b.pendingBlock.ID()}
+	}
+
+	latestBlock, err := b.getLatestBlock(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timestamp = timestamp.UTC()
+	if timestamp.Before(latestBlock.Header.Timestamp) {
+		return nil, nil, types.NewInvalidArgumentError(
+			fmt.Sprintf(
+				"block timestamp %s is before the timestamp of the latest block %s",
+				timestamp.Format(time.RFC3339Nano),
+				latestBlock.Header.Timestamp.Format(time.RFC3339Nano),
+			),
+		)
+	}
+
+	if timestamp.After(b.clock.Now()) {
+		b.setClock(offsetClockAt(b.clock, timestamp))
+	}
+	b.pendingBlock.timestamp = timestamp
+	b.pendingBlock.resetPendingSnapshot()
+
+	return b.executeAndCommitBlock()
+}
+
 // ResetPendingBlock clears the transactions in pending block.
 func (b *Blockchain) ResetPendingBlock() error {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	defer b.unlock()
 
 	latestBlock, err := b.storage.LatestBlock(context.Background())
 	if err != nil {
@@ -1346,70 +2437,169 @@ func (b *Blockchain) ResetPendingBlock() error {
 	}
 
 	// reset pending block using latest committed block and ledger state
-	b.pendingBlock = newPendingBlock(&latestBlock, latestLedger, b.clock)
+	b.pendingBlock = b.newPendingBlock(&latestBlock, latestLedger)
+	b.includedScheduled = nil
 
 	return nil
 }
 
 // ExecuteScript executes a read-only script against the world state and returns the result.
 func (b *Blockchain) ExecuteScript(
+	ctx context.Context,
 	script []byte,
 	arguments [][]byte,
 ) (*types.ScriptResult, error) {
+	release, err := b.acquireScriptWorker(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	latestBlock, err := b.getLatestBlock()
+	latestBlock, err := b.getLatestBlock(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return b.executeScriptAtBlockID(script, arguments, latestBlock.Header.ID())
+	return b.executeScriptAtBlockID(ctx, script, arguments, latestBlock.Header.ID())
 }
 
-func (b *Blockchain) ExecuteScriptAtBlockID(script []byte, arguments [][]byte, id flowgo.Identifier) (*types.ScriptResult, error) {
+func (b *Blockchain) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, id flowgo.Identifier) (*types.ScriptResult, error) {
+	release, err := b.acquireScriptWorker(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	return b.executeScriptAtBlockID(script, arguments, id)
+	return b.executeScriptAtBlockID(ctx, script, arguments, id)
 }
 
-func (b *Blockchain) executeScriptAtBlockID(script []byte, arguments [][]byte, id flowgo.Identifier) (*types.ScriptResult, error) {
-	requestedBlock, err := b.storage.BlockByID(context.Background(), id)
+// acquireScriptWorker waits for a free script worker. It is acquired before
+// the emulator lock, so queued scripts do not hold back block commits.
+func (b *Blockchain) acquireScriptWorker(ctx context.Context, script []byte) (func(), error) {
+	release, err := b.scriptPool.acquire(ctx)
+	if err != nil {
+		return nil, &types.ScriptInterruptedError{
+			ScriptID: flowgo.MakeIDFromFingerPrint(script),
+			Err:      err,
+		}
+	}
+	return release, nil
+}
+
+func (b *Blockchain) executeScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, id flowgo.Identifier) (*types.ScriptResult, error) {
+	requestedBlock, err := b.storage.BlockByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	requestedLedgerSnapshot, err := b.storage.LedgerByHeight(
-		context.Background(),
-		requestedBlock.Header.Height,
-	)
+	requestedLedgerSnapshot, err := b.ledgerByHeight(ctx, requestedBlock.Header.Height)
 	if err != nil {
 		return nil, err
 	}
 
-	header := requestedBlock.Header
-	blockContext := b.newFVMContextFromHeader(header)
+	return b.executeScriptOnLedger(ctx, script, arguments, requestedBlock.Header, requestedLedgerSnapshot)
+}
+
+// executeScriptOnLedger executes a script in the context of the given block header, against the given ledger.
+func (b *Blockchain) executeScriptOnLedger(
+	ctx context.Context,
+	script []byte,
+	arguments [][]byte,
+	header *flowgo.Header,
+	requestedLedgerSnapshot snapshot.StorageSnapshot,
+) (*types.ScriptResult, error) {
+	// the caller may have given up while the block and ledger were loaded
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if fault := b.faults.match(nil, script); fault != nil {
+		scriptID := flowgo.MakeIDFromFingerPrint(script)
+		if fault.Outcome == FaultOutcomeTimeout {
+			return nil, &types.ScriptInterruptedError{
+				ScriptID: scriptID,
+				Err:      context.DeadlineExceeded,
+			}
+		}
+
+		err := faultError(fault)
+		return &types.ScriptResult{
+			ScriptID:  flowsdk.Identifier(scriptID),
+			Error:     convert.VMErrorToEmulator(err),
+			ErrorCode: types.ClassifyError(err),
+		}, nil
+	}
+
+	if b.conf.ScriptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.conf.ScriptTimeout)
+		defer cancel()
+	}
+
+	blockContext := fvm.NewContextFromParent(
+		b.newFVMContextFromHeader(header),
+		fvm.WithReusableCadenceRuntimePool(
+			newInterruptibleRuntimePool(ctx, b.runtimeConfig, b.coverageReportedRuntime),
+		),
+	)
 
 	scriptProc := fvm.Script(script).WithArguments(arguments...)
-	b.currentCode = string(script)
-	b.currentScriptID = scriptProc.ID.String()
 
-	pragmas := ExtractPragmas(b.currentCode)
+	// tag the logs of the execution with the script, and the request which runs it
+	serverLogger := b.conf.ServerLogger
+	if requestID := requestid.FromContext(ctx); requestID != "" {
+		serverLogger = serverLogger.With().Str(requestid.LogField, requestID).Logger()
+	}
+	blockContext = fvm.NewContextFromParent(
+		blockContext,
+		fvm.WithLogger(b.executionLogger(serverLogger, "scriptID", scriptProc.ID.String())),
+	)
+
+	blockContext, span := b.startExecutionSpan(
+		ctx,
+		blockContext,
+		SpanExecuteScript,
+		attribute.String("script.id", scriptProc.ID.String()),
+		attribute.Int64("block.height", int64(header.Height)),
+	)
+	defer span.End()
+
+	// identical scripts may run concurrently, the debugger reads the code of any of them
+	b.running.Store(scriptProc.ID.String(), string(script))
+	defer b.running.Delete(scriptProc.ID.String())
+
+	pragmas := ExtractPragmas(string(script))
 
 	if b.activeDebuggingSession && pragmas.Contains(PragmaDebug) {
 		b.debugger.RequestPause()
 	}
+	b.fvmStats.ledgerViewCreated()
 	_, output, err := b.vm.Run(
 		blockContext,
 		scriptProc,
 		requestedLedgerSnapshot)
+
+	scriptID := flowsdk.Identifier(flowgo.MakeIDFromFingerPrint(script))
+
+	// execution failed because the context ended, report the interruption
+	// rather than the error the runtime produced while unwinding
+	if ctxErr := ctx.Err(); ctxErr != nil && (err != nil || output.Err != nil) {
+		return nil, &types.ScriptInterruptedError{
+			ScriptID: flowgo.Identifier(scriptID),
+			Err:      ctxErr,
+		}
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	scriptID := flowsdk.Identifier(flowgo.MakeIDFromFingerPrint(script))
-
 	events, err := convert.FlowEventsToSDK(output.Events)
 	if err != nil {
 		return nil, err
@@ -1428,7 +2618,7 @@ func (b *Blockchain) executeScriptAtBlockID(script []byte, arguments [][]byte, i
 	if pragmas.Contains(PragmaSourceFile) {
 		location := common.NewScriptLocation(nil, scriptID.Bytes())
 		sourceFile := pragmas.FilterByName(PragmaSourceFile).First().Argument()
-		b.sourceFileMap[location] = sourceFile
+		b.addSourceFile(location, sourceFile)
 	}
 
 	return &types.ScriptResult{
@@ -1439,31 +2629,98 @@ func (b *Blockchain) executeScriptAtBlockID(script []byte, arguments [][]byte, i
 		Events:          events,
 		ComputationUsed: output.ComputationUsed,
 		MemoryEstimate:  output.MemoryEstimate,
+		ErrorCode:       types.ClassifyError(output.Err),
 	}, nil
 }
 
 func (b *Blockchain) ExecuteScriptAtBlockHeight(
+	ctx context.Context,
 	script []byte,
 	arguments [][]byte,
 	blockHeight uint64,
 ) (*types.ScriptResult, error) {
+	release, err := b.acquireScriptWorker(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	requestedBlock, err := b.getBlockByHeight(ctx, blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.executeScriptAtBlockID(ctx, script, arguments, requestedBlock.Header.ID())
+}
+
+// ExecuteScriptAtPendingBlock executes a read-only script against the world state
+// as it is once the transactions of the pending block are executed.
+func (b *Blockchain) ExecuteScriptAtPendingBlock(
+	ctx context.Context,
+	script []byte,
+	arguments [][]byte,
+) (*types.ScriptResult, error) {
+	release, err := b.acquireScriptWorker(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	requestedBlock, err := b.getBlockByHeight(blockHeight)
+	header, ledger, err := b.pendingLedger()
 	if err != nil {
 		return nil, err
 	}
 
-	return b.executeScriptAtBlockID(script, arguments, requestedBlock.Header.ID())
+	return b.executeScriptOnLedger(ctx, script, arguments, header, ledger)
+}
+
+// pendingLedger returns the header of the pending block and the ledger as it is
+// once its transactions are executed. The transactions not executed yet are
+// executed without logging, and are executed again when the block is. The
+// transactions the fault rules match are skipped, as they will be when executed.
+func (b *Blockchain) pendingLedger() (*flowgo.Header, snapshot.StorageSnapshot, error) {
+	header := b.pendingBlock.Block().Header
+	blockContext := fvm.NewContextFromParent(
+		b.newFVMContextFromHeader(header),
+		fvm.WithLogger(zerolog.Nop()),
+	)
+
+	// the faults are predicted without being counted
+	faults, faultsVersion := b.faults.clone()
+	faultedContext := b.faultedContext(
+		blockContext,
+		fvmerrors.NewCodedError(fvmerrors.ErrCodeExecutionError, "simulated execution failure"),
+	)
+
+	ledger, err := b.pendingBlock.PendingSnapshot(b.vm, blockContext, faultedContext, faults, faultsVersion, b.fvmStats)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return header, ledger, nil
 }
 
 func convertToSealedResults(
 	results map[flowgo.Identifier]IndexedTransactionResult,
+	collections []*flowgo.LightCollection,
 	blockID flowgo.Identifier,
 	blockHeight uint64,
 ) (map[flowgo.Identifier]*types.StorableTransactionResult, error) {
 
+	collectionIDs := make(map[flowgo.Identifier]flowgo.Identifier)
+	for _, collection := range collections {
+		collectionID := collection.ID()
+		for _, txID := range collection.Transactions {
+			collectionIDs[txID] = collectionID
+		}
+	}
+
 	output := make(map[flowgo.Identifier]*types.StorableTransactionResult)
 
 	for id, result := range results {
@@ -1471,6 +2728,10 @@ func convertToSealedResults(
 		if err != nil {
 			return nil, err
 		}
+		temp.CollectionID = collectionIDs[id]
+		temp.TransactionIndex = result.Index
+		temp.EmulatorErrorCode = types.ClassifyError(result.Err)
+		temp.RequestID = result.RequestID
 		output[id] = &temp
 	}
 
@@ -1502,7 +2763,7 @@ func (b *Blockchain) debugSignatureError(err error, tx *flowgo.TransactionBody)
 // testAlternativeHashAlgo tries to verify the signature with alternative hashing algorithm and if
 // the signature is verified returns more verbose error
 func (b *Blockchain) testAlternativeHashAlgo(sig flowgo.TransactionSignature, msg []byte) *types.TransactionResultDebug {
-	acc, err := b.getAccount(sig.Address)
+	acc, err := b.getAccount(context.Background(), sig.Address)
 	if err != nil {
 		return nil
 	}
@@ -1538,27 +2799,138 @@ func (b *Blockchain) CoverageReport() *runtime.CoverageReport {
 }
 
 func (b *Blockchain) ResetCoverageReport() {
+	if b.coverageReportedRuntime.CoverageReport == nil {
+		return
+	}
 	b.coverageReportedRuntime.Reset()
+	b.persistCoverageReport(b.encodeCoverageReport())
+}
+
+// loadCoverageReport merges the coverage report persisted by an earlier run, if
+// coverage is reported and the store persists it.
+func (b *Blockchain) loadCoverageReport() error {
+	report := b.coverageReportedRuntime.CoverageReport
+	coverageStore, ok := b.storage.(storage.CoverageReportStore)
+	if report == nil || !ok {
+		return nil
+	}
+
+	encoded, err := coverageStore.CoverageReport(context.Background())
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load coverage report: %w", err)
+	}
+
+	err = json.Unmarshal(encoded, report)
+	if err != nil {
+		return fmt.Errorf("failed to decode coverage report: %w", err)
+	}
+
+	return nil
+}
+
+// encodeCoverageReport encodes the coverage report to persist it, if coverage is
+// reported and the store persists it, and returns nil otherwise. The report is
+// updated by the executed procedures, so the caller must hold the lock.
+func (b *Blockchain) encodeCoverageReport() []byte {
+	report := b.coverageReportedRuntime.CoverageReport
+	if _, ok := b.storage.(storage.CoverageReportStore); report == nil || !ok {
+		return nil
+	}
+
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		b.conf.ServerLogger.Warn().Err(err).Msg("❗  Failed to persist coverage report")
+		return nil
+	}
+
+	return encoded
+}
+
+// persistCoverageReport stores the encoded coverage report, if any. The report
+// may be large, so it is stored without holding the lock. Failures are logged,
+// as coverage is not chain state.
+func (b *Blockchain) persistCoverageReport(encoded []byte) {
+	coverageStore, ok := b.storage.(storage.CoverageReportStore)
+	if encoded == nil || !ok {
+		return
+	}
+
+	err := coverageStore.SetCoverageReport(context.Background(), encoded)
+	if err != nil {
+		b.conf.ServerLogger.Warn().Err(err).Msg("❗  Failed to persist coverage report")
+	}
 }
 
-func (b *Blockchain) GetTransactionsByBlockID(blockID flowgo.Identifier) ([]*flowgo.TransactionBody, error) {
+// GetComputationReport returns the computation used by an executed transaction,
+// broken down by computation kind. Reports are only recorded with computation
+// reporting enabled, and are not persisted.
+func (b *Blockchain) GetComputationReport(_ context.Context, txID flowgo.Identifier) (*ComputationReport, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	block, err := b.getBlockByID(blockID)
+	report, ok := b.computationReports.get(txID)
+	if !ok {
+		return nil, &types.ComputationReportNotFoundError{TransactionID: txID}
+	}
+
+	return report, nil
+}
+
+// FVMStats returns the program cache, runtime pool and ledger view usage of
+// the FVM since the emulator was started.
+func (b *Blockchain) FVMStats() FVMStats {
+	return b.fvmStats.snapshot()
+}
+
+// GetBlockDependencies returns the transaction dependency DAG of a committed block.
+func (b *Blockchain) GetBlockDependencies(ctx context.Context, blockID flowgo.Identifier) (*BlockDependencies, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	_, err := b.getBlockByID(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, ok := b.dependencies.get(blockID)
+	if !ok {
+		return nil, &types.BlockDependenciesNotFoundError{BlockID: blockID}
+	}
+
+	return dependencies, nil
+}
+
+// ContractProfiles returns the computation usage aggregated per imported contract,
+// ordered by descending computation.
+func (b *Blockchain) ContractProfiles() []ContractProfile {
+	return b.profiler.ranking()
+}
+
+func (b *Blockchain) ResetContractProfiles() {
+	b.profiler.reset()
+}
+
+func (b *Blockchain) GetTransactionsByBlockID(ctx context.Context, blockID flowgo.Identifier) ([]*flowgo.TransactionBody, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	block, err := b.getBlockByID(ctx, blockID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block %s: %w", blockID, err)
 	}
 
 	var transactions []*flowgo.TransactionBody
 	for i, guarantee := range block.Payload.Guarantees {
-		c, err := b.getCollectionByID(guarantee.CollectionID)
+		c, err := b.getCollectionByID(ctx, guarantee.CollectionID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get collection [%d] %s: %w", i, guarantee.CollectionID, err)
 		}
 
 		for j, txID := range c.Transactions {
-			tx, err := b.getTransaction(txID)
+			tx, err := b.getTransaction(ctx, txID)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get transaction [%d] %s: %w", j, txID, err)
 			}
@@ -1568,24 +2940,24 @@ func (b *Blockchain) GetTransactionsByBlockID(blockID flowgo.Identifier) ([]*flo
 	return transactions, nil
 }
 
-func (b *Blockchain) GetTransactionResultsByBlockID(blockID flowgo.Identifier) ([]*access.TransactionResult, error) {
+func (b *Blockchain) GetTransactionResultsByBlockID(ctx context.Context, blockID flowgo.Identifier) ([]*access.TransactionResult, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	block, err := b.getBlockByID(blockID)
+	block, err := b.getBlockByID(ctx, blockID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block %s: %w", blockID, err)
 	}
 
 	var results []*access.TransactionResult
 	for i, guarantee := range block.Payload.Guarantees {
-		c, err := b.getCollectionByID(guarantee.CollectionID)
+		c, err := b.getCollectionByID(ctx, guarantee.CollectionID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get collection [%d] %s: %w", i, guarantee.CollectionID, err)
 		}
 
 		for j, txID := range c.Transactions {
-			result, err := b.getTransactionResult(txID)
+			result, err := b.getTransactionResult(ctx, txID)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get transaction result [%d] %s: %w", j, txID, err)
 			}
@@ -1595,26 +2967,138 @@ func (b *Blockchain) GetTransactionResultsByBlockID(blockID flowgo.Identifier) (
 	return results, nil
 }
 
-func (b *Blockchain) GetLogs(identifier flowgo.Identifier) ([]string, error) {
-	txResult, err := b.storage.TransactionResultByID(context.Background(), identifier)
+// GetTransactionResultByIndex returns the result of the transaction at the given index
+// of the transactions of a committed block.
+func (b *Blockchain) GetTransactionResultByIndex(
+	ctx context.Context,
+	blockID flowgo.Identifier,
+	index uint32,
+) (*access.TransactionResult, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	block, err := b.getBlockByID(ctx, blockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s: %w", blockID, err)
+	}
+
+	var txIDs []flowgo.Identifier
+	for i, guarantee := range block.Payload.Guarantees {
+		c, err := b.getCollectionByID(ctx, guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get collection [%d] %s: %w", i, guarantee.CollectionID, err)
+		}
+
+		for _, txID := range c.Transactions {
+			storedResult, err := b.storage.TransactionResultByID(ctx, txID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get transaction result %s: %w", txID, err)
+			}
+			if storedResult.TransactionIndex == index {
+				return b.sealedTransactionResult(ctx, txID, storedResult)
+			}
+			txIDs = append(txIDs, txID)
+		}
+	}
+
+	// results stored by earlier versions do not record their index,
+	// which is the position of the transaction in the block
+	if int(index) < len(txIDs) {
+		return b.getTransactionResult(ctx, txIDs[index])
+	}
+
+	return nil, &types.TransactionNotFoundError{ID: flowgo.ZeroID}
+}
+
+// GetTransactionLogs returns the Cadence logs of a committed transaction, persisted with its result.
+func (b *Blockchain) GetTransactionLogs(ctx context.Context, txID flowgo.Identifier) ([]string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	txResult, err := b.storage.TransactionResultByID(ctx, txID)
 	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, &types.TransactionNotFoundError{ID: txID}
+		}
 		return nil, err
+	}
 
+	if txResult.Logs == nil {
+		return []string{}, nil
 	}
 	return txResult.Logs, nil
 }
 
+// Deprecated: use GetTransactionLogs, which reports unknown transactions with
+// a types.TransactionNotFoundError.
+func (b *Blockchain) GetLogs(ctx context.Context, identifier flowgo.Identifier) ([]string, error) {
+	logs, err := b.GetTransactionLogs(ctx, identifier)
+	var notFoundErr *types.TransactionNotFoundError
+	if errors.As(err, &notFoundErr) {
+		return nil, storage.ErrNotFound
+	}
+	return logs, err
+}
+
+// GetTransactionError returns the error of a committed transaction,
+// or nil if the transaction succeeded.
+func (b *Blockchain) GetTransactionError(ctx context.Context, id flowgo.Identifier) (*types.TransactionError, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	txResult, err := b.storage.TransactionResultByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, &types.TransactionNotFoundError{ID: id}
+		}
+		return nil, err
+	}
+	if txResult.ErrorCode == 0 {
+		return nil, nil
+	}
+
+	return &types.TransactionError{
+		Code:    txResult.EmulatorErrorCode,
+		Hint:    txResult.EmulatorErrorCode.Hint(),
+		Message: txResult.ErrorMessage,
+	}, nil
+}
+
 // SetClock sets the given clock on blockchain's pending block.
 // After this block is committed, the block timestamp will
 // contain the value of clock.Now().
 func (b *Blockchain) SetClock(clock Clock) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.setClock(clock)
+}
+
+func (b *Blockchain) setClock(clock Clock) {
 	b.clock = clock
 	b.pendingBlock.SetClock(clock)
 }
 
+// AdvanceTime moves the clock of the emulator forward by the given duration,
+// and returns the new timestamp of the pending block.
+func (b *Blockchain) AdvanceTime(duration time.Duration) (time.Time, error) {
+	if duration < 0 {
+		return time.Time{}, types.NewInvalidArgumentError("time cannot move backwards")
+	}
+
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.setClock(NewOffsetClock(b.clock, duration))
+
+	return b.pendingBlock.timestamp, nil
+}
+
 func (b *Blockchain) GetSourceFile(location common.Location) string {
 
+	b.sourceFileMu.RLock()
 	value, exists := b.sourceFileMap[location]
+	b.sourceFileMu.RUnlock()
 	if exists {
 		return value
 	}
@@ -1624,6 +3108,7 @@ func (b *Blockchain) GetSourceFile(location common.Location) string {
 		return location.ID()
 	}
 	view := b.pendingBlock.ledgerState.NewChild()
+	b.fvmStats.ledgerViewCreated()
 
 	env := environment.NewScriptEnvironmentFromStorageSnapshot(
 		b.vmCtx.EnvironmentParams,
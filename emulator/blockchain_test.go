@@ -97,6 +97,7 @@ func DeployAndGenerateAddTwoScript(t *testing.T, adapter *adapters.SDKAdapter) (
 		context.Background(),
 		nil,
 		contracts,
+		0,
 	)
 	require.NoError(t, err)
 
@@ -0,0 +1,117 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// blockFeedBatchSize is the number of blocks a block feed reads from storage at once.
+const blockFeedBatchSize = 100
+
+// BlockFeed delivers the blocks committed after it was created, in height order and
+// without gaps, however slow the subscriber is.
+//
+// Commit notifications only wake the subscriber up, the blocks are read from storage,
+// so notifications dropped while the subscriber is busy do not make it miss blocks.
+// After a rollback, delivery continues at the height rolled back to.
+type BlockFeed struct {
+	emulator Emulator
+	commits  chan BlockEvent
+	next     uint64
+	lastID   flowgo.Identifier
+}
+
+// NewBlockFeed subscribes to the blocks committed after the latest block.
+// The feed must be closed once it is not used anymore.
+func NewBlockFeed(ctx context.Context, emulator Emulator) (*BlockFeed, error) {
+	feed := &BlockFeed{
+		emulator: emulator,
+		commits:  make(chan BlockEvent, 1),
+	}
+
+	// subscribe first, so blocks committed meanwhile wake the subscriber up
+	emulator.SubscribeBlockCommitted(feed.commits)
+
+	latest, err := emulator.GetLatestBlock(ctx)
+	if err != nil {
+		emulator.UnsubscribeBlockCommitted(feed.commits)
+		return nil, err
+	}
+	feed.next = latest.Header.Height + 1
+	feed.lastID = latest.ID()
+
+	return feed, nil
+}
+
+// Commits returns the channel notified when blocks are committed. The blocks
+// committed since the last delivery are delivered with Deliver.
+func (f *BlockFeed) Commits() <-chan BlockEvent {
+	return f.commits
+}
+
+// Deliver calls handle with every block committed since the last delivery, given
+// the notification of the latest commit. Blocks which failed to be read or handled
+// are delivered again with the next notification.
+func (f *BlockFeed) Deliver(ctx context.Context, event BlockEvent, handle func(block *flowgo.Block) error) error {
+	// a block below the next height was either delivered already,
+	// or committed after a rollback replaced the delivered blocks
+	height := event.Block.Header.Height
+	if height < f.next {
+		last, err := f.emulator.GetBlockByHeight(ctx, f.next-1)
+		if err != nil || last.ID() != f.lastID {
+			f.next = height
+		}
+	}
+
+	latest, err := f.emulator.GetLatestBlock(ctx)
+	if err != nil {
+		return err
+	}
+
+	for f.next <= latest.Header.Height {
+		end := f.next + blockFeedBatchSize - 1
+		if end > latest.Header.Height {
+			end = latest.Header.Height
+		}
+
+		blocks, err := f.emulator.GetBlocksByHeightRange(ctx, f.next, end)
+		if err != nil {
+			return err
+		}
+
+		for _, block := range blocks {
+			err = handle(block)
+			if err != nil {
+				return err
+			}
+			f.lastID = block.ID()
+			f.next++
+		}
+	}
+
+	return nil
+}
+
+// Close unsubscribes the feed.
+func (f *BlockFeed) Close() {
+	f.emulator.UnsubscribeBlockCommitted(f.commits)
+}
@@ -0,0 +1,78 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+func TestBlockFeed(t *testing.T) {
+
+	t.Parallel()
+
+	ctx := context.Background()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	feed, err := emulator.NewBlockFeed(ctx, b)
+	require.NoError(t, err)
+	defer feed.Close()
+
+	var delivered []uint64
+	deliver := func(t *testing.T) {
+		event := <-feed.Commits()
+		err := feed.Deliver(ctx, event, func(block *flowgo.Block) error {
+			delivered = append(delivered, block.Header.Height)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("dropped notifications", func(t *testing.T) {
+		// the notifications of the blocks committed meanwhile are dropped
+		for i := 0; i < 5; i++ {
+			_, err := b.CommitBlock()
+			require.NoError(t, err)
+		}
+
+		deliver(t)
+		assert.Equal(t, []uint64{1, 2, 3, 4, 5}, delivered)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		delivered = nil
+
+		err := b.RollbackToBlockHeight(3)
+		require.NoError(t, err)
+
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+
+		deliver(t)
+		assert.Equal(t, []uint64{4}, delivered)
+	})
+}
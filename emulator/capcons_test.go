@@ -19,6 +19,7 @@
 package emulator_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/onflow/flow-emulator/emulator"
@@ -39,6 +40,6 @@ func TestCapabilityControllers(t *testing.T) {
 		}
 	`
 
-	_, err = b.ExecuteScript([]byte(script), nil)
+	_, err = b.ExecuteScript(context.Background(), []byte(script), nil)
 	require.NoError(t, err)
 }
@@ -0,0 +1,193 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/onflow/cadence"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/storage"
+)
+
+// ContractChangeKind is the kind of change of a contract.
+type ContractChangeKind string
+
+const (
+	ContractAdded   ContractChangeKind = "added"
+	ContractUpdated ContractChangeKind = "updated"
+	ContractRemoved ContractChangeKind = "removed"
+)
+
+// ContractChange is a contract deployed, updated or removed by a block.
+type ContractChange struct {
+	Address string             `json:"address"`
+	Name    string             `json:"name"`
+	Kind    ContractChangeKind `json:"kind"`
+}
+
+// BlockChanges summarizes the state changes of a committed block, so that tools
+// can refresh what changed instead of fetching all state again.
+type BlockChanges struct {
+	Height       uint64    `json:"height"`
+	BlockID      string    `json:"blockId"`
+	Timestamp    time.Time `json:"timestamp"`
+	Transactions int       `json:"transactions"`
+	// AccountsCreated are the addresses of the accounts created by the block.
+	AccountsCreated []string         `json:"accountsCreated"`
+	Contracts       []ContractChange `json:"contracts"`
+	// AccountsChanged are the addresses of the accounts whose registers the block
+	// wrote, sorted. It is nil if the storage can not list the registers written
+	// by blocks, e.g. the storage of a forked network.
+	AccountsChanged []string `json:"accountsChanged"`
+	// RegistersChanged is the number of registers the block wrote, nil if the
+	// storage can not list the registers written by blocks.
+	RegistersChanged *int `json:"registersChanged"`
+}
+
+var contractChangeKinds = map[string]ContractChangeKind{
+	flowsdk.EventAccountContractAdded:   ContractAdded,
+	flowsdk.EventAccountContractUpdated: ContractUpdated,
+	flowsdk.EventAccountContractRemoved: ContractRemoved,
+}
+
+// GetChanges returns the changes of the committed blocks from the given height,
+// oldest first, at most limit blocks.
+func (b *Blockchain) GetChanges(ctx context.Context, fromHeight uint64, limit int) ([]BlockChanges, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]BlockChanges, 0)
+	for height := fromHeight; height <= latestBlock.Header.Height && len(changes) < limit; height++ {
+		blockChanges, err := b.getBlockChanges(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *blockChanges)
+	}
+
+	return changes, nil
+}
+
+func (b *Blockchain) getBlockChanges(ctx context.Context, height uint64) (*BlockChanges, error) {
+	block, err := b.getBlockByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &BlockChanges{
+		Height:          height,
+		BlockID:         block.ID().String(),
+		Timestamp:       block.Header.Timestamp,
+		AccountsCreated: []string{},
+		Contracts:       []ContractChange{},
+	}
+
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := b.storage.CollectionByID(ctx, guarantee.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+		changes.Transactions += len(collection.Transactions)
+	}
+
+	events, err := b.storage.EventsByHeight(ctx, height, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, flowEvent := range events {
+		eventType := string(flowEvent.Type)
+		kind, isContractChange := contractChangeKinds[eventType]
+		if eventType != flowsdk.EventAccountCreated && !isContractChange {
+			continue
+		}
+
+		event, err := convert.FlowEventToSDK(flowEvent)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isContractChange {
+			changes.AccountsCreated = append(
+				changes.AccountsCreated,
+				"0x"+flowsdk.AccountCreatedEvent(event).Address().Hex(),
+			)
+			continue
+		}
+
+		contract := ContractChange{Kind: kind}
+		for i, field := range event.Value.EventType.Fields {
+			switch field.Identifier {
+			case "address":
+				if address, ok := event.Value.Fields[i].(cadence.Address); ok {
+					contract.Address = flowgo.Address(address).HexWithPrefix()
+				}
+			case "contract":
+				if name, ok := event.Value.Fields[i].(cadence.String); ok {
+					contract.Name = string(name)
+				}
+			}
+		}
+		changes.Contracts = append(changes.Contracts, contract)
+	}
+
+	deltaProvider, ok := b.storage.(storage.LedgerDeltaProvider)
+	if !ok || b.checkStateAvailable(height) != nil {
+		return changes, nil
+	}
+
+	delta, err := deltaProvider.LedgerDeltaByHeight(ctx, height)
+	if err != nil {
+		// the registers written by blocks of forked networks are not known
+		return changes, nil
+	}
+
+	registers := delta.UpdatedRegisters()
+	registersChanged := len(registers)
+	changes.RegistersChanged = &registersChanged
+
+	accounts := map[string]struct{}{}
+	for _, register := range registers {
+		// global registers have no owner
+		if register.Key.Owner == "" {
+			continue
+		}
+		address := flowgo.BytesToAddress([]byte(register.Key.Owner))
+		accounts[address.HexWithPrefix()] = struct{}{}
+	}
+
+	changes.AccountsChanged = make([]string, 0, len(accounts))
+	for address := range accounts {
+		changes.AccountsChanged = append(changes.AccountsChanged, address)
+	}
+	sort.Strings(changes.AccountsChanged)
+
+	return changes, nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/templates"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+func TestGetChanges(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithStorageLimitEnabled(false),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	accountKey := b.ServiceKey().AccountKey()
+	accountKey.Weight = flowsdk.AccountKeyWeightThreshold
+
+	address, err := adapter.CreateAccount(
+		context.Background(),
+		[]*flowsdk.AccountKey{accountKey},
+		[]templates.Contract{{Name: "Counter", Source: `pub contract Counter {}`}},
+		0,
+	)
+	require.NoError(t, err)
+
+	// the account is created in the block before the latest one
+	latestBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	createdBlock, err := b.GetBlockByHeight(context.Background(), latestBlock.Header.Height-1)
+	require.NoError(t, err)
+
+	tx := templates.UpdateAccountContract(
+		address,
+		templates.Contract{Name: "Counter", Source: `pub contract Counter { pub fun count(): Int { return 0 } }`},
+	).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignPayload(address, 0, signer)
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+	require.NoError(t, err)
+
+	_, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	AssertTransactionSucceeded(t, results[0])
+
+	accountAddress := "0x" + address.Hex()
+	height := createdBlock.Header.Height
+
+	t.Run("all", func(t *testing.T) {
+		changes, err := b.GetChanges(context.Background(), height, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+
+		created := changes[0]
+		assert.Equal(t, height, created.Height)
+		assert.Equal(t, createdBlock.ID().String(), created.BlockID)
+		assert.Equal(t, 1, created.Transactions)
+		assert.Equal(t, []string{accountAddress}, created.AccountsCreated)
+		assert.Equal(t,
+			[]emulator.ContractChange{{Address: accountAddress, Name: "Counter", Kind: emulator.ContractAdded}},
+			created.Contracts,
+		)
+		require.NotNil(t, created.RegistersChanged)
+		assert.Positive(t, *created.RegistersChanged)
+		assert.Contains(t, created.AccountsChanged, accountAddress)
+
+		updated := changes[2]
+		assert.Empty(t, updated.AccountsCreated)
+		assert.Equal(t,
+			[]emulator.ContractChange{{Address: accountAddress, Name: "Counter", Kind: emulator.ContractUpdated}},
+			updated.Contracts,
+		)
+		assert.Contains(t, updated.AccountsChanged, accountAddress)
+
+		empty := changes[1]
+		assert.Equal(t, 0, empty.Transactions)
+		assert.Empty(t, empty.AccountsCreated)
+		assert.Empty(t, empty.Contracts)
+		require.NotNil(t, empty.RegistersChanged)
+		assert.Equal(t, 0, *empty.RegistersChanged)
+		assert.Empty(t, empty.AccountsChanged)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		changes, err := b.GetChanges(context.Background(), height, 2)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, height+1, changes[1].Height)
+	})
+
+	t.Run("after latest", func(t *testing.T) {
+		changes, err := b.GetChanges(context.Background(), height+3, 10)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
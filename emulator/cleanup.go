@@ -0,0 +1,214 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/environment"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// accountTransactionGasLimit bounds the computation of transactions run on behalf of accounts,
+// like cleanups, which may destroy many stored values.
+const accountTransactionGasLimit = 1_000_000
+
+// cleanupTransaction removes the contracts, links and stored values of the authorizer,
+// except for the FLOW vault and its capabilities, which the account needs to remain valid.
+const cleanupTransaction = `
+transaction {
+	prepare(account: AuthAccount) {
+		for name in account.contracts.names {
+			account.contracts.remove(name: name)
+		}
+
+		let publicPaths: [PublicPath] = []
+		account.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+			if path != /public/flowTokenReceiver && path != /public/flowTokenBalance {
+				publicPaths.append(path)
+			}
+			return true
+		})
+		for path in publicPaths {
+			account.unlink(path)
+		}
+
+		let privatePaths: [PrivatePath] = []
+		account.forEachPrivate(fun (path: PrivatePath, type: Type): Bool {
+			privatePaths.append(path)
+			return true
+		})
+		for path in privatePaths {
+			account.unlink(path)
+		}
+
+		let resourcePaths: [StoragePath] = []
+		let valuePaths: [StoragePath] = []
+		account.forEachStored(fun (path: StoragePath, type: Type): Bool {
+			if path == /storage/flowTokenVault {
+				return true
+			}
+			if type.isSubtype(of: Type<@AnyResource>()) {
+				resourcePaths.append(path)
+			} else {
+				valuePaths.append(path)
+			}
+			return true
+		})
+		for path in resourcePaths {
+			destroy account.load<@AnyResource>(from: path)
+		}
+		for path in valuePaths {
+			account.load<AnyStruct>(from: path)
+		}
+	}
+}
+`
+
+// CleanupAccount removes the contracts, links and stored values of an account,
+// simulating an account being emptied, in a block of its own.
+//
+// The FLOW vault of the account and its receiver and balance capabilities are kept,
+// as are its keys, so the account remains valid. Contracts are only removed if
+// contract removal is enabled. The accounts of the core contracts can not be cleaned up.
+func (b *Blockchain) CleanupAccount(ctx context.Context, address flowgo.Address) (*types.TransactionResult, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.conf.ReadOnly {
+		return nil, &types.ReadOnlyError{}
+	}
+
+	chain := b.GetChain()
+	for _, coreAddress := range []flowgo.Address{
+		chain.ServiceAddress(),
+		fvm.FungibleTokenAddress(chain),
+		fvm.FlowTokenAddress(chain),
+		environment.FlowFeesAddress(chain),
+	} {
+		if address == coreAddress {
+			return nil, types.NewInvalidArgumentError(
+				fmt.Sprintf("account %s holds core contracts and can not be cleaned up", address.HexWithPrefix()),
+			)
+		}
+	}
+
+	account, err := b.getAccount(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(account.Contracts) > 0 && !b.conf.ContractRemovalEnabled {
+		return nil, types.NewInvalidArgumentError(
+			fmt.Sprintf("account %s has contracts, and contract removal is disabled", address.HexWithPrefix()),
+		)
+	}
+
+	return b.commitAccountTransaction(ctx, address, []byte(cleanupTransaction))
+}
+
+// accountTransaction is a transaction run on behalf of an account, without its signature.
+type accountTransaction struct {
+	address   flowgo.Address
+	script    []byte
+	arguments [][]byte
+}
+
+// commitAccountTransaction executes a transaction authorized by the given account
+// in a block of its own, and commits the block. The transaction is not signed,
+// and is paid for by the service account without fees.
+//
+// The caller must hold the lock.
+func (b *Blockchain) commitAccountTransaction(
+	ctx context.Context,
+	address flowgo.Address,
+	script []byte,
+) (*types.TransactionResult, error) {
+	results, err := b.commitAccountTransactions(ctx, []accountTransaction{{
+		address: address,
+		script:  script,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return results[0], nil
+}
+
+// commitAccountTransactions executes transactions authorized by the given accounts,
+// one after the other, in a block of their own, and commits the block.
+//
+// The caller must hold the lock.
+func (b *Blockchain) commitAccountTransactions(
+	ctx context.Context,
+	transactions []accountTransaction,
+) ([]*types.TransactionResult, error) {
+	if !b.pendingBlock.Empty() {
+		return nil, &types.PendingBlockNotEmptyError{BlockID: b.pendingBlock.ID()}
+	}
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceKey := b.serviceKey
+	for _, transaction := range transactions {
+		tx := flowgo.NewTransactionBody().
+			SetScript(transaction.script).
+			SetGasLimit(accountTransactionGasLimit).
+			SetReferenceBlockID(latestBlock.ID()).
+			SetProposalKey(flowgo.Address(serviceKey.Address), uint64(serviceKey.Index), 0).
+			SetPayer(flowgo.Address(serviceKey.Address)).
+			AddAuthorizer(transaction.address)
+		for _, argument := range transaction.arguments {
+			tx.AddArgument(argument)
+		}
+
+		b.pendingBlock.AddTransaction(*tx, "", 0)
+	}
+
+	// the transactions are not signed by the accounts, and do not use the service key
+	blockContext := fvm.NewContextFromParent(
+		b.newFVMContextFromHeader(b.pendingBlock.Block().Header),
+		fvm.WithAuthorizationChecksEnabled(false),
+		fvm.WithSequenceNumberCheckAndIncrementEnabled(false),
+		fvm.WithTransactionFeesEnabled(false),
+	)
+
+	results := make([]*types.TransactionResult, 0, len(transactions))
+	for range transactions {
+		result, err := b.executeNextTransaction(blockContext)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	_, err = b.commitBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,186 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/templates"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+const cleanupContract = `
+	pub contract Collectibles {
+		pub resource NFT {}
+
+		pub fun mint(): @NFT {
+			return <- create NFT()
+		}
+	}
+`
+
+func TestCleanupAccount(t *testing.T) {
+
+	t.Parallel()
+
+	newAccount := func(t *testing.T, contractRemoval bool) (*emulator.Blockchain, flowsdk.Address) {
+		b, err := emulator.New(
+			emulator.WithStorageLimitEnabled(false),
+			emulator.WithContractRemovalEnabled(contractRemoval),
+		)
+		require.NoError(t, err)
+
+		logger := zerolog.Nop()
+		adapter := adapters.NewSDKAdapter(&logger, b)
+
+		accountKey := b.ServiceKey().AccountKey()
+		accountKey.Weight = flowsdk.AccountKeyWeightThreshold
+
+		address, err := adapter.CreateAccount(
+			context.Background(),
+			[]*flowsdk.AccountKey{accountKey},
+			[]templates.Contract{{Name: "Collectibles", Source: cleanupContract}},
+			0,
+		)
+		require.NoError(t, err)
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(fmt.Sprintf(`
+				import Collectibles from 0x%s
+
+				transaction {
+					prepare(signer: AuthAccount) {
+						signer.save(<- Collectibles.mint(), to: /storage/nft)
+						signer.save("value", to: /storage/value)
+						signer.link<&Collectibles.NFT>(/public/nft, target: /storage/nft)
+						signer.link<&Collectibles.NFT>(/private/nft, target: /storage/nft)
+					}
+				}
+			`, address.Hex()))).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(address, 0, 0).
+			SetPayer(b.ServiceKey().Address).
+			AddAuthorizer(address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignPayload(address, 0, signer)
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, results[0])
+
+		return b, address
+	}
+
+	storageKeys := func(t *testing.T, b *emulator.Blockchain, address flowsdk.Address, domain string) []string {
+		storage, err := b.GetAccountStorage(
+			context.Background(),
+			flowgo.Address(address),
+			emulator.AccountStorageQuery{Domains: []string{domain}},
+		)
+		require.NoError(t, err)
+
+		keys := make([]string, 0, len(storage.Items))
+		for _, item := range storage.Items {
+			keys = append(keys, item.Key)
+		}
+		return keys
+	}
+
+	t.Run("clears storage and contracts", func(t *testing.T) {
+		t.Parallel()
+
+		b, address := newAccount(t, true)
+
+		latestBlock, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+
+		result, err := b.CleanupAccount(context.Background(), flowgo.Address(address))
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+
+		block, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, latestBlock.Header.Height+1, block.Header.Height)
+
+		account, err := b.GetAccount(context.Background(), flowgo.Address(address))
+		require.NoError(t, err)
+		assert.Empty(t, account.Contracts)
+		assert.Len(t, account.Keys, 1)
+
+		assert.Equal(t, []string{"flowTokenVault"}, storageKeys(t, b, address, "storage"))
+		assert.Equal(t, []string{"flowTokenBalance", "flowTokenReceiver"}, storageKeys(t, b, address, "public"))
+		assert.Empty(t, storageKeys(t, b, address, "private"))
+	})
+
+	t.Run("contract removal disabled", func(t *testing.T) {
+		t.Parallel()
+
+		b, address := newAccount(t, false)
+
+		_, err := b.CleanupAccount(context.Background(), flowgo.Address(address))
+		var invalidErr *types.InvalidArgumentError
+		assert.ErrorAs(t, err, &invalidErr)
+
+		assert.Equal(t, []string{"flowTokenVault", "nft", "value"}, storageKeys(t, b, address, "storage"))
+	})
+
+	t.Run("core account", func(t *testing.T) {
+		t.Parallel()
+
+		b, _ := newAccount(t, true)
+
+		_, err := b.CleanupAccount(context.Background(), flowgo.Address(b.ServiceKey().Address))
+		var invalidErr *types.InvalidArgumentError
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		t.Parallel()
+
+		b, _ := newAccount(t, true)
+
+		address, err := b.GetNetworkParameters().ChainID.Chain().AddressAtIndex(1000)
+		require.NoError(t, err)
+
+		_, err = b.CleanupAccount(context.Background(), address)
+		var notFoundErr *types.AccountNotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+}
@@ -18,7 +18,10 @@
 
 package emulator
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 type Clock interface {
 	Now() time.Time
@@ -33,3 +36,73 @@ func (sc SystemClock) Now() time.Time {
 func NewSystemClock() SystemClock {
 	return SystemClock{}
 }
+
+// OffsetClock is a clock running ahead of another clock by a fixed offset.
+type OffsetClock struct {
+	Clock  Clock
+	Offset time.Duration
+}
+
+func (oc OffsetClock) Now() time.Time {
+	return oc.Clock.Now().Add(oc.Offset)
+}
+
+// NewOffsetClock returns a clock running ahead of the given clock by the given offset.
+// Offsets accumulate if the given clock is an offset clock itself.
+func NewOffsetClock(clock Clock, offset time.Duration) OffsetClock {
+	if offsetClock, ok := clock.(OffsetClock); ok {
+		offsetClock.Offset += offset
+		return offsetClock
+	}
+	return OffsetClock{
+		Clock:  clock,
+		Offset: offset,
+	}
+}
+
+// offsetClockAt returns a clock reading the given time now, running ahead of the given clock.
+// The offset of the given clock replaces its offset if it is an offset clock itself.
+func offsetClockAt(clock Clock, now time.Time) OffsetClock {
+	if offsetClock, ok := clock.(OffsetClock); ok {
+		clock = offsetClock.Clock
+	}
+	return OffsetClock{
+		Clock:  clock,
+		Offset: now.Sub(clock.Now()),
+	}
+}
+
+// DeterministicClock is a clock which gives the pending block at a height a fixed time:
+// the start time, advanced by the step for every block after the first one. Block
+// timestamps, and therefore block IDs, are the same across runs.
+type DeterministicClock struct {
+	mu     sync.RWMutex
+	start  time.Time
+	step   time.Duration
+	height uint64
+}
+
+// NewDeterministicClock returns a deterministic clock at the time of the first block.
+func NewDeterministicClock(start time.Time, step time.Duration) *DeterministicClock {
+	return &DeterministicClock{
+		start:  start.UTC(),
+		step:   step,
+		height: 1,
+	}
+}
+
+func (dc *DeterministicClock) Now() time.Time {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+	return dc.start.Add(time.Duration(dc.height-1) * dc.step)
+}
+
+// SetHeight moves the clock to the time of the block at the given height, at least 1.
+func (dc *DeterministicClock) SetHeight(height uint64) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if height < 1 {
+		height = 1
+	}
+	dc.height = height
+}
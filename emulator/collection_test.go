@@ -19,6 +19,7 @@ package emulator_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/onflow/flow-emulator/adapters"
@@ -113,4 +114,60 @@ func TestCollections(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Multiple collections", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New(
+			emulator.WithMaxCollectionSize(2),
+		)
+		require.NoError(t, err)
+
+		logger := zerolog.Nop()
+		adapter := adapters.NewSDKAdapter(&logger, b)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		transactions := make([]*flowsdk.Transaction, 5)
+		for i := range transactions {
+			tx := flowsdk.NewTransaction().
+				SetScript([]byte(fmt.Sprintf(`transaction { execute { log(%d) } }`, i))).
+				SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+				SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber+uint64(i)).
+				SetPayer(b.ServiceKey().Address)
+
+			err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+			require.NoError(t, err)
+
+			err = adapter.SendTransaction(context.Background(), *tx)
+			require.NoError(t, err)
+
+			transactions[i] = tx
+		}
+
+		block, _, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+
+		// 5 transactions are split into collections of 2, 2 and 1 transactions
+		require.Len(t, block.Payload.Guarantees, 3)
+
+		i := 0
+		for _, guarantee := range block.Payload.Guarantees {
+			collection, err := adapter.GetCollectionByID(context.Background(), convert.FlowIdentifierToSDK(guarantee.ID()))
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(collection.TransactionIDs), 2)
+
+			for _, txID := range collection.TransactionIDs {
+				assert.Equal(t, transactions[i].ID(), txID)
+
+				result, err := b.GetTransactionResult(context.Background(), convert.SDKIdentifierToFlow(txID))
+				require.NoError(t, err)
+				assert.Equal(t, guarantee.CollectionID, result.CollectionID)
+				i++
+			}
+		}
+		assert.Equal(t, len(transactions), i)
+	})
 }
@@ -0,0 +1,203 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// CompatibilityReport lists the persisted state which the current emulator
+// and Cadence version can not execute against.
+type CompatibilityReport struct {
+	Accounts     int
+	Contracts    int
+	StoredValues int
+	Issues       []CompatibilityIssue
+}
+
+// Compatible returns true if no issues were found.
+func (r *CompatibilityReport) Compatible() bool {
+	return len(r.Issues) == 0
+}
+
+// CompatibilityIssue is a contract or stored value which failed to load.
+//
+// Exactly one of Contract and Path is set, unless the account's storage
+// could not be listed at all.
+type CompatibilityIssue struct {
+	Address  flowgo.Address
+	Contract string
+	Path     string
+	Error    string
+}
+
+func (i CompatibilityIssue) String() string {
+	switch {
+	case i.Contract != "":
+		return fmt.Sprintf("contract A.%s.%s: %s", i.Address.Hex(), i.Contract, i.Error)
+	case i.Path != "":
+		return fmt.Sprintf("stored value 0x%s %s: %s", i.Address.Hex(), i.Path, i.Error)
+	default:
+		return fmt.Sprintf("account 0x%s: %s", i.Address.Hex(), i.Error)
+	}
+}
+
+// importContractScript loads, parses and checks a deployed contract.
+const importContractScript = `
+	import %s from 0x%s
+
+	pub fun main() {}
+`
+
+const storagePathsScript = `
+	pub fun main(address: Address): [StoragePath] {
+		return getAuthAccount(address).storagePaths
+	}
+`
+
+// loadStoredValueScript decodes the value stored at a path. Structs are
+// copied, which decodes them fully; resources can not be copied, so they are
+// borrowed instead.
+const loadStoredValueScript = `
+	pub fun main(address: Address, path: StoragePath) {
+		let account = getAuthAccount(address)
+		let type = account.type(at: path)!
+		if type.isSubtype(of: Type<@AnyResource>()) {
+			account.borrow<&AnyResource>(from: path)!
+		} else {
+			account.copy<AnyStruct>(from: path)!
+		}
+	}
+`
+
+// CheckCompatibility loads every deployed contract and every stored value at
+// the latest block, and reports the ones the current Cadence version fails on.
+func (b *Blockchain) CheckCompatibility(ctx context.Context) (*CompatibilityReport, error) {
+	report := &CompatibilityReport{}
+
+	chain := b.vmCtx.Chain
+	for index := uint64(1); ; index++ {
+		address, err := chain.AddressAtIndex(index)
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := b.GetAccount(ctx, address)
+		var notFoundErr *types.AccountNotFoundError
+		if errors.As(err, &notFoundErr) || (err == nil && account == nil) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		report.Accounts++
+
+		err = b.checkAccountCompatibility(ctx, account, report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func (b *Blockchain) checkAccountCompatibility(
+	ctx context.Context,
+	account *flowgo.Account,
+	report *CompatibilityReport,
+) error {
+	contracts := make([]string, 0, len(account.Contracts))
+	for name := range account.Contracts {
+		contracts = append(contracts, name)
+	}
+	sort.Strings(contracts)
+
+	for _, name := range contracts {
+		report.Contracts++
+
+		script := fmt.Sprintf(importContractScript, name, account.Address.Hex())
+		result, err := b.ExecuteScript(ctx, []byte(script), nil)
+		if err != nil {
+			return err
+		}
+		if result.Error != nil {
+			report.Issues = append(report.Issues, CompatibilityIssue{
+				Address:  account.Address,
+				Contract: name,
+				Error:    result.Error.Error(),
+			})
+		}
+	}
+
+	address, err := jsoncdc.Encode(cadence.NewAddress(account.Address))
+	if err != nil {
+		return err
+	}
+
+	result, err := b.ExecuteScript(ctx, []byte(storagePathsScript), [][]byte{address})
+	if err != nil {
+		return err
+	}
+	if result.Error != nil {
+		report.Issues = append(report.Issues, CompatibilityIssue{
+			Address: account.Address,
+			Error:   result.Error.Error(),
+		})
+		return nil
+	}
+
+	for _, value := range result.Value.(cadence.Array).Values {
+		path := value.(cadence.Path)
+		if path.Domain != common.PathDomainStorage {
+			continue
+		}
+
+		report.StoredValues++
+
+		encodedPath, err := jsoncdc.Encode(path)
+		if err != nil {
+			return err
+		}
+
+		result, err := b.ExecuteScript(ctx, []byte(loadStoredValueScript), [][]byte{address, encodedPath})
+		if err != nil {
+			return err
+		}
+		if result.Error != nil {
+			report.Issues = append(report.Issues, CompatibilityIssue{
+				Address: account.Address,
+				Path:    path.String(),
+				Error:   result.Error.Error(),
+			})
+		}
+	}
+
+	return nil
+}
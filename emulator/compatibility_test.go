@@ -0,0 +1,123 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+
+	t.Parallel()
+
+	serviceAddress := flowsdk.Address(flowgo.Emulator.Chain().ServiceAddress())
+
+	b, adapter := setupTransactionTests(
+		t,
+		emulator.WithContractRemovalEnabled(true),
+		emulator.Contracts([]emulator.ContractDescription{
+			{
+				Name:    "Base",
+				Address: serviceAddress,
+				Source:  []byte(`pub contract Base { pub struct S {} }`),
+			},
+			{
+				Name:    "Dependent",
+				Address: serviceAddress,
+				Source: []byte(fmt.Sprintf(
+					`import Base from 0x%s
+					pub contract Dependent { pub fun make(): Base.S { return Base.S() } }`,
+					serviceAddress.Hex(),
+				)),
+			},
+		}),
+	)
+
+	sendTransaction := func(script string) {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(script)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address).
+			AddAuthorizer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = adapter.SendTransaction(context.Background(), *tx)
+		require.NoError(t, err)
+
+		result, err := b.ExecuteNextTransaction()
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	sendTransaction(fmt.Sprintf(`
+		import Base from 0x%s
+
+		transaction {
+			prepare(signer: AuthAccount) {
+				signer.save(Base.S(), to: /storage/s)
+			}
+		}`,
+		serviceAddress.Hex(),
+	))
+
+	report, err := b.CheckCompatibility(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.Compatible(), "unexpected issues: %v", report.Issues)
+	assert.Greater(t, report.Accounts, 0)
+	assert.Greater(t, report.Contracts, 2)
+	assert.Greater(t, report.StoredValues, 0)
+
+	sendTransaction(`
+		transaction {
+			prepare(signer: AuthAccount) {
+				signer.contracts.remove(name: "Base")
+			}
+		}`,
+	)
+
+	report, err = b.CheckCompatibility(context.Background())
+	require.NoError(t, err)
+	require.False(t, report.Compatible())
+
+	var contracts, paths []string
+	for _, issue := range report.Issues {
+		contracts = append(contracts, issue.Contract)
+		paths = append(paths, issue.Path)
+	}
+	assert.Contains(t, contracts, "Dependent")
+	assert.Contains(t, paths, "/storage/s")
+}
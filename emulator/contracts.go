@@ -19,6 +19,7 @@
 package emulator
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-emulator/convert"
@@ -84,7 +85,7 @@ func deployContract(b *Blockchain, name string, contract []byte) error {
 		return fmt.Errorf("not able to deploy contracts without set private key")
 	}
 
-	latestBlock, err := b.GetLatestBlock()
+	latestBlock, err := b.GetLatestBlock(context.Background())
 	if err != nil {
 		return err
 	}
@@ -109,7 +110,7 @@ func deployContract(b *Blockchain, name string, contract []byte) error {
 		return err
 	}
 
-	err = b.AddTransaction(*convert.SDKTransactionToFlow(*tx))
+	err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
 	if err != nil {
 		return err
 	}
@@ -18,6 +18,7 @@
 package emulator_test
 
 import (
+	"context"
 	"fmt"
 	flowgo "github.com/onflow/flow-go/model/flow"
 	"testing"
@@ -54,10 +55,38 @@ func TestCommonContractsDeployment(t *testing.T) {
 				getAccount(0x%s).contracts.get(name: "%s") ?? panic("contract is not deployed")
 	    	}`, contract.Address, contract.Name)
 
-			scriptResult, err := b.ExecuteScript([]byte(scriptCode), [][]byte{})
+			scriptResult, err := b.ExecuteScript(context.Background(), []byte(scriptCode), [][]byte{})
 			require.NoError(t, err)
 			require.NoError(t, scriptResult.Error)
 
 		}
 	}
 }
+
+func TestBlobsContract(t *testing.T) {
+
+	t.Parallel()
+
+	chain := flowgo.Emulator.Chain()
+	contract := emulator.NewBlobsContract(chain, "http://localhost:8080/emulator/blobs/")
+
+	b, err := emulator.New(
+		emulator.Contracts([]emulator.ContractDescription{contract}),
+	)
+	require.NoError(t, err)
+
+	scriptCode := fmt.Sprintf(`
+		import EmulatorBlobs from 0x%s
+
+		pub fun main(): [String] {
+			return [EmulatorBlobs.url("ab12"), EmulatorBlobs.file("ab12").uri()]
+		}`, contract.Address)
+
+	scriptResult, err := b.ExecuteScript(context.Background(), []byte(scriptCode), [][]byte{})
+	require.NoError(t, err)
+	require.NoError(t, scriptResult.Error)
+	require.Equal(t,
+		`["http://localhost:8080/emulator/blobs/ab12", "http://localhost:8080/emulator/blobs/ab12"]`,
+		scriptResult.Value.String(),
+	)
+}
@@ -0,0 +1,222 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	coreContracts "github.com/onflow/flow-core-contracts/lib/go/contracts"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/environment"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// coreContractUpdateTransaction updates a contract of the authorizer to the given hex encoded code.
+const coreContractUpdateTransaction = `
+transaction {
+	prepare(account: AuthAccount) {
+		account.contracts.update__experimental(name: "%s", code: "%s".decodeHex())
+	}
+}
+`
+
+// CoreContract is a core contract deployed when the emulator state is bootstrapped.
+type CoreContract struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	// UpToDate is true if the deployed code is the version bundled with the emulator.
+	UpToDate bool `json:"upToDate"`
+	// TransactionID is the ID of the transaction which upgraded the contract, if any.
+	TransactionID string `json:"transactionId,omitempty"`
+	// Error is the error of the upgrade transaction, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+type bundledContract struct {
+	name    string
+	address flowgo.Address
+	code    []byte
+}
+
+// bundledCoreContracts returns the core contracts bundled with the emulator, in the order
+// they are deployed when the state is bootstrapped, so dependencies come first.
+func (b *Blockchain) bundledCoreContracts() []bundledContract {
+	chain := b.GetChain()
+	service := chain.ServiceAddress()
+	fungibleToken := fvm.FungibleTokenAddress(chain)
+	flowToken := fvm.FlowTokenAddress(chain)
+	flowFees := environment.FlowFeesAddress(chain)
+
+	return []bundledContract{
+		{
+			name:    "FungibleToken",
+			address: fungibleToken,
+			code:    coreContracts.FungibleToken(),
+		},
+		{
+			name:    "NonFungibleToken",
+			address: service,
+			code:    coreContracts.NonFungibleToken(),
+		},
+		{
+			name:    "MetadataViews",
+			address: service,
+			code:    coreContracts.MetadataViews(fungibleToken.HexWithPrefix(), service.HexWithPrefix()),
+		},
+		{
+			name:    "ViewResolver",
+			address: service,
+			code:    coreContracts.ViewResolver(),
+		},
+		{
+			name:    "FungibleTokenMetadataViews",
+			address: fungibleToken,
+			code:    coreContracts.FungibleTokenMetadataViews(fungibleToken.Hex(), service.Hex()),
+		},
+		{
+			name:    "FlowToken",
+			address: flowToken,
+			code: coreContracts.FlowToken(
+				fungibleToken.HexWithPrefix(),
+				service.HexWithPrefix(),
+				service.HexWithPrefix(),
+			),
+		},
+		{
+			name:    "FlowStorageFees",
+			address: service,
+			code:    coreContracts.FlowStorageFees(fungibleToken.HexWithPrefix(), flowToken.HexWithPrefix()),
+		},
+		{
+			name:    "FlowFees",
+			address: flowFees,
+			code: coreContracts.FlowFees(
+				fungibleToken.HexWithPrefix(),
+				flowToken.HexWithPrefix(),
+				service.HexWithPrefix(),
+			),
+		},
+		{
+			name:    "FlowServiceAccount",
+			address: service,
+			code: coreContracts.FlowServiceAccount(
+				fungibleToken.HexWithPrefix(),
+				flowToken.HexWithPrefix(),
+				flowFees.HexWithPrefix(),
+				service.HexWithPrefix(),
+			),
+		},
+	}
+}
+
+// CoreContracts lists the core contracts, and whether the deployed code is the version
+// bundled with the emulator. States created by older emulators may deploy older versions.
+func (b *Blockchain) CoreContracts(ctx context.Context) ([]CoreContract, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.coreContracts(ctx)
+}
+
+func (b *Blockchain) coreContracts(ctx context.Context) ([]CoreContract, error) {
+	bundled := b.bundledCoreContracts()
+	contracts := make([]CoreContract, 0, len(bundled))
+
+	for _, contract := range bundled {
+		account, err := b.getAccount(ctx, contract.address)
+		if err != nil {
+			return nil, err
+		}
+
+		contracts = append(contracts, CoreContract{
+			Name:     contract.name,
+			Address:  contract.address.HexWithPrefix(),
+			UpToDate: bytes.Equal(account.Contracts[contract.name], contract.code),
+		})
+	}
+
+	return contracts, nil
+}
+
+// UpgradeCoreContracts updates the core contracts which are not up to date to the version
+// bundled with the emulator, so states created by older emulators keep pace with the
+// emulator without being reset.
+//
+// The contracts are updated in dependency order, by a transaction each, on behalf of
+// the accounts holding them, in a block of their own. The block is not committed if all
+// contracts are up to date. It returns the core contracts after the upgrade, with the
+// transaction which updated each contract, and its error if the update failed.
+func (b *Blockchain) UpgradeCoreContracts(ctx context.Context) ([]CoreContract, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.conf.ReadOnly {
+		return nil, &types.ReadOnlyError{}
+	}
+
+	contracts, err := b.coreContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []accountTransaction
+	var upgraded []int
+	for i, contract := range b.bundledCoreContracts() {
+		if contracts[i].UpToDate {
+			continue
+		}
+		transactions = append(transactions, accountTransaction{
+			address: contract.address,
+			script: []byte(fmt.Sprintf(
+				coreContractUpdateTransaction,
+				contract.name,
+				hex.EncodeToString(contract.code),
+			)),
+		})
+		upgraded = append(upgraded, i)
+	}
+	if len(transactions) == 0 {
+		return contracts, nil
+	}
+
+	results, err := b.commitAccountTransactions(ctx, transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err = b.coreContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		contract := &contracts[upgraded[i]]
+		contract.TransactionID = result.TransactionID.String()
+		if !result.Succeeded() {
+			contract.Error = result.Error.Error()
+		}
+	}
+
+	return contracts, nil
+}
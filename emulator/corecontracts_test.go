@@ -0,0 +1,159 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/templates"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// outdateCoreContract updates a core contract deployed on the service account to a different code.
+func outdateCoreContract(t *testing.T, b *emulator.Blockchain, name string) {
+	serviceAddress := b.ServiceKey().Address
+
+	account, err := b.GetAccount(context.Background(), flowgo.Address(serviceAddress))
+	require.NoError(t, err)
+
+	tx := templates.UpdateAccountContract(serviceAddress, templates.Contract{
+		Name:   name,
+		Source: string(account.Contracts[name]) + "\n// outdated\n",
+	}).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(serviceAddress, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(serviceAddress)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(serviceAddress, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+	require.NoError(t, err)
+
+	_, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	AssertTransactionSucceeded(t, results[0])
+}
+
+func TestUpgradeCoreContracts(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("up to date", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		contracts, err := b.CoreContracts(context.Background())
+		require.NoError(t, err)
+		require.NotEmpty(t, contracts)
+
+		for _, contract := range contracts {
+			assert.True(t, contract.UpToDate, contract.Name)
+		}
+
+		latest, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+
+		contracts, err = b.UpgradeCoreContracts(context.Background())
+		require.NoError(t, err)
+
+		for _, contract := range contracts {
+			assert.True(t, contract.UpToDate, contract.Name)
+			assert.Empty(t, contract.TransactionID, contract.Name)
+		}
+
+		// no block is committed when nothing needs to be upgraded
+		block, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, latest.Header.Height, block.Header.Height)
+	})
+
+	t.Run("outdated", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		outdateCoreContract(t, b, "FlowStorageFees")
+
+		contracts, err := b.CoreContracts(context.Background())
+		require.NoError(t, err)
+
+		for _, contract := range contracts {
+			assert.Equal(t, contract.Name != "FlowStorageFees", contract.UpToDate, contract.Name)
+		}
+
+		contracts, err = b.UpgradeCoreContracts(context.Background())
+		require.NoError(t, err)
+
+		for _, contract := range contracts {
+			assert.True(t, contract.UpToDate, contract.Name)
+			assert.Empty(t, contract.Error, contract.Name)
+			if contract.Name == "FlowStorageFees" {
+				assert.NotEmpty(t, contract.TransactionID)
+			} else {
+				assert.Empty(t, contract.TransactionID, contract.Name)
+			}
+		}
+	})
+
+	t.Run("pending block not empty", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		outdateCoreContract(t, b, "FlowStorageFees")
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction {}`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, err = b.UpgradeCoreContracts(context.Background())
+		var notEmptyErr *types.PendingBlockNotEmptyError
+		assert.ErrorAs(t, err, &notEmptyErr)
+	})
+}
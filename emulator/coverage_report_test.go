@@ -27,6 +27,7 @@ import (
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/flow-emulator/adapters"
 	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage/sqlite"
 	flowsdk "github.com/onflow/flow-go-sdk"
 	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/rs/zerolog"
@@ -66,7 +67,7 @@ func TestCoverageReport(t *testing.T) {
 	callScript := GenerateGetCounterCountScript(counterAddress, b.ServiceKey().Address)
 
 	// Sample call (value is 0)
-	scriptResult, err := b.ExecuteScript([]byte(callScript), nil)
+	scriptResult, err := b.ExecuteScript(context.Background(), []byte(callScript), nil)
 	require.NoError(t, err)
 	assert.Equal(t, cadence.NewInt(0), scriptResult.Value)
 
@@ -97,3 +98,54 @@ func TestCoverageReport(t *testing.T) {
 		coverage.LineHits,
 	)
 }
+
+func TestCoverageReportPersistence(t *testing.T) {
+
+	t.Parallel()
+
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	coverageReport := runtime.NewCoverageReport()
+	b, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithCoverageReport(coverageReport),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	_, counterAddress := DeployAndGenerateAddTwoScript(t, adapter)
+
+	_, err = b.ExecuteScript(
+		context.Background(),
+		[]byte(GenerateGetCounterCountScript(counterAddress, b.ServiceKey().Address)),
+		nil,
+	)
+	require.NoError(t, err)
+
+	// the report is persisted when the blockchain is closed
+	require.NotEmpty(t, coverageReport.Coverage)
+	b.Close()
+
+	restartedReport := runtime.NewCoverageReport()
+	restarted, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithCoverageReport(restartedReport),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, coverageReport.Summary(), restartedReport.Summary())
+
+	restarted.ResetCoverageReport()
+
+	resetReport := runtime.NewCoverageReport()
+	_, err = emulator.New(
+		emulator.WithStore(store),
+		emulator.WithCoverageReport(resetReport),
+	)
+	require.NoError(t, err)
+
+	assert.Empty(t, resetReport.Coverage)
+}
@@ -0,0 +1,163 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"sort"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// BlockDependencies is the dependency DAG of the transactions in a block.
+//
+// A transaction depends on every earlier transaction in the block which wrote
+// a register it reads or writes, or which read a register it writes. Edges
+// always point from a later transaction to an earlier one.
+type BlockDependencies struct {
+	BlockID      flowgo.Identifier       `json:"blockId"`
+	Transactions []TransactionDependency `json:"transactions"`
+}
+
+// TransactionDependency lists the earlier transactions a transaction conflicts with.
+type TransactionDependency struct {
+	TransactionID flowgo.Identifier `json:"transactionId"`
+	Index         uint32            `json:"index"`
+	DependsOn     []DependencyEdge  `json:"dependsOn"`
+}
+
+// DependencyEdge is a conflict with an earlier transaction, together with the
+// registers both transactions access.
+type DependencyEdge struct {
+	TransactionID flowgo.Identifier `json:"transactionId"`
+	Registers     []string          `json:"registers"`
+}
+
+// dependencyGraphCacheSize is the number of most recently committed blocks
+// whose dependency graphs are kept.
+const dependencyGraphCacheSize = 1000
+
+// dependencyGraphs keeps the dependency graphs of the most recently committed blocks, by block ID.
+type dependencyGraphs struct {
+	cache *lru.Cache
+}
+
+func newDependencyGraphs() *dependencyGraphs {
+	cache, err := lru.New(dependencyGraphCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &dependencyGraphs{cache: cache}
+}
+
+func (g *dependencyGraphs) get(blockID flowgo.Identifier) (*BlockDependencies, bool) {
+	dependencies, ok := g.cache.Get(blockID)
+	if !ok {
+		return nil, false
+	}
+	return dependencies.(*BlockDependencies), true
+}
+
+func (g *dependencyGraphs) set(blockID flowgo.Identifier, dependencies *BlockDependencies) {
+	g.cache.Add(blockID, dependencies)
+}
+
+// registerAccess is the set of registers read and written by a single transaction.
+type registerAccess struct {
+	reads  map[flowgo.RegisterID]struct{}
+	writes map[flowgo.RegisterID]struct{}
+}
+
+func newRegisterAccess(executionSnapshot *snapshot.ExecutionSnapshot) registerAccess {
+	access := registerAccess{
+		reads:  make(map[flowgo.RegisterID]struct{}, len(executionSnapshot.ReadSet)),
+		writes: make(map[flowgo.RegisterID]struct{}, len(executionSnapshot.WriteSet)),
+	}
+	for id := range executionSnapshot.ReadSet {
+		access.reads[id] = struct{}{}
+	}
+	for id := range executionSnapshot.WriteSet {
+		access.writes[id] = struct{}{}
+	}
+	return access
+}
+
+// conflicts returns the registers through which the later access depends on
+// the earlier one, sorted for stable output.
+func (later registerAccess) conflicts(earlier registerAccess) []string {
+	registers := make(map[flowgo.RegisterID]struct{})
+
+	for id := range earlier.writes {
+		_, read := later.reads[id]
+		_, written := later.writes[id]
+		if read || written {
+			registers[id] = struct{}{}
+		}
+	}
+	for id := range earlier.reads {
+		if _, ok := later.writes[id]; ok {
+			registers[id] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(registers))
+	for id := range registers {
+		result = append(result, id.String())
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// buildBlockDependencies computes the dependency DAG for transactions executed
+// in the given order.
+func buildBlockDependencies(
+	blockID flowgo.Identifier,
+	transactionIDs []flowgo.Identifier,
+	accesses map[flowgo.Identifier]registerAccess,
+) *BlockDependencies {
+	dependencies := &BlockDependencies{
+		BlockID:      blockID,
+		Transactions: make([]TransactionDependency, 0, len(transactionIDs)),
+	}
+
+	for i, txID := range transactionIDs {
+		dependency := TransactionDependency{
+			TransactionID: txID,
+			Index:         uint32(i),
+			DependsOn:     []DependencyEdge{},
+		}
+
+		for _, earlierID := range transactionIDs[:i] {
+			registers := accesses[txID].conflicts(accesses[earlierID])
+			if len(registers) == 0 {
+				continue
+			}
+			dependency.DependsOn = append(dependency.DependsOn, DependencyEdge{
+				TransactionID: earlierID,
+				Registers:     registers,
+			})
+		}
+
+		dependencies.Transactions = append(dependencies.Transactions, dependency)
+	}
+
+	return dependencies
+}
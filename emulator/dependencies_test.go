@@ -0,0 +1,118 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestGetBlockDependencies(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("transactions from the same proposer", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		var txIDs []flowgo.Identifier
+		for i := uint64(0); i < 2; i++ {
+			tx := flowsdk.NewTransaction().
+				SetScript([]byte(`transaction { execute { log("hello") } }`)).
+				SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+				SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber+i).
+				SetPayer(b.ServiceKey().Address)
+
+			err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+			require.NoError(t, err)
+
+			err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+			require.NoError(t, err)
+
+			txIDs = append(txIDs, convert.SDKIdentifierToFlow(tx.ID()))
+		}
+
+		block, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		for _, result := range results {
+			AssertTransactionSucceeded(t, result)
+		}
+
+		dependencies, err := b.GetBlockDependencies(context.Background(), block.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, block.ID(), dependencies.BlockID)
+		require.Len(t, dependencies.Transactions, 2)
+
+		first := dependencies.Transactions[0]
+		assert.Equal(t, txIDs[0], first.TransactionID)
+		assert.Empty(t, first.DependsOn)
+
+		// both transactions increment the sequence number of the same proposal key
+		second := dependencies.Transactions[1]
+		assert.Equal(t, txIDs[1], second.TransactionID)
+		require.Len(t, second.DependsOn, 1)
+		assert.Equal(t, txIDs[0], second.DependsOn[0].TransactionID)
+		assert.NotEmpty(t, second.DependsOn[0].Registers)
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		_, err = b.GetBlockDependencies(context.Background(), flowgo.Identifier{0x1})
+
+		var notFoundErr *types.BlockNotFoundByIDError
+		require.ErrorAs(t, err, &notFoundErr)
+	})
+
+	t.Run("block committed before start", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		genesis, err := b.GetBlockByHeight(context.Background(), 0)
+		require.NoError(t, err)
+
+		_, err = b.GetBlockDependencies(context.Background(), genesis.ID())
+
+		var notFoundErr *types.BlockDependenciesNotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+	})
+}
@@ -19,7 +19,10 @@
 package emulator
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/onflow/cadence/runtime"
 	"github.com/onflow/cadence/runtime/common"
@@ -28,7 +31,9 @@ import (
 	sdkcrypto "github.com/onflow/flow-go-sdk/crypto"
 	"github.com/onflow/flow-go/access"
 	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
 
+	"github.com/onflow/flow-emulator/storage"
 	"github.com/onflow/flow-emulator/types"
 )
 
@@ -100,6 +105,11 @@ type CoverageReportCapable interface {
 	ResetCoverageReport()
 }
 
+type ProfilerCapable interface {
+	ContractProfiles() []ContractProfile
+	ResetContractProfiles()
+}
+
 type DebuggingCapable interface {
 	StartDebugger() *interpreter.Debugger
 	EndDebugging()
@@ -110,43 +120,91 @@ type DebuggingCapable interface {
 
 type SnapshotCapable interface {
 	Snapshots() ([]string, error)
-	CreateSnapshot(name string) error
+	SnapshotInfos() ([]storage.SnapshotInfo, error)
+	CreateSnapshot(name string, description string) error
 	LoadSnapshot(name string) error
+	DeleteSnapshot(name string) error
+}
+
+type StorageSwitchCapable interface {
+	Storage() storage.Store
+	SwitchStorage(ctx context.Context, target storage.Store) (storage.Store, error)
+	Checkpoint(ctx context.Context) error
+	CompactStorage(ctx context.Context) (int64, error)
+	ExportState(w io.Writer) error
+	ImportState(r io.Reader) error
 }
 
 type RollbackCapable interface {
 	RollbackToBlockHeight(height uint64) error
 }
 
+type StatePinningCapable interface {
+	PinBlockHeight(height uint64) error
+	UnpinBlockHeight(height uint64) error
+	PinnedBlockHeights() []uint64
+}
+
+type StandbyCapable interface {
+	EnableStandby() error
+	ResetState() error
+}
+
 type AccessProvider interface {
 	Ping() error
 	GetNetworkParameters() access.NetworkParameters
 
-	GetLatestBlock() (*flowgo.Block, error)
-	GetBlockByID(id flowgo.Identifier) (*flowgo.Block, error)
-	GetBlockByHeight(height uint64) (*flowgo.Block, error)
+	GetLatestBlock(ctx context.Context) (*flowgo.Block, error)
+	GetBlockByID(ctx context.Context, id flowgo.Identifier) (*flowgo.Block, error)
+	GetBlockByHeight(ctx context.Context, height uint64) (*flowgo.Block, error)
+	GetBlocksByHeightRange(ctx context.Context, startHeight, endHeight uint64) ([]*flowgo.Block, error)
 
-	GetCollectionByID(colID flowgo.Identifier) (*flowgo.LightCollection, error)
+	GetCollectionByID(ctx context.Context, colID flowgo.Identifier) (*flowgo.LightCollection, error)
 
-	GetTransaction(txID flowgo.Identifier) (*flowgo.TransactionBody, error)
-	GetTransactionResult(txID flowgo.Identifier) (*access.TransactionResult, error)
-	GetTransactionsByBlockID(blockID flowgo.Identifier) ([]*flowgo.TransactionBody, error)
-	GetTransactionResultsByBlockID(blockID flowgo.Identifier) ([]*access.TransactionResult, error)
+	GetTransaction(ctx context.Context, txID flowgo.Identifier) (*flowgo.TransactionBody, error)
+	GetTransactionResult(ctx context.Context, txID flowgo.Identifier) (*access.TransactionResult, error)
+	GetTransactionsByBlockID(ctx context.Context, blockID flowgo.Identifier) ([]*flowgo.TransactionBody, error)
+	GetTransactionResultsByBlockID(ctx context.Context, blockID flowgo.Identifier) ([]*access.TransactionResult, error)
+	GetTransactionResultByIndex(ctx context.Context, blockID flowgo.Identifier, index uint32) (*access.TransactionResult, error)
+	GetTransactionLogs(ctx context.Context, txID flowgo.Identifier) ([]string, error)
 
-	GetAccount(address flowgo.Address) (*flowgo.Account, error)
-	GetAccountAtBlockHeight(address flowgo.Address, blockHeight uint64) (*flowgo.Account, error)
-	GetAccountByIndex(uint) (*flowgo.Account, error)
+	GetAccount(ctx context.Context, address flowgo.Address) (*flowgo.Account, error)
+	GetAccountAtBlockHeight(ctx context.Context, address flowgo.Address, blockHeight uint64) (*flowgo.Account, error)
+	GetAccountAtPendingBlock(ctx context.Context, address flowgo.Address) (*flowgo.Account, error)
+	GetAccountByIndex(ctx context.Context, index uint) (*flowgo.Account, error)
 
-	GetEventsByHeight(blockHeight uint64, eventType string) ([]flowgo.Event, error)
-	GetEventsForBlockIDs(eventType string, blockIDs []flowgo.Identifier) ([]flowgo.BlockEvents, error)
-	GetEventsForHeightRange(eventType string, startHeight, endHeight uint64) ([]flowgo.BlockEvents, error)
+	GetRegisterValues(ctx context.Context, registerIDs flowgo.RegisterIDs, height uint64) ([]flowgo.RegisterValue, error)
 
-	ExecuteScript(script []byte, arguments [][]byte) (*types.ScriptResult, error)
-	ExecuteScriptAtBlockHeight(script []byte, arguments [][]byte, blockHeight uint64) (*types.ScriptResult, error)
-	ExecuteScriptAtBlockID(script []byte, arguments [][]byte, id flowgo.Identifier) (*types.ScriptResult, error)
+	GetEventsByHeight(ctx context.Context, blockHeight uint64, eventType string) ([]flowgo.Event, error)
+	GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []flowgo.Identifier) ([]flowgo.BlockEvents, error)
+	GetEventsForHeightRange(ctx context.Context, eventType string, startHeight, endHeight uint64) ([]flowgo.BlockEvents, error)
+
+	ExecuteScript(ctx context.Context, script []byte, arguments [][]byte) (*types.ScriptResult, error)
+	ExecuteScriptAtBlockHeight(ctx context.Context, script []byte, arguments [][]byte, blockHeight uint64) (*types.ScriptResult, error)
+	ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, id flowgo.Identifier) (*types.ScriptResult, error)
+	ExecuteScriptAtPendingBlock(ctx context.Context, script []byte, arguments [][]byte) (*types.ScriptResult, error)
+
+	SendTransaction(ctx context.Context, tx *flowgo.TransactionBody) error
+	AddTransaction(ctx context.Context, tx flowgo.TransactionBody) error
+	CheckSequenceNumber(ctx context.Context, tx *flowgo.TransactionBody) error
+	SendCheckedTransaction(ctx context.Context, tx *flowgo.TransactionBody) error
+}
+
+type TransactionPriorityCapable interface {
+	SendTransactionWithPriority(ctx context.Context, tx *flowgo.TransactionBody, priority int) error
+}
+
+type SchedulingCapable interface {
+	AddScheduledTransaction(ctx context.Context, tx flowgo.TransactionBody, height uint64) error
+}
 
-	SendTransaction(tx *flowgo.TransactionBody) error
-	AddTransaction(tx flowgo.TransactionBody) error
+type PendingBlockCapable interface {
+	PendingBlockInfo() *PendingBlockInfo
+	RemovePendingTransaction(txID flowgo.Identifier) error
+}
+
+type TransactionExpiryProvider interface {
+	ReferenceBlockWindow(ctx context.Context) (*ReferenceBlockWindow, error)
 }
 
 type AutoMineCapable interface {
@@ -156,13 +214,112 @@ type AutoMineCapable interface {
 
 type ExecutionCapable interface {
 	ExecuteAndCommitBlock() (*flowgo.Block, []*types.TransactionResult, error)
+	ExecuteAndCommitBlockAt(timestamp time.Time) (*flowgo.Block, []*types.TransactionResult, error)
 	ExecuteNextTransaction() (*types.TransactionResult, error)
+	ExecuteNextTransactionInteractive() (*InteractiveExecution, error)
 	ExecuteBlock() ([]*types.TransactionResult, error)
 	CommitBlock() (*flowgo.Block, error)
 }
 
+type ClockCapable interface {
+	SetClock(clock Clock)
+	AdvanceTime(duration time.Duration) (time.Time, error)
+}
+
 type LogProvider interface {
-	GetLogs(flowgo.Identifier) ([]string, error)
+	GetLogs(ctx context.Context, id flowgo.Identifier) ([]string, error)
+	GetTransactionError(ctx context.Context, id flowgo.Identifier) (*types.TransactionError, error)
+}
+
+type ComputationReportProvider interface {
+	GetComputationReport(ctx context.Context, txID flowgo.Identifier) (*ComputationReport, error)
+}
+
+type FVMStatsProvider interface {
+	FVMStats() FVMStats
+}
+
+type DependencyGraphProvider interface {
+	GetBlockDependencies(ctx context.Context, blockID flowgo.Identifier) (*BlockDependencies, error)
+}
+
+type ExecutionDataProvider interface {
+	GetExecutionDataByBlockID(ctx context.Context, blockID flowgo.Identifier) (*execution_data.BlockExecutionData, error)
+}
+
+type ChangesProvider interface {
+	GetChanges(ctx context.Context, fromHeight uint64, limit int) ([]BlockChanges, error)
+}
+
+type SyncCapable interface {
+	GetCommittedBlock(ctx context.Context, height uint64) (*CommittedBlock, error)
+	ApplyCommittedBlock(ctx context.Context, block *CommittedBlock) error
+	SyncHead(ctx context.Context) error
+}
+
+type ArgumentValidationCapable interface {
+	ValidateArguments(ctx context.Context, code []byte, arguments [][]byte) (*ArgumentValidation, error)
+}
+
+type CompatibilityCheckCapable interface {
+	CheckCompatibility(ctx context.Context) (*CompatibilityReport, error)
+}
+
+type SubscriptionCapable interface {
+	SubscribeBlockCommitted(ch chan<- BlockEvent)
+	UnsubscribeBlockCommitted(ch chan<- BlockEvent)
+	OnTransactionExecuted(callback TransactionExecutedCallback) (unsubscribe func())
+}
+
+type InboxProvider interface {
+	AccountInbox(ctx context.Context, address flowgo.Address) ([]InboxEntry, error)
+}
+
+type AccountStorageProvider interface {
+	GetAccountStorage(ctx context.Context, address flowgo.Address, query AccountStorageQuery) (*AccountStorage, error)
+}
+
+type AccountFixtureCapable interface {
+	ExportAccountFixture(ctx context.Context, address flowgo.Address) (*AccountFixture, error)
+	ImportAccountFixture(ctx context.Context, fixture *AccountFixture) (*flowgo.Block, error)
+}
+
+type FeeConfigurationCapable interface {
+	FeeParameters(ctx context.Context) (*FeeParameters, error)
+	SetFeeParameters(ctx context.Context, parameters FeeParameters) (*types.TransactionResult, error)
+	ExecutionEffortWeights(ctx context.Context) (map[common.ComputationKind]uint64, error)
+	SetExecutionEffortWeights(ctx context.Context, weights map[common.ComputationKind]uint64) (*types.TransactionResult, error)
+}
+
+type StatusProvider interface {
+	Status(ctx context.Context) (*Status, error)
+}
+
+type AccountCleanupCapable interface {
+	CleanupAccount(ctx context.Context, address flowgo.Address) (*types.TransactionResult, error)
+}
+
+type VaultRecoveryCapable interface {
+	CheckVaults(ctx context.Context, address flowgo.Address) ([]Vault, error)
+	RepairVaults(ctx context.Context, address flowgo.Address) ([]Vault, *types.TransactionResult, error)
+}
+
+type CoreContractsCapable interface {
+	CoreContracts(ctx context.Context) ([]CoreContract, error)
+	UpgradeCoreContracts(ctx context.Context) ([]CoreContract, error)
+}
+
+type EventExpectationCapable interface {
+	ExpectEvent(matcher EventMatcher) (EventExpectation, error)
+	EventExpectations() []EventExpectation
+	UnmatchedEventExpectations() []UnmatchedEventExpectation
+	ClearUnmatchedEventExpectations()
+}
+
+type FaultInjectionCapable interface {
+	AddFaultRule(matcher FaultMatcher) (FaultRule, error)
+	FaultRules() []FaultRule
+	RemoveFaultRule(id string) bool
 }
 
 type SourceMapCapable interface {
@@ -172,15 +329,44 @@ type SourceMapCapable interface {
 // Emulator defines the method set of an emulated emulator.
 type Emulator interface {
 	ServiceKey() ServiceKey
+	TestAccounts() []TestAccount
 
 	AccessProvider
 
 	CoverageReportCapable
+	ProfilerCapable
 	DebuggingCapable
 	SnapshotCapable
+	StorageSwitchCapable
 	RollbackCapable
+	StatePinningCapable
+	StandbyCapable
 	AutoMineCapable
+	TransactionPriorityCapable
+	SchedulingCapable
+	PendingBlockCapable
+	TransactionExpiryProvider
 	ExecutionCapable
+	ClockCapable
 	LogProvider
+	DependencyGraphProvider
+	ChangesProvider
+	ExecutionDataProvider
+	ComputationReportProvider
+	FVMStatsProvider
 	SourceMapCapable
+	SubscriptionCapable
+	EventExpectationCapable
+	FaultInjectionCapable
+	SyncCapable
+	CompatibilityCheckCapable
+	ArgumentValidationCapable
+	InboxProvider
+	AccountStorageProvider
+	AccountFixtureCapable
+	AccountCleanupCapable
+	VaultRecoveryCapable
+	CoreContractsCapable
+	FeeConfigurationCapable
+	StatusProvider
 }
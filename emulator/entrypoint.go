@@ -0,0 +1,124 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/parser"
+)
+
+const (
+	EntryPointKindScript      = "script"
+	EntryPointKindTransaction = "transaction"
+)
+
+// EntryPoint describes how a script or transaction is invoked.
+type EntryPoint struct {
+	// Kind is either EntryPointKindScript or EntryPointKindTransaction.
+	Kind       string                `json:"kind"`
+	Parameters []EntryPointParameter `json:"parameters"`
+	// ReturnType is the return type of a script, as written in the code.
+	ReturnType string `json:"returnType,omitempty"`
+	// Authorizers is the number of accounts which must authorize a transaction.
+	Authorizers int `json:"authorizers"`
+}
+
+// EntryPointParameter is a parameter of a script or transaction. Its type is
+// given as written in the code, e.g. `UFix64`, `[Address]` or `FungibleToken.Vault`.
+type EntryPointParameter struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// IntrospectEntryPoint parses a script or transaction and describes its entry point.
+func IntrospectEntryPoint(code []byte) (*EntryPoint, error) {
+	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, main, err := findEntryPoint(program)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction != nil {
+		entryPoint := &EntryPoint{
+			Kind:       EntryPointKindTransaction,
+			Parameters: entryPointParameters(transaction.ParameterList),
+		}
+		if transaction.Prepare != nil {
+			prepare := transaction.Prepare.FunctionDeclaration
+			if prepare.ParameterList != nil {
+				entryPoint.Authorizers = len(prepare.ParameterList.Parameters)
+			}
+		}
+		return entryPoint, nil
+	}
+
+	returnType := "Void"
+	if main.ReturnTypeAnnotation != nil && main.ReturnTypeAnnotation.Type != nil {
+		if declared := main.ReturnTypeAnnotation.String(); declared != "" {
+			returnType = declared
+		}
+	}
+
+	return &EntryPoint{
+		Kind:       EntryPointKindScript,
+		Parameters: entryPointParameters(main.ParameterList),
+		ReturnType: returnType,
+	}, nil
+}
+
+func entryPointParameters(list *ast.ParameterList) []EntryPointParameter {
+	parameters := []EntryPointParameter{}
+	if list == nil {
+		return parameters
+	}
+
+	for _, parameter := range list.Parameters {
+		parameters = append(parameters, EntryPointParameter{
+			Name: parameter.Identifier.Identifier,
+			Type: parameter.TypeAnnotation.String(),
+		})
+	}
+
+	return parameters
+}
+
+// findEntryPoint returns either the transaction or the main function declared
+// by a program.
+func findEntryPoint(program *ast.Program) (*ast.TransactionDeclaration, *ast.FunctionDeclaration, error) {
+	if transactions := program.TransactionDeclarations(); len(transactions) > 0 {
+		if len(transactions) > 1 {
+			return nil, nil, fmt.Errorf("code declares more than one transaction")
+		}
+		return transactions[0], nil, nil
+	}
+
+	for _, function := range program.FunctionDeclarations() {
+		if function.Identifier.Identifier == "main" {
+			return nil, function, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("code declares neither a transaction nor a main function")
+}
@@ -0,0 +1,109 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+func TestIntrospectEntryPoint(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("script", func(t *testing.T) {
+		t.Parallel()
+
+		entryPoint, err := emulator.IntrospectEntryPoint([]byte(`
+			import FungibleToken from 0xee82856bf20e2aa6
+
+			pub fun main(addresses: [Address], limit: UInt8?): {Address: UFix64} {
+				return {}
+			}
+		`))
+		require.NoError(t, err)
+
+		assert.Equal(t, &emulator.EntryPoint{
+			Kind: emulator.EntryPointKindScript,
+			Parameters: []emulator.EntryPointParameter{
+				{Name: "addresses", Type: "[Address]"},
+				{Name: "limit", Type: "UInt8?"},
+			},
+			ReturnType: "{Address: UFix64}",
+		}, entryPoint)
+	})
+
+	t.Run("script without return type", func(t *testing.T) {
+		t.Parallel()
+
+		entryPoint, err := emulator.IntrospectEntryPoint([]byte(`pub fun main() {}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, "Void", entryPoint.ReturnType)
+		assert.Empty(t, entryPoint.Parameters)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		t.Parallel()
+
+		entryPoint, err := emulator.IntrospectEntryPoint([]byte(`
+			import FungibleToken from 0xee82856bf20e2aa6
+
+			transaction(amount: UFix64, vault: @FungibleToken.Vault) {
+				prepare(payer: AuthAccount, receiver: AuthAccount) {
+					destroy vault
+				}
+			}
+		`))
+		require.NoError(t, err)
+
+		assert.Equal(t, &emulator.EntryPoint{
+			Kind: emulator.EntryPointKindTransaction,
+			Parameters: []emulator.EntryPointParameter{
+				{Name: "amount", Type: "UFix64"},
+				{Name: "vault", Type: "@FungibleToken.Vault"},
+			},
+			Authorizers: 2,
+		}, entryPoint)
+	})
+
+	t.Run("transaction without prepare", func(t *testing.T) {
+		t.Parallel()
+
+		entryPoint, err := emulator.IntrospectEntryPoint([]byte(`transaction { execute {} }`))
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, entryPoint.Authorizers)
+		assert.Empty(t, entryPoint.Parameters)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := emulator.IntrospectEntryPoint([]byte(`pub fun main(`))
+		assert.Error(t, err)
+
+		_, err = emulator.IntrospectEntryPoint([]byte(`pub struct S {}`))
+		assert.Error(t, err)
+	})
+}
@@ -58,7 +58,7 @@ func TestEventEmitted(t *testing.T) {
 			}
 		`)
 
-		result, err := b.ExecuteScript(script, nil)
+		result, err := b.ExecuteScript(context.Background(), script, nil)
 		assert.NoError(t, err)
 		require.NoError(t, result.Error)
 		require.Empty(t, result.Events)
@@ -97,6 +97,7 @@ func TestEventEmitted(t *testing.T) {
 			context.Background(),
 			[]*flowsdk.AccountKey{publicKey},
 			accountContracts,
+			0,
 		)
 		assert.NoError(t, err)
 
@@ -173,3 +174,42 @@ func TestEventEmitted(t *testing.T) {
 
 	})
 }
+
+func TestGetEventsForHeightRange(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	for i := 0; i < 40; i++ {
+		_, err := b.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	latestBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	t.Run("ascending heights", func(t *testing.T) {
+		blockEvents, err := b.GetEventsForHeightRange(context.Background(), "", 2, 30)
+		require.NoError(t, err)
+		require.Len(t, blockEvents, 29)
+
+		for i, events := range blockEvents {
+			assert.Equal(t, uint64(2+i), events.BlockHeight)
+		}
+	})
+
+	t.Run("range past latest block", func(t *testing.T) {
+		blockEvents, err := b.GetEventsForHeightRange(context.Background(), "", 10, latestBlock.Header.Height+100)
+		require.NoError(t, err)
+		require.Len(t, blockEvents, int(latestBlock.Header.Height-10+1))
+		assert.Equal(t, latestBlock.Header.Height, blockEvents[len(blockEvents)-1].BlockHeight)
+	})
+
+	t.Run("range after latest block", func(t *testing.T) {
+		blockEvents, err := b.GetEventsForHeightRange(context.Background(), "", latestBlock.Header.Height+1, latestBlock.Header.Height+10)
+		require.NoError(t, err)
+		require.Empty(t, blockEvents)
+	})
+}
@@ -0,0 +1,135 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"fmt"
+
+	exeState "github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+// GetExecutionDataByBlockID returns the execution data of a committed block.
+//
+// The execution data is built from the committed block: there is a chunk for
+// every collection of the block, with the transactions and events of the
+// collection, followed by a system chunk. The emulator does not track register
+// writes per collection, so the trie update of the whole block is part of the
+// system chunk, along with events not emitted by a transaction of a collection.
+// The emulator does not maintain a state trie, so the root hash of the trie
+// update is empty.
+func (b *Blockchain) GetExecutionDataByBlockID(
+	ctx context.Context,
+	blockID flowgo.Identifier,
+) (*execution_data.BlockExecutionData, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	deltaProvider, ok := b.storage.(storage.LedgerDeltaProvider)
+	if !ok {
+		return nil, fmt.Errorf("storage does not support exporting ledger deltas")
+	}
+
+	block, err := b.getBlockByID(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the trie update of a pruned block is not known
+	err = b.checkStateAvailable(block.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := b.storage.EventsByHeight(ctx, block.Header.Height, "")
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]*execution_data.ChunkExecutionData, 0, len(block.Payload.Guarantees)+1)
+	collected := make(map[flowgo.Identifier]struct{})
+
+	for _, guarantee := range block.Payload.Guarantees {
+		lightCollection, err := b.storage.CollectionByID(ctx, guarantee.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+
+		collection := &flowgo.Collection{
+			Transactions: make([]*flowgo.TransactionBody, 0, len(lightCollection.Transactions)),
+		}
+		for _, txID := range lightCollection.Transactions {
+			tx, err := b.storage.TransactionByID(ctx, txID)
+			if err != nil {
+				return nil, err
+			}
+			collection.Transactions = append(collection.Transactions, &tx)
+			collected[txID] = struct{}{}
+		}
+
+		chunk := &execution_data.ChunkExecutionData{
+			Collection: collection,
+		}
+		for _, event := range events {
+			if lightCollection.Has(event.TransactionID) {
+				chunk.Events = append(chunk.Events, event)
+			}
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	delta, err := deltaProvider.LedgerDeltaByHeight(ctx, block.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	trieUpdate := &ledger.TrieUpdate{}
+	for _, entry := range delta.UpdatedRegisters() {
+		key := exeState.RegisterIDToKey(entry.Key)
+		path, err := pathfinder.KeyToPath(key, complete.DefaultPathFinderVersion)
+		if err != nil {
+			return nil, err
+		}
+		trieUpdate.Paths = append(trieUpdate.Paths, path)
+		trieUpdate.Payloads = append(trieUpdate.Payloads, ledger.NewPayload(key, entry.Value))
+	}
+
+	systemChunk := &execution_data.ChunkExecutionData{
+		Collection: &flowgo.Collection{},
+		TrieUpdate: trieUpdate,
+	}
+	for _, event := range events {
+		if _, ok := collected[event.TransactionID]; !ok {
+			systemChunk.Events = append(systemChunk.Events, event)
+		}
+	}
+	chunks = append(chunks, systemChunk)
+
+	return &execution_data.BlockExecutionData{
+		BlockID:             blockID,
+		ChunkExecutionDatas: chunks,
+	}, nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/templates"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestGetExecutionDataByBlockID(t *testing.T) {
+
+	t.Parallel()
+
+	const tokenContract = `
+		access(all) contract Token {
+			access(all) event Minted(amount: UFix64)
+
+			init() {
+				emit Minted(amount: 10.0)
+			}
+		}
+	`
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	contracts := []templates.Contract{{Name: "Token", Source: tokenContract}}
+	address, err := adapter.CreateAccount(context.Background(), nil, contracts, 0)
+	require.NoError(t, err)
+
+	// the account is created in the block before the latest, empty block
+	latest, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	block, err := b.GetBlockByHeight(context.Background(), latest.Header.Height-1)
+	require.NoError(t, err)
+
+	t.Run("block with a collection", func(t *testing.T) {
+		data, err := b.GetExecutionDataByBlockID(context.Background(), block.ID())
+		require.NoError(t, err)
+		assert.Equal(t, block.ID(), data.BlockID)
+
+		// a chunk for the collection, and the system chunk
+		require.Len(t, data.ChunkExecutionDatas, 2)
+
+		chunk := data.ChunkExecutionDatas[0]
+		require.Len(t, chunk.Collection.Transactions, 1)
+		txID := chunk.Collection.Transactions[0].ID()
+
+		events, err := b.GetEventsByHeight(context.Background(), block.Header.Height, "")
+		require.NoError(t, err)
+		assert.Equal(t, flowgo.EventsList(events), chunk.Events)
+
+		var eventTypes []flowgo.EventType
+		for _, event := range chunk.Events {
+			assert.Equal(t, txID, event.TransactionID)
+			eventTypes = append(eventTypes, event.Type)
+		}
+		assert.Contains(t, eventTypes, flowgo.EventType("A."+address.Hex()+".Token.Minted"))
+
+		systemChunk := data.ChunkExecutionDatas[1]
+		assert.Empty(t, systemChunk.Collection.Transactions)
+		assert.Empty(t, systemChunk.Events)
+		require.NotNil(t, systemChunk.TrieUpdate)
+		assert.NotEmpty(t, systemChunk.TrieUpdate.Payloads)
+		assert.Len(t, systemChunk.TrieUpdate.Paths, len(systemChunk.TrieUpdate.Payloads))
+	})
+
+	t.Run("empty block", func(t *testing.T) {
+		empty, err := b.CommitBlock()
+		require.NoError(t, err)
+
+		data, err := b.GetExecutionDataByBlockID(context.Background(), empty.ID())
+		require.NoError(t, err)
+
+		require.Len(t, data.ChunkExecutionDatas, 1)
+		assert.Empty(t, data.ChunkExecutionDatas[0].Events)
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+		_, err := b.GetExecutionDataByBlockID(context.Background(), flowgo.Identifier{1})
+		var notFoundErr *types.BlockNotFoundByIDError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+}
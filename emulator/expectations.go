@@ -0,0 +1,246 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// ExpectationReport is how an event expectation is reported when no event of
+// the committed block matched it.
+type ExpectationReport string
+
+const (
+	// ExpectationReportLog logs a warning for the unmatched expectation.
+	ExpectationReportLog ExpectationReport = "log"
+	// ExpectationReportError logs an error for the unmatched expectation, and keeps it
+	// in the unmatched expectations until they are cleared. The block is committed nonetheless.
+	ExpectationReportError ExpectationReport = "error"
+)
+
+// EventMatcher describes an event the pending block is expected to emit.
+type EventMatcher struct {
+	// Type is the fully-qualified event type, e.g. A.f8d6e0586b0a20c7.Token.Minted.
+	Type string `json:"type"`
+	// Fields are the expected values of event fields, formatted as strings.
+	// Strings are compared without quotes and addresses in hex.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Report is how a missing event is reported, ExpectationReportLog by default.
+	Report ExpectationReport `json:"report,omitempty"`
+}
+
+// EventExpectation is an event matcher registered for the pending block.
+type EventExpectation struct {
+	ID string `json:"id"`
+	EventMatcher
+}
+
+func (e EventExpectation) String() string {
+	if len(e.Fields) == 0 {
+		return e.Type
+	}
+
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]string, len(names))
+	for i, name := range names {
+		fields[i] = fmt.Sprintf("%s: %s", name, e.Fields[name])
+	}
+
+	return fmt.Sprintf("%s(%s)", e.Type, strings.Join(fields, ", "))
+}
+
+// UnmatchedEventExpectation is an expectation reported as an error, which no event
+// of the block committed after it was registered matched.
+type UnmatchedEventExpectation struct {
+	EventExpectation
+	BlockID     flowgo.Identifier `json:"blockId"`
+	BlockHeight uint64            `json:"blockHeight"`
+}
+
+// ExpectEvent registers an event the pending block is expected to emit.
+//
+// The expectation is checked once the pending block is committed and then
+// discarded. If no event of the block matches it, it is reported as configured
+// by the matcher.
+func (b *Blockchain) ExpectEvent(matcher EventMatcher) (EventExpectation, error) {
+	if matcher.Type == "" {
+		return EventExpectation{}, types.NewInvalidArgumentError("event type is required")
+	}
+
+	switch matcher.Report {
+	case "":
+		matcher.Report = ExpectationReportLog
+	case ExpectationReportLog, ExpectationReportError:
+	default:
+		return EventExpectation{}, types.NewInvalidArgumentError(fmt.Sprintf(
+			"invalid report %q, must be %q or %q",
+			matcher.Report,
+			ExpectationReportLog,
+			ExpectationReportError,
+		))
+	}
+
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.expectationCount++
+	expectation := EventExpectation{
+		ID:           fmt.Sprintf("%d", b.expectationCount),
+		EventMatcher: matcher,
+	}
+	b.expectations = append(b.expectations, expectation)
+
+	return expectation, nil
+}
+
+// EventExpectations returns the expectations registered for the pending block,
+// oldest first.
+func (b *Blockchain) EventExpectations() []EventExpectation {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	expectations := make([]EventExpectation, len(b.expectations))
+	copy(expectations, b.expectations)
+
+	return expectations
+}
+
+// UnmatchedEventExpectations returns the expectations reported as errors which were
+// not matched by the block committed after them, oldest first.
+func (b *Blockchain) UnmatchedEventExpectations() []UnmatchedEventExpectation {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	unmatched := make([]UnmatchedEventExpectation, len(b.unmatchedExpectations))
+	copy(unmatched, b.unmatchedExpectations)
+
+	return unmatched
+}
+
+// ClearUnmatchedEventExpectations discards the unmatched expectations.
+func (b *Blockchain) ClearUnmatchedEventExpectations() {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.unmatchedExpectations = nil
+}
+
+// checkEventExpectations matches the expectations of the pending block against
+// the events of the committed block, and reports the unmatched ones. The commit
+// does not fail because of unmatched expectations.
+func (b *Blockchain) checkEventExpectations(block *flowgo.Block, events []flowgo.Event) {
+	expectations := b.expectations
+	b.expectations = nil
+
+	for _, expectation := range expectations {
+		if b.matchesAnyEvent(expectation, events) {
+			continue
+		}
+
+		if expectation.Report == ExpectationReportError {
+			b.conf.ServerLogger.Error().
+				Uint64("blockHeight", block.Header.Height).
+				Str("expectationID", expectation.ID).
+				Msgf("❗  Expected event %s was not emitted", expectation)
+
+			b.unmatchedExpectations = append(b.unmatchedExpectations, UnmatchedEventExpectation{
+				EventExpectation: expectation,
+				BlockID:          block.ID(),
+				BlockHeight:      block.Header.Height,
+			})
+			continue
+		}
+
+		b.conf.ServerLogger.Warn().
+			Uint64("blockHeight", block.Header.Height).
+			Str("expectationID", expectation.ID).
+			Msgf("⚠️  Expected event %s was not emitted", expectation)
+	}
+}
+
+func (b *Blockchain) matchesAnyEvent(expectation EventExpectation, events []flowgo.Event) bool {
+	for _, flowEvent := range events {
+		if string(flowEvent.Type) != expectation.Type {
+			continue
+		}
+
+		if len(expectation.Fields) == 0 {
+			return true
+		}
+
+		event, err := convert.FlowEventToSDK(flowEvent)
+		if err != nil {
+			b.conf.ServerLogger.Error().Err(err).Msg("Failed to decode event")
+			continue
+		}
+
+		if matchesFields(event.Value, expectation.Fields) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesFields(event cadence.Event, fields map[string]string) bool {
+	values := make(map[string]cadence.Value, len(event.Fields))
+	for i, field := range event.EventType.Fields {
+		values[field.Identifier] = event.Fields[i]
+	}
+
+	for name, expected := range fields {
+		value, ok := values[name]
+		if !ok || !matchesValue(value, expected) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesValue(value cadence.Value, expected string) bool {
+	switch value := value.(type) {
+	case cadence.String:
+		return string(value) == expected
+	case cadence.Address:
+		return flowgo.Address(value) == flowgo.HexToAddress(expected)
+	case cadence.Optional:
+		if value.Value == nil {
+			return expected == "nil"
+		}
+		return matchesValue(value.Value, expected)
+	case nil:
+		return expected == "nil"
+	default:
+		return value.String() == expected
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/templates"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestEventExpectations(t *testing.T) {
+
+	t.Parallel()
+
+	const tokenContract = `
+		access(all) contract Token {
+			access(all) event Minted(amount: UFix64, to: Address, memo: String?)
+
+			init() {
+				emit Minted(amount: 10.0, to: self.account.address, memo: "demo")
+			}
+		}
+	`
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	t.Run("invalid matcher", func(t *testing.T) {
+		for name, matcher := range map[string]emulator.EventMatcher{
+			"no type":        {},
+			"invalid report": {Type: "flow.AccountCreated", Report: "panic"},
+		} {
+			_, err := b.ExpectEvent(matcher)
+			var invalidArgumentErr *types.InvalidArgumentError
+			assert.ErrorAs(t, err, &invalidArgumentErr, name)
+		}
+
+		assert.Empty(t, b.EventExpectations())
+	})
+
+	t.Run("matched", func(t *testing.T) {
+		// the address of the account created next is known in advance
+		address, err := b.GetNetworkParameters().ChainID.Chain().AddressAtIndex(5)
+		require.NoError(t, err)
+
+		created, err := b.ExpectEvent(emulator.EventMatcher{
+			Type:   "flow.AccountCreated",
+			Report: emulator.ExpectationReportError,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, emulator.ExpectationReportError, created.Report)
+
+		minted, err := b.ExpectEvent(emulator.EventMatcher{
+			Type: "A." + address.Hex() + ".Token.Minted",
+			Fields: map[string]string{
+				"amount": "10.00000000",
+				"to":     address.Hex(),
+				"memo":   "demo",
+			},
+			Report: emulator.ExpectationReportError,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, emulator.ExpectationReportLog, mustExpect(t, b, "flow.AccountKeyAdded").Report)
+
+		expectations := b.EventExpectations()
+		require.Len(t, expectations, 3)
+		assert.Equal(t, created.ID, expectations[0].ID)
+		assert.Equal(t, minted.ID, expectations[1].ID)
+
+		contracts := []templates.Contract{{Name: "Token", Source: tokenContract}}
+		_, err = adapter.CreateAccount(context.Background(), nil, contracts, 0)
+		require.NoError(t, err)
+
+		assert.Empty(t, b.EventExpectations())
+	})
+
+	t.Run("unmatched", func(t *testing.T) {
+		latest, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+
+		mustExpect(t, b, "flow.AccountContractAdded")
+		_, err = b.ExpectEvent(emulator.EventMatcher{
+			Type:   "flow.AccountCreated",
+			Report: emulator.ExpectationReportError,
+		})
+		require.NoError(t, err)
+
+		// the commit does not fail because of unmatched expectations
+		block, err := b.CommitBlock()
+		require.NoError(t, err)
+		assert.Equal(t, latest.Header.Height+1, block.Header.Height)
+
+		// only the expectations reported as errors are kept
+		unmatched := b.UnmatchedEventExpectations()
+		require.Len(t, unmatched, 1)
+		assert.Equal(t, "flow.AccountCreated", unmatched[0].Type)
+		assert.Equal(t, block.ID(), unmatched[0].BlockID)
+		assert.Equal(t, block.Header.Height, unmatched[0].BlockHeight)
+
+		assert.Empty(t, b.EventExpectations())
+
+		// expectations only apply to the pending block
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+		assert.Len(t, b.UnmatchedEventExpectations(), 1)
+
+		b.ClearUnmatchedEventExpectations()
+		assert.Empty(t, b.UnmatchedEventExpectations())
+	})
+
+	t.Run("auto-mined", func(t *testing.T) {
+		b, err := emulator.New()
+		require.NoError(t, err)
+		b.EnableAutoMine()
+
+		_, err = b.ExpectEvent(emulator.EventMatcher{
+			Type:   "flow.AccountContractAdded",
+			Report: emulator.ExpectationReportError,
+		})
+		require.NoError(t, err)
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction {}`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		// the transaction triggering the commit is not reported as failed
+		err = b.SendTransaction(context.Background(), convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		assert.Len(t, b.UnmatchedEventExpectations(), 1)
+	})
+}
+
+func mustExpect(t *testing.T, b *emulator.Blockchain, eventType string) emulator.EventExpectation {
+	expectation, err := b.ExpectEvent(emulator.EventMatcher{Type: eventType})
+	require.NoError(t, err)
+	return expectation
+}
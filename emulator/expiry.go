@@ -0,0 +1,108 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// ReferenceBlockWindow is the range of blocks new transactions can reference
+// without being rejected as expired.
+type ReferenceBlockWindow struct {
+	// Expiry is the number of blocks after which transactions expire,
+	// zero if transactions do not expire.
+	Expiry uint `json:"expiry"`
+	// ExpiryBuffer is the number of blocks before their expiry transactions are rejected.
+	ExpiryBuffer uint `json:"expiryBuffer"`
+	// ReferenceBlockRequired is true if transactions without a reference block are rejected.
+	ReferenceBlockRequired bool              `json:"referenceBlockRequired"`
+	OldestHeight           uint64            `json:"oldestHeight"`
+	OldestBlockID          flowgo.Identifier `json:"oldestBlockId"`
+	LatestHeight           uint64            `json:"latestHeight"`
+	LatestBlockID          flowgo.Identifier `json:"latestBlockId"`
+}
+
+// ReferenceBlockWindow returns the range of blocks new transactions can reference.
+func (b *Blockchain) ReferenceBlockWindow(ctx context.Context) (*ReferenceBlockWindow, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oldestBlock, err := b.getBlockByHeight(ctx, b.conf.oldestReferenceHeight(latestBlock.Header.Height))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReferenceBlockWindow{
+		Expiry:                 b.conf.TransactionExpiry,
+		ExpiryBuffer:           b.conf.TransactionExpiryBuffer,
+		ReferenceBlockRequired: b.conf.ReferenceBlockRequired,
+		OldestHeight:           oldestBlock.Header.Height,
+		OldestBlockID:          oldestBlock.ID(),
+		LatestHeight:           latestBlock.Header.Height,
+		LatestBlockID:          latestBlock.ID(),
+	}, nil
+}
+
+// oldestReferenceHeight returns the height of the oldest block transactions can
+// reference while the block at the given height is the latest one.
+func (conf config) oldestReferenceHeight(latestHeight uint64) uint64 {
+	if conf.TransactionExpiry == 0 {
+		return 0
+	}
+
+	window := uint64(conf.TransactionExpiry - conf.TransactionExpiryBuffer)
+	if latestHeight < window {
+		return 0
+	}
+
+	return latestHeight - window
+}
+
+// checkScheduledExpiry checks that a valid transaction scheduled at the given height
+// does not expire before the block at that height is executed.
+func (b *Blockchain) checkScheduledExpiry(ctx context.Context, tx flowgo.TransactionBody, height uint64) error {
+	if b.conf.TransactionExpiry == 0 || tx.ReferenceBlockID == flowgo.ZeroID {
+		return nil
+	}
+
+	referenceBlock, err := b.storage.BlockByID(ctx, tx.ReferenceBlockID)
+	if err != nil {
+		return err
+	}
+
+	// the block preceding the scheduled one is the latest when the transaction is included
+	finalHeight := height - 1
+	if referenceBlock.Header.Height < b.conf.oldestReferenceHeight(finalHeight) {
+		return &types.ExpiredTransactionError{
+			RefHeight:   referenceBlock.Header.Height,
+			FinalHeight: finalHeight,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestTransactionExpiry(t *testing.T) {
+
+	t.Parallel()
+
+	setup := func(t *testing.T, opts ...emulator.Option) (*emulator.Blockchain, func(referenceBlockID flowgo.Identifier) *flowgo.TransactionBody) {
+		b, err := emulator.New(opts...)
+		require.NoError(t, err)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		sequenceNumber := b.ServiceKey().SequenceNumber
+
+		newTransaction := func(referenceBlockID flowgo.Identifier) *flowgo.TransactionBody {
+			tx := flowsdk.NewTransaction().
+				SetScript([]byte(`transaction {}`)).
+				SetReferenceBlockID(flowsdk.Identifier(referenceBlockID)).
+				SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+				SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, sequenceNumber).
+				SetPayer(b.ServiceKey().Address)
+			sequenceNumber++
+
+			err := tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+			require.NoError(t, err)
+
+			return convert.SDKTransactionToFlow(*tx)
+		}
+
+		return b, newTransaction
+	}
+
+	commitBlocks := func(t *testing.T, b *emulator.Blockchain, count int) {
+		for i := 0; i < count; i++ {
+			_, err := b.CommitBlock()
+			require.NoError(t, err)
+		}
+	}
+
+	latestBlock := func(t *testing.T, b *emulator.Blockchain) *flowgo.Block {
+		block, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		return block
+	}
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		b, newTransaction := setup(t)
+
+		window, err := b.ReferenceBlockWindow(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint(flowgo.DefaultTransactionExpiry), window.Expiry)
+		assert.False(t, window.ReferenceBlockRequired)
+
+		// transactions without a reference block are accepted
+		err = b.AddTransaction(context.Background(), *newTransaction(flowgo.ZeroID))
+		require.NoError(t, err)
+	})
+
+	t.Run("buffer", func(t *testing.T) {
+		t.Parallel()
+
+		b, newTransaction := setup(t,
+			emulator.WithTransactionExpiry(10),
+			emulator.WithTransactionExpiryBuffer(4),
+		)
+
+		reference := latestBlock(t, b)
+		commitBlocks(t, b, 6)
+
+		window, err := b.ReferenceBlockWindow(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, emulator.ReferenceBlockWindow{
+			Expiry:                 10,
+			ExpiryBuffer:           4,
+			ReferenceBlockRequired: true,
+			OldestHeight:           reference.Header.Height,
+			OldestBlockID:          reference.ID(),
+			LatestHeight:           reference.Header.Height + 6,
+			LatestBlockID:          latestBlock(t, b).ID(),
+		}, *window)
+
+		err = b.AddTransaction(context.Background(), *newTransaction(reference.ID()))
+		require.NoError(t, err)
+		_, _, err = b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+
+		// the transaction would expire in less than the buffer
+		err = b.AddTransaction(context.Background(), *newTransaction(reference.ID()))
+		var expiredErr *types.ExpiredTransactionError
+		require.ErrorAs(t, err, &expiredErr)
+		assert.Equal(t, reference.Header.Height, expiredErr.RefHeight)
+		assert.Equal(t, reference.Header.Height+7, expiredErr.FinalHeight)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		b, newTransaction := setup(t, emulator.WithTransactionExpiry(0))
+
+		reference := latestBlock(t, b)
+		commitBlocks(t, b, 20)
+
+		err := b.AddTransaction(context.Background(), *newTransaction(reference.ID()))
+		require.NoError(t, err)
+
+		window, err := b.ReferenceBlockWindow(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), window.OldestHeight)
+	})
+
+	t.Run("scheduled", func(t *testing.T) {
+		t.Parallel()
+
+		b, newTransaction := setup(t, emulator.WithTransactionExpiry(10))
+
+		reference := latestBlock(t, b)
+
+		// the latest block is the reference block plus 10 when the block at this height is executed
+		err := b.AddScheduledTransaction(context.Background(), *newTransaction(reference.ID()), reference.Header.Height+11)
+		require.NoError(t, err)
+
+		err = b.AddScheduledTransaction(context.Background(), *newTransaction(reference.ID()), reference.Header.Height+12)
+		var expiredErr *types.ExpiredTransactionError
+		require.ErrorAs(t, err, &expiredErr)
+		assert.Equal(t, reference.Header.Height+11, expiredErr.FinalHeight)
+	})
+
+	t.Run("invalid buffer", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := emulator.New(
+			emulator.WithTransactionExpiry(10),
+			emulator.WithTransactionExpiryBuffer(10),
+		)
+		assert.Error(t, err)
+	})
+}
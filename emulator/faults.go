@@ -0,0 +1,284 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/flow-go/fvm"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
+	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// FaultOutcome is what a transaction or script matched by a fault rule returns,
+// instead of being executed.
+type FaultOutcome string
+
+const (
+	// FaultOutcomeFailure fails the transaction or script with an execution error.
+	FaultOutcomeFailure FaultOutcome = "failure"
+	// FaultOutcomeTimeout fails the transaction with an execution error reporting a timeout,
+	// and interrupts the script like the script timeout does.
+	FaultOutcomeTimeout FaultOutcome = "timeout"
+)
+
+// FaultMatcher describes the transactions and scripts a fault is injected into.
+type FaultMatcher struct {
+	// TransactionID matches the transaction with the given ID. Scripts are not matched.
+	TransactionID string `json:"transactionId,omitempty"`
+	// Pattern is a regular expression matched against the code of transactions and scripts.
+	Pattern string `json:"pattern,omitempty"`
+	// Outcome is the fault injected into the matched transactions and scripts.
+	Outcome FaultOutcome `json:"outcome"`
+	// Message is added to the error of the fault.
+	Message string `json:"message,omitempty"`
+	// Count is the number of times the fault is injected before the rule is removed,
+	// unlimited if zero.
+	Count uint64 `json:"count,omitempty"`
+}
+
+// FaultRule is a fault matcher registered with the emulator.
+type FaultRule struct {
+	ID string `json:"id"`
+	FaultMatcher
+	// Injected is the number of times the fault was injected.
+	Injected uint64 `json:"injected"`
+}
+
+type faultRule struct {
+	FaultRule
+	transactionID flowgo.Identifier
+	pattern       *regexp.Regexp
+}
+
+func (r *faultRule) matches(transactionID *flowgo.Identifier, code []byte) bool {
+	if r.TransactionID != "" && (transactionID == nil || *transactionID != r.transactionID) {
+		return false
+	}
+	if r.pattern != nil && !r.pattern.Match(code) {
+		return false
+	}
+	return true
+}
+
+// faults holds the fault rules. It has a lock of its own, as scripts are
+// matched while only the read lock of the emulator is held.
+type faults struct {
+	mu    sync.Mutex
+	rules []*faultRule
+	count uint64
+	// version changes whenever the rules, or the number of faults they injected, change
+	version uint64
+}
+
+// clone returns a copy of the rules, to predict the faults injected into the pending
+// transactions without counting them, along with the version of the rules.
+func (f *faults) clone() (*faults, uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rules := make([]*faultRule, len(f.rules))
+	for i, rule := range f.rules {
+		copied := *rule
+		rules[i] = &copied
+	}
+
+	return &faults{rules: rules}, f.version
+}
+
+// match returns the rule matching the transaction or script, if any,
+// and removes the rule once its count is exhausted.
+func (f *faults) match(transactionID *flowgo.Identifier, code []byte) *FaultRule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, rule := range f.rules {
+		if !rule.matches(transactionID, code) {
+			continue
+		}
+
+		f.version++
+		rule.Injected++
+		if rule.Count > 0 && rule.Injected >= rule.Count {
+			f.rules = append(f.rules[:i], f.rules[i+1:]...)
+		}
+
+		matched := rule.FaultRule
+		return &matched
+	}
+
+	return nil
+}
+
+// faultError is the execution error of a transaction or script matched by the rule.
+func faultError(rule *FaultRule) fvmerrors.CodedError {
+	message := rule.Message
+	if message == "" {
+		message = fmt.Sprintf("injected by fault rule %s", rule.ID)
+	}
+
+	if rule.Outcome == FaultOutcomeTimeout {
+		return fvmerrors.NewCodedError(fvmerrors.ErrCodeExecutionError, "simulated execution timeout: %s", message)
+	}
+	return fvmerrors.NewCodedError(fvmerrors.ErrCodeExecutionError, "simulated execution failure: %s", message)
+}
+
+// faultedContext returns a context executing transactions without their body, which
+// fails with the given error instead. The signatures are still verified, the sequence
+// number of the proposal key is incremented and the fees are deducted, so the ledger
+// changes like for a transaction which failed on its own.
+func (b *Blockchain) faultedContext(ctx fvm.Context, err error) fvm.Context {
+	return fvm.NewContextFromParent(
+		ctx,
+		fvm.WithReusableCadenceRuntimePool(
+			reusableRuntime.NewCustomReusableCadenceRuntimePool(
+				0,
+				b.runtimeConfig,
+				func(config runtime.Config) runtime.Runtime {
+					return faultedRuntime{
+						Runtime: runtime.NewInterpreterRuntime(config),
+						err:     err,
+					}
+				},
+			),
+		),
+	)
+}
+
+// faultedRuntime fails transaction bodies with an error, without executing them.
+type faultedRuntime struct {
+	runtime.Runtime
+	err error
+}
+
+func (r faultedRuntime) NewTransactionExecutor(_ runtime.Script, context runtime.Context) runtime.Executor {
+	// the FVM expects the errors of transaction bodies to be Cadence errors
+	return faultedExecutor{
+		err: runtime.Error{
+			Err:      r.err,
+			Location: context.Location,
+		},
+	}
+}
+
+type faultedExecutor struct {
+	err error
+}
+
+func (e faultedExecutor) Preprocess() error {
+	return nil
+}
+
+func (e faultedExecutor) Execute() error {
+	return e.err
+}
+
+func (e faultedExecutor) Result() (cadence.Value, error) {
+	return nil, e.err
+}
+
+// AddFaultRule registers a rule injecting a fault into the transactions and scripts
+// it matches. Matched transactions and scripts are not executed.
+//
+// Rules are matched in the order they were added, and the first matching rule is applied.
+func (b *Blockchain) AddFaultRule(matcher FaultMatcher) (FaultRule, error) {
+	switch matcher.Outcome {
+	case FaultOutcomeFailure, FaultOutcomeTimeout:
+	default:
+		return FaultRule{}, types.NewInvalidArgumentError(fmt.Sprintf(
+			"invalid outcome %q, must be %q or %q",
+			matcher.Outcome,
+			FaultOutcomeFailure,
+			FaultOutcomeTimeout,
+		))
+	}
+
+	if matcher.TransactionID == "" && matcher.Pattern == "" {
+		return FaultRule{}, types.NewInvalidArgumentError("a transaction ID or a pattern is required")
+	}
+
+	rule := &faultRule{}
+
+	if matcher.TransactionID != "" {
+		id, err := flowgo.HexStringToIdentifier(matcher.TransactionID)
+		if err != nil {
+			return FaultRule{}, types.NewInvalidArgumentError(fmt.Sprintf("invalid transaction ID: %s", err))
+		}
+		rule.transactionID = id
+		matcher.TransactionID = id.String()
+	}
+
+	if matcher.Pattern != "" {
+		pattern, err := regexp.Compile(matcher.Pattern)
+		if err != nil {
+			return FaultRule{}, types.NewInvalidArgumentError(fmt.Sprintf("invalid pattern: %s", err))
+		}
+		rule.pattern = pattern
+	}
+
+	b.faults.mu.Lock()
+	defer b.faults.mu.Unlock()
+
+	b.faults.count++
+	b.faults.version++
+	rule.FaultRule = FaultRule{
+		ID:           fmt.Sprintf("%d", b.faults.count),
+		FaultMatcher: matcher,
+	}
+	b.faults.rules = append(b.faults.rules, rule)
+
+	return rule.FaultRule, nil
+}
+
+// FaultRules returns the registered fault rules, oldest first.
+func (b *Blockchain) FaultRules() []FaultRule {
+	b.faults.mu.Lock()
+	defer b.faults.mu.Unlock()
+
+	rules := make([]FaultRule, len(b.faults.rules))
+	for i, rule := range b.faults.rules {
+		rules[i] = rule.FaultRule
+	}
+
+	return rules
+}
+
+// RemoveFaultRule removes the fault rule with the given ID.
+// It returns false if there is no such rule.
+func (b *Blockchain) RemoveFaultRule(id string) bool {
+	b.faults.mu.Lock()
+	defer b.faults.mu.Unlock()
+
+	for i, rule := range b.faults.rules {
+		if rule.ID == id {
+			b.faults.rules = append(b.faults.rules[:i], b.faults.rules[i+1:]...)
+			b.faults.version++
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,239 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestFaultRules(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	// submit signs the transaction with the service key, adds it to the pending block,
+	// and commits the block
+	submit := func(t *testing.T, script string) (flowgo.Identifier, *types.TransactionResult) {
+		serviceKey := b.ServiceKey()
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(script)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(serviceKey.Address, serviceKey.Index, serviceKey.SequenceNumber).
+			SetPayer(serviceKey.Address)
+
+		signer, err := serviceKey.Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(serviceKey.Address, serviceKey.Index, signer)
+		require.NoError(t, err)
+
+		flowTx := convert.SDKTransactionToFlow(*tx)
+		err = b.AddTransaction(context.Background(), *flowTx)
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		return flowTx.ID(), results[0]
+	}
+
+	t.Run("invalid matcher", func(t *testing.T) {
+		for name, matcher := range map[string]emulator.FaultMatcher{
+			"no outcome":             {Pattern: "log"},
+			"invalid outcome":        {Pattern: "log", Outcome: "crash"},
+			"no match":               {Outcome: emulator.FaultOutcomeFailure},
+			"invalid transaction ID": {TransactionID: "zz", Outcome: emulator.FaultOutcomeFailure},
+			"invalid pattern":        {Pattern: "(", Outcome: emulator.FaultOutcomeFailure},
+		} {
+			_, err := b.AddFaultRule(matcher)
+			var invalidArgumentErr *types.InvalidArgumentError
+			assert.ErrorAs(t, err, &invalidArgumentErr, name)
+		}
+
+		assert.Empty(t, b.FaultRules())
+	})
+
+	t.Run("transaction pattern", func(t *testing.T) {
+		rule, err := b.AddFaultRule(emulator.FaultMatcher{
+			Pattern: `log\("faulty"\)`,
+			Outcome: emulator.FaultOutcomeFailure,
+			Message: "node crashed",
+			Count:   1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []emulator.FaultRule{rule}, b.FaultRules())
+
+		before := b.ServiceKey().SequenceNumber
+
+		_, result := submit(t, `transaction { execute { log("faulty") } }`)
+		require.Error(t, result.Error)
+		assert.Contains(t, result.Error.Error(), "simulated execution failure: node crashed")
+		assert.Empty(t, result.Logs)
+
+		// the sequence number is incremented like for a failed transaction
+		assert.Equal(t, before+1, b.ServiceKey().SequenceNumber)
+
+		// the rule is removed once its count is exhausted
+		assert.Empty(t, b.FaultRules())
+
+		// the next transaction of the proposal key succeeds
+		_, result = submit(t, `transaction { execute { log("faulty") } }`)
+		assert.NoError(t, result.Error)
+		assert.Equal(t, []string{`"faulty"`}, result.Logs)
+	})
+
+	t.Run("transaction ID", func(t *testing.T) {
+		serviceKey := b.ServiceKey()
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction {}`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(serviceKey.Address, serviceKey.Index, serviceKey.SequenceNumber).
+			SetPayer(serviceKey.Address)
+
+		signer, err := serviceKey.Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(serviceKey.Address, serviceKey.Index, signer)
+		require.NoError(t, err)
+
+		rule, err := b.AddFaultRule(emulator.FaultMatcher{
+			TransactionID: tx.ID().String(),
+			Outcome:       emulator.FaultOutcomeTimeout,
+		})
+		require.NoError(t, err)
+
+		id, result := submit(t, `transaction { execute { log("unaffected") } }`)
+		assert.NotEqual(t, flowgo.Identifier(tx.ID()), id)
+		assert.NoError(t, result.Error)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Error(t, results[0].Error)
+		assert.Contains(t, results[0].Error.Error(), "simulated execution timeout")
+
+		// the failed result is committed like any other
+		stored, err := b.GetTransactionResult(context.Background(), flowgo.Identifier(tx.ID()))
+		require.NoError(t, err)
+		assert.Contains(t, stored.ErrorMessage, "simulated execution timeout")
+
+		rules := b.FaultRules()
+		require.Len(t, rules, 1)
+		assert.Equal(t, uint64(1), rules[0].Injected)
+
+		assert.True(t, b.RemoveFaultRule(rule.ID))
+		assert.False(t, b.RemoveFaultRule(rule.ID))
+	})
+
+	t.Run("scripts", func(t *testing.T) {
+		failure, err := b.AddFaultRule(emulator.FaultMatcher{
+			Pattern: "failing",
+			Outcome: emulator.FaultOutcomeFailure,
+		})
+		require.NoError(t, err)
+
+		timeout, err := b.AddFaultRule(emulator.FaultMatcher{
+			Pattern: "slow",
+			Outcome: emulator.FaultOutcomeTimeout,
+		})
+		require.NoError(t, err)
+
+		result, err := b.ExecuteScript(context.Background(), []byte(`pub fun main(): String { return "failing" }`), nil)
+		require.NoError(t, err)
+		require.Error(t, result.Error)
+		assert.Contains(t, result.Error.Error(), "simulated execution failure")
+
+		_, err = b.ExecuteScript(context.Background(), []byte(`pub fun main(): String { return "slow" }`), nil)
+		var interruptedErr *types.ScriptInterruptedError
+		require.ErrorAs(t, err, &interruptedErr)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		result, err = b.ExecuteScript(context.Background(), []byte(`pub fun main(): Int { return 1 }`), nil)
+		require.NoError(t, err)
+		assert.NoError(t, result.Error)
+
+		assert.True(t, b.RemoveFaultRule(failure.ID))
+		assert.True(t, b.RemoveFaultRule(timeout.ID))
+	})
+}
+
+func TestFaultedTransactionFees(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithTransactionFeesEnabled(true),
+	)
+	require.NoError(t, err)
+
+	_, err = b.AddFaultRule(emulator.FaultMatcher{
+		Pattern: "faulty",
+		Outcome: emulator.FaultOutcomeFailure,
+	})
+	require.NoError(t, err)
+
+	serviceKey := b.ServiceKey()
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(`transaction { execute { log("faulty") } }`)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(serviceKey.Address, serviceKey.Index, serviceKey.SequenceNumber).
+		SetPayer(serviceKey.Address)
+
+	signer, err := serviceKey.Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(serviceKey.Address, serviceKey.Index, signer)
+	require.NoError(t, err)
+
+	err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+	require.NoError(t, err)
+
+	_, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Error(t, results[0].Error)
+
+	// the fees are deducted like for a failed transaction
+	var feesDeducted bool
+	for _, event := range results[0].Events {
+		if strings.HasSuffix(event.Type, ".FlowFees.FeesDeducted") {
+			feesDeducted = true
+		}
+	}
+	assert.True(t, feesDeducted)
+}
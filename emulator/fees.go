@@ -0,0 +1,213 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package emulator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/blueprints"
+	"github.com/onflow/flow-go/fvm/environment"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// FeeParameters are the parameters of transaction fees, kept by the FlowFees contract.
+//
+// The fees of a transaction are the sum of its inclusion effort times the inclusion
+// effort cost, and its execution effort times the execution effort cost, multiplied
+// by the surge factor.
+type FeeParameters struct {
+	SurgeFactor         cadence.UFix64
+	InclusionEffortCost cadence.UFix64
+	ExecutionEffortCost cadence.UFix64
+}
+
+// DefaultFeeParameters are the fee parameters of new emulators, taken from fvm.DefaultTransactionFees.
+var DefaultFeeParameters = FeeParameters(fvm.DefaultTransactionFees)
+
+// DefaultExecutionEffortWeights are the execution effort weights of new emulators.
+//
+// Kinds of computation without a weight do not add to the execution effort.
+var DefaultExecutionEffortWeights = map[common.ComputationKind]uint64{
+	common.ComputationKindStatement:          1569,
+	common.ComputationKindLoop:               1569,
+	common.ComputationKindFunctionInvocation: 1569,
+	environment.ComputationKindGetValue:      808,
+	environment.ComputationKindCreateAccount: 2837670,
+	environment.ComputationKindSetValue:      765,
+}
+
+const feeParametersScript = `
+import FlowFees from %s
+
+pub fun main(): [UFix64] {
+    let parameters = FlowFees.getFeeParameters()
+    return [parameters.surgeFactor, parameters.inclusionEffortCost, parameters.executionEffortCost]
+}
+`
+
+const executionEffortWeightsScript = `
+pub fun main(): {UInt64: UInt64} {
+    return getAuthAccount(%s).copy<{UInt64: UInt64}>(from: %s) ?? {}
+}
+`
+
+// FeeParameters returns the fee parameters at the latest block.
+func (b *Blockchain) FeeParameters(ctx context.Context) (*FeeParameters, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	script := fmt.Sprintf(feeParametersScript, environment.FlowFeesAddress(b.GetChain()).HexWithPrefix())
+
+	value, err := b.executeLatestBlockScript(ctx, script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee parameters: %w", err)
+	}
+
+	parameters, ok := value.(cadence.Array)
+	if !ok || len(parameters.Values) != 3 {
+		return nil, fmt.Errorf("unexpected fee parameters: %s", value)
+	}
+
+	return &FeeParameters{
+		SurgeFactor:         parameters.Values[0].(cadence.UFix64),
+		InclusionEffortCost: parameters.Values[1].(cadence.UFix64),
+		ExecutionEffortCost: parameters.Values[2].(cadence.UFix64),
+	}, nil
+}
+
+// SetFeeParameters changes the fee parameters, with a transaction of the service account
+// in a block of its own. Transactions are only charged fees if transaction fees are enabled.
+func (b *Blockchain) SetFeeParameters(ctx context.Context, parameters FeeParameters) (*types.TransactionResult, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.conf.ReadOnly {
+		return nil, &types.ReadOnlyError{}
+	}
+
+	chain := b.GetChain()
+	tx := blueprints.SetupFeesTransaction(
+		chain.ServiceAddress(),
+		environment.FlowFeesAddress(chain),
+		parameters.SurgeFactor,
+		parameters.InclusionEffortCost,
+		parameters.ExecutionEffortCost,
+	)
+
+	return b.commitServiceAccountTransaction(ctx, tx)
+}
+
+// ExecutionEffortWeights returns the execution effort weights at the latest block,
+// by kind of computation.
+func (b *Blockchain) ExecutionEffortWeights(ctx context.Context) (map[common.ComputationKind]uint64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	script := fmt.Sprintf(
+		executionEffortWeightsScript,
+		b.GetChain().ServiceAddress().HexWithPrefix(),
+		blueprints.TransactionFeesExecutionEffortWeightsPath,
+	)
+
+	value, err := b.executeLatestBlockScript(ctx, script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution effort weights: %w", err)
+	}
+
+	dictionary, ok := value.(cadence.Dictionary)
+	if !ok {
+		return nil, fmt.Errorf("unexpected execution effort weights: %s", value)
+	}
+
+	weights := make(map[common.ComputationKind]uint64, len(dictionary.Pairs))
+	for _, pair := range dictionary.Pairs {
+		weights[common.ComputationKind(pair.Key.(cadence.UInt64))] = uint64(pair.Value.(cadence.UInt64))
+	}
+
+	return weights, nil
+}
+
+// SetExecutionEffortWeights replaces the execution effort weights, with a transaction
+// of the service account in a block of its own. Kinds of computation without a weight
+// do not add to the execution effort of transactions executed afterwards.
+func (b *Blockchain) SetExecutionEffortWeights(
+	ctx context.Context,
+	weights map[common.ComputationKind]uint64,
+) (*types.TransactionResult, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.conf.ReadOnly {
+		return nil, &types.ReadOnlyError{}
+	}
+
+	uintWeights := make(map[uint]uint64, len(weights))
+	for kind, weight := range weights {
+		uintWeights[uint(kind)] = weight
+	}
+
+	tx, err := blueprints.SetExecutionEffortWeightsTransaction(b.GetChain().ServiceAddress(), uintWeights)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.commitServiceAccountTransaction(ctx, tx)
+}
+
+// commitServiceAccountTransaction executes the given transaction on behalf of the service
+// account in a block of its own. The caller must hold the lock.
+func (b *Blockchain) commitServiceAccountTransaction(
+	ctx context.Context,
+	tx *flowgo.TransactionBody,
+) (*types.TransactionResult, error) {
+	results, err := b.commitAccountTransactions(ctx, []accountTransaction{{
+		address:   b.GetChain().ServiceAddress(),
+		script:    tx.Script,
+		arguments: tx.Arguments,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return results[0], nil
+}
+
+// executeLatestBlockScript executes the given script at the latest block, and returns its value.
+// The caller must hold the lock.
+func (b *Blockchain) executeLatestBlockScript(ctx context.Context, script string) (cadence.Value, error) {
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := b.executeScriptAtBlockID(ctx, []byte(script), nil, latestBlock.ID())
+	if err != nil {
+		return nil, err
+	}
+	if !result.Succeeded() {
+		return nil, result.Error
+	}
+
+	return result.Value, nil
+}
@@ -0,0 +1,163 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+func TestFeeParameters(t *testing.T) {
+
+	t.Parallel()
+
+	surgeFactor, err := cadence.NewUFix64("2.0")
+	require.NoError(t, err)
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		parameters, err := b.FeeParameters(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, emulator.DefaultFeeParameters, *parameters)
+
+		weights, err := b.ExecutionEffortWeights(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, emulator.DefaultExecutionEffortWeights, weights)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		t.Parallel()
+
+		configured := emulator.DefaultFeeParameters
+		configured.SurgeFactor = surgeFactor
+
+		b, err := emulator.New(
+			emulator.WithFeeParameters(configured),
+			emulator.WithExecutionEffortWeights(map[common.ComputationKind]uint64{
+				common.ComputationKindStatement: 1 << 16,
+			}),
+		)
+		require.NoError(t, err)
+
+		parameters, err := b.FeeParameters(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, configured, *parameters)
+
+		weights, err := b.ExecutionEffortWeights(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[common.ComputationKind]uint64{common.ComputationKindStatement: 1 << 16}, weights)
+	})
+
+	t.Run("set fee parameters", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		changed := emulator.DefaultFeeParameters
+		changed.SurgeFactor = surgeFactor
+
+		result, err := b.SetFeeParameters(context.Background(), changed)
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+
+		parameters, err := b.FeeParameters(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, changed, *parameters)
+	})
+
+	t.Run("set execution effort weights", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		logger := zerolog.Nop()
+		adapter := adapters.NewSDKAdapter(&logger, b)
+
+		loop := []byte(`
+            transaction {
+                execute {
+                    var i = 0
+                    while i < 100 {
+                        i = i + 1
+                    }
+                }
+            }
+        `)
+
+		before := computationUsed(t, b, adapter, loop)
+
+		weights := make(map[common.ComputationKind]uint64, len(emulator.DefaultExecutionEffortWeights))
+		for kind, weight := range emulator.DefaultExecutionEffortWeights {
+			weights[kind] = weight * 2
+		}
+
+		result, err := b.SetExecutionEffortWeights(context.Background(), weights)
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+
+		stored, err := b.ExecutionEffortWeights(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, weights, stored)
+
+		after := computationUsed(t, b, adapter, loop)
+		assert.Greater(t, after, before)
+	})
+}
+
+func computationUsed(t *testing.T, b *emulator.Blockchain, adapter *adapters.SDKAdapter, script []byte) uint64 {
+	tx := flowsdk.NewTransaction().
+		SetScript(script).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = adapter.SendTransaction(context.Background(), *tx)
+	require.NoError(t, err)
+
+	result, err := b.ExecuteNextTransaction()
+	require.NoError(t, err)
+	AssertTransactionSucceeded(t, result)
+
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+
+	return result.ComputationUsed
+}
@@ -0,0 +1,63 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+
+	"github.com/onflow/flow-emulator/storage/fork"
+)
+
+// Fork returns a blockchain continuing from the latest committed block of this one.
+//
+// The fork shares the committed state of the blockchain, copied on write: the blocks
+// committed to the fork are kept in memory and are not visible to the blockchain, and
+// the blocks committed to the blockchain afterwards are not visible to the fork.
+// Forks can be used concurrently, e.g. to run tests in parallel from one setup,
+// and are discarded once no longer referenced. The blockchain must not be rolled
+// back below the height of its forks while they are used.
+//
+// The fork has the configuration of the blockchain, but does not deploy the configured
+// contracts again, produce blocks on a timer, create automatic snapshots or report code
+// coverage. The transactions of the pending block are not included in the fork.
+func (b *Blockchain) Fork() (*Blockchain, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	ctx := context.Background()
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := fork.New(ctx, b.storage, latestBlock.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := b.conf
+	conf.Store = store
+	conf.Contracts = nil
+	conf.BlockTime = 0
+	conf.AutoSnapshotInterval = 0
+	conf.CoverageReport = nil
+
+	return newBlockchain(conf)
+}
@@ -0,0 +1,153 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/cadence"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/memstore"
+)
+
+func TestFork(t *testing.T) {
+
+	t.Parallel()
+
+	stores := map[string]func() storage.Store{
+		"default":  func() storage.Store { return nil },
+		"memstore": func() storage.Store { return memstore.New() },
+	}
+
+	for name, newStore := range stores {
+		newStore := newStore
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			initialBalance, err := cadence.NewUFix64("10.0")
+			require.NoError(t, err)
+
+			options := []emulator.Option{
+				emulator.WithStorageLimitEnabled(false),
+				emulator.WithBootstrapAccounts(1, initialBalance),
+			}
+			if store := newStore(); store != nil {
+				options = append(options, emulator.WithStore(store))
+			}
+
+			parent, err := emulator.New(options...)
+			require.NoError(t, err)
+
+			logger := zerolog.Nop()
+			parentAdapter := adapters.NewSDKAdapter(&logger, parent)
+
+			addTwoScript, counterAddress := DeployAndGenerateAddTwoScript(t, parentAdapter)
+			IncrementHelper(t, parent, parentAdapter, counterAddress, addTwoScript, 2)
+
+			forkedBlock, err := parent.GetLatestBlock(context.Background())
+			require.NoError(t, err)
+
+			for i := 0; i < 3; i++ {
+				t.Run("fork", func(t *testing.T) {
+					t.Parallel()
+
+					b, err := parent.Fork()
+					require.NoError(t, err)
+
+					adapter := adapters.NewSDKAdapter(&logger, b)
+
+					latestBlock, err := b.GetLatestBlock(context.Background())
+					require.NoError(t, err)
+					assert.Equal(t, forkedBlock.ID(), latestBlock.ID())
+					assert.Equal(t, parent.TestAccounts(), b.TestAccounts())
+
+					// the fork continues from the state of the parent, and its blocks are its own
+					IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 4)
+					IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 6)
+
+					block, err := b.GetBlockByHeight(context.Background(), forkedBlock.Header.Height)
+					require.NoError(t, err)
+					assert.Equal(t, forkedBlock.ID(), block.ID())
+
+					// the fork can be rolled back to where it was forked, not below
+					require.NoError(t, b.RollbackToBlockHeight(forkedBlock.Header.Height))
+					IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 4)
+					assert.Error(t, b.RollbackToBlockHeight(forkedBlock.Header.Height-1))
+				})
+			}
+
+			t.Cleanup(func() {
+				// the blocks of the forks are not committed to the parent
+				latestBlock, err := parent.GetLatestBlock(context.Background())
+				require.NoError(t, err)
+				assert.Equal(t, forkedBlock.ID(), latestBlock.ID())
+
+				IncrementHelper(t, parent, parentAdapter, counterAddress, addTwoScript, 4)
+			})
+		})
+	}
+}
+
+func TestForkDoesNotSeeLaterParentBlocks(t *testing.T) {
+
+	t.Parallel()
+
+	parent, err := emulator.New(emulator.WithStorageLimitEnabled(false))
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	parentAdapter := adapters.NewSDKAdapter(&logger, parent)
+
+	addTwoScript, counterAddress := DeployAndGenerateAddTwoScript(t, parentAdapter)
+
+	b, err := parent.Fork()
+	require.NoError(t, err)
+
+	IncrementHelper(t, parent, parentAdapter, counterAddress, addTwoScript, 2)
+
+	parentBlock, err := parent.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	txs, err := parent.GetTransactionsByBlockID(context.Background(), parentBlock.ID())
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+
+	_, err = b.GetBlockByID(context.Background(), parentBlock.ID())
+	assert.Error(t, err)
+
+	_, err = b.GetTransaction(context.Background(), txs[0].ID())
+	assert.Error(t, err)
+
+	// the same transaction is executed in a block of the fork
+	adapter := adapters.NewSDKAdapter(&logger, b)
+	IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 2)
+
+	result, err := adapter.GetTransactionResult(context.Background(), flowsdk.Identifier(txs[0].ID()))
+	require.NoError(t, err)
+	assert.NotEqual(t, flowsdk.Identifier(parentBlock.ID()), result.BlockID)
+}
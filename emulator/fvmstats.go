@@ -0,0 +1,164 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"sync/atomic"
+
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// transactionRuntimePoolSize is the number of runtimes the transaction runtime
+// pool keeps for reuse.
+const transactionRuntimePoolSize = 1
+
+// FVMStats reports how efficiently the FVM reuses its caches and runtimes.
+type FVMStats struct {
+	ProgramsCacheHits    uint64  `json:"programsCacheHits"`
+	ProgramsCacheMisses  uint64  `json:"programsCacheMisses"`
+	ProgramsCacheHitRate float64 `json:"programsCacheHitRate"`
+	TransactionsExecuted uint64  `json:"transactionsExecuted"`
+	RuntimePoolSize      int     `json:"runtimePoolSize"`
+	RuntimePoolMisses    uint64  `json:"runtimePoolMisses"`
+	LedgerViews          uint64  `json:"ledgerViews"`
+}
+
+// fvmStats collects the FVM statistics of a blockchain. It is registered as
+// the metrics reporter of the FVM context, which reports program cache hits
+// and misses. The runtime pool counts the runtimes it has to create, and the
+// blockchain records the transactions and ledger views.
+type fvmStats struct {
+	environment.NoopMetricsReporter
+
+	programsCacheHits   atomic.Uint64
+	programsCacheMisses atomic.Uint64
+	transactions        atomic.Uint64
+	runtimePoolMisses   atomic.Uint64
+	ledgerViews         atomic.Uint64
+}
+
+var _ environment.MetricsReporter = &fvmStats{}
+
+func (s *fvmStats) RuntimeTransactionProgramsCacheHit() {
+	s.programsCacheHits.Add(1)
+}
+
+func (s *fvmStats) RuntimeTransactionProgramsCacheMiss() {
+	s.programsCacheMisses.Add(1)
+}
+
+func (s *fvmStats) transactionExecuted() {
+	s.transactions.Add(1)
+}
+
+func (s *fvmStats) runtimePoolMiss() {
+	s.runtimePoolMisses.Add(1)
+}
+
+func (s *fvmStats) ledgerViewCreated() {
+	s.ledgerViews.Add(1)
+}
+
+// collectors returns the Prometheus counters of the statistics of this blockchain,
+// which read the statistics when they are collected.
+func (s *fvmStats) collectors() []prometheus.Collector {
+	counter := func(name string, help string, value *atomic.Uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Namespace: "emulator",
+				Subsystem: "fvm",
+				Name:      name,
+				Help:      help,
+			},
+			func() float64 {
+				return float64(value.Load())
+			},
+		)
+	}
+
+	return []prometheus.Collector{
+		counter(
+			"programs_cache_hits_total",
+			"Number of programs found in the derived data cache.",
+			&s.programsCacheHits,
+		),
+		counter(
+			"programs_cache_misses_total",
+			"Number of programs parsed and checked because they were not cached.",
+			&s.programsCacheMisses,
+		),
+		counter(
+			"runtime_pool_transactions_total",
+			"Number of transactions executed with runtimes of the transaction runtime pool.",
+			&s.transactions,
+		),
+		counter(
+			"runtime_pool_misses_total",
+			"Number of runtimes created because the transaction runtime pool was empty.",
+			&s.runtimePoolMisses,
+		),
+		counter(
+			"ledger_views_total",
+			"Number of child views created on the ledger to execute procedures.",
+			&s.ledgerViews,
+		),
+	}
+}
+
+// register registers the counters of the statistics with the given registerer,
+// and returns a function unregistering them.
+func (s *fvmStats) register(registerer prometheus.Registerer) (func(), error) {
+	// only the registered counters are unregistered, as counters are unregistered by
+	// their description, which equal counters of other blockchains may share
+	var registered []prometheus.Collector
+	unregister := func() {
+		for _, collector := range registered {
+			registerer.Unregister(collector)
+		}
+	}
+
+	for _, collector := range s.collectors() {
+		err := registerer.Register(collector)
+		if err != nil {
+			unregister()
+			return nil, err
+		}
+		registered = append(registered, collector)
+	}
+
+	return unregister, nil
+}
+
+func (s *fvmStats) snapshot() FVMStats {
+	stats := FVMStats{
+		ProgramsCacheHits:    s.programsCacheHits.Load(),
+		ProgramsCacheMisses:  s.programsCacheMisses.Load(),
+		TransactionsExecuted: s.transactions.Load(),
+		RuntimePoolSize:      transactionRuntimePoolSize,
+		RuntimePoolMisses:    s.runtimePoolMisses.Load(),
+		LedgerViews:          s.ledgerViews.Load(),
+	}
+
+	if lookups := stats.ProgramsCacheHits + stats.ProgramsCacheMisses; lookups > 0 {
+		stats.ProgramsCacheHitRate = float64(stats.ProgramsCacheHits) / float64(lookups)
+	}
+
+	return stats
+}
@@ -0,0 +1,115 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+func TestFVMStats(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithStorageLimitEnabled(false),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	addTwoScript, _ := DeployAndGenerateAddTwoScript(t, adapter)
+
+	before := b.FVMStats()
+	assert.Equal(t, 1, before.RuntimePoolSize)
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(addTwoScript)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address).
+		AddAuthorizer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = adapter.SendTransaction(context.Background(), *tx)
+	require.NoError(t, err)
+
+	txResult, err := b.ExecuteNextTransaction()
+	require.NoError(t, err)
+	AssertTransactionSucceeded(t, txResult)
+
+	after := b.FVMStats()
+
+	assert.Equal(t, before.TransactionsExecuted+1, after.TransactionsExecuted)
+	assert.Greater(t, after.LedgerViews, before.LedgerViews)
+	// the imported contract is parsed and checked, as it is not cached across transactions
+	assert.Greater(t, after.ProgramsCacheMisses, before.ProgramsCacheMisses)
+	assert.LessOrEqual(t, after.ProgramsCacheHitRate, 1.0)
+	assert.Positive(t, after.RuntimePoolMisses)
+}
+
+func TestFVMStatsMetrics(t *testing.T) {
+
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	b, err := emulator.New(
+		emulator.WithMetricsRegisterer(registry),
+	)
+	require.NoError(t, err)
+
+	// the metrics of a second blockchain cannot be registered without distinct labels
+	_, err = emulator.New(
+		emulator.WithMetricsRegisterer(registry),
+	)
+	require.Error(t, err)
+
+	metrics, err := registry.Gather()
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		names = append(names, metric.GetName())
+	}
+	assert.Contains(t, names, "emulator_fvm_ledger_views_total")
+	assert.Contains(t, names, "emulator_fvm_programs_cache_hits_total")
+
+	b.Close()
+
+	metrics, err = registry.Gather()
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+}
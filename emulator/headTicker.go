@@ -0,0 +1,58 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"time"
+)
+
+// HeadTicker periodically moves a replica onto the latest block committed
+// to its shared storage by the writer.
+type HeadTicker struct {
+	emulator Emulator
+	ticker   *time.Ticker
+	done     chan bool
+}
+
+func NewHeadTicker(
+	emulator Emulator,
+	interval time.Duration,
+) *HeadTicker {
+	return &HeadTicker{
+		emulator: emulator,
+		ticker:   time.NewTicker(interval),
+		done:     make(chan bool, 1),
+	}
+}
+
+func (t *HeadTicker) Start() error {
+	for {
+		select {
+		case <-t.ticker.C:
+			_ = t.emulator.SyncHead(context.Background())
+		case <-t.done:
+			return nil
+		}
+	}
+}
+
+func (t *HeadTicker) Stop() {
+	t.done <- true
+}
@@ -0,0 +1,115 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"sort"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/fvm/storage/state"
+	"github.com/onflow/flow-go/fvm/tracing"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// InboxEntry is a capability published to the inbox of an account,
+// waiting to be claimed by its recipient.
+type InboxEntry struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Recipient string `json:"recipient"`
+}
+
+// AccountInbox returns the capabilities published by the given account
+// at the latest block which have not been claimed or unpublished yet,
+// sorted by name.
+func (b *Blockchain) AccountInbox(ctx context.Context, address flowgo.Address) ([]InboxEntry, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// also ensures the account exists
+	_, err := b.getAccount(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger, err := b.storage.LedgerByHeight(ctx, latestBlock.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	b.fvmStats.ledgerViewCreated()
+	txnState := state.NewTransactionState(ledger, state.DefaultParameters())
+	valueStore := environment.NewValueStore(
+		tracing.NewTracerSpan(),
+		environment.NewMeter(txnState),
+		environment.NewAccounts(txnState),
+	)
+	storage := runtime.NewStorage(valueStore, nil)
+
+	entries := make([]InboxEntry, 0)
+
+	storageMap := storage.GetStorageMap(common.Address(address), stdlib.InboxStorageDomain, false)
+	if storageMap == nil {
+		return entries, nil
+	}
+
+	iterator := storageMap.Iterator(nil)
+	for key, value := iterator.Next(); key != nil; key, value = iterator.Next() {
+		published, ok := value.(*interpreter.PublishedValue)
+		if !ok {
+			continue
+		}
+
+		entries = append(entries, InboxEntry{
+			Name:      string(key.(interpreter.StringAtreeValue)),
+			Type:      capabilityType(published.Value).String(),
+			Recipient: published.Recipient.ToAddress().HexWithPrefix(),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries, nil
+}
+
+// capabilityType returns the static type of the given capability,
+// without requiring an interpreter to resolve it.
+func capabilityType(capability interpreter.CapabilityValue) interpreter.StaticType {
+	var borrowType interpreter.StaticType
+	switch capability := capability.(type) {
+	case *interpreter.PathCapabilityValue:
+		borrowType = capability.BorrowType
+	case *interpreter.IDCapabilityValue:
+		borrowType = capability.BorrowType
+	}
+
+	return interpreter.NewCapabilityStaticType(nil, borrowType)
+}
@@ -36,6 +36,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/memstore"
 	"github.com/onflow/flow-emulator/storage/sqlite"
 )
 
@@ -62,7 +64,7 @@ func TestInitialization(t *testing.T) {
 
 		assert.NotNil(t, serviceAcct)
 
-		latestBlock, err := b.GetLatestBlock()
+		latestBlock, err := b.GetLatestBlock(context.Background())
 		require.NoError(t, err)
 
 		assert.EqualValues(t, 0, latestBlock.Header.Height)
@@ -93,7 +95,7 @@ func TestInitialization(t *testing.T) {
 			},
 		}
 
-		counterAddress, err := adapter.CreateAccount(context.Background(), nil, contracts)
+		counterAddress, err := adapter.CreateAccount(context.Background(), nil, contracts, 0)
 		require.NoError(t, err)
 
 		// Submit a transaction adds some ledger state and event state
@@ -195,10 +197,52 @@ func TestInitialization(t *testing.T) {
 				b.ServiceKey().Address,
 			)
 
-			result, err := b.ExecuteScript([]byte(readScript), nil)
+			result, err := b.ExecuteScript(context.Background(), []byte(readScript), nil)
 			require.NoError(t, err)
 
 			assert.Equal(t, cadence.NewInt(1), result.Value)
 		})
 	})
+
+	t.Run("should refuse store created for another chain", func(t *testing.T) {
+
+		t.Parallel()
+
+		store := memstore.New()
+
+		_, err := emulator.New(emulator.WithStore(store))
+		require.NoError(t, err)
+
+		_, err = emulator.New(
+			emulator.WithStore(store),
+			emulator.WithChainID(flowgo.Testnet),
+		)
+
+		var incompatibleErr *storage.IncompatibleStoreError
+		require.ErrorAs(t, err, &incompatibleErr)
+		assert.Equal(t, flowgo.Emulator.String(), incompatibleErr.Stored)
+		assert.Equal(t, flowgo.Testnet.String(), incompatibleErr.Configured)
+
+		_, err = emulator.New(emulator.WithStore(store))
+		require.NoError(t, err)
+	})
+
+	t.Run("should refuse store written by a newer emulator", func(t *testing.T) {
+
+		t.Parallel()
+
+		store := memstore.New()
+
+		_, err := emulator.New(emulator.WithStore(store))
+		require.NoError(t, err)
+
+		err = store.PutMeta(context.Background(), "config", []byte(`{"version":1000,"chainID":"flow-emulator"}`))
+		require.NoError(t, err)
+
+		_, err = emulator.New(emulator.WithStore(store))
+
+		var versionErr *storage.UnsupportedSchemaVersionError
+		require.ErrorAs(t, err, &versionErr)
+		assert.Equal(t, uint64(1000), versionErr.Version)
+	})
 }
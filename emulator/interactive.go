@@ -0,0 +1,380 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/flow-go/fvm"
+	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// DefaultInteractiveExecutionTimeout is how long an interactive execution may
+// stay paused before it is aborted, unless configured otherwise.
+const DefaultInteractiveExecutionTimeout = time.Minute
+
+// ExecutionStop is a statement of a transaction at which an interactive
+// execution is paused, before the statement is executed.
+type ExecutionStop struct {
+	Location  common.Location
+	Line      int
+	Statement ast.Statement
+	// Interpreter executes the statement. It can be used to inspect the values
+	// of the execution, e.g. the account storage, while it is paused.
+	Interpreter *interpreter.Interpreter
+}
+
+// InteractiveExecution is the execution of the next transaction of the pending
+// block, paused at the statement boundaries of the transaction.
+//
+// The emulator is locked until the execution finished, so the execution must
+// be run to completion with Next or Resume, or aborted with Abort. An execution
+// left paused for longer than the interactive execution timeout is aborted,
+// so an abandoned execution does not lock the emulator forever.
+type InteractiveExecution struct {
+	blockchain  *Blockchain
+	transaction *flowgo.TransactionBody
+	debugger    *interpreter.Debugger
+	cancel      context.CancelFunc
+	// inBody is true while the transaction body executes. Statements executed
+	// by the FVM around it, e.g. to deduct fees, are not paused at.
+	inBody *atomic.Bool
+	writes *registerWrites
+	done   chan interactiveOutcome
+
+	// mu serializes the calls of the client with the abort of an idle execution.
+	mu sync.Mutex
+	// generation is incremented by every call of the client, so an idle timer
+	// armed before the call does not abort the execution.
+	generation uint64
+	idle       *time.Timer
+
+	stop     *ExecutionStop
+	finished bool
+	result   *types.TransactionResult
+	err      error
+}
+
+type interactiveOutcome struct {
+	result *types.TransactionResult
+	err    error
+}
+
+// ExecuteNextTransactionInteractive starts the execution of the next transaction
+// of the pending block, paused at its first statement.
+//
+// The execution is recorded in the pending block like with ExecuteNextTransaction
+// once it finished. It cannot be started while a debugging session is running.
+func (b *Blockchain) ExecuteNextTransactionInteractive() (*InteractiveExecution, error) {
+	b.mu.Lock()
+
+	if b.activeDebuggingSession {
+		b.unlock()
+		return nil, fmt.Errorf("cannot execute a transaction interactively while a debugging session is running")
+	}
+
+	if b.pendingBlock.ExecutionComplete() {
+		b.unlock()
+		return nil, &types.PendingBlockTransactionsExhaustedError{
+			BlockID: b.pendingBlock.ID(),
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	execution := &InteractiveExecution{
+		blockchain:  b,
+		transaction: b.pendingBlock.NextTransaction(),
+		debugger:    interpreter.NewDebugger(),
+		cancel:      cancel,
+		inBody:      &atomic.Bool{},
+		writes:      &registerWrites{values: map[flowgo.RegisterID]flowgo.RegisterValue{}},
+		done:        make(chan interactiveOutcome, 1),
+	}
+
+	header := b.pendingBlock.Block().Header
+	blockContext := fvm.NewContextFromParent(
+		b.newFVMContextFromHeader(header),
+		fvm.WithReusableCadenceRuntimePool(execution.runtimePool(ctx)),
+	)
+
+	execution.debugger.RequestPause()
+
+	go func() {
+		result, err := b.executeNextTransaction(blockContext)
+		execution.done <- interactiveOutcome{result: result, err: err}
+	}()
+
+	execution.wait(true)
+	execution.armIdleTimer()
+
+	return execution, nil
+}
+
+// Transaction returns the transaction being executed.
+func (e *InteractiveExecution) Transaction() *flowgo.TransactionBody {
+	return e.transaction
+}
+
+// Stop returns the statement the execution is paused at, or nil once it finished.
+func (e *InteractiveExecution) Stop() *ExecutionStop {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.stop
+}
+
+// Delta returns the registers written by the transaction so far.
+//
+// Cadence keeps the changes to account storage in memory until the transaction
+// body completed, so they are only part of the delta afterwards. Before, they
+// can be read with the interpreter of the stop.
+func (e *InteractiveExecution) Delta() map[flowgo.RegisterID]flowgo.RegisterValue {
+	return e.writes.copy()
+}
+
+// Next executes the statement the execution is paused at, and pauses at the next
+// statement. It returns nil once the execution finished.
+func (e *InteractiveExecution) Next() (*ExecutionStop, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.generation++
+	if e.finished {
+		return nil, e.err
+	}
+
+	e.debugger.RequestPause()
+	e.debugger.Continue()
+	e.wait(true)
+
+	if e.finished {
+		return nil, e.err
+	}
+	e.armIdleTimer()
+	return e.stop, nil
+}
+
+// Resume executes the rest of the transaction without pausing, and returns its result.
+func (e *InteractiveExecution) Resume() (*types.TransactionResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.generation++
+	if !e.finished {
+		e.debugger.Continue()
+		e.wait(false)
+	}
+
+	return e.result, e.err
+}
+
+// Abort interrupts the transaction, which fails, and returns its result.
+func (e *InteractiveExecution) Abort() (*types.TransactionResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.generation++
+	e.abort()
+
+	return e.result, e.err
+}
+
+func (e *InteractiveExecution) abort() {
+	if !e.finished {
+		e.cancel()
+		e.debugger.Continue()
+		e.wait(false)
+	}
+}
+
+// armIdleTimer aborts the execution if the client does not call it again
+// before the interactive execution timeout.
+func (e *InteractiveExecution) armIdleTimer() {
+	b := e.blockchain
+	timeout := b.conf.InteractiveExecutionTimeout
+	if timeout <= 0 {
+		timeout = DefaultInteractiveExecutionTimeout
+	}
+
+	generation := e.generation
+	e.idle = time.AfterFunc(timeout, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		if e.finished || e.generation != generation {
+			return
+		}
+
+		b.conf.ServerLogger.Warn().
+			Str("txID", e.transaction.ID().String()).
+			Msgf("❗  Aborting interactive execution idle for %s", timeout)
+		e.abort()
+	})
+}
+
+// wait waits until the execution pauses at a statement of the transaction
+// body, or until it finished if step is false.
+func (e *InteractiveExecution) wait(step bool) {
+	for {
+		select {
+		case stop := <-e.debugger.Stops():
+			if step && e.inBody.Load() {
+				e.stop = &ExecutionStop{
+					Location:    stop.Interpreter.Location,
+					Line:        stop.Statement.StartPosition().Line,
+					Statement:   stop.Statement,
+					Interpreter: stop.Interpreter,
+				}
+				return
+			}
+
+			// the pause was consumed by a statement which is not paused at
+			if step {
+				e.debugger.RequestPause()
+			}
+			e.debugger.Continue()
+
+		case outcome := <-e.done:
+			e.stop = nil
+			e.finished = true
+			e.result = outcome.result
+			e.err = outcome.err
+			e.cancel()
+			if e.idle != nil {
+				e.idle.Stop()
+			}
+			e.blockchain.unlock()
+			return
+		}
+	}
+}
+
+// runtimePool returns a runtime pool for the execution, whose runtimes pause at
+// the statements of the transaction body, record its writes and abort it once
+// the given context is done.
+func (e *InteractiveExecution) runtimePool(ctx context.Context) reusableRuntime.ReusableCadenceRuntimePool {
+	b := e.blockchain
+
+	config := b.runtimeConfig
+	config.Debugger = e.debugger
+
+	base := &CoverageReportedRuntime{
+		Runtime:        runtime.NewInterpreterRuntime(config),
+		CoverageReport: b.coverageReportedRuntime.CoverageReport,
+		Environment:    runtime.NewBaseInterpreterEnvironment(config),
+	}
+
+	return reusableRuntime.NewCustomReusableCadenceRuntimePool(
+		0,
+		config,
+		func(config runtime.Config) runtime.Runtime {
+			return interactiveRuntime{
+				Runtime:   base,
+				ctx:       ctx,
+				execution: e,
+			}
+		},
+	)
+}
+
+// interactiveRuntime executes the transaction body of an interactive execution.
+type interactiveRuntime struct {
+	runtime.Runtime
+	ctx       context.Context
+	execution *InteractiveExecution
+}
+
+func (ir interactiveRuntime) NewTransactionExecutor(
+	script runtime.Script,
+	runtimeContext runtime.Context,
+) runtime.Executor {
+	runtimeContext.Interface = recordingInterface{
+		Interface: interruptibleInterface{
+			Interface: runtimeContext.Interface,
+			ctx:       ir.ctx,
+		},
+		writes: ir.execution.writes,
+	}
+
+	return interactiveExecutor{
+		Executor: ir.Runtime.NewTransactionExecutor(script, runtimeContext),
+		inBody:   ir.execution.inBody,
+	}
+}
+
+type interactiveExecutor struct {
+	runtime.Executor
+	inBody *atomic.Bool
+}
+
+func (e interactiveExecutor) Execute() error {
+	e.inBody.Store(true)
+	defer e.inBody.Store(false)
+
+	return e.Executor.Execute()
+}
+
+// recordingInterface records the registers written through the runtime interface.
+type recordingInterface struct {
+	runtime.Interface
+	writes *registerWrites
+}
+
+func (i recordingInterface) SetValue(owner, key, value []byte) error {
+	err := i.Interface.SetValue(owner, key, value)
+	if err == nil {
+		i.writes.set(flowgo.NewRegisterID(string(owner), string(key)), value)
+	}
+	return err
+}
+
+type registerWrites struct {
+	mu     sync.Mutex
+	values map[flowgo.RegisterID]flowgo.RegisterValue
+}
+
+func (w *registerWrites) set(id flowgo.RegisterID, value flowgo.RegisterValue) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.values[id] = append(flowgo.RegisterValue(nil), value...)
+}
+
+func (w *registerWrites) copy() map[flowgo.RegisterID]flowgo.RegisterValue {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	values := make(map[flowgo.RegisterID]flowgo.RegisterValue, len(w.values))
+	for id, value := range w.values {
+		values[id] = value
+	}
+	return values
+}
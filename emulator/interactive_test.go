@@ -0,0 +1,263 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func setupInteractiveTest(t *testing.T, script string, opts ...emulator.Option) *emulator.Blockchain {
+	b, err := emulator.New(
+		append([]emulator.Option{emulator.WithStorageLimitEnabled(false)}, opts...)...,
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(script)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address).
+		AddAuthorizer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = adapter.SendTransaction(context.Background(), *tx)
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestExecuteNextTransactionInteractive(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("Next", func(t *testing.T) {
+
+		t.Parallel()
+
+		b := setupInteractiveTest(t, `
+			transaction {
+				prepare(signer: AuthAccount) {
+					let answer = 42
+					signer.save(answer, to: /storage/answer)
+					log(answer)
+				}
+			}
+		`)
+
+		execution, err := b.ExecuteNextTransactionInteractive()
+		require.NoError(t, err)
+
+		stop := execution.Stop()
+		require.NotNil(t, stop)
+		assert.IsType(t, common.TransactionLocation{}, stop.Location)
+		assert.Equal(t, 4, stop.Line)
+
+		stop, err = execution.Next()
+		require.NoError(t, err)
+		require.NotNil(t, stop)
+		assert.Equal(t, 5, stop.Line)
+
+		stop, err = execution.Next()
+		require.NoError(t, err)
+		require.NotNil(t, stop)
+		assert.Equal(t, 6, stop.Line)
+
+		// the saved value is only in the storage of the interpreter
+		address := common.Address(b.ServiceKey().Address)
+		value := stop.Interpreter.ReadStored(address, "storage", interpreter.StringStorageMapKey("answer"))
+		require.NotNil(t, value)
+		assert.Equal(t, "42", value.String())
+		assert.Empty(t, execution.Delta())
+
+		stop, err = execution.Next()
+		require.NoError(t, err)
+		assert.Nil(t, stop)
+
+		result, err := execution.Resume()
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+		assert.Equal(t, []string{"42"}, result.Logs)
+
+		// the storage of the account was written once the body completed
+		delta := execution.Delta()
+		require.NotEmpty(t, delta)
+		for id := range delta {
+			assert.Equal(t, string(b.ServiceKey().Address.Bytes()), id.Owner)
+		}
+
+		// the emulator is unlocked and the execution is part of the pending block
+		_, err = b.ExecuteNextTransaction()
+		var exhaustedErr *types.PendingBlockTransactionsExhaustedError
+		assert.ErrorAs(t, err, &exhaustedErr)
+
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+
+		scriptResult, err := b.ExecuteScript(
+			context.Background(),
+			[]byte(`pub fun main(): Int { return getAuthAccount(0x`+b.ServiceKey().Address.Hex()+`).load<Int>(from: /storage/answer)! }`),
+			nil,
+		)
+		require.NoError(t, err)
+		require.NoError(t, scriptResult.Error)
+		assert.Equal(t, "42", scriptResult.Value.String())
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+
+		t.Parallel()
+
+		b := setupInteractiveTest(t, `transaction { prepare(signer: AuthAccount) { log(1); log(2) } }`)
+
+		execution, err := b.ExecuteNextTransactionInteractive()
+		require.NoError(t, err)
+		require.NotNil(t, execution.Stop())
+
+		result, err := execution.Resume()
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+		assert.Equal(t, []string{"1", "2"}, result.Logs)
+		assert.Nil(t, execution.Stop())
+
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+
+		t.Parallel()
+
+		b := setupInteractiveTest(t, `
+			transaction {
+				prepare(signer: AuthAccount) {
+					var i = 0
+					while true {
+						i = i + 1
+					}
+				}
+			}
+		`)
+
+		execution, err := b.ExecuteNextTransactionInteractive()
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			stop, err := execution.Next()
+			require.NoError(t, err)
+			require.NotNil(t, stop)
+		}
+
+		result, err := execution.Abort()
+		require.NoError(t, err)
+		assert.True(t, result.Reverted())
+
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+	})
+
+	t.Run("AbandonedExecution", func(t *testing.T) {
+
+		t.Parallel()
+
+		b := setupInteractiveTest(
+			t,
+			`transaction { prepare(signer: AuthAccount) { log(1); log(2) } }`,
+			emulator.WithInteractiveExecutionTimeout(100*time.Millisecond),
+		)
+
+		execution, err := b.ExecuteNextTransactionInteractive()
+		require.NoError(t, err)
+		require.NotNil(t, execution.Stop())
+
+		// the execution is dropped without finishing it
+		committed := make(chan error, 1)
+		go func() {
+			_, err := b.CommitBlock()
+			committed <- err
+		}()
+
+		select {
+		case err := <-committed:
+			require.NoError(t, err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("abandoned interactive execution kept the emulator locked")
+		}
+
+		assert.Nil(t, execution.Stop())
+		result, err := execution.Resume()
+		require.NoError(t, err)
+		assert.True(t, result.Reverted())
+	})
+
+	t.Run("EmptyPendingBlock", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		_, err = b.ExecuteNextTransactionInteractive()
+		var exhaustedErr *types.PendingBlockTransactionsExhaustedError
+		assert.ErrorAs(t, err, &exhaustedErr)
+
+		// the emulator is not left locked
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+	})
+
+	t.Run("DebuggingSession", func(t *testing.T) {
+
+		t.Parallel()
+
+		b := setupInteractiveTest(t, `transaction { prepare(signer: AuthAccount) {} }`)
+
+		b.StartDebugger()
+		_, err := b.ExecuteNextTransactionInteractive()
+		assert.Error(t, err)
+		b.EndDebugging()
+
+		execution, err := b.ExecuteNextTransactionInteractive()
+		require.NoError(t, err)
+		result, err := execution.Resume()
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+	})
+}
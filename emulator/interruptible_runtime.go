@@ -0,0 +1,84 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
+)
+
+// interruptibleRuntime aborts script execution as soon as its context is done.
+//
+// Cadence meters computation before every statement, loop iteration and
+// function invocation, so failing the metering call is enough to stop even
+// a script that never returns.
+type interruptibleRuntime struct {
+	runtime.Runtime
+	ctx context.Context
+}
+
+func (ir interruptibleRuntime) ExecuteScript(
+	script runtime.Script,
+	runtimeContext runtime.Context,
+) (
+	cadence.Value,
+	error,
+) {
+	runtimeContext.Interface = interruptibleInterface{
+		Interface: runtimeContext.Interface,
+		ctx:       ir.ctx,
+	}
+	return ir.Runtime.ExecuteScript(script, runtimeContext)
+}
+
+type interruptibleInterface struct {
+	runtime.Interface
+	ctx context.Context
+}
+
+func (i interruptibleInterface) MeterComputation(kind common.ComputationKind, intensity uint) error {
+	if err := i.ctx.Err(); err != nil {
+		return err
+	}
+	return i.Interface.MeterComputation(kind, intensity)
+}
+
+// newInterruptibleRuntimePool returns a runtime pool which hands out runtimes
+// bound to the given context. The pool does not cache runtimes, as each one is
+// only valid for the lifetime of a single execution.
+func newInterruptibleRuntimePool(
+	ctx context.Context,
+	config runtime.Config,
+	base runtime.Runtime,
+) reusableRuntime.ReusableCadenceRuntimePool {
+	return reusableRuntime.NewCustomReusableCadenceRuntimePool(
+		0,
+		config,
+		func(config runtime.Config) runtime.Runtime {
+			return interruptibleRuntime{
+				Runtime: base,
+				ctx:     ctx,
+			}
+		},
+	)
+}
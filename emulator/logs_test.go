@@ -19,6 +19,7 @@
 package emulator_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/onflow/flow-emulator/emulator"
@@ -40,7 +41,7 @@ func TestRuntimeLogs(t *testing.T) {
 		}
 	`)
 
-	result, err := b.ExecuteScript(script, nil)
+	result, err := b.ExecuteScript(context.Background(), script, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, []string{`"elephant ears"`}, result.Logs)
 }
@@ -0,0 +1,156 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+// storeConfigKey is the metadata key of the configuration a store was created with.
+const storeConfigKey = "config"
+
+// storeConfigVersion is the version of the emulator state written by this emulator.
+const storeConfigVersion uint64 = 1
+
+// storeConfig is the configuration a store was created with, persisted in its metadata.
+type storeConfig struct {
+	Version uint64         `json:"version"`
+	ChainID flowgo.ChainID `json:"chainID"`
+
+	// bootstrap parameters, which only apply when the store is created
+	GenesisTokenSupply        string `json:"genesisTokenSupply"`
+	StorageLimitEnabled       bool   `json:"storageLimitEnabled"`
+	MinimumStorageReservation string `json:"minimumStorageReservation"`
+	StorageMBPerFLOW          string `json:"storageMBPerFLOW"`
+}
+
+func newStoreConfig(conf config) storeConfig {
+	return storeConfig{
+		Version:                   storeConfigVersion,
+		ChainID:                   conf.GetChainID(),
+		GenesisTokenSupply:        conf.GenesisTokenSupply.String(),
+		StorageLimitEnabled:       conf.StorageLimitEnabled,
+		MinimumStorageReservation: conf.MinimumStorageReservation.String(),
+		StorageMBPerFLOW:          conf.StorageMBPerFLOW.String(),
+	}
+}
+
+func (c storeConfig) bootstrapParameters() storeConfig {
+	c.Version = 0
+	c.ChainID = ""
+	return c
+}
+
+// saveStoreConfig persists the configuration a store is created with.
+func saveStoreConfig(conf config, store storage.Store) error {
+	encoded, err := json.Marshal(newStoreConfig(conf))
+	if err != nil {
+		return err
+	}
+
+	err = store.PutMeta(context.Background(), storeConfigKey, encoded)
+	if err != nil {
+		return fmt.Errorf("failed to save storage configuration: %w", err)
+	}
+
+	return nil
+}
+
+// checkStoreConfig refuses stores created with a configuration the emulator can not
+// run on. Stores created before the configuration was persisted adopt the current one.
+func checkStoreConfig(conf config, store storage.Store) error {
+	encoded, err := store.GetMeta(context.Background(), storeConfigKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		if conf.ReadOnly {
+			return nil
+		}
+		return saveStoreConfig(conf, store)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read storage configuration: %w", err)
+	}
+
+	var stored storeConfig
+	err = json.Unmarshal(encoded, &stored)
+	if err != nil {
+		return fmt.Errorf("failed to decode storage configuration: %w", err)
+	}
+
+	if stored.Version > storeConfigVersion {
+		return &storage.UnsupportedSchemaVersionError{
+			Version:   stored.Version,
+			Supported: storeConfigVersion,
+		}
+	}
+
+	current := newStoreConfig(conf)
+
+	if stored.ChainID != current.ChainID {
+		return &storage.IncompatibleStoreError{
+			Setting:    "chain ID",
+			Stored:     stored.ChainID.String(),
+			Configured: current.ChainID.String(),
+		}
+	}
+
+	if stored.bootstrapParameters() != current.bootstrapParameters() {
+		conf.ServerLogger.Warn().
+			Str("genesisTokenSupply", stored.GenesisTokenSupply).
+			Bool("storageLimitEnabled", stored.StorageLimitEnabled).
+			Str("minimumStorageReservation", stored.MinimumStorageReservation).
+			Str("storageMBPerFLOW", stored.StorageMBPerFLOW).
+			Msg("Bootstrap parameters differ from the ones the storage was created with, the stored ones apply")
+	}
+
+	return nil
+}
+
+// storeMetadataKeys are the metadata keys describing the state a store holds.
+var storeMetadataKeys = []string{
+	storeConfigKey,
+	prunedHeightKey,
+	pinnedHeightsKey,
+}
+
+// copyStoreMetadata copies the metadata describing the state a store holds to another
+// store, so that the copy of the state is described the same way.
+func copyStoreMetadata(ctx context.Context, source storage.Store, target storage.Store) error {
+	for _, key := range storeMetadataKeys {
+		value, err := source.GetMeta(ctx, key)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the %s metadata: %w", key, err)
+		}
+
+		err = target.PutMeta(ctx, key, value)
+		if err != nil {
+			return fmt.Errorf("failed to copy the %s metadata: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -5,16 +5,21 @@
 package mocks
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	runtime "github.com/onflow/cadence/runtime"
 	common "github.com/onflow/cadence/runtime/common"
 	interpreter "github.com/onflow/cadence/runtime/interpreter"
 	emulator "github.com/onflow/flow-emulator/emulator"
+	storage "github.com/onflow/flow-emulator/storage"
 	types "github.com/onflow/flow-emulator/types"
 	access "github.com/onflow/flow-go/access"
 	flow "github.com/onflow/flow-go/model/flow"
+	execution_data "github.com/onflow/flow-go/module/executiondatasync/execution_data"
 )
 
 // MockEmulator is a mock of Emulator interface.
@@ -40,18 +45,176 @@ func (m *MockEmulator) EXPECT() *MockEmulatorMockRecorder {
 	return m.recorder
 }
 
+// AccountInbox mocks base method.
+func (m *MockEmulator) AccountInbox(arg0 context.Context, arg1 flow.Address) ([]emulator.InboxEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountInbox", arg0, arg1)
+	ret0, _ := ret[0].([]emulator.InboxEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountInbox indicates an expected call of AccountInbox.
+func (mr *MockEmulatorMockRecorder) AccountInbox(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountInbox", reflect.TypeOf((*MockEmulator)(nil).AccountInbox), arg0, arg1)
+}
+
+// AddFaultRule mocks base method.
+func (m *MockEmulator) AddFaultRule(arg0 emulator.FaultMatcher) (emulator.FaultRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFaultRule", arg0)
+	ret0, _ := ret[0].(emulator.FaultRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddFaultRule indicates an expected call of AddFaultRule.
+func (mr *MockEmulatorMockRecorder) AddFaultRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFaultRule", reflect.TypeOf((*MockEmulator)(nil).AddFaultRule), arg0)
+}
+
+// AddScheduledTransaction mocks base method.
+func (m *MockEmulator) AddScheduledTransaction(arg0 context.Context, arg1 flow.TransactionBody, arg2 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddScheduledTransaction", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddScheduledTransaction indicates an expected call of AddScheduledTransaction.
+func (mr *MockEmulatorMockRecorder) AddScheduledTransaction(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddScheduledTransaction", reflect.TypeOf((*MockEmulator)(nil).AddScheduledTransaction), arg0, arg1, arg2)
+}
+
 // AddTransaction mocks base method.
-func (m *MockEmulator) AddTransaction(arg0 flow.TransactionBody) error {
+func (m *MockEmulator) AddTransaction(arg0 context.Context, arg1 flow.TransactionBody) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddTransaction", arg0)
+	ret := m.ctrl.Call(m, "AddTransaction", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddTransaction indicates an expected call of AddTransaction.
-func (mr *MockEmulatorMockRecorder) AddTransaction(arg0 interface{}) *gomock.Call {
+func (mr *MockEmulatorMockRecorder) AddTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransaction", reflect.TypeOf((*MockEmulator)(nil).AddTransaction), arg0, arg1)
+}
+
+// AdvanceTime mocks base method.
+func (m *MockEmulator) AdvanceTime(arg0 time.Duration) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceTime", arg0)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceTime indicates an expected call of AdvanceTime.
+func (mr *MockEmulatorMockRecorder) AdvanceTime(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceTime", reflect.TypeOf((*MockEmulator)(nil).AdvanceTime), arg0)
+}
+
+// ApplyCommittedBlock mocks base method.
+func (m *MockEmulator) ApplyCommittedBlock(arg0 context.Context, arg1 *emulator.CommittedBlock) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyCommittedBlock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyCommittedBlock indicates an expected call of ApplyCommittedBlock.
+func (mr *MockEmulatorMockRecorder) ApplyCommittedBlock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyCommittedBlock", reflect.TypeOf((*MockEmulator)(nil).ApplyCommittedBlock), arg0, arg1)
+}
+
+// CheckCompatibility mocks base method.
+func (m *MockEmulator) CheckCompatibility(arg0 context.Context) (*emulator.CompatibilityReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckCompatibility", arg0)
+	ret0, _ := ret[0].(*emulator.CompatibilityReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckCompatibility indicates an expected call of CheckCompatibility.
+func (mr *MockEmulatorMockRecorder) CheckCompatibility(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckCompatibility", reflect.TypeOf((*MockEmulator)(nil).CheckCompatibility), arg0)
+}
+
+// CheckSequenceNumber mocks base method.
+func (m *MockEmulator) CheckSequenceNumber(arg0 context.Context, arg1 *flow.TransactionBody) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSequenceNumber", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckSequenceNumber indicates an expected call of CheckSequenceNumber.
+func (mr *MockEmulatorMockRecorder) CheckSequenceNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSequenceNumber", reflect.TypeOf((*MockEmulator)(nil).CheckSequenceNumber), arg0, arg1)
+}
+
+// CheckVaults mocks base method.
+func (m *MockEmulator) CheckVaults(arg0 context.Context, arg1 flow.Address) ([]emulator.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckVaults", arg0, arg1)
+	ret0, _ := ret[0].([]emulator.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckVaults indicates an expected call of CheckVaults.
+func (mr *MockEmulatorMockRecorder) CheckVaults(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransaction", reflect.TypeOf((*MockEmulator)(nil).AddTransaction), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckVaults", reflect.TypeOf((*MockEmulator)(nil).CheckVaults), arg0, arg1)
+}
+
+// Checkpoint mocks base method.
+func (m *MockEmulator) Checkpoint(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkpoint", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Checkpoint indicates an expected call of Checkpoint.
+func (mr *MockEmulatorMockRecorder) Checkpoint(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkpoint", reflect.TypeOf((*MockEmulator)(nil).Checkpoint), arg0)
+}
+
+// CleanupAccount mocks base method.
+func (m *MockEmulator) CleanupAccount(arg0 context.Context, arg1 flow.Address) (*types.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanupAccount", arg0, arg1)
+	ret0, _ := ret[0].(*types.TransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CleanupAccount indicates an expected call of CleanupAccount.
+func (mr *MockEmulatorMockRecorder) CleanupAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupAccount", reflect.TypeOf((*MockEmulator)(nil).CleanupAccount), arg0, arg1)
+}
+
+// ClearUnmatchedEventExpectations mocks base method.
+func (m *MockEmulator) ClearUnmatchedEventExpectations() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearUnmatchedEventExpectations")
+}
+
+// ClearUnmatchedEventExpectations indicates an expected call of ClearUnmatchedEventExpectations.
+func (mr *MockEmulatorMockRecorder) ClearUnmatchedEventExpectations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearUnmatchedEventExpectations", reflect.TypeOf((*MockEmulator)(nil).ClearUnmatchedEventExpectations))
 }
 
 // CommitBlock mocks base method.
@@ -69,6 +232,50 @@ func (mr *MockEmulatorMockRecorder) CommitBlock() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitBlock", reflect.TypeOf((*MockEmulator)(nil).CommitBlock))
 }
 
+// CompactStorage mocks base method.
+func (m *MockEmulator) CompactStorage(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactStorage", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompactStorage indicates an expected call of CompactStorage.
+func (mr *MockEmulatorMockRecorder) CompactStorage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactStorage", reflect.TypeOf((*MockEmulator)(nil).CompactStorage), arg0)
+}
+
+// ContractProfiles mocks base method.
+func (m *MockEmulator) ContractProfiles() []emulator.ContractProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContractProfiles")
+	ret0, _ := ret[0].([]emulator.ContractProfile)
+	return ret0
+}
+
+// ContractProfiles indicates an expected call of ContractProfiles.
+func (mr *MockEmulatorMockRecorder) ContractProfiles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContractProfiles", reflect.TypeOf((*MockEmulator)(nil).ContractProfiles))
+}
+
+// CoreContracts mocks base method.
+func (m *MockEmulator) CoreContracts(arg0 context.Context) ([]emulator.CoreContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CoreContracts", arg0)
+	ret0, _ := ret[0].([]emulator.CoreContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CoreContracts indicates an expected call of CoreContracts.
+func (mr *MockEmulatorMockRecorder) CoreContracts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CoreContracts", reflect.TypeOf((*MockEmulator)(nil).CoreContracts), arg0)
+}
+
 // CoverageReport mocks base method.
 func (m *MockEmulator) CoverageReport() *runtime.CoverageReport {
 	m.ctrl.T.Helper()
@@ -84,17 +291,31 @@ func (mr *MockEmulatorMockRecorder) CoverageReport() *gomock.Call {
 }
 
 // CreateSnapshot mocks base method.
-func (m *MockEmulator) CreateSnapshot(arg0 string) error {
+func (m *MockEmulator) CreateSnapshot(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSnapshot", arg0)
+	ret := m.ctrl.Call(m, "CreateSnapshot", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateSnapshot indicates an expected call of CreateSnapshot.
-func (mr *MockEmulatorMockRecorder) CreateSnapshot(arg0 interface{}) *gomock.Call {
+func (mr *MockEmulatorMockRecorder) CreateSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockEmulator)(nil).CreateSnapshot), arg0, arg1)
+}
+
+// DeleteSnapshot mocks base method.
+func (m *MockEmulator) DeleteSnapshot(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSnapshot", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSnapshot indicates an expected call of DeleteSnapshot.
+func (mr *MockEmulatorMockRecorder) DeleteSnapshot(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockEmulator)(nil).CreateSnapshot), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnapshot", reflect.TypeOf((*MockEmulator)(nil).DeleteSnapshot), arg0)
 }
 
 // DisableAutoMine mocks base method.
@@ -121,6 +342,20 @@ func (mr *MockEmulatorMockRecorder) EnableAutoMine() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableAutoMine", reflect.TypeOf((*MockEmulator)(nil).EnableAutoMine))
 }
 
+// EnableStandby mocks base method.
+func (m *MockEmulator) EnableStandby() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableStandby")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableStandby indicates an expected call of EnableStandby.
+func (mr *MockEmulatorMockRecorder) EnableStandby() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableStandby", reflect.TypeOf((*MockEmulator)(nil).EnableStandby))
+}
+
 // EndDebugging mocks base method.
 func (m *MockEmulator) EndDebugging() {
 	m.ctrl.T.Helper()
@@ -133,6 +368,20 @@ func (mr *MockEmulatorMockRecorder) EndDebugging() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndDebugging", reflect.TypeOf((*MockEmulator)(nil).EndDebugging))
 }
 
+// EventExpectations mocks base method.
+func (m *MockEmulator) EventExpectations() []emulator.EventExpectation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventExpectations")
+	ret0, _ := ret[0].([]emulator.EventExpectation)
+	return ret0
+}
+
+// EventExpectations indicates an expected call of EventExpectations.
+func (mr *MockEmulatorMockRecorder) EventExpectations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventExpectations", reflect.TypeOf((*MockEmulator)(nil).EventExpectations))
+}
+
 // ExecuteAndCommitBlock mocks base method.
 func (m *MockEmulator) ExecuteAndCommitBlock() (*flow.Block, []*types.TransactionResult, error) {
 	m.ctrl.T.Helper()
@@ -149,6 +398,22 @@ func (mr *MockEmulatorMockRecorder) ExecuteAndCommitBlock() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAndCommitBlock", reflect.TypeOf((*MockEmulator)(nil).ExecuteAndCommitBlock))
 }
 
+// ExecuteAndCommitBlockAt mocks base method.
+func (m *MockEmulator) ExecuteAndCommitBlockAt(arg0 time.Time) (*flow.Block, []*types.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteAndCommitBlockAt", arg0)
+	ret0, _ := ret[0].(*flow.Block)
+	ret1, _ := ret[1].([]*types.TransactionResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExecuteAndCommitBlockAt indicates an expected call of ExecuteAndCommitBlockAt.
+func (mr *MockEmulatorMockRecorder) ExecuteAndCommitBlockAt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAndCommitBlockAt", reflect.TypeOf((*MockEmulator)(nil).ExecuteAndCommitBlockAt), arg0)
+}
+
 // ExecuteBlock mocks base method.
 func (m *MockEmulator) ExecuteBlock() ([]*types.TransactionResult, error) {
 	m.ctrl.T.Helper()
@@ -179,317 +444,658 @@ func (mr *MockEmulatorMockRecorder) ExecuteNextTransaction() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteNextTransaction", reflect.TypeOf((*MockEmulator)(nil).ExecuteNextTransaction))
 }
 
+// ExecuteNextTransactionInteractive mocks base method.
+func (m *MockEmulator) ExecuteNextTransactionInteractive() (*emulator.InteractiveExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteNextTransactionInteractive")
+	ret0, _ := ret[0].(*emulator.InteractiveExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteNextTransactionInteractive indicates an expected call of ExecuteNextTransactionInteractive.
+func (mr *MockEmulatorMockRecorder) ExecuteNextTransactionInteractive() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteNextTransactionInteractive", reflect.TypeOf((*MockEmulator)(nil).ExecuteNextTransactionInteractive))
+}
+
 // ExecuteScript mocks base method.
-func (m *MockEmulator) ExecuteScript(arg0 []byte, arg1 [][]byte) (*types.ScriptResult, error) {
+func (m *MockEmulator) ExecuteScript(arg0 context.Context, arg1 []byte, arg2 [][]byte) (*types.ScriptResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExecuteScript", arg0, arg1)
+	ret := m.ctrl.Call(m, "ExecuteScript", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*types.ScriptResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ExecuteScript indicates an expected call of ExecuteScript.
-func (mr *MockEmulatorMockRecorder) ExecuteScript(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockEmulatorMockRecorder) ExecuteScript(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScript", reflect.TypeOf((*MockEmulator)(nil).ExecuteScript), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScript", reflect.TypeOf((*MockEmulator)(nil).ExecuteScript), arg0, arg1, arg2)
 }
 
 // ExecuteScriptAtBlockHeight mocks base method.
-func (m *MockEmulator) ExecuteScriptAtBlockHeight(arg0 []byte, arg1 [][]byte, arg2 uint64) (*types.ScriptResult, error) {
+func (m *MockEmulator) ExecuteScriptAtBlockHeight(arg0 context.Context, arg1 []byte, arg2 [][]byte, arg3 uint64) (*types.ScriptResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExecuteScriptAtBlockHeight", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ExecuteScriptAtBlockHeight", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*types.ScriptResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ExecuteScriptAtBlockHeight indicates an expected call of ExecuteScriptAtBlockHeight.
-func (mr *MockEmulatorMockRecorder) ExecuteScriptAtBlockHeight(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockEmulatorMockRecorder) ExecuteScriptAtBlockHeight(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScriptAtBlockHeight", reflect.TypeOf((*MockEmulator)(nil).ExecuteScriptAtBlockHeight), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScriptAtBlockHeight", reflect.TypeOf((*MockEmulator)(nil).ExecuteScriptAtBlockHeight), arg0, arg1, arg2, arg3)
 }
 
 // ExecuteScriptAtBlockID mocks base method.
-func (m *MockEmulator) ExecuteScriptAtBlockID(arg0 []byte, arg1 [][]byte, arg2 flow.Identifier) (*types.ScriptResult, error) {
+func (m *MockEmulator) ExecuteScriptAtBlockID(arg0 context.Context, arg1 []byte, arg2 [][]byte, arg3 flow.Identifier) (*types.ScriptResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExecuteScriptAtBlockID", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ExecuteScriptAtBlockID", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*types.ScriptResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ExecuteScriptAtBlockID indicates an expected call of ExecuteScriptAtBlockID.
-func (mr *MockEmulatorMockRecorder) ExecuteScriptAtBlockID(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockEmulatorMockRecorder) ExecuteScriptAtBlockID(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScriptAtBlockID", reflect.TypeOf((*MockEmulator)(nil).ExecuteScriptAtBlockID), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScriptAtBlockID", reflect.TypeOf((*MockEmulator)(nil).ExecuteScriptAtBlockID), arg0, arg1, arg2, arg3)
 }
 
-// GetAccount mocks base method.
-func (m *MockEmulator) GetAccount(arg0 flow.Address) (*flow.Account, error) {
+// ExecuteScriptAtPendingBlock mocks base method.
+func (m *MockEmulator) ExecuteScriptAtPendingBlock(arg0 context.Context, arg1 []byte, arg2 [][]byte) (*types.ScriptResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0)
-	ret0, _ := ret[0].(*flow.Account)
+	ret := m.ctrl.Call(m, "ExecuteScriptAtPendingBlock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*types.ScriptResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockEmulatorMockRecorder) GetAccount(arg0 interface{}) *gomock.Call {
+// ExecuteScriptAtPendingBlock indicates an expected call of ExecuteScriptAtPendingBlock.
+func (mr *MockEmulatorMockRecorder) ExecuteScriptAtPendingBlock(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockEmulator)(nil).GetAccount), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScriptAtPendingBlock", reflect.TypeOf((*MockEmulator)(nil).ExecuteScriptAtPendingBlock), arg0, arg1, arg2)
 }
 
-// GetAccountAtBlockHeight mocks base method.
-func (m *MockEmulator) GetAccountAtBlockHeight(arg0 flow.Address, arg1 uint64) (*flow.Account, error) {
+// ExecutionEffortWeights mocks base method.
+func (m *MockEmulator) ExecutionEffortWeights(arg0 context.Context) (map[common.ComputationKind]uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountAtBlockHeight", arg0, arg1)
-	ret0, _ := ret[0].(*flow.Account)
+	ret := m.ctrl.Call(m, "ExecutionEffortWeights", arg0)
+	ret0, _ := ret[0].(map[common.ComputationKind]uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountAtBlockHeight indicates an expected call of GetAccountAtBlockHeight.
-func (mr *MockEmulatorMockRecorder) GetAccountAtBlockHeight(arg0, arg1 interface{}) *gomock.Call {
+// ExecutionEffortWeights indicates an expected call of ExecutionEffortWeights.
+func (mr *MockEmulatorMockRecorder) ExecutionEffortWeights(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountAtBlockHeight", reflect.TypeOf((*MockEmulator)(nil).GetAccountAtBlockHeight), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecutionEffortWeights", reflect.TypeOf((*MockEmulator)(nil).ExecutionEffortWeights), arg0)
 }
 
-// GetAccountByIndex mocks base method.
-func (m *MockEmulator) GetAccountByIndex(arg0 uint) (*flow.Account, error) {
+// ExpectEvent mocks base method.
+func (m *MockEmulator) ExpectEvent(arg0 emulator.EventMatcher) (emulator.EventExpectation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountByIndex", arg0)
-	ret0, _ := ret[0].(*flow.Account)
+	ret := m.ctrl.Call(m, "ExpectEvent", arg0)
+	ret0, _ := ret[0].(emulator.EventExpectation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountByIndex indicates an expected call of GetAccountByIndex.
-func (mr *MockEmulatorMockRecorder) GetAccountByIndex(arg0 interface{}) *gomock.Call {
+// ExpectEvent indicates an expected call of ExpectEvent.
+func (mr *MockEmulatorMockRecorder) ExpectEvent(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByIndex", reflect.TypeOf((*MockEmulator)(nil).GetAccountByIndex), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpectEvent", reflect.TypeOf((*MockEmulator)(nil).ExpectEvent), arg0)
 }
 
-// GetAccountUnsafe mocks base method.
-func (m *MockEmulator) GetAccountUnsafe(arg0 flow.Address) (*flow.Account, error) {
+// ExportAccountFixture mocks base method.
+func (m *MockEmulator) ExportAccountFixture(arg0 context.Context, arg1 flow.Address) (*emulator.AccountFixture, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountUnsafe", arg0)
-	ret0, _ := ret[0].(*flow.Account)
+	ret := m.ctrl.Call(m, "ExportAccountFixture", arg0, arg1)
+	ret0, _ := ret[0].(*emulator.AccountFixture)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountUnsafe indicates an expected call of GetAccountUnsafe.
-func (mr *MockEmulatorMockRecorder) GetAccountUnsafe(arg0 interface{}) *gomock.Call {
+// ExportAccountFixture indicates an expected call of ExportAccountFixture.
+func (mr *MockEmulatorMockRecorder) ExportAccountFixture(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountUnsafe", reflect.TypeOf((*MockEmulator)(nil).GetAccountUnsafe), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAccountFixture", reflect.TypeOf((*MockEmulator)(nil).ExportAccountFixture), arg0, arg1)
 }
 
-// GetBlockByHeight mocks base method.
-func (m *MockEmulator) GetBlockByHeight(arg0 uint64) (*flow.Block, error) {
+// ExportState mocks base method.
+func (m *MockEmulator) ExportState(arg0 io.Writer) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBlockByHeight", arg0)
-	ret0, _ := ret[0].(*flow.Block)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ExportState", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetBlockByHeight indicates an expected call of GetBlockByHeight.
-func (mr *MockEmulatorMockRecorder) GetBlockByHeight(arg0 interface{}) *gomock.Call {
+// ExportState indicates an expected call of ExportState.
+func (mr *MockEmulatorMockRecorder) ExportState(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByHeight", reflect.TypeOf((*MockEmulator)(nil).GetBlockByHeight), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportState", reflect.TypeOf((*MockEmulator)(nil).ExportState), arg0)
 }
 
-// GetBlockByID mocks base method.
-func (m *MockEmulator) GetBlockByID(arg0 flow.Identifier) (*flow.Block, error) {
+// FVMStats mocks base method.
+func (m *MockEmulator) FVMStats() emulator.FVMStats {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBlockByID", arg0)
-	ret0, _ := ret[0].(*flow.Block)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "FVMStats")
+	ret0, _ := ret[0].(emulator.FVMStats)
+	return ret0
 }
 
-// GetBlockByID indicates an expected call of GetBlockByID.
-func (mr *MockEmulatorMockRecorder) GetBlockByID(arg0 interface{}) *gomock.Call {
+// FVMStats indicates an expected call of FVMStats.
+func (mr *MockEmulatorMockRecorder) FVMStats() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByID", reflect.TypeOf((*MockEmulator)(nil).GetBlockByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FVMStats", reflect.TypeOf((*MockEmulator)(nil).FVMStats))
 }
 
-// GetCollectionByID mocks base method.
-func (m *MockEmulator) GetCollectionByID(arg0 flow.Identifier) (*flow.LightCollection, error) {
+// FaultRules mocks base method.
+func (m *MockEmulator) FaultRules() []emulator.FaultRule {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCollectionByID", arg0)
-	ret0, _ := ret[0].(*flow.LightCollection)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "FaultRules")
+	ret0, _ := ret[0].([]emulator.FaultRule)
+	return ret0
 }
 
-// GetCollectionByID indicates an expected call of GetCollectionByID.
-func (mr *MockEmulatorMockRecorder) GetCollectionByID(arg0 interface{}) *gomock.Call {
+// FaultRules indicates an expected call of FaultRules.
+func (mr *MockEmulatorMockRecorder) FaultRules() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionByID", reflect.TypeOf((*MockEmulator)(nil).GetCollectionByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FaultRules", reflect.TypeOf((*MockEmulator)(nil).FaultRules))
 }
 
-// GetEventsByHeight mocks base method.
-func (m *MockEmulator) GetEventsByHeight(arg0 uint64, arg1 string) ([]flow.Event, error) {
+// FeeParameters mocks base method.
+func (m *MockEmulator) FeeParameters(arg0 context.Context) (*emulator.FeeParameters, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsByHeight", arg0, arg1)
-	ret0, _ := ret[0].([]flow.Event)
+	ret := m.ctrl.Call(m, "FeeParameters", arg0)
+	ret0, _ := ret[0].(*emulator.FeeParameters)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEventsByHeight indicates an expected call of GetEventsByHeight.
-func (mr *MockEmulatorMockRecorder) GetEventsByHeight(arg0, arg1 interface{}) *gomock.Call {
+// FeeParameters indicates an expected call of FeeParameters.
+func (mr *MockEmulatorMockRecorder) FeeParameters(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsByHeight", reflect.TypeOf((*MockEmulator)(nil).GetEventsByHeight), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeeParameters", reflect.TypeOf((*MockEmulator)(nil).FeeParameters), arg0)
 }
 
-// GetEventsForBlockIDs mocks base method.
-func (m *MockEmulator) GetEventsForBlockIDs(arg0 string, arg1 []flow.Identifier) ([]flow.BlockEvents, error) {
+// GetAccount mocks base method.
+func (m *MockEmulator) GetAccount(arg0 context.Context, arg1 flow.Address) (*flow.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForBlockIDs", arg0, arg1)
-	ret0, _ := ret[0].([]flow.BlockEvents)
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(*flow.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEventsForBlockIDs indicates an expected call of GetEventsForBlockIDs.
-func (mr *MockEmulatorMockRecorder) GetEventsForBlockIDs(arg0, arg1 interface{}) *gomock.Call {
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockEmulatorMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForBlockIDs", reflect.TypeOf((*MockEmulator)(nil).GetEventsForBlockIDs), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockEmulator)(nil).GetAccount), arg0, arg1)
 }
 
-// GetEventsForHeightRange mocks base method.
-func (m *MockEmulator) GetEventsForHeightRange(arg0 string, arg1, arg2 uint64) ([]flow.BlockEvents, error) {
+// GetAccountAtBlockHeight mocks base method.
+func (m *MockEmulator) GetAccountAtBlockHeight(arg0 context.Context, arg1 flow.Address, arg2 uint64) (*flow.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForHeightRange", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]flow.BlockEvents)
+	ret := m.ctrl.Call(m, "GetAccountAtBlockHeight", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*flow.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEventsForHeightRange indicates an expected call of GetEventsForHeightRange.
-func (mr *MockEmulatorMockRecorder) GetEventsForHeightRange(arg0, arg1, arg2 interface{}) *gomock.Call {
+// GetAccountAtBlockHeight indicates an expected call of GetAccountAtBlockHeight.
+func (mr *MockEmulatorMockRecorder) GetAccountAtBlockHeight(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForHeightRange", reflect.TypeOf((*MockEmulator)(nil).GetEventsForHeightRange), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountAtBlockHeight", reflect.TypeOf((*MockEmulator)(nil).GetAccountAtBlockHeight), arg0, arg1, arg2)
 }
 
-// GetLatestBlock mocks base method.
-func (m *MockEmulator) GetLatestBlock() (*flow.Block, error) {
+// GetAccountAtPendingBlock mocks base method.
+func (m *MockEmulator) GetAccountAtPendingBlock(arg0 context.Context, arg1 flow.Address) (*flow.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLatestBlock")
-	ret0, _ := ret[0].(*flow.Block)
+	ret := m.ctrl.Call(m, "GetAccountAtPendingBlock", arg0, arg1)
+	ret0, _ := ret[0].(*flow.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLatestBlock indicates an expected call of GetLatestBlock.
-func (mr *MockEmulatorMockRecorder) GetLatestBlock() *gomock.Call {
+// GetAccountAtPendingBlock indicates an expected call of GetAccountAtPendingBlock.
+func (mr *MockEmulatorMockRecorder) GetAccountAtPendingBlock(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestBlock", reflect.TypeOf((*MockEmulator)(nil).GetLatestBlock))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountAtPendingBlock", reflect.TypeOf((*MockEmulator)(nil).GetAccountAtPendingBlock), arg0, arg1)
 }
 
-// GetLogs mocks base method.
-func (m *MockEmulator) GetLogs(arg0 flow.Identifier) ([]string, error) {
+// GetAccountByIndex mocks base method.
+func (m *MockEmulator) GetAccountByIndex(arg0 context.Context, arg1 uint) (*flow.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLogs", arg0)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "GetAccountByIndex", arg0, arg1)
+	ret0, _ := ret[0].(*flow.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLogs indicates an expected call of GetLogs.
-func (mr *MockEmulatorMockRecorder) GetLogs(arg0 interface{}) *gomock.Call {
+// GetAccountByIndex indicates an expected call of GetAccountByIndex.
+func (mr *MockEmulatorMockRecorder) GetAccountByIndex(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockEmulator)(nil).GetLogs), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByIndex", reflect.TypeOf((*MockEmulator)(nil).GetAccountByIndex), arg0, arg1)
 }
 
-// GetNetworkParameters mocks base method.
-func (m *MockEmulator) GetNetworkParameters() access.NetworkParameters {
+// GetAccountStorage mocks base method.
+func (m *MockEmulator) GetAccountStorage(arg0 context.Context, arg1 flow.Address, arg2 emulator.AccountStorageQuery) (*emulator.AccountStorage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNetworkParameters")
-	ret0, _ := ret[0].(access.NetworkParameters)
-	return ret0
+	ret := m.ctrl.Call(m, "GetAccountStorage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*emulator.AccountStorage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// GetNetworkParameters indicates an expected call of GetNetworkParameters.
-func (mr *MockEmulatorMockRecorder) GetNetworkParameters() *gomock.Call {
+// GetAccountStorage indicates an expected call of GetAccountStorage.
+func (mr *MockEmulatorMockRecorder) GetAccountStorage(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkParameters", reflect.TypeOf((*MockEmulator)(nil).GetNetworkParameters))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountStorage", reflect.TypeOf((*MockEmulator)(nil).GetAccountStorage), arg0, arg1, arg2)
 }
 
-// GetSourceFile mocks base method.
-func (m *MockEmulator) GetSourceFile(arg0 common.Location) string {
+// GetAccountUnsafe mocks base method.
+func (m *MockEmulator) GetAccountUnsafe(arg0 flow.Address) (*flow.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSourceFile", arg0)
-	ret0, _ := ret[0].(string)
-	return ret0
+	ret := m.ctrl.Call(m, "GetAccountUnsafe", arg0)
+	ret0, _ := ret[0].(*flow.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// GetSourceFile indicates an expected call of GetSourceFile.
-func (mr *MockEmulatorMockRecorder) GetSourceFile(arg0 interface{}) *gomock.Call {
+// GetAccountUnsafe indicates an expected call of GetAccountUnsafe.
+func (mr *MockEmulatorMockRecorder) GetAccountUnsafe(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceFile", reflect.TypeOf((*MockEmulator)(nil).GetSourceFile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountUnsafe", reflect.TypeOf((*MockEmulator)(nil).GetAccountUnsafe), arg0)
 }
 
-// GetTransaction mocks base method.
-func (m *MockEmulator) GetTransaction(arg0 flow.Identifier) (*flow.TransactionBody, error) {
+// GetBlockByHeight mocks base method.
+func (m *MockEmulator) GetBlockByHeight(arg0 context.Context, arg1 uint64) (*flow.Block, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransaction", arg0)
-	ret0, _ := ret[0].(*flow.TransactionBody)
+	ret := m.ctrl.Call(m, "GetBlockByHeight", arg0, arg1)
+	ret0, _ := ret[0].(*flow.Block)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransaction indicates an expected call of GetTransaction.
-func (mr *MockEmulatorMockRecorder) GetTransaction(arg0 interface{}) *gomock.Call {
+// GetBlockByHeight indicates an expected call of GetBlockByHeight.
+func (mr *MockEmulatorMockRecorder) GetBlockByHeight(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockEmulator)(nil).GetTransaction), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByHeight", reflect.TypeOf((*MockEmulator)(nil).GetBlockByHeight), arg0, arg1)
 }
 
-// GetTransactionResult mocks base method.
-func (m *MockEmulator) GetTransactionResult(arg0 flow.Identifier) (*access.TransactionResult, error) {
+// GetBlockByID mocks base method.
+func (m *MockEmulator) GetBlockByID(arg0 context.Context, arg1 flow.Identifier) (*flow.Block, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionResult", arg0)
-	ret0, _ := ret[0].(*access.TransactionResult)
+	ret := m.ctrl.Call(m, "GetBlockByID", arg0, arg1)
+	ret0, _ := ret[0].(*flow.Block)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransactionResult indicates an expected call of GetTransactionResult.
-func (mr *MockEmulatorMockRecorder) GetTransactionResult(arg0 interface{}) *gomock.Call {
+// GetBlockByID indicates an expected call of GetBlockByID.
+func (mr *MockEmulatorMockRecorder) GetBlockByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionResult", reflect.TypeOf((*MockEmulator)(nil).GetTransactionResult), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByID", reflect.TypeOf((*MockEmulator)(nil).GetBlockByID), arg0, arg1)
 }
 
-// GetTransactionResultsByBlockID mocks base method.
-func (m *MockEmulator) GetTransactionResultsByBlockID(arg0 flow.Identifier) ([]*access.TransactionResult, error) {
+// GetBlockDependencies mocks base method.
+func (m *MockEmulator) GetBlockDependencies(arg0 context.Context, arg1 flow.Identifier) (*emulator.BlockDependencies, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionResultsByBlockID", arg0)
-	ret0, _ := ret[0].([]*access.TransactionResult)
+	ret := m.ctrl.Call(m, "GetBlockDependencies", arg0, arg1)
+	ret0, _ := ret[0].(*emulator.BlockDependencies)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransactionResultsByBlockID indicates an expected call of GetTransactionResultsByBlockID.
-func (mr *MockEmulatorMockRecorder) GetTransactionResultsByBlockID(arg0 interface{}) *gomock.Call {
+// GetBlockDependencies indicates an expected call of GetBlockDependencies.
+func (mr *MockEmulatorMockRecorder) GetBlockDependencies(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionResultsByBlockID", reflect.TypeOf((*MockEmulator)(nil).GetTransactionResultsByBlockID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockDependencies", reflect.TypeOf((*MockEmulator)(nil).GetBlockDependencies), arg0, arg1)
 }
 
-// GetTransactionsByBlockID mocks base method.
-func (m *MockEmulator) GetTransactionsByBlockID(arg0 flow.Identifier) ([]*flow.TransactionBody, error) {
+// GetBlocksByHeightRange mocks base method.
+func (m *MockEmulator) GetBlocksByHeightRange(arg0 context.Context, arg1, arg2 uint64) ([]*flow.Block, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionsByBlockID", arg0)
-	ret0, _ := ret[0].([]*flow.TransactionBody)
+	ret := m.ctrl.Call(m, "GetBlocksByHeightRange", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*flow.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocksByHeightRange indicates an expected call of GetBlocksByHeightRange.
+func (mr *MockEmulatorMockRecorder) GetBlocksByHeightRange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocksByHeightRange", reflect.TypeOf((*MockEmulator)(nil).GetBlocksByHeightRange), arg0, arg1, arg2)
+}
+
+// GetChanges mocks base method.
+func (m *MockEmulator) GetChanges(arg0 context.Context, arg1 uint64, arg2 int) ([]emulator.BlockChanges, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChanges", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]emulator.BlockChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChanges indicates an expected call of GetChanges.
+func (mr *MockEmulatorMockRecorder) GetChanges(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChanges", reflect.TypeOf((*MockEmulator)(nil).GetChanges), arg0, arg1, arg2)
+}
+
+// GetCollectionByID mocks base method.
+func (m *MockEmulator) GetCollectionByID(arg0 context.Context, arg1 flow.Identifier) (*flow.LightCollection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionByID", arg0, arg1)
+	ret0, _ := ret[0].(*flow.LightCollection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionByID indicates an expected call of GetCollectionByID.
+func (mr *MockEmulatorMockRecorder) GetCollectionByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionByID", reflect.TypeOf((*MockEmulator)(nil).GetCollectionByID), arg0, arg1)
+}
+
+// GetCommittedBlock mocks base method.
+func (m *MockEmulator) GetCommittedBlock(arg0 context.Context, arg1 uint64) (*emulator.CommittedBlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommittedBlock", arg0, arg1)
+	ret0, _ := ret[0].(*emulator.CommittedBlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommittedBlock indicates an expected call of GetCommittedBlock.
+func (mr *MockEmulatorMockRecorder) GetCommittedBlock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommittedBlock", reflect.TypeOf((*MockEmulator)(nil).GetCommittedBlock), arg0, arg1)
+}
+
+// GetComputationReport mocks base method.
+func (m *MockEmulator) GetComputationReport(arg0 context.Context, arg1 flow.Identifier) (*emulator.ComputationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComputationReport", arg0, arg1)
+	ret0, _ := ret[0].(*emulator.ComputationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComputationReport indicates an expected call of GetComputationReport.
+func (mr *MockEmulatorMockRecorder) GetComputationReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComputationReport", reflect.TypeOf((*MockEmulator)(nil).GetComputationReport), arg0, arg1)
+}
+
+// GetEventsByHeight mocks base method.
+func (m *MockEmulator) GetEventsByHeight(arg0 context.Context, arg1 uint64, arg2 string) ([]flow.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsByHeight", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]flow.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsByHeight indicates an expected call of GetEventsByHeight.
+func (mr *MockEmulatorMockRecorder) GetEventsByHeight(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsByHeight", reflect.TypeOf((*MockEmulator)(nil).GetEventsByHeight), arg0, arg1, arg2)
+}
+
+// GetEventsForBlockIDs mocks base method.
+func (m *MockEmulator) GetEventsForBlockIDs(arg0 context.Context, arg1 string, arg2 []flow.Identifier) ([]flow.BlockEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsForBlockIDs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]flow.BlockEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsForBlockIDs indicates an expected call of GetEventsForBlockIDs.
+func (mr *MockEmulatorMockRecorder) GetEventsForBlockIDs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForBlockIDs", reflect.TypeOf((*MockEmulator)(nil).GetEventsForBlockIDs), arg0, arg1, arg2)
+}
+
+// GetEventsForHeightRange mocks base method.
+func (m *MockEmulator) GetEventsForHeightRange(arg0 context.Context, arg1 string, arg2, arg3 uint64) ([]flow.BlockEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsForHeightRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]flow.BlockEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsForHeightRange indicates an expected call of GetEventsForHeightRange.
+func (mr *MockEmulatorMockRecorder) GetEventsForHeightRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForHeightRange", reflect.TypeOf((*MockEmulator)(nil).GetEventsForHeightRange), arg0, arg1, arg2, arg3)
+}
+
+// GetExecutionDataByBlockID mocks base method.
+func (m *MockEmulator) GetExecutionDataByBlockID(arg0 context.Context, arg1 flow.Identifier) (*execution_data.BlockExecutionData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExecutionDataByBlockID", arg0, arg1)
+	ret0, _ := ret[0].(*execution_data.BlockExecutionData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExecutionDataByBlockID indicates an expected call of GetExecutionDataByBlockID.
+func (mr *MockEmulatorMockRecorder) GetExecutionDataByBlockID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutionDataByBlockID", reflect.TypeOf((*MockEmulator)(nil).GetExecutionDataByBlockID), arg0, arg1)
+}
+
+// GetLatestBlock mocks base method.
+func (m *MockEmulator) GetLatestBlock(arg0 context.Context) (*flow.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestBlock", arg0)
+	ret0, _ := ret[0].(*flow.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestBlock indicates an expected call of GetLatestBlock.
+func (mr *MockEmulatorMockRecorder) GetLatestBlock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestBlock", reflect.TypeOf((*MockEmulator)(nil).GetLatestBlock), arg0)
+}
+
+// GetLogs mocks base method.
+func (m *MockEmulator) GetLogs(arg0 context.Context, arg1 flow.Identifier) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogs", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogs indicates an expected call of GetLogs.
+func (mr *MockEmulatorMockRecorder) GetLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockEmulator)(nil).GetLogs), arg0, arg1)
+}
+
+// GetNetworkParameters mocks base method.
+func (m *MockEmulator) GetNetworkParameters() access.NetworkParameters {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkParameters")
+	ret0, _ := ret[0].(access.NetworkParameters)
+	return ret0
+}
+
+// GetNetworkParameters indicates an expected call of GetNetworkParameters.
+func (mr *MockEmulatorMockRecorder) GetNetworkParameters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkParameters", reflect.TypeOf((*MockEmulator)(nil).GetNetworkParameters))
+}
+
+// GetRegisterValues mocks base method.
+func (m *MockEmulator) GetRegisterValues(arg0 context.Context, arg1 flow.RegisterIDs, arg2 uint64) ([]flow.RegisterValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegisterValues", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]flow.RegisterValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegisterValues indicates an expected call of GetRegisterValues.
+func (mr *MockEmulatorMockRecorder) GetRegisterValues(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisterValues", reflect.TypeOf((*MockEmulator)(nil).GetRegisterValues), arg0, arg1, arg2)
+}
+
+// GetSourceFile mocks base method.
+func (m *MockEmulator) GetSourceFile(arg0 common.Location) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSourceFile", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetSourceFile indicates an expected call of GetSourceFile.
+func (mr *MockEmulatorMockRecorder) GetSourceFile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceFile", reflect.TypeOf((*MockEmulator)(nil).GetSourceFile), arg0)
+}
+
+// GetTransaction mocks base method.
+func (m *MockEmulator) GetTransaction(arg0 context.Context, arg1 flow.Identifier) (*flow.TransactionBody, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransaction", arg0, arg1)
+	ret0, _ := ret[0].(*flow.TransactionBody)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockEmulatorMockRecorder) GetTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockEmulator)(nil).GetTransaction), arg0, arg1)
+}
+
+// GetTransactionError mocks base method.
+func (m *MockEmulator) GetTransactionError(arg0 context.Context, arg1 flow.Identifier) (*types.TransactionError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionError", arg0, arg1)
+	ret0, _ := ret[0].(*types.TransactionError)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionError indicates an expected call of GetTransactionError.
+func (mr *MockEmulatorMockRecorder) GetTransactionError(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionError", reflect.TypeOf((*MockEmulator)(nil).GetTransactionError), arg0, arg1)
+}
+
+// GetTransactionLogs mocks base method.
+func (m *MockEmulator) GetTransactionLogs(arg0 context.Context, arg1 flow.Identifier) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionLogs", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionLogs indicates an expected call of GetTransactionLogs.
+func (mr *MockEmulatorMockRecorder) GetTransactionLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionLogs", reflect.TypeOf((*MockEmulator)(nil).GetTransactionLogs), arg0, arg1)
+}
+
+// GetTransactionResult mocks base method.
+func (m *MockEmulator) GetTransactionResult(arg0 context.Context, arg1 flow.Identifier) (*access.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionResult", arg0, arg1)
+	ret0, _ := ret[0].(*access.TransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionResult indicates an expected call of GetTransactionResult.
+func (mr *MockEmulatorMockRecorder) GetTransactionResult(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionResult", reflect.TypeOf((*MockEmulator)(nil).GetTransactionResult), arg0, arg1)
+}
+
+// GetTransactionResultByIndex mocks base method.
+func (m *MockEmulator) GetTransactionResultByIndex(arg0 context.Context, arg1 flow.Identifier, arg2 uint32) (*access.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionResultByIndex", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*access.TransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionResultByIndex indicates an expected call of GetTransactionResultByIndex.
+func (mr *MockEmulatorMockRecorder) GetTransactionResultByIndex(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionResultByIndex", reflect.TypeOf((*MockEmulator)(nil).GetTransactionResultByIndex), arg0, arg1, arg2)
+}
+
+// GetTransactionResultsByBlockID mocks base method.
+func (m *MockEmulator) GetTransactionResultsByBlockID(arg0 context.Context, arg1 flow.Identifier) ([]*access.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionResultsByBlockID", arg0, arg1)
+	ret0, _ := ret[0].([]*access.TransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionResultsByBlockID indicates an expected call of GetTransactionResultsByBlockID.
+func (mr *MockEmulatorMockRecorder) GetTransactionResultsByBlockID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionResultsByBlockID", reflect.TypeOf((*MockEmulator)(nil).GetTransactionResultsByBlockID), arg0, arg1)
+}
+
+// GetTransactionsByBlockID mocks base method.
+func (m *MockEmulator) GetTransactionsByBlockID(arg0 context.Context, arg1 flow.Identifier) ([]*flow.TransactionBody, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByBlockID", arg0, arg1)
+	ret0, _ := ret[0].([]*flow.TransactionBody)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionsByBlockID indicates an expected call of GetTransactionsByBlockID.
-func (mr *MockEmulatorMockRecorder) GetTransactionsByBlockID(arg0 interface{}) *gomock.Call {
+func (mr *MockEmulatorMockRecorder) GetTransactionsByBlockID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByBlockID", reflect.TypeOf((*MockEmulator)(nil).GetTransactionsByBlockID), arg0, arg1)
+}
+
+// ImportAccountFixture mocks base method.
+func (m *MockEmulator) ImportAccountFixture(arg0 context.Context, arg1 *emulator.AccountFixture) (*flow.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportAccountFixture", arg0, arg1)
+	ret0, _ := ret[0].(*flow.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportAccountFixture indicates an expected call of ImportAccountFixture.
+func (mr *MockEmulatorMockRecorder) ImportAccountFixture(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByBlockID", reflect.TypeOf((*MockEmulator)(nil).GetTransactionsByBlockID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportAccountFixture", reflect.TypeOf((*MockEmulator)(nil).ImportAccountFixture), arg0, arg1)
+}
+
+// ImportState mocks base method.
+func (m *MockEmulator) ImportState(arg0 io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportState", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportState indicates an expected call of ImportState.
+func (mr *MockEmulatorMockRecorder) ImportState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportState", reflect.TypeOf((*MockEmulator)(nil).ImportState), arg0)
 }
 
 // LoadSnapshot mocks base method.
@@ -506,6 +1112,48 @@ func (mr *MockEmulatorMockRecorder) LoadSnapshot(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSnapshot", reflect.TypeOf((*MockEmulator)(nil).LoadSnapshot), arg0)
 }
 
+// OnTransactionExecuted mocks base method.
+func (m *MockEmulator) OnTransactionExecuted(arg0 emulator.TransactionExecutedCallback) func() {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnTransactionExecuted", arg0)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// OnTransactionExecuted indicates an expected call of OnTransactionExecuted.
+func (mr *MockEmulatorMockRecorder) OnTransactionExecuted(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnTransactionExecuted", reflect.TypeOf((*MockEmulator)(nil).OnTransactionExecuted), arg0)
+}
+
+// PendingBlockInfo mocks base method.
+func (m *MockEmulator) PendingBlockInfo() *emulator.PendingBlockInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingBlockInfo")
+	ret0, _ := ret[0].(*emulator.PendingBlockInfo)
+	return ret0
+}
+
+// PendingBlockInfo indicates an expected call of PendingBlockInfo.
+func (mr *MockEmulatorMockRecorder) PendingBlockInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingBlockInfo", reflect.TypeOf((*MockEmulator)(nil).PendingBlockInfo))
+}
+
+// PinBlockHeight mocks base method.
+func (m *MockEmulator) PinBlockHeight(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinBlockHeight", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinBlockHeight indicates an expected call of PinBlockHeight.
+func (mr *MockEmulatorMockRecorder) PinBlockHeight(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinBlockHeight", reflect.TypeOf((*MockEmulator)(nil).PinBlockHeight), arg0)
+}
+
 // Ping mocks base method.
 func (m *MockEmulator) Ping() error {
 	m.ctrl.T.Helper()
@@ -520,6 +1168,91 @@ func (mr *MockEmulatorMockRecorder) Ping() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockEmulator)(nil).Ping))
 }
 
+// PinnedBlockHeights mocks base method.
+func (m *MockEmulator) PinnedBlockHeights() []uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinnedBlockHeights")
+	ret0, _ := ret[0].([]uint64)
+	return ret0
+}
+
+// PinnedBlockHeights indicates an expected call of PinnedBlockHeights.
+func (mr *MockEmulatorMockRecorder) PinnedBlockHeights() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinnedBlockHeights", reflect.TypeOf((*MockEmulator)(nil).PinnedBlockHeights))
+}
+
+// ReferenceBlockWindow mocks base method.
+func (m *MockEmulator) ReferenceBlockWindow(arg0 context.Context) (*emulator.ReferenceBlockWindow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferenceBlockWindow", arg0)
+	ret0, _ := ret[0].(*emulator.ReferenceBlockWindow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferenceBlockWindow indicates an expected call of ReferenceBlockWindow.
+func (mr *MockEmulatorMockRecorder) ReferenceBlockWindow(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferenceBlockWindow", reflect.TypeOf((*MockEmulator)(nil).ReferenceBlockWindow), arg0)
+}
+
+// RemoveFaultRule mocks base method.
+func (m *MockEmulator) RemoveFaultRule(arg0 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFaultRule", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// RemoveFaultRule indicates an expected call of RemoveFaultRule.
+func (mr *MockEmulatorMockRecorder) RemoveFaultRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFaultRule", reflect.TypeOf((*MockEmulator)(nil).RemoveFaultRule), arg0)
+}
+
+// RemovePendingTransaction mocks base method.
+func (m *MockEmulator) RemovePendingTransaction(arg0 flow.Identifier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePendingTransaction", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePendingTransaction indicates an expected call of RemovePendingTransaction.
+func (mr *MockEmulatorMockRecorder) RemovePendingTransaction(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePendingTransaction", reflect.TypeOf((*MockEmulator)(nil).RemovePendingTransaction), arg0)
+}
+
+// RepairVaults mocks base method.
+func (m *MockEmulator) RepairVaults(arg0 context.Context, arg1 flow.Address) ([]emulator.Vault, *types.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairVaults", arg0, arg1)
+	ret0, _ := ret[0].([]emulator.Vault)
+	ret1, _ := ret[1].(*types.TransactionResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RepairVaults indicates an expected call of RepairVaults.
+func (mr *MockEmulatorMockRecorder) RepairVaults(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairVaults", reflect.TypeOf((*MockEmulator)(nil).RepairVaults), arg0, arg1)
+}
+
+// ResetContractProfiles mocks base method.
+func (m *MockEmulator) ResetContractProfiles() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResetContractProfiles")
+}
+
+// ResetContractProfiles indicates an expected call of ResetContractProfiles.
+func (mr *MockEmulatorMockRecorder) ResetContractProfiles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetContractProfiles", reflect.TypeOf((*MockEmulator)(nil).ResetContractProfiles))
+}
+
 // ResetCoverageReport mocks base method.
 func (m *MockEmulator) ResetCoverageReport() {
 	m.ctrl.T.Helper()
@@ -532,6 +1265,20 @@ func (mr *MockEmulatorMockRecorder) ResetCoverageReport() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetCoverageReport", reflect.TypeOf((*MockEmulator)(nil).ResetCoverageReport))
 }
 
+// ResetState mocks base method.
+func (m *MockEmulator) ResetState() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetState")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetState indicates an expected call of ResetState.
+func (mr *MockEmulatorMockRecorder) ResetState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetState", reflect.TypeOf((*MockEmulator)(nil).ResetState))
+}
+
 // RollbackToBlockHeight mocks base method.
 func (m *MockEmulator) RollbackToBlockHeight(arg0 uint64) error {
 	m.ctrl.T.Helper()
@@ -546,18 +1293,46 @@ func (mr *MockEmulatorMockRecorder) RollbackToBlockHeight(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackToBlockHeight", reflect.TypeOf((*MockEmulator)(nil).RollbackToBlockHeight), arg0)
 }
 
+// SendCheckedTransaction mocks base method.
+func (m *MockEmulator) SendCheckedTransaction(arg0 context.Context, arg1 *flow.TransactionBody) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendCheckedTransaction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendCheckedTransaction indicates an expected call of SendCheckedTransaction.
+func (mr *MockEmulatorMockRecorder) SendCheckedTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCheckedTransaction", reflect.TypeOf((*MockEmulator)(nil).SendCheckedTransaction), arg0, arg1)
+}
+
 // SendTransaction mocks base method.
-func (m *MockEmulator) SendTransaction(arg0 *flow.TransactionBody) error {
+func (m *MockEmulator) SendTransaction(arg0 context.Context, arg1 *flow.TransactionBody) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendTransaction", arg0)
+	ret := m.ctrl.Call(m, "SendTransaction", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SendTransaction indicates an expected call of SendTransaction.
-func (mr *MockEmulatorMockRecorder) SendTransaction(arg0 interface{}) *gomock.Call {
+func (mr *MockEmulatorMockRecorder) SendTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTransaction", reflect.TypeOf((*MockEmulator)(nil).SendTransaction), arg0, arg1)
+}
+
+// SendTransactionWithPriority mocks base method.
+func (m *MockEmulator) SendTransactionWithPriority(arg0 context.Context, arg1 *flow.TransactionBody, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTransactionWithPriority", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendTransactionWithPriority indicates an expected call of SendTransactionWithPriority.
+func (mr *MockEmulatorMockRecorder) SendTransactionWithPriority(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTransaction", reflect.TypeOf((*MockEmulator)(nil).SendTransaction), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTransactionWithPriority", reflect.TypeOf((*MockEmulator)(nil).SendTransactionWithPriority), arg0, arg1, arg2)
 }
 
 // ServiceKey mocks base method.
@@ -574,6 +1349,63 @@ func (mr *MockEmulatorMockRecorder) ServiceKey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceKey", reflect.TypeOf((*MockEmulator)(nil).ServiceKey))
 }
 
+// SetClock mocks base method.
+func (m *MockEmulator) SetClock(arg0 emulator.Clock) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetClock", arg0)
+}
+
+// SetClock indicates an expected call of SetClock.
+func (mr *MockEmulatorMockRecorder) SetClock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetClock", reflect.TypeOf((*MockEmulator)(nil).SetClock), arg0)
+}
+
+// SetExecutionEffortWeights mocks base method.
+func (m *MockEmulator) SetExecutionEffortWeights(arg0 context.Context, arg1 map[common.ComputationKind]uint64) (*types.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetExecutionEffortWeights", arg0, arg1)
+	ret0, _ := ret[0].(*types.TransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetExecutionEffortWeights indicates an expected call of SetExecutionEffortWeights.
+func (mr *MockEmulatorMockRecorder) SetExecutionEffortWeights(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExecutionEffortWeights", reflect.TypeOf((*MockEmulator)(nil).SetExecutionEffortWeights), arg0, arg1)
+}
+
+// SetFeeParameters mocks base method.
+func (m *MockEmulator) SetFeeParameters(arg0 context.Context, arg1 emulator.FeeParameters) (*types.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFeeParameters", arg0, arg1)
+	ret0, _ := ret[0].(*types.TransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetFeeParameters indicates an expected call of SetFeeParameters.
+func (mr *MockEmulatorMockRecorder) SetFeeParameters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFeeParameters", reflect.TypeOf((*MockEmulator)(nil).SetFeeParameters), arg0, arg1)
+}
+
+// SnapshotInfos mocks base method.
+func (m *MockEmulator) SnapshotInfos() ([]storage.SnapshotInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotInfos")
+	ret0, _ := ret[0].([]storage.SnapshotInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotInfos indicates an expected call of SnapshotInfos.
+func (mr *MockEmulatorMockRecorder) SnapshotInfos() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotInfos", reflect.TypeOf((*MockEmulator)(nil).SnapshotInfos))
+}
+
 // Snapshots mocks base method.
 func (m *MockEmulator) Snapshots() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -602,3 +1434,157 @@ func (mr *MockEmulatorMockRecorder) StartDebugger() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDebugger", reflect.TypeOf((*MockEmulator)(nil).StartDebugger))
 }
+
+// Status mocks base method.
+func (m *MockEmulator) Status(arg0 context.Context) (*emulator.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", arg0)
+	ret0, _ := ret[0].(*emulator.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockEmulatorMockRecorder) Status(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockEmulator)(nil).Status), arg0)
+}
+
+// Storage mocks base method.
+func (m *MockEmulator) Storage() storage.Store {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Storage")
+	ret0, _ := ret[0].(storage.Store)
+	return ret0
+}
+
+// Storage indicates an expected call of Storage.
+func (mr *MockEmulatorMockRecorder) Storage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Storage", reflect.TypeOf((*MockEmulator)(nil).Storage))
+}
+
+// SubscribeBlockCommitted mocks base method.
+func (m *MockEmulator) SubscribeBlockCommitted(arg0 chan<- emulator.BlockEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SubscribeBlockCommitted", arg0)
+}
+
+// SubscribeBlockCommitted indicates an expected call of SubscribeBlockCommitted.
+func (mr *MockEmulatorMockRecorder) SubscribeBlockCommitted(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeBlockCommitted", reflect.TypeOf((*MockEmulator)(nil).SubscribeBlockCommitted), arg0)
+}
+
+// SwitchStorage mocks base method.
+func (m *MockEmulator) SwitchStorage(arg0 context.Context, arg1 storage.Store) (storage.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwitchStorage", arg0, arg1)
+	ret0, _ := ret[0].(storage.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwitchStorage indicates an expected call of SwitchStorage.
+func (mr *MockEmulatorMockRecorder) SwitchStorage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchStorage", reflect.TypeOf((*MockEmulator)(nil).SwitchStorage), arg0, arg1)
+}
+
+// SyncHead mocks base method.
+func (m *MockEmulator) SyncHead(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncHead", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncHead indicates an expected call of SyncHead.
+func (mr *MockEmulatorMockRecorder) SyncHead(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncHead", reflect.TypeOf((*MockEmulator)(nil).SyncHead), arg0)
+}
+
+// TestAccounts mocks base method.
+func (m *MockEmulator) TestAccounts() []emulator.TestAccount {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestAccounts")
+	ret0, _ := ret[0].([]emulator.TestAccount)
+	return ret0
+}
+
+// TestAccounts indicates an expected call of TestAccounts.
+func (mr *MockEmulatorMockRecorder) TestAccounts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestAccounts", reflect.TypeOf((*MockEmulator)(nil).TestAccounts))
+}
+
+// UnmatchedEventExpectations mocks base method.
+func (m *MockEmulator) UnmatchedEventExpectations() []emulator.UnmatchedEventExpectation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmatchedEventExpectations")
+	ret0, _ := ret[0].([]emulator.UnmatchedEventExpectation)
+	return ret0
+}
+
+// UnmatchedEventExpectations indicates an expected call of UnmatchedEventExpectations.
+func (mr *MockEmulatorMockRecorder) UnmatchedEventExpectations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmatchedEventExpectations", reflect.TypeOf((*MockEmulator)(nil).UnmatchedEventExpectations))
+}
+
+// UnpinBlockHeight mocks base method.
+func (m *MockEmulator) UnpinBlockHeight(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinBlockHeight", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinBlockHeight indicates an expected call of UnpinBlockHeight.
+func (mr *MockEmulatorMockRecorder) UnpinBlockHeight(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinBlockHeight", reflect.TypeOf((*MockEmulator)(nil).UnpinBlockHeight), arg0)
+}
+
+// UnsubscribeBlockCommitted mocks base method.
+func (m *MockEmulator) UnsubscribeBlockCommitted(arg0 chan<- emulator.BlockEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UnsubscribeBlockCommitted", arg0)
+}
+
+// UnsubscribeBlockCommitted indicates an expected call of UnsubscribeBlockCommitted.
+func (mr *MockEmulatorMockRecorder) UnsubscribeBlockCommitted(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeBlockCommitted", reflect.TypeOf((*MockEmulator)(nil).UnsubscribeBlockCommitted), arg0)
+}
+
+// UpgradeCoreContracts mocks base method.
+func (m *MockEmulator) UpgradeCoreContracts(arg0 context.Context) ([]emulator.CoreContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpgradeCoreContracts", arg0)
+	ret0, _ := ret[0].([]emulator.CoreContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeCoreContracts indicates an expected call of UpgradeCoreContracts.
+func (mr *MockEmulatorMockRecorder) UpgradeCoreContracts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeCoreContracts", reflect.TypeOf((*MockEmulator)(nil).UpgradeCoreContracts), arg0)
+}
+
+// ValidateArguments mocks base method.
+func (m *MockEmulator) ValidateArguments(arg0 context.Context, arg1 []byte, arg2 [][]byte) (*emulator.ArgumentValidation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateArguments", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*emulator.ArgumentValidation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateArguments indicates an expected call of ValidateArguments.
+func (mr *MockEmulatorMockRecorder) ValidateArguments(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateArguments", reflect.TypeOf((*MockEmulator)(nil).ValidateArguments), arg0, arg1, arg2)
+}
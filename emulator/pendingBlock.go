@@ -18,10 +18,11 @@
 package emulator
 
 import (
-	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/onflow/flow-go/fvm"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/fvm/storage/snapshot"
 	"github.com/onflow/flow-go/fvm/storage/state"
 	flowgo "github.com/onflow/flow-go/model/flow"
@@ -30,6 +31,8 @@ import (
 type IndexedTransactionResult struct {
 	fvm.ProcedureOutput
 	Index uint32
+	// RequestID is the ID of the request which submitted the transaction, if any.
+	RequestID string
 }
 
 // MaxViewIncrease represents the largest difference in view number between
@@ -49,12 +52,41 @@ type pendingBlock struct {
 	transactionIDs []flowgo.Identifier
 	// mapping from transaction ID to transaction result
 	transactionResults map[flowgo.Identifier]IndexedTransactionResult
+	// mapping from transaction ID to the ID of the request which submitted it
+	requestIDs map[flowgo.Identifier]string
+	// mapping from transaction ID to its priority, transactions are ordered by priority then FIFO
+	priorities map[flowgo.Identifier]int
+	// mapping from transaction ID to the time it was added to the pending block
+	addedAt map[flowgo.Identifier]time.Time
+	// mapping from transaction ID to the registers it read and wrote
+	registerAccesses map[flowgo.Identifier]registerAccess
 	// current working ledger, updated after each transaction execution
 	ledgerState *state.ExecutionState
+	// ledger with the writes of the executed transactions, which can be read
+	// without affecting the working ledger
+	ledgerSnapshot snapshot.SnapshotTree
 	// events emitted during execution
 	events []flowgo.Event
 	// index of transaction execution
 	index uint32
+	// transactions which did not fit in the block gas limit, included in the next block
+	deferred []deferredTransaction
+	// maximum number of transactions in a collection, zero if unlimited
+	maxCollectionSize int
+	// ledger returned by PendingSnapshot, reset when the pending block changes, along with
+	// the version of the fault rules it was computed with. It has a lock of its own, as
+	// the pending state is read while only the read lock of the emulator is held.
+	pendingSnapshotMu     sync.Mutex
+	pendingSnapshot       snapshot.StorageSnapshot
+	pendingSnapshotFaults uint64
+}
+
+// deferredTransaction is a transaction deferred to the next block,
+// along with the ID of the request which submitted it.
+type deferredTransaction struct {
+	tx        flowgo.TransactionBody
+	requestID string
+	priority  int
 }
 
 // newPendingBlock creates a new pending block sequentially after a specified block.
@@ -62,23 +94,29 @@ func newPendingBlock(
 	prevBlock *flowgo.Block,
 	ledgerSnapshot snapshot.StorageSnapshot,
 	clock Clock,
+	viewIncrease uint64,
+	maxCollectionSize int,
 ) *pendingBlock {
 	return &pendingBlock{
-		height: prevBlock.Header.Height + 1,
-		// the view increments by between 1 and MaxViewIncrease to match
-		// behaviour on a real network, where views are not consecutive
-		view:               prevBlock.Header.View + uint64(rand.Intn(MaxViewIncrease)+1),
+		height:             prevBlock.Header.Height + 1,
+		view:               prevBlock.Header.View + viewIncrease,
 		parentID:           prevBlock.ID(),
 		clock:              clock,
 		timestamp:          clock.Now(),
 		transactions:       make(map[flowgo.Identifier]*flowgo.TransactionBody),
 		transactionIDs:     make([]flowgo.Identifier, 0),
 		transactionResults: make(map[flowgo.Identifier]IndexedTransactionResult),
+		requestIDs:         make(map[flowgo.Identifier]string),
+		priorities:         make(map[flowgo.Identifier]int),
+		addedAt:            make(map[flowgo.Identifier]time.Time),
+		registerAccesses:   make(map[flowgo.Identifier]registerAccess),
 		ledgerState: state.NewExecutionState(
 			ledgerSnapshot,
 			state.DefaultParameters()),
-		events: make([]flowgo.Event, 0),
-		index:  0,
+		ledgerSnapshot:    snapshot.NewSnapshotTree(ledgerSnapshot),
+		events:            make([]flowgo.Event, 0),
+		index:             0,
+		maxCollectionSize: maxCollectionSize,
 	}
 }
 
@@ -111,19 +149,34 @@ func (b *pendingBlock) Block() *flowgo.Block {
 	}
 }
 
+// Collections returns the collections of the pending block, which split its
+// transactions into collections of at most the maximum collection size.
 func (b *pendingBlock) Collections() []*flowgo.LightCollection {
 	if len(b.transactionIDs) == 0 {
 		return []*flowgo.LightCollection{}
 	}
 
-	transactionIDs := make([]flowgo.Identifier, len(b.transactionIDs))
+	size := len(b.transactionIDs)
+	if b.maxCollectionSize > 0 && b.maxCollectionSize < size {
+		size = b.maxCollectionSize
+	}
+
+	collections := make([]*flowgo.LightCollection, 0, (len(b.transactionIDs)+size-1)/size)
+	for start := 0; start < len(b.transactionIDs); start += size {
+		end := start + size
+		if end > len(b.transactionIDs) {
+			end = len(b.transactionIDs)
+		}
 
-	// TODO: remove once SDK models are removed
-	copy(transactionIDs, b.transactionIDs)
+		transactionIDs := make([]flowgo.Identifier, end-start)
 
-	collection := flowgo.LightCollection{Transactions: transactionIDs}
+		// TODO: remove once SDK models are removed
+		copy(transactionIDs, b.transactionIDs[start:end])
 
-	return []*flowgo.LightCollection{&collection}
+		collections = append(collections, &flowgo.LightCollection{Transactions: transactionIDs})
+	}
+
+	return collections
 }
 
 func (b *pendingBlock) Transactions() map[flowgo.Identifier]*flowgo.TransactionBody {
@@ -134,26 +187,135 @@ func (b *pendingBlock) TransactionResults() map[flowgo.Identifier]IndexedTransac
 	return b.transactionResults
 }
 
+// Dependencies returns the dependency DAG of the executed transactions.
+func (b *pendingBlock) Dependencies() *BlockDependencies {
+	return buildBlockDependencies(b.ID(), b.transactionIDs, b.registerAccesses)
+}
+
 // Finalize returns the execution snapshot for the pending block.
 func (b *pendingBlock) Finalize() *snapshot.ExecutionSnapshot {
 	return b.ledgerState.Finalize()
 }
 
 // AddTransaction adds a transaction to the pending block.
-func (b *pendingBlock) AddTransaction(tx flowgo.TransactionBody) {
-	b.transactionIDs = append(b.transactionIDs, tx.ID())
-	b.transactions[tx.ID()] = &tx
+// The request ID, if any, identifies the request which submitted the transaction.
+//
+// The transaction is placed after all transactions with the same or a higher priority,
+// so transactions are executed by priority, then in the order they were added.
+func (b *pendingBlock) AddTransaction(tx flowgo.TransactionBody, requestID string, priority int) {
+	txID := tx.ID()
+	b.resetPendingSnapshot()
+
+	index := len(b.transactionIDs)
+	for i, id := range b.transactionIDs {
+		if b.priorities[id] < priority {
+			index = i
+			break
+		}
+	}
+
+	b.transactionIDs = append(b.transactionIDs, flowgo.ZeroID)
+	copy(b.transactionIDs[index+1:], b.transactionIDs[index:])
+	b.transactionIDs[index] = txID
+
+	b.transactions[txID] = &tx
+	b.addedAt[txID] = b.clock.Now()
+	if requestID != "" {
+		b.requestIDs[txID] = requestID
+	}
+	if priority != 0 {
+		b.priorities[txID] = priority
+	}
 }
 
-// ContainsTransaction checks if a transaction is included in the pending block.
+// RemoveTransaction removes a transaction which was not executed yet from the pending block,
+// and returns false if the pending block does not contain it.
+func (b *pendingBlock) RemoveTransaction(txID flowgo.Identifier) bool {
+	for i, id := range b.transactionIDs {
+		if id != txID {
+			continue
+		}
+		if i < int(b.index) {
+			return false
+		}
+
+		b.resetPendingSnapshot()
+		b.transactionIDs = append(b.transactionIDs[:i], b.transactionIDs[i+1:]...)
+		delete(b.transactions, txID)
+		delete(b.addedAt, txID)
+		delete(b.requestIDs, txID)
+		delete(b.priorities, txID)
+
+		return true
+	}
+
+	return false
+}
+
+// RequestID returns the ID of the request which submitted the transaction, if any.
+func (b *pendingBlock) RequestID(txID flowgo.Identifier) string {
+	return b.requestIDs[txID]
+}
+
+// ContainsTransaction checks if a transaction is included in the pending block,
+// or deferred to the next block.
 func (b *pendingBlock) ContainsTransaction(txID flowgo.Identifier) bool {
-	_, exists := b.transactions[txID]
-	return exists
+	return b.GetTransaction(txID) != nil
 }
 
-// GetTransaction retrieves a transaction in the pending block by ID.
+// GetTransaction retrieves a transaction in the pending block by ID,
+// including the transactions deferred to the next block.
 func (b *pendingBlock) GetTransaction(txID flowgo.Identifier) *flowgo.TransactionBody {
-	return b.transactions[txID]
+	if tx, ok := b.transactions[txID]; ok {
+		return tx
+	}
+
+	for _, deferred := range b.deferred {
+		if deferred.tx.ID() == txID {
+			return &deferred.tx
+		}
+	}
+
+	return nil
+}
+
+// DeferOverGasLimit defers the transactions exceeding the given total gas limit
+// to the next block, and returns the number of deferred transactions.
+//
+// Transactions are counted with the gas limit they declare, in the order they were added.
+// The first transaction is always kept, even if its gas limit exceeds the block gas limit.
+func (b *pendingBlock) DeferOverGasLimit(limit uint64) int {
+	var total uint64
+	for i, txID := range b.transactionIDs {
+		total += b.transactions[txID].GasLimit
+		if i == 0 || total <= limit {
+			continue
+		}
+
+		b.resetPendingSnapshot()
+		deferredIDs := b.transactionIDs[i:]
+		for _, deferredID := range deferredIDs {
+			b.deferred = append(b.deferred, deferredTransaction{
+				tx:        *b.transactions[deferredID],
+				requestID: b.requestIDs[deferredID],
+				priority:  b.priorities[deferredID],
+			})
+			delete(b.transactions, deferredID)
+			delete(b.addedAt, deferredID)
+			delete(b.requestIDs, deferredID)
+			delete(b.priorities, deferredID)
+		}
+		b.transactionIDs = b.transactionIDs[:i]
+
+		return len(deferredIDs)
+	}
+
+	return 0
+}
+
+// Deferred returns the transactions deferred to the next block.
+func (b *pendingBlock) Deferred() []deferredTransaction {
+	return b.deferred
 }
 
 // NextTransaction returns the next indexed transaction.
@@ -182,6 +344,7 @@ func (b *pendingBlock) ExecuteNextTransaction(
 
 	// increment transaction index even if transaction reverts
 	b.index++
+	b.resetPendingSnapshot()
 
 	executionSnapshot, output, err := vm.Run(
 		ctx,
@@ -199,15 +362,123 @@ func (b *pendingBlock) ExecuteNextTransaction(
 		// fail fast if fatal error occurs
 		return fvm.ProcedureOutput{}, err
 	}
+	b.ledgerSnapshot = b.ledgerSnapshot.Append(executionSnapshot)
 
 	b.transactionResults[txnBody.ID()] = IndexedTransactionResult{
 		ProcedureOutput: output,
 		Index:           txnIndex,
+		RequestID:       b.requestIDs[txnBody.ID()],
 	}
+	b.registerAccesses[txnBody.ID()] = newRegisterAccess(executionSnapshot)
 
 	return output, nil
 }
 
+// ApplyWrites writes the registers of the given snapshot to the ledger of the pending block,
+// without executing a transaction. The writes are committed with the pending block.
+func (b *pendingBlock) ApplyWrites(executionSnapshot *snapshot.ExecutionSnapshot) error {
+	b.resetPendingSnapshot()
+
+	err := b.ledgerState.Merge(executionSnapshot)
+	if err != nil {
+		return err
+	}
+	b.ledgerSnapshot = b.ledgerSnapshot.Append(executionSnapshot)
+
+	return nil
+}
+
+// SkipNextTransaction records the given error as the result of the next transaction,
+// without executing its body. The transaction is executed with the given context,
+// which fails the body, so its sequence number is incremented and its fees are
+// deducted like for a failed transaction.
+func (b *pendingBlock) SkipNextTransaction(
+	vm *fvm.VirtualMachine,
+	ctx fvm.Context,
+	err fvmerrors.CodedError,
+) (
+	fvm.ProcedureOutput,
+	error,
+) {
+	txnID := b.NextTransaction().ID()
+
+	output, runErr := b.ExecuteNextTransaction(vm, ctx)
+	if runErr != nil {
+		return fvm.ProcedureOutput{}, runErr
+	}
+
+	output.Err = err
+
+	result := b.transactionResults[txnID]
+	result.ProcedureOutput = output
+	b.transactionResults[txnID] = result
+
+	return output, nil
+}
+
+// PendingSnapshot returns the ledger as it is once all the transactions of the
+// pending block are executed.
+//
+// The transactions which were not executed yet are executed with the given
+// context against a copy of the working ledger, leaving the pending block unchanged.
+// The transactions the given fault rules match are executed with the faulted context
+// instead, like when the block is executed. The ledger is cached until the pending
+// block or the version of the fault rules changes.
+func (b *pendingBlock) PendingSnapshot(
+	vm *fvm.VirtualMachine,
+	ctx fvm.Context,
+	faultedCtx fvm.Context,
+	faults *faults,
+	faultsVersion uint64,
+	stats *fvmStats,
+) (
+	snapshot.StorageSnapshot,
+	error,
+) {
+	b.pendingSnapshotMu.Lock()
+	defer b.pendingSnapshotMu.Unlock()
+
+	if b.pendingSnapshot != nil && b.pendingSnapshotFaults == faultsVersion {
+		return b.pendingSnapshot, nil
+	}
+
+	ledger := b.ledgerSnapshot
+
+	for index := b.index; index < uint32(len(b.transactionIDs)); index++ {
+		txnID := b.transactionIDs[index]
+		txnBody := b.transactions[txnID]
+
+		txnCtx := ctx
+		if faults.match(&txnID, txnBody.Script) != nil {
+			txnCtx = faultedCtx
+		}
+
+		stats.ledgerViewCreated()
+		executionSnapshot, _, err := vm.Run(
+			txnCtx,
+			fvm.Transaction(txnBody, index),
+			ledger)
+		if err != nil {
+			return nil, err
+		}
+
+		ledger = ledger.Append(executionSnapshot)
+	}
+
+	b.pendingSnapshot = ledger
+	b.pendingSnapshotFaults = faultsVersion
+
+	return ledger, nil
+}
+
+// resetPendingSnapshot discards the ledger cached by PendingSnapshot.
+func (b *pendingBlock) resetPendingSnapshot() {
+	b.pendingSnapshotMu.Lock()
+	defer b.pendingSnapshotMu.Unlock()
+
+	b.pendingSnapshot = nil
+}
+
 // Events returns all events captured during the execution of the pending block.
 func (b *pendingBlock) Events() []flowgo.Event {
 	return b.events
@@ -237,6 +508,7 @@ func (b *pendingBlock) Empty() bool {
 // After this block is committed, the block timestamp will
 // contain the value of clock.Now().
 func (b *pendingBlock) SetClock(clock Clock) {
+	b.resetPendingSnapshot()
 	b.clock = clock
 	b.timestamp = clock.Now()
 }
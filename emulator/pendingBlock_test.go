@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
 	"github.com/onflow/flow-emulator/emulator"
 	"github.com/onflow/flow-emulator/types"
 	"github.com/rs/zerolog"
@@ -456,3 +457,346 @@ func TestPendingBlockSetTimestamp(t *testing.T) {
 	)
 	assert.Equal(t, expected, string(scriptResult))
 }
+
+func TestPendingBlockAdvanceTime(t *testing.T) {
+
+	t.Parallel()
+
+	b, adapter, _, _, _ := setupPendingBlockTests(t)
+	clock := testClock{
+		Time: time.Now().UTC(),
+	}
+	b.SetClock(clock)
+
+	timestamp, err := b.AdvanceTime(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, clock.Time.Add(time.Hour), timestamp)
+
+	// offsets accumulate
+	timestamp, err = b.AdvanceTime(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, clock.Time.Add(2*time.Hour), timestamp)
+
+	_, err = b.AdvanceTime(-time.Hour)
+	assert.Error(t, err)
+
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+
+	scriptResult, err := adapter.ExecuteScriptAtLatestBlock(
+		context.Background(),
+		[]byte(`
+		    pub fun main(): UFix64 {
+		        return getCurrentBlock().timestamp
+		    }
+		`),
+		[][]byte{},
+	)
+	require.NoError(t, err)
+
+	expected := fmt.Sprintf(
+		"{\"value\":\"%d.00000000\",\"type\":\"UFix64\"}\n",
+		clock.Time.Add(2*time.Hour).Unix(),
+	)
+	assert.Equal(t, expected, string(scriptResult))
+}
+
+func TestPendingBlockPriority(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithStorageLimitEnabled(false),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	// each transaction has its own proposer, so it can execute in any order
+	newTransaction := func(t *testing.T) *flowgo.TransactionBody {
+		accountKey := b.ServiceKey().AccountKey()
+		accountKey.Weight = flowsdk.AccountKeyWeightThreshold
+
+		address, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKey}, nil, 0)
+		require.NoError(t, err)
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { execute { log("hello") } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(address, 0, 0).
+			SetPayer(b.ServiceKey().Address)
+
+		err = tx.SignPayload(address, 0, signer)
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		return convert.SDKTransactionToFlow(*tx)
+	}
+
+	low := newTransaction(t)
+	first := newTransaction(t)
+	high := newTransaction(t)
+	second := newTransaction(t)
+
+	submissions := []struct {
+		tx       *flowgo.TransactionBody
+		priority int
+	}{
+		{low, -1},
+		{first, 0},
+		{high, 10},
+		{second, 0},
+	}
+	for _, submission := range submissions {
+		err = b.SendTransactionWithPriority(context.Background(), submission.tx, submission.priority)
+		require.NoError(t, err)
+	}
+
+	_, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	// ordered by priority, then in the order they were submitted
+	expected := []flowgo.Identifier{high.ID(), first.ID(), second.ID(), low.ID()}
+	for i, result := range results {
+		require.NoError(t, result.Error)
+		assert.Equal(t, expected[i].String(), result.TransactionID.String())
+	}
+}
+
+func TestPendingBlockState(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithStorageLimitEnabled(false),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	addTwoScript, counterAddress := DeployAndGenerateAddTwoScript(t, adapter)
+	serviceAddress := b.ServiceKey().Address
+	getCounterScript := GenerateGetCounterCountScript(counterAddress, serviceAddress)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	newTransaction := func(t *testing.T, script string, sequenceNumber uint64) *flowsdk.Transaction {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(script)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(serviceAddress, b.ServiceKey().Index, sequenceNumber).
+			SetPayer(serviceAddress).
+			AddAuthorizer(serviceAddress)
+
+		err := tx.SignEnvelope(serviceAddress, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	count := func(t *testing.T, pending bool) string {
+		var result *types.ScriptResult
+		var err error
+		if pending {
+			result, err = b.ExecuteScriptAtPendingBlock(context.Background(), []byte(getCounterScript), nil)
+		} else {
+			result, err = b.ExecuteScript(context.Background(), []byte(getCounterScript), nil)
+		}
+		require.NoError(t, err)
+		require.NoError(t, result.Error)
+		return result.Value.String()
+	}
+
+	sequenceNumber := b.ServiceKey().SequenceNumber
+
+	err = adapter.SendTransaction(context.Background(), *newTransaction(t, addTwoScript, sequenceNumber))
+	require.NoError(t, err)
+
+	// the transactions not executed yet are executed for the query only
+	assert.Equal(t, "0", count(t, false))
+	assert.Equal(t, "2", count(t, true))
+
+	deployScript := `
+		transaction {
+			prepare(signer: AuthAccount) {
+				signer.contracts.add(name: "Pending", code: "pub contract Pending {}".utf8)
+			}
+		}
+	`
+	err = adapter.SendTransaction(context.Background(), *newTransaction(t, deployScript, sequenceNumber+1))
+	require.NoError(t, err)
+
+	lastTx := newTransaction(t, addTwoScript, sequenceNumber+2)
+	err = adapter.SendTransaction(context.Background(), *lastTx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "0", count(t, false))
+	assert.Equal(t, "4", count(t, true))
+
+	// the transactions matched by fault rules are skipped, without counting the faults
+	rule, err := b.AddFaultRule(emulator.FaultMatcher{
+		TransactionID: lastTx.ID().String(),
+		Outcome:       emulator.FaultOutcomeFailure,
+		Count:         1,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2", count(t, true))
+	require.Len(t, b.FaultRules(), 1)
+	assert.Equal(t, uint64(0), b.FaultRules()[0].Injected)
+
+	require.True(t, b.RemoveFaultRule(rule.ID))
+	assert.Equal(t, "4", count(t, true))
+
+	// the executed transactions are read from the working ledger
+	_, err = b.ExecuteNextTransaction()
+	require.NoError(t, err)
+
+	assert.Equal(t, "0", count(t, false))
+	assert.Equal(t, "4", count(t, true))
+
+	account, err := b.GetAccount(context.Background(), convert.SDKAddressToFlow(serviceAddress))
+	require.NoError(t, err)
+	assert.NotContains(t, account.Contracts, "Pending")
+
+	account, err = b.GetAccountAtPendingBlock(context.Background(), convert.SDKAddressToFlow(serviceAddress))
+	require.NoError(t, err)
+	assert.Contains(t, account.Contracts, "Pending")
+
+	unknown, err := b.GetChain().AddressAtIndex(1000)
+	require.NoError(t, err)
+	_, err = b.GetAccountAtPendingBlock(context.Background(), unknown)
+	var accountNotFoundErr *types.AccountNotFoundError
+	assert.ErrorAs(t, err, &accountNotFoundErr)
+
+	// the pending block is left unchanged by the queries
+	results, err := b.ExecuteBlock()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		AssertTransactionSucceeded(t, result)
+	}
+
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+
+	assert.Equal(t, "4", count(t, false))
+	assert.Equal(t, "4", count(t, true))
+}
+
+func TestPendingBlockInfo(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("ListsTransactionsAndProgress", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, adapter, tx1, tx2, _ := setupPendingBlockTests(t)
+
+		info := b.PendingBlockInfo()
+		assert.Equal(t, b.PendingBlockID(), info.ID)
+		assert.Equal(t, 0, info.TransactionCount)
+		assert.Empty(t, info.Transactions)
+
+		err := adapter.SendTransaction(context.Background(), *tx1)
+		require.NoError(t, err)
+
+		err = adapter.SendTransaction(context.Background(), *tx2)
+		require.NoError(t, err)
+
+		info = b.PendingBlockInfo()
+		assert.Equal(t, b.PendingBlockID(), info.ID)
+		assert.Equal(t, b.PendingBlockTimestamp(), info.Timestamp)
+		assert.Equal(t, 2, info.TransactionCount)
+		assert.Equal(t, 0, info.ExecutedCount)
+		require.Len(t, info.Transactions, 2)
+		assert.Equal(t, flowgo.Identifier(tx1.ID()), info.Transactions[0].ID)
+		assert.Equal(t, flowgo.Identifier(tx2.ID()), info.Transactions[1].ID)
+		assert.False(t, info.Transactions[0].AddedAt.IsZero())
+
+		_, err = b.ExecuteNextTransaction()
+		require.NoError(t, err)
+
+		info = b.PendingBlockInfo()
+		assert.Equal(t, 1, info.ExecutedCount)
+		assert.True(t, info.Transactions[0].Executed)
+		assert.False(t, info.Transactions[1].Executed)
+	})
+
+	t.Run("AddedAtFollowsClock", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, adapter, tx1, _, _ := setupPendingBlockTests(t)
+		clock := testClock{
+			Time: time.Now().Add(time.Hour * 24).UTC(),
+		}
+		b.SetClock(clock)
+
+		err := adapter.SendTransaction(context.Background(), *tx1)
+		require.NoError(t, err)
+
+		info := b.PendingBlockInfo()
+		require.Len(t, info.Transactions, 1)
+		assert.Equal(t, clock.Time, info.Transactions[0].AddedAt)
+	})
+
+	t.Run("RemoveTransaction", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, adapter, tx1, tx2, _ := setupPendingBlockTests(t)
+
+		err := adapter.SendTransaction(context.Background(), *tx1)
+		require.NoError(t, err)
+
+		err = adapter.SendTransaction(context.Background(), *tx2)
+		require.NoError(t, err)
+
+		err = b.RemovePendingTransaction(flowgo.Identifier(tx1.ID()))
+		require.NoError(t, err)
+
+		info := b.PendingBlockInfo()
+		require.Len(t, info.Transactions, 1)
+		assert.Equal(t, flowgo.Identifier(tx2.ID()), info.Transactions[0].ID)
+
+		err = b.RemovePendingTransaction(flowgo.Identifier(tx1.ID()))
+		var notFoundErr *types.TransactionNotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, tx2.ID(), results[0].TransactionID)
+	})
+
+	t.Run("RemoveTransactionDuringExecution", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, adapter, tx1, tx2, _ := setupPendingBlockTests(t)
+
+		err := adapter.SendTransaction(context.Background(), *tx1)
+		require.NoError(t, err)
+
+		err = adapter.SendTransaction(context.Background(), *tx2)
+		require.NoError(t, err)
+
+		_, err = b.ExecuteNextTransaction()
+		require.NoError(t, err)
+
+		err = b.RemovePendingTransaction(flowgo.Identifier(tx2.ID()))
+		var midExecutionErr *types.PendingBlockMidExecutionError
+		assert.ErrorAs(t, err, &midExecutionErr)
+	})
+}
@@ -0,0 +1,105 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package emulator
+
+import (
+	"time"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// PendingBlockInfo describes the contents of the pending block.
+type PendingBlockInfo struct {
+	ID     flowgo.Identifier `json:"id"`
+	Height uint64            `json:"height"`
+	View   uint64            `json:"view"`
+	// Timestamp is the timestamp the block will have once committed.
+	Timestamp time.Time `json:"timestamp"`
+	// TransactionCount is the number of transactions in the pending block.
+	TransactionCount int `json:"transactionCount"`
+	// ExecutedCount is the number of transactions of the pending block executed so far.
+	ExecutedCount int                  `json:"executedCount"`
+	Transactions  []PendingTransaction `json:"transactions"`
+}
+
+// PendingTransaction is a transaction of the pending block, in execution order.
+type PendingTransaction struct {
+	ID        flowgo.Identifier `json:"id"`
+	Priority  int               `json:"priority"`
+	RequestID string            `json:"requestId,omitempty"`
+	Executed  bool              `json:"executed"`
+	// AddedAt is the time the transaction was added to the pending block.
+	AddedAt time.Time `json:"addedAt"`
+}
+
+// PendingBlockInfo returns the contents of the pending block and its execution progress.
+func (b *Blockchain) PendingBlockInfo() *PendingBlockInfo {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	pending := b.pendingBlock
+
+	transactions := make([]PendingTransaction, len(pending.transactionIDs))
+	for i, txID := range pending.transactionIDs {
+		transactions[i] = PendingTransaction{
+			ID:        txID,
+			Priority:  pending.priorities[txID],
+			RequestID: pending.requestIDs[txID],
+			Executed:  i < int(pending.index),
+			AddedAt:   pending.addedAt[txID],
+		}
+	}
+
+	executed := int(pending.index)
+	if executed > len(transactions) {
+		executed = len(transactions)
+	}
+
+	return &PendingBlockInfo{
+		ID:               pending.ID(),
+		Height:           pending.height,
+		View:             pending.view,
+		Timestamp:        pending.timestamp,
+		TransactionCount: len(transactions),
+		ExecutedCount:    executed,
+		Transactions:     transactions,
+	}
+}
+
+// RemovePendingTransaction drops a transaction from the pending block.
+// Transactions can only be removed before the execution of the pending block starts.
+func (b *Blockchain) RemovePendingTransaction(txID flowgo.Identifier) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.conf.ReadOnly {
+		return &types.ReadOnlyError{}
+	}
+
+	if b.pendingBlock.ExecutionStarted() {
+		return &types.PendingBlockMidExecutionError{BlockID: b.pendingBlock.ID()}
+	}
+
+	if !b.pendingBlock.RemoveTransaction(txID) {
+		return &types.TransactionNotFoundError{ID: txID}
+	}
+
+	return nil
+}
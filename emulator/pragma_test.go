@@ -45,7 +45,7 @@ func TestSourceFilePragmaForScript(t *testing.T) {
 			log("42")
 		}`
 
-	scriptResult, err := b.ExecuteScript([]byte(scriptCode), [][]byte{})
+	scriptResult, err := b.ExecuteScript(context.Background(), []byte(scriptCode), [][]byte{})
 	require.NoError(t, err)
 	require.NoError(t, scriptResult.Error)
 
@@ -0,0 +1,250 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"sort"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser"
+	"github.com/onflow/flow-go/fvm/meter"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// ContractProfile is the computation usage attributed to a single contract.
+//
+// Cadence does not report which location performed a metered operation, so
+// usage is attributed inclusively: the full computation of a transaction is
+// added to every contract the transaction imports. Imports of a whole account
+// (`import 0x1`) are attributed to the account's address location.
+type ContractProfile struct {
+	Location        string          `json:"location"`
+	Transactions    uint64          `json:"transactions"`
+	ComputationUsed uint64          `json:"computationUsed"`
+	Intensities     map[string]uint `json:"intensities"`
+}
+
+// ComputationReport is the computation used by a transaction, broken down by
+// computation kind, e.g. Statement, Loop, FunctionInvocation, GetValue or SetValue.
+type ComputationReport struct {
+	TransactionID   flowgo.Identifier `json:"transactionId"`
+	ComputationUsed uint64            `json:"computationUsed"`
+	Intensities     map[string]uint   `json:"intensities"`
+}
+
+// computationReportCacheSize is the number of most recently executed transactions
+// whose computation reports are kept.
+const computationReportCacheSize = 10000
+
+// computationReports keeps the computation reports of the most recently executed transactions, by ID.
+type computationReports struct {
+	cache *lru.Cache
+}
+
+func newComputationReports() *computationReports {
+	cache, err := lru.New(computationReportCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &computationReports{cache: cache}
+}
+
+func (r *computationReports) get(txID flowgo.Identifier) (*ComputationReport, bool) {
+	report, ok := r.cache.Get(txID)
+	if !ok {
+		return nil, false
+	}
+	return report.(*ComputationReport), true
+}
+
+func (r *computationReports) set(txID flowgo.Identifier, report *ComputationReport) {
+	r.cache.Add(txID, report)
+}
+
+// importedLocationsCacheSize is the number of scripts whose imported contracts are kept,
+// so transactions running the same script are only parsed once.
+const importedLocationsCacheSize = 1000
+
+// scriptUsage is the computation usage of the transactions running a script,
+// which is not attributed to the contracts the script imports yet.
+type scriptUsage struct {
+	code            []byte
+	transactions    uint64
+	computationUsed uint64
+	intensities     map[string]uint
+}
+
+type contractProfiler struct {
+	mu       sync.Mutex
+	profiles map[string]*ContractProfile
+	// pending is the usage recorded since the profiles were last ranked, by script.
+	// Scripts are only parsed when ranking, as usage is recorded while executing transactions.
+	pending map[string]*scriptUsage
+	// imports caches the contracts imported by the scripts, by script
+	imports *lru.Cache
+}
+
+func newContractProfiler() *contractProfiler {
+	imports, err := lru.New(importedLocationsCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &contractProfiler{
+		profiles: make(map[string]*ContractProfile),
+		pending:  make(map[string]*scriptUsage),
+		imports:  imports,
+	}
+}
+
+// record records the computation of a transaction, to attribute it to the contracts
+// the transaction imports when ranking.
+func (p *contractProfiler) record(
+	code []byte,
+	computationUsed uint64,
+	intensities meter.MeteredComputationIntensities,
+) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	usage, ok := p.pending[string(code)]
+	if !ok {
+		usage = &scriptUsage{
+			code:        code,
+			intensities: make(map[string]uint),
+		}
+		p.pending[string(code)] = usage
+	}
+
+	usage.transactions++
+	usage.computationUsed += computationUsed
+	for kind, intensity := range intensities {
+		usage.intensities[kind.String()] += intensity
+	}
+}
+
+// attribute attributes the pending usage to the contracts imported by the scripts.
+func (p *contractProfiler) attribute() {
+	for key, usage := range p.pending {
+		delete(p.pending, key)
+
+		var locations []string
+		if cached, ok := p.imports.Get(key); ok {
+			locations = cached.([]string)
+		} else {
+			locations = importedLocations(usage.code)
+			p.imports.Add(key, locations)
+		}
+
+		for _, location := range locations {
+			profile, ok := p.profiles[location]
+			if !ok {
+				profile = &ContractProfile{
+					Location:    location,
+					Intensities: make(map[string]uint),
+				}
+				p.profiles[location] = profile
+			}
+
+			profile.Transactions += usage.transactions
+			profile.ComputationUsed += usage.computationUsed
+			for kind, intensity := range usage.intensities {
+				profile.Intensities[kind] += intensity
+			}
+		}
+	}
+}
+
+// ranking returns copies of all profiles, ordered by descending computation usage.
+func (p *contractProfiler) ranking() []ContractProfile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.attribute()
+
+	result := make([]ContractProfile, 0, len(p.profiles))
+	for _, profile := range p.profiles {
+		intensities := make(map[string]uint, len(profile.Intensities))
+		for kind, intensity := range profile.Intensities {
+			intensities[kind] = intensity
+		}
+
+		result = append(result, ContractProfile{
+			Location:        profile.Location,
+			Transactions:    profile.Transactions,
+			ComputationUsed: profile.ComputationUsed,
+			Intensities:     intensities,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ComputationUsed != result[j].ComputationUsed {
+			return result[i].ComputationUsed > result[j].ComputationUsed
+		}
+		return result[i].Location < result[j].Location
+	})
+
+	return result
+}
+
+func (p *contractProfiler) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.profiles = make(map[string]*ContractProfile)
+	p.pending = make(map[string]*scriptUsage)
+}
+
+// importedLocations returns the IDs of the contracts imported by the given code.
+func importedLocations(code []byte) []string {
+	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var locations []string
+
+	add := func(location common.Location) {
+		id := location.ID()
+		if _, ok := seen[id]; ok {
+			return
+		}
+		seen[id] = struct{}{}
+		locations = append(locations, id)
+	}
+
+	for _, declaration := range program.ImportDeclarations() {
+		addressLocation, ok := declaration.Location.(common.AddressLocation)
+		if !ok || len(declaration.Identifiers) == 0 {
+			add(declaration.Location)
+			continue
+		}
+
+		for _, identifier := range declaration.Identifiers {
+			add(common.AddressLocation{
+				Address: addressLocation.Address,
+				Name:    identifier.Identifier,
+			})
+		}
+	}
+
+	return locations
+}
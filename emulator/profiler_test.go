@@ -0,0 +1,211 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestContractProfiles(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithStorageLimitEnabled(false),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	addTwoScript, counterAddress := DeployAndGenerateAddTwoScript(t, adapter)
+
+	b.ResetContractProfiles()
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(addTwoScript)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address).
+		AddAuthorizer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = adapter.SendTransaction(context.Background(), *tx)
+	require.NoError(t, err)
+
+	txResult, err := b.ExecuteNextTransaction()
+	require.NoError(t, err)
+	AssertTransactionSucceeded(t, txResult)
+
+	profiles := b.ContractProfiles()
+	require.Len(t, profiles, 1)
+
+	profile := profiles[0]
+	// the script imports the whole account, so usage is attributed to the address
+	assert.Equal(t, fmt.Sprintf("A.%s", counterAddress.Hex()), profile.Location)
+	assert.Equal(t, uint64(1), profile.Transactions)
+	assert.Equal(t, txResult.ComputationUsed, profile.ComputationUsed)
+	assert.NotEmpty(t, profile.Intensities)
+
+	// the usage of transactions running the same script adds up across rankings
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+
+	tx = flowsdk.NewTransaction().
+		SetScript([]byte(addTwoScript)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address).
+		AddAuthorizer(b.ServiceKey().Address)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = adapter.SendTransaction(context.Background(), *tx)
+	require.NoError(t, err)
+
+	secondResult, err := b.ExecuteNextTransaction()
+	require.NoError(t, err)
+	AssertTransactionSucceeded(t, secondResult)
+
+	profiles = b.ContractProfiles()
+	require.Len(t, profiles, 1)
+	assert.Equal(t, uint64(2), profiles[0].Transactions)
+	assert.Equal(t, txResult.ComputationUsed+secondResult.ComputationUsed, profiles[0].ComputationUsed)
+
+	b.ResetContractProfiles()
+	assert.Empty(t, b.ContractProfiles())
+}
+
+func TestComputationReport(t *testing.T) {
+
+	t.Parallel()
+
+	const script = `
+		transaction {
+			prepare(signer: AuthAccount) {
+				var i = 0
+				while i < 10 {
+					i = i + 1
+				}
+				signer.save(i, to: /storage/counter)
+			}
+		}
+	`
+
+	execute := func(t *testing.T, b *emulator.Blockchain) flowgo.Identifier {
+		logger := zerolog.Nop()
+		adapter := adapters.NewSDKAdapter(&logger, b)
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(script)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address).
+			AddAuthorizer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = adapter.SendTransaction(context.Background(), *tx)
+		require.NoError(t, err)
+
+		txResult, err := b.ExecuteNextTransaction()
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, txResult)
+
+		// the breakdown is part of every transaction result
+		assert.Positive(t, txResult.ComputationIntensities["Loop"])
+		assert.Positive(t, txResult.ComputationIntensities["Statement"])
+		assert.Positive(t, txResult.ComputationIntensities["SetValue"])
+
+		return flowgo.Identifier(tx.ID())
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New(emulator.WithComputationReporting(true))
+		require.NoError(t, err)
+
+		txID := execute(t, b)
+
+		report, err := b.GetComputationReport(context.Background(), txID)
+		require.NoError(t, err)
+
+		assert.Equal(t, txID, report.TransactionID)
+		assert.Positive(t, report.ComputationUsed)
+		assert.Equal(t, uint(10), report.Intensities["Loop"])
+		assert.Positive(t, report.Intensities["SetValue"])
+	})
+
+	t.Run("rolled back", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New(emulator.WithComputationReporting(true))
+		require.NoError(t, err)
+
+		latest, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+
+		txID := execute(t, b)
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+
+		err = b.RollbackToBlockHeight(latest.Header.Height)
+		require.NoError(t, err)
+
+		_, err = b.GetComputationReport(context.Background(), txID)
+		var notFoundErr *types.ComputationReportNotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		txID := execute(t, b)
+
+		_, err = b.GetComputationReport(context.Background(), txID)
+		var notFoundErr *types.ComputationReportNotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+}
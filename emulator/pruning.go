@@ -0,0 +1,364 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// prunedHeightKey is the metadata key of the oldest block height whose ledger state is kept.
+const prunedHeightKey = "prunedHeight"
+
+// pinnedHeightsKey is the metadata key of the block heights whose ledger state is not pruned.
+const pinnedHeightsKey = "pinnedHeights"
+
+// statePruner garbage-collects the register versions of pruned blocks in the background,
+// so committing blocks does not wait for the storage to be pruned. The store to prune is
+// given on every schedule, so a store swapped in by SwitchStorage or standby is pruned
+// instead of the one the blockchain was started with.
+type statePruner struct {
+	mu      sync.Mutex
+	pruner  storage.LedgerPruner
+	logger  *zerolog.Logger
+	running bool
+	// done while no pruning is running
+	wg sync.WaitGroup
+	// ctx is canceled once the pruner is stopped
+	ctx    context.Context
+	cancel context.CancelFunc
+	// height is the height the ledger is pruned up to, and pruned the one it was pruned to
+	height uint64
+	pruned uint64
+	// pinned are the heights whose ledger is kept
+	pinned []uint64
+}
+
+func newStatePruner(logger *zerolog.Logger) *statePruner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &statePruner{
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// schedule prunes the ledger of the given store below the given height, except at the
+// pinned heights, once the pruning in progress is done.
+func (p *statePruner) schedule(pruner storage.LedgerPruner, height uint64, pinned []uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pruner != p.pruner {
+		p.pruner = pruner
+		p.pruned = 0
+	}
+	p.height = height
+	p.pinned = pinned
+	if p.running || p.ctx.Err() != nil {
+		return
+	}
+	p.running = true
+	p.wg.Add(1)
+	go p.run()
+}
+
+// reset drops the store being pruned, and waits for the pruning in progress to finish,
+// so the store can be closed. Nothing is pruned until the next schedule.
+func (p *statePruner) reset() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.pruner = nil
+	p.height = 0
+	p.pruned = 0
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// stop interrupts the pruning in progress and waits for it to end.
+// Nothing is pruned afterwards.
+func (p *statePruner) stop() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.cancel()
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+func (p *statePruner) run() {
+	defer p.wg.Done()
+
+	for {
+		p.mu.Lock()
+		pruner, height, pinned := p.pruner, p.height, p.pinned
+		if pruner == nil || height == p.pruned || p.ctx.Err() != nil {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+
+		// an interrupted pruning is finished by the next one, after a restart
+		err := pruner.PruneLedger(p.ctx, height, pinned)
+		switch {
+		case p.ctx.Err() != nil:
+		case err != nil:
+			p.logger.Warn().Err(err).Msgf("❗  Failed to prune the state below block %d", height)
+		default:
+			p.logger.Debug().Msgf("✂️   State below block %d pruned", height)
+		}
+
+		p.mu.Lock()
+		if pruner == p.pruner {
+			p.pruned = height
+		}
+		p.mu.Unlock()
+	}
+}
+
+// pruneState drops the ledger state of the blocks beyond the configured state history,
+// once the block at the given height is committed. Reading the state of the pruned
+// blocks fails right away, and their register versions are garbage-collected in the
+// background. Failures are logged, and do not fail the commit.
+func (b *Blockchain) pruneState(height uint64) {
+	history := b.conf.StateHistory
+	if history == 0 || height < history {
+		return
+	}
+
+	oldest := height - history + 1
+	if oldest <= b.prunedHeight {
+		return
+	}
+
+	pruner, ok := b.storage.(storage.LedgerPruner)
+	if !ok {
+		b.conf.ServerLogger.Warn().Msg("❗  Failed to prune the state: storage doesn't support pruning")
+		return
+	}
+
+	err := savePrunedHeight(b.storage, oldest)
+	if err != nil {
+		b.conf.ServerLogger.Warn().Err(err).Msg("❗  Failed to prune the state")
+		return
+	}
+
+	b.prunedHeight = oldest
+	b.statePruner.schedule(pruner, oldest, b.sortedPinnedHeights())
+}
+
+// checkStateAvailable returns an error if the ledger state at the given height was pruned.
+func (b *Blockchain) checkStateAvailable(height uint64) error {
+	if _, pinned := b.pinnedHeights[height]; height < b.prunedHeight && !pinned {
+		return &types.StatePrunedError{
+			Height:       height,
+			OldestHeight: b.prunedHeight,
+		}
+	}
+	return nil
+}
+
+// ledgerByHeight returns the ledger state at the given height, unless it was pruned.
+func (b *Blockchain) ledgerByHeight(ctx context.Context, height uint64) (snapshot.StorageSnapshot, error) {
+	err := b.checkStateAvailable(height)
+	if err != nil {
+		return nil, err
+	}
+	return b.storage.LedgerByHeight(ctx, height)
+}
+
+// rollbackPrunedState drops the pinned heights above the given height, whose blocks
+// are rolled back. The state at a pinned height below the pruned height is kept, so
+// the state is pruned below it once the blockchain is rolled back to it.
+func (b *Blockchain) rollbackPrunedState(height uint64) error {
+	if height < b.prunedHeight {
+		err := savePrunedHeight(b.storage, height)
+		if err != nil {
+			return fmt.Errorf("failed to save the pruned height: %w", err)
+		}
+		b.prunedHeight = height
+	}
+
+	pinnedHeights := make(map[uint64]struct{}, len(b.pinnedHeights))
+	for pinnedHeight := range b.pinnedHeights {
+		if pinnedHeight <= height {
+			pinnedHeights[pinnedHeight] = struct{}{}
+		}
+	}
+	if len(pinnedHeights) == len(b.pinnedHeights) {
+		return nil
+	}
+
+	return b.setPinnedHeights(pinnedHeights)
+}
+
+// PinBlockHeight exempts the ledger state at the given height from pruning, so the
+// state of key fixture blocks can be read while the state of other old blocks is pruned.
+// The pinned heights are kept in the storage.
+func (b *Blockchain) PinBlockHeight(height uint64) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	latestBlock, err := b.getLatestBlock(context.Background())
+	if err != nil {
+		return err
+	}
+	if height > latestBlock.Header.Height {
+		return types.NewInvalidArgumentError(
+			fmt.Sprintf(
+				"cannot pin block height %d, the latest block is at height %d",
+				height,
+				latestBlock.Header.Height,
+			),
+		)
+	}
+
+	err = b.checkStateAvailable(height)
+	if err != nil {
+		return err
+	}
+
+	pinnedHeights := make(map[uint64]struct{}, len(b.pinnedHeights)+1)
+	for pinnedHeight := range b.pinnedHeights {
+		pinnedHeights[pinnedHeight] = struct{}{}
+	}
+	pinnedHeights[height] = struct{}{}
+
+	return b.setPinnedHeights(pinnedHeights)
+}
+
+// UnpinBlockHeight lets the ledger state at the given height be pruned again,
+// the next time the state is pruned.
+func (b *Blockchain) UnpinBlockHeight(height uint64) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if _, ok := b.pinnedHeights[height]; !ok {
+		return types.NewInvalidArgumentError(fmt.Sprintf("block height %d is not pinned", height))
+	}
+
+	pinnedHeights := make(map[uint64]struct{}, len(b.pinnedHeights))
+	for pinnedHeight := range b.pinnedHeights {
+		if pinnedHeight != height {
+			pinnedHeights[pinnedHeight] = struct{}{}
+		}
+	}
+
+	return b.setPinnedHeights(pinnedHeights)
+}
+
+// PinnedBlockHeights returns the block heights whose ledger state is not pruned, in ascending order.
+func (b *Blockchain) PinnedBlockHeights() []uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.sortedPinnedHeights()
+}
+
+func (b *Blockchain) sortedPinnedHeights() []uint64 {
+	heights := make([]uint64, 0, len(b.pinnedHeights))
+	for height := range b.pinnedHeights {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights
+}
+
+// setPinnedHeights saves the given pinned heights, and uses them once they are saved.
+func (b *Blockchain) setPinnedHeights(pinnedHeights map[uint64]struct{}) error {
+	heights := make([]uint64, 0, len(pinnedHeights))
+	for height := range pinnedHeights {
+		heights = append(heights, height)
+	}
+
+	err := savePinnedHeights(b.storage, heights)
+	if err != nil {
+		return fmt.Errorf("failed to save the pinned heights: %w", err)
+	}
+
+	b.pinnedHeights = pinnedHeights
+	return nil
+}
+
+func savePrunedHeight(store storage.Store, height uint64) error {
+	encoded := make([]byte, 8)
+	binary.BigEndian.PutUint64(encoded, height)
+	return store.PutMeta(context.Background(), prunedHeightKey, encoded)
+}
+
+// loadPrunedHeight returns the oldest height whose ledger state is kept in the store.
+func loadPrunedHeight(store storage.Store) (uint64, error) {
+	encoded, err := store.GetMeta(context.Background(), prunedHeightKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the pruned height: %w", err)
+	}
+	if len(encoded) != 8 {
+		return 0, fmt.Errorf("invalid pruned height")
+	}
+	return binary.BigEndian.Uint64(encoded), nil
+}
+
+func savePinnedHeights(store storage.Store, heights []uint64) error {
+	encoded := make([]byte, 8*len(heights))
+	for i, height := range heights {
+		binary.BigEndian.PutUint64(encoded[8*i:], height)
+	}
+	return store.PutMeta(context.Background(), pinnedHeightsKey, encoded)
+}
+
+// loadPinnedHeights returns the block heights whose ledger state is not pruned in the store.
+func loadPinnedHeights(store storage.Store) (map[uint64]struct{}, error) {
+	encoded, err := store.GetMeta(context.Background(), pinnedHeightsKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return map[uint64]struct{}{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the pinned heights: %w", err)
+	}
+	if len(encoded)%8 != 0 {
+		return nil, fmt.Errorf("invalid pinned heights")
+	}
+
+	heights := make(map[uint64]struct{}, len(encoded)/8)
+	for i := 0; i < len(encoded); i += 8 {
+		heights[binary.BigEndian.Uint64(encoded[i:])] = struct{}{}
+	}
+	return heights, nil
+}
@@ -0,0 +1,261 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/templates"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestStateHistory(t *testing.T) {
+
+	t.Parallel()
+
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	b, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithStateHistory(3),
+	)
+	require.NoError(t, err)
+
+	genesisDelta, err := store.LedgerDeltaByHeight(context.Background(), 0)
+	require.NoError(t, err)
+
+	// transactions of the service account write the registers of its sequence number and balance
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+	for i := 0; i < 2; i++ {
+		_, err := adapter.CreateAccount(context.Background(), nil, []templates.Contract{}, 0)
+		require.NoError(t, err)
+	}
+
+	latest, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	oldest := latest.Header.Height - 2
+
+	serviceAddress := b.GetChain().ServiceAddress()
+	registerIDs := flowgo.RegisterIDs{flowgo.AccountStatusRegisterID(serviceAddress)}
+
+	t.Run("pruned state", func(t *testing.T) {
+		_, err := b.GetRegisterValues(context.Background(), registerIDs, oldest-1)
+		var prunedErr *types.StatePrunedError
+		require.ErrorAs(t, err, &prunedErr)
+		assert.Equal(t, oldest, prunedErr.OldestHeight)
+
+		_, err = b.GetAccountAtBlockHeight(context.Background(), serviceAddress, oldest-1)
+		assert.ErrorAs(t, err, &prunedErr)
+
+		_, err = b.ExecuteScriptAtBlockHeight(context.Background(), []byte(`pub fun main() {}`), nil, oldest-1)
+		assert.ErrorAs(t, err, &prunedErr)
+
+		err = b.RollbackToBlockHeight(oldest - 1)
+		assert.ErrorAs(t, err, &prunedErr)
+	})
+
+	t.Run("kept state", func(t *testing.T) {
+		_, err := b.GetAccountAtBlockHeight(context.Background(), serviceAddress, oldest)
+		assert.NoError(t, err)
+
+		result, err := b.ExecuteScriptAtBlockHeight(context.Background(), []byte(`pub fun main() {}`), nil, oldest)
+		require.NoError(t, err)
+		assert.NoError(t, result.Error)
+	})
+
+	t.Run("garbage collection", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			delta, err := store.LedgerDeltaByHeight(context.Background(), 0)
+			require.NoError(t, err)
+			return len(delta.WriteSet) < len(genesisDelta.WriteSet)
+		}, 30*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		// the pruning of the previous blockchain ends before the store is reopened
+		b.Close()
+
+		restarted, err := emulator.New(
+			emulator.WithStore(store),
+			emulator.WithStateHistory(10),
+		)
+		require.NoError(t, err)
+
+		_, err = restarted.GetRegisterValues(context.Background(), registerIDs, oldest-1)
+		var prunedErr *types.StatePrunedError
+		assert.ErrorAs(t, err, &prunedErr)
+	})
+}
+
+func TestStateHistorySwitchStorage(t *testing.T) {
+
+	t.Parallel()
+
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	b, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithStateHistory(3),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+	for i := 0; i < 4; i++ {
+		_, err := adapter.CreateAccount(context.Background(), nil, []templates.Contract{}, 0)
+		require.NoError(t, err)
+	}
+
+	latest, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	oldest := latest.Header.Height - 2
+
+	target, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	_, err = b.SwitchStorage(context.Background(), target)
+	require.NoError(t, err)
+
+	// the pruned height is copied along with the blocks
+	restarted, err := emulator.New(
+		emulator.WithStore(target),
+		emulator.WithStateHistory(10),
+	)
+	require.NoError(t, err)
+
+	serviceAddress := b.GetChain().ServiceAddress()
+	_, err = restarted.GetAccountAtBlockHeight(context.Background(), serviceAddress, oldest-1)
+	var prunedErr *types.StatePrunedError
+	assert.ErrorAs(t, err, &prunedErr)
+
+	// the number of register versions kept for the copied blocks
+	copiedVersions := func() int {
+		versions := 0
+		for height := uint64(0); height <= latest.Header.Height; height++ {
+			delta, err := target.LedgerDeltaByHeight(context.Background(), height)
+			require.NoError(t, err)
+			versions += len(delta.WriteSet)
+		}
+		return versions
+	}
+	copied := copiedVersions()
+
+	for i := 0; i < 4; i++ {
+		_, err := adapter.CreateAccount(context.Background(), nil, []templates.Contract{}, 0)
+		require.NoError(t, err)
+	}
+
+	// the switched-to store is pruned, not the one the blockchain was started with
+	assert.Eventually(t, func() bool {
+		return copiedVersions() < copied
+	}, 30*time.Second, 10*time.Millisecond)
+}
+
+func TestStateHistoryPinnedHeights(t *testing.T) {
+
+	t.Parallel()
+
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	b, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithStateHistory(2),
+	)
+	require.NoError(t, err)
+	defer b.Close()
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+	_, err = adapter.CreateAccount(context.Background(), nil, []templates.Contract{}, 0)
+	require.NoError(t, err)
+
+	fixture, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	pinned := fixture.Header.Height
+
+	require.NoError(t, b.PinBlockHeight(pinned))
+	assert.Equal(t, []uint64{pinned}, b.PinnedBlockHeights())
+
+	for i := 0; i < 4; i++ {
+		_, err := adapter.CreateAccount(context.Background(), nil, []templates.Contract{}, 0)
+		require.NoError(t, err)
+	}
+
+	serviceAddress := b.GetChain().ServiceAddress()
+	var prunedErr *types.StatePrunedError
+
+	t.Run("pinned height", func(t *testing.T) {
+		_, err := b.GetAccountAtBlockHeight(context.Background(), serviceAddress, pinned)
+		require.NoError(t, err)
+
+		_, err = b.GetAccountAtBlockHeight(context.Background(), serviceAddress, pinned+1)
+		assert.ErrorAs(t, err, &prunedErr)
+	})
+
+	t.Run("pin pruned height", func(t *testing.T) {
+		err := b.PinBlockHeight(pinned + 1)
+		assert.ErrorAs(t, err, &prunedErr)
+
+		var invalidErr *types.InvalidArgumentError
+		err = b.PinBlockHeight(pinned + 100)
+		assert.ErrorAs(t, err, &invalidErr)
+
+		err = b.UnpinBlockHeight(pinned + 1)
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		require.NoError(t, b.RollbackToBlockHeight(pinned))
+
+		latest, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, pinned, latest.Header.Height)
+
+		_, err = b.GetAccountAtBlockHeight(context.Background(), serviceAddress, pinned)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{pinned}, b.PinnedBlockHeights())
+	})
+
+	t.Run("unpin", func(t *testing.T) {
+		_, err := adapter.CreateAccount(context.Background(), nil, []templates.Contract{}, 0)
+		require.NoError(t, err)
+		_, err = adapter.CreateAccount(context.Background(), nil, []templates.Contract{}, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, b.UnpinBlockHeight(pinned))
+		assert.Empty(t, b.PinnedBlockHeights())
+
+		_, err = b.GetAccountAtBlockHeight(context.Background(), serviceAddress, pinned)
+		assert.ErrorAs(t, err, &prunedErr)
+	})
+}
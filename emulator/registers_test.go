@@ -0,0 +1,81 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestGetRegisterValues(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	before, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	address, err := adapter.CreateAccount(context.Background(), nil, nil, 0)
+	require.NoError(t, err)
+
+	after, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	registerIDs := flowgo.RegisterIDs{
+		flowgo.AccountStatusRegisterID(convert.SDKAddressToFlow(address)),
+		flowgo.NewRegisterID(string(convert.SDKAddressToFlow(address).Bytes()), "missing"),
+	}
+
+	t.Run("before the account is created", func(t *testing.T) {
+		values, err := b.GetRegisterValues(context.Background(), registerIDs, before.Header.Height)
+		require.NoError(t, err)
+		require.Len(t, values, 2)
+		assert.Empty(t, values[0])
+		assert.Empty(t, values[1])
+	})
+
+	t.Run("after the account is created", func(t *testing.T) {
+		values, err := b.GetRegisterValues(context.Background(), registerIDs, after.Header.Height)
+		require.NoError(t, err)
+		require.Len(t, values, 2)
+		assert.NotEmpty(t, values[0])
+		assert.Empty(t, values[1])
+	})
+
+	t.Run("unknown height", func(t *testing.T) {
+		_, err := b.GetRegisterValues(context.Background(), registerIDs, after.Header.Height+1)
+		var notFound *types.BlockNotFoundByHeightError
+		assert.ErrorAs(t, err, &notFound)
+	})
+}
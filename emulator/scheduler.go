@@ -0,0 +1,133 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+	"github.com/onflow/flow-emulator/utils/requestid"
+)
+
+// scheduledTransaction is a transaction waiting for the block it is scheduled in.
+type scheduledTransaction struct {
+	tx        flowgo.TransactionBody
+	requestID string
+	height    uint64
+}
+
+// AddScheduledTransaction validates a transaction and schedules it to be included
+// in the block at the given height, once the preceding block is committed.
+//
+// Transactions scheduled at the height of the pending block are added to it right away.
+// Transactions which would expire before the block at the given height are rejected.
+// Scheduled transactions are reported as pending until they are executed,
+// and are kept in memory only.
+func (b *Blockchain) AddScheduledTransaction(ctx context.Context, tx flowgo.TransactionBody, height uint64) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.conf.ReadOnly {
+		return &types.ReadOnlyError{}
+	}
+
+	if height == b.pendingBlock.height {
+		return b.addTransaction(ctx, tx, 0, false)
+	}
+
+	if height < b.pendingBlock.height {
+		return types.NewInvalidArgumentError(
+			fmt.Sprintf(
+				"cannot schedule transaction at height %d, the pending block is at height %d",
+				height,
+				b.pendingBlock.height,
+			),
+		)
+	}
+
+	err := b.validateNewTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	err = b.checkScheduledExpiry(ctx, tx, height)
+	if err != nil {
+		return err
+	}
+
+	b.scheduled = append(b.scheduled, scheduledTransaction{
+		tx:        tx,
+		requestID: requestid.FromContext(ctx),
+		height:    height,
+	})
+
+	// transactions scheduled at the same height are included in the order they were scheduled
+	sort.SliceStable(b.scheduled, func(i, j int) bool {
+		return b.scheduled[i].height < b.scheduled[j].height
+	})
+
+	return nil
+}
+
+// getScheduledTransaction returns the scheduled transaction with the given ID, if any.
+func (b *Blockchain) getScheduledTransaction(txID flowgo.Identifier) *flowgo.TransactionBody {
+	for _, scheduled := range b.scheduled {
+		if scheduled.tx.ID() == txID {
+			return &scheduled.tx
+		}
+	}
+
+	return nil
+}
+
+// includeScheduledTransactions adds the transactions scheduled at the height
+// of the pending block to it. Transactions scheduled at lower heights,
+// e.g. before a rollback, are added as well.
+func (b *Blockchain) includeScheduledTransactions() {
+	remaining := make([]scheduledTransaction, 0, len(b.scheduled))
+
+	for _, scheduled := range b.scheduled {
+		if scheduled.height > b.pendingBlock.height {
+			remaining = append(remaining, scheduled)
+			continue
+		}
+
+		b.pendingBlock.AddTransaction(scheduled.tx, scheduled.requestID, 0)
+		b.includedScheduled = append(b.includedScheduled, scheduled)
+	}
+
+	b.scheduled = remaining
+}
+
+// rescheduleIncludedTransactions schedules the scheduled transactions added to the
+// pending block again, before the pending block is recreated without them.
+func (b *Blockchain) rescheduleIncludedTransactions() {
+	if len(b.includedScheduled) == 0 {
+		return
+	}
+
+	// the included transactions are scheduled at heights up to the pending block,
+	// so the scheduled transactions stay ordered by height
+	b.scheduled = append(b.includedScheduled, b.scheduled...)
+	b.includedScheduled = nil
+}
@@ -0,0 +1,145 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestAddScheduledTransaction(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	newTransaction := func(t *testing.T, sequenceNumber uint64) *flowgo.TransactionBody {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { execute { log(getCurrentBlock().height) } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, sequenceNumber).
+			SetPayer(b.ServiceKey().Address)
+
+		err := tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		return convert.SDKTransactionToFlow(*tx)
+	}
+
+	latestBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	scheduledHeight := latestBlock.Header.Height + 3
+	tx := newTransaction(t, b.ServiceKey().SequenceNumber)
+
+	err = b.AddScheduledTransaction(context.Background(), *tx, scheduledHeight)
+	require.NoError(t, err)
+
+	t.Run("duplicate", func(t *testing.T) {
+		err := b.AddScheduledTransaction(context.Background(), *tx, scheduledHeight+1)
+		var duplicateErr *types.DuplicateTransactionError
+		assert.ErrorAs(t, err, &duplicateErr)
+	})
+
+	t.Run("committed height", func(t *testing.T) {
+		err := b.AddScheduledTransaction(context.Background(), *newTransaction(t, 100), latestBlock.Header.Height)
+		var invalidErr *types.InvalidArgumentError
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+
+	// the transaction is pending until its block
+	for height := latestBlock.Header.Height + 1; height < scheduledHeight; height++ {
+		result, err := adapter.GetTransactionResult(context.Background(), flowsdk.Identifier(tx.ID()))
+		require.NoError(t, err)
+		assert.Equal(t, flowsdk.TransactionStatusPending, result.Status)
+
+		block, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		assert.Equal(t, height, block.Header.Height)
+		assert.Empty(t, results)
+	}
+
+	block, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	assert.Equal(t, scheduledHeight, block.Header.Height)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+	assert.Equal(t, tx.ID().String(), results[0].TransactionID.String())
+
+	result, err := b.GetTransactionResult(context.Background(), tx.ID())
+	require.NoError(t, err)
+	assert.Equal(t, flowgo.TransactionStatusSealed, result.Status)
+	assert.Equal(t, scheduledHeight, result.BlockHeight)
+
+	t.Run("pending height", func(t *testing.T) {
+		tx := newTransaction(t, b.ServiceKey().SequenceNumber)
+
+		err := b.AddScheduledTransaction(context.Background(), *tx, scheduledHeight+1)
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, tx.ID().String(), results[0].TransactionID.String())
+	})
+
+	t.Run("reloaded", func(t *testing.T) {
+		latestBlock, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+
+		tx := newTransaction(t, b.ServiceKey().SequenceNumber)
+
+		err = b.AddScheduledTransaction(context.Background(), *tx, latestBlock.Header.Height+2)
+		require.NoError(t, err)
+
+		_, _, err = b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+
+		// the transaction was added to the pending block, which is recreated
+		err = b.RollbackToBlockHeight(latestBlock.Header.Height)
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		_, results, err = b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, tx.ID().String(), results[0].TransactionID.String())
+	})
+}
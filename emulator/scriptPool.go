@@ -0,0 +1,90 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	scriptQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "emulator",
+		Subsystem: "scripts",
+		Name:      "queue_length",
+		Help:      "Number of scripts waiting for a worker.",
+	})
+	scriptWorkersBusy = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "emulator",
+		Subsystem: "scripts",
+		Name:      "workers_busy",
+		Help:      "Number of workers executing a script.",
+	})
+	scriptQueueWait = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "emulator",
+		Subsystem: "scripts",
+		Name:      "queue_wait_seconds",
+		Help:      "Time scripts waited for a worker.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+	})
+)
+
+var defaultScriptWorkers = runtime.NumCPU()
+
+// scriptPool bounds the number of scripts executing concurrently. Scripts
+// beyond the pool size wait in line until a worker is released.
+type scriptPool struct {
+	workers chan struct{}
+}
+
+func newScriptPool(size int) *scriptPool {
+	if size < 1 {
+		size = defaultScriptWorkers
+	}
+	return &scriptPool{
+		workers: make(chan struct{}, size),
+	}
+}
+
+// acquire waits for a free worker and returns a function releasing it.
+// It fails with the context's error if the context ends while waiting.
+func (p *scriptPool) acquire(ctx context.Context) (func(), error) {
+	start := time.Now()
+	scriptQueueLength.Inc()
+
+	select {
+	case p.workers <- struct{}{}:
+	case <-ctx.Done():
+		scriptQueueLength.Dec()
+		return nil, ctx.Err()
+	}
+
+	scriptQueueLength.Dec()
+	scriptQueueWait.Observe(time.Since(start).Seconds())
+	scriptWorkersBusy.Inc()
+
+	return func() {
+		scriptWorkersBusy.Dec()
+		<-p.workers
+	}, nil
+}
@@ -20,8 +20,13 @@ package emulator_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/onflow/flow-emulator/adapters"
 	"github.com/onflow/flow-emulator/emulator"
@@ -34,6 +39,8 @@ import (
 	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/types"
 )
 
 func TestExecuteScript(t *testing.T) {
@@ -66,7 +73,7 @@ func TestExecuteScript(t *testing.T) {
 	callScript := GenerateGetCounterCountScript(counterAddress, b.ServiceKey().Address)
 
 	// Sample call (value is 0)
-	scriptResult, err := b.ExecuteScript([]byte(callScript), nil)
+	scriptResult, err := b.ExecuteScript(context.Background(), []byte(callScript), nil)
 	require.NoError(t, err)
 	assert.Equal(t, cadence.NewInt(0), scriptResult.Value)
 
@@ -82,7 +89,7 @@ func TestExecuteScript(t *testing.T) {
 		t.Skip("TODO: fix stored ledger")
 
 		// Sample call (value is still 0)
-		result, err := b.ExecuteScript([]byte(callScript), nil)
+		result, err := b.ExecuteScript(context.Background(), []byte(callScript), nil)
 		require.NoError(t, err)
 		assert.Equal(t, cadence.NewInt(0), result.Value)
 	})
@@ -92,7 +99,7 @@ func TestExecuteScript(t *testing.T) {
 
 	t.Run("AfterCommit", func(t *testing.T) {
 		// Sample call (value is 2)
-		result, err := b.ExecuteScript([]byte(callScript), nil)
+		result, err := b.ExecuteScript(context.Background(), []byte(callScript), nil)
 		require.NoError(t, err)
 		assert.Equal(t, cadence.NewInt(2), result.Value)
 	})
@@ -118,7 +125,7 @@ func TestExecuteScript_WithArguments(t *testing.T) {
 		arg, err := jsoncdc.Encode(cadence.NewInt(10))
 		require.NoError(t, err)
 
-		scriptResult, err := b.ExecuteScript([]byte(scriptWithArgs), [][]byte{arg})
+		scriptResult, err := b.ExecuteScript(context.Background(), []byte(scriptWithArgs), [][]byte{arg})
 		require.NoError(t, err)
 
 		assert.Equal(t, cadence.NewInt(10), scriptResult.Value)
@@ -140,7 +147,7 @@ func TestExecuteScript_WithArguments(t *testing.T) {
 
 		arg, err := jsoncdc.Encode(cadence.String("Hello, World"))
 		require.NoError(t, err)
-		scriptResult, err := b.ExecuteScript([]byte(scriptWithArgs), [][]byte{arg})
+		scriptResult, err := b.ExecuteScript(context.Background(), []byte(scriptWithArgs), [][]byte{arg})
 		require.NoError(t, err)
 		assert.Contains(t, scriptResult.Logs, "\"Hello, World\"")
 	})
@@ -164,7 +171,7 @@ func TestExecuteScript_FlowServiceAccountBalance(t *testing.T) {
 		b.GetChain().ServiceAddress().HexWithPrefix(),
 	)
 
-	res, err := b.ExecuteScript([]byte(code), nil)
+	res, err := b.ExecuteScript(context.Background(), []byte(code), nil)
 	require.NoError(t, err)
 	require.NoError(t, res.Error)
 
@@ -186,7 +193,7 @@ func TestInfiniteScript(t *testing.T) {
 			main()
 		}
 	`
-	result, err := b.ExecuteScript([]byte(code), nil)
+	result, err := b.ExecuteScript(context.Background(), []byte(code), nil)
 	require.NoError(t, err)
 
 	require.True(t, fvmerrors.IsComputationLimitExceededError(result.Error))
@@ -223,7 +230,7 @@ func TestScriptExecutionLimit(t *testing.T) {
 		)
 		require.NoError(t, err)
 
-		result, err := b.ExecuteScript([]byte(code), nil)
+		result, err := b.ExecuteScript(context.Background(), []byte(code), nil)
 		require.NoError(t, err)
 
 		require.True(t, fvmerrors.IsComputationLimitExceededError(result.Error))
@@ -239,8 +246,193 @@ func TestScriptExecutionLimit(t *testing.T) {
 		)
 		require.NoError(t, err)
 
-		result, err := b.ExecuteScript([]byte(code), nil)
+		result, err := b.ExecuteScript(context.Background(), []byte(code), nil)
 		require.NoError(t, err)
 		require.NoError(t, result.Error)
 	})
 }
+
+func TestExecuteScript_CancelledContext(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	const code = `
+		pub fun main(): Int {
+			return 42
+		}
+	`
+	_, err = b.ExecuteScript(ctx, []byte(code), nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestExecuteScript_CancelledWhileRunning(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithScriptGasLimit(math.MaxUint64),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	const code = `
+		pub fun main() {
+			while true {}
+		}
+	`
+
+	start := time.Now()
+	_, err = b.ExecuteScript(ctx, []byte(code), nil)
+
+	var interruptedErr *types.ScriptInterruptedError
+	require.ErrorAs(t, err, &interruptedErr)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestExecuteScript_Timeout(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithScriptGasLimit(math.MaxUint64),
+		emulator.WithScriptTimeout(100*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	const code = `
+		pub fun main() {
+			while true {}
+		}
+	`
+	_, err = b.ExecuteScript(context.Background(), []byte(code), nil)
+
+	var interruptedErr *types.ScriptInterruptedError
+	require.ErrorAs(t, err, &interruptedErr)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the emulator remains usable after the interruption
+	result, err := b.ExecuteScript(context.Background(), []byte(`pub fun main(): Int { return 1 }`), nil)
+	require.NoError(t, err)
+	require.NoError(t, result.Error)
+}
+
+func TestExecuteScript_Workers(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithScriptGasLimit(math.MaxUint64),
+		emulator.WithScriptWorkers(1),
+	)
+	require.NoError(t, err)
+
+	const code = `
+		pub fun main() {
+			while true {}
+		}
+	`
+
+	// occupy the only worker
+	runningCtx, cancelRunning := context.WithCancel(context.Background())
+	running := make(chan error, 1)
+	go func() {
+		_, err := b.ExecuteScript(runningCtx, []byte(code), nil)
+		running <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	// a second script waits for the worker until its deadline passes
+	queuedCtx, cancelQueued := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelQueued()
+
+	_, err = b.ExecuteScript(queuedCtx, []byte(`pub fun main(): Int { return 1 }`), nil)
+
+	var interruptedErr *types.ScriptInterruptedError
+	require.ErrorAs(t, err, &interruptedErr)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// releasing the worker lets further scripts run
+	cancelRunning()
+	require.ErrorIs(t, <-running, context.Canceled)
+
+	result, err := b.ExecuteScript(context.Background(), []byte(`pub fun main(): Int { return 1 }`), nil)
+	require.NoError(t, err)
+	require.NoError(t, result.Error)
+}
+
+type syncWriter struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+func TestConcurrentScriptLogs(t *testing.T) {
+
+	t.Parallel()
+
+	writer := &syncWriter{}
+
+	b, err := emulator.New(
+		emulator.WithServerLogger(zerolog.New(writer)),
+		emulator.WithScriptWorkers(4),
+	)
+	require.NoError(t, err)
+
+	const scripts = 16
+
+	scriptIDs := make([]string, scripts)
+	var wg sync.WaitGroup
+	for i := 0; i < scripts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			script := fmt.Sprintf(`pub fun main() { log("script %d") }`, i)
+			result, err := b.ExecuteScript(context.Background(), []byte(script), nil)
+			require.NoError(t, err)
+			require.NoError(t, result.Error)
+
+			scriptIDs[i] = result.ScriptID.String()
+		}(i)
+	}
+	wg.Wait()
+
+	logged := 0
+	for _, line := range writer.lines {
+		var entry struct {
+			Message  string `json:"message"`
+			ScriptID string `json:"scriptID"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+
+		start := strings.Index(entry.Message, `"script `)
+		if start < 0 {
+			continue
+		}
+		var i int
+		_, err := fmt.Sscanf(entry.Message[start:], `"script %d"`, &i)
+		require.NoError(t, err)
+
+		// every log is tagged with the script which logged it
+		assert.Equal(t, scriptIDs[i], entry.ScriptID)
+		logged++
+	}
+	assert.Equal(t, scripts, logged)
+}
@@ -0,0 +1,81 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"errors"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// maxSequenceNumberRetries bounds how often a service transaction is signed again
+// because other transactions of the service key were sent concurrently.
+const maxSequenceNumberRetries = 10
+
+// SendServiceTransaction proposes, pays and signs the given transaction with the
+// service key, and sends it with SendCheckedTransaction.
+//
+// The transaction is proposed with the next sequence number of the service key,
+// including its pending transactions, so it can be sent while other transactions
+// of the service key are pending. If beforeSend is not nil, it is called with the
+// signed transaction right before it is sent, as it may be executed before
+// SendServiceTransaction returns. It returns the transaction sent.
+func SendServiceTransaction(
+	ctx context.Context,
+	e Emulator,
+	tx *flowsdk.Transaction,
+	beforeSend func(*flowgo.TransactionBody),
+) (*flowgo.TransactionBody, error) {
+	serviceKey := e.ServiceKey()
+	serviceAddress := serviceKey.Address
+
+	signer, err := serviceKey.Signer()
+	if err != nil {
+		return nil, err
+	}
+
+	sequenceNumber := serviceKey.SequenceNumber
+	for retries := 0; ; retries++ {
+		tx.SetProposalKey(serviceAddress, serviceKey.Index, sequenceNumber).
+			SetPayer(serviceAddress)
+		tx.EnvelopeSignatures = nil
+
+		err = tx.SignEnvelope(serviceAddress, serviceKey.Index, signer)
+		if err != nil {
+			return nil, err
+		}
+
+		flowTx := convert.SDKTransactionToFlow(*tx)
+		if beforeSend != nil {
+			beforeSend(flowTx)
+		}
+		err = e.SendCheckedTransaction(ctx, flowTx)
+
+		var sequenceNumberErr *types.InvalidSequenceNumberError
+		if !errors.As(err, &sequenceNumberErr) || retries == maxSequenceNumberRetries {
+			return flowTx, err
+		}
+		sequenceNumber = sequenceNumberErr.Expected
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/memstore"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+func TestSnapshotsAndRollbackWithStores(t *testing.T) {
+
+	t.Parallel()
+
+	stores := map[string]func(t *testing.T) storage.Store{
+		"default": func(t *testing.T) storage.Store {
+			return nil
+		},
+		"memstore": func(t *testing.T) storage.Store {
+			return memstore.New()
+		},
+		"sqlite": func(t *testing.T) storage.Store {
+			store, err := sqlite.New(t.TempDir())
+			require.NoError(t, err)
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		newStore := newStore
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := []emulator.Option{
+				emulator.WithStorageLimitEnabled(false),
+			}
+			if store := newStore(t); store != nil {
+				options = append(options, emulator.WithStore(store))
+			}
+
+			b, err := emulator.New(options...)
+			require.NoError(t, err)
+
+			logger := zerolog.Nop()
+			adapter := adapters.NewSDKAdapter(&logger, b)
+
+			addTwoScript, counterAddress := DeployAndGenerateAddTwoScript(t, adapter)
+
+			deployed, err := b.GetLatestBlock(context.Background())
+			require.NoError(t, err)
+
+			err = b.CreateSnapshot("deployed", "counter deployed")
+			require.NoError(t, err)
+
+			IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 2)
+			IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 4)
+
+			err = b.RollbackToBlockHeight(deployed.Header.Height)
+			require.NoError(t, err)
+
+			latest, err := b.GetLatestBlock(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, deployed.Header.Height, latest.Header.Height)
+
+			IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 2)
+			IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 4)
+			IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 6)
+
+			err = b.LoadSnapshot("deployed")
+			require.NoError(t, err)
+
+			latest, err = b.GetLatestBlock(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, deployed.Header.Height, latest.Header.Height)
+
+			IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 2)
+
+			snapshots, err := b.Snapshots()
+			require.NoError(t, err)
+			assert.Contains(t, snapshots, "deployed")
+		})
+	}
+}
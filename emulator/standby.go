@@ -0,0 +1,92 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package emulator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/onflow/flow-emulator/storage/fork"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// ErrStandbyNotEnabled is returned when the state is reset without a standby state.
+var ErrStandbyNotEnabled = errors.New("standby is not enabled")
+
+// ErrStandbyEnabled is returned when standby is enabled a second time.
+var ErrStandbyEnabled = errors.New("standby is already enabled")
+
+// EnableStandby keeps the current state as the standby state, which ResetState returns to.
+//
+// The blocks committed afterwards are kept in memory, copied on write on top of the
+// standby state, so that resetting the state only discards them. Standby can only be
+// enabled once, as the blocks kept in memory would otherwise pile up below every
+// new standby state.
+func (b *Blockchain) EnableStandby() error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.conf.ReadOnly {
+		return &types.ReadOnlyError{}
+	}
+
+	if b.standby != nil {
+		return ErrStandbyEnabled
+	}
+
+	if !b.pendingBlock.Empty() {
+		return &types.PendingBlockNotEmptyError{BlockID: b.pendingBlock.ID()}
+	}
+
+	latestBlock, err := b.getLatestBlock(context.Background())
+	if err != nil {
+		return err
+	}
+
+	b.standby = b.storage
+	b.standbyHeight = latestBlock.Header.Height
+
+	return b.resetToStandby()
+}
+
+// ResetState discards the blocks committed since standby was enabled, the pending
+// block and the scheduled transactions, and returns to the standby state.
+func (b *Blockchain) ResetState() error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.standby == nil {
+		return ErrStandbyNotEnabled
+	}
+
+	// the scheduled transactions are discarded before the pending block is recreated
+	b.discardBlockState()
+
+	return b.resetToStandby()
+}
+
+func (b *Blockchain) resetToStandby() error {
+	store, err := fork.New(context.Background(), b.standby, b.standbyHeight)
+	if err != nil {
+		return err
+	}
+
+	b.storage = store
+
+	return b.ReloadBlockchain()
+}
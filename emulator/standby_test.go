@@ -0,0 +1,135 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestStandby(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("reset returns to the standby state", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New(
+			emulator.WithStorageLimitEnabled(false),
+		)
+		require.NoError(t, err)
+
+		logger := zerolog.Nop()
+		adapter := adapters.NewSDKAdapter(&logger, b)
+
+		addTwoScript, counterAddress := DeployAndGenerateAddTwoScript(t, adapter)
+		IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 2)
+
+		err = b.EnableStandby()
+		require.NoError(t, err)
+
+		standbyBlock, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			// the counter is incremented from the standby state every time
+			IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 4)
+
+			latestBlock, err := b.GetLatestBlock(context.Background())
+			require.NoError(t, err)
+			require.Greater(t, latestBlock.Header.Height, standbyBlock.Header.Height)
+
+			err = b.ResetState()
+			require.NoError(t, err)
+
+			latestBlock, err = b.GetLatestBlock(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, standbyBlock.ID(), latestBlock.ID())
+
+			_, err = b.GetBlockByHeight(context.Background(), standbyBlock.Header.Height+1)
+			assert.Error(t, err)
+		}
+
+		// standby can only be enabled once
+		err = b.EnableStandby()
+		assert.ErrorIs(t, err, emulator.ErrStandbyEnabled)
+
+		// the unmatched expectations of the discarded blocks are discarded too
+		_, err = b.ExpectEvent(emulator.EventMatcher{
+			Type:   "A.0000000000000001.Missing.Event",
+			Report: emulator.ExpectationReportError,
+		})
+		require.NoError(t, err)
+		_, _, err = b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Len(t, b.UnmatchedEventExpectations(), 1)
+
+		err = b.ResetState()
+		require.NoError(t, err)
+		assert.Empty(t, b.UnmatchedEventExpectations())
+	})
+
+	t.Run("reset requires standby", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		err = b.ResetState()
+		assert.ErrorIs(t, err, emulator.ErrStandbyNotEnabled)
+	})
+
+	t.Run("standby requires an empty pending block", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { execute { log("hello") } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		logger := zerolog.Nop()
+		adapter := adapters.NewSDKAdapter(&logger, b)
+
+		err = adapter.SendTransaction(context.Background(), *tx)
+		require.NoError(t, err)
+
+		err = b.EnableStandby()
+		var notEmptyErr *types.PendingBlockNotEmptyError
+		assert.ErrorAs(t, err, &notEmptyErr)
+	})
+}
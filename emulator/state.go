@@ -0,0 +1,254 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/util"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// stateArchiveFormat identifies state archives written by ExportState.
+const stateArchiveFormat = "flow-emulator-state"
+
+// stateArchiveVersion is the version of the state archives written by this emulator.
+const stateArchiveVersion uint64 = 1
+
+// stateArchiveHeader starts a state archive. It is followed by one
+// CommittedBlock per block, from the genesis block to the latest block.
+type stateArchiveHeader struct {
+	Format  string
+	Version uint64
+	ChainID flowgo.ChainID
+	Blocks  uint64
+	// Config is the configuration the exported store was created with, if known.
+	Config []byte
+}
+
+// ExportState writes every committed block, with the registers it wrote and
+// its events, to a self-contained gzip-compressed archive.
+//
+// The blocks up to the latest block at the time of the call are exported.
+// Blocks committed meanwhile are not, and the blocks are read without
+// stopping blocks from being committed.
+//
+// The archive can be loaded by ImportState, also by another emulator.
+func (b *Blockchain) ExportState(w io.Writer) error {
+	ctx := context.Background()
+
+	header, store, err := b.stateArchiveHeader(ctx)
+	if err != nil {
+		return err
+	}
+
+	compressor := gzip.NewWriter(w)
+	encoder := cbor.NewEncoder(compressor)
+
+	err = encoder.Encode(header)
+	if err != nil {
+		return err
+	}
+
+	for height := uint64(0); height < header.Blocks; height++ {
+		committed, err := readCommittedBlock(ctx, store, height)
+		if err != nil {
+			return fmt.Errorf("failed to export block at height %d: %w", height, err)
+		}
+
+		err = encoder.Encode(committed)
+		if err != nil {
+			return err
+		}
+	}
+
+	return compressor.Close()
+}
+
+// stateArchiveHeader returns the header of an archive of the blocks committed so
+// far, and the store to read them from.
+func (b *Blockchain) stateArchiveHeader(ctx context.Context) (*stateArchiveHeader, storage.Store, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// the registers written by a pruned block are not known
+	err := b.checkStateAvailable(0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := &stateArchiveHeader{
+		Format:  stateArchiveFormat,
+		Version: stateArchiveVersion,
+		ChainID: b.conf.GetChainID(),
+		Blocks:  latestBlock.Header.Height + 1,
+	}
+	header.Config, err = b.storage.GetMeta(ctx, storeConfigKey)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, err
+	}
+
+	return header, b.storage, nil
+}
+
+// ImportState replaces the state of the blockchain with the state of an
+// archive written by ExportState. The pending block must be empty.
+//
+// The archived state is loaded into a new in-memory store, which the
+// blockchain is served from afterwards, and the previous store is closed.
+// The scheduled transactions, event expectations and everything else kept
+// about the previous blocks are discarded. To load an archive into persisted
+// storage, load it with ImportState before creating the blockchain.
+func (b *Blockchain) ImportState(r io.Reader) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if !b.pendingBlock.Empty() {
+		return &types.PendingBlockNotEmptyError{BlockID: b.pendingBlock.ID()}
+	}
+
+	store, err := util.CreateDefaultStorage()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	header, err := importState(ctx, r, store)
+	if err != nil {
+		return err
+	}
+
+	if header.ChainID != b.conf.GetChainID() {
+		return &storage.IncompatibleStoreError{
+			Setting:    "chain ID",
+			Stored:     header.ChainID.String(),
+			Configured: b.conf.GetChainID().String(),
+		}
+	}
+
+	previous := b.storage
+	b.storage = store
+
+	// the scheduled transactions are discarded with the previous blocks
+	err = b.reloadBlockchain()
+	if err != nil {
+		b.storage = previous
+		if reloadErr := b.ReloadBlockchain(); reloadErr != nil {
+			b.conf.ServerLogger.Error().Err(reloadErr).Msg("❗  Failed to restore the previous storage")
+		}
+		return err
+	}
+
+	b.discardBlockState()
+
+	// the standby state stays open, to return to it when the state is reset
+	if closer, ok := previous.(io.Closer); ok && previous != b.standby {
+		err = closer.Close()
+		if err != nil {
+			b.conf.ServerLogger.Warn().Err(err).Msg("❗  Failed to close the previous storage")
+		}
+	}
+
+	return nil
+}
+
+// ImportState loads an archive written by ExportState into the given empty
+// store. A blockchain created with the store serves the archived state.
+func ImportState(ctx context.Context, r io.Reader, store storage.Store) error {
+	_, err := store.LatestBlockHeight(ctx)
+	if err == nil {
+		return storage.ErrStoreNotEmpty
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+
+	_, err = importState(ctx, r, store)
+	return err
+}
+
+func importState(ctx context.Context, r io.Reader, store storage.Store) (*stateArchiveHeader, error) {
+	decompressor, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state archive: %w", err)
+	}
+	defer decompressor.Close()
+
+	decoder := cbor.NewDecoder(decompressor)
+
+	var header stateArchiveHeader
+	err = decoder.Decode(&header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state archive: %w", err)
+	}
+
+	if header.Format != stateArchiveFormat {
+		return nil, fmt.Errorf("not an emulator state archive")
+	}
+	if header.Version > stateArchiveVersion {
+		return nil, &storage.UnsupportedSchemaVersionError{
+			Version:   header.Version,
+			Supported: stateArchiveVersion,
+		}
+	}
+
+	for height := uint64(0); height < header.Blocks; height++ {
+		var committed CommittedBlock
+		err := decoder.Decode(&committed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read block at height %d from state archive: %w", height, err)
+		}
+
+		if committed.Block.Header.Height != height {
+			return nil, fmt.Errorf(
+				"state archive holds block at height %d where height %d was expected",
+				committed.Block.Header.Height,
+				height,
+			)
+		}
+
+		err = committed.Commit(ctx, store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import block at height %d: %w", height, err)
+		}
+	}
+
+	if header.Config != nil {
+		err = store.PutMeta(ctx, storeConfigKey, header.Config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &header, nil
+}
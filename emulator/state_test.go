@@ -0,0 +1,148 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/memstore"
+)
+
+func TestExportImportState(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithStorageLimitEnabled(false),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	address, err := adapter.CreateAccount(context.Background(), nil, nil, 0)
+	require.NoError(t, err)
+
+	latestBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	require.NoError(t, b.ExportState(&archive))
+
+	assertImported := func(t *testing.T, imported *emulator.Blockchain) {
+		block, err := imported.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, latestBlock.ID(), block.ID())
+
+		account, err := imported.GetAccount(context.Background(), flowgo.Address(address))
+		require.NoError(t, err)
+		assert.Equal(t, flowgo.Address(address), account.Address)
+	}
+
+	t.Run("into a blockchain", func(t *testing.T) {
+		t.Parallel()
+
+		imported, err := emulator.New()
+		require.NoError(t, err)
+
+		err = imported.ImportState(bytes.NewReader(archive.Bytes()))
+		require.NoError(t, err)
+
+		assertImported(t, imported)
+
+		// the imported state can be extended
+		_, _, err = imported.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+	})
+
+	t.Run("discards the previous blocks", func(t *testing.T) {
+		t.Parallel()
+
+		imported, err := emulator.New()
+		require.NoError(t, err)
+
+		_, err = imported.ExpectEvent(emulator.EventMatcher{
+			Type:   "A.0000000000000001.Missing.Event",
+			Report: emulator.ExpectationReportError,
+		})
+		require.NoError(t, err)
+		_, _, err = imported.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Len(t, imported.UnmatchedEventExpectations(), 1)
+
+		_, err = imported.ExpectEvent(emulator.EventMatcher{Type: "A.0000000000000001.Missing.Event"})
+		require.NoError(t, err)
+
+		err = imported.ImportState(bytes.NewReader(archive.Bytes()))
+		require.NoError(t, err)
+
+		assertImported(t, imported)
+		assert.Empty(t, imported.EventExpectations())
+		assert.Empty(t, imported.UnmatchedEventExpectations())
+	})
+
+	t.Run("into empty storage", func(t *testing.T) {
+		t.Parallel()
+
+		store := memstore.New()
+
+		err := emulator.ImportState(context.Background(), bytes.NewReader(archive.Bytes()), store)
+		require.NoError(t, err)
+
+		imported, err := emulator.New(emulator.WithStore(store))
+		require.NoError(t, err)
+
+		assertImported(t, imported)
+
+		err = emulator.ImportState(context.Background(), bytes.NewReader(archive.Bytes()), store)
+		assert.ErrorIs(t, err, storage.ErrStoreNotEmpty)
+	})
+
+	t.Run("for another chain", func(t *testing.T) {
+		t.Parallel()
+
+		imported, err := emulator.New(emulator.WithChainID(flowgo.Testnet))
+		require.NoError(t, err)
+
+		err = imported.ImportState(bytes.NewReader(archive.Bytes()))
+
+		var incompatibleErr *storage.IncompatibleStoreError
+		require.ErrorAs(t, err, &incompatibleErr)
+	})
+
+	t.Run("not an archive", func(t *testing.T) {
+		t.Parallel()
+
+		imported, err := emulator.New()
+		require.NoError(t, err)
+
+		err = imported.ImportState(bytes.NewReader([]byte("not an archive")))
+		require.Error(t, err)
+	})
+}
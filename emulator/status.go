@@ -0,0 +1,66 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"time"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+// Status is a snapshot of the emulator's state, for monitoring.
+type Status struct {
+	LatestBlockHeight uint64
+	LastBlockTime     time.Time
+	// PendingTransactions is the number of transactions in the pending block,
+	// executed or not.
+	PendingTransactions int
+	// BlockSubscriptions is the number of channels subscribed to committed blocks.
+	BlockSubscriptions int
+	DebuggingSession   bool
+	// OpenStoreHandles is the number of connections or file handles the store
+	// holds, zero for stores which do not hold any.
+	OpenStoreHandles int
+}
+
+// Status returns a snapshot of the emulator's state.
+func (b *Blockchain) Status(ctx context.Context) (*Status, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{
+		LatestBlockHeight:   latestBlock.Header.Height,
+		LastBlockTime:       latestBlock.Header.Timestamp,
+		PendingTransactions: len(b.pendingBlock.Transactions()),
+		BlockSubscriptions:  b.subscriptions.blockCommittedCount(),
+		DebuggingSession:    b.activeDebuggingSession,
+	}
+
+	if handles, ok := b.storage.(storage.HandleProvider); ok {
+		status.OpenStoreHandles = handles.OpenHandles()
+	}
+
+	return status, nil
+}
@@ -0,0 +1,212 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"sync"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// BlockEvent is delivered to subscribers after a block is committed to storage.
+type BlockEvent struct {
+	Block  *flowgo.Block
+	Events []flowgo.Event
+	// Missed is the number of blocks committed since the previous event delivered
+	// to the subscriber, which were dropped because its channel was full.
+	Missed uint64
+}
+
+// TransactionExecutedCallback is invoked after each transaction in the pending
+// block is executed, before the block is committed.
+type TransactionExecutedCallback func(tx *flowgo.TransactionBody, result *types.TransactionResult)
+
+// subscriptions keeps track of the listeners registered for chain activity.
+//
+// It is guarded by its own mutex so that listeners can be added or removed
+// while the blockchain lock is held by block production.
+type subscriptions struct {
+	mu                   sync.RWMutex
+	blockCommitted       []*blockSubscriber
+	transactionCallbacks []transactionSubscriber
+	nextCallbackID       uint64
+	// executed are the transactions executed while the blockchain was locked,
+	// whose callbacks are invoked once the lock is released
+	executed []executedTransaction
+	flushing bool
+}
+
+type blockSubscriber struct {
+	ch     chan<- BlockEvent
+	missed uint64
+}
+
+type transactionSubscriber struct {
+	id       uint64
+	callback TransactionExecutedCallback
+}
+
+type executedTransaction struct {
+	tx     *flowgo.TransactionBody
+	result *types.TransactionResult
+}
+
+func (s *subscriptions) addBlockCommitted(ch chan<- BlockEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blockCommitted = append(s.blockCommitted, &blockSubscriber{ch: ch})
+}
+
+func (s *subscriptions) removeBlockCommitted(ch chan<- BlockEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, subscriber := range s.blockCommitted {
+		if subscriber.ch == ch {
+			s.blockCommitted = append(s.blockCommitted[:i], s.blockCommitted[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *subscriptions) blockCommittedCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.blockCommitted)
+}
+
+func (s *subscriptions) addTransactionExecuted(callback TransactionExecutedCallback) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextCallbackID++
+	id := s.nextCallbackID
+	s.transactionCallbacks = append(s.transactionCallbacks, transactionSubscriber{
+		id:       id,
+		callback: callback,
+	})
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for i, subscriber := range s.transactionCallbacks {
+			if subscriber.id == id {
+				s.transactionCallbacks = append(s.transactionCallbacks[:i:i], s.transactionCallbacks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notifyBlockCommitted sends the event to every subscriber without blocking.
+// Subscribers that are not ready to receive miss the event, which is counted
+// in the Missed field of the next event they receive.
+func (s *subscriptions) notifyBlockCommitted(event BlockEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, subscriber := range s.blockCommitted {
+		event.Missed = subscriber.missed
+		select {
+		case subscriber.ch <- event:
+			subscriber.missed = 0
+		default:
+			subscriber.missed++
+		}
+	}
+}
+
+// notifyTransactionExecuted queues the executed transaction for the callbacks,
+// which are invoked by flush once the blockchain lock is released.
+func (s *subscriptions) notifyTransactionExecuted(tx *flowgo.TransactionBody, result *types.TransactionResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.transactionCallbacks) == 0 {
+		return
+	}
+
+	s.executed = append(s.executed, executedTransaction{tx: tx, result: result})
+}
+
+// flush invokes the callbacks for the queued transactions, in execution order.
+//
+// Only one caller delivers at a time, it also delivers the transactions queued
+// while it runs the callbacks, so callbacks may execute transactions themselves.
+func (s *subscriptions) flush() {
+	s.mu.Lock()
+	if s.flushing {
+		s.mu.Unlock()
+		return
+	}
+	s.flushing = true
+
+	for len(s.executed) > 0 {
+		executed := s.executed
+		s.executed = nil
+		callbacks := s.transactionCallbacks
+		s.mu.Unlock()
+
+		for _, transaction := range executed {
+			for _, subscriber := range callbacks {
+				subscriber.callback(transaction.tx, transaction.result)
+			}
+		}
+
+		s.mu.Lock()
+	}
+
+	s.flushing = false
+	s.mu.Unlock()
+}
+
+// unlock releases the blockchain lock, and then invokes the transaction callbacks
+// for the transactions executed while it was held.
+func (b *Blockchain) unlock() {
+	b.mu.Unlock()
+	b.subscriptions.flush()
+}
+
+// SubscribeBlockCommitted registers a channel that receives a BlockEvent
+// every time a block is committed.
+//
+// Events are sent without blocking block production, use a buffered channel
+// to avoid missing blocks. Missed blocks are counted in the next event received.
+func (b *Blockchain) SubscribeBlockCommitted(ch chan<- BlockEvent) {
+	b.subscriptions.addBlockCommitted(ch)
+}
+
+// UnsubscribeBlockCommitted removes a channel previously registered with SubscribeBlockCommitted.
+func (b *Blockchain) UnsubscribeBlockCommitted(ch chan<- BlockEvent) {
+	b.subscriptions.removeBlockCommitted(ch)
+}
+
+// OnTransactionExecuted registers a callback invoked after every transaction execution,
+// and returns a function removing it.
+//
+// The callback runs once the blockchain lock is released, in execution order,
+// so it does not stall block production and can call back into the emulator.
+func (b *Blockchain) OnTransactionExecuted(callback TransactionExecutedCallback) (unsubscribe func()) {
+	return b.subscriptions.addTransactionExecuted(callback)
+}
@@ -0,0 +1,180 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestSubscriptions(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("block committed", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		ch := make(chan emulator.BlockEvent, 1)
+		b.SubscribeBlockCommitted(ch)
+
+		block, err := b.CommitBlock()
+		require.NoError(t, err)
+
+		event := <-ch
+		assert.Equal(t, block.ID(), event.Block.ID())
+
+		b.UnsubscribeBlockCommitted(ch)
+
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+
+		assert.Len(t, ch, 0)
+	})
+
+	t.Run("missed blocks", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		ch := make(chan emulator.BlockEvent, 1)
+		b.SubscribeBlockCommitted(ch)
+
+		for i := 0; i < 3; i++ {
+			_, err = b.CommitBlock()
+			require.NoError(t, err)
+		}
+
+		event := <-ch
+		assert.Equal(t, uint64(0), event.Missed)
+
+		block, err := b.CommitBlock()
+		require.NoError(t, err)
+
+		// the two blocks committed while the channel was full were dropped
+		event = <-ch
+		assert.Equal(t, block.ID(), event.Block.ID())
+		assert.Equal(t, uint64(2), event.Missed)
+	})
+
+	t.Run("transaction executed", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		var executed []flowgo.Identifier
+		unsubscribe := b.OnTransactionExecuted(func(tx *flowgo.TransactionBody, result *types.TransactionResult) {
+			assert.Equal(t, tx.ID(), convert.SDKIdentifierToFlow(result.TransactionID))
+			executed = append(executed, tx.ID())
+
+			// callbacks run after the blockchain lock is released
+			_, err := b.GetLatestBlock(context.Background())
+			assert.NoError(t, err)
+		})
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { execute { log("hello") } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, _, err = b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+
+		assert.Equal(t, []flowgo.Identifier{convert.SDKIdentifierToFlow(tx.ID())}, executed)
+
+		unsubscribe()
+
+		tx.SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber)
+		tx.EnvelopeSignatures = nil
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, _, err = b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+
+		assert.Len(t, executed, 1)
+	})
+
+	t.Run("transaction executed in a delayed block", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, err := emulator.New(emulator.WithConsensusDelay(10 * time.Millisecond))
+		require.NoError(t, err)
+		b.EnableAutoMine()
+
+		executed := make(chan flowgo.Identifier, 1)
+		b.OnTransactionExecuted(func(tx *flowgo.TransactionBody, _ *types.TransactionResult) {
+			executed <- tx.ID()
+		})
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction {}`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.SendTransaction(context.Background(), convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		// the callback runs once the delayed commit releases the lock
+		select {
+		case txID := <-executed:
+			assert.Equal(t, convert.SDKIdentifierToFlow(tx.ID()), txID)
+		case <-time.After(30 * time.Second):
+			t.Fatal("transaction callback not called")
+		}
+	})
+}
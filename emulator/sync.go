@@ -0,0 +1,377 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// CommittedBlock holds everything needed to replay a committed block into
+// another emulator's storage.
+type CommittedBlock struct {
+	Block        flowgo.Block
+	Collections  []flowgo.LightCollection
+	Transactions []flowgo.TransactionBody
+	// TransactionResults holds the result of each entry in Transactions.
+	TransactionResults []types.StorableTransactionResult
+	Registers          []CommittedRegister
+	Events             []flowgo.Event
+}
+
+// CommittedRegister is a register written by a committed block.
+//
+// Register owners and keys are raw bytes rather than text, so they are kept
+// as byte slices to survive encodings which require valid UTF-8 strings.
+type CommittedRegister struct {
+	Owner []byte
+	Key   []byte
+	Value []byte
+}
+
+// Commit saves the block and its register writes to the given store.
+func (c *CommittedBlock) Commit(ctx context.Context, store storage.Store) error {
+	if len(c.Transactions) != len(c.TransactionResults) {
+		return fmt.Errorf(
+			"transactions count (%d) does not match result count (%d)",
+			len(c.Transactions),
+			len(c.TransactionResults),
+		)
+	}
+
+	collections := make([]*flowgo.LightCollection, len(c.Collections))
+	for i := range c.Collections {
+		collections[i] = &c.Collections[i]
+	}
+
+	transactions := make(map[flowgo.Identifier]*flowgo.TransactionBody, len(c.Transactions))
+	transactionResults := make(map[flowgo.Identifier]*types.StorableTransactionResult, len(c.Transactions))
+	for i := range c.Transactions {
+		txID := c.Transactions[i].ID()
+		transactions[txID] = &c.Transactions[i]
+		transactionResults[txID] = &c.TransactionResults[i]
+	}
+
+	executionSnapshot := &snapshot.ExecutionSnapshot{
+		WriteSet: make(map[flowgo.RegisterID]flowgo.RegisterValue, len(c.Registers)),
+	}
+	for _, register := range c.Registers {
+		registerID := flowgo.RegisterID{
+			Owner: string(register.Owner),
+			Key:   string(register.Key),
+		}
+		executionSnapshot.WriteSet[registerID] = register.Value
+	}
+
+	return store.CommitBlock(ctx, storage.BlockCommit{
+		Block:              c.Block,
+		Collections:        collections,
+		Transactions:       transactions,
+		TransactionResults: transactionResults,
+		ExecutionSnapshot:  executionSnapshot,
+		Events:             c.Events,
+	})
+}
+
+// GetCommittedBlock exports the committed block at the given height, including
+// the registers it wrote, so it can be replayed by a follower.
+func (b *Blockchain) GetCommittedBlock(ctx context.Context, height uint64) (*CommittedBlock, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.getCommittedBlock(ctx, height)
+}
+
+func (b *Blockchain) getCommittedBlock(ctx context.Context, height uint64) (*CommittedBlock, error) {
+	// the registers written by a pruned block are not known
+	err := b.checkStateAvailable(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return readCommittedBlock(ctx, b.storage, height)
+}
+
+// readCommittedBlock reads the block at the given height from the given store, with the
+// register versions the store keeps for it, which are only part of the registers it wrote
+// once pruned.
+func readCommittedBlock(ctx context.Context, store storage.Store, height uint64) (*CommittedBlock, error) {
+	deltaProvider, ok := store.(storage.LedgerDeltaProvider)
+	if !ok {
+		return nil, fmt.Errorf("storage does not support exporting ledger deltas")
+	}
+
+	block, err := store.BlockByHeight(ctx, height)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, &types.BlockNotFoundByHeightError{Height: height}
+		}
+		return nil, err
+	}
+
+	committed := &CommittedBlock{
+		Block: *block,
+	}
+
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := store.CollectionByID(ctx, guarantee.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+		committed.Collections = append(committed.Collections, collection)
+
+		for _, txID := range collection.Transactions {
+			tx, err := store.TransactionByID(ctx, txID)
+			if err != nil {
+				return nil, err
+			}
+			result, err := store.TransactionResultByID(ctx, txID)
+			if err != nil {
+				return nil, err
+			}
+			committed.Transactions = append(committed.Transactions, tx)
+			committed.TransactionResults = append(committed.TransactionResults, result)
+		}
+	}
+
+	delta, err := deltaProvider.LedgerDeltaByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range delta.UpdatedRegisters() {
+		committed.Registers = append(committed.Registers, CommittedRegister{
+			Owner: []byte(entry.Key.Owner),
+			Key:   []byte(entry.Key.Key),
+			Value: entry.Value,
+		})
+	}
+
+	committed.Events, err = store.EventsByHeight(ctx, height, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return committed, nil
+}
+
+// ApplyCommittedBlock appends a block exported by another emulator on top of
+// the latest block, and moves the pending block past it. The block must be a
+// child of the latest block.
+func (b *Blockchain) ApplyCommittedBlock(ctx context.Context, committed *CommittedBlock) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return err
+	}
+
+	if committed.Block.Header.Height != latestBlock.Header.Height+1 {
+		return fmt.Errorf(
+			"cannot apply block at height %d on top of height %d",
+			committed.Block.Header.Height,
+			latestBlock.Header.Height,
+		)
+	}
+
+	// the chains diverged, e.g. the source emulator was rolled back
+	if committed.Block.Header.ParentID != latestBlock.ID() {
+		return fmt.Errorf(
+			"cannot apply block %s with parent %s on top of block %s",
+			committed.Block.ID(),
+			committed.Block.Header.ParentID,
+			latestBlock.ID(),
+		)
+	}
+
+	err = committed.Commit(ctx, b.storage)
+	if err != nil {
+		return err
+	}
+
+	ledger, err := b.storage.LedgerByHeight(ctx, committed.Block.Header.Height)
+	if err != nil {
+		return err
+	}
+
+	block := committed.Block
+	b.pendingBlock = b.newPendingBlock(&block, ledger)
+
+	b.subscriptions.notifyBlockCommitted(BlockEvent{
+		Block:  &block,
+		Events: committed.Events,
+	})
+
+	return nil
+}
+
+// SyncHead moves the pending block on top of the latest block in storage,
+// picking up blocks committed by another emulator sharing the same store.
+func (b *Blockchain) SyncHead(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	latestBlock, err := b.storage.LatestBlock(ctx)
+	if err != nil {
+		return err
+	}
+
+	if latestBlock.ID() == b.pendingBlock.parentID {
+		return nil
+	}
+
+	ledger, err := b.storage.LedgerByHeight(ctx, latestBlock.Header.Height)
+	if err != nil {
+		return err
+	}
+
+	b.pendingBlock = b.newPendingBlock(&latestBlock, ledger)
+
+	events, err := b.storage.EventsByHeight(ctx, latestBlock.Header.Height, "")
+	if err != nil {
+		return err
+	}
+
+	b.subscriptions.notifyBlockCommitted(BlockEvent{
+		Block:  &latestBlock,
+		Events: events,
+	})
+
+	return nil
+}
+
+// SwitchStorage copies every committed block and the store metadata, such as the
+// configuration and pruned height, to the given empty store, then serves the
+// blockchain from it. Blocks are neither executed nor committed
+// while the blocks are copied. The pending block must be empty, as pending
+// transactions are not copied.
+//
+// The previous store is returned, so the caller can close it.
+func (b *Blockchain) SwitchStorage(ctx context.Context, target storage.Store) (storage.Store, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if !b.pendingBlock.Empty() {
+		return nil, &types.PendingBlockNotEmptyError{BlockID: b.pendingBlock.ID()}
+	}
+
+	_, err := target.LatestBlockHeight(ctx)
+	if err == nil {
+		return nil, storage.ErrStoreNotEmpty
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the pruned blocks are copied with the register versions kept for them, which the
+	// state of the blocks that are not pruned is read from
+	for height := uint64(0); height <= latestBlock.Header.Height; height++ {
+		committed, err := readCommittedBlock(ctx, b.storage, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export block at height %d: %w", height, err)
+		}
+
+		err = committed.Commit(ctx, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy block at height %d: %w", height, err)
+		}
+	}
+
+	err = copyStoreMetadata(ctx, b.storage, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// carry the accumulated coverage report over, if both stores can hold it
+	source, sourceHasReport := b.storage.(storage.CoverageReportStore)
+	destination, destinationHasReport := target.(storage.CoverageReportStore)
+	if sourceHasReport && destinationHasReport {
+		report, err := source.CoverageReport(ctx)
+		if err == nil {
+			err = destination.SetCoverageReport(ctx, report)
+		}
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	ledger, err := target.LedgerByHeight(ctx, latestBlock.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := b.storage
+	b.storage = target
+	b.pendingBlock = b.newPendingBlock(latestBlock, ledger)
+
+	return previous, nil
+}
+
+// Storage returns the store the blockchain is served from.
+func (b *Blockchain) Storage() storage.Store {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.storage
+}
+
+// Checkpoint saves the state to the checkpoint the storage was bootstrapped
+// from. Blocks are not committed while the state is copied, so the checkpoint
+// always holds whole blocks, but they are while the copy is saved.
+func (b *Blockchain) Checkpoint(ctx context.Context) error {
+	b.mu.RLock()
+	checkpointer, ok := b.storage.(storage.Checkpointer)
+	if !ok {
+		b.mu.RUnlock()
+		return storage.ErrCheckpointNotSupported
+	}
+	save, err := checkpointer.PrepareCheckpoint(ctx)
+	b.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	return save(ctx)
+}
+
+// CompactStorage reclaims the disk space of data deleted from the storage, e.g. of
+// pruned register versions, and returns the number of bytes reclaimed.
+func (b *Blockchain) CompactStorage(ctx context.Context) (int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	compactor, ok := b.storage.(storage.Compactor)
+	if !ok {
+		return 0, storage.ErrCompactionNotSupported
+	}
+
+	return compactor.Compact(ctx)
+}
@@ -0,0 +1,230 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestReplica(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("follows writer through shared storage", func(t *testing.T) {
+
+		t.Parallel()
+
+		ctx := context.Background()
+		dir := t.TempDir()
+
+		writerStore, err := sqlite.New(dir)
+		require.NoError(t, err)
+
+		writer, err := emulator.New(emulator.WithStore(writerStore))
+		require.NoError(t, err)
+
+		replicaStore, err := sqlite.New(dir)
+		require.NoError(t, err)
+
+		replica, err := emulator.New(
+			emulator.WithStore(replicaStore),
+			emulator.WithReadOnly(true),
+		)
+		require.NoError(t, err)
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { execute { log("hello") } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(writer.ServiceKey().Address, writer.ServiceKey().Index, writer.ServiceKey().SequenceNumber).
+			SetPayer(writer.ServiceKey().Address)
+
+		signer, err := writer.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(writer.ServiceKey().Address, writer.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		flowTx := convert.SDKTransactionToFlow(*tx)
+
+		err = writer.AddTransaction(ctx, *flowTx)
+		require.NoError(t, err)
+
+		block, _, err := writer.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+
+		err = replica.SyncHead(ctx)
+		require.NoError(t, err)
+
+		latest, err := replica.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, block.ID(), latest.ID())
+
+		result, err := replica.GetTransactionResult(ctx, flowTx.ID())
+		require.NoError(t, err)
+		assert.Equal(t, flowgo.TransactionStatusSealed, result.Status)
+
+		// replicas serve scripts against the writer's latest state
+		account, err := replica.GetAccount(ctx, writer.GetChain().ServiceAddress())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), account.Keys[0].SeqNumber)
+
+		err = replica.AddTransaction(ctx, *flowTx)
+		var readOnlyErr *types.ReadOnlyError
+		require.ErrorAs(t, err, &readOnlyErr)
+
+		_, err = replica.CommitBlock()
+		require.ErrorAs(t, err, &readOnlyErr)
+	})
+
+	t.Run("empty storage", func(t *testing.T) {
+
+		t.Parallel()
+
+		store, err := sqlite.New(t.TempDir())
+		require.NoError(t, err)
+
+		_, err = emulator.New(
+			emulator.WithStore(store),
+			emulator.WithReadOnly(true),
+		)
+		require.Error(t, err)
+	})
+}
+
+func TestSwitchStorage(t *testing.T) {
+
+	t.Parallel()
+
+	ctx := context.Background()
+
+	source, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	b, err := emulator.New(emulator.WithStore(source))
+	require.NoError(t, err)
+
+	sendTransaction := func(t *testing.T) *flowgo.TransactionBody {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { execute { log("hello") } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		flowTx := convert.SDKTransactionToFlow(*tx)
+
+		err = b.AddTransaction(ctx, *flowTx)
+		require.NoError(t, err)
+
+		return flowTx
+	}
+
+	flowTx := sendTransaction(t)
+	block, _, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+
+	t.Run("pending transactions", func(t *testing.T) {
+		sendTransaction(t)
+
+		target, err := sqlite.New(sqlite.InMemory)
+		require.NoError(t, err)
+
+		_, err = b.SwitchStorage(ctx, target)
+		var pendingErr *types.PendingBlockNotEmptyError
+		require.ErrorAs(t, err, &pendingErr)
+
+		_, _, err = b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+	})
+
+	t.Run("target not empty", func(t *testing.T) {
+		target, err := sqlite.New(sqlite.InMemory)
+		require.NoError(t, err)
+
+		other, err := emulator.New(emulator.WithStore(target))
+		require.NoError(t, err)
+		require.NotNil(t, other)
+
+		_, err = b.SwitchStorage(ctx, target)
+		require.ErrorIs(t, err, storage.ErrStoreNotEmpty)
+	})
+
+	t.Run("switch", func(t *testing.T) {
+		latest, err := b.GetLatestBlock(ctx)
+		require.NoError(t, err)
+
+		target, err := sqlite.New(t.TempDir())
+		require.NoError(t, err)
+
+		previous, err := b.SwitchStorage(ctx, target)
+		require.NoError(t, err)
+		assert.Same(t, source, previous)
+		assert.Same(t, target, b.Storage())
+
+		// the copied blocks and state are served from the new store
+		switched, err := b.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, latest.ID(), switched.ID())
+
+		copied, err := target.BlockByHeight(ctx, block.Header.Height)
+		require.NoError(t, err)
+		assert.Equal(t, block.ID(), copied.ID())
+
+		// the store metadata is copied along with the blocks
+		sourceConfig, err := source.GetMeta(ctx, "config")
+		require.NoError(t, err)
+		targetConfig, err := target.GetMeta(ctx, "config")
+		require.NoError(t, err)
+		assert.Equal(t, sourceConfig, targetConfig)
+
+		result, err := b.GetTransactionResult(ctx, flowTx.ID())
+		require.NoError(t, err)
+		assert.Equal(t, flowgo.TransactionStatusSealed, result.Status)
+
+		account, err := b.GetAccount(ctx, b.GetChain().ServiceAddress())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), account.Keys[0].SeqNumber)
+
+		// blocks are committed to the new store from then on
+		sendTransaction(t)
+		next, _, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+
+		stored, err := target.LatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, next.ID(), stored.ID())
+	})
+}
@@ -0,0 +1,271 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/cadence"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	sdkcrypto "github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go/fvm"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/types"
+)
+
+const testAccountPrivateKeySeed = "emulator test account private key seed"
+
+// TestAccount is a pre-funded account created during bootstrap, see WithBootstrapAccounts.
+type TestAccount struct {
+	Address    flowsdk.Address
+	PrivateKey sdkcrypto.PrivateKey
+	SigAlgo    sdkcrypto.SignatureAlgorithm
+	HashAlgo   sdkcrypto.HashAlgorithm
+}
+
+func (a TestAccount) Signer() (sdkcrypto.Signer, error) {
+	return sdkcrypto.NewInMemorySigner(a.PrivateKey, a.HashAlgo)
+}
+
+// AccountKey returns the only key of the account, which has full weight.
+func (a TestAccount) AccountKey() *flowsdk.AccountKey {
+	return &flowsdk.AccountKey{
+		Index:     0,
+		PublicKey: a.PrivateKey.PublicKey(),
+		SigAlgo:   a.SigAlgo,
+		HashAlgo:  a.HashAlgo,
+		Weight:    flowsdk.AccountKeyWeightThreshold,
+	}
+}
+
+// generateTestAccountKey derives the private key of the test account with the given index,
+// so the keys of test accounts are the same every time the emulator starts.
+func generateTestAccountKey(index int) (sdkcrypto.PrivateKey, error) {
+	return sdkcrypto.GeneratePrivateKey(
+		DefaultServiceKeySigAlgo,
+		[]byte(fmt.Sprintf("%s %d", testAccountPrivateKeySeed, index)),
+	)
+}
+
+// createTestAccountsTransaction creates an account for each of the given public keys,
+// and transfers the initial balance to it from the service account.
+const createTestAccountsTransaction = `
+import FungibleToken from %s
+import FlowToken from %s
+
+transaction(publicKeys: [String], initialBalance: UFix64) {
+	prepare(signer: AuthAccount) {
+		let vault = signer.borrow<&FlowToken.Vault>(from: /storage/flowTokenVault)
+			?? panic("could not borrow the FLOW vault of the service account")
+
+		for publicKey in publicKeys {
+			let account = AuthAccount(payer: signer)
+			account.keys.add(
+				publicKey: PublicKey(
+					publicKey: publicKey.decodeHex(),
+					signatureAlgorithm: SignatureAlgorithm.ECDSA_P256
+				),
+				hashAlgorithm: HashAlgorithm.SHA3_256,
+				weight: 1000.0
+			)
+
+			account.getCapability(/public/flowTokenReceiver)
+				.borrow<&{FungibleToken.Receiver}>()!
+				.deposit(from: <- vault.withdraw(amount: initialBalance))
+		}
+	}
+}
+`
+
+// TestAccounts returns the accounts created during bootstrap, see WithBootstrapAccounts.
+func (b *Blockchain) TestAccounts() []TestAccount {
+	return b.testAccounts
+}
+
+// bootstrapTestAccounts creates the configured number of test accounts, funded with
+// the configured initial balance. Test accounts created by a previous run on the
+// same storage are reused.
+func (b *Blockchain) bootstrapTestAccounts() error {
+	count := b.conf.BootstrapAccounts
+
+	keys := make([]sdkcrypto.PrivateKey, count)
+	for i := range keys {
+		key, err := generateTestAccountKey(i)
+		if err != nil {
+			return fmt.Errorf("failed to generate test account key: %w", err)
+		}
+		keys[i] = key
+	}
+
+	addresses, err := b.findTestAccounts(keys)
+	if err != nil {
+		return err
+	}
+
+	var missing []int
+	for i, address := range addresses {
+		if address == flowsdk.EmptyAddress {
+			missing = append(missing, i)
+		}
+	}
+
+	if len(missing) > 0 {
+		missingKeys := make([]sdkcrypto.PrivateKey, len(missing))
+		for i, index := range missing {
+			missingKeys[i] = keys[index]
+		}
+
+		created, err := b.createTestAccounts(missingKeys)
+		if err != nil {
+			return fmt.Errorf("failed to create test accounts: %w", err)
+		}
+
+		for i, index := range missing {
+			addresses[index] = created[i]
+		}
+	}
+
+	b.testAccounts = make([]TestAccount, count)
+	for i, key := range keys {
+		b.testAccounts[i] = TestAccount{
+			Address:    addresses[i],
+			PrivateKey: key,
+			SigAlgo:    DefaultServiceKeySigAlgo,
+			HashAlgo:   DefaultServiceKeyHashAlgo,
+		}
+	}
+
+	return nil
+}
+
+// findTestAccounts returns the addresses of the existing accounts with the given keys,
+// and the empty address for the keys without account.
+func (b *Blockchain) findTestAccounts(keys []sdkcrypto.PrivateKey) ([]flowsdk.Address, error) {
+	addresses := make([]flowsdk.Address, len(keys))
+
+	// accounts are created in the order of their index, so the first missing index ends the search
+	for index := uint(1); ; index++ {
+		account, err := b.GetAccountByIndex(context.Background(), index)
+		var notFoundErr *types.AccountNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return addresses, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(account.Keys) != 1 {
+			continue
+		}
+
+		for i, key := range keys {
+			if account.Keys[0].PublicKey.Equals(key.PublicKey()) {
+				addresses[i] = flowsdk.Address(account.Address)
+			}
+		}
+	}
+}
+
+// createTestAccounts creates and funds an account for each of the given keys, in a block of its own.
+func (b *Blockchain) createTestAccounts(keys []sdkcrypto.PrivateKey) ([]flowsdk.Address, error) {
+	serviceKey := b.ServiceKey()
+	serviceAddress := serviceKey.Address
+
+	if serviceKey.PrivateKey == nil {
+		return nil, fmt.Errorf("not able to create test accounts without set private key")
+	}
+
+	latestBlock, err := b.GetLatestBlock(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeys := make([]cadence.Value, len(keys))
+	for i, key := range keys {
+		publicKeys[i] = cadence.String(hex.EncodeToString(key.PublicKey().Encode()))
+	}
+
+	chain := b.GetChain()
+	script := fmt.Sprintf(
+		createTestAccountsTransaction,
+		fvm.FungibleTokenAddress(chain).HexWithPrefix(),
+		fvm.FlowTokenAddress(chain).HexWithPrefix(),
+	)
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(script)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetReferenceBlockID(flowsdk.Identifier(latestBlock.ID())).
+		SetProposalKey(serviceAddress, serviceKey.Index, serviceKey.SequenceNumber).
+		SetPayer(serviceAddress).
+		AddAuthorizer(serviceAddress)
+
+	err = tx.AddArgument(cadence.NewArray(publicKeys))
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.AddArgument(b.conf.BootstrapAccountBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := serviceKey.Signer()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.SignEnvelope(serviceAddress, serviceKey.Index, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+	if err != nil {
+		return nil, err
+	}
+
+	_, results, err := b.ExecuteAndCommitBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	result := results[len(results)-1]
+	if !result.Succeeded() {
+		return nil, result.Error
+	}
+
+	var addresses []flowsdk.Address
+	for _, event := range result.Events {
+		if event.Type == flowsdk.EventAccountCreated {
+			addresses = append(addresses, flowsdk.Address(event.Value.Fields[0].(cadence.Address)))
+		}
+	}
+
+	if len(addresses) != len(keys) {
+		return nil, fmt.Errorf("expected %d AccountCreated events, got %d", len(keys), len(addresses))
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,117 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/cadence"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+func TestBootstrapAccounts(t *testing.T) {
+
+	t.Parallel()
+
+	initialBalance, err := cadence.NewUFix64("10.0")
+	require.NoError(t, err)
+
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	b, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithBootstrapAccounts(3, initialBalance),
+	)
+	require.NoError(t, err)
+
+	accounts := b.TestAccounts()
+	require.Len(t, accounts, 3)
+
+	addresses := map[flowsdk.Address]struct{}{}
+	for _, testAccount := range accounts {
+		addresses[testAccount.Address] = struct{}{}
+
+		account, err := b.GetAccount(context.Background(), flowgo.Address(testAccount.Address))
+		require.NoError(t, err)
+
+		require.Len(t, account.Keys, 1)
+		assert.True(t, account.Keys[0].PublicKey.Equals(testAccount.PrivateKey.PublicKey()))
+		assert.GreaterOrEqual(t, account.Balance, uint64(initialBalance))
+	}
+	assert.Len(t, addresses, 3)
+
+	t.Run("deterministic", func(t *testing.T) {
+		t.Parallel()
+
+		other, err := emulator.New(emulator.WithBootstrapAccounts(3, initialBalance))
+		require.NoError(t, err)
+
+		assert.Equal(t, accounts, other.TestAccounts())
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		latestBlock, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+
+		restarted, err := emulator.New(
+			emulator.WithStore(store),
+			emulator.WithBootstrapAccounts(3, initialBalance),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, accounts, restarted.TestAccounts())
+
+		restartedBlock, err := restarted.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, latestBlock.Header.Height, restartedBlock.Header.Height)
+	})
+
+	t.Run("sign transaction", func(t *testing.T) {
+		testAccount := accounts[0]
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { prepare(signer: AuthAccount) {} }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(testAccount.Address, 0, 0).
+			SetPayer(testAccount.Address).
+			AddAuthorizer(testAccount.Address)
+
+		signer, err := testAccount.Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(testAccount.Address, 0, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, results[0])
+	})
+}
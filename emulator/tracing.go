@@ -0,0 +1,55 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/module/trace"
+	"go.opentelemetry.io/otel/attribute"
+	otelTrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// SpanExecuteTransaction is the root span of the execution of a transaction.
+	SpanExecuteTransaction trace.SpanName = "emulator.executeTransaction"
+	// SpanExecuteScript is the root span of the execution of a script.
+	SpanExecuteScript trace.SpanName = "emulator.executeScript"
+)
+
+// startExecutionSpan starts the root span of the execution of a transaction or script,
+// as a child of the span of the given context, if any, and returns the FVM context
+// which records the spans of the execution under it.
+//
+// Without tracer, the span is a no-op and the FVM context is returned unchanged.
+func (b *Blockchain) startExecutionSpan(
+	ctx context.Context,
+	fvmCtx fvm.Context,
+	name trace.SpanName,
+	attributes ...attribute.KeyValue,
+) (fvm.Context, otelTrace.Span) {
+	if b.conf.Tracer == nil {
+		return fvmCtx, trace.NoopSpan
+	}
+
+	span, _ := b.conf.Tracer.StartSpanFromContext(ctx, name, otelTrace.WithAttributes(attributes...))
+
+	return fvm.NewContextFromParent(fvmCtx, fvm.WithSpan(span)), span
+}
@@ -0,0 +1,134 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otelTrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+// recordingTracer records the names of the spans started with it.
+type recordingTracer struct {
+	*trace.NoopTracer
+	mu    sync.Mutex
+	spans []trace.SpanName
+}
+
+func (t *recordingTracer) record(name trace.SpanName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, name)
+}
+
+func (t *recordingTracer) StartSpanFromContext(
+	ctx context.Context,
+	operationName trace.SpanName,
+	opts ...otelTrace.SpanStartOption,
+) (otelTrace.Span, context.Context) {
+	t.record(operationName)
+	return trace.NoopSpan, ctx
+}
+
+func (t *recordingTracer) StartSpanFromParent(
+	parentSpan otelTrace.Span,
+	operationName trace.SpanName,
+	opts ...otelTrace.SpanStartOption,
+) otelTrace.Span {
+	t.record(operationName)
+	return trace.NoopSpan
+}
+
+func (t *recordingTracer) recorded() []trace.SpanName {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := t.spans
+	t.spans = nil
+	return spans
+}
+
+func TestTracer(t *testing.T) {
+
+	t.Parallel()
+
+	tracer := &recordingTracer{NoopTracer: trace.NewNoopTracer()}
+
+	b, err := emulator.New(
+		emulator.WithTracer(tracer),
+	)
+	require.NoError(t, err)
+
+	// ignore the spans of the bootstrapping
+	tracer.recorded()
+
+	t.Run("transaction", func(t *testing.T) {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { prepare(signer: AuthAccount) { log(signer.address) } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address).
+			AddAuthorizer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		AssertTransactionSucceeded(t, results[0])
+
+		spans := tracer.recorded()
+		require.NotEmpty(t, spans)
+		assert.Equal(t, emulator.SpanExecuteTransaction, spans[0])
+		assert.Contains(t, spans, trace.FVMExecuteTransaction)
+		assert.Contains(t, spans, trace.FVMCadenceTrace.Child("interpretProgram"))
+	})
+
+	t.Run("script", func(t *testing.T) {
+		result, err := b.ExecuteScript(
+			context.Background(),
+			[]byte(`pub fun main(): UFix64 { return getAccount(0xf8d6e0586b0a20c7).balance }`),
+			nil,
+		)
+		require.NoError(t, err)
+		require.True(t, result.Succeeded())
+
+		spans := tracer.recorded()
+		require.NotEmpty(t, spans)
+		assert.Equal(t, emulator.SpanExecuteScript, spans[0])
+		assert.Contains(t, spans, trace.FVMEnvGetValue)
+		assert.Contains(t, spans, trace.FVMCadenceTrace.Child("interpretProgram"))
+	})
+}
@@ -301,6 +301,98 @@ func TestSubmitTransaction_Invalid(t *testing.T) {
 		assert.Equal(t, invalidSequenceNumber, seqErr.ProvidedSeqNumber())
 	})
 
+	t.Run("Sequence number check", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, adapter := setupTransactionTests(
+			t,
+			emulator.WithStorageLimitEnabled(false),
+		)
+
+		addTwoScript, _ := DeployAndGenerateAddTwoScript(t, adapter)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		newTransaction := func(sequenceNumber uint64) *flowgo.TransactionBody {
+			tx := flowsdk.NewTransaction().
+				SetScript([]byte(addTwoScript)).
+				SetPayer(b.ServiceKey().Address).
+				SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, sequenceNumber).
+				SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+				AddAuthorizer(b.ServiceKey().Address)
+
+			err := tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+			require.NoError(t, err)
+
+			return convert.SDKTransactionToFlow(*tx)
+		}
+
+		sequenceNumber := b.ServiceKey().SequenceNumber
+
+		tx := newTransaction(sequenceNumber)
+		require.NoError(t, b.CheckSequenceNumber(context.Background(), tx))
+
+		err = b.AddTransaction(context.Background(), *tx)
+		require.NoError(t, err)
+
+		// the pending transaction uses the current sequence number
+		err = b.CheckSequenceNumber(context.Background(), newTransaction(sequenceNumber))
+
+		var seqErr *types.InvalidSequenceNumberError
+		require.ErrorAs(t, err, &seqErr)
+		assert.Equal(t, sequenceNumber+1, seqErr.Expected)
+		assert.Equal(t, sequenceNumber, seqErr.Provided)
+
+		require.NoError(t, b.CheckSequenceNumber(context.Background(), newTransaction(sequenceNumber+1)))
+
+		_, _, err = b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+
+		require.NoError(t, b.CheckSequenceNumber(context.Background(), newTransaction(sequenceNumber+1)))
+
+		// checked transactions are rejected when submitted
+		err = b.SendCheckedTransaction(context.Background(), newTransaction(sequenceNumber))
+		require.ErrorAs(t, err, &seqErr)
+		assert.Equal(t, sequenceNumber+1, seqErr.Expected)
+
+		// scheduled transactions are counted
+		latest, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+
+		scheduled := newTransaction(sequenceNumber + 1)
+		err = b.AddScheduledTransaction(context.Background(), *scheduled, latest.Header.Height+3)
+		require.NoError(t, err)
+
+		err = b.SendCheckedTransaction(context.Background(), newTransaction(sequenceNumber+1))
+		require.ErrorAs(t, err, &seqErr)
+		assert.Equal(t, sequenceNumber+2, seqErr.Expected)
+
+		require.NoError(t, b.SendCheckedTransaction(context.Background(), newTransaction(sequenceNumber+2)))
+	})
+
+	t.Run("Sequence number check without validation", func(t *testing.T) {
+
+		t.Parallel()
+
+		b, _ := setupTransactionTests(
+			t,
+			emulator.WithStorageLimitEnabled(false),
+			emulator.WithTransactionValidationEnabled(false),
+		)
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction {}`)).
+			SetPayer(b.ServiceKey().Address).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber+10).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit)
+
+		// the sequence number is left to the execution to check
+		err := b.SendCheckedTransaction(context.Background(), convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+	})
+
 	const expiry = 10
 
 	t.Run("Missing reference block ID", func(t *testing.T) {
@@ -343,7 +435,7 @@ func TestSubmitTransaction_Invalid(t *testing.T) {
 
 		addTwoScript, _ := DeployAndGenerateAddTwoScript(t, adapter)
 
-		expiredBlock, err := b.GetLatestBlock()
+		expiredBlock, err := b.GetLatestBlock(context.Background())
 		require.NoError(t, err)
 
 		// commit blocks until expiry window is exceeded
@@ -421,7 +513,7 @@ func TestSubmitTransaction_Invalid(t *testing.T) {
 		accountKeyB.HashAlgo = crypto.SHA3_256
 		accountKeyB.Weight = flowsdk.AccountKeyWeightThreshold
 
-		accountAddressB, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyB}, nil)
+		accountAddressB, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyB}, nil, 0)
 		assert.NoError(t, err)
 
 		tx := flowsdk.NewTransaction().
@@ -597,6 +689,47 @@ func TestSubmitTransaction_Reverted(t *testing.T) {
 	assert.Error(t, tx1Result.Error)
 }
 
+func TestSubmitTransaction_TruncatedErrorMessage(t *testing.T) {
+
+	t.Parallel()
+
+	b, _ := setupTransactionTests(t, emulator.WithErrorMessageMaxLength(100))
+
+	sdkTx := flowsdk.NewTransaction().
+		SetScript([]byte(fmt.Sprintf(`transaction { execute { panic("%s") } }`, strings.Repeat("x", 500)))).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = sdkTx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	tx := convert.SDKTransactionToFlow(*sdkTx)
+
+	err = b.AddTransaction(context.Background(), *tx)
+	require.NoError(t, err)
+
+	_, _, err = b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+
+	result, err := b.GetTransactionResult(context.Background(), tx.ID())
+	require.NoError(t, err)
+	assert.Len(t, result.ErrorMessage, 100)
+	assert.Contains(t, result.ErrorMessage, types.TruncatedMarker)
+
+	// the full message remains available
+	txErr, err := b.GetTransactionError(context.Background(), tx.ID())
+	require.NoError(t, err)
+	assert.Contains(t, txErr.Message, strings.Repeat("x", 500))
+
+	_, err = b.GetTransactionError(context.Background(), flowgo.Identifier{})
+	var notFoundErr *types.TransactionNotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
 func TestSubmitTransaction_Authorizers(t *testing.T) {
 
 	t.Parallel()
@@ -611,7 +744,7 @@ func TestSubmitTransaction_Authorizers(t *testing.T) {
 	accountKeyB, signerB := accountKeys.NewWithSigner()
 	accountKeyB.SetWeight(flowsdk.AccountKeyWeightThreshold)
 
-	accountAddressB, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyB}, nil)
+	accountAddressB, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyB}, nil, 0)
 	assert.NoError(t, err)
 
 	t.Run("Extra authorizers", func(t *testing.T) {
@@ -873,7 +1006,7 @@ func TestSubmitTransaction_EnvelopeSignature(t *testing.T) {
 		accountKeyB, signerB := accountKeys.NewWithSigner()
 		accountKeyB.SetWeight(flowsdk.AccountKeyWeightThreshold / 2)
 
-		accountAddressA, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyA, accountKeyB}, nil)
+		accountAddressA, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyA, accountKeyB}, nil, 0)
 		assert.NoError(t, err)
 
 		script := []byte(`
@@ -942,7 +1075,7 @@ func TestSubmitTransaction_PayloadSignatures(t *testing.T) {
 		accountKeyB, _ := accountKeys.NewWithSigner()
 		accountKeyB.SetWeight(flowsdk.AccountKeyWeightThreshold)
 
-		accountAddressB, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyB}, nil)
+		accountAddressB, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyB}, nil, 0)
 		assert.NoError(t, err)
 
 		tx := flowsdk.NewTransaction().
@@ -981,7 +1114,7 @@ func TestSubmitTransaction_PayloadSignatures(t *testing.T) {
 		accountKeyB, signerB := accountKeys.NewWithSigner()
 		accountKeyB.SetWeight(flowsdk.AccountKeyWeightThreshold)
 
-		accountAddressB, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyB}, nil)
+		accountAddressB, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKeyB}, nil, 0)
 		assert.NoError(t, err)
 
 		multipleAccountScript := []byte(`
@@ -1437,12 +1570,26 @@ func TestGetTransactionResult(t *testing.T) {
 	assert.Equal(t, flowsdk.TransactionStatusPending, result.Status)
 	require.Empty(t, result.Events)
 
-	_, err = b.CommitBlock()
-	assert.NoError(t, err)
+	block, err := b.CommitBlock()
+	require.NoError(t, err)
 
 	result, err = adapter.GetTransactionResult(context.Background(), tx.ID())
 	assert.NoError(t, err)
 	assert.Equal(t, flowsdk.TransactionStatusSealed, result.Status)
+	assert.Equal(t, flowsdk.Identifier(block.ID()), result.BlockID)
+	assert.Equal(t, block.Header.Height, result.BlockHeight)
+
+	flowResult, err := b.GetTransactionResult(context.Background(), flowgo.Identifier(tx.ID()))
+	require.NoError(t, err)
+	require.Len(t, block.Payload.Guarantees, 1)
+	assert.Equal(t, block.Payload.Guarantees[0].CollectionID, flowResult.CollectionID)
+
+	indexedResult, err := b.GetTransactionResultByIndex(context.Background(), block.ID(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, flowResult, indexedResult)
+
+	_, err = b.GetTransactionResultByIndex(context.Background(), block.ID(), 1)
+	assert.ErrorAs(t, err, new(*types.TransactionNotFoundError))
 
 	require.Len(t, result.Events, 1)
 
@@ -1807,7 +1954,7 @@ func TestInfiniteTransaction(t *testing.T) {
 
 	accountKeys := test.AccountKeyGenerator()
 	accountKey, signer := accountKeys.NewWithSigner()
-	accountAddress, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKey}, nil)
+	accountAddress, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKey}, nil, 0)
 	assert.NoError(t, err)
 
 	// Sign the transaction using the new account.
@@ -1873,7 +2020,7 @@ func TestTransactionExecutionLimit(t *testing.T) {
 
 		accountKeys := test.AccountKeyGenerator()
 		accountKey, signer := accountKeys.NewWithSigner()
-		accountAddress, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKey}, nil)
+		accountAddress, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKey}, nil, 0)
 		assert.NoError(t, err)
 
 		// Sign the transaction using the new account.
@@ -1916,7 +2063,7 @@ func TestTransactionExecutionLimit(t *testing.T) {
 
 		accountKeys := test.AccountKeyGenerator()
 		accountKey, signer := accountKeys.NewWithSigner()
-		accountAddress, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKey}, nil)
+		accountAddress, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKey}, nil, 0)
 		assert.NoError(t, err)
 
 		// Sign the transaction using the new account.
@@ -2094,11 +2241,11 @@ func TestRollbackTransaction(t *testing.T) {
 
 	addTwoScript, counterAddress := DeployAndGenerateAddTwoScript(t, adapter)
 
-	blockWhenNoCounter, err := b.GetLatestBlock()
+	blockWhenNoCounter, err := b.GetLatestBlock(context.Background())
 	require.NoError(t, err)
 
 	IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 2)
-	blockWhenCounterIsTwo, err := b.GetLatestBlock()
+	blockWhenCounterIsTwo, err := b.GetLatestBlock(context.Background())
 	require.NoError(t, err)
 
 	IncrementHelper(t, b, adapter, counterAddress, addTwoScript, 4)
@@ -0,0 +1,445 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go/fvm"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// VaultLinkStatus is the state of a public capability to a vault.
+type VaultLinkStatus string
+
+const (
+	// VaultLinkOK is a public capability which can be borrowed with the expected interface.
+	VaultLinkOK VaultLinkStatus = "ok"
+	// VaultLinkMissing is a capability no public path is linked for.
+	VaultLinkMissing VaultLinkStatus = "missing"
+	// VaultLinkBroken is a public path linked to the vault, or the canonical path of the FLOW vault,
+	// which can not be borrowed with the expected interface.
+	VaultLinkBroken VaultLinkStatus = "broken"
+)
+
+// VaultLink is the public capability to a vault for one of the fungible token interfaces.
+type VaultLink struct {
+	Path   string          `json:"path,omitempty"`
+	Status VaultLinkStatus `json:"status"`
+}
+
+// Vault is a fungible token vault stored in an account, and the state of its public capabilities.
+type Vault struct {
+	Path     string    `json:"path"`
+	Type     string    `json:"type"`
+	Missing  bool      `json:"missing,omitempty"`
+	Receiver VaultLink `json:"receiver"`
+	Balance  VaultLink `json:"balance"`
+}
+
+// Healthy returns true if the vault exists and its receiver and balance capabilities are linked.
+func (v Vault) Healthy() bool {
+	return !v.Missing &&
+		v.Receiver.Status == VaultLinkOK &&
+		v.Balance.Status == VaultLinkOK
+}
+
+const (
+	flowTokenVaultPath    = "/storage/flowTokenVault"
+	flowTokenReceiverPath = "/public/flowTokenReceiver"
+	flowTokenBalancePath  = "/public/flowTokenBalance"
+)
+
+// inspectVaultsScript lists the fungible token vaults stored in an account,
+// and its public links, with the fungible token interfaces they can be borrowed with.
+const inspectVaultsScript = `
+import FungibleToken from %s
+
+pub struct StoredVault {
+	pub let path: String
+	pub let type: String
+
+	init(path: String, type: String) {
+		self.path = path
+		self.type = type
+	}
+}
+
+pub struct PublicLink {
+	pub let path: String
+	pub let target: String
+	pub let receiver: Bool
+	pub let balance: Bool
+
+	init(path: String, target: String, receiver: Bool, balance: Bool) {
+		self.path = path
+		self.target = target
+		self.receiver = receiver
+		self.balance = balance
+	}
+}
+
+pub struct Inspection {
+	pub let vaults: [StoredVault]
+	pub let links: [PublicLink]
+
+	init(vaults: [StoredVault], links: [PublicLink]) {
+		self.vaults = vaults
+		self.links = links
+	}
+}
+
+pub fun main(address: Address): Inspection {
+	let account = getAuthAccount(address)
+
+	let vaults: [StoredVault] = []
+	account.forEachStored(fun (path: StoragePath, type: Type): Bool {
+		if type.isSubtype(of: Type<@FungibleToken.Vault>()) {
+			vaults.append(StoredVault(path: path.toString(), type: type.identifier))
+		}
+		return true
+	})
+
+	let links: [PublicLink] = []
+	account.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+		var target = ""
+		if let linkTarget = account.getLinkTarget(path) {
+			target = linkTarget.toString()
+		}
+		links.append(PublicLink(
+			path: path.toString(),
+			target: target,
+			receiver: account.getCapability<&{FungibleToken.Receiver}>(path).check(),
+			balance: account.getCapability<&{FungibleToken.Balance}>(path).check()
+		))
+		return true
+	})
+
+	return Inspection(vaults: vaults, links: links)
+}
+`
+
+type vaultInspection struct {
+	vaults []Vault
+	links  []publicLink
+}
+
+type publicLink struct {
+	path     string
+	target   string
+	receiver bool
+	balance  bool
+}
+
+// CheckVaults returns the fungible token vaults of an account at the latest block,
+// and whether their public receiver and balance capabilities are linked.
+// The FLOW vault is always listed, and marked as missing if it is not stored.
+func (b *Blockchain) CheckVaults(ctx context.Context, address flowgo.Address) ([]Vault, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.checkVaults(ctx, address)
+}
+
+// RepairVaults relinks the broken or missing receiver and balance capabilities of the
+// vaults of an account, and stores an empty FLOW vault if it is missing, in a block of its own.
+//
+// Links are only repaired where the path is known: the canonical paths of the FLOW vault,
+// and the public paths linked to a vault. It returns the vaults after the repair,
+// and the result of the repair transaction, which is nil if nothing needed to be repaired.
+func (b *Blockchain) RepairVaults(ctx context.Context, address flowgo.Address) ([]Vault, *types.TransactionResult, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.conf.ReadOnly {
+		return nil, nil, &types.ReadOnlyError{}
+	}
+
+	vaults, err := b.checkVaults(ctx, address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	script, err := b.vaultRepairTransaction(vaults)
+	if err != nil {
+		return nil, nil, err
+	}
+	if script == nil {
+		return vaults, nil, nil
+	}
+
+	result, err := b.commitAccountTransaction(ctx, address, script)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !result.Succeeded() {
+		return vaults, result, nil
+	}
+
+	vaults, err = b.checkVaults(ctx, address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vaults, result, nil
+}
+
+func (b *Blockchain) checkVaults(ctx context.Context, address flowgo.Address) ([]Vault, error) {
+	// also ensures the account exists
+	_, err := b.getAccount(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	inspection, err := b.inspectVaults(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	flowTokenVaultType := b.flowTokenVaultType()
+
+	vaults := inspection.vaults
+	hasFlowTokenVault := false
+	for _, vault := range vaults {
+		if vault.Path == flowTokenVaultPath {
+			hasFlowTokenVault = true
+		}
+	}
+	if !hasFlowTokenVault {
+		vaults = append(vaults, Vault{
+			Path:    flowTokenVaultPath,
+			Type:    flowTokenVaultType,
+			Missing: true,
+		})
+	}
+
+	for i := range vaults {
+		vault := &vaults[i]
+		flowTokenVault := vault.Path == flowTokenVaultPath && vault.Type == flowTokenVaultType
+
+		vault.Receiver = vaultLink(vault, inspection.links, flowTokenVault, false)
+		vault.Balance = vaultLink(vault, inspection.links, flowTokenVault, true)
+	}
+
+	sort.Slice(vaults, func(i, j int) bool {
+		return vaults[i].Path < vaults[j].Path
+	})
+
+	return vaults, nil
+}
+
+// vaultLink determines the state of the receiver or balance capability of a vault.
+//
+// If no public path linked to the vault can be borrowed with the interface,
+// the capability is broken if the path it is expected at is linked:
+// the canonical path for the FLOW vault, otherwise a path linked to the vault
+// which can not be borrowed with either interface, named for the balance or not.
+func vaultLink(vault *Vault, links []publicLink, flowTokenVault bool, balance bool) VaultLink {
+	borrowable := func(link publicLink) bool {
+		if balance {
+			return link.balance
+		}
+		return link.receiver
+	}
+
+	if !vault.Missing {
+		for _, link := range links {
+			if link.target == vault.Path && borrowable(link) {
+				return VaultLink{Path: link.path, Status: VaultLinkOK}
+			}
+		}
+	}
+
+	if flowTokenVault {
+		path := flowTokenReceiverPath
+		if balance {
+			path = flowTokenBalancePath
+		}
+		for _, link := range links {
+			if link.path == path {
+				return VaultLink{Path: path, Status: VaultLinkBroken}
+			}
+		}
+		return VaultLink{Path: path, Status: VaultLinkMissing}
+	}
+
+	for _, link := range links {
+		if link.target != vault.Path || link.receiver || link.balance {
+			continue
+		}
+		if strings.Contains(strings.ToLower(link.path), "balance") == balance {
+			return VaultLink{Path: link.path, Status: VaultLinkBroken}
+		}
+	}
+
+	return VaultLink{Status: VaultLinkMissing}
+}
+
+func (b *Blockchain) flowTokenVaultType() string {
+	return fmt.Sprintf("A.%s.FlowToken.Vault", fvm.FlowTokenAddress(b.GetChain()).Hex())
+}
+
+func (b *Blockchain) inspectVaults(ctx context.Context, address flowgo.Address) (*vaultInspection, error) {
+	latestBlock, err := b.getLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	script := fmt.Sprintf(inspectVaultsScript, fvm.FungibleTokenAddress(b.GetChain()).HexWithPrefix())
+
+	argument, err := jsoncdc.Encode(cadence.NewAddress(address))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := b.executeScriptAtBlockID(ctx, []byte(script), [][]byte{argument}, latestBlock.ID())
+	if err != nil {
+		return nil, err
+	}
+	if !result.Succeeded() {
+		return nil, fmt.Errorf("failed to inspect vaults: %w", result.Error)
+	}
+
+	value, ok := result.Value.(cadence.Struct)
+	if !ok || len(value.Fields) != 2 {
+		return nil, fmt.Errorf("unexpected vault inspection result: %s", result.Value)
+	}
+
+	inspection := &vaultInspection{}
+
+	for _, element := range value.Fields[0].(cadence.Array).Values {
+		fields := element.(cadence.Struct).Fields
+		inspection.vaults = append(inspection.vaults, Vault{
+			Path: string(fields[0].(cadence.String)),
+			Type: string(fields[1].(cadence.String)),
+		})
+	}
+
+	for _, element := range value.Fields[1].(cadence.Array).Values {
+		fields := element.(cadence.Struct).Fields
+		inspection.links = append(inspection.links, publicLink{
+			path:     string(fields[0].(cadence.String)),
+			target:   string(fields[1].(cadence.String)),
+			receiver: bool(fields[2].(cadence.Bool)),
+			balance:  bool(fields[3].(cadence.Bool)),
+		})
+	}
+
+	return inspection, nil
+}
+
+// vaultRepairTransaction generates the transaction repairing the given vaults,
+// or returns nil if there is nothing to repair.
+func (b *Blockchain) vaultRepairTransaction(vaults []Vault) ([]byte, error) {
+	chain := b.GetChain()
+
+	imports := map[string]string{
+		"FungibleToken": fvm.FungibleTokenAddress(chain).HexWithPrefix(),
+	}
+	var statements []string
+
+	for _, vault := range vaults {
+		if vault.Missing {
+			// only the FLOW vault is listed when missing
+			imports["FlowToken"] = fvm.FlowTokenAddress(chain).HexWithPrefix()
+			statements = append(statements, fmt.Sprintf(
+				"account.save(<- FlowToken.createEmptyVault(), to: %s)",
+				vault.Path,
+			))
+		}
+
+		for _, repair := range []struct {
+			link       VaultLink
+			capability string
+		}{
+			{vault.Receiver, "FungibleToken.Receiver"},
+			{vault.Balance, "FungibleToken.Balance"},
+		} {
+			if repair.link.Status == VaultLinkOK || repair.link.Path == "" {
+				continue
+			}
+
+			contract, address, typeName, err := parseVaultType(vault.Type)
+			if err != nil {
+				return nil, err
+			}
+			if imported, ok := imports[contract]; ok && imported != address {
+				return nil, types.NewInvalidArgumentError(
+					fmt.Sprintf("vaults of contracts named %s at different addresses can not be repaired together", contract),
+				)
+			}
+			imports[contract] = address
+
+			statements = append(
+				statements,
+				fmt.Sprintf("account.unlink(%s)", repair.link.Path),
+				fmt.Sprintf(
+					"account.link<&%s{%s}>(%s, target: %s)",
+					typeName,
+					repair.capability,
+					repair.link.Path,
+					vault.Path,
+				),
+			)
+		}
+	}
+
+	if len(statements) == 0 {
+		return nil, nil
+	}
+
+	contracts := make([]string, 0, len(imports))
+	for contract := range imports {
+		contracts = append(contracts, contract)
+	}
+	sort.Strings(contracts)
+
+	var script strings.Builder
+	for _, contract := range contracts {
+		_, _ = fmt.Fprintf(&script, "import %s from %s\n", contract, imports[contract])
+	}
+	script.WriteString("\ntransaction {\n\tprepare(account: AuthAccount) {\n")
+	for _, statement := range statements {
+		_, _ = fmt.Fprintf(&script, "\t\t%s\n", statement)
+	}
+	script.WriteString("\t}\n}\n")
+
+	return []byte(script.String()), nil
+}
+
+// parseVaultType splits the identifier of a composite type declared in a contract,
+// e.g. A.0ae53cb6e3f42a79.FlowToken.Vault, into the contract name, the contract address,
+// and the name of the type qualified by the contract name.
+func parseVaultType(identifier string) (contract string, address string, typeName string, err error) {
+	parts := strings.SplitN(identifier, ".", 4)
+	if len(parts) != 4 || parts[0] != "A" {
+		return "", "", "", types.NewInvalidArgumentError(
+			fmt.Sprintf("vault type %s is not declared in a contract", identifier),
+		)
+	}
+
+	return parts[2], "0x" + parts[1], parts[2] + "." + parts[3], nil
+}
@@ -0,0 +1,240 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emulator_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/templates"
+	"github.com/onflow/flow-go/fvm"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+const exampleTokenContract = `
+	import FungibleToken from %s
+
+	pub contract ExampleToken: FungibleToken {
+		pub var totalSupply: UFix64
+
+		pub event TokensInitialized(initialSupply: UFix64)
+		pub event TokensWithdrawn(amount: UFix64, from: Address?)
+		pub event TokensDeposited(amount: UFix64, to: Address?)
+
+		pub resource Vault: FungibleToken.Provider, FungibleToken.Receiver, FungibleToken.Balance {
+			pub var balance: UFix64
+
+			init(balance: UFix64) {
+				self.balance = balance
+			}
+
+			pub fun withdraw(amount: UFix64): @FungibleToken.Vault {
+				self.balance = self.balance - amount
+				return <- create Vault(balance: amount)
+			}
+
+			pub fun deposit(from: @FungibleToken.Vault) {
+				let vault <- from as! @ExampleToken.Vault
+				self.balance = self.balance + vault.balance
+				vault.balance = 0.0
+				destroy vault
+			}
+		}
+
+		pub fun createEmptyVault(): @FungibleToken.Vault {
+			return <- create Vault(balance: 0.0)
+		}
+
+		init() {
+			self.totalSupply = 0.0
+		}
+	}
+`
+
+func TestVaultRecovery(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithStorageLimitEnabled(false),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	chain := b.GetChain()
+	fungibleTokenAddress := fvm.FungibleTokenAddress(chain).HexWithPrefix()
+	flowTokenAddress := fvm.FlowTokenAddress(chain).HexWithPrefix()
+	flowTokenVaultType := fmt.Sprintf("A.%s.FlowToken.Vault", fvm.FlowTokenAddress(chain).Hex())
+
+	tokenAddress, err := adapter.CreateAccount(
+		context.Background(),
+		nil,
+		[]templates.Contract{{
+			Name:   "ExampleToken",
+			Source: fmt.Sprintf(exampleTokenContract, fungibleTokenAddress),
+		}},
+		0,
+	)
+	require.NoError(t, err)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	// newAccount creates an account with the service key, and sets it up with the given transaction
+	newAccount := func(t *testing.T, setup string) flowgo.Address {
+		accountKey := b.ServiceKey().AccountKey()
+		accountKey.Weight = flowsdk.AccountKeyWeightThreshold
+
+		address, err := adapter.CreateAccount(context.Background(), []*flowsdk.AccountKey{accountKey}, nil, 0)
+		require.NoError(t, err)
+
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(fmt.Sprintf(`
+				import FungibleToken from %s
+				import FlowToken from %s
+				import ExampleToken from 0x%s
+
+				transaction {
+					prepare(account: AuthAccount) {
+						%s
+					}
+				}
+			`, fungibleTokenAddress, flowTokenAddress, tokenAddress.Hex(), setup))).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(address, 0, 0).
+			SetPayer(b.ServiceKey().Address).
+			AddAuthorizer(address)
+
+		err = tx.SignPayload(address, 0, signer)
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, results[0])
+
+		return flowgo.Address(address)
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		address := newAccount(t, ``)
+
+		vaults, err := b.CheckVaults(context.Background(), address)
+		require.NoError(t, err)
+
+		require.Len(t, vaults, 1)
+		assert.True(t, vaults[0].Healthy())
+		assert.Equal(t, flowTokenVaultType, vaults[0].Type)
+
+		_, result, err := b.RepairVaults(context.Background(), address)
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("broken FLOW links", func(t *testing.T) {
+		address := newAccount(t, `
+			account.unlink(/public/flowTokenReceiver)
+			account.unlink(/public/flowTokenBalance)
+			account.link<&FlowToken.Vault{FungibleToken.Provider}>(/public/flowTokenBalance, target: /storage/flowTokenVault)
+		`)
+
+		vaults, err := b.CheckVaults(context.Background(), address)
+		require.NoError(t, err)
+
+		assert.Equal(t, []emulator.Vault{
+			{
+				Path:     "/storage/flowTokenVault",
+				Type:     flowTokenVaultType,
+				Receiver: emulator.VaultLink{Path: "/public/flowTokenReceiver", Status: emulator.VaultLinkMissing},
+				Balance:  emulator.VaultLink{Path: "/public/flowTokenBalance", Status: emulator.VaultLinkBroken},
+			},
+		}, vaults)
+
+		vaults, result, err := b.RepairVaults(context.Background(), address)
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+
+		require.Len(t, vaults, 1)
+		assert.True(t, vaults[0].Healthy())
+		assert.Equal(t, "/public/flowTokenReceiver", vaults[0].Receiver.Path)
+		assert.Equal(t, "/public/flowTokenBalance", vaults[0].Balance.Path)
+	})
+
+	t.Run("missing FLOW vault", func(t *testing.T) {
+		address := newAccount(t, `
+			destroy account.load<@FlowToken.Vault>(from: /storage/flowTokenVault)
+		`)
+
+		vaults, err := b.CheckVaults(context.Background(), address)
+		require.NoError(t, err)
+
+		require.Len(t, vaults, 1)
+		assert.True(t, vaults[0].Missing)
+		assert.Equal(t, emulator.VaultLinkBroken, vaults[0].Receiver.Status)
+
+		vaults, result, err := b.RepairVaults(context.Background(), address)
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+
+		require.Len(t, vaults, 1)
+		assert.True(t, vaults[0].Healthy())
+	})
+
+	t.Run("broken token link", func(t *testing.T) {
+		address := newAccount(t, `
+			account.save(<- ExampleToken.createEmptyVault(), to: /storage/exampleTokenVault)
+			account.link<&ExampleToken.Vault{FungibleToken.Provider}>(/public/exampleTokenReceiver, target: /storage/exampleTokenVault)
+		`)
+
+		vaults, err := b.CheckVaults(context.Background(), address)
+		require.NoError(t, err)
+
+		require.Len(t, vaults, 2)
+		exampleTokenVault := vaults[0]
+		assert.Equal(t, "/storage/exampleTokenVault", exampleTokenVault.Path)
+		assert.Equal(t, fmt.Sprintf("A.%s.ExampleToken.Vault", tokenAddress.Hex()), exampleTokenVault.Type)
+		assert.Equal(t, emulator.VaultLink{Path: "/public/exampleTokenReceiver", Status: emulator.VaultLinkBroken}, exampleTokenVault.Receiver)
+		assert.Equal(t, emulator.VaultLink{Status: emulator.VaultLinkMissing}, exampleTokenVault.Balance)
+		assert.True(t, vaults[1].Healthy())
+
+		vaults, result, err := b.RepairVaults(context.Background(), address)
+		require.NoError(t, err)
+		AssertTransactionSucceeded(t, result)
+
+		// the balance capability has no known path to be linked at
+		assert.Equal(t, emulator.VaultLinkOK, vaults[0].Receiver.Status)
+		assert.Equal(t, emulator.VaultLinkMissing, vaults[0].Balance.Status)
+	})
+}
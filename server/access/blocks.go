@@ -0,0 +1,118 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/onflow/flow-emulator/adapters"
+)
+
+// The Flow Access API has no streaming endpoints yet, so the block stream is
+// served as an emulator specific service next to it, reusing the Access API messages.
+const (
+	blockStreamServiceName = "flow.emulator.BlockStreamAPI"
+	subscribeBlocksMethod  = "/" + blockStreamServiceName + "/SubscribeBlocks"
+)
+
+var blockStreamServiceDesc = grpc.ServiceDesc{
+	ServiceName: blockStreamServiceName,
+	HandlerType: (*blockStreamServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       subscribeBlocksHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+type blockStreamServer interface {
+	SubscribeBlocks(req *emptypb.Empty, stream grpc.ServerStream) error
+}
+
+func subscribeBlocksHandler(srv any, stream grpc.ServerStream) error {
+	req := new(emptypb.Empty)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	return srv.(blockStreamServer).SubscribeBlocks(req, stream)
+}
+
+// blockStreamHandler streams the headers of committed blocks.
+type blockStreamHandler struct {
+	adapter *adapters.AccessAdapter
+}
+
+func (h *blockStreamHandler) SubscribeBlocks(_ *emptypb.Empty, stream grpc.ServerStream) error {
+	return h.adapter.SubscribeBlocks(stream.Context(), func(header *flowgo.Header) error {
+		msg, err := convert.BlockHeaderToMessage(header, nil)
+		if err != nil {
+			return err
+		}
+
+		return stream.SendMsg(&accessproto.BlockHeaderResponse{
+			Block:       msg,
+			BlockStatus: entities.BlockStatus_BLOCK_SEALED,
+		})
+	})
+}
+
+// BlockSubscription receives the headers of the blocks committed by an emulator.
+type BlockSubscription struct {
+	stream grpc.ClientStream
+}
+
+// SubscribeBlocks subscribes to the blocks committed by the emulator served on conn,
+// from the next block on. The subscription ends when the context is done.
+func SubscribeBlocks(ctx context.Context, conn grpc.ClientConnInterface) (*BlockSubscription, error) {
+	stream, err := conn.NewStream(ctx, &blockStreamServiceDesc.Streams[0], subscribeBlocksMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.SendMsg(&emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.CloseSend()
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockSubscription{stream: stream}, nil
+}
+
+// Recv blocks until the next block is committed and returns its header.
+func (s *BlockSubscription) Recv() (*accessproto.BlockHeaderResponse, error) {
+	resp := new(accessproto.BlockHeaderResponse)
+	err := s.stream.RecvMsg(resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
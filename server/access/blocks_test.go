@@ -0,0 +1,98 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/access"
+)
+
+func TestSubscribeBlocks(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	server := access.NewGRPCServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false, math.MaxInt32, math.MaxInt32, false)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		server.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscription, err := access.SubscribeBlocks(ctx, conn)
+	require.NoError(t, err)
+
+	// wait until the server subscribed, blocks committed before are not streamed
+	require.Eventually(t, func() bool {
+		s, err := b.Status(context.Background())
+		return err == nil && s.BlockSubscriptions == 1
+	}, time.Second*5, time.Millisecond*10)
+
+	latest, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	// blocks committed faster than they are streamed are not missed
+	const count = 40
+	for i := 0; i < count; i++ {
+		_, err := b.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	for i := uint64(1); i <= count; i++ {
+		resp, err := subscription.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, latest.Header.Height+i, resp.Block.Height)
+		assert.Equal(t, entities.BlockStatus_BLOCK_SEALED, resp.BlockStatus)
+	}
+
+	// the subscription ends with the context
+	cancel()
+	_, err = subscription.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
@@ -0,0 +1,164 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/onflow/cadence/encoding/ccf"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/access"
+)
+
+func TestEventEncoding(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	_, err = adapters.NewSDKAdapter(&logger, b).CreateAccount(context.Background(), nil, nil, 0)
+	require.NoError(t, err)
+
+	server, err := access.NewRestServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false)
+	require.NoError(t, err)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	// payload returns the payload of the first event created with the account, as returned by the REST API
+	payload := func(t *testing.T, query string, header string) []byte {
+		req, err := http.NewRequest(
+			http.MethodGet,
+			fmt.Sprintf("http://%s/v1/events?type=flow.AccountCreated&start_height=0&end_height=1%s", server.Addr(), query),
+			nil,
+		)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set("X-Emulator-Event-Encoding-Version", header)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var blocks []struct {
+			Events []struct {
+				Payload string `json:"payload"`
+			} `json:"events"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&blocks)
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+		require.NotEmpty(t, blocks[1].Events)
+
+		decoded, err := base64.StdEncoding.DecodeString(blocks[1].Events[0].Payload)
+		require.NoError(t, err)
+
+		return decoded
+	}
+
+	t.Run("JSON-CDC by default", func(t *testing.T) {
+		_, err := jsoncdc.Decode(nil, payload(t, "", ""))
+		assert.NoError(t, err)
+
+		_, err = jsoncdc.Decode(nil, payload(t, "&event_encoding_version=JSON_CDC_V0", ""))
+		assert.NoError(t, err)
+	})
+
+	t.Run("CCF", func(t *testing.T) {
+		_, err := ccf.Decode(nil, payload(t, "&event_encoding_version=CCF_V0", ""))
+		assert.NoError(t, err)
+
+		_, err = ccf.Decode(nil, payload(t, "", "ccf_v0"))
+		assert.NoError(t, err)
+	})
+}
+
+func TestGRPCEventEncoding(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	_, err = adapters.NewSDKAdapter(&logger, b).CreateAccount(context.Background(), nil, nil, 0)
+	require.NoError(t, err)
+
+	server := access.NewGRPCServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false, math.MaxInt32, math.MaxInt32, false)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		server.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := accessproto.NewAccessAPIClient(conn)
+
+	payload := func(t *testing.T, md metadata.MD) []byte {
+		ctx := metadata.NewOutgoingContext(context.Background(), md)
+		resp, err := client.GetEventsForHeightRange(ctx, &accessproto.GetEventsForHeightRangeRequest{
+			Type:        "flow.AccountCreated",
+			StartHeight: 1,
+			EndHeight:   1,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 1)
+		require.NotEmpty(t, resp.Results[0].Events)
+
+		return resp.Results[0].Events[0].Payload
+	}
+
+	_, err = jsoncdc.Decode(nil, payload(t, metadata.MD{}))
+	assert.NoError(t, err)
+
+	_, err = ccf.Decode(nil, payload(t, metadata.Pairs("x-emulator-event-encoding-version", "CCF_V0")))
+	assert.NoError(t, err)
+}
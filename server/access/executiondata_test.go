@@ -0,0 +1,175 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/templates"
+	executiondataproto "github.com/onflow/flow/protobuf/go/flow/executiondata"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/access"
+)
+
+func TestExecutionDataAPI(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	server := access.NewGRPCServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false, math.MaxInt32, math.MaxInt32, false)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		server.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := executiondataproto.NewExecutionDataAPIClient(conn)
+
+	sdkAdapter := adapters.NewSDKAdapter(&logger, b)
+	contracts := []templates.Contract{{
+		Name:   "Token",
+		Source: `access(all) contract Token {}`,
+	}}
+	_, err = sdkAdapter.CreateAccount(context.Background(), nil, contracts, 0)
+	require.NoError(t, err)
+
+	// the account is created in the block before the latest, empty block
+	latest, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	block, err := b.GetBlockByHeight(context.Background(), latest.Header.Height-1)
+	require.NoError(t, err)
+	blockID := block.ID()
+
+	t.Run("get by block ID", func(t *testing.T) {
+		resp, err := client.GetExecutionDataByBlockID(
+			context.Background(),
+			&executiondataproto.GetExecutionDataByBlockIDRequest{BlockId: blockID[:]},
+		)
+		require.NoError(t, err)
+
+		data := resp.BlockExecutionData
+		assert.Equal(t, blockID[:], data.BlockId)
+		require.Len(t, data.ChunkExecutionData, 2)
+		assert.Len(t, data.ChunkExecutionData[0].Collection.Transactions, 1)
+		assert.NotEmpty(t, data.ChunkExecutionData[0].Events)
+		assert.NotEmpty(t, data.ChunkExecutionData[1].TrieUpdate.Payloads)
+	})
+
+	t.Run("get unknown block", func(t *testing.T) {
+		unknownID := make([]byte, 32)
+		unknownID[0] = 1
+
+		_, err := client.GetExecutionDataByBlockID(
+			context.Background(),
+			&executiondataproto.GetExecutionDataByBlockIDRequest{BlockId: unknownID},
+		)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("subscribe execution data", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		subscription, err := client.SubscribeExecutionData(ctx, &executiondataproto.SubscribeExecutionDataRequest{
+			StartBlockId: blockID[:],
+		})
+		require.NoError(t, err)
+
+		// blocks already committed are streamed first
+		resp, err := subscription.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, block.Header.Height, resp.BlockHeight)
+		assert.Equal(t, blockID[:], resp.BlockExecutionData.BlockId)
+
+		resp, err = subscription.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, latest.Header.Height, resp.BlockHeight)
+
+		committed, err := b.CommitBlock()
+		require.NoError(t, err)
+
+		resp, err = subscription.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, committed.Header.Height, resp.BlockHeight)
+
+		// the subscription ends with the context
+		cancel()
+		_, err = subscription.Recv()
+		assert.Equal(t, codes.Canceled, status.Code(err))
+	})
+
+	t.Run("subscribe events", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		subscription, err := client.SubscribeEvents(ctx, &executiondataproto.SubscribeEventsRequest{
+			StartBlockHeight: block.Header.Height,
+			Filter: &executiondataproto.EventFilter{
+				EventType: []string{"flow.AccountCreated"},
+			},
+		})
+		require.NoError(t, err)
+
+		resp, err := subscription.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, block.Header.Height, resp.BlockHeight)
+		require.Len(t, resp.Events, 1)
+		assert.Equal(t, "flow.AccountCreated", resp.Events[0].Type)
+
+		// blocks without matching events are streamed without events
+		resp, err = subscription.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, latest.Header.Height, resp.BlockHeight)
+		assert.Empty(t, resp.Events)
+	})
+
+	t.Run("subscribe with start block ID and height", func(t *testing.T) {
+		subscription, err := client.SubscribeExecutionData(context.Background(), &executiondataproto.SubscribeExecutionDataRequest{
+			StartBlockId:     blockID[:],
+			StartBlockHeight: block.Header.Height,
+		})
+		require.NoError(t, err)
+
+		_, err = subscription.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -19,6 +19,7 @@
 package access
 
 import (
+	"context"
 	"fmt"
 	"net"
 
@@ -28,13 +29,20 @@ import (
 	grpcprometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/onflow/flow-go/access"
 	legacyaccess "github.com/onflow/flow-go/access/legacy"
+	"github.com/onflow/flow-go/engine/access/state_stream"
 	"github.com/onflow/flow-go/model/flow"
 	flowgo "github.com/onflow/flow-go/model/flow"
 	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	executiondataproto "github.com/onflow/flow/protobuf/go/flow/executiondata"
 	legacyaccessproto "github.com/onflow/flow/protobuf/go/flow/legacy/access"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/runtime/protoimpl"
 )
 
 type mockHeaderCache struct {
@@ -52,10 +60,44 @@ type GRPCServer struct {
 	listener   net.Listener
 }
 
-func NewGRPCServer(logger *zerolog.Logger, adapter *adapters.AccessAdapter, chain flow.Chain, host string, port int, debug bool) *GRPCServer {
+// NewGRPCServer returns a server of the Access API.
+//
+// Requests larger than maxRecvMsgSize bytes are rejected, and so are responses larger
+// than maxSendMsgSize bytes. With sizeErrors, every response is measured, so those
+// exceeding the limit fail with an error suggesting to request less data at once.
+func NewGRPCServer(
+	logger *zerolog.Logger,
+	adapter *adapters.AccessAdapter,
+	chain flow.Chain,
+	host string,
+	port int,
+	debug bool,
+	maxRecvMsgSize int,
+	maxSendMsgSize int,
+	sizeErrors bool,
+) *GRPCServer {
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		grpcprometheus.StreamServerInterceptor,
+		streamRequestIDInterceptor,
+		streamSessionInterceptor,
+	}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpcprometheus.UnaryServerInterceptor,
+		unaryRequestIDInterceptor,
+		unarySessionInterceptor,
+	}
+	// measuring responses costs a pass over every response,
+	// so it is only done when the actionable errors are requested
+	if sizeErrors {
+		streamInterceptors = append(streamInterceptors, streamMessageSizeInterceptor(maxSendMsgSize))
+		unaryInterceptors = append(unaryInterceptors, unaryMessageSizeInterceptor(maxSendMsgSize))
+	}
+
 	grpcServer := grpc.NewServer(
-		grpc.StreamInterceptor(grpcprometheus.StreamServerInterceptor),
-		grpc.UnaryInterceptor(grpcprometheus.UnaryServerInterceptor),
+		grpc.MaxRecvMsgSize(maxRecvMsgSize),
+		grpc.MaxSendMsgSize(maxSendMsgSize),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
 	)
 
 	//TODO: bluesign: clean this up
@@ -64,6 +106,14 @@ func NewGRPCServer(logger *zerolog.Logger, adapter *adapters.AccessAdapter, chai
 
 	legacyaccessproto.RegisterAccessAPIServer(grpcServer, legacyaccess.NewHandler(adapter, chain))
 	accessproto.RegisterAccessAPIServer(grpcServer, access.NewHandler(adapter, chain, mockHeaderCache{}, me))
+	grpcServer.RegisterService(&blockStreamServiceDesc, &blockStreamHandler{adapter: adapter})
+	grpcServer.RegisterService(&registersServiceDesc, &registersGRPCHandler{adapter: adapter})
+	executiondataproto.RegisterExecutionDataAPIServer(grpcServer, state_stream.NewHandler(
+		adapter,
+		chain,
+		state_stream.DefaultEventFilterConfig,
+		state_stream.DefaultMaxGlobalStreams,
+	))
 
 	grpcprometheus.Register(grpcServer)
 
@@ -89,9 +139,25 @@ func (g *GRPCServer) Listen() error {
 		return err
 	}
 	g.listener = lis
+	// the port may have been assigned by the operating system
+	g.port = lis.Addr().(*net.TCPAddr).Port
 	return nil
 }
 
+// Addr returns the address the server listens on, or nil if it is not listening yet.
+func (g *GRPCServer) Addr() net.Addr {
+	if g.listener == nil {
+		return nil
+	}
+	return g.listener.Addr()
+}
+
+// Port returns the port the server listens on. Once listening, a port configured
+// as zero is the one assigned by the operating system.
+func (g *GRPCServer) Port() int {
+	return g.port
+}
+
 func (g *GRPCServer) Start() error {
 	if g.listener == nil {
 		if err := g.Listen(); err != nil {
@@ -112,3 +178,83 @@ func (g *GRPCServer) Start() error {
 func (g *GRPCServer) Stop() {
 	g.grpcServer.GracefulStop()
 }
+
+// unaryMessageSizeInterceptor replaces responses larger than maxSize bytes with an
+// actionable error. Otherwise, the client receives a generic transport error.
+func unaryMessageSizeInterceptor(maxSize int) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkMessageSize(info.FullMethod, resp, maxSize); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// streamMessageSizeInterceptor fails streams with an actionable error when a message
+// larger than maxSize bytes is sent.
+func streamMessageSizeInterceptor(maxSize int) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &sizeCheckingStream{
+			ServerStream: stream,
+			method:       info.FullMethod,
+			maxSize:      maxSize,
+		})
+	}
+}
+
+type sizeCheckingStream struct {
+	grpc.ServerStream
+	method  string
+	maxSize int
+}
+
+func (s *sizeCheckingStream) SendMsg(m any) error {
+	if err := checkMessageSize(s.method, m, s.maxSize); err != nil {
+		return err
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// messageSize returns the encoded size of the given message, if it is a protobuf message.
+func messageSize(m any) (int, bool) {
+	switch m := m.(type) {
+	case proto.Message:
+		return proto.Size(m), true
+	case protoiface.MessageV1:
+		// the Access API messages are generated by an older protoc-gen-go
+		return proto.Size(protoimpl.X.ProtoMessageV2Of(m)), true
+	default:
+		return 0, false
+	}
+}
+
+func checkMessageSize(method string, m any, maxSize int) error {
+	size, ok := messageSize(m)
+	if !ok || size <= maxSize {
+		return nil
+	}
+
+	return status.Errorf(
+		codes.ResourceExhausted,
+		"response of %s is %d bytes, exceeding the maximum message size of %d bytes: "+
+			"request less data at once, e.g. events of a smaller block height range, "+
+			"or raise the limit with --grpc-max-send-msg-size (clients may need to raise their receive limit too)",
+		method,
+		size,
+		maxSize,
+	)
+}
@@ -0,0 +1,285 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/access"
+)
+
+func TestGRPCDeadlineInterruptsScript(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithScriptGasLimit(math.MaxUint64),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	// reserve a free port for the server
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := lis.Addr().(*net.TCPAddr).Port
+	require.NoError(t, lis.Close())
+
+	server := access.NewGRPCServer(&logger, adapter, b.GetChain(), "127.0.0.1", port, false, math.MaxInt32, math.MaxInt32, false)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		fmt.Sprintf("127.0.0.1:%d", port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := accessproto.NewAccessAPIClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err = client.ExecuteScriptAtLatestBlock(ctx, &accessproto.ExecuteScriptAtLatestBlockRequest{
+		Script: []byte(`
+			pub fun main() {
+				while true {}
+			}
+		`),
+	})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	// the script holds the emulator's read lock while it runs, so committing a
+	// block only succeeds once the server has aborted the script
+	committed := make(chan error, 1)
+	go func() {
+		_, err := b.CommitBlock()
+		committed <- err
+	}()
+
+	select {
+	case err := <-committed:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("script was not interrupted after the client deadline passed")
+	}
+}
+
+func TestGRPCMessageSizeLimits(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	server := access.NewGRPCServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false, 1024, 64, true)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		server.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := accessproto.NewAccessAPIClient(conn)
+
+	t.Run("request too large", func(t *testing.T) {
+		_, err := client.ExecuteScriptAtLatestBlock(context.Background(), &accessproto.ExecuteScriptAtLatestBlockRequest{
+			Script: []byte(strings.Repeat(" ", 2048)),
+		})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("response too large", func(t *testing.T) {
+		_, err := client.GetLatestBlock(context.Background(), &accessproto.GetLatestBlockRequest{})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), "--grpc-max-send-msg-size")
+	})
+
+	t.Run("response within limit", func(t *testing.T) {
+		_, err := client.Ping(context.Background(), &accessproto.PingRequest{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("response too large without size errors", func(t *testing.T) {
+		server := access.NewGRPCServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false, 1024, 64, false)
+		require.NoError(t, server.Listen())
+		go func() {
+			_ = server.Start()
+		}()
+		defer server.Stop()
+
+		conn, err := grpc.Dial(
+			server.Addr().String(),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// responses are not measured, gRPC still rejects them
+		_, err = accessproto.NewAccessAPIClient(conn).GetLatestBlock(context.Background(), &accessproto.GetLatestBlockRequest{})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.NotContains(t, status.Convert(err).Message(), "--grpc-max-send-msg-size")
+	})
+}
+
+func TestGRPCRequestID(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	var logs strings.Builder
+	logger := zerolog.New(&syncWriter{w: &logs})
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	server := access.NewGRPCServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false, math.MaxInt32, math.MaxInt32, false)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		server.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := accessproto.NewAccessAPIClient(conn)
+
+	getLatestBlock := func(t *testing.T, md metadata.MD) string {
+		var header metadata.MD
+		ctx := metadata.NewOutgoingContext(context.Background(), md)
+		_, err := client.GetLatestBlock(ctx, &accessproto.GetLatestBlockRequest{}, grpc.Header(&header))
+		require.NoError(t, err)
+		values := header.Get("x-request-id")
+		require.Len(t, values, 1)
+		return values[0]
+	}
+
+	t.Run("request ID", func(t *testing.T) {
+		requestID := getLatestBlock(t, metadata.Pairs("x-request-id", "my-request"))
+		assert.Equal(t, "my-request", requestID)
+		assert.Contains(t, logs.String(), `"requestID":"my-request"`)
+	})
+
+	t.Run("trace parent", func(t *testing.T) {
+		requestID := getLatestBlock(t, metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", requestID)
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		first := getLatestBlock(t, metadata.MD{})
+		second := getLatestBlock(t, metadata.MD{})
+		assert.NotEmpty(t, first)
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestGRPCTimeTravelSession(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := b.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+	adapter.Sessions().Set("alice", 1)
+
+	server := access.NewGRPCServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false, math.MaxInt32, math.MaxInt32, false)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		server.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := accessproto.NewAccessAPIClient(conn)
+
+	latestHeight := func(t *testing.T, md metadata.MD) uint64 {
+		ctx := metadata.NewOutgoingContext(context.Background(), md)
+		resp, err := client.GetLatestBlockHeader(ctx, &accessproto.GetLatestBlockHeaderRequest{})
+		require.NoError(t, err)
+		return resp.Block.Height
+	}
+
+	assert.Equal(t, uint64(1), latestHeight(t, metadata.Pairs("x-emulator-session", "alice")))
+	assert.Equal(t, uint64(2), latestHeight(t, metadata.Pairs("x-emulator-session", "bob")))
+	assert.Equal(t, uint64(2), latestHeight(t, metadata.MD{}))
+}
+
+// syncWriter serializes the writes of the concurrently served requests.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
@@ -0,0 +1,92 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-emulator/adapters"
+)
+
+const (
+	transactionsPathPrefix = "/v1/transactions/"
+	transactionLogsSuffix  = "/logs"
+)
+
+// TransactionLogs is the response of the transaction logs route.
+type TransactionLogs struct {
+	TransactionID string   `json:"transaction_id"`
+	Logs          []string `json:"logs"`
+}
+
+// transactionLogsHandler serves the Cadence logs of committed transactions at
+// /v1/transactions/{id}/logs, next to the transaction routes of the Access API,
+// which serves all other paths.
+type transactionLogsHandler struct {
+	adapter *adapters.AccessAdapter
+	next    http.Handler
+}
+
+func (h *transactionLogsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, transactionsPathPrefix)
+	id := strings.TrimSuffix(path, transactionLogsSuffix)
+	if id == path || r.Method != http.MethodGet || strings.Contains(id, "/") {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	txID, err := flowgo.HexStringToIdentifier(id)
+	if err != nil {
+		writeRestError(w, http.StatusBadRequest, "invalid transaction ID")
+		return
+	}
+
+	logs, err := h.adapter.GetTransactionLogs(r.Context(), txID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			writeRestError(w, http.StatusNotFound, status.Convert(err).Message())
+		} else {
+			writeRestError(w, http.StatusInternalServerError, status.Convert(err).Message())
+		}
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(TransactionLogs{
+		TransactionID: txID.String(),
+		Logs:          logs,
+	})
+}
+
+// writeRestError writes an error in the format of the Access API.
+func writeRestError(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(models.ModelError{
+		Code:    int32(code),
+		Message: message,
+	})
+}
@@ -0,0 +1,108 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/access"
+)
+
+func TestTransactionLogs(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	server, err := access.NewRestServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false)
+	require.NoError(t, err)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	serviceKey := b.ServiceKey()
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(`transaction { execute { log("hello"); log(42) } }`)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(serviceKey.Address, serviceKey.Index, serviceKey.SequenceNumber).
+		SetPayer(serviceKey.Address)
+
+	signer, err := serviceKey.Signer()
+	require.NoError(t, err)
+	require.NoError(t, tx.SignEnvelope(serviceKey.Address, serviceKey.Index, signer))
+
+	flowTx := convert.SDKTransactionToFlow(*tx)
+	require.NoError(t, b.AddTransaction(context.Background(), *flowTx))
+	_, _, err = b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+
+	get := func(t *testing.T, path string) *http.Response {
+		resp, err := http.Get(fmt.Sprintf("http://%s%s", server.Addr(), path))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("logs", func(t *testing.T) {
+		resp := get(t, "/v1/transactions/"+flowTx.ID().String()+"/logs")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var logs access.TransactionLogs
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&logs))
+		assert.Equal(t, flowTx.ID().String(), logs.TransactionID)
+		assert.Equal(t, []string{`"hello"`, "42"}, logs.Logs)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		resp := get(t, "/v1/transactions/"+flowgo.ZeroID.String()+"/logs")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("malformed ID", func(t *testing.T) {
+		resp := get(t, "/v1/transactions/nothex/logs")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("other transaction routes", func(t *testing.T) {
+		resp := get(t, "/v1/transactions/"+flowTx.ID().String())
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
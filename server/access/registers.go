@@ -0,0 +1,263 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	executionproto "github.com/onflow/flow/protobuf/go/flow/execution"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-emulator/adapters"
+)
+
+const registersPath = "/v1/registers"
+
+// The Flow Access API has no method for raw registers, so they are served over gRPC
+// as an emulator specific service, reusing the Execution API register messages.
+const (
+	registersServiceName     = "flow.emulator.RegistersAPI"
+	getRegisterAtBlockMethod = "/" + registersServiceName + "/GetRegisterAtBlockID"
+)
+
+var registersServiceDesc = grpc.ServiceDesc{
+	ServiceName: registersServiceName,
+	HandlerType: (*registersServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRegisterAtBlockID",
+			Handler:    getRegisterAtBlockIDHandler,
+		},
+	},
+}
+
+type registersServer interface {
+	GetRegisterAtBlockID(
+		ctx context.Context,
+		req *executionproto.GetRegisterAtBlockIDRequest,
+	) (*executionproto.GetRegisterAtBlockIDResponse, error)
+}
+
+func getRegisterAtBlockIDHandler(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	req := new(executionproto.GetRegisterAtBlockIDRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(registersServer).GetRegisterAtBlockID(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: getRegisterAtBlockMethod,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(registersServer).GetRegisterAtBlockID(ctx, req.(*executionproto.GetRegisterAtBlockIDRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+// registersGRPCHandler serves raw register values over gRPC. A register is read
+// at the block of the request, or the latest block if no block ID is given.
+type registersGRPCHandler struct {
+	adapter *adapters.AccessAdapter
+}
+
+func (h *registersGRPCHandler) GetRegisterAtBlockID(
+	ctx context.Context,
+	req *executionproto.GetRegisterAtBlockIDRequest,
+) (*executionproto.GetRegisterAtBlockIDResponse, error) {
+	if len(req.GetRegisterKey()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "register key is required")
+	}
+
+	var block *flowgo.Block
+	var err error
+	if len(req.GetBlockId()) == 0 {
+		block, _, err = h.adapter.GetLatestBlock(ctx, true)
+	} else {
+		if len(req.GetBlockId()) != flowgo.IdentifierLen {
+			return nil, status.Error(codes.InvalidArgument, "invalid block ID")
+		}
+		block, _, err = h.adapter.GetBlockByID(ctx, flowgo.HashToID(req.GetBlockId()))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	registerID := flowgo.NewRegisterID(string(req.GetRegisterOwner()), string(req.GetRegisterKey()))
+	values, err := h.adapter.GetRegisterValues(ctx, flowgo.RegisterIDs{registerID}, block.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &executionproto.GetRegisterAtBlockIDResponse{
+		Value: values[0],
+	}, nil
+}
+
+// GetRegisterAtBlockID returns the raw value of a register of the emulator served on conn,
+// at the given block, or the latest block if blockID is the zero ID.
+func GetRegisterAtBlockID(
+	ctx context.Context,
+	conn grpc.ClientConnInterface,
+	blockID flowgo.Identifier,
+	registerID flowgo.RegisterID,
+) (flowgo.RegisterValue, error) {
+	req := &executionproto.GetRegisterAtBlockIDRequest{
+		RegisterOwner: []byte(registerID.Owner),
+		RegisterKey:   []byte(registerID.Key),
+	}
+	if blockID != flowgo.ZeroID {
+		req.BlockId = blockID[:]
+	}
+
+	resp := new(executionproto.GetRegisterAtBlockIDResponse)
+	err := conn.Invoke(ctx, getRegisterAtBlockMethod, req, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetValue(), nil
+}
+
+// RegisterValue is a raw register value returned by the registers route.
+// The owner and key are hex encoded, the value is base64 encoded.
+type RegisterValue struct {
+	Owner string `json:"owner"`
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// RegisterValues is the response of the registers route.
+type RegisterValues struct {
+	Height    uint64          `json:"height,string"`
+	Registers []RegisterValue `json:"registers"`
+}
+
+// registersHandler serves raw register values at /v1/registers. Registers are
+// given as repeated id query parameters in the form <owner hex>.<key hex>, read
+// at the block height of the height query parameter, or the latest block.
+type registersHandler struct {
+	adapter *adapters.AccessAdapter
+}
+
+func (h *registersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		writeRestError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+
+	ids := query["id"]
+	if len(ids) == 0 {
+		writeRestError(w, http.StatusBadRequest, "at least one register ID is required")
+		return
+	}
+
+	registerIDs := make(flowgo.RegisterIDs, len(ids))
+	for i, id := range ids {
+		registerID, err := parseRegisterID(id)
+		if err != nil {
+			writeRestError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		registerIDs[i] = registerID
+	}
+
+	var height uint64
+	if value := query.Get("height"); value != "" {
+		var err error
+		height, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeRestError(w, http.StatusBadRequest, "invalid height")
+			return
+		}
+	} else {
+		block, _, err := h.adapter.GetLatestBlock(r.Context(), true)
+		if err != nil {
+			writeRestError(w, http.StatusInternalServerError, status.Convert(err).Message())
+			return
+		}
+		height = block.Header.Height
+	}
+
+	values, err := h.adapter.GetRegisterValues(r.Context(), registerIDs, height)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			writeRestError(w, http.StatusNotFound, status.Convert(err).Message())
+		case codes.InvalidArgument:
+			writeRestError(w, http.StatusBadRequest, status.Convert(err).Message())
+		default:
+			writeRestError(w, http.StatusInternalServerError, status.Convert(err).Message())
+		}
+		return
+	}
+
+	registers := make([]RegisterValue, len(values))
+	for i, value := range values {
+		registers[i] = RegisterValue{
+			Owner: hex.EncodeToString([]byte(registerIDs[i].Owner)),
+			Key:   hex.EncodeToString([]byte(registerIDs[i].Key)),
+			Value: value,
+		}
+	}
+
+	_ = json.NewEncoder(w).Encode(RegisterValues{
+		Height:    height,
+		Registers: registers,
+	})
+}
+
+// parseRegisterID parses a register ID in the form <owner hex>.<key hex>.
+// The owner is empty for global registers.
+func parseRegisterID(id string) (flowgo.RegisterID, error) {
+	owner, key, ok := strings.Cut(id, ".")
+	if !ok {
+		return flowgo.RegisterID{}, fmt.Errorf("invalid register ID %q: expected <owner>.<key>", id)
+	}
+
+	ownerBytes, err := hex.DecodeString(owner)
+	if err != nil {
+		return flowgo.RegisterID{}, fmt.Errorf("invalid register ID %q: owner is not hex encoded", id)
+	}
+
+	keyBytes, err := hex.DecodeString(key)
+	if err != nil || len(keyBytes) == 0 {
+		return flowgo.RegisterID{}, fmt.Errorf("invalid register ID %q: key is not hex encoded", id)
+	}
+
+	return flowgo.NewRegisterID(string(ownerBytes), string(keyBytes)), nil
+}
@@ -0,0 +1,178 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/access"
+)
+
+func TestRegisters(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	server, err := access.NewRestServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false)
+	require.NoError(t, err)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	serviceAddress := b.ServiceKey().Address
+	owner := hex.EncodeToString(serviceAddress.Bytes())
+	statusKey := hex.EncodeToString([]byte(flowgo.AccountStatusKey))
+
+	expected, err := b.GetRegisterValues(
+		context.Background(),
+		flowgo.RegisterIDs{flowgo.AccountStatusRegisterID(flowgo.Address(serviceAddress))},
+		0,
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, expected[0])
+
+	get := func(t *testing.T, path string) *http.Response {
+		resp, err := http.Get(fmt.Sprintf("http://%s%s", server.Addr(), path))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("registers", func(t *testing.T) {
+		resp := get(t, "/v1/registers?height=0&id="+owner+"."+statusKey+"&id="+owner+".00")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var registers access.RegisterValues
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&registers))
+		assert.Equal(t, uint64(0), registers.Height)
+		require.Len(t, registers.Registers, 2)
+		assert.Equal(t, access.RegisterValue{Owner: owner, Key: statusKey, Value: expected[0]}, registers.Registers[0])
+		assert.Empty(t, registers.Registers[1].Value)
+	})
+
+	t.Run("latest height", func(t *testing.T) {
+		resp := get(t, "/v1/registers?id="+owner+"."+statusKey)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("unknown height", func(t *testing.T) {
+		resp := get(t, "/v1/registers?height=100&id="+owner+"."+statusKey)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("malformed ID", func(t *testing.T) {
+		resp := get(t, "/v1/registers?id=nothex")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("no IDs", func(t *testing.T) {
+		resp := get(t, "/v1/registers")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestRegistersGRPC(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	server := access.NewGRPCServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false, math.MaxInt32, math.MaxInt32, false)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		server.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	statusID := flowgo.AccountStatusRegisterID(flowgo.Address(b.ServiceKey().Address))
+
+	expected, err := b.GetRegisterValues(context.Background(), flowgo.RegisterIDs{statusID}, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, expected[0])
+
+	genesis, err := b.GetBlockByHeight(context.Background(), 0)
+	require.NoError(t, err)
+
+	t.Run("register", func(t *testing.T) {
+		value, err := access.GetRegisterAtBlockID(context.Background(), conn, genesis.ID(), statusID)
+		require.NoError(t, err)
+		assert.Equal(t, expected[0], value)
+	})
+
+	t.Run("latest block", func(t *testing.T) {
+		value, err := access.GetRegisterAtBlockID(context.Background(), conn, flowgo.ZeroID, statusID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, value)
+	})
+
+	t.Run("missing register", func(t *testing.T) {
+		value, err := access.GetRegisterAtBlockID(
+			context.Background(),
+			conn,
+			genesis.ID(),
+			flowgo.NewRegisterID(statusID.Owner, "missing"),
+		)
+		require.NoError(t, err)
+		assert.Empty(t, value)
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+		_, err := access.GetRegisterAtBlockID(context.Background(), conn, flowgo.Identifier{1}, statusID)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
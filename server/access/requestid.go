@@ -0,0 +1,79 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-emulator/utils/requestid"
+)
+
+// requestIDFromMetadata identifies the request with the given incoming metadata.
+func requestIDFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return requestid.FromHeaders(func(name string) string {
+		values := md.Get(strings.ToLower(name))
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	})
+}
+
+// unaryRequestIDInterceptor identifies each request in its context and in the
+// response header, so that the logs of the request can be correlated.
+func unaryRequestIDInterceptor(
+	ctx context.Context,
+	req any,
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	requestID := requestIDFromMetadata(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(requestid.Header), requestID))
+	return handler(requestid.WithContext(ctx, requestID), req)
+}
+
+// streamRequestIDInterceptor identifies each stream in its context and in the
+// response header.
+func streamRequestIDInterceptor(
+	srv any,
+	stream grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	requestID := requestIDFromMetadata(stream.Context())
+	_ = stream.SetHeader(metadata.Pairs(strings.ToLower(requestid.Header), requestID))
+	return handler(srv, &requestIDStream{
+		ServerStream: stream,
+		ctx:          requestid.WithContext(stream.Context(), requestID),
+	})
+}
+
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDStream) Context() context.Context {
+	return s.ctx
+}
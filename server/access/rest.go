@@ -20,6 +20,7 @@ package access
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/onflow/flow-go/module"
@@ -28,6 +29,10 @@ import (
 	"os"
 
 	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/utils/eventencoding"
+	"github.com/onflow/flow-emulator/utils/pending"
+	"github.com/onflow/flow-emulator/utils/requestid"
+	"github.com/onflow/flow-emulator/utils/timetravel"
 	metricsProm "github.com/slok/go-http-metrics/metrics/prometheus"
 
 	"github.com/onflow/flow-go/engine/access/rest"
@@ -50,9 +55,25 @@ func (r *RestServer) Listen() error {
 		return err
 	}
 	r.listener = l
+	// the port may have been assigned by the operating system
+	r.port = l.Addr().(*net.TCPAddr).Port
 	return nil
 }
 
+// Addr returns the address the server listens on, or nil if it is not listening yet.
+func (r *RestServer) Addr() net.Addr {
+	if r.listener == nil {
+		return nil
+	}
+	return r.listener.Addr()
+}
+
+// Port returns the port the server listens on. Once listening, a port configured
+// as zero is the one assigned by the operating system.
+func (r *RestServer) Port() int {
+	return r.port
+}
+
 func (r *RestServer) Start() error {
 	if r.listener == nil {
 		if err := r.Listen(); err != nil {
@@ -65,6 +86,9 @@ func (r *RestServer) Start() error {
 		Msgf("✅  Started REST API server on port %d", r.port)
 
 	err := r.server.Serve(r.listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
@@ -94,6 +118,14 @@ func NewRestServer(logger *zerolog.Logger, adapter *adapters.AccessAdapter, chai
 		return nil, err
 	}
 
+	// serve the events WebSocket, the transaction logs and the registers next to the Access API
+	mux := http.NewServeMux()
+	mux.Handle(EventsWebSocketPath, &eventsWebSocketHandler{logger: logger, adapter: adapter})
+	mux.Handle(transactionsPathPrefix, &transactionLogsHandler{adapter: adapter, next: srv.Handler})
+	mux.Handle(registersPath, &registersHandler{adapter: adapter})
+	mux.Handle("/", srv.Handler)
+	srv.Handler = requestid.Handler(timetravel.Handler(pending.Handler(eventencoding.Handler(mux))))
+
 	return &RestServer{
 		logger: logger,
 		host:   host,
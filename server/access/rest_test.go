@@ -16,3 +16,114 @@
  * limitations under the License.
  */
 package access_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/access"
+)
+
+func TestPendingQueries(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	server, err := access.NewRestServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false)
+	require.NoError(t, err)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	serviceKey := b.ServiceKey()
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(`
+			transaction {
+				prepare(signer: AuthAccount) {
+					signer.contracts.add(name: "Pending", code: "pub contract Pending {}".utf8)
+				}
+			}
+		`)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(serviceKey.Address, serviceKey.Index, serviceKey.SequenceNumber).
+		SetPayer(serviceKey.Address).
+		AddAuthorizer(serviceKey.Address)
+
+	signer, err := serviceKey.Signer()
+	require.NoError(t, err)
+	require.NoError(t, tx.SignEnvelope(serviceKey.Address, serviceKey.Index, signer))
+
+	// the transaction is added to the pending block, which is not committed
+	require.NoError(t, b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx)))
+
+	url := func(path string, pending bool) string {
+		return fmt.Sprintf("http://%s%s?pending=%t", server.Addr(), path, pending)
+	}
+
+	t.Run("accounts", func(t *testing.T) {
+		contracts := func(t *testing.T, pending bool) map[string]string {
+			resp, err := http.Get(url("/v1/accounts/"+serviceKey.Address.Hex(), pending) + "&expand=contracts")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var account struct {
+				Contracts map[string]string `json:"contracts"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&account))
+			return account.Contracts
+		}
+
+		assert.NotContains(t, contracts(t, false), "Pending")
+		assert.Contains(t, contracts(t, true), "Pending")
+	})
+
+	t.Run("scripts", func(t *testing.T) {
+		script := fmt.Sprintf(
+			`pub fun main(): Bool { return getAccount(0x%s).contracts.get(name: "Pending") != nil }`,
+			serviceKey.Address.Hex(),
+		)
+		body, err := json.Marshal(map[string]any{
+			"script":    base64.StdEncoding.EncodeToString([]byte(script)),
+			"arguments": []string{},
+		})
+		require.NoError(t, err)
+
+		execute := func(t *testing.T, pending bool) string {
+			resp, err := http.Post(url("/v1/scripts", pending), "application/json", bytes.NewReader(body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var encoded string
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&encoded))
+			value, err := base64.StdEncoding.DecodeString(encoded)
+			require.NoError(t, err)
+			return string(value)
+		}
+
+		assert.JSONEq(t, `{"type":"Bool","value":false}`, execute(t, false))
+		assert.JSONEq(t, `{"type":"Bool","value":true}`, execute(t, true))
+	})
+}
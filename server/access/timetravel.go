@@ -0,0 +1,93 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-emulator/utils/eventencoding"
+	"github.com/onflow/flow-emulator/utils/pending"
+	"github.com/onflow/flow-emulator/utils/timetravel"
+)
+
+// sessionContext returns a copy of the context carrying the session ID of its
+// incoming metadata, if any, whether the request queries the pending state,
+// and the event encoding it chose.
+func sessionContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	header := func(name string) string {
+		values := md.Get(strings.ToLower(name))
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+
+	if pending.FromHeaders(header) {
+		ctx = pending.WithContext(ctx)
+	}
+
+	if version, ok := eventencoding.FromHeaders(header); ok {
+		ctx = eventencoding.WithContext(ctx, version)
+	}
+
+	session := timetravel.FromHeaders(header)
+	if session == "" {
+		return ctx
+	}
+	return timetravel.WithContext(ctx, session)
+}
+
+// unarySessionInterceptor carries the session of each request in its context,
+// so that queries can be served at the height chosen by the session, or
+// against the pending state.
+func unarySessionInterceptor(
+	ctx context.Context,
+	req any,
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	return handler(sessionContext(ctx), req)
+}
+
+// streamSessionInterceptor carries the session of each stream in its context.
+func streamSessionInterceptor(
+	srv any,
+	stream grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	return handler(srv, &sessionStream{
+		ServerStream: stream,
+		ctx:          sessionContext(stream.Context()),
+	})
+}
+
+type sessionStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *sessionStream) Context() context.Context {
+	return s.ctx
+}
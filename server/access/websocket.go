@@ -0,0 +1,186 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+
+	"github.com/onflow/flow-emulator/adapters"
+)
+
+const (
+	EventsWebSocketPath = "/v1/ws/events"
+
+	defaultHeartbeatInterval = 10 * time.Second
+)
+
+// EventsMessage is a message sent over the events WebSocket.
+//
+// Messages of type "events" contain the events of a block. Messages of type
+// "heartbeat" are sent when no events were sent for the heartbeat interval,
+// and report the height of the latest block processed. Clients resume a
+// subscription after this height.
+type EventsMessage struct {
+	Type string `json:"type"`
+	*models.BlockEvents
+}
+
+// eventsWebSocketHandler streams the events of committed blocks over WebSockets.
+type eventsWebSocketHandler struct {
+	logger  *zerolog.Logger
+	adapter *adapters.AccessAdapter
+}
+
+func (h *eventsWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	eventTypes := query["type"]
+	if len(eventTypes) == 0 {
+		http.Error(w, "at least one event type is required", http.StatusBadRequest)
+		return
+	}
+
+	var startHeight *uint64
+	if value := query.Get("start_height"); value != "" {
+		height, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid start height", http.StatusBadRequest)
+			return
+		}
+		startHeight = &height
+	}
+
+	heartbeatInterval := defaultHeartbeatInterval
+	if value := query.Get("heartbeat_interval"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			http.Error(w, "invalid heartbeat interval", http.StatusBadRequest)
+			return
+		}
+		heartbeatInterval = interval
+	}
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		// frontends of dapps in development are served from other origins
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return
+	}
+	defer conn.Close(websocket.StatusInternalError, "")
+
+	// the client does not send messages, reading only detects when it closes
+	ctx, cancel := context.WithCancel(conn.CloseRead(r.Context()))
+	defer cancel()
+
+	// without a start height, the events of the blocks committed from now on are sent
+	if startHeight == nil {
+		latest, _, err := h.adapter.GetLatestBlockHeader(ctx, true)
+		if err != nil {
+			_ = conn.Close(websocket.StatusInternalError, "failed to get latest block")
+			return
+		}
+		next := latest.Height + 1
+		startHeight = &next
+	}
+
+	var lastHeight atomic.Uint64
+	if *startHeight > 0 {
+		lastHeight.Store(*startHeight - 1)
+	}
+	heartbeats := time.NewTicker(heartbeatInterval)
+	defer heartbeats.Stop()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heartbeats.C:
+				err := wsjson.Write(ctx, conn, EventsMessage{
+					Type: "heartbeat",
+					BlockEvents: &models.BlockEvents{
+						BlockHeight: strconv.FormatUint(lastHeight.Load(), 10),
+					},
+				})
+				if err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	handle := func(header *flowgo.Header) error {
+		var events []flowgo.Event
+		for _, eventType := range eventTypes {
+			blockEvents, err := h.adapter.GetEventsForHeightRange(ctx, eventType, header.Height, header.Height)
+			if err != nil {
+				return err
+			}
+			for _, block := range blockEvents {
+				events = append(events, block.Events...)
+			}
+		}
+		lastHeight.Store(header.Height)
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		var blockEvents models.BlockEvents
+		blockEvents.Build(flowgo.BlockEvents{
+			BlockID:        header.ID(),
+			BlockHeight:    header.Height,
+			BlockTimestamp: header.Timestamp,
+			Events:         events,
+		})
+		heartbeats.Reset(heartbeatInterval)
+
+		return wsjson.Write(ctx, conn, EventsMessage{
+			Type:        "events",
+			BlockEvents: &blockEvents,
+		})
+	}
+
+	err = h.adapter.SubscribeBlocksFromHeight(ctx, *startHeight, handle)
+	if err != nil && ctx.Err() == nil {
+		h.logger.Debug().Err(err).Msg("📡  Events subscription failed")
+		if status.Code(err) == codes.InvalidArgument {
+			_ = conn.Close(websocket.StatusPolicyViolation, "invalid start height")
+		} else {
+			_ = conn.Close(websocket.StatusInternalError, "subscription failed")
+		}
+		return
+	}
+
+	_ = conn.Close(websocket.StatusNormalClosure, "")
+}
@@ -0,0 +1,117 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package access_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/access"
+)
+
+func TestEventsWebSocket(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	server, err := access.NewRestServer(&logger, adapter, b.GetChain(), "127.0.0.1", 0, false)
+	require.NoError(t, err)
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Start()
+	}()
+	defer server.Stop()
+
+	sdkAdapter := adapters.NewSDKAdapter(&logger, b)
+
+	// an account created before subscribing is only streamed from a start height
+	_, err = sdkAdapter.CreateAccount(context.Background(), nil, nil, 0)
+	require.NoError(t, err)
+
+	latest, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	url := fmt.Sprintf("ws://%s%s?type=%s", server.Addr(), access.EventsWebSocketPath, flowsdk.EventAccountCreated)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("from start height", func(t *testing.T) {
+		conn, _, err := websocket.Dial(ctx, url+"&start_height=1", nil)
+		require.NoError(t, err)
+		defer conn.Close(websocket.StatusNormalClosure, "")
+
+		var msg access.EventsMessage
+		require.NoError(t, wsjson.Read(ctx, conn, &msg))
+		assert.Equal(t, "events", msg.Type)
+		require.Len(t, msg.Events, 1)
+		assert.Equal(t, flowsdk.EventAccountCreated, msg.Events[0].Type_)
+
+		height, err := strconv.ParseUint(msg.BlockHeight, 10, 64)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, height, latest.Header.Height)
+	})
+
+	t.Run("new blocks and heartbeats", func(t *testing.T) {
+		conn, _, err := websocket.Dial(ctx, url+"&heartbeat_interval=100ms", nil)
+		require.NoError(t, err)
+		defer conn.Close(websocket.StatusNormalClosure, "")
+
+		// without blocks, heartbeats report the latest block processed
+		var msg access.EventsMessage
+		require.NoError(t, wsjson.Read(ctx, conn, &msg))
+		assert.Equal(t, "heartbeat", msg.Type)
+		assert.Equal(t, strconv.FormatUint(latest.Header.Height, 10), msg.BlockHeight)
+
+		_, err = sdkAdapter.CreateAccount(context.Background(), nil, nil, 0)
+		require.NoError(t, err)
+
+		for msg.Type == "heartbeat" {
+			require.NoError(t, wsjson.Read(ctx, conn, &msg))
+		}
+		assert.Equal(t, "events", msg.Type)
+		require.Len(t, msg.Events, 1)
+		assert.Equal(t, flowsdk.EventAccountCreated, msg.Events[0].Type_)
+	})
+
+	t.Run("missing event type", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("http://%s%s", server.Addr(), access.EventsWebSocketPath))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,424 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cron runs scripts and transactions against the emulator periodically,
+// e.g. to stand in for the off-chain bots a protocol relies on.
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// TickInterval is the resolution of the jobs run every given duration.
+const TickInterval = 100 * time.Millisecond
+
+// Spec describes a job to run periodically.
+//
+// Exactly one of EveryBlocks and Every must be set.
+type Spec struct {
+	// Script is the Cadence code of a script or a transaction.
+	Script string `json:"script"`
+	// Arguments are the JSON-CDC encoded arguments.
+	Arguments []json.RawMessage `json:"arguments,omitempty"`
+	// EveryBlocks runs the job each time this number of blocks was committed.
+	EveryBlocks uint64 `json:"everyBlocks,omitempty"`
+	// Every runs the job each time this duration elapsed, e.g. "30s".
+	Every string `json:"every,omitempty"`
+}
+
+// Job is a script or transaction run periodically.
+type Job struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Spec
+	Runs    int  `json:"runs"`
+	LastRun *Run `json:"lastRun,omitempty"`
+
+	// sequence orders the jobs in the order they were added
+	sequence    uint64
+	every       time.Duration
+	authorizers int
+	arguments   [][]byte
+	// blocks is the number of blocks committed since the last run
+	blocks uint64
+	// next is the time of the next run of jobs run every given duration
+	next time.Time
+}
+
+// Run is the outcome of a run of a job.
+type Run struct {
+	Time        time.Time `json:"time"`
+	BlockHeight uint64    `json:"blockHeight"`
+	// TransactionID is the ID of the submitted transaction, for transaction jobs.
+	TransactionID string `json:"transactionId,omitempty"`
+	// Value is the JSON-CDC encoded result, for script jobs.
+	Value json.RawMessage `json:"value,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// Scheduler runs jobs against the emulator. Transactions are proposed, paid for
+// and authorized by the service account.
+//
+// Blocks committed only with transactions of the scheduler are not counted
+// towards the jobs run every number of blocks, so that they do not trigger
+// each other when blocks are committed for every transaction.
+type Scheduler struct {
+	logger   *zerolog.Logger
+	emulator emulator.Emulator
+	done     chan bool
+
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	nextID uint64
+	// submitted are the IDs of the transactions submitted by the scheduler
+	// which were not seen in a committed block yet
+	submitted map[flowgo.Identifier]struct{}
+}
+
+func New(logger *zerolog.Logger, emulator emulator.Emulator) *Scheduler {
+	return &Scheduler{
+		logger:    logger,
+		emulator:  emulator,
+		done:      make(chan bool, 1),
+		jobs:      map[string]*Job{},
+		submitted: map[flowgo.Identifier]struct{}{},
+	}
+}
+
+// Add validates and registers a job. Jobs run every given duration first run
+// once this duration elapsed.
+func (s *Scheduler) Add(spec Spec) (*Job, error) {
+	job := &Job{Spec: spec}
+
+	if (spec.EveryBlocks == 0) == (spec.Every == "") {
+		return nil, types.NewInvalidArgumentError("exactly one of everyBlocks and every must be set")
+	}
+
+	if spec.Every != "" {
+		every, err := time.ParseDuration(spec.Every)
+		if err != nil || every < TickInterval {
+			return nil, types.NewInvalidArgumentError(
+				fmt.Sprintf("invalid interval %q, it must be a duration of at least %s", spec.Every, TickInterval),
+			)
+		}
+		job.every = every
+		job.next = time.Now().Add(every)
+	}
+
+	entryPoint, err := emulator.IntrospectEntryPoint([]byte(spec.Script))
+	if err != nil {
+		return nil, types.NewInvalidArgumentError(err.Error())
+	}
+	job.Kind = entryPoint.Kind
+	job.authorizers = entryPoint.Authorizers
+
+	if len(spec.Arguments) != len(entryPoint.Parameters) {
+		return nil, types.NewInvalidArgumentError(
+			fmt.Sprintf("expected %d arguments, got %d", len(entryPoint.Parameters), len(spec.Arguments)),
+		)
+	}
+	for _, argument := range spec.Arguments {
+		job.arguments = append(job.arguments, argument)
+	}
+
+	if job.Kind == emulator.EntryPointKindTransaction && s.emulator.ServiceKey().PrivateKey == nil {
+		return nil, types.NewInvalidArgumentError("not able to sign transactions without the service account private key")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	job.sequence = s.nextID
+	job.ID = strconv.FormatUint(s.nextID, 10)
+	s.jobs[job.ID] = job
+
+	return job.copy(), nil
+}
+
+// Remove unregisters a job, and returns false if there is no job with the given ID.
+func (s *Scheduler) Remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.jobs[id]
+	delete(s.jobs, id)
+	return ok
+}
+
+// Jobs returns the registered jobs, oldest first.
+func (s *Scheduler) Jobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.sortedJobs() {
+		jobs = append(jobs, *job.copy())
+	}
+
+	return jobs
+}
+
+// Start runs the jobs until the scheduler is stopped. Every block committed
+// meanwhile is counted, even if blocks are committed faster than jobs run.
+func (s *Scheduler) Start() error {
+	ctx := context.Background()
+
+	blocks, err := emulator.NewBlockFeed(ctx, s.emulator)
+	if err != nil {
+		return err
+	}
+	defer blocks.Close()
+
+	ticker := time.NewTicker(TickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-blocks.Commits():
+			err := blocks.Deliver(ctx, event, func(block *flowgo.Block) error {
+				s.blockCommitted(block)
+				return nil
+			})
+			if err != nil {
+				s.logger.Warn().Err(err).Msg("❗  Failed to read the committed blocks for the scheduled jobs")
+			}
+		case now := <-ticker.C:
+			s.tick(now)
+		case <-s.done:
+			return nil
+		}
+	}
+}
+
+func (s *Scheduler) Stop() {
+	s.done <- true
+}
+
+// blockCommitted runs the jobs due after the given block was committed.
+func (s *Scheduler) blockCommitted(block *flowgo.Block) {
+	ctx := context.Background()
+
+	if s.committedBySchedulerOnly(ctx, block) {
+		return
+	}
+
+	s.runDue(func(job *Job) bool {
+		if job.EveryBlocks == 0 {
+			return false
+		}
+		job.blocks++
+		return job.blocks >= job.EveryBlocks
+	})
+}
+
+// tick runs the jobs run every given duration which are due.
+func (s *Scheduler) tick(now time.Time) {
+	s.runDue(func(job *Job) bool {
+		return job.every > 0 && !now.Before(job.next)
+	})
+}
+
+// runDue runs the jobs for which due returns true, in the order they were added.
+func (s *Scheduler) runDue(due func(job *Job) bool) {
+	s.mu.Lock()
+	var jobs []*Job
+	for _, job := range s.sortedJobs() {
+		if due(job) {
+			jobs = append(jobs, job)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		run := s.run(context.Background(), job)
+
+		s.mu.Lock()
+		job.Runs++
+		job.LastRun = run
+		job.blocks = 0
+		if job.every > 0 {
+			job.next = run.Time.Add(job.every)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// committedBySchedulerOnly returns true if all the transactions of the block
+// were submitted by the scheduler. Empty blocks are not.
+func (s *Scheduler) committedBySchedulerOnly(ctx context.Context, block *flowgo.Block) bool {
+	if len(block.Payload.Guarantees) == 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	own := true
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := s.emulator.GetCollectionByID(ctx, guarantee.CollectionID)
+		if err != nil {
+			return false
+		}
+
+		for _, txID := range collection.Transactions {
+			if _, ok := s.submitted[txID]; ok {
+				delete(s.submitted, txID)
+			} else {
+				own = false
+			}
+		}
+	}
+
+	return own
+}
+
+func (s *Scheduler) run(ctx context.Context, job *Job) *Run {
+	run := &Run{
+		Time: time.Now(),
+	}
+
+	latestBlock, err := s.emulator.GetLatestBlock(ctx)
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+	run.BlockHeight = latestBlock.Header.Height
+
+	if job.Kind == emulator.EntryPointKindScript {
+		err = s.runScript(ctx, job, run)
+	} else {
+		err = s.runTransaction(ctx, job, latestBlock, run)
+	}
+	if err != nil {
+		run.Error = err.Error()
+		s.logger.Warn().
+			Str("job", job.ID).
+			Err(err).
+			Msgf("❗  Cron job %s failed", job.ID)
+		return run
+	}
+
+	s.logger.Debug().
+		Str("job", job.ID).
+		Str("kind", job.Kind).
+		Msgf("⏰  Cron job %s ran", job.ID)
+
+	return run
+}
+
+func (s *Scheduler) runScript(ctx context.Context, job *Job, run *Run) error {
+	result, err := s.emulator.ExecuteScript(ctx, []byte(job.Script), job.arguments)
+	if err != nil {
+		return err
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	value, err := jsoncdc.Encode(result.Value)
+	if err != nil {
+		return err
+	}
+	run.Value = value
+
+	return nil
+}
+
+func (s *Scheduler) runTransaction(ctx context.Context, job *Job, latestBlock *flowgo.Block, run *Run) error {
+	serviceAddress := s.emulator.ServiceKey().Address
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(job.Script)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetReferenceBlockID(flowsdk.Identifier(latestBlock.ID()))
+	for i := 0; i < job.authorizers; i++ {
+		tx.AddAuthorizer(serviceAddress)
+	}
+	for _, argument := range job.arguments {
+		tx.AddRawArgument(argument)
+	}
+
+	// the transaction is recorded before it is sent, as it may be committed right away,
+	// and signed again if other transactions of the service key were sent meanwhile
+	var recorded *flowgo.Identifier
+	record := func(flowTx *flowgo.TransactionBody) {
+		txID := flowTx.ID()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if recorded != nil {
+			delete(s.submitted, *recorded)
+		}
+		s.submitted[txID] = struct{}{}
+		recorded = &txID
+	}
+
+	flowTx, err := emulator.SendServiceTransaction(ctx, s.emulator, tx, record)
+	if flowTx != nil {
+		run.TransactionID = flowTx.ID().String()
+	}
+	if err != nil {
+		if recorded != nil {
+			s.mu.Lock()
+			delete(s.submitted, *recorded)
+			s.mu.Unlock()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// sortedJobs returns the jobs in the order they were added.
+func (s *Scheduler) sortedJobs() []*Job {
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].sequence < jobs[j].sequence
+	})
+
+	return jobs
+}
+
+func (j *Job) copy() *Job {
+	job := *j
+	if j.LastRun != nil {
+		lastRun := *j.LastRun
+		job.LastRun = &lastRun
+	}
+	return &job
+}
@@ -0,0 +1,209 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cron_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/cron"
+)
+
+func startScheduler(t *testing.T, b *emulator.Blockchain) *cron.Scheduler {
+	logger := zerolog.Nop()
+	scheduler := cron.New(&logger, b)
+
+	go func() {
+		_ = scheduler.Start()
+	}()
+	t.Cleanup(scheduler.Stop)
+
+	// wait until the scheduler subscribed, blocks committed before are not counted
+	require.Eventually(t, func() bool {
+		status, err := b.Status(context.Background())
+		return err == nil && status.BlockSubscriptions == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	return scheduler
+}
+
+func TestAdd(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	scheduler := cron.New(&logger, b)
+
+	script := `access(all) fun main(): Int { return 1 }`
+
+	for name, spec := range map[string]cron.Spec{
+		"no interval":       {Script: script},
+		"both intervals":    {Script: script, EveryBlocks: 1, Every: "1s"},
+		"invalid duration":  {Script: script, Every: "often"},
+		"too short":         {Script: script, Every: "1ms"},
+		"invalid script":    {Script: "access(all) fun", EveryBlocks: 1},
+		"missing arguments": {Script: `access(all) fun main(a: Int): Int { return a }`, EveryBlocks: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := scheduler.Add(spec)
+			assert.Error(t, err)
+		})
+	}
+
+	job, err := scheduler.Add(cron.Spec{Script: script, EveryBlocks: 1})
+	require.NoError(t, err)
+	assert.Equal(t, emulator.EntryPointKindScript, job.Kind)
+
+	jobs := scheduler.Jobs()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, job.ID, jobs[0].ID)
+
+	assert.True(t, scheduler.Remove(job.ID))
+	assert.False(t, scheduler.Remove(job.ID))
+	assert.Empty(t, scheduler.Jobs())
+}
+
+func TestEveryBlocks(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	// blocks are committed for each transaction, including the ones of the scheduler
+	b.EnableAutoMine()
+
+	scheduler := startScheduler(t, b)
+
+	job, err := scheduler.Add(cron.Spec{
+		Script:      `transaction(message: String) { prepare(signer: AuthAccount) { log(message) } }`,
+		Arguments:   []json.RawMessage{json.RawMessage(`{"type":"String","value":"poke"}`)},
+		EveryBlocks: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, emulator.EntryPointKindTransaction, job.Kind)
+
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return scheduler.Jobs()[0].Runs == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	lastRun := scheduler.Jobs()[0].LastRun
+	require.NotNil(t, lastRun)
+	require.Empty(t, lastRun.Error)
+	require.NotEmpty(t, lastRun.TransactionID)
+
+	// the block committed for the transaction of the job does not count
+	// towards the next run
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 1, scheduler.Jobs()[0].Runs)
+
+	latest, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	results, err := b.GetTransactionResultsByBlockID(context.Background(), latest.ID())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, lastRun.TransactionID, results[0].TransactionID.String())
+	assert.Empty(t, results[0].ErrorMessage)
+
+	// the sequence number of the service account key is tracked across runs
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return scheduler.Jobs()[0].Runs == 2
+	}, 30*time.Second, 10*time.Millisecond)
+	assert.Empty(t, scheduler.Jobs()[0].LastRun.Error)
+}
+
+func TestEveryBlocksUnderLoad(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	scheduler := startScheduler(t, b)
+
+	_, err = scheduler.Add(cron.Spec{
+		Script: `
+			access(all) fun main(): Int {
+				var i = 0
+				while i < 2000 {
+					i = i + 1
+				}
+				return i
+			}
+		`,
+		EveryBlocks: 1,
+	})
+	require.NoError(t, err)
+
+	// blocks are committed faster than the job runs, none of them is missed
+	const blocks = 50
+	for i := 0; i < blocks; i++ {
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return scheduler.Jobs()[0].Runs == blocks
+	}, 30*time.Second, 10*time.Millisecond)
+}
+
+func TestEvery(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	scheduler := startScheduler(t, b)
+
+	_, err = scheduler.Add(cron.Spec{
+		Script: `access(all) fun main(): Int { return 42 }`,
+		Every:  "100ms",
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return scheduler.Jobs()[0].Runs >= 2
+	}, 30*time.Second, 10*time.Millisecond)
+
+	lastRun := scheduler.Jobs()[0].LastRun
+	require.NotNil(t, lastRun)
+	assert.Empty(t, lastRun.Error)
+	assert.JSONEq(t, `{"type":"Int","value":"42"}`, string(lastRun.Value))
+}
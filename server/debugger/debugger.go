@@ -51,14 +51,38 @@ func New(logger *zerolog.Logger, emulator emulator.Emulator, port int) *Debugger
 	}
 }
 
-func (d *Debugger) Start() error {
-
+func (d *Debugger) Listen() error {
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", d.port))
 	if err != nil {
 		return err
 	}
 	d.listener = listener
-	defer listener.Close()
+	// the port may have been assigned by the operating system
+	d.port = listener.Addr().(*net.TCPAddr).Port
+	return nil
+}
+
+// Addr returns the address the debugger listens on, or nil if it is not listening yet.
+func (d *Debugger) Addr() net.Addr {
+	if d.listener == nil {
+		return nil
+	}
+	return d.listener.Addr()
+}
+
+// Port returns the port the debugger listens on. Once listening, a port configured
+// as zero is the one assigned by the operating system.
+func (d *Debugger) Port() int {
+	return d.port
+}
+
+func (d *Debugger) Start() error {
+	if d.listener == nil {
+		if err := d.Listen(); err != nil {
+			return err
+		}
+	}
+	defer d.listener.Close()
 
 	d.wg.Add(1)
 	go d.serve()
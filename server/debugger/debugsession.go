@@ -220,7 +220,7 @@ func (s *session) handleVariablesRequest(request *dap.VariablesRequest) {
 	case ScopeIdentifierStorage:
 		index := 1
 		for {
-			account, err := s.emulator.GetAccountByIndex(uint(index))
+			account, err := s.emulator.GetAccountByIndex(context.Background(), uint(index))
 			if err != nil { //end of accounts
 				break
 			}
@@ -741,8 +741,7 @@ func (s *session) pathCode(path string) string {
 	basename := strings.TrimSuffix(path, ".cdc")
 	backendEmulator := s.emulator
 
-	runningScriptID, runningCode := backendEmulator.(*emulator.Blockchain).CurrentScript()
-	if basename == runningScriptID {
+	if runningCode, ok := backendEmulator.(*emulator.Blockchain).RunningCode(basename); ok {
 		return runningCode
 	}
 
@@ -825,7 +824,7 @@ func (s *session) run() context.CancelFunc {
 			// TODO: add support for arguments
 			// TODO: add support for transactions. requires automine
 
-			result, err := s.emulator.ExecuteScript([]byte(s.code), nil)
+			result, err := s.emulator.ExecuteScript(ctx, []byte(s.code), nil)
 			cancel()
 
 			var outputBody dap.OutputEventBody
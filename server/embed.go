@@ -0,0 +1,184 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/psiemens/graceland"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage"
+)
+
+// Server is an emulator server embedded in a Go program, e.g. an integration test.
+//
+// Unlike NewEmulatorServer, ports left at zero are assigned by the operating
+// system, so any number of servers can run in parallel. The assigned
+// addresses are available from the Handle returned by Start.
+type Server struct {
+	conf   Config
+	logger zerolog.Logger
+}
+
+// Option is a function applying a change to an embedded server.
+type Option func(*Server)
+
+// WithLogger sets the logger of the server. By default, nothing is logged.
+func WithLogger(logger zerolog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// New returns an embedded server with the given configuration.
+// The configuration is copied, so it may be reused for other servers.
+func New(conf *Config, opts ...Option) *Server {
+	s := &Server{
+		conf:   *conf,
+		logger: zerolog.Nop(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Start creates the emulator and starts serving.
+//
+// It returns once all listeners are bound, so the server accepts connections
+// as soon as Start returns. The server runs until the context is done or
+// Handle.Stop is called.
+func (s *Server) Start(ctx context.Context) (*Handle, error) {
+	conf := s.conf
+	conf.EphemeralPorts = true
+
+	server, err := newEmulatorServer(&s.logger, sanitizeConfig(&conf))
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.Listen()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Handle{
+		server: server,
+		group:  server.newGroup(),
+		done:   make(chan struct{}),
+	}
+	server.group = h.group
+
+	go func() {
+		h.err = h.group.Start()
+		h.stop()
+		close(h.done)
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			h.stop()
+		case <-h.done:
+		}
+	}()
+
+	return h, nil
+}
+
+// Handle gives access to a running embedded server.
+type Handle struct {
+	server   *EmulatorServer
+	group    *graceland.Group
+	stopOnce sync.Once
+	done     chan struct{}
+	err      error
+}
+
+// Blockchain returns the emulated blockchain served.
+func (h *Handle) Blockchain() *emulator.Blockchain {
+	return h.server.emulator
+}
+
+// Storage returns the storage of the emulated blockchain.
+func (h *Handle) Storage() storage.Store {
+	return h.server.emulator.Storage()
+}
+
+// AccessAdapter returns the Access API implementation served over gRPC and REST.
+func (h *Handle) AccessAdapter() *adapters.AccessAdapter {
+	return h.server.accessAdapter
+}
+
+// GRPCAddr returns the address of the gRPC Access API.
+func (h *Handle) GRPCAddr() net.Addr {
+	return h.server.grpc.Addr()
+}
+
+// RESTAddr returns the address of the REST Access API.
+func (h *Handle) RESTAddr() net.Addr {
+	return h.server.rest.Addr()
+}
+
+// AdminAddr returns the address of the admin API.
+func (h *Handle) AdminAddr() net.Addr {
+	return h.server.admin.Addr()
+}
+
+// DebuggerAddr returns the address of the debugger.
+func (h *Handle) DebuggerAddr() net.Addr {
+	return h.server.debugger.Addr()
+}
+
+// Secondary returns the emulated blockchain of the secondary chain,
+// or nil if no secondary chain is configured.
+func (h *Handle) Secondary() *emulator.Blockchain {
+	return h.server.secondary
+}
+
+// SecondaryGRPCAddr returns the address of the gRPC Access API of the
+// secondary chain, or nil if no secondary chain is configured.
+func (h *Handle) SecondaryGRPCAddr() net.Addr {
+	if h.server.secondaryGRPC == nil {
+		return nil
+	}
+	return h.server.secondaryGRPC.Addr()
+}
+
+// Done returns a channel which is closed once the server stopped.
+func (h *Handle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Stop stops the server and waits until it stopped.
+// It returns the error which stopped the server unexpectedly, if any.
+func (h *Handle) Stop() error {
+	h.stop()
+	<-h.done
+	return h.err
+}
+
+func (h *Handle) stop() {
+	h.stopOnce.Do(h.group.Stop)
+}
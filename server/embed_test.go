@@ -0,0 +1,146 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestEmbeddedServer(t *testing.T) {
+
+	conf := &Config{Host: "127.0.0.1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// servers with ephemeral ports do not conflict
+	first, err := New(conf).Start(ctx)
+	require.NoError(t, err)
+	defer first.Stop()
+
+	second, err := New(conf).Start(ctx)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first.GRPCAddr().String(), second.GRPCAddr().String())
+	assert.NotEqual(t, first.AdminAddr().String(), second.AdminAddr().String())
+
+	// the server accepts connections as soon as Start returns
+	conn, err := grpc.Dial(
+		first.GRPCAddr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := accessproto.NewAccessAPIClient(conn)
+	_, err = client.Ping(context.Background(), &accessproto.PingRequest{})
+	require.NoError(t, err)
+
+	block, err := first.Blockchain().CommitBlock()
+	require.NoError(t, err)
+
+	latest, err := first.Storage().LatestBlock(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, block.ID(), latest.ID())
+
+	// the status reports the assigned ports
+	resp, err := http.Get(fmt.Sprintf("http://%s/emulator/status", first.AdminAddr()))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var status utils.Status
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	require.NotNil(t, status.Ports)
+	assert.Equal(t, first.GRPCAddr().(*net.TCPAddr).Port, status.Ports.GRPC)
+	assert.Equal(t, first.RESTAddr().(*net.TCPAddr).Port, status.Ports.REST)
+	assert.Equal(t, first.AdminAddr().(*net.TCPAddr).Port, status.Ports.Admin)
+	assert.Equal(t, first.DebuggerAddr().(*net.TCPAddr).Port, status.Ports.Debugger)
+
+	// stopping one server leaves the other running
+	require.NoError(t, second.Stop())
+
+	_, err = client.Ping(context.Background(), &accessproto.PingRequest{})
+	require.NoError(t, err)
+
+	// the server stops when the context is done
+	cancel()
+	select {
+	case <-first.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+}
+
+func TestEmbeddedServerSecondaryChain(t *testing.T) {
+
+	conf := &Config{
+		Host:             "127.0.0.1",
+		SecondaryChainID: flowgo.Testnet,
+	}
+
+	handle, err := New(conf).Start(context.Background())
+	require.NoError(t, err)
+	defer handle.Stop()
+
+	require.NotNil(t, handle.Secondary())
+	assert.Equal(t, flowgo.Emulator, handle.Blockchain().GetChain().ChainID())
+	assert.Equal(t, flowgo.Testnet, handle.Secondary().GetChain().ChainID())
+
+	require.NotNil(t, handle.SecondaryGRPCAddr())
+	assert.NotEqual(t, handle.GRPCAddr().String(), handle.SecondaryGRPCAddr().String())
+
+	// the secondary chain is served over its own Access API
+	conn, err := grpc.Dial(
+		handle.SecondaryGRPCAddr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := accessproto.NewAccessAPIClient(conn)
+	parameters, err := client.GetNetworkParameters(context.Background(), &accessproto.GetNetworkParametersRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, flowgo.Testnet.String(), parameters.ChainId)
+
+	// the status reports the port of the secondary chain
+	resp, err := http.Get(fmt.Sprintf("http://%s/emulator/status", handle.AdminAddr()))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var status utils.Status
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	require.NotNil(t, status.Ports)
+	assert.Equal(t, handle.SecondaryGRPCAddr().(*net.TCPAddr).Port, status.Ports.SecondaryGRPC)
+}
@@ -19,26 +19,38 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/onflow/cadence/runtime"
 	"github.com/onflow/flow-emulator/adapters"
 	"github.com/onflow/flow-emulator/emulator"
 	"github.com/onflow/flow-emulator/server/access"
+	"github.com/onflow/flow-emulator/server/cron"
 	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/server/watcher"
+	"github.com/onflow/flow-emulator/server/webhook"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/flow-go-sdk/crypto"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/fvm/environment"
 	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/trace"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/psiemens/graceland"
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-emulator/server/debugger"
 	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/chaos"
 	"github.com/onflow/flow-emulator/storage/remote"
 	"github.com/onflow/flow-emulator/storage/sqlite"
 	"github.com/onflow/flow-emulator/storage/util"
@@ -50,25 +62,44 @@ import (
 type EmulatorServer struct {
 	logger        *zerolog.Logger
 	config        *Config
-	emulator      emulator.Emulator
+	emulator      *emulator.Blockchain
 	accessAdapter *adapters.AccessAdapter
 	group         *graceland.Group
 	liveness      graceland.Routine
-	storage       graceland.Routine
+	storage       storage.Store
 	grpc          *access.GRPCServer
 	rest          *access.RestServer
 	admin         *utils.HTTPServer
-	blocks        graceland.Routine
-	debugger      graceland.Routine
+	follower      graceland.Routine
+	debugger      *debugger.Debugger
+	// contractWatcher redeploys changed contract files, if a watch path is configured
+	contractWatcher *watcher.ContractWatcher
+	// cron runs the scripts and transactions registered with the admin API periodically
+	cron *cron.Scheduler
+	// webhooks posts the events of contracts to the webhooks registered with the admin API
+	webhooks *webhook.Dispatcher
+	// secondary is an emulator of another chain, served next to the primary one
+	secondary        *emulator.Blockchain
+	secondaryGRPC    *access.GRPCServer
+	secondaryStorage storage.Store
+	// tracer exports the spans of transaction and script execution, if tracing is enabled
+	tracer module.Tracer
+	// metrics holds the metrics of the emulators of this server, served by the admin server
+	metrics *prometheus.Registry
 }
 
 const (
-	defaultGRPCPort               = 3569
-	defaultRESTPort               = 8888
-	defaultAdminPort              = 8080
+	defaultGRPCPort          = 3569
+	defaultSecondaryGRPCPort = 3570
+	defaultRESTPort          = 8888
+	defaultAdminPort         = 8080
+	// defaultGRPCMaxMsgSize is the maximum gRPC message size of Flow nodes, 20 MiB
+	defaultGRPCMaxMsgSize         = 20 << 20
 	defaultLivenessCheckTolerance = time.Second
 	defaultDBGCInterval           = time.Minute * 5
 	defaultDBGCRatio              = 0.5
+	defaultSyncInterval           = time.Second
+	defaultDeterministicTimeStep  = time.Second
 )
 
 var (
@@ -90,30 +121,65 @@ var (
 
 // Config is the configuration for an emulator server.
 type Config struct {
-	GRPCPort                  int
-	GRPCDebug                 bool
+	GRPCPort  int
+	GRPCDebug bool
+	// GRPCMaxRecvMsgSize is the maximum size of gRPC requests in bytes.
+	GRPCMaxRecvMsgSize int
+	// GRPCMaxSendMsgSize is the maximum size of gRPC responses in bytes.
+	GRPCMaxSendMsgSize int
+	// GRPCMessageSizeErrors measures every gRPC response, so responses larger than
+	// GRPCMaxSendMsgSize fail with an actionable error instead of a generic one.
+	GRPCMessageSizeErrors     bool
 	AdminPort                 int
 	DebuggerPort              int
 	RESTPort                  int
 	RESTDebug                 bool
 	HTTPHeaders               []utils.HTTPHeader
 	BlockTime                 time.Duration
+	ConsensusDelay            time.Duration
 	ServicePublicKey          crypto.PublicKey
 	ServicePrivateKey         crypto.PrivateKey
 	ServiceKeySigAlgo         crypto.SignatureAlgorithm
 	ServiceKeyHashAlgo        crypto.HashAlgorithm
 	GenesisTokenSupply        cadence.UFix64
+	BootstrapAccounts         int
+	BootstrapAccountBalance   cadence.UFix64
 	TransactionExpiry         uint
+	TransactionExpiryBuffer   uint
 	StorageLimitEnabled       bool
 	MinimumStorageReservation cadence.UFix64
 	StorageMBPerFLOW          cadence.UFix64
 	TransactionFeesEnabled    bool
 	TransactionMaxGasLimit    uint64
+	TransactionMaxByteSize    uint64
 	ScriptGasLimit            uint64
+	BlockGasLimit             uint64
+	MaxCollectionSize         int
+	ScriptTimeout             time.Duration
+	ErrorMessageMaxLength     int
+	ScriptWorkers             int
 	Persist                   bool
 	Snapshot                  bool
+	// AutoSnapshotInterval is the number of blocks between automatic snapshots, 0 disables them.
+	AutoSnapshotInterval uint64
+	// AutoSnapshotKeep is the number of automatic snapshots kept.
+	AutoSnapshotKeep int
+	// StateHistory is the number of latest blocks whose ledger state is kept, 0 keeps all.
+	StateHistory uint64
+	// Standby keeps the state once the emulator is bootstrapped, with its contracts deployed,
+	// as the standby state the state is reset to.
+	Standby bool
+	// DeterministicTimeStart is the timestamp of the first block, if block timestamps and views are
+	// deterministic. Blocks are timestamped DeterministicTimeStep apart.
+	DeterministicTimeStart time.Time
+	DeterministicTimeStep  time.Duration
 	// ContractRemovalEnabled configures possible removal of contracts.
 	ContractRemovalEnabled bool
+	// AttachmentsEnabled, AccountLinkingEnabled and CapabilityControllersEnabled
+	// toggle the corresponding Cadence runtime features.
+	AttachmentsEnabled           bool
+	AccountLinkingEnabled        bool
+	CapabilityControllersEnabled bool
 	// DBPath is the path to the Badger database on disk.
 	DBPath string
 	// DBGCInterval is the time interval at which to garbage collect the Badger value log.
@@ -129,36 +195,147 @@ type Config struct {
 	SkipTransactionValidation bool
 	// Host listen on for the emulator servers (REST/GRPC/Admin)
 	Host string
+	// EphemeralPorts lets the operating system assign the ports left at zero,
+	// instead of using the default ports.
+	EphemeralPorts bool
 	//Chain to emulation
 	ChainID flowgo.ChainID
 	//Redis URL for redis storage backend
 	RedisURL string
 	//Sqlite URL for sqlite storage backend
 	SqliteURL string
+	// Durability controls how eagerly the sqlite backend flushes block commits to disk,
+	// it is only supported by the sqlite backend
+	Durability storage.Durability
+	// StorageCompression is the compression of the events and transaction results stored
+	StorageCompression storage.Compression
+	// StorageProvider is the name of a storage backend registered with storage.Register.
+	StorageProvider string
+	// StorageDSN is the data source name passed to the storage provider.
+	StorageDSN string
+	// StorageChaos injects random latency and transient errors into storage operations.
+	StorageChaos chaos.Config
 	// CoverageReportingEnabled enables/disables Cadence code coverage reporting.
 	CoverageReportingEnabled bool
 	// StartBlockHeight is the height at which to start the emulator.
 	StartBlockHeight uint64
+	// Follow is the admin API address (host:port) of an emulator to replicate as a read-only follower.
+	Follow string
+	// Replica serves queries read-only from storage shared with another emulator which mints blocks.
+	Replica bool
+	// InteractionTemplatesPath is a directory of interaction templates (FLIX) to serve.
+	InteractionTemplatesPath string
+	// SecondaryChainID starts a second, in-memory emulator of the given chain,
+	// so client code can be tested against two address formats in one process.
+	SecondaryChainID flowgo.ChainID
+	// SecondaryGRPCPort is the port of the Access API of the secondary emulator.
+	SecondaryGRPCPort int
+	// ComputationReportingEnabled records the computation used by every
+	// transaction, broken down by computation kind.
+	ComputationReportingEnabled bool
+	// TestVectorsEnabled records the canonical encoding and signatures of
+	// successful transactions, served by the admin API as test vectors.
+	TestVectorsEnabled bool
+	// ContractsWatchPath is a directory of Cadence contracts redeployed to the
+	// service account whenever their files change.
+	ContractsWatchPath string
+	// TracingEnabled exports the spans of transaction and script execution with OpenTelemetry,
+	// configured with the standard OTEL_EXPORTER_OTLP_* environment variables.
+	TracingEnabled bool
+	// ImportStatePath is a state archive loaded into the empty storage when the server is created.
+	ImportStatePath string
+	// ExportStatePath is a file the state is archived to when the server stops.
+	ExportStatePath string
+	// LogStream receives the entries of the server logger, which are streamed
+	// by the admin API when it is set.
+	LogStream *utils.LogStream
+	// BlobStoreByteSize is the total size of the blobs kept by the admin API,
+	// the least recently used blobs are removed beyond it.
+	BlobStoreByteSize int
 }
 
 type listener interface {
 	Listen() error
+	Addr() net.Addr
 }
 
 // NewEmulatorServer creates a new instance of a Flow Emulator server.
+//
+// It returns nil if the server can not be created, after logging the reason.
 func NewEmulatorServer(logger *zerolog.Logger, conf *Config) *EmulatorServer {
-	conf = sanitizeConfig(conf)
+	server, err := newEmulatorServer(logger, sanitizeConfig(conf))
+	if err != nil {
+		logger.Error().Err(err).Msg("❗  Failed to create emulator server")
+		return nil
+	}
+	return server
+}
+
+func newEmulatorServer(logger *zerolog.Logger, conf *Config) (*EmulatorServer, error) {
+
+	if conf.Replica && conf.Follow != "" {
+		return nil, fmt.Errorf("--replica cannot be combined with --follow")
+	}
+
+	if conf.Replica && conf.RedisURL == "" && conf.SqliteURL == "" && conf.StorageProvider == "" && !conf.Persist {
+		return nil, fmt.Errorf("--replica requires storage shared with the writer, use --redis-url, --sqlite-url, --storage-provider or --persist")
+	}
+
+	if conf.ImportStatePath != "" && readOnly(conf) {
+		return nil, fmt.Errorf("--import-state cannot be combined with --follow or --replica")
+	}
+
+	if conf.Standby && readOnly(conf) {
+		return nil, fmt.Errorf("--standby cannot be combined with --follow or --replica")
+	}
+
+	// the blocks committed on top of the standby state are kept in memory
+	if conf.Standby && (conf.Persist || conf.SqliteURL != "" || conf.RedisURL != "" || conf.StorageProvider != "") {
+		return nil, fmt.Errorf("--standby cannot be combined with --persist, --sqlite-url, --redis-url or --storage-provider")
+	}
+
+	if conf.Standby && (conf.Snapshot || conf.AutoSnapshotInterval > 0) {
+		return nil, fmt.Errorf("--standby cannot be combined with --snapshot or --snapshot-interval")
+	}
+
+	if conf.SecondaryChainID != "" && conf.SecondaryChainID == conf.ChainID {
+		return nil, fmt.Errorf("--secondary-chain-id must differ from --chain-id")
+	}
 
 	store, err := configureStorage(conf)
 	if err != nil {
-		logger.Error().Err(err).Msg("❗  Failed to configure storage")
-		return nil
+		return nil, fmt.Errorf("failed to configure storage: %w", err)
+	}
+
+	if conf.ImportStatePath != "" {
+		err = importState(conf, store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import state: %w", err)
+		}
+		logger.Info().Str("path", conf.ImportStatePath).Msg("📦 Imported state")
 	}
 
-	emulatedBlockchain, err := configureBlockchain(logger, conf, store)
+	if conf.Follow != "" {
+		err = configureFollowerStorage(conf, store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sync genesis block from followed emulator: %w", err)
+		}
+	}
+
+	var tracer module.Tracer
+	if conf.TracingEnabled {
+		tracer, err = trace.NewTracer(*logger, "flow-emulator", conf.ChainID.String(), trace.SensitivityCaptureAll)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure tracer: %w", err)
+		}
+		logger.Info().Msg("🔭 Tracing transaction and script execution")
+	}
+
+	metrics := prometheus.NewRegistry()
+
+	emulatedBlockchain, err := configureBlockchain(logger, conf, store, tracer, metrics)
 	if err != nil {
-		logger.Err(err).Msg("❗  Failed to configure emulated emulator")
-		return nil
+		return nil, fmt.Errorf("failed to configure emulated emulator: %w", err)
 	}
 
 	chain := emulatedBlockchain.GetChain()
@@ -177,8 +354,11 @@ func NewEmulatorServer(logger *zerolog.Logger, conf *Config) *EmulatorServer {
 		logger.Info().Fields(map[string]any{contract: address}).Msg("📜 Flow contract")
 	}
 
-	if conf.WithContracts {
-		commonContracts := emulator.NewCommonContracts(chain)
+	if conf.WithContracts && !readOnly(conf) {
+		commonContracts := append(
+			emulator.NewCommonContracts(chain),
+			emulator.NewBlobsContract(chain, blobsURL(conf)),
+		)
 		err := emulator.DeployContracts(emulatedBlockchain, commonContracts)
 		if err != nil {
 			logger.Error().Err(err).Msg("❗  Failed to deploy contracts")
@@ -193,13 +373,20 @@ func NewEmulatorServer(logger *zerolog.Logger, conf *Config) *EmulatorServer {
 		}
 	}
 
+	if conf.Standby {
+		err = emulatedBlockchain.EnableStandby()
+		if err != nil {
+			return nil, fmt.Errorf("failed to enable standby: %w", err)
+		}
+		logger.Info().Msg("⏸️  Standby state kept, reset it with DELETE /emulator/state")
+	}
+
 	accessAdapter := adapters.NewAccessAdapter(logger, emulatedBlockchain)
 	livenessTicker := utils.NewLivenessTicker(conf.LivenessCheckTolerance)
-	grpcServer := access.NewGRPCServer(logger, accessAdapter, chain, conf.Host, conf.GRPCPort, conf.GRPCDebug)
+	grpcServer := access.NewGRPCServer(logger, accessAdapter, chain, conf.Host, conf.GRPCPort, conf.GRPCDebug, conf.GRPCMaxRecvMsgSize, conf.GRPCMaxSendMsgSize, conf.GRPCMessageSizeErrors)
 	restServer, err := access.NewRestServer(logger, accessAdapter, chain, conf.Host, conf.RESTPort, conf.RESTDebug)
 	if err != nil {
-		logger.Error().Err(err).Msg("❗  Failed to startup REST API")
-		return nil
+		return nil, fmt.Errorf("failed to startup REST API: %w", err)
 	}
 
 	server := &EmulatorServer{
@@ -213,19 +400,127 @@ func NewEmulatorServer(logger *zerolog.Logger, conf *Config) *EmulatorServer {
 		emulator:      emulatedBlockchain,
 		accessAdapter: accessAdapter,
 		debugger:      debugger.New(logger, emulatedBlockchain, conf.DebuggerPort),
+		tracer:        tracer,
+		metrics:       metrics,
+	}
+
+	templates := map[string]utils.InteractionTemplate{}
+	if conf.InteractionTemplatesPath != "" {
+		templates, err = utils.LoadInteractionTemplates(conf.InteractionTemplatesPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load interaction templates: %w", err)
+		}
+		logger.Info().Int("templates", len(templates)).Msg("📜 Loaded interaction templates")
+	}
+
+	var adminOptions []utils.EmulatorAPIServerOption
+	if conf.SecondaryChainID != "" {
+		err = server.configureSecondary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure secondary emulator: %w", err)
+		}
+		adminOptions = append(adminOptions, utils.WithSecondary(server.secondary))
 	}
 
-	server.admin = utils.NewAdminServer(logger, emulatedBlockchain, accessAdapter, grpcServer, livenessTicker, conf.Host, conf.AdminPort, conf.HTTPHeaders)
+	if conf.TestVectorsEnabled {
+		recorder := utils.NewTestVectorRecorder(emulatedBlockchain, 0)
+		emulatedBlockchain.OnTransactionExecuted(recorder.Record)
+		adminOptions = append(adminOptions, utils.WithTestVectors(recorder))
+	}
+
+	if conf.ContractsWatchPath != "" {
+		if readOnly(conf) {
+			return nil, fmt.Errorf("--contracts-watch cannot be combined with --follow or --replica")
+		}
+
+		server.contractWatcher, err = watcher.New(logger, emulatedBlockchain, conf.ContractsWatchPath, watcher.DefaultDebounce)
+		if err != nil {
+			return nil, fmt.Errorf("failed to watch contracts: %w", err)
+		}
+		adminOptions = append(adminOptions, utils.WithContractWatcher(server.contractWatcher))
+	}
+
+	adminOptions = append(adminOptions, utils.WithTransactionMaxByteSize(conf.TransactionMaxByteSize))
+	adminOptions = append(adminOptions, utils.WithBlobStoreByteSize(conf.BlobStoreByteSize))
+
+	server.cron = cron.New(logger, emulatedBlockchain)
+	adminOptions = append(adminOptions, utils.WithCron(server.cron))
+
+	server.webhooks = webhook.New(logger, emulatedBlockchain)
+	adminOptions = append(adminOptions, utils.WithWebhooks(server.webhooks))
+
+	if conf.LogStream != nil {
+		adminOptions = append(adminOptions, utils.WithLogStream(conf.LogStream))
+	}
+
+	server.admin = utils.NewAdminServer(logger, emulatedBlockchain, accessAdapter, grpcServer, livenessTicker, templates, conf.Host, conf.AdminPort, conf.HTTPHeaders, server.ports, metrics, adminOptions...)
 
-	// only create blocks ticker if block time > 0
-	if conf.BlockTime > 0 {
-		server.blocks = emulator.NewBlocksTicker(emulatedBlockchain, conf.BlockTime)
+	// followers only receive blocks from the followed emulator
+	if conf.Follow != "" {
+		server.follower = utils.NewFollower(logger, emulatedBlockchain, conf.Follow, defaultSyncInterval)
 		emulatedBlockchain.DisableAutoMine()
-	} else {
+	} else if conf.Replica {
+		// replicas pick up blocks the writer commits to the shared storage
+		server.follower = emulator.NewHeadTicker(emulatedBlockchain, defaultSyncInterval)
+		emulatedBlockchain.DisableAutoMine()
+	} else if conf.BlockTime == 0 {
+		// with a block time, the emulator commits blocks itself
 		emulatedBlockchain.EnableAutoMine()
 	}
 
-	return server
+	return server, nil
+}
+
+// configureSecondary creates the secondary emulator, which uses the
+// configuration of the primary one with another chain and in-memory storage.
+func (s *EmulatorServer) configureSecondary() error {
+	store, err := util.NewSqliteStorage(sqlite.InMemory, storage.DurabilityDefault)
+	if err != nil {
+		return err
+	}
+
+	conf := *s.config
+	conf.ChainID = s.config.SecondaryChainID
+	conf.SimpleAddressesEnabled = false
+	conf.Follow = ""
+	conf.Replica = false
+	conf.BlockTime = 0
+
+	secondary, err := configureBlockchain(s.logger, &conf, store, s.tracer, s.metrics)
+	if err != nil {
+		return err
+	}
+	secondary.EnableAutoMine()
+
+	s.secondary = secondary
+	s.secondaryStorage = store
+	s.secondaryGRPC = access.NewGRPCServer(
+		s.logger,
+		adapters.NewAccessAdapter(s.logger, secondary),
+		secondary.GetChain(),
+		conf.Host,
+		conf.SecondaryGRPCPort,
+		conf.GRPCDebug,
+		conf.GRPCMaxRecvMsgSize,
+		conf.GRPCMaxSendMsgSize,
+		conf.GRPCMessageSizeErrors,
+	)
+
+	s.logger.Info().
+		Str("chainID", conf.ChainID.String()).
+		Str("serviceAddress", secondary.ServiceKey().Address.Hex()).
+		Msg("🔗  Configured secondary emulator")
+
+	return nil
+}
+
+// listeners returns the servers accepting connections.
+func (s *EmulatorServer) listeners() []listener {
+	listeners := []listener{s.grpc, s.rest, s.admin, s.debugger}
+	if s.secondaryGRPC != nil {
+		listeners = append(listeners, s.secondaryGRPC)
+	}
+	return listeners
 }
 
 // Listen starts listening for incoming connections.
@@ -233,7 +528,10 @@ func NewEmulatorServer(logger *zerolog.Logger, conf *Config) *EmulatorServer {
 // After this non-blocking function executes we can treat the
 // emulator server as ready.
 func (s *EmulatorServer) Listen() error {
-	for _, lis := range []listener{s.grpc, s.rest, s.admin} {
+	for _, lis := range s.listeners() {
+		if lis.Addr() != nil { // already listening
+			continue
+		}
 		err := lis.Listen()
 		if err != nil { // fail quick
 			return err
@@ -249,48 +547,99 @@ func (s *EmulatorServer) Listen() error {
 func (s *EmulatorServer) Start() {
 	s.Stop()
 
-	s.group = graceland.NewGroup()
-	// only start blocks ticker if it exists
-	if s.blocks != nil {
-		s.group.Add(s.blocks)
+	// bind the listeners first, so the ports assigned by the operating system are logged
+	err := s.Listen()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("❗  Server error")
+		return
 	}
-	s.group.Add(s.liveness)
+
+	s.group = s.newGroup()
+
+	err = s.group.Start()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("❗  Server error")
+	}
+
+	s.Stop()
+}
+
+// newGroup returns the group of routines making up the server.
+func (s *EmulatorServer) newGroup() *graceland.Group {
+	group := graceland.NewGroup()
+	group.Add(s.liveness)
 
 	s.logger.Info().
-		Int("port", s.config.GRPCPort).
-		Msgf("🌱 Starting gRPC server on port %d", s.config.GRPCPort)
-	s.group.Add(s.grpc)
+		Int("port", s.grpc.Port()).
+		Msgf("🌱 Starting gRPC server on port %d", s.grpc.Port())
+	group.Add(s.grpc)
 
 	s.logger.Info().
-		Int("port", s.config.RESTPort).
-		Msgf("🌱 Starting REST API on port %d", s.config.RESTPort)
-	s.group.Add(s.rest)
+		Int("port", s.rest.Port()).
+		Msgf("🌱 Starting REST API on port %d", s.rest.Port())
+	group.Add(s.rest)
 
 	s.logger.Info().
-		Int("port", s.config.AdminPort).
-		Msgf("🌱 Starting admin server on port %d", s.config.AdminPort)
-	s.group.Add(s.admin)
+		Int("port", s.admin.Port()).
+		Msgf("🌱 Starting admin server on port %d", s.admin.Port())
+	group.Add(s.admin)
 
 	s.logger.Info().
-		Int("port", s.config.DebuggerPort).
-		Msgf("🌱 Starting debugger on port %d", s.config.DebuggerPort)
-	s.group.Add(s.debugger)
+		Int("port", s.debugger.Port()).
+		Msgf("🌱 Starting debugger on port %d", s.debugger.Port())
+	group.Add(s.debugger)
+
+	if s.secondaryGRPC != nil {
+		s.logger.Info().
+			Int("port", s.secondaryGRPC.Port()).
+			Msgf("🌱 Starting secondary gRPC server on port %d", s.secondaryGRPC.Port())
+		group.Add(s.secondaryGRPC)
+	}
 
-	// only start blocks ticker if it exists
-	if s.blocks != nil {
-		s.group.Add(s.blocks)
+	if s.follower != nil {
+		if s.config.Replica {
+			s.logger.Info().Msg("🔄  Serving as read-only replica of shared storage")
+		} else {
+			s.logger.Info().
+				Str("source", s.config.Follow).
+				Msgf("🔄  Following emulator at %s", s.config.Follow)
+		}
+		group.Add(s.follower)
 	}
 
-	// routines are shut down in insertion order, so database is added last
-	s.group.Add(s.storage)
+	if s.contractWatcher != nil {
+		s.logger.Info().
+			Str("path", s.config.ContractsWatchPath).
+			Msgf("👀 Watching contracts in %s", s.config.ContractsWatchPath)
+		group.Add(s.contractWatcher)
+	}
 
-	err := s.group.Start()
-	if err != nil {
-		s.logger.Error().Err(err).Msg("❗  Server error")
+	group.Add(s.cron)
+	group.Add(s.webhooks)
+
+	// routines are shut down in insertion order, so database is added last
+	group.Add(s.storage)
+	if s.secondaryStorage != nil {
+		group.Add(s.secondaryStorage)
 	}
 
-	s.Stop()
+	return group
 }
+
+// ports returns the ports the servers listen on.
+func (s *EmulatorServer) ports() utils.PortsStatus {
+	ports := utils.PortsStatus{
+		GRPC:     s.grpc.Port(),
+		REST:     s.rest.Port(),
+		Admin:    s.admin.Port(),
+		Debugger: s.debugger.Port(),
+	}
+	if s.secondaryGRPC != nil {
+		ports.SecondaryGRPC = s.secondaryGRPC.Port()
+	}
+	return ports
+}
+
 func (s *EmulatorServer) Emulator() emulator.Emulator {
 	return s.emulator
 }
@@ -304,13 +653,40 @@ func (s *EmulatorServer) Stop() {
 		return
 	}
 
+	// blocks are no longer committed once the storage is closed
+	s.emulator.StopBlockProduction()
+
 	s.group.Stop()
 
+	if s.config.ExportStatePath != "" {
+		err := s.exportState()
+		if err != nil {
+			s.logger.Error().Err(err).Msg("❗  Failed to export state")
+		} else {
+			s.logger.Info().Str("path", s.config.ExportStatePath).Msg("📦 Exported state")
+		}
+	}
+
+	s.emulator.Close()
+	if s.secondary != nil {
+		s.secondary.Close()
+	}
+
+	// flush the spans not exported yet
+	if s.tracer != nil {
+		<-s.tracer.Done()
+		s.tracer = nil
+	}
+
 	s.logger.Info().Msg("🛑  Server stopped")
 }
 
 func configureStorage(conf *Config) (storageProvider storage.Store, err error) {
 	if conf.RedisURL != "" {
+		if conf.Durability != storage.DurabilityDefault {
+			// redis flushing is governed by the server's appendfsync setting
+			return nil, fmt.Errorf("durability cannot be configured for redis storage")
+		}
 		storageProvider, err = util.NewRedisStorage(conf.RedisURL)
 		if err != nil {
 			return nil, err
@@ -321,7 +697,21 @@ func configureStorage(conf *Config) (storageProvider storage.Store, err error) {
 		if storageProvider != nil {
 			return nil, fmt.Errorf("you cannot define more than one storage")
 		}
-		storageProvider, err = util.NewSqliteStorage(conf.SqliteURL)
+		storageProvider, err = util.NewSqliteStorage(conf.SqliteURL, conf.Durability)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.StorageProvider != "" {
+		if storageProvider != nil {
+			return nil, fmt.Errorf("you cannot define more than one storage")
+		}
+		if conf.Durability != storage.DurabilityDefault {
+			// providers are only given a data source name
+			return nil, fmt.Errorf("durability cannot be configured for storage providers")
+		}
+		storageProvider, err = storage.Open(conf.StorageProvider, conf.StorageDSN)
 		if err != nil {
 			return nil, err
 		}
@@ -332,19 +722,27 @@ func configureStorage(conf *Config) (storageProvider storage.Store, err error) {
 			return nil, fmt.Errorf("you cannot use persist with current configuration")
 		}
 		_ = os.Mkdir(conf.DBPath, os.ModePerm)
-		storageProvider, err = util.NewSqliteStorage(conf.DBPath)
+		storageProvider, err = util.NewSqliteStorage(conf.DBPath, conf.Durability)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if storageProvider == nil {
-		storageProvider, err = util.NewSqliteStorage(sqlite.InMemory)
+		storageProvider, err = util.NewSqliteStorage(sqlite.InMemory, conf.Durability)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if conf.StorageCompression != storage.CompressionNone {
+		compressible, ok := storageProvider.(storage.CompressionConfigurable)
+		if !ok {
+			return nil, fmt.Errorf("compression cannot be configured for the storage")
+		}
+		compressible.SetCompression(conf.StorageCompression)
+	}
+
 	if conf.ChainID == flowgo.Testnet || conf.ChainID == flowgo.Mainnet {
 		// TODO: any reason redis shouldn't work?
 		baseProvider, ok := storageProvider.(*sqlite.Store)
@@ -367,7 +765,11 @@ func configureStorage(conf *Config) (storageProvider storage.Store, err error) {
 		storageProvider = provider
 	}
 
-	if conf.Snapshot {
+	if conf.StorageChaos.Enabled() {
+		storageProvider = chaos.New(storageProvider, conf.StorageChaos)
+	}
+
+	if conf.Snapshot || conf.AutoSnapshotInterval > 0 {
 		snapshotProvider, isSnapshotProvider := storageProvider.(storage.SnapshotProvider)
 		if !isSnapshotProvider {
 			return nil, fmt.Errorf("selected storage provider does not support snapshots")
@@ -380,20 +782,146 @@ func configureStorage(conf *Config) (storageProvider storage.Store, err error) {
 	return storageProvider, err
 }
 
-func configureBlockchain(logger *zerolog.Logger, conf *Config, store storage.Store) (*emulator.Blockchain, error) {
+// readOnly reports whether the emulator only serves blocks minted elsewhere.
+func readOnly(conf *Config) bool {
+	return conf.Follow != "" || conf.Replica
+}
+
+// configureFollowerStorage seeds empty storage with the genesis block of the
+// followed emulator, so the follower does not bootstrap a chain of its own.
+func configureFollowerStorage(conf *Config, store storage.Store) error {
+	ctx := context.Background()
+
+	_, err := store.LatestBlock(ctx)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+
+	genesis, err := utils.FetchCommittedBlock(ctx, http.DefaultClient, conf.Follow, 0)
+	if err != nil {
+		return err
+	}
+
+	return genesis.Commit(ctx, store)
+}
+
+// importState loads the state archive of the configuration into the empty storage.
+func importState(conf *Config, store storage.Store) error {
+	file, err := os.Open(conf.ImportStatePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return emulator.ImportState(context.Background(), file, store)
+}
+
+// exportState archives the state to the export path of the configuration.
+// The archive is written next to it first, so an existing archive is only
+// replaced by a complete one.
+func (s *EmulatorServer) exportState() error {
+	path := s.config.ExportStatePath
+	temporaryPath := path + ".tmp"
+
+	file, err := os.Create(temporaryPath)
+	if err != nil {
+		return err
+	}
+
+	err = s.emulator.ExportState(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(temporaryPath)
+		return err
+	}
+
+	return os.Rename(temporaryPath, path)
+}
+
+func configureBlockchain(
+	logger *zerolog.Logger,
+	conf *Config,
+	store storage.Store,
+	tracer module.Tracer,
+	metrics prometheus.Registerer,
+) (*emulator.Blockchain, error) {
 	options := []emulator.Option{
 		emulator.WithServerLogger(*logger),
 		emulator.WithStore(store),
 		emulator.WithGenesisTokenSupply(conf.GenesisTokenSupply),
 		emulator.WithTransactionMaxGasLimit(conf.TransactionMaxGasLimit),
+		emulator.WithTransactionMaxByteSize(conf.TransactionMaxByteSize),
 		emulator.WithScriptGasLimit(conf.ScriptGasLimit),
+		emulator.WithBlockGasLimit(conf.BlockGasLimit),
+		emulator.WithMaxCollectionSize(conf.MaxCollectionSize),
+		emulator.WithScriptTimeout(conf.ScriptTimeout),
+		emulator.WithErrorMessageMaxLength(conf.ErrorMessageMaxLength),
+		emulator.WithComputationReporting(conf.ComputationReportingEnabled),
+		emulator.WithScriptWorkers(conf.ScriptWorkers),
+		emulator.WithConsensusDelay(conf.ConsensusDelay),
 		emulator.WithTransactionExpiry(conf.TransactionExpiry),
+		emulator.WithTransactionExpiryBuffer(conf.TransactionExpiryBuffer),
 		emulator.WithStorageLimitEnabled(conf.StorageLimitEnabled),
 		emulator.WithMinimumStorageReservation(conf.MinimumStorageReservation),
 		emulator.WithStorageMBPerFLOW(conf.StorageMBPerFLOW),
 		emulator.WithTransactionFeesEnabled(conf.TransactionFeesEnabled),
 		emulator.WithChainID(conf.ChainID),
 		emulator.WithContractRemovalEnabled(conf.ContractRemovalEnabled),
+		emulator.WithAttachmentsEnabled(conf.AttachmentsEnabled),
+		emulator.WithAccountLinkingEnabled(conf.AccountLinkingEnabled),
+		emulator.WithCapabilityControllersEnabled(conf.CapabilityControllersEnabled),
+		emulator.WithReadOnly(readOnly(conf)),
+		// the primary and the secondary emulator are told apart by their chain
+		emulator.WithMetricsRegisterer(prometheus.WrapRegistererWith(
+			prometheus.Labels{"chain_id": conf.ChainID.String()},
+			metrics,
+		)),
+	}
+
+	if tracer != nil {
+		options = append(options, emulator.WithTracer(tracer))
+	}
+
+	if !conf.DeterministicTimeStart.IsZero() {
+		options = append(
+			options,
+			emulator.WithDeterministicTime(conf.DeterministicTimeStart, conf.DeterministicTimeStep),
+		)
+	}
+
+	// replicas share the snapshots and test accounts of the emulator minting blocks
+	if conf.AutoSnapshotInterval > 0 && !readOnly(conf) {
+		options = append(
+			options,
+			emulator.WithAutoSnapshots(conf.AutoSnapshotInterval, conf.AutoSnapshotKeep),
+		)
+	}
+
+	// followers and replicas only commit the blocks of the emulator they follow
+	if conf.BlockTime > 0 && conf.Follow == "" && !conf.Replica {
+		options = append(
+			options,
+			emulator.WithBlockTime(conf.BlockTime),
+		)
+	}
+
+	if conf.StateHistory > 0 && !readOnly(conf) {
+		options = append(
+			options,
+			emulator.WithStateHistory(conf.StateHistory),
+		)
+	}
+
+	if conf.BootstrapAccounts > 0 && !readOnly(conf) {
+		options = append(
+			options,
+			emulator.WithBootstrapAccounts(conf.BootstrapAccounts, conf.BootstrapAccountBalance),
+		)
 	}
 
 	if conf.SkipTransactionValidation {
@@ -438,18 +966,34 @@ func configureBlockchain(logger *zerolog.Logger, conf *Config, store storage.Sto
 }
 
 func sanitizeConfig(conf *Config) *Config {
-	if conf.GRPCPort == 0 {
+	if conf.GRPCPort == 0 && !conf.EphemeralPorts {
 		conf.GRPCPort = defaultGRPCPort
 	}
 
-	if conf.RESTPort == 0 {
+	if conf.SecondaryChainID != "" && conf.SecondaryGRPCPort == 0 && !conf.EphemeralPorts {
+		conf.SecondaryGRPCPort = defaultSecondaryGRPCPort
+	}
+
+	if conf.RESTPort == 0 && !conf.EphemeralPorts {
 		conf.RESTPort = defaultRESTPort
 	}
 
-	if conf.AdminPort == 0 {
+	if conf.AdminPort == 0 && !conf.EphemeralPorts {
 		conf.AdminPort = defaultAdminPort
 	}
 
+	if conf.GRPCMaxRecvMsgSize == 0 {
+		conf.GRPCMaxRecvMsgSize = defaultGRPCMaxMsgSize
+	}
+
+	if conf.GRPCMaxSendMsgSize == 0 {
+		conf.GRPCMaxSendMsgSize = defaultGRPCMaxMsgSize
+	}
+
+	if conf.TransactionMaxByteSize == 0 {
+		conf.TransactionMaxByteSize = flowgo.DefaultMaxTransactionByteSize
+	}
+
 	if conf.HTTPHeaders == nil {
 		conf.HTTPHeaders = defaultHTTPHeaders
 	}
@@ -466,9 +1010,23 @@ func sanitizeConfig(conf *Config) *Config {
 		conf.LivenessCheckTolerance = defaultLivenessCheckTolerance
 	}
 
+	if conf.DeterministicTimeStep == 0 {
+		conf.DeterministicTimeStep = defaultDeterministicTimeStep
+	}
+
 	if conf.ChainID == "" {
 		conf.ChainID = flowgo.Emulator
 	}
 
 	return conf
 }
+
+// blobsURL returns the URL of the blobs stored in the admin API, which the hashes of
+// blobs are resolved against by the EmulatorBlobs contract.
+func blobsURL(conf *Config) string {
+	host := conf.Host
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return fmt.Sprintf("http://%s/emulator/blobs/", net.JoinHostPort(host, strconv.Itoa(conf.AdminPort)))
+}
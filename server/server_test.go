@@ -21,10 +21,19 @@ package server
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/sqlite"
 )
 
 func TestExecuteScript(t *testing.T) {
@@ -73,7 +82,7 @@ func TestExecuteScriptImportingContracts(t *testing.T) {
 		serviceAccount,
 	)
 
-	_, err := server.Emulator().ExecuteScript([]byte(code), nil)
+	_, err := server.Emulator().ExecuteScript(context.Background(), []byte(code), nil)
 	require.NoError(t, err)
 
 }
@@ -91,3 +100,129 @@ func TestCustomChainID(t *testing.T) {
 
 	require.Equal(t, "f4527793ee68aede", serviceAccount)
 }
+
+func TestBlockTime(t *testing.T) {
+
+	conf := &Config{
+		BlockTime:      50 * time.Millisecond,
+		EphemeralPorts: true,
+	}
+	logger := zerolog.Nop()
+	server := NewEmulatorServer(&logger, conf)
+	require.NotNil(t, server)
+	go server.Start()
+
+	// the server is started once it is live
+	require.Eventually(t, func() bool {
+		response, err := http.Get(fmt.Sprintf("http://localhost:%d%s", server.admin.Port(), utils.LivenessPath))
+		if err != nil {
+			return false
+		}
+		_ = response.Body.Close()
+		return response.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	startBlock, err := server.Emulator().GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		block, err := server.Emulator().GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		return block.Header.Height >= startBlock.Header.Height+2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// blocks are no longer committed once the server is stopped
+	server.Stop()
+
+	stoppedBlock, err := server.Emulator().GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	time.Sleep(3 * conf.BlockTime)
+
+	block, err := server.Emulator().GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, stoppedBlock.Header.Height, block.Header.Height)
+}
+
+func TestSecondaryChainMustDiffer(t *testing.T) {
+
+	conf := &Config{
+		SecondaryChainID: flowgo.Emulator,
+	}
+	logger := zerolog.Nop()
+	server := NewEmulatorServer(&logger, conf)
+	require.Nil(t, server)
+}
+
+func TestStandbyMustNotPersist(t *testing.T) {
+
+	logger := zerolog.Nop()
+
+	for _, conf := range []*Config{
+		{Standby: true, Persist: true, DBPath: t.TempDir()},
+		{Standby: true, SqliteURL: t.TempDir()},
+		{Standby: true, RedisURL: "redis://127.0.0.1:6379"},
+		{Standby: true, StorageProvider: "standby-test"},
+		{Standby: true, Snapshot: true},
+		{Standby: true, AutoSnapshotInterval: 10},
+	} {
+		server := NewEmulatorServer(&logger, conf)
+		require.Nil(t, server)
+	}
+}
+
+func TestStorageProvider(t *testing.T) {
+
+	var opened bool
+	storage.Register("server-test", func(dsn string) (storage.Store, error) {
+		opened = true
+		return sqlite.New(dsn)
+	})
+
+	conf := &Config{
+		StorageProvider: "server-test",
+		StorageDSN:      sqlite.InMemory,
+	}
+	logger := zerolog.Nop()
+	server := NewEmulatorServer(&logger, conf)
+	require.NotNil(t, server)
+	require.True(t, opened)
+
+	conf = &Config{
+		StorageProvider: "server-test-unknown",
+	}
+	server = NewEmulatorServer(&logger, conf)
+	require.Nil(t, server)
+}
+
+func TestExportImportState(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "state.archive")
+
+	logger := zerolog.Nop()
+	server := NewEmulatorServer(&logger, &Config{ExportStatePath: path})
+	require.NotNil(t, server)
+
+	block, _, err := server.Emulator().ExecuteAndCommitBlock()
+	require.NoError(t, err)
+
+	require.NoError(t, server.exportState())
+
+	imported := NewEmulatorServer(&logger, &Config{ImportStatePath: path})
+	require.NotNil(t, imported)
+
+	latest, err := imported.Emulator().GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, block.ID(), latest.ID())
+
+	// the archive is written next to its path first
+	_, err = os.Stat(path + ".tmp")
+	require.True(t, os.IsNotExist(err))
+
+	// followers do not own their state
+	conf := &Config{
+		ImportStatePath: path,
+		Follow:          "localhost:8080",
+	}
+	require.Nil(t, NewEmulatorServer(&logger, conf))
+}
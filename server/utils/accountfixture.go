@@ -0,0 +1,102 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// ExportAccountFixture exports the registers of the account as a fixture,
+// which can be imported into another emulator.
+func (m EmulatorAPIServer) ExportAccountFixture(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	chain := m.emulator.GetNetworkParameters().ChainID.Chain()
+
+	address := flowgo.HexToAddress(vars["address"])
+	if !chain.IsValid(address) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	fixture, err := m.emulator.ExportAccountFixture(r.Context(), address)
+	if err != nil {
+		var notFoundErr *types.AccountNotFoundError
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(fixture)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// ImportAccountFixture recreates the account of the fixture in the request body in a new block.
+func (m EmulatorAPIServer) ImportAccountFixture(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var fixture emulator.AccountFixture
+	err := json.NewDecoder(r.Body).Decode(&fixture)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	block, err := m.emulator.ImportAccountFixture(r.Context(), &fixture)
+	if err != nil {
+		var invalidErr *types.InvalidArgumentError
+		var notEmptyErr *types.PendingBlockNotEmptyError
+		var readOnlyErr *types.ReadOnlyError
+		switch {
+		case errors.As(err, &invalidErr):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.As(err, &notEmptyErr), errors.As(err, &readOnlyErr):
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(&BlockResponse{
+		Height:  int(block.Header.Height),
+		BlockId: block.Header.ID().String(),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestAccountFixtureEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	source, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	address, err := adapters.NewSDKAdapter(&logger, source).CreateAccount(context.Background(), nil, nil, 0)
+	require.NoError(t, err)
+
+	target, err := emulator.New()
+	require.NoError(t, err)
+
+	sourceAPI := httptest.NewServer(utils.NewEmulatorAPIServer(source, nil))
+	defer sourceAPI.Close()
+
+	targetAPI := httptest.NewServer(utils.NewEmulatorAPIServer(target, nil))
+	defer targetAPI.Close()
+
+	resp, err := http.Get(sourceAPI.URL + "/emulator/accounts/" + address.Hex() + "/fixture")
+	require.NoError(t, err)
+	fixture, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var decoded emulator.AccountFixture
+	err = json.Unmarshal(fixture, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, flowgo.Address(address), decoded.Address)
+
+	importFixture := func(t *testing.T) int {
+		resp, err := http.Post(targetAPI.URL+"/emulator/accounts/fixtures", "application/json", bytes.NewReader(fixture))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, importFixture(t))
+
+	_, err = target.GetAccount(context.Background(), flowgo.Address(address))
+	assert.NoError(t, err)
+
+	// the account exists now
+	assert.Equal(t, http.StatusBadRequest, importFixture(t))
+
+	missing, err := flowgo.Emulator.Chain().AddressAtIndex(1000)
+	require.NoError(t, err)
+
+	resp, err = http.Get(sourceAPI.URL + "/emulator/accounts/" + missing.Hex() + "/fixture")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,203 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+const saveValuesScript = `
+	transaction {
+		prepare(signer: AuthAccount) {
+			signer.save(1, to: /storage/a)
+			signer.save("two", to: /storage/b)
+			signer.save([1, 2, 3], to: /storage/c)
+			signer.save(4, to: /storage/d)
+		}
+	}
+`
+
+func TestAccountStorageEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	serviceAddress := b.ServiceKey().Address
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(saveValuesScript)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(serviceAddress, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(serviceAddress).
+		AddAuthorizer(serviceAddress)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(serviceAddress, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+	require.NoError(t, err)
+
+	_, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	require.NoError(t, results[0].Error)
+
+	getStorage := func(t *testing.T, address string, query url.Values) (int, *emulator.AccountStorage) {
+		resp, err := http.Get(api.URL + "/emulator/storages/" + address + "?" + query.Encode())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var storage emulator.AccountStorage
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&storage))
+		return resp.StatusCode, &storage
+	}
+
+	keys := func(items []emulator.StorageItem) []string {
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			keys = append(keys, item.Domain+"/"+item.Key)
+		}
+		return keys
+	}
+
+	t.Run("paged", func(t *testing.T) {
+		query := url.Values{
+			"domain": {"storage"},
+			"limit":  {"3"},
+		}
+
+		status, page := getStorage(t, serviceAddress.Hex(), query)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, page.Items, 3)
+		require.NotEmpty(t, page.NextCursor)
+
+		var all []string
+		all = append(all, keys(page.Items)...)
+
+		for page.NextCursor != "" {
+			query.Set("cursor", page.NextCursor)
+			status, page = getStorage(t, serviceAddress.Hex(), query)
+			require.Equal(t, http.StatusOK, status)
+			all = append(all, keys(page.Items)...)
+		}
+
+		assert.Subset(t, all, []string{"storage/a", "storage/b", "storage/c", "storage/d"})
+		assert.IsIncreasing(t, all)
+	})
+
+	t.Run("type filter", func(t *testing.T) {
+		status, page := getStorage(t, serviceAddress.Hex(), url.Values{
+			"domain": {"storage"},
+			"type":   {"Int"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{"storage/a", "storage/d"}, keys(page.Items))
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("type filter paged", func(t *testing.T) {
+		query := url.Values{
+			"domain": {"storage"},
+			"type":   {"Int"},
+			"limit":  {"1"},
+		}
+
+		status, page := getStorage(t, serviceAddress.Hex(), query)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{"storage/a"}, keys(page.Items))
+
+		// the cursor is past the values filtered out
+		assert.Equal(t, "storage/c", page.NextCursor)
+
+		query.Set("cursor", page.NextCursor)
+		status, page = getStorage(t, serviceAddress.Hex(), query)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{"storage/d"}, keys(page.Items))
+
+		// no further values match
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("sizes", func(t *testing.T) {
+		status, page := getStorage(t, serviceAddress.Hex(), url.Values{
+			"domain": {"storage"},
+			"type":   {"[Int]"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, page.Items, 1)
+
+		status, ints := getStorage(t, serviceAddress.Hex(), url.Values{
+			"domain": {"storage"},
+			"type":   {"Int"},
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		item := page.Items[0]
+		assert.Equal(t, "c", item.Key)
+		assert.Greater(t, item.Size, ints.Items[0].Size)
+	})
+
+	t.Run("invalid domain", func(t *testing.T) {
+		status, _ := getStorage(t, serviceAddress.Hex(), url.Values{"domain": {"unknown"}})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		status, _ := getStorage(t, serviceAddress.Hex(), url.Values{"limit": {"zero"}})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		address, err := b.GetNetworkParameters().ChainID.Chain().AddressAtIndex(1000)
+		require.NoError(t, err)
+
+		status, _ := getStorage(t, address.Hex(), nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		status, _ := getStorage(t, "ffffffffffffffff", nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
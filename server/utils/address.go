@@ -0,0 +1,107 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// addressChains are the chains an address is checked against, besides the
+// configured one, to help spot addresses generated for another network.
+var addressChains = []flowgo.ChainID{
+	flowgo.Emulator,
+	flowgo.MonotonicEmulator,
+	flowgo.Testnet,
+	flowgo.Mainnet,
+}
+
+// AddressInfo describes an address in the context of a chain.
+type AddressInfo struct {
+	// Address is the address in canonical form: 0x-prefixed, zero-padded hex.
+	Address string `json:"address"`
+	// Hex is the zero-padded hex form, without prefix, as used by Cadence imports.
+	Hex string `json:"hex"`
+	// Uint64 is the address as a big-endian unsigned integer.
+	Uint64 uint64 `json:"uint64"`
+	// ChainID is the chain the address was validated against.
+	ChainID flowgo.ChainID `json:"chainId"`
+	// Valid is true if the address can be generated by the chain.
+	Valid bool `json:"valid"`
+	// Index is the position of the address in the chain's address sequence,
+	// the service account being 1. It is only set for valid addresses.
+	Index *uint64 `json:"index,omitempty"`
+	// ValidChains lists all known chains which can generate the address.
+	ValidChains []flowgo.ChainID `json:"validChains"`
+}
+
+// DecodeAddress parses a hex address, with or without 0x prefix and leading
+// zeros, and validates it against the given chain.
+func DecodeAddress(chain flowgo.Chain, value string) (*AddressInfo, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+	if trimmed == "" {
+		return nil, fmt.Errorf("address is empty")
+	}
+	if len(trimmed) > 2*flowgo.AddressLength {
+		return nil, fmt.Errorf(
+			"address %s is too long, addresses have at most %d hex digits",
+			value,
+			2*flowgo.AddressLength,
+		)
+	}
+	if len(trimmed)%2 == 1 {
+		trimmed = "0" + trimmed
+	}
+
+	b, err := hex.DecodeString(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("address %s is not valid hex", value)
+	}
+
+	address := flowgo.BytesToAddress(b)
+
+	info := &AddressInfo{
+		Address:     address.HexWithPrefix(),
+		Hex:         address.Hex(),
+		Uint64:      binary.BigEndian.Uint64(address[:]),
+		ChainID:     chain.ChainID(),
+		Valid:       chain.IsValid(address),
+		ValidChains: []flowgo.ChainID{},
+	}
+
+	if info.Valid {
+		index, err := chain.IndexFromAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		info.Index = &index
+	}
+
+	for _, chainID := range addressChains {
+		if chainID.Chain().IsValid(address) {
+			info.ValidChains = append(info.ValidChains, chainID)
+		}
+	}
+
+	return info, nil
+}
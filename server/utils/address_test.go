@@ -0,0 +1,98 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestAddressEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	get := func(t *testing.T, value string) (int, *utils.AddressInfo) {
+		resp, err := http.Get(api.URL + "/emulator/utils/address/" + value)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var info utils.AddressInfo
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+		return resp.StatusCode, &info
+	}
+
+	t.Run("service address", func(t *testing.T) {
+		status, info := get(t, "f8d6e0586b0a20c7")
+		require.Equal(t, http.StatusOK, status)
+
+		assert.Equal(t, "0xf8d6e0586b0a20c7", info.Address)
+		assert.Equal(t, "f8d6e0586b0a20c7", info.Hex)
+		assert.Equal(t, uint64(0xf8d6e0586b0a20c7), info.Uint64)
+		assert.Equal(t, flowgo.Emulator, info.ChainID)
+		assert.True(t, info.Valid)
+		require.NotNil(t, info.Index)
+		assert.Equal(t, uint64(1), *info.Index)
+		assert.Equal(t, []flowgo.ChainID{flowgo.Emulator}, info.ValidChains)
+	})
+
+	t.Run("address of another chain", func(t *testing.T) {
+		status, info := get(t, "0xe467b9dd11fa00df")
+		require.Equal(t, http.StatusOK, status)
+
+		assert.False(t, info.Valid)
+		assert.Nil(t, info.Index)
+		assert.Equal(t, []flowgo.ChainID{flowgo.Mainnet}, info.ValidChains)
+	})
+
+	t.Run("short address is padded", func(t *testing.T) {
+		status, info := get(t, "0x1")
+		require.Equal(t, http.StatusOK, status)
+
+		assert.Equal(t, "0x0000000000000001", info.Address)
+		assert.False(t, info.Valid)
+		assert.Contains(t, info.ValidChains, flowgo.MonotonicEmulator)
+	})
+
+	t.Run("malformed address", func(t *testing.T) {
+		status, _ := get(t, "0xnothex")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = get(t, "0x0123456789abcdef01")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
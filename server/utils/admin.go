@@ -28,8 +28,10 @@ import (
 	"github.com/onflow/flow-emulator/adapters"
 	"github.com/onflow/flow-emulator/emulator"
 	"github.com/onflow/flow-emulator/server/access"
+	"github.com/onflow/flow-emulator/utils/requestid"
 
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 )
@@ -38,6 +40,9 @@ const (
 	LivenessPath    = "/live"
 	MetricsPath     = "/metrics"
 	EmulatorApiPath = "/emulator/"
+	// EmulatorApiV1Prefix is the prefix of the current admin API version.
+	// Unprefixed paths remain served for backward compatibility.
+	EmulatorApiV1Prefix = "/v1"
 )
 
 type HTTPHeader struct {
@@ -59,9 +64,13 @@ func NewAdminServer(
 	adapter *adapters.AccessAdapter,
 	grpcServer *access.GRPCServer,
 	liveness *LivenessTicker,
+	templates map[string]InteractionTemplate,
 	host string,
 	port int,
 	headers []HTTPHeader,
+	ports func() PortsStatus,
+	metrics prometheus.Gatherer,
+	opts ...EmulatorAPIServerOption,
 ) *HTTPServer {
 	wrappedServer := grpcweb.WrapServer(
 		grpcServer.Server(),
@@ -71,8 +80,14 @@ func NewAdminServer(
 
 	mux := http.NewServeMux()
 
-	// register metrics handler
-	mux.Handle(MetricsPath, promhttp.Handler())
+	// register metrics handler, serving the metrics of the emulator next to the global ones
+	mux.Handle(MetricsPath, promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(
+			prometheus.Gatherers{prometheus.DefaultGatherer, metrics},
+			promhttp.HandlerOpts{},
+		),
+	))
 
 	// register liveness handler
 	mux.Handle(LivenessPath, liveness.Handler())
@@ -81,11 +96,20 @@ func NewAdminServer(
 	mux.Handle("/", wrappedHandler(wrappedServer, headers))
 
 	// register API handler
-	mux.Handle(EmulatorApiPath, NewEmulatorAPIServer(emulator, adapter))
+	apiServer := NewEmulatorAPIServer(emulator, adapter, append([]EmulatorAPIServerOption{WithPorts(ports)}, opts...)...)
+	mux.Handle(EmulatorApiPath, apiServer)
+	mux.Handle(EmulatorApiV1Prefix+EmulatorApiPath, apiServer)
+
+	// register interaction template handler
+	templateServer := NewInteractionTemplateServer(emulator, templates)
+	mux.Handle(InteractionTemplatesPath, templateServer)
+	mux.Handle(InteractionTemplatesPath+"/", templateServer)
+	mux.Handle(EmulatorApiV1Prefix+InteractionTemplatesPath, http.StripPrefix(EmulatorApiV1Prefix, templateServer))
+	mux.Handle(EmulatorApiV1Prefix+InteractionTemplatesPath+"/", http.StripPrefix(EmulatorApiV1Prefix, templateServer))
 
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", host, port),
-		Handler: mux,
+		Handler: requestid.Handler(mux),
 	}
 
 	return &HTTPServer{
@@ -103,9 +127,25 @@ func (h *HTTPServer) Listen() error {
 	}
 
 	h.listener = lis
+	// the port may have been assigned by the operating system
+	h.port = lis.Addr().(*net.TCPAddr).Port
 	return nil
 }
 
+// Addr returns the address the server listens on, or nil if it is not listening yet.
+func (h *HTTPServer) Addr() net.Addr {
+	if h.listener == nil {
+		return nil
+	}
+	return h.listener.Addr()
+}
+
+// Port returns the port the server listens on. Once listening, a port configured
+// as zero is the one assigned by the operating system.
+func (h *HTTPServer) Port() int {
+	return h.port
+}
+
 func (h *HTTPServer) Start() error {
 	if h.listener == nil {
 		if err := h.Listen(); err != nil {
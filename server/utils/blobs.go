@@ -0,0 +1,287 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// maxBlobByteSize bounds the size of blobs stored in the admin API.
+const maxBlobByteSize = 16 << 20
+
+// DefaultBlobStoreByteSize is the total size of the blobs kept by the admin API
+// when the size is not configured.
+const DefaultBlobStoreByteSize = 256 << 20
+
+// WithBlobStoreByteSize sets the total size of the blobs kept by the admin API.
+// Once it is exceeded, the least recently stored or read blobs are removed.
+func WithBlobStoreByteSize(size int) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		if size > 0 {
+			m.blobs.maxSize = size
+		}
+	}
+}
+
+// Blob is a blob stored in the admin API, e.g. an image referenced by the metadata of an NFT.
+type Blob struct {
+	// Hash is the hex encoded SHA-256 hash of the blob, which identifies it.
+	Hash        string `json:"hash"`
+	Size        int    `json:"size"`
+	ContentType string `json:"contentType"`
+}
+
+// PutBlobRequest is a blob sent as JSON, encoded as base64 (the default) or hex.
+type PutBlobRequest struct {
+	Data        string `json:"data"`
+	Encoding    string `json:"encoding"`
+	ContentType string `json:"contentType"`
+}
+
+type storedBlob struct {
+	data        []byte
+	contentType string
+	// element is the entry of the blob in the recently used blobs
+	element *list.Element
+}
+
+// blobStore holds the blobs stored in the admin API, by hash. Blobs are not persisted.
+// Once the blobs take up more than maxSize bytes, the least recently used ones are evicted.
+type blobStore struct {
+	mu    sync.Mutex
+	blobs map[string]*storedBlob
+	// recent holds the hashes of the blobs, most recently used first
+	recent  *list.List
+	size    int
+	maxSize int
+}
+
+func newBlobStore() *blobStore {
+	return &blobStore{
+		blobs:   make(map[string]*storedBlob),
+		recent:  list.New(),
+		maxSize: DefaultBlobStoreByteSize,
+	}
+}
+
+func (s *blobStore) put(data []byte, contentType string) (Blob, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(data) > s.maxSize {
+		return Blob{}, fmt.Errorf(
+			"blob of %d bytes exceeds the size of the blob store of %d bytes",
+			len(data),
+			s.maxSize,
+		)
+	}
+
+	s.removeBlob(hash)
+
+	s.blobs[hash] = &storedBlob{
+		data:        data,
+		contentType: contentType,
+		element:     s.recent.PushFront(hash),
+	}
+	s.size += len(data)
+
+	for s.size > s.maxSize {
+		s.removeBlob(s.recent.Back().Value.(string))
+	}
+
+	return Blob{
+		Hash:        hash,
+		Size:        len(data),
+		ContentType: contentType,
+	}, nil
+}
+
+func (s *blobStore) get(hash string) (storedBlob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	blob, ok := s.blobs[hash]
+	if !ok {
+		return storedBlob{}, false
+	}
+
+	s.recent.MoveToFront(blob.element)
+	return *blob, true
+}
+
+func (s *blobStore) remove(hash string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.removeBlob(hash)
+}
+
+func (s *blobStore) removeBlob(hash string) bool {
+	blob, ok := s.blobs[hash]
+	if !ok {
+		return false
+	}
+
+	s.recent.Remove(blob.element)
+	s.size -= len(blob.data)
+	delete(s.blobs, hash)
+	return true
+}
+
+func (s *blobStore) list() []Blob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	blobs := make([]Blob, 0, len(s.blobs))
+	for hash, blob := range s.blobs {
+		blobs = append(blobs, Blob{
+			Hash:        hash,
+			Size:        len(blob.data),
+			ContentType: blob.contentType,
+		})
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].Hash < blobs[j].Hash
+	})
+
+	return blobs
+}
+
+// PutBlob stores the request body as a blob, and responds with its hash.
+//
+// JSON bodies are decoded as a PutBlobRequest, so tests can send blobs encoded as base64
+// or hex. Bodies of any other content type are stored as is.
+func (m EmulatorAPIServer) PutBlob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	data, contentType, err := readBlob(w, r)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	blob, err := m.blobs.put(data, contentType)
+	if err != nil {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(blob)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func readBlob(w http.ResponseWriter, r *http.Request) ([]byte, string, error) {
+	body := http.MaxBytesReader(w, r.Body, maxBlobByteSize)
+
+	contentType := r.Header.Get("Content-Type")
+	if !hasMediaType(contentType, JSONContentType) {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, "", err
+		}
+		if contentType == "" {
+			contentType = OctetStreamContentType
+		}
+		return data, contentType, nil
+	}
+
+	var req PutBlobRequest
+	err := json.NewDecoder(body).Decode(&req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var data []byte
+	switch req.Encoding {
+	case "", "base64":
+		data, err = base64.StdEncoding.DecodeString(req.Data)
+	case "hex":
+		data, err = hex.DecodeString(req.Data)
+	default:
+		return nil, "", fmt.Errorf("unsupported encoding: %s", req.Encoding)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s data: %w", req.Encoding, err)
+	}
+
+	contentType = req.ContentType
+	if contentType == "" {
+		contentType = OctetStreamContentType
+	}
+
+	return data, contentType, nil
+}
+
+// GetBlob responds with the content of a blob.
+func (m EmulatorAPIServer) GetBlob(w http.ResponseWriter, r *http.Request) {
+	blob, ok := m.blobs.get(mux.Vars(r)["hash"])
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", blob.contentType)
+	_, _ = w.Write(blob.data)
+}
+
+// Blobs lists the blobs stored, ordered by hash.
+func (m EmulatorAPIServer) Blobs(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(m.blobs.list())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// RemoveBlob removes a blob.
+func (m EmulatorAPIServer) RemoveBlob(w http.ResponseWriter, r *http.Request) {
+	if !m.blobs.remove(mux.Vars(r)["hash"]) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,190 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestBlobs(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	data := []byte{0x89, 'P', 'N', 'G', 0x00, 0x01}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	request := func(t *testing.T, method string, path string, contentType string, body []byte) *http.Response {
+		req, err := http.NewRequest(method, api.URL+"/emulator"+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	put := func(t *testing.T, contentType string, body []byte) (int, utils.Blob) {
+		resp := request(t, http.MethodPut, "/blobs", contentType, body)
+		defer resp.Body.Close()
+
+		var blob utils.Blob
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&blob))
+		}
+		return resp.StatusCode, blob
+	}
+
+	t.Run("raw", func(t *testing.T) {
+		status, blob := put(t, "image/png", data)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, utils.Blob{Hash: hash, Size: len(data), ContentType: "image/png"}, blob)
+
+		resp := request(t, http.MethodGet, "/blobs/"+hash, "", nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, data, body)
+	})
+
+	t.Run("base64 and hex", func(t *testing.T) {
+		for _, req := range []utils.PutBlobRequest{
+			{Data: "iVBORwAB", ContentType: "image/png"},
+			{Data: "89504e470001", Encoding: "hex", ContentType: "image/png"},
+		} {
+			body, err := json.Marshal(req)
+			require.NoError(t, err)
+
+			status, blob := put(t, "application/json", body)
+			require.Equal(t, http.StatusOK, status)
+			assert.Equal(t, hash, blob.Hash)
+		}
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		status, _ := put(t, "application/json", []byte(`{"data": "zz", "encoding": "hex"}`))
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = put(t, "application/json", []byte(`{"data": "", "encoding": "base32"}`))
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		resp := request(t, http.MethodGet, "/blobs", "", nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var blobs []utils.Blob
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&blobs))
+		assert.Contains(t, blobs, utils.Blob{Hash: hash, Size: len(data), ContentType: "image/png"})
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		status, blob := put(t, "", []byte("removed"))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "application/octet-stream", blob.ContentType)
+
+		resp := request(t, http.MethodDelete, "/blobs/"+blob.Hash, "", nil)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = request(t, http.MethodGet, "/blobs/"+blob.Hash, "", nil)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp = request(t, http.MethodDelete, "/blobs/"+blob.Hash, "", nil)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestBlobsEviction(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil, utils.WithBlobStoreByteSize(10)))
+	defer api.Close()
+
+	put := func(t *testing.T, data string) (int, utils.Blob) {
+		req, err := http.NewRequest(http.MethodPut, api.URL+"/emulator/blobs", strings.NewReader(data))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "text/plain")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var blob utils.Blob
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&blob))
+		}
+		return resp.StatusCode, blob
+	}
+
+	get := func(t *testing.T, hash string) int {
+		resp, err := http.Get(api.URL + "/emulator/blobs/" + hash)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	_, first := put(t, "aaaa")
+	_, second := put(t, "bbbb")
+
+	// reading the first blob makes the second one the least recently used
+	require.Equal(t, http.StatusOK, get(t, first.Hash))
+
+	status, third := put(t, "cccc")
+	require.Equal(t, http.StatusOK, status)
+
+	assert.Equal(t, http.StatusOK, get(t, first.Hash))
+	assert.Equal(t, http.StatusNotFound, get(t, second.Hash))
+	assert.Equal(t, http.StatusOK, get(t, third.Hash))
+
+	status, _ = put(t, "larger than the store")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+}
@@ -0,0 +1,144 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+// WithSecondary enables the bridge endpoints between the emulator and the
+// given secondary emulator, which runs another chain in the same process.
+func WithSecondary(secondary emulator.Emulator) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		m.secondary = secondary
+	}
+}
+
+// BridgeChain describes one of the chains of a bridge.
+type BridgeChain struct {
+	ChainID        flowgo.ChainID `json:"chainId"`
+	ServiceAddress string         `json:"serviceAddress"`
+}
+
+// BridgeResponse describes the chains of the primary and secondary emulators.
+type BridgeResponse struct {
+	Primary   BridgeChain `json:"primary"`
+	Secondary BridgeChain `json:"secondary"`
+}
+
+// BridgedAddress is an address of one chain, and the address at the same
+// position in the address sequence of the other chain.
+type BridgedAddress struct {
+	From *AddressInfo `json:"from"`
+	To   *AddressInfo `json:"to"`
+}
+
+// BridgeAddress translates an address generated by the from chain to the
+// address generated at the same index by the to chain.
+func BridgeAddress(from, to flowgo.Chain, value string) (*BridgedAddress, error) {
+	fromInfo, err := DecodeAddress(from, value)
+	if err != nil {
+		return nil, err
+	}
+	if fromInfo.Index == nil {
+		return nil, fmt.Errorf("address %s is not valid for chain %s", value, from.ChainID())
+	}
+
+	address, err := to.AddressAtIndex(*fromInfo.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	toInfo, err := DecodeAddress(to, address.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	return &BridgedAddress{
+		From: fromInfo,
+		To:   toInfo,
+	}, nil
+}
+
+func bridgeChain(e emulator.Emulator) BridgeChain {
+	return BridgeChain{
+		ChainID:        e.GetNetworkParameters().ChainID,
+		ServiceAddress: "0x" + e.ServiceKey().Address.Hex(),
+	}
+}
+
+// Bridge describes the chains of the primary and the secondary emulator.
+func (m EmulatorAPIServer) Bridge(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.secondary == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err := json.NewEncoder(w).Encode(BridgeResponse{
+		Primary:   bridgeChain(m.emulator),
+		Secondary: bridgeChain(m.secondary),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// BridgeAddress translates an address of the primary chain to the secondary
+// chain, or an address of the secondary chain to the primary chain.
+func (m EmulatorAPIServer) BridgeAddress(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	if m.secondary == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	primary := m.emulator.GetNetworkParameters().ChainID.Chain()
+	secondary := m.secondary.GetNetworkParameters().ChainID.Chain()
+
+	from, to := primary, secondary
+	info, err := DecodeAddress(primary, vars["address"])
+	if err == nil && !info.Valid {
+		from, to = secondary, primary
+	}
+
+	bridged, err := BridgeAddress(from, to, vars["address"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(bridged)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestBridgeAddress(t *testing.T) {
+
+	t.Parallel()
+
+	emulatorChain := flowgo.Emulator.Chain()
+	testnetChain := flowgo.Testnet.Chain()
+
+	bridged, err := utils.BridgeAddress(emulatorChain, testnetChain, emulatorChain.ServiceAddress().Hex())
+	require.NoError(t, err)
+
+	assert.Equal(t, emulatorChain.ServiceAddress().HexWithPrefix(), bridged.From.Address)
+	assert.Equal(t, testnetChain.ServiceAddress().HexWithPrefix(), bridged.To.Address)
+	require.NotNil(t, bridged.To.Index)
+	assert.Equal(t, uint64(1), *bridged.To.Index)
+
+	_, err = utils.BridgeAddress(emulatorChain, testnetChain, testnetChain.ServiceAddress().Hex())
+	assert.Error(t, err)
+}
+
+func TestBridgeEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	primary, err := emulator.New()
+	require.NoError(t, err)
+
+	secondary, err := emulator.New(emulator.WithChainID(flowgo.Testnet))
+	require.NoError(t, err)
+
+	t.Run("without secondary", func(t *testing.T) {
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(primary, nil))
+		defer api.Close()
+
+		resp, err := http.Get(api.URL + "/emulator/bridge")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(primary, nil, utils.WithSecondary(secondary)))
+	defer api.Close()
+
+	t.Run("chains", func(t *testing.T) {
+		resp, err := http.Get(api.URL + "/emulator/bridge")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var bridge utils.BridgeResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&bridge))
+
+		assert.Equal(t, flowgo.Emulator, bridge.Primary.ChainID)
+		assert.Equal(t, flowgo.Emulator.Chain().ServiceAddress().HexWithPrefix(), bridge.Primary.ServiceAddress)
+		assert.Equal(t, flowgo.Testnet, bridge.Secondary.ChainID)
+		assert.Equal(t, flowgo.Testnet.Chain().ServiceAddress().HexWithPrefix(), bridge.Secondary.ServiceAddress)
+	})
+
+	getAddress := func(t *testing.T, address string) (int, *utils.BridgedAddress) {
+		resp, err := http.Get(api.URL + "/emulator/bridge/address/" + address)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var bridged utils.BridgedAddress
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&bridged))
+		return resp.StatusCode, &bridged
+	}
+
+	t.Run("primary to secondary", func(t *testing.T) {
+		status, bridged := getAddress(t, flowgo.Emulator.Chain().ServiceAddress().Hex())
+		require.Equal(t, http.StatusOK, status)
+
+		assert.Equal(t, flowgo.Emulator, bridged.From.ChainID)
+		assert.Equal(t, flowgo.Testnet, bridged.To.ChainID)
+		assert.Equal(t, flowgo.Testnet.Chain().ServiceAddress().HexWithPrefix(), bridged.To.Address)
+	})
+
+	t.Run("secondary to primary", func(t *testing.T) {
+		address, err := flowgo.Testnet.Chain().AddressAtIndex(5)
+		require.NoError(t, err)
+
+		status, bridged := getAddress(t, address.HexWithPrefix())
+		require.Equal(t, http.StatusOK, status)
+
+		expected, err := flowgo.Emulator.Chain().AddressAtIndex(5)
+		require.NoError(t, err)
+
+		assert.Equal(t, flowgo.Testnet, bridged.From.ChainID)
+		assert.Equal(t, flowgo.Emulator, bridged.To.ChainID)
+		assert.Equal(t, expected.HexWithPrefix(), bridged.To.Address)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		status, _ := getAddress(t, "ffffffffffffffff")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
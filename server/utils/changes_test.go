@@ -0,0 +1,100 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestChangesEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := b.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	changes := func(t *testing.T, query string) (int, *utils.ChangesResponse) {
+		resp, err := http.Get(api.URL + "/emulator/changes" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var response utils.ChangesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, &response
+	}
+
+	t.Run("poll", func(t *testing.T) {
+		status, response := changes(t, "?fromHeight=1&limit=2")
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Blocks, 2)
+		assert.Equal(t, uint64(1), response.Blocks[0].Height)
+		assert.Equal(t, uint64(2), response.Blocks[1].Height)
+		assert.Equal(t, uint64(3), response.NextHeight)
+		assert.Equal(t, uint64(3), response.LatestHeight)
+
+		status, response = changes(t, "?fromHeight=3")
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Blocks, 1)
+		assert.Equal(t, uint64(4), response.NextHeight)
+
+		status, response = changes(t, "?fromHeight=4")
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, response.Blocks)
+		assert.Equal(t, uint64(4), response.NextHeight)
+	})
+
+	t.Run("from genesis", func(t *testing.T) {
+		status, response := changes(t, "")
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Blocks, 4)
+		assert.Equal(t, uint64(0), response.Blocks[0].Height)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		status, _ := changes(t, "?fromHeight=latest")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = changes(t, "?limit=0")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = changes(t, "?limit=100000")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
@@ -0,0 +1,123 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestAccountCleanupEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewSDKAdapter(&logger, b)
+
+	address, err := adapter.CreateAccount(context.Background(), nil, nil, 0)
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	cleanup := func(t *testing.T, address string) *http.Response {
+		resp, err := http.Post(api.URL+"/emulator/accounts/"+address+"/cleanup", "", nil)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("invalid address", func(t *testing.T) {
+		resp := cleanup(t, "zz")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("core account", func(t *testing.T) {
+		resp := cleanup(t, b.ServiceKey().Address.Hex())
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		address, err := b.GetNetworkParameters().ChainID.Chain().AddressAtIndex(1000)
+		require.NoError(t, err)
+
+		resp := cleanup(t, address.Hex())
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("pending block not empty", func(t *testing.T) {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction {}`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(b.ServiceKey().Address)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		resp := cleanup(t, address.Hex())
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		_, _, err = b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		resp := cleanup(t, address.Hex())
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response utils.AccountCleanupResponse
+		err := json.NewDecoder(resp.Body).Decode(&response)
+		require.NoError(t, err)
+
+		block, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int(block.Header.Height), response.Height)
+		assert.Equal(t, block.ID().String(), response.BlockID)
+		assert.NotEmpty(t, response.TransactionID)
+	})
+}
@@ -0,0 +1,99 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestComputationReportEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	getReport := func(t *testing.T, api *httptest.Server, id string) (int, *emulator.ComputationReport) {
+		resp, err := http.Get(api.URL + "/emulator/computationReport/" + id)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var report emulator.ComputationReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		return resp.StatusCode, &report
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New(emulator.WithComputationReporting(true))
+		require.NoError(t, err)
+
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+		defer api.Close()
+
+		txID := sendTransaction(t, b, `
+			transaction {
+				execute {
+					var i = 0
+					while i < 100 {
+						i = i + 1
+					}
+				}
+			}
+		`)
+
+		status, report := getReport(t, api, txID.String())
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, txID, report.TransactionID)
+		assert.Positive(t, report.ComputationUsed)
+		assert.Equal(t, uint(100), report.Intensities["Loop"])
+
+		status, _ = getReport(t, api, "0000000000000000000000000000000000000000000000000000000000000001")
+		assert.Equal(t, http.StatusNotFound, status)
+
+		status, _ = getReport(t, api, "not-an-id")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+		defer api.Close()
+
+		txID := sendTransaction(t, b, `transaction { execute { log("hello") } }`)
+
+		status, _ := getReport(t, api, txID.String())
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
@@ -0,0 +1,78 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestConfigTestAccounts(t *testing.T) {
+
+	t.Parallel()
+
+	initialBalance, err := cadence.NewUFix64("10.0")
+	require.NoError(t, err)
+
+	serviceKey := emulator.DefaultServiceKey()
+
+	b, err := emulator.New(
+		emulator.WithServicePrivateKey(serviceKey.PrivateKey, serviceKey.SigAlgo, serviceKey.HashAlgo),
+		emulator.WithBootstrapAccounts(2, initialBalance),
+	)
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	resp, err := http.Get(api.URL + "/emulator/config")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var config struct {
+		TestAccounts []struct {
+			Address    string `json:"address"`
+			PrivateKey string `json:"private_key"`
+			PublicKey  string `json:"public_key"`
+			SigAlgo    string `json:"sig_algo"`
+			HashAlgo   string `json:"hash_algo"`
+		} `json:"test_accounts"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&config)
+	require.NoError(t, err)
+
+	require.Len(t, config.TestAccounts, 2)
+	for i, testAccount := range b.TestAccounts() {
+		assert.Equal(t, "0x"+testAccount.Address.Hex(), config.TestAccounts[i].Address)
+		assert.Equal(t, testAccount.PrivateKey.String(), config.TestAccounts[i].PrivateKey)
+		assert.Equal(t, testAccount.PrivateKey.PublicKey().String(), config.TestAccounts[i].PublicKey)
+		assert.Equal(t, "ECDSA_P256", config.TestAccounts[i].SigAlgo)
+		assert.Equal(t, "SHA3_256", config.TestAccounts[i].HashAlgo)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/templates"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestCoreContractsEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	decode := func(t *testing.T, resp *http.Response) []emulator.CoreContract {
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var contracts []emulator.CoreContract
+		err := json.NewDecoder(resp.Body).Decode(&contracts)
+		require.NoError(t, err)
+
+		return contracts
+	}
+
+	serviceAddress := b.ServiceKey().Address
+
+	account, err := b.GetAccount(context.Background(), flowgo.Address(serviceAddress))
+	require.NoError(t, err)
+
+	tx := templates.UpdateAccountContract(serviceAddress, templates.Contract{
+		Name:   "FlowServiceAccount",
+		Source: string(account.Contracts["FlowServiceAccount"]) + "\n// outdated\n",
+	}).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(serviceAddress, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(serviceAddress)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(serviceAddress, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+	require.NoError(t, err)
+
+	_, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	require.NoError(t, results[0].Error)
+
+	resp, err := http.Get(api.URL + "/emulator/coreContracts")
+	require.NoError(t, err)
+
+	for _, contract := range decode(t, resp) {
+		assert.Equal(t, contract.Name != "FlowServiceAccount", contract.UpToDate, contract.Name)
+	}
+
+	resp, err = http.Post(api.URL+"/emulator/coreContracts/upgrade", "", nil)
+	require.NoError(t, err)
+
+	for _, contract := range decode(t, resp) {
+		assert.True(t, contract.UpToDate, contract.Name)
+		assert.Equal(t, contract.Name == "FlowServiceAccount", contract.TransactionID != "", contract.Name)
+	}
+}
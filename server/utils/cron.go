@@ -0,0 +1,101 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/onflow/flow-emulator/server/cron"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// WithCron serves the jobs of the given scheduler.
+func WithCron(scheduler *cron.Scheduler) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		m.cron = scheduler
+	}
+}
+
+// CronJobs returns the jobs run periodically, oldest first.
+func (m EmulatorAPIServer) CronJobs(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.cron == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err := json.NewEncoder(w).Encode(m.cron.Jobs())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// AddCronJob registers a script or transaction to run every number of blocks or every duration.
+func (m EmulatorAPIServer) AddCronJob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.cron == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var spec cron.Spec
+	err := json.NewDecoder(r.Body).Decode(&spec)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	job, err := m.cron.Add(spec)
+	if err != nil {
+		var invalidArgumentErr *types.InvalidArgumentError
+		if errors.As(err, &invalidArgumentErr) {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(job)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// RemoveCronJob stops running a job.
+func (m EmulatorAPIServer) RemoveCronJob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.cron == nil || !m.cron.Remove(mux.Vars(r)["id"]) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
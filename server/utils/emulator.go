@@ -19,16 +19,31 @@
 package utils
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/gorilla/mux"
+	flowsdk "github.com/onflow/flow-go-sdk"
 	flowgo "github.com/onflow/flow-go/model/flow"
 	"golang.org/x/exp/slices"
 
 	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
 	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/cron"
+	"github.com/onflow/flow-emulator/server/watcher"
+	"github.com/onflow/flow-emulator/server/webhook"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/types"
 )
 
 type BlockResponse struct {
@@ -38,34 +53,186 @@ type BlockResponse struct {
 }
 
 type EmulatorAPIServer struct {
-	router   *mux.Router
-	emulator emulator.Emulator
-	adapter  *adapters.AccessAdapter
+	router    *mux.Router
+	emulator  emulator.Emulator
+	adapter   *adapters.AccessAdapter
+	ports     func() PortsStatus
+	secondary emulator.Emulator
+	// testVectors is nil unless test vectors are recorded
+	testVectors *TestVectorRecorder
+	// contractWatcher is nil unless contract files are watched
+	contractWatcher *watcher.ContractWatcher
+	// logStream is nil unless the server logs are streamed
+	logStream *LogStream
+	// cron is nil unless jobs can be run periodically
+	cron *cron.Scheduler
+	// webhooks is nil unless events can be posted to webhooks
+	webhooks *webhook.Dispatcher
+	// transactionMaxByteSize bounds transactions sent or uploaded in chunks
+	transactionMaxByteSize uint64
+	uploads                *transactionUploads
+	blobs                  *blobStore
 }
 
-func NewEmulatorAPIServer(emulator emulator.Emulator, adapter *adapters.AccessAdapter) *EmulatorAPIServer {
+// EmulatorAPIServerOption is a function applying a change to an admin API server.
+type EmulatorAPIServerOption func(*EmulatorAPIServer)
+
+// WithPorts reports the ports returned by the given function in the status endpoint.
+func WithPorts(ports func() PortsStatus) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		m.ports = ports
+	}
+}
+
+func NewEmulatorAPIServer(emulator emulator.Emulator, adapter *adapters.AccessAdapter, opts ...EmulatorAPIServerOption) *EmulatorAPIServer {
 	router := mux.NewRouter().StrictSlash(true)
 	r := &EmulatorAPIServer{router: router,
-		emulator: emulator,
-		adapter:  adapter,
+		emulator:               emulator,
+		adapter:                adapter,
+		transactionMaxByteSize: flowgo.DefaultMaxTransactionByteSize,
+		uploads:                newTransactionUploads(),
+		blobs:                  newBlobStore(),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	// every route is served under the current API version, and without a
+	// version for tools built against the unversioned API
+	for _, prefix := range []string{EmulatorApiV1Prefix, ""} {
+		for _, route := range r.routes() {
+			var routeHandler http.Handler = route.Handler
+			if !route.Stream {
+				routeHandler = negotiateContent(routeHandler)
+			}
+			handler := router.Handle(prefix+"/emulator"+route.Path, routeHandler)
+			if len(route.Methods) > 0 {
+				handler.Methods(route.Methods...)
+			}
+		}
+	}
+
+	return r
+}
 
-	router.HandleFunc("/emulator/newBlock", r.CommitBlock)
+// Route is an admin API endpoint, relative to the API prefix.
+type Route struct {
+	Path    string
+	Methods []string
+	Handler http.HandlerFunc
+	// Stream routes write their response as it is produced,
+	// so it is not buffered to be negotiated as CBOR.
+	Stream bool
+}
 
-	router.HandleFunc("/emulator/rollback", r.Rollback).Methods("POST")
+func (m EmulatorAPIServer) routes() []Route {
+	return []Route{
+		{Path: "/newBlock", Handler: m.CommitBlock},
+		{Path: "/pendingBlock", Methods: []string{"GET"}, Handler: m.PendingBlock},
+		{Path: "/pendingBlock/transactions/{id}", Methods: []string{"DELETE"}, Handler: m.RemovePendingTransaction},
 
-	router.HandleFunc("/emulator/snapshots", r.SnapshotCreate).Methods("POST")
-	router.HandleFunc("/emulator/snapshots", r.SnapshotList).Methods("GET")
-	router.HandleFunc("/emulator/snapshots/{name}", r.SnapshotJump).Methods("PUT")
+		{Path: "/rollback", Methods: []string{"POST"}, Handler: m.Rollback},
+		{Path: "/state", Methods: []string{"DELETE"}, Handler: m.ResetState},
+		{Path: "/state/standby", Methods: []string{"POST"}, Handler: m.EnableStandby},
 
-	router.HandleFunc("/emulator/logs/{id}", r.Logs).Methods("GET")
+		{Path: "/advanceTime", Methods: []string{"POST"}, Handler: m.AdvanceTime},
 
-	router.HandleFunc("/emulator/config", r.Config)
+		{Path: "/timetravel", Methods: []string{"GET", "POST", "DELETE"}, Handler: m.TimeTravel},
 
-	router.HandleFunc("/emulator/codeCoverage", r.CodeCoverage).Methods("GET")
-	router.HandleFunc("/emulator/codeCoverage/reset", r.ResetCodeCoverage).Methods("PUT")
+		{Path: "/snapshots", Methods: []string{"POST"}, Handler: m.SnapshotCreate},
+		{Path: "/snapshots", Methods: []string{"GET"}, Handler: m.SnapshotList},
+		{Path: "/snapshots/{name}", Methods: []string{"PUT"}, Handler: m.SnapshotJump},
+		{Path: "/snapshots/{name}", Methods: []string{"DELETE"}, Handler: m.SnapshotDelete},
 
-	return r
+		{Path: "/storage", Methods: []string{"PUT"}, Handler: m.SwitchStorage},
+		{Path: "/storage/compact", Methods: []string{"POST"}, Handler: m.CompactStorage},
+		{Path: "/checkpoint", Methods: []string{"POST"}, Handler: m.Checkpoint},
+
+		{Path: "/pins", Methods: []string{"GET"}, Handler: m.Pins},
+		{Path: "/pins/transactions/{id}", Methods: []string{"PUT"}, Handler: m.PinTransaction},
+		{Path: "/pins/{height}", Methods: []string{"PUT"}, Handler: m.PinBlockHeight},
+		{Path: "/pins/{height}", Methods: []string{"DELETE"}, Handler: m.UnpinBlockHeight},
+
+		{Path: "/transactions", Methods: []string{"POST"}, Handler: m.SendTransaction},
+		{Path: "/transactions/uploads", Methods: []string{"POST"}, Handler: m.StartTransactionUpload},
+		{Path: "/transactions/uploads/{upload}", Methods: []string{"PATCH"}, Handler: m.AppendTransactionUpload},
+		{Path: "/transactions/uploads/{upload}", Methods: []string{"DELETE"}, Handler: m.CancelTransactionUpload},
+		{Path: "/transactions/uploads/{upload}/submit", Methods: []string{"POST"}, Handler: m.SubmitTransactionUpload},
+		{Path: "/transactions/{id}/logs", Methods: []string{"GET"}, Handler: m.Logs},
+		{Path: "/transactions/{id}/error", Methods: []string{"GET"}, Handler: m.TransactionError},
+		{Path: "/referenceBlocks", Methods: []string{"GET"}, Handler: m.ReferenceBlockWindow},
+		// deprecated, superseded by /transactions/{id}/logs
+		{Path: "/logs/{id}", Methods: []string{"GET"}, Handler: m.Logs},
+		{Path: "/logs", Methods: []string{"GET"}, Handler: m.StreamLogs, Stream: true},
+
+		{Path: "/accounts/{address}/inbox", Methods: []string{"GET"}, Handler: m.AccountInbox},
+		{Path: "/accounts/{address}/cleanup", Methods: []string{"POST"}, Handler: m.AccountCleanup},
+		{Path: "/accounts/{address}/vaults", Methods: []string{"GET"}, Handler: m.AccountVaults},
+		{Path: "/accounts/{address}/vaults/repair", Methods: []string{"POST"}, Handler: m.RepairAccountVaults},
+		{Path: "/accounts/{address}/fixture", Methods: []string{"GET"}, Handler: m.ExportAccountFixture},
+		{Path: "/accounts/fixtures", Methods: []string{"POST"}, Handler: m.ImportAccountFixture},
+		{Path: "/storages/{address}", Methods: []string{"GET"}, Handler: m.AccountStorage},
+
+		{Path: "/coreContracts", Methods: []string{"GET"}, Handler: m.CoreContracts},
+		{Path: "/coreContracts/upgrade", Methods: []string{"POST"}, Handler: m.UpgradeCoreContracts},
+
+		{Path: "/blocks/{id}/dependencies", Methods: []string{"GET"}, Handler: m.BlockDependencies},
+		{Path: "/changes", Methods: []string{"GET"}, Handler: m.Changes},
+
+		{Path: "/expectations", Methods: []string{"GET"}, Handler: m.EventExpectations},
+		{Path: "/expectations", Methods: []string{"POST"}, Handler: m.ExpectEvent},
+		{Path: "/expectations/unmatched", Methods: []string{"GET"}, Handler: m.UnmatchedEventExpectations},
+		{Path: "/expectations/unmatched", Methods: []string{"DELETE"}, Handler: m.ClearUnmatchedEventExpectations},
+
+		{Path: "/faults", Methods: []string{"GET"}, Handler: m.FaultRules},
+		{Path: "/faults", Methods: []string{"POST"}, Handler: m.AddFaultRule},
+		{Path: "/faults/{id}", Methods: []string{"DELETE"}, Handler: m.RemoveFaultRule},
+
+		{Path: "/computationReport/{id}", Methods: []string{"GET"}, Handler: m.ComputationReport},
+
+		{Path: "/fees", Methods: []string{"GET"}, Handler: m.Fees},
+		{Path: "/fees/parameters", Methods: []string{"PUT"}, Handler: m.SetFeeParameters},
+		{Path: "/fees/executionEffortWeights", Methods: []string{"PUT"}, Handler: m.SetExecutionEffortWeights},
+
+		{Path: "/bridge", Methods: []string{"GET"}, Handler: m.Bridge},
+		{Path: "/bridge/address/{address}", Methods: []string{"GET"}, Handler: m.BridgeAddress},
+
+		{Path: "/sync/blocks/{height}", Methods: []string{"GET"}, Handler: m.CommittedBlock},
+
+		{Path: "/blobs", Methods: []string{"GET"}, Handler: m.Blobs},
+		{Path: "/blobs", Methods: []string{"PUT"}, Handler: m.PutBlob},
+		{Path: "/blobs/{hash}", Methods: []string{"GET"}, Handler: m.GetBlob},
+		{Path: "/blobs/{hash}", Methods: []string{"DELETE"}, Handler: m.RemoveBlob},
+
+		{Path: "/config", Handler: m.Config},
+
+		{Path: "/status", Methods: []string{"GET"}, Handler: m.Status},
+		{Path: "/stats/fvm", Methods: []string{"GET"}, Handler: m.FVMStats},
+
+		{Path: "/codeCoverage", Methods: []string{"GET"}, Handler: m.CodeCoverage},
+		{Path: "/codeCoverage", Methods: []string{"DELETE"}, Handler: m.ResetCodeCoverage},
+		{Path: "/codeCoverage/reset", Methods: []string{"PUT"}, Handler: m.ResetCodeCoverage},
+
+		{Path: "/contracts/reloads", Methods: []string{"GET"}, Handler: m.ContractReloads},
+
+		{Path: "/cron", Methods: []string{"GET"}, Handler: m.CronJobs},
+		{Path: "/cron", Methods: []string{"POST"}, Handler: m.AddCronJob},
+		{Path: "/cron/{id}", Methods: []string{"DELETE"}, Handler: m.RemoveCronJob},
+
+		{Path: "/testVectors", Methods: []string{"GET"}, Handler: m.TestVectors},
+		{Path: "/testVectors", Methods: []string{"DELETE"}, Handler: m.ResetTestVectors},
+
+		{Path: "/profiler/contracts", Methods: []string{"GET"}, Handler: m.ContractProfiles},
+		{Path: "/profiler/contracts/reset", Methods: []string{"PUT"}, Handler: m.ResetContractProfiles},
+
+		{Path: "/utils/address/{value}", Methods: []string{"GET"}, Handler: m.Address},
+		{Path: "/utils/validateArguments", Methods: []string{"POST"}, Handler: m.ValidateArguments},
+		{Path: "/utils/entryPoint", Methods: []string{"POST"}, Handler: m.EntryPoint},
+
+		{Path: "/webhooks", Methods: []string{"GET"}, Handler: m.Webhooks},
+		{Path: "/webhooks", Methods: []string{"POST"}, Handler: m.AddWebhook},
+		{Path: "/webhooks/{id}", Methods: []string{"DELETE"}, Handler: m.RemoveWebhook},
+	}
 }
 
 func (m EmulatorAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -73,27 +240,114 @@ func (m EmulatorAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (m EmulatorAPIServer) Config(w http.ResponseWriter, _ *http.Request) {
+	type TestAccountInfo struct {
+		Address    string `json:"address"`
+		PrivateKey string `json:"private_key"`
+		PublicKey  string `json:"public_key"`
+		SigAlgo    string `json:"sig_algo"`
+		HashAlgo   string `json:"hash_algo"`
+	}
+
 	type ConfigInfo struct {
-		ServiceKey string `json:"service_key"`
+		ServiceKey   string            `json:"service_key"`
+		TestAccounts []TestAccountInfo `json:"test_accounts"`
 	}
 
 	c := ConfigInfo{
-		ServiceKey: m.emulator.ServiceKey().PublicKey.String(),
+		ServiceKey:   m.emulator.ServiceKey().PublicKey.String(),
+		TestAccounts: []TestAccountInfo{},
+	}
+
+	for _, account := range m.emulator.TestAccounts() {
+		c.TestAccounts = append(c.TestAccounts, TestAccountInfo{
+			Address:    "0x" + account.Address.Hex(),
+			PrivateKey: account.PrivateKey.String(),
+			PublicKey:  account.PrivateKey.PublicKey().String(),
+			SigAlgo:    account.SigAlgo.String(),
+			HashAlgo:   account.HashAlgo.String(),
+		})
 	}
 
 	s, _ := json.MarshalIndent(c, "", "\t")
 	_, _ = w.Write(s)
 }
 
-func (m EmulatorAPIServer) CommitBlock(w http.ResponseWriter, r *http.Request) {
+func (m EmulatorAPIServer) Status(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	status, err := GetStatus(r.Context(), m.emulator)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if m.ports != nil {
+		ports := m.ports()
+		status.Ports = &ports
+	}
+
+	err = json.NewEncoder(w).Encode(status)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// ReferenceBlockWindow reports the range of blocks new transactions can reference without being expired.
+func (m EmulatorAPIServer) ReferenceBlockWindow(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	_, err := m.emulator.CommitBlock()
+
+	window, err := m.emulator.ReferenceBlockWindow(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(window)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+}
+
+// CommitBlock commits the pending block. With a timestamp, given in RFC 3339 format or
+// as Unix seconds, the pending block is executed and committed at that time.
+func (m EmulatorAPIServer) CommitBlock(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if value := r.FormValue("timestamp"); value != "" {
+		timestamp, err := parseBlockTimestamp(value)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		_, _, err = m.emulator.ExecuteAndCommitBlockAt(timestamp)
+		if err != nil {
+			var invalidErr *types.InvalidArgumentError
+			var midExecutionErr *types.PendingBlockMidExecutionError
+			switch {
+			case errors.As(err, &invalidErr):
+				w.WriteHeader(http.StatusBadRequest)
+			case errors.As(err, &midExecutionErr):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+	} else {
+		_, err := m.emulator.CommitBlock()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+	}
 
-	block, err := m.emulator.GetLatestBlock()
+	block, err := m.emulator.GetLatestBlock(r.Context())
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -112,6 +366,21 @@ func (m EmulatorAPIServer) CommitBlock(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// parseBlockTimestamp parses a timestamp in RFC 3339 format or as Unix seconds.
+func parseBlockTimestamp(value string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q, expected RFC 3339 or Unix seconds", value)
+	}
+
+	return timestamp, nil
+}
+
 func (m EmulatorAPIServer) Rollback(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	if r.FormValue("height") == "" {
@@ -132,10 +401,47 @@ func (m EmulatorAPIServer) Rollback(w http.ResponseWriter, r *http.Request) {
 
 }
 
-func (m EmulatorAPIServer) SnapshotList(w http.ResponseWriter, _ *http.Request) {
+// TimeResponse reports the timestamp of the pending block.
+type TimeResponse struct {
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AdvanceTime moves the clock of the emulator forward by the number of seconds
+// given by the seconds parameter, so that the next blocks are timestamped later.
+func (m EmulatorAPIServer) AdvanceTime(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	snapshots, err := m.emulator.Snapshots()
+	seconds, err := strconv.ParseUint(r.FormValue("seconds"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	timestamp, err := m.emulator.AdvanceTime(time.Duration(seconds) * time.Second)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(TimeResponse{Timestamp: timestamp})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// SnapshotList returns the names of the snapshots, or the snapshots with their
+// metadata, oldest first, if the details query parameter is true.
+func (m EmulatorAPIServer) SnapshotList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var snapshots any
+	var err error
+	if details, _ := strconv.ParseBool(r.URL.Query().Get("details")); details {
+		snapshots, err = m.emulator.SnapshotInfos()
+	} else {
+		snapshots, err = m.emulator.Snapshots()
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -152,9 +458,9 @@ func (m EmulatorAPIServer) SnapshotList(w http.ResponseWriter, _ *http.Request)
 
 }
 
-func (m EmulatorAPIServer) latestBlockResponse(name string, w http.ResponseWriter) {
+func (m EmulatorAPIServer) latestBlockResponse(ctx context.Context, name string, w http.ResponseWriter) {
 
-	block, err := m.emulator.GetLatestBlock()
+	block, err := m.emulator.GetLatestBlock(ctx)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -199,7 +505,39 @@ func (m EmulatorAPIServer) SnapshotJump(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	m.latestBlockResponse(name, w)
+	m.latestBlockResponse(r.Context(), name, w)
+}
+
+// SnapshotDelete removes a snapshot. The snapshot the emulator runs on can
+// not be deleted.
+func (m EmulatorAPIServer) SnapshotDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+	name := vars["name"]
+
+	snapshots, err := m.emulator.Snapshots()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !slices.Contains(snapshots, name) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err = m.emulator.DeleteSnapshot(name)
+	if errors.Is(err, storage.ErrSnapshotLoaded) {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (m EmulatorAPIServer) SnapshotCreate(w http.ResponseWriter, r *http.Request) {
@@ -221,12 +559,110 @@ func (m EmulatorAPIServer) SnapshotCreate(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err = m.emulator.CreateSnapshot(name)
+	err = m.emulator.CreateSnapshot(name, r.FormValue("description"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	m.latestBlockResponse(r.Context(), name, w)
+}
+
+// SwitchStorage copies the state to a store opened with a registered storage
+// provider, and serves the emulator from it from then on.
+func (m EmulatorAPIServer) SwitchStorage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	provider := r.FormValue("provider")
+
+	if provider == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	target, err := storage.Open(provider, r.FormValue("dsn"))
+	if err != nil {
+		var unknownErr *storage.UnknownProviderError
+		if errors.As(err, &unknownErr) {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	previous, err := m.emulator.SwitchStorage(r.Context(), target)
+	if err != nil {
+		closeStore(target)
+
+		var pendingErr *types.PendingBlockNotEmptyError
+		if errors.As(err, &pendingErr) || errors.Is(err, storage.ErrStoreNotEmpty) {
+			w.WriteHeader(http.StatusConflict)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	closeStore(previous)
+
+	m.latestBlockResponse(r.Context(), provider, w)
+}
+
+// Checkpoint saves the state to the checkpoint the storage was bootstrapped from.
+func (m EmulatorAPIServer) Checkpoint(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := m.emulator.Checkpoint(r.Context())
+	if errors.Is(err, storage.ErrCheckpointNotSupported) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	m.latestBlockResponse(r.Context(), "checkpoint", w)
+}
+
+// StorageCompaction is the response of the storage compaction endpoint.
+type StorageCompaction struct {
+	// ReclaimedBytes is the disk space reclaimed, which is negative if the storage grew.
+	ReclaimedBytes int64 `json:"reclaimedBytes"`
+}
+
+// CompactStorage reclaims the disk space of data deleted from the storage, e.g. of
+// pruned register versions.
+func (m EmulatorAPIServer) CompactStorage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	reclaimed, err := m.emulator.CompactStorage(r.Context())
+	if errors.Is(err, storage.ErrCompactionNotSupported) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(StorageCompaction{ReclaimedBytes: reclaimed})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	m.latestBlockResponse(name, w)
+}
+
+// closeStore releases the database handles of stores which hold any.
+func closeStore(store storage.Store) {
+	if closer, ok := store.(io.Closer); ok {
+		_ = closer.Close()
+	}
 }
 
 func (m EmulatorAPIServer) CodeCoverage(w http.ResponseWriter, r *http.Request) {
@@ -245,29 +681,755 @@ func (m EmulatorAPIServer) ResetCodeCoverage(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusOK)
 }
 
-func (m EmulatorAPIServer) Logs(w http.ResponseWriter, r *http.Request) {
+func (m EmulatorAPIServer) BlockDependencies(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	vars := mux.Vars(r)
-	id := vars["id"]
 
-	identifier, err := flowgo.HexStringToIdentifier(id)
+	blockID, err := flowgo.HexStringToIdentifier(vars["id"])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	logs, err := m.emulator.GetLogs(identifier)
+	dependencies, err := m.emulator.GetBlockDependencies(r.Context(), blockID)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		var notFoundErr types.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
 		return
 	}
 
-	if len(logs) == 0 {
-		err = json.NewEncoder(w).Encode([]string{})
-	} else {
-		err = json.NewEncoder(w).Encode(logs)
+	err = json.NewEncoder(w).Encode(dependencies)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// defaultChangesLimit and maxChangesLimit bound the number of blocks of a changes response.
+const (
+	defaultChangesLimit = 100
+	maxChangesLimit     = 1000
+)
+
+// ChangesResponse lists the changes of committed blocks.
+type ChangesResponse struct {
+	Blocks []emulator.BlockChanges `json:"blocks"`
+	// NextHeight is the fromHeight of the next request, to poll for the following changes.
+	NextHeight uint64 `json:"nextHeight"`
+	// LatestHeight is the height of the latest block when the changes were listed.
+	LatestHeight uint64 `json:"latestHeight"`
+}
+
+// Changes lists the accounts created, contracts deployed, updated and removed,
+// and registers written by each committed block from the height given by the
+// fromHeight parameter, oldest first, at most limit blocks.
+func (m EmulatorAPIServer) Changes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var fromHeight uint64
+	if value := r.FormValue("fromHeight"); value != "" {
+		var err error
+		fromHeight, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid fromHeight"})
+			return
+		}
+	}
+
+	limit := defaultChangesLimit
+	if value := r.FormValue("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxChangesLimit {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"error": fmt.Sprintf("limit must be between 1 and %d", maxChangesLimit),
+			})
+			return
+		}
+	}
+
+	latestBlock, err := m.emulator.GetLatestBlock(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	blocks, err := m.emulator.GetChanges(r.Context(), fromHeight, limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	response := ChangesResponse{
+		Blocks:       blocks,
+		NextHeight:   fromHeight,
+		LatestHeight: latestBlock.Header.Height,
+	}
+	if len(blocks) > 0 {
+		last := blocks[len(blocks)-1].Height
+		response.NextHeight = last + 1
+		if last > response.LatestHeight {
+			response.LatestHeight = last
+		}
+	}
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// ComputationReport returns the computation used by a transaction, broken down
+// by computation kind. It is only recorded with computation reporting enabled.
+func (m EmulatorAPIServer) ComputationReport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	txID, err := flowgo.HexStringToIdentifier(vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	report, err := m.emulator.GetComputationReport(r.Context(), txID)
+	if err != nil {
+		var notFoundErr types.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(report)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (m EmulatorAPIServer) FVMStats(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(m.emulator.FVMStats())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (m EmulatorAPIServer) CommittedBlock(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	height, err := strconv.ParseUint(vars["height"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	block, err := m.emulator.GetCommittedBlock(r.Context(), height)
+	if err != nil {
+		var notFoundErr types.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	encoded, err := cbor.Marshal(block)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", SyncContentType)
+	_, _ = w.Write(encoded)
+}
+
+func (m EmulatorAPIServer) ContractProfiles(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(m.emulator.ContractProfiles())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (m EmulatorAPIServer) ResetContractProfiles(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	m.emulator.ResetContractProfiles()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m EmulatorAPIServer) Address(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	chain := m.emulator.GetNetworkParameters().ChainID.Chain()
+
+	info, err := DecodeAddress(chain, vars["value"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(info)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// maxRequestOverhead is the size of a transactions request in bytes, in addition to the transaction.
+const maxRequestOverhead = 1024
+
+// SendTransactionRequest is the body of a transactions request.
+type SendTransactionRequest struct {
+	// Transaction is the hex encoded RLP encoding of the signed transaction.
+	Transaction string `json:"transaction"`
+	// Priority orders the transaction in the pending block, highest first.
+	Priority int `json:"priority"`
+}
+
+// SendTransaction submits a signed transaction with a priority, ordering it in the
+// pending block ahead of the transactions with a lower priority.
+//
+// The transaction is either sent as a SendTransactionRequest, or streamed as the raw
+// RLP encoding with the application/octet-stream content type, and the priority as
+// query parameter.
+func (m EmulatorAPIServer) SendTransaction(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if hasMediaType(r.Header.Get("Content-Type"), OctetStreamContentType) {
+		m.sendTransactionStream(w, r)
+		return
+	}
+
+	// the transaction is hex encoded
+	body := http.MaxBytesReader(w, r.Body, int64(2*m.transactionMaxByteSize+maxRequestOverhead))
+
+	var request SendTransactionRequest
+	err := json.NewDecoder(body).Decode(&request)
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	encoded, err := hex.DecodeString(strings.TrimPrefix(request.Transaction, "0x"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	m.submitTransaction(w, r, encoded, request.Priority)
+}
+
+func (m EmulatorAPIServer) sendTransactionStream(w http.ResponseWriter, r *http.Request) {
+	priority := 0
+	if value := r.URL.Query().Get("priority"); value != "" {
+		var err error
+		priority, err = strconv.Atoi(value)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("invalid priority: %s", value)})
+			return
+		}
+	}
+
+	encoded, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(m.transactionMaxByteSize)))
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	m.submitTransaction(w, r, encoded, priority)
+}
+
+// submitTransaction decodes the RLP encoding of a signed transaction and submits it.
+func (m EmulatorAPIServer) submitTransaction(w http.ResponseWriter, r *http.Request, encoded []byte, priority int) {
+	tx, err := flowsdk.DecodeTransaction(encoded)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	flowTx := convert.SDKTransactionToFlow(*tx)
+
+	err = m.emulator.SendTransactionWithPriority(r.Context(), flowTx, priority)
+	if err != nil {
+		var validationErr types.TransactionValidationError
+		var midExecutionErr *types.PendingBlockMidExecutionError
+		var readOnlyErr *types.ReadOnlyError
+		switch {
+		case errors.As(err, &midExecutionErr), errors.As(err, &readOnlyErr):
+			w.WriteHeader(http.StatusConflict)
+		case errors.As(err, &validationErr):
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(map[string]string{"id": flowTx.ID().String()})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeBodyError reports an error reading a request body, which may have exceeded its maximum size.
+func writeBodyError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("request body exceeds the maximum size of %d bytes", maxBytesErr.Limit),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// ValidateArgumentsRequest is the body of a validateArguments request.
+type ValidateArgumentsRequest struct {
+	// Script is the source of a script or transaction.
+	Script string `json:"script"`
+	// Arguments are the JSON-CDC encoded arguments.
+	Arguments []json.RawMessage `json:"arguments"`
+}
+
+func (m EmulatorAPIServer) ValidateArguments(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var request ValidateArgumentsRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	arguments := make([][]byte, len(request.Arguments))
+	for i, argument := range request.Arguments {
+		arguments[i] = argument
+	}
+
+	validation, err := m.emulator.ValidateArguments(r.Context(), []byte(request.Script), arguments)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(validation)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// EntryPointRequest is the body of an entryPoint request.
+type EntryPointRequest struct {
+	// Script is the source of a script or transaction.
+	Script string `json:"script"`
+}
+
+func (m EmulatorAPIServer) EntryPoint(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var request EntryPointRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	entryPoint, err := emulator.IntrospectEntryPoint([]byte(request.Script))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(entryPoint)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// TransactionError returns the error of a committed transaction, classified by
+// a stable error code with a remediation hint when it is a common failure.
+// Transactions which succeeded have no error.
+func (m EmulatorAPIServer) TransactionError(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	identifier, err := flowgo.HexStringToIdentifier(vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	txErr, err := m.emulator.GetTransactionError(r.Context(), identifier)
+	var notFoundErr *types.TransactionNotFoundError
+	if errors.As(err, &notFoundErr) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if txErr == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(txErr)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// AccountInbox returns the capabilities the account published to its inbox
+// which have not been claimed yet.
+func (m EmulatorAPIServer) AccountInbox(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	chain := m.emulator.GetNetworkParameters().ChainID.Chain()
+
+	address := flowgo.HexToAddress(vars["address"])
+	if !chain.IsValid(address) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	entries, err := m.emulator.AccountInbox(r.Context(), address)
+	var notFoundErr *types.AccountNotFoundError
+	if errors.As(err, &notFoundErr) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(entries)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// AccountStorage returns a page of the values stored in the account,
+// optionally filtered by domain and type.
+func (m EmulatorAPIServer) AccountStorage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	chain := m.emulator.GetNetworkParameters().ChainID.Chain()
+
+	address := flowgo.HexToAddress(vars["address"])
+	if !chain.IsValid(address) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	params := r.URL.Query()
+
+	query := emulator.AccountStorageQuery{
+		Type:   params.Get("type"),
+		Cursor: params.Get("cursor"),
+	}
+
+	for _, domains := range params["domain"] {
+		for _, domain := range strings.Split(domains, ",") {
+			if domain != "" {
+				query.Domains = append(query.Domains, domain)
+			}
+		}
+	}
+
+	if limit := params.Get("limit"); limit != "" {
+		var err error
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := m.emulator.GetAccountStorage(r.Context(), address, query)
+	var notFoundErr *types.AccountNotFoundError
+	if errors.As(err, &notFoundErr) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var invalidErr *types.InvalidArgumentError
+	if errors.As(err, &invalidErr) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// AccountCleanupResponse reports the transaction and block of an account cleanup.
+type AccountCleanupResponse struct {
+	TransactionID string `json:"transactionId"`
+	Height        int    `json:"height"`
+	BlockID       string `json:"blockId"`
+}
+
+// AccountCleanup removes the contracts, links and stored values of the account
+// in a block of its own, keeping its keys and FLOW vault.
+func (m EmulatorAPIServer) AccountCleanup(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	chain := m.emulator.GetNetworkParameters().ChainID.Chain()
+
+	address := flowgo.HexToAddress(vars["address"])
+	if !chain.IsValid(address) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := m.emulator.CleanupAccount(r.Context(), address)
+	if err != nil {
+		var notFoundErr *types.AccountNotFoundError
+		var invalidErr *types.InvalidArgumentError
+		var notEmptyErr *types.PendingBlockNotEmptyError
+		var readOnlyErr *types.ReadOnlyError
+		switch {
+		case errors.As(err, &notFoundErr):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.As(err, &invalidErr):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.As(err, &notEmptyErr), errors.As(err, &readOnlyErr):
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	if !result.Succeeded() {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": result.Error.Error()})
+		return
+	}
+
+	block, err := m.emulator.GetLatestBlock(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(AccountCleanupResponse{
+		TransactionID: result.TransactionID.String(),
+		Height:        int(block.Header.Height),
+		BlockID:       block.ID().String(),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// AccountVaults returns the fungible token vaults of the account,
+// and whether their public receiver and balance capabilities are linked.
+func (m EmulatorAPIServer) AccountVaults(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	chain := m.emulator.GetNetworkParameters().ChainID.Chain()
+
+	address := flowgo.HexToAddress(vars["address"])
+	if !chain.IsValid(address) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	vaults, err := m.emulator.CheckVaults(r.Context(), address)
+	var notFoundErr *types.AccountNotFoundError
+	if errors.As(err, &notFoundErr) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(vaults)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// VaultRepairResponse reports the vaults of an account after a repair,
+// and the repair transaction, if anything needed to be repaired.
+type VaultRepairResponse struct {
+	TransactionID string           `json:"transactionId,omitempty"`
+	Vaults        []emulator.Vault `json:"vaults"`
+}
+
+// RepairAccountVaults relinks the broken or missing receiver and balance capabilities
+// of the vaults of the account, in a block of its own.
+func (m EmulatorAPIServer) RepairAccountVaults(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	chain := m.emulator.GetNetworkParameters().ChainID.Chain()
+
+	address := flowgo.HexToAddress(vars["address"])
+	if !chain.IsValid(address) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	vaults, result, err := m.emulator.RepairVaults(r.Context(), address)
+	if err != nil {
+		var notFoundErr *types.AccountNotFoundError
+		var invalidErr *types.InvalidArgumentError
+		var notEmptyErr *types.PendingBlockNotEmptyError
+		var readOnlyErr *types.ReadOnlyError
+		switch {
+		case errors.As(err, &notFoundErr):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.As(err, &invalidErr):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.As(err, &notEmptyErr), errors.As(err, &readOnlyErr):
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	response := VaultRepairResponse{Vaults: vaults}
+	if result != nil {
+		if !result.Succeeded() {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": result.Error.Error()})
+			return
+		}
+		response.TransactionID = result.TransactionID.String()
+	}
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// CoreContracts lists the core contracts, and whether they are the versions bundled with the emulator.
+func (m EmulatorAPIServer) CoreContracts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	contracts, err := m.emulator.CoreContracts(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(contracts)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpgradeCoreContracts updates the outdated core contracts to the versions bundled
+// with the emulator, in a block of its own.
+func (m EmulatorAPIServer) UpgradeCoreContracts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	contracts, err := m.emulator.UpgradeCoreContracts(r.Context())
+	if err != nil {
+		var notEmptyErr *types.PendingBlockNotEmptyError
+		var readOnlyErr *types.ReadOnlyError
+		switch {
+		case errors.As(err, &notEmptyErr), errors.As(err, &readOnlyErr):
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(contracts)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Logs returns the Cadence logs of a committed transaction, persisted with its result.
+func (m EmulatorAPIServer) Logs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	identifier, err := flowgo.HexStringToIdentifier(id)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	logs, err := m.emulator.GetTransactionLogs(r.Context(), identifier)
+	var notFoundErr *types.TransactionNotFoundError
+	if errors.As(err, &notFoundErr) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 
+	err = json.NewEncoder(w).Encode(logs)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -0,0 +1,88 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// EventExpectations returns the events the pending block is expected to emit, oldest first.
+func (m EmulatorAPIServer) EventExpectations(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(m.emulator.EventExpectations())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// UnmatchedEventExpectations returns the expectations reported as errors which were not
+// matched by the block committed after them, oldest first.
+func (m EmulatorAPIServer) UnmatchedEventExpectations(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(m.emulator.UnmatchedEventExpectations())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// ClearUnmatchedEventExpectations discards the unmatched expectations.
+func (m EmulatorAPIServer) ClearUnmatchedEventExpectations(w http.ResponseWriter, _ *http.Request) {
+	m.emulator.ClearUnmatchedEventExpectations()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ExpectEvent registers an event the pending block is expected to emit.
+func (m EmulatorAPIServer) ExpectEvent(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var matcher emulator.EventMatcher
+	err := json.NewDecoder(r.Body).Decode(&matcher)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	expectation, err := m.emulator.ExpectEvent(matcher)
+	if err != nil {
+		var invalidArgumentErr *types.InvalidArgumentError
+		if errors.As(err, &invalidArgumentErr) {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(expectation)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
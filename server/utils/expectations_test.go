@@ -0,0 +1,102 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestEventExpectationEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	expect := func(t *testing.T, body string) *http.Response {
+		resp, err := http.Post(api.URL+"/emulator/expectations", "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("invalid matcher", func(t *testing.T) {
+		resp := expect(t, `{"report": "error"}`)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("expect and commit", func(t *testing.T) {
+		resp := expect(t, `{"type": "flow.AccountCreated", "report": "error"}`)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var expectation emulator.EventExpectation
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&expectation))
+		assert.Equal(t, "flow.AccountCreated", expectation.Type)
+
+		listResp, err := http.Get(api.URL + "/emulator/expectations")
+		require.NoError(t, err)
+		defer listResp.Body.Close()
+
+		var expectations []emulator.EventExpectation
+		require.NoError(t, json.NewDecoder(listResp.Body).Decode(&expectations))
+		require.Len(t, expectations, 1)
+		assert.Equal(t, expectation.ID, expectations[0].ID)
+
+		commitResp, err := http.Post(api.URL+"/emulator/newBlock", "application/json", nil)
+		require.NoError(t, err)
+		defer commitResp.Body.Close()
+		assert.Equal(t, http.StatusOK, commitResp.StatusCode)
+
+		assert.Empty(t, b.EventExpectations())
+
+		unmatchedResp, err := http.Get(api.URL + "/emulator/expectations/unmatched")
+		require.NoError(t, err)
+		defer unmatchedResp.Body.Close()
+
+		var unmatched []emulator.UnmatchedEventExpectation
+		require.NoError(t, json.NewDecoder(unmatchedResp.Body).Decode(&unmatched))
+		require.Len(t, unmatched, 1)
+		assert.Equal(t, expectation.ID, unmatched[0].ID)
+		assert.Equal(t, uint64(1), unmatched[0].BlockHeight)
+
+		req, err := http.NewRequest(http.MethodDelete, api.URL+"/emulator/expectations/unmatched", nil)
+		require.NoError(t, err)
+		clearResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer clearResp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, clearResp.StatusCode)
+
+		assert.Empty(t, b.UnmatchedEventExpectations())
+	})
+}
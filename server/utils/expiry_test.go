@@ -0,0 +1,71 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestReferenceBlocksEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(
+		emulator.WithTransactionExpiry(2),
+		emulator.WithTransactionExpiryBuffer(1),
+	)
+	require.NoError(t, err)
+
+	var blockIDs []string
+	for i := 0; i < 3; i++ {
+		block, err := b.CommitBlock()
+		require.NoError(t, err)
+		blockIDs = append(blockIDs, block.ID().String())
+	}
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	resp, err := http.Get(api.URL + "/emulator/referenceBlocks")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var window map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&window))
+
+	assert.Equal(t, map[string]any{
+		"expiry":                 float64(2),
+		"expiryBuffer":           float64(1),
+		"referenceBlockRequired": true,
+		"oldestHeight":           float64(2),
+		"oldestBlockId":          blockIDs[1],
+		"latestHeight":           float64(3),
+		"latestBlockId":          blockIDs[2],
+	}, window)
+}
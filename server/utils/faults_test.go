@@ -0,0 +1,104 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestFaultRuleEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	addRule := func(t *testing.T, body string) *http.Response {
+		resp, err := http.Post(api.URL+"/emulator/faults", "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		return resp
+	}
+
+	remove := func(t *testing.T, id string) int {
+		req, err := http.NewRequest(http.MethodDelete, api.URL+"/emulator/faults/"+id, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	t.Run("invalid matcher", func(t *testing.T) {
+		resp := addRule(t, `{"pattern": "main", "outcome": "crash"}`)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("add, list and remove", func(t *testing.T) {
+		resp := addRule(t, `{"pattern": "main", "outcome": "failure", "message": "node down"}`)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var rule emulator.FaultRule
+		err := json.NewDecoder(resp.Body).Decode(&rule)
+		require.NoError(t, err)
+		assert.NotEmpty(t, rule.ID)
+		assert.Equal(t, emulator.FaultOutcomeFailure, rule.Outcome)
+
+		result, err := b.ExecuteScript(context.Background(), []byte(`pub fun main() {}`), nil)
+		require.NoError(t, err)
+		require.Error(t, result.Error)
+		assert.Contains(t, result.Error.Error(), "node down")
+
+		listResp, err := http.Get(api.URL + "/emulator/faults")
+		require.NoError(t, err)
+		defer listResp.Body.Close()
+
+		var rules []emulator.FaultRule
+		err = json.NewDecoder(listResp.Body).Decode(&rules)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		assert.Equal(t, rule.ID, rules[0].ID)
+		assert.Equal(t, uint64(1), rules[0].Injected)
+
+		assert.Equal(t, http.StatusNoContent, remove(t, rule.ID))
+		assert.Equal(t, http.StatusNotFound, remove(t, rule.ID))
+
+		result, err = b.ExecuteScript(context.Background(), []byte(`pub fun main() {}`), nil)
+		require.NoError(t, err)
+		assert.NoError(t, result.Error)
+	})
+}
@@ -0,0 +1,158 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// FeeParametersBody are the transaction fee parameters, as UFix64 strings.
+type FeeParametersBody struct {
+	SurgeFactor         string `json:"surgeFactor"`
+	InclusionEffortCost string `json:"inclusionEffortCost"`
+	ExecutionEffortCost string `json:"executionEffortCost"`
+}
+
+type FeesResponse struct {
+	FeeParametersBody
+	// ExecutionEffortWeights are keyed by the number of the kind of computation
+	ExecutionEffortWeights map[common.ComputationKind]uint64 `json:"executionEffortWeights"`
+}
+
+// Fees returns the transaction fee parameters and the execution effort weights.
+func (m EmulatorAPIServer) Fees(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	m.writeFees(w, r)
+}
+
+// SetFeeParameters changes the transaction fee parameters, in a block of its own.
+func (m EmulatorAPIServer) SetFeeParameters(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var body FeeParametersBody
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	var parameters emulator.FeeParameters
+	for _, field := range []struct {
+		name  string
+		value string
+		to    *cadence.UFix64
+	}{
+		{"surgeFactor", body.SurgeFactor, &parameters.SurgeFactor},
+		{"inclusionEffortCost", body.InclusionEffortCost, &parameters.InclusionEffortCost},
+		{"executionEffortCost", body.ExecutionEffortCost, &parameters.ExecutionEffortCost},
+	} {
+		*field.to, err = cadence.NewUFix64(field.value)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"error": fmt.Sprintf("invalid %s %q: %s", field.name, field.value, err),
+			})
+			return
+		}
+	}
+
+	result, err := m.emulator.SetFeeParameters(r.Context(), parameters)
+	if !writeFeesTransactionError(w, result, err) {
+		m.writeFees(w, r)
+	}
+}
+
+// SetExecutionEffortWeights replaces the execution effort weights, in a block of its own.
+func (m EmulatorAPIServer) SetExecutionEffortWeights(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var weights map[common.ComputationKind]uint64
+	err := json.NewDecoder(r.Body).Decode(&weights)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	result, err := m.emulator.SetExecutionEffortWeights(r.Context(), weights)
+	if !writeFeesTransactionError(w, result, err) {
+		m.writeFees(w, r)
+	}
+}
+
+// writeFeesTransactionError writes the error of a transaction changing the fees, if it failed.
+func writeFeesTransactionError(w http.ResponseWriter, result *types.TransactionResult, err error) bool {
+	if err != nil {
+		var notEmptyErr *types.PendingBlockNotEmptyError
+		var readOnlyErr *types.ReadOnlyError
+		if errors.As(err, &notEmptyErr) || errors.As(err, &readOnlyErr) {
+			w.WriteHeader(http.StatusConflict)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return true
+	}
+
+	if !result.Succeeded() {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": result.Error.Error()})
+		return true
+	}
+
+	return false
+}
+
+func (m EmulatorAPIServer) writeFees(w http.ResponseWriter, r *http.Request) {
+	parameters, err := m.emulator.FeeParameters(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	weights, err := m.emulator.ExecutionEffortWeights(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(FeesResponse{
+		FeeParametersBody: FeeParametersBody{
+			SurgeFactor:         parameters.SurgeFactor.String(),
+			InclusionEffortCost: parameters.InclusionEffortCost.String(),
+			ExecutionEffortCost: parameters.ExecutionEffortCost.String(),
+		},
+		ExecutionEffortWeights: weights,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
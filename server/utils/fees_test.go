@@ -0,0 +1,83 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestFeesEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	do := func(t *testing.T, method string, path string, body string) (int, utils.FeesResponse) {
+		req, err := http.NewRequest(method, api.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var fees utils.FeesResponse
+		if resp.StatusCode == http.StatusOK {
+			err = json.NewDecoder(resp.Body).Decode(&fees)
+			require.NoError(t, err)
+		}
+		return resp.StatusCode, fees
+	}
+
+	status, fees := do(t, http.MethodGet, "/emulator/fees", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "1.00000000", fees.SurgeFactor)
+	assert.Equal(t, emulator.DefaultExecutionEffortWeights, fees.ExecutionEffortWeights)
+
+	status, fees = do(t, http.MethodPut, "/emulator/fees/parameters",
+		`{"surgeFactor":"2.5","inclusionEffortCost":"0.0001","executionEffortCost":"0.00002"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "2.50000000", fees.SurgeFactor)
+	assert.Equal(t, "0.00010000", fees.InclusionEffortCost)
+	assert.Equal(t, "0.00002000", fees.ExecutionEffortCost)
+
+	status, _ = do(t, http.MethodPut, "/emulator/fees/parameters",
+		`{"surgeFactor":"-1.0","inclusionEffortCost":"0.0001","executionEffortCost":"0.00002"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, fees = do(t, http.MethodPut, "/emulator/fees/executionEffortWeights", `{"1001":3000}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, fees.ExecutionEffortWeights, 1)
+	assert.Equal(t, uint64(3000), fees.ExecutionEffortWeights[1001])
+
+	status, _ = do(t, http.MethodPut, "/emulator/fees/executionEffortWeights", `{"statement":3000}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,155 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+const SyncContentType = "application/cbor"
+
+// ErrCommittedBlockNotFound is returned when the followed emulator has not
+// committed the requested block yet.
+var ErrCommittedBlockNotFound = errors.New("committed block not found")
+
+// FetchCommittedBlock downloads a committed block from the admin API of
+// another emulator, given as host:port.
+func FetchCommittedBlock(
+	ctx context.Context,
+	client *http.Client,
+	source string,
+	height uint64,
+) (*emulator.CommittedBlock, error) {
+	url := fmt.Sprintf("http://%s/emulator/sync/blocks/%d", source, height)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrCommittedBlockNotFound
+	default:
+		return nil, fmt.Errorf("failed to fetch block at height %d from %s: %s", height, source, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var block emulator.CommittedBlock
+	err = cbor.Unmarshal(body, &block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &block, nil
+}
+
+// Follower keeps a read-only emulator in sync with another emulator by
+// pulling newly committed blocks from its admin API.
+type Follower struct {
+	logger   *zerolog.Logger
+	emulator emulator.Emulator
+	source   string
+	client   *http.Client
+	ticker   *time.Ticker
+	done     chan bool
+}
+
+func NewFollower(
+	logger *zerolog.Logger,
+	emulator emulator.Emulator,
+	source string,
+	interval time.Duration,
+) *Follower {
+	return &Follower{
+		logger:   logger,
+		emulator: emulator,
+		source:   source,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		ticker:   time.NewTicker(interval),
+		done:     make(chan bool, 1),
+	}
+}
+
+func (f *Follower) Start() error {
+	for {
+		select {
+		case <-f.ticker.C:
+			err := f.Sync(context.Background())
+			if err != nil {
+				f.logger.Error().Err(err).Str("source", f.source).Msg("❗  Failed to sync blocks")
+			}
+		case <-f.done:
+			return nil
+		}
+	}
+}
+
+func (f *Follower) Stop() {
+	f.done <- true
+}
+
+// Sync applies all blocks the followed emulator committed since the last sync.
+func (f *Follower) Sync(ctx context.Context) error {
+	for {
+		latest, err := f.emulator.GetLatestBlock(ctx)
+		if err != nil {
+			return err
+		}
+
+		block, err := FetchCommittedBlock(ctx, f.client, f.source, latest.Header.Height+1)
+		if errors.Is(err, ErrCommittedBlockNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = f.emulator.ApplyCommittedBlock(ctx, block)
+		if err != nil {
+			return err
+		}
+
+		f.logger.Debug().
+			Uint64("blockHeight", block.Block.Header.Height).
+			Str("blockID", block.Block.ID().String()).
+			Msg("🔄  Synced block")
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestFollower(t *testing.T) {
+
+	t.Parallel()
+
+	ctx := context.Background()
+
+	primary, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(primary, nil))
+	defer api.Close()
+	source := strings.TrimPrefix(api.URL, "http://")
+
+	// seed the follower with the genesis block of the primary
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	genesis, err := utils.FetchCommittedBlock(ctx, http.DefaultClient, source, 0)
+	require.NoError(t, err)
+	require.NoError(t, genesis.Commit(ctx, store))
+
+	follower, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithReadOnly(true),
+	)
+	require.NoError(t, err)
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(`transaction { execute { log("hello") } }`)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(primary.ServiceKey().Address, primary.ServiceKey().Index, primary.ServiceKey().SequenceNumber).
+		SetPayer(primary.ServiceKey().Address)
+
+	signer, err := primary.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(primary.ServiceKey().Address, primary.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	flowTx := convert.SDKTransactionToFlow(*tx)
+
+	err = primary.AddTransaction(ctx, *flowTx)
+	require.NoError(t, err)
+
+	block, _, err := primary.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	err = utils.NewFollower(&logger, follower, source, time.Second).Sync(ctx)
+	require.NoError(t, err)
+
+	latest, err := follower.GetLatestBlock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, block.ID(), latest.ID())
+
+	result, err := follower.GetTransactionResult(ctx, flowTx.ID())
+	require.NoError(t, err)
+	assert.Equal(t, flowgo.TransactionStatusSealed, result.Status)
+
+	primaryAccount, err := primary.GetAccount(ctx, primary.GetChain().ServiceAddress())
+	require.NoError(t, err)
+	followerAccount, err := follower.GetAccount(ctx, primary.GetChain().ServiceAddress())
+	require.NoError(t, err)
+	assert.Equal(t, primaryAccount.Keys[0].SeqNumber, followerAccount.Keys[0].SeqNumber)
+
+	// followers do not accept transactions
+	err = follower.AddTransaction(ctx, *flowTx)
+	var readOnlyErr *types.ReadOnlyError
+	require.ErrorAs(t, err, &readOnlyErr)
+
+	// blocks of a diverged chain are not applied
+	err = primary.RollbackToBlockHeight(block.Header.Height - 1)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = primary.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	err = utils.NewFollower(&logger, follower, source, time.Second).Sync(ctx)
+	require.ErrorContains(t, err, "cannot apply block")
+
+	latest, err = follower.GetLatestBlock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, block.ID(), latest.ID())
+}
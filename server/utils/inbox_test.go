@@ -0,0 +1,127 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+const publishScript = `
+	transaction {
+		prepare(signer: AuthAccount) {
+			signer.save(42, to: /storage/answer)
+			let cap = signer.link<&Int>(/private/answer, target: /storage/answer)!
+			signer.inbox.publish(cap, name: "answer", recipient: signer.address)
+		}
+	}
+`
+
+func TestAccountInboxEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	serviceAddress := b.ServiceKey().Address
+
+	getInbox := func(t *testing.T, address string) (int, []emulator.InboxEntry) {
+		resp, err := http.Get(api.URL + "/emulator/accounts/" + address + "/inbox")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var entries []emulator.InboxEntry
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&entries))
+		return resp.StatusCode, entries
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		status, entries := getInbox(t, serviceAddress.Hex())
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("published", func(t *testing.T) {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(publishScript)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(serviceAddress, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(serviceAddress).
+			AddAuthorizer(serviceAddress)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(serviceAddress, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.NoError(t, results[0].Error)
+
+		status, entries := getInbox(t, "0x"+serviceAddress.Hex())
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t,
+			[]emulator.InboxEntry{
+				{
+					Name:      "answer",
+					Type:      "Capability<&Int>",
+					Recipient: "0x" + serviceAddress.Hex(),
+				},
+			},
+			entries,
+		)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		address, err := b.GetNetworkParameters().ChainID.Chain().AddressAtIndex(1000)
+		require.NoError(t, err)
+
+		status, _ := getInbox(t, address.Hex())
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		status, _ := getInbox(t, "ffffffffffffffff")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
@@ -0,0 +1,176 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestTransactionLogsEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	b, err := emulator.New(emulator.WithStore(store))
+	require.NoError(t, err)
+
+	txID := sendTransaction(t, b, `transaction { execute { log("hello"); log(42) } }`)
+
+	// the logs are read back from storage by an emulator restarted on it
+	restarted, err := emulator.New(emulator.WithStore(store))
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(restarted, nil))
+	defer api.Close()
+
+	get := func(t *testing.T, path string) (int, []string) {
+		resp, err := http.Get(api.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var logs []string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&logs))
+		return resp.StatusCode, logs
+	}
+
+	t.Run("logs", func(t *testing.T) {
+		status, logs := get(t, "/emulator/transactions/"+txID.String()+"/logs")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{`"hello"`, "42"}, logs)
+	})
+
+	t.Run("deprecated path", func(t *testing.T) {
+		status, logs := get(t, "/emulator/logs/"+txID.String())
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{`"hello"`, "42"}, logs)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		status, _ := get(t, "/emulator/transactions/"+flowgo.ZeroID.String()+"/logs")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("malformed ID", func(t *testing.T) {
+		status, _ := get(t, "/emulator/transactions/nothex/logs")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestTransactionErrorEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	failedTxID := sendTransaction(t, b, `transaction { execute { let x: Int? = nil; x! } }`)
+	failedBorrowTxID := sendTransaction(
+		t,
+		b,
+		`transaction { execute { getAccount(0xf8d6e0586b0a20c7).getCapability<&AnyStruct>(/public/missing).borrow()! } }`,
+	)
+	succeededTxID := sendTransaction(t, b, `transaction { execute {} }`)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	get := func(t *testing.T, txID flowgo.Identifier) (int, *types.TransactionError) {
+		resp, err := http.Get(api.URL + "/emulator/transactions/" + txID.String() + "/error")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var txErr types.TransactionError
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&txErr))
+		return resp.StatusCode, &txErr
+	}
+
+	t.Run("failed transaction", func(t *testing.T) {
+		status, txErr := get(t, failedTxID)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, types.ErrorCodeForceNil, txErr.Code)
+		assert.Equal(t, types.ErrorCodeForceNil.Hint(), txErr.Hint)
+		assert.Contains(t, txErr.Message, "unexpectedly found nil")
+	})
+
+	t.Run("failed borrow", func(t *testing.T) {
+		status, txErr := get(t, failedBorrowTxID)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, types.ErrorCodeMissingCapability, txErr.Code)
+	})
+
+	t.Run("succeeded transaction", func(t *testing.T) {
+		status, _ := get(t, succeededTxID)
+		assert.Equal(t, http.StatusNoContent, status)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		status, _ := get(t, flowgo.ZeroID)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
+
+// sendTransaction commits a block with a transaction of the service account.
+func sendTransaction(t *testing.T, b *emulator.Blockchain, script string) flowgo.Identifier {
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(script)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	flowTx := convert.SDKTransactionToFlow(*tx)
+
+	err = b.AddTransaction(context.Background(), *flowTx)
+	require.NoError(t, err)
+
+	_, _, err = b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+
+	return flowTx.ID()
+}
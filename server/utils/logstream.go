@@ -0,0 +1,168 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// logStreamBuffer is the number of log entries buffered for each client.
+// Entries are dropped for clients which fall further behind.
+const logStreamBuffer = 256
+
+// LogStream is a writer broadcasting every log entry written to it to the
+// connected clients. A logger writes to it as a second output, with
+// zerolog.MultiLevelWriter, to stream its JSON entries.
+type LogStream struct {
+	mu          sync.Mutex
+	subscribers map[chan []byte]struct{}
+}
+
+func NewLogStream() *LogStream {
+	return &LogStream{
+		subscribers: map[chan []byte]struct{}{},
+	}
+}
+
+// Write sends a log entry to the subscribers, without blocking on slow ones.
+func (s *LogStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.subscribers) == 0 {
+		return len(p), nil
+	}
+
+	entry := bytes.TrimSpace(p)
+	entry = append(make([]byte, 0, len(entry)), entry...)
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- entry:
+		default:
+		}
+	}
+
+	return len(p), nil
+}
+
+// Subscribe returns a channel receiving the log entries written from now on,
+// and a function to unsubscribe.
+func (s *LogStream) Subscribe() (<-chan []byte, func()) {
+	subscriber := make(chan []byte, logStreamBuffer)
+
+	s.mu.Lock()
+	s.subscribers[subscriber] = struct{}{}
+	s.mu.Unlock()
+
+	return subscriber, func() {
+		s.mu.Lock()
+		delete(s.subscribers, subscriber)
+		s.mu.Unlock()
+	}
+}
+
+// WithLogStream streams the entries of the given log stream from the logs endpoint.
+func WithLogStream(stream *LogStream) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		m.logStream = stream
+	}
+}
+
+// StreamLogs streams the server logs as server-sent events, one JSON log
+// entry per event, until the client disconnects. The optional level query
+// parameter sets the minimum level of the streamed entries.
+func (m EmulatorAPIServer) StreamLogs(w http.ResponseWriter, r *http.Request) {
+	if m.logStream == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "log streaming is not enabled"})
+		return
+	}
+
+	minLevel := zerolog.TraceLevel
+	if value := r.URL.Query().Get("level"); value != "" {
+		level, err := zerolog.ParseLevel(value)
+		if err != nil || level == zerolog.NoLevel {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("invalid level %q", value)})
+			return
+		}
+		minLevel = level
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	entries, unsubscribe := m.logStream.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case entry := <-entries:
+			if !logEntryEnabled(entry, minLevel) {
+				continue
+			}
+
+			_, err := fmt.Fprintf(w, "data: %s\n\n", entry)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// logEntryEnabled returns true if the JSON log entry is at or above the given level.
+// Entries without a level are always streamed.
+func logEntryEnabled(entry []byte, minLevel zerolog.Level) bool {
+	if minLevel <= zerolog.TraceLevel {
+		return true
+	}
+
+	var fields struct {
+		Level string `json:"level"`
+	}
+	if json.Unmarshal(entry, &fields) != nil || fields.Level == "" {
+		return true
+	}
+
+	level, err := zerolog.ParseLevel(fields.Level)
+	if err != nil {
+		return true
+	}
+
+	return level >= minLevel
+}
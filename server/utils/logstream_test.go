@@ -0,0 +1,129 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestStreamLogsEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+		defer api.Close()
+
+		resp, err := http.Get(api.URL + "/emulator/logs")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	stream := utils.NewLogStream()
+	logger := zerolog.New(stream)
+
+	b, err := emulator.New(emulator.WithServerLogger(logger))
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil, utils.WithLogStream(stream)))
+	defer api.Close()
+
+	t.Run("invalid level", func(t *testing.T) {
+		resp, err := http.Get(api.URL + "/emulator/logs?level=loud")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("streamed", func(t *testing.T) {
+		resp, err := http.Get(api.URL + "/emulator/logs?level=info")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		// debug entries are filtered out
+		logger.Debug().Msg("filtered")
+
+		txID := sendTransaction(t, b, `transaction { execute { log("hello") } }`)
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				continue
+			}
+			require.True(t, strings.HasPrefix(line, "data: "), line)
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &entry))
+			message, _ := entry[zerolog.MessageFieldName].(string)
+			require.NotEqual(t, "filtered", message)
+			if !strings.Contains(message, `"hello"`) {
+				continue
+			}
+
+			assert.Equal(t, "info", entry["level"])
+			assert.Equal(t, txID.String(), entry["txID"])
+			return
+		}
+
+		require.NoError(t, scanner.Err())
+		t.Fatal("log stream ended before the Cadence log was streamed")
+	})
+
+	t.Run("accepts CBOR", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, api.URL+"/emulator/logs", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", utils.CBORContentType)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// the stream is not negotiated
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		logger.Info().Msg("streamed")
+
+		line, err := bufio.NewReader(resp.Body).ReadString('\n')
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(line, "data: "), line)
+	})
+}
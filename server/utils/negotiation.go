@@ -0,0 +1,246 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	JSONContentType = "application/json"
+	CBORContentType = "application/cbor"
+	// OctetStreamContentType is the content type of raw transactions sent to the admin API.
+	OctetStreamContentType = "application/octet-stream"
+)
+
+// negotiateContent lets clients exchange CBOR instead of JSON with an admin
+// API handler. Request bodies sent as CBOR are passed to the handler as JSON,
+// and JSON responses are encoded as CBOR if the request accepts CBOR.
+func negotiateContent(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hasMediaType(r.Header.Get("Content-Type"), CBORContentType) {
+			err := cborRequestToJSON(r)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		if !acceptsCBOR(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		buffered := &bufferedResponseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+		handler.ServeHTTP(buffered, r)
+
+		body := buffered.body.Bytes()
+		if hasMediaType(w.Header().Get("Content-Type"), JSONContentType) && len(body) > 0 {
+			encoded, err := jsonToCBOR(body)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			body = encoded
+			w.Header().Set("Content-Type", CBORContentType)
+		}
+
+		w.Header().Del("Content-Length")
+		w.WriteHeader(buffered.status)
+		_, _ = w.Write(body)
+	})
+}
+
+// acceptsCBOR returns true if the request prefers CBOR over JSON.
+func acceptsCBOR(r *http.Request) bool {
+	bestType := ""
+	bestQuality := -1.0
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		if mediaType != CBORContentType && mediaType != JSONContentType {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		if quality > bestQuality {
+			bestType = mediaType
+			bestQuality = quality
+		}
+	}
+
+	return bestType == CBORContentType && bestQuality > 0
+}
+
+func hasMediaType(contentType string, expected string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == expected
+}
+
+func cborRequestToJSON(r *http.Request) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	var value any
+	err = cbor.Unmarshal(body, &value)
+	if err != nil {
+		return err
+	}
+
+	value, err = jsonCompatible(value)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(encoded))
+	r.ContentLength = int64(len(encoded))
+	r.Header.Set("Content-Type", JSONContentType)
+
+	return nil
+}
+
+// jsonCompatible converts the maps decoded from CBOR, which may have keys of
+// any type, to maps with string keys.
+func jsonCompatible(value any) (any, error) {
+	switch value := value.(type) {
+	case map[any]any:
+		result := make(map[string]any, len(value))
+		for key, item := range value {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported map key %v", key)
+			}
+			converted, err := jsonCompatible(item)
+			if err != nil {
+				return nil, err
+			}
+			result[name] = converted
+		}
+		return result, nil
+
+	case []any:
+		result := make([]any, len(value))
+		for i, item := range value {
+			converted, err := jsonCompatible(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+
+	default:
+		return value, nil
+	}
+}
+
+func jsonToCBOR(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	return cbor.Marshal(cborCompatible(value))
+}
+
+// cborCompatible converts JSON numbers to integers where possible, so large
+// integers such as addresses do not lose precision.
+func cborCompatible(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			return u
+		}
+		f, _ := value.Float64()
+		return f
+
+	case map[string]any:
+		for key, item := range value {
+			value[key] = cborCompatible(item)
+		}
+		return value
+
+	case []any:
+		for i, item := range value {
+			value[i] = cborCompatible(item)
+		}
+		return value
+
+	default:
+		return value
+	}
+}
+
+// bufferedResponseWriter holds back a response, so it can be re-encoded.
+// Like http.ResponseWriter, the first status written wins.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
@@ -0,0 +1,137 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestVersionedAPI(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	request := func(t *testing.T, method, path, contentType, accept string, body []byte) *http.Response {
+		req, err := http.NewRequest(method, api.URL+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("versioned and unversioned paths", func(t *testing.T) {
+		for _, path := range []string{
+			"/v1/emulator/utils/address/f8d6e0586b0a20c7",
+			"/emulator/utils/address/f8d6e0586b0a20c7",
+		} {
+			resp := request(t, http.MethodGet, path, "", "", nil)
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode, path)
+			assert.Equal(t, utils.JSONContentType, resp.Header.Get("Content-Type"))
+
+			var info utils.AddressInfo
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+			assert.Equal(t, "0xf8d6e0586b0a20c7", info.Address)
+		}
+	})
+
+	t.Run("CBOR response", func(t *testing.T) {
+		resp := request(t, http.MethodGet, "/v1/emulator/utils/address/f8d6e0586b0a20c7", "", "application/json;q=0.5, application/cbor", nil)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, utils.CBORContentType, resp.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		var info struct {
+			Address string `cbor:"address"`
+			Uint64  uint64 `cbor:"uint64"`
+		}
+		require.NoError(t, cbor.Unmarshal(body, &info))
+		assert.Equal(t, "0xf8d6e0586b0a20c7", info.Address)
+		assert.Equal(t, uint64(0xf8d6e0586b0a20c7), info.Uint64)
+	})
+
+	t.Run("JSON preferred", func(t *testing.T) {
+		resp := request(t, http.MethodGet, "/v1/emulator/utils/address/f8d6e0586b0a20c7", "", "application/json, application/cbor;q=0.5", nil)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, utils.JSONContentType, resp.Header.Get("Content-Type"))
+	})
+
+	t.Run("CBOR request", func(t *testing.T) {
+		body, err := cbor.Marshal(map[string]any{
+			"script": `pub fun main(a: Int) {}`,
+			"arguments": []any{
+				map[string]any{"type": "Int", "value": "1"},
+			},
+		})
+		require.NoError(t, err)
+
+		resp := request(t, http.MethodPost, "/v1/emulator/utils/validateArguments", utils.CBORContentType, utils.CBORContentType, body)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, utils.CBORContentType, resp.Header.Get("Content-Type"))
+
+		encoded, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		var validation struct {
+			Valid bool `cbor:"valid"`
+		}
+		require.NoError(t, cbor.Unmarshal(encoded, &validation))
+		assert.True(t, validation.Valid)
+	})
+
+	t.Run("error status kept", func(t *testing.T) {
+		resp := request(t, http.MethodGet, "/v1/emulator/utils/address/zz", "", utils.CBORContentType, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, utils.CBORContentType, resp.Header.Get("Content-Type"))
+	})
+}
@@ -0,0 +1,72 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// PendingBlock returns the transactions of the pending block and its execution progress.
+func (m EmulatorAPIServer) PendingBlock(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(m.emulator.PendingBlockInfo())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// RemovePendingTransaction drops a transaction from the pending block,
+// before the execution of the pending block starts.
+func (m EmulatorAPIServer) RemovePendingTransaction(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	txID, err := flowgo.HexStringToIdentifier(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = m.emulator.RemovePendingTransaction(txID)
+	if err != nil {
+		var notFoundErr *types.TransactionNotFoundError
+		var midExecutionErr *types.PendingBlockMidExecutionError
+		var readOnlyErr *types.ReadOnlyError
+		switch {
+		case errors.As(err, &notFoundErr):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.As(err, &midExecutionErr), errors.As(err, &readOnlyErr):
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
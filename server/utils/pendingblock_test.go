@@ -0,0 +1,96 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestPendingBlockEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(`transaction { execute { log("hello") } }`)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	err = b.SendTransaction(context.Background(), convert.SDKTransactionToFlow(*tx))
+	require.NoError(t, err)
+
+	pendingBlock := func(t *testing.T) emulator.PendingBlockInfo {
+		resp, err := http.Get(api.URL + "/emulator/pendingBlock")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var info emulator.PendingBlockInfo
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+		return info
+	}
+
+	remove := func(t *testing.T, id string) int {
+		req, err := http.NewRequest(http.MethodDelete, api.URL+"/emulator/pendingBlock/transactions/"+id, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	info := pendingBlock(t)
+	assert.Equal(t, 1, info.TransactionCount)
+	assert.Equal(t, 0, info.ExecutedCount)
+	require.Len(t, info.Transactions, 1)
+	assert.Equal(t, tx.ID().String(), info.Transactions[0].ID.String())
+
+	assert.Equal(t, http.StatusBadRequest, remove(t, "zz"))
+	assert.Equal(t, http.StatusNoContent, remove(t, tx.ID().String()))
+	assert.Equal(t, http.StatusNotFound, remove(t, tx.ID().String()))
+
+	info = pendingBlock(t)
+	assert.Equal(t, 0, info.TransactionCount)
+	assert.Empty(t, info.Transactions)
+}
@@ -0,0 +1,142 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+// PinsResponse reports the block heights whose ledger state is exempt from pruning.
+type PinsResponse struct {
+	Heights []uint64 `json:"heights"`
+}
+
+// Pins returns the pinned block heights, in ascending order.
+func (m EmulatorAPIServer) Pins(w http.ResponseWriter, _ *http.Request) {
+	m.writePins(w)
+}
+
+// PinBlockHeight exempts the ledger state at a block height from pruning.
+func (m EmulatorAPIServer) PinBlockHeight(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	height, err := strconv.ParseUint(mux.Vars(r)["height"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	m.pinBlockHeight(w, height)
+}
+
+// PinTransaction exempts the ledger state of the block of a sealed transaction from pruning.
+func (m EmulatorAPIServer) PinTransaction(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	identifier, err := flowgo.HexStringToIdentifier(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := m.emulator.GetTransactionResult(r.Context(), identifier)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if result.Status == flowgo.TransactionStatusUnknown {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if result.Status != flowgo.TransactionStatusSealed {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("transaction %s is not sealed", identifier),
+		})
+		return
+	}
+
+	m.pinBlockHeight(w, result.BlockHeight)
+}
+
+func (m EmulatorAPIServer) pinBlockHeight(w http.ResponseWriter, height uint64) {
+	err := m.emulator.PinBlockHeight(height)
+	if err != nil {
+		var invalidArgumentErr *types.InvalidArgumentError
+		var prunedErr *types.StatePrunedError
+		switch {
+		case errors.As(err, &invalidArgumentErr):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.As(err, &prunedErr):
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	m.writePins(w)
+}
+
+// UnpinBlockHeight lets the ledger state at a block height be pruned again.
+func (m EmulatorAPIServer) UnpinBlockHeight(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	height, err := strconv.ParseUint(mux.Vars(r)["height"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = m.emulator.UnpinBlockHeight(height)
+	if err != nil {
+		var invalidArgumentErr *types.InvalidArgumentError
+		if errors.As(err, &invalidArgumentErr) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m EmulatorAPIServer) writePins(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(PinsResponse{Heights: m.emulator.PinnedBlockHeights()})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestPins(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New(emulator.WithStateHistory(2))
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	request := func(t *testing.T, method string, path string) *http.Response {
+		req, err := http.NewRequest(method, api.URL+"/emulator"+path, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	txID := sendTransaction(t, b, `transaction { execute { log("fixture") } }`)
+	fixture, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	pinned := fixture.Header.Height
+
+	t.Run("pin transaction", func(t *testing.T) {
+		resp := request(t, http.MethodPut, "/pins/transactions/"+txID.String())
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var pins utils.PinsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&pins))
+		assert.Equal(t, []uint64{pinned}, pins.Heights)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		resp := request(t, http.MethodPut, "/pins/transactions/"+flowgo.ZeroID.String())
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	for i := 0; i < 3; i++ {
+		sendTransaction(t, b, `transaction { execute { log("block") } }`)
+	}
+
+	t.Run("list", func(t *testing.T) {
+		resp := request(t, http.MethodGet, "/pins")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var pins utils.PinsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&pins))
+		assert.Equal(t, []uint64{pinned}, pins.Heights)
+	})
+
+	t.Run("pruned height", func(t *testing.T) {
+		resp := request(t, http.MethodPut, fmt.Sprintf("/pins/%d", pinned+1))
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+	})
+
+	t.Run("future height", func(t *testing.T) {
+		resp := request(t, http.MethodPut, fmt.Sprintf("/pins/%d", pinned+100))
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unpin", func(t *testing.T) {
+		resp := request(t, http.MethodDelete, fmt.Sprintf("/pins/%d", pinned))
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = request(t, http.MethodDelete, fmt.Sprintf("/pins/%d", pinned))
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
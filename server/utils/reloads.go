@@ -0,0 +1,49 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/onflow/flow-emulator/server/watcher"
+)
+
+// WithContractWatcher serves the reloads of the contracts watched by the given watcher.
+func WithContractWatcher(contractWatcher *watcher.ContractWatcher) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		m.contractWatcher = contractWatcher
+	}
+}
+
+// ContractReloads returns the most recent redeployments of watched contract files, oldest first.
+func (m EmulatorAPIServer) ContractReloads(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.contractWatcher == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err := json.NewEncoder(w).Encode(m.contractWatcher.Reloads())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
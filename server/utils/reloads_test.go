@@ -0,0 +1,87 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/server/watcher"
+)
+
+func TestContractReloadsEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	t.Run("disabled", func(t *testing.T) {
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+		defer api.Close()
+
+		resp, err := http.Get(api.URL + "/emulator/contracts/reloads")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("reloaded", func(t *testing.T) {
+		dir := t.TempDir()
+		logger := zerolog.Nop()
+
+		contractWatcher, err := watcher.New(&logger, b, dir, watcher.DefaultDebounce)
+		require.NoError(t, err)
+
+		file := filepath.Join(dir, "Hello.cdc")
+		code := `access(all) contract Hello { access(all) fun hello(): String { return "hello" } }`
+		require.NoError(t, os.WriteFile(file, []byte(code), 0o644))
+
+		reload := contractWatcher.Reload(context.Background(), file)
+		require.NotNil(t, reload)
+		require.Empty(t, reload.Error)
+
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil, utils.WithContractWatcher(contractWatcher)))
+		defer api.Close()
+
+		resp, err := http.Get(api.URL + "/emulator/contracts/reloads")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var reloads []watcher.Reload
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&reloads))
+		require.Len(t, reloads, 1)
+		assert.Equal(t, "Hello", reloads[0].Contract)
+		assert.Equal(t, file, reloads[0].File)
+		assert.Equal(t, reload.TransactionID, reloads[0].TransactionID)
+	})
+}
@@ -0,0 +1,119 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+func TestSnapshotEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	b, err := emulator.New(emulator.WithStore(store))
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	create := func(t *testing.T, name string, description string) {
+		resp, err := http.PostForm(api.URL+"/emulator/snapshots", url.Values{
+			"name":        {name},
+			"description": {description},
+		})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	list := func(t *testing.T) []storage.SnapshotInfo {
+		resp, err := http.Get(api.URL + "/emulator/snapshots?details=true")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var snapshots []storage.SnapshotInfo
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&snapshots))
+		return snapshots
+	}
+
+	remove := func(t *testing.T, name string) int {
+		req, err := http.NewRequest(http.MethodDelete, api.URL+"/emulator/snapshots/"+name, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	create(t, "api-first", "before deployment")
+
+	_, err = b.CommitBlock()
+	require.NoError(t, err)
+
+	create(t, "api-second", "")
+
+	snapshots := list(t)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "api-first", snapshots[0].Name)
+	assert.Equal(t, "before deployment", snapshots[0].Description)
+	assert.Equal(t, "api-second", snapshots[1].Name)
+	assert.Equal(t, snapshots[0].BlockHeight+1, snapshots[1].BlockHeight)
+
+	// without details, only the names are listed, as before snapshots had metadata
+	resp, err := http.Get(api.URL + "/emulator/snapshots")
+	require.NoError(t, err)
+	var names []string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&names))
+	resp.Body.Close()
+	assert.Equal(t, []string{"api-first", "api-second"}, names)
+
+	assert.Equal(t, http.StatusNoContent, remove(t, "api-first"))
+	assert.Equal(t, http.StatusNotFound, remove(t, "api-first"))
+
+	snapshots = list(t)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "api-second", snapshots[0].Name)
+
+	// the snapshot the emulator runs on can not be deleted
+	req, err := http.NewRequest(http.MethodPut, api.URL+"/emulator/snapshots/api-second", nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, http.StatusConflict, remove(t, "api-second"))
+}
@@ -0,0 +1,71 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestStandbyEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	do := func(t *testing.T, method string, path string) int {
+		req, err := http.NewRequest(method, api.URL+path, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// the state can not be reset before standby is enabled
+	assert.Equal(t, http.StatusConflict, do(t, http.MethodDelete, "/emulator/state"))
+
+	assert.Equal(t, http.StatusNoContent, do(t, http.MethodPost, "/emulator/state/standby"))
+
+	standbyBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = b.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, http.StatusNoContent, do(t, http.MethodDelete, "/emulator/state"))
+
+	latestBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, standbyBlock.ID(), latestBlock.ID())
+}
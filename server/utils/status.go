@@ -0,0 +1,101 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+// processStart approximates the time the emulator process started.
+var processStart = time.Now()
+
+// Status is the health of the emulator process, served by the status endpoint.
+type Status struct {
+	// Uptime is the process uptime in seconds.
+	Uptime           float64        `json:"uptime"`
+	StartTime        time.Time      `json:"startTime"`
+	Memory           MemoryStatus   `json:"memory"`
+	Goroutines       int            `json:"goroutines"`
+	OpenStoreHandles int            `json:"openStoreHandles"`
+	Sessions         SessionsStatus `json:"sessions"`
+	// PendingTransactions is the number of transactions in the pending block.
+	PendingTransactions int       `json:"pendingTransactions"`
+	LatestBlockHeight   uint64    `json:"latestBlockHeight"`
+	LastBlockTime       time.Time `json:"lastBlockTime"`
+	// Ports are the ports the emulator servers listen on, if known.
+	Ports *PortsStatus `json:"ports,omitempty"`
+}
+
+// PortsStatus are the ports the emulator servers listen on.
+// Ports configured as zero are reported as assigned by the operating system.
+type PortsStatus struct {
+	GRPC     int `json:"grpc"`
+	REST     int `json:"rest"`
+	Admin    int `json:"admin"`
+	Debugger int `json:"debugger"`
+	// SecondaryGRPC is the Access API port of the secondary emulator, if any.
+	SecondaryGRPC int `json:"secondaryGrpc,omitempty"`
+}
+
+// MemoryStatus is the memory used by the process, in bytes.
+type MemoryStatus struct {
+	// HeapAlloc is the memory occupied by live and not yet collected heap objects.
+	HeapAlloc uint64 `json:"heapAlloc"`
+	// Sys is the memory obtained from the operating system.
+	Sys uint64 `json:"sys"`
+}
+
+// SessionsStatus counts the sessions attached to the emulator.
+type SessionsStatus struct {
+	BlockSubscriptions int  `json:"blockSubscriptions"`
+	Debugging          bool `json:"debugging"`
+}
+
+// GetStatus collects the status of the emulator and the process running it.
+func GetStatus(ctx context.Context, emu emulator.Emulator) (*Status, error) {
+	emulatorStatus, err := emu.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	return &Status{
+		Uptime:    time.Since(processStart).Seconds(),
+		StartTime: processStart,
+		Memory: MemoryStatus{
+			HeapAlloc: memStats.HeapAlloc,
+			Sys:       memStats.Sys,
+		},
+		Goroutines:       runtime.NumGoroutine(),
+		OpenStoreHandles: emulatorStatus.OpenStoreHandles,
+		Sessions: SessionsStatus{
+			BlockSubscriptions: emulatorStatus.BlockSubscriptions,
+			Debugging:          emulatorStatus.DebuggingSession,
+		},
+		PendingTransactions: emulatorStatus.PendingTransactions,
+		LatestBlockHeight:   emulatorStatus.LatestBlockHeight,
+		LastBlockTime:       emulatorStatus.LastBlockTime,
+	}, nil
+}
@@ -0,0 +1,85 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+func TestStatusEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	store, err := sqlite.New(sqlite.InMemory)
+	require.NoError(t, err)
+
+	b, err := emulator.New(
+		emulator.WithStore(store),
+		emulator.WithTransactionValidationEnabled(false),
+	)
+	require.NoError(t, err)
+
+	block, err := b.CommitBlock()
+	require.NoError(t, err)
+
+	ch := make(chan emulator.BlockEvent, 1)
+	b.SubscribeBlockCommitted(ch)
+	defer b.UnsubscribeBlockCommitted(ch)
+
+	tx := flowgo.NewTransactionBody().
+		SetScript([]byte(`transaction {}`)).
+		SetPayer(b.GetChain().ServiceAddress()).
+		SetProposalKey(b.GetChain().ServiceAddress(), 0, 0)
+	err = b.AddTransaction(context.Background(), *tx)
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	resp, err := http.Get(api.URL + "/emulator/status")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var status utils.Status
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+
+	assert.Positive(t, status.Uptime)
+	assert.Positive(t, status.Memory.HeapAlloc)
+	assert.Positive(t, status.Memory.Sys)
+	assert.Positive(t, status.Goroutines)
+	assert.Positive(t, status.OpenStoreHandles)
+	assert.Equal(t, 1, status.Sessions.BlockSubscriptions)
+	assert.False(t, status.Sessions.Debugging)
+	assert.Equal(t, 1, status.PendingTransactions)
+	assert.Equal(t, block.Header.Height, status.LatestBlockHeight)
+	assert.True(t, block.Header.Timestamp.Equal(status.LastBlockTime))
+}
@@ -0,0 +1,207 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/checkpoint"
+	"github.com/onflow/flow-emulator/storage/memstore"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+func TestSwitchStorageEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	storage.Register("utils-switch-test", func(dsn string) (storage.Store, error) {
+		return sqlite.New(dsn)
+	})
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	switchStorage := func(t *testing.T, values url.Values) (int, *utils.BlockResponse) {
+		req, err := http.NewRequest(
+			http.MethodPut,
+			api.URL+"/emulator/storage",
+			strings.NewReader(values.Encode()),
+		)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var block utils.BlockResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&block))
+		return resp.StatusCode, &block
+	}
+
+	sendTransaction(t, b, `transaction { execute { log("hello") } }`)
+
+	t.Run("missing provider", func(t *testing.T) {
+		status, _ := switchStorage(t, url.Values{})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		status, _ := switchStorage(t, url.Values{"provider": {"utils-switch-test-unknown"}})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	dir := t.TempDir()
+
+	t.Run("switch", func(t *testing.T) {
+		previous := b.Storage()
+
+		status, block := switchStorage(t, url.Values{
+			"provider": {"utils-switch-test"},
+			"dsn":      {dir},
+		})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "utils-switch-test", block.Context)
+		assert.Equal(t, 1, block.Height)
+		assert.NotSame(t, previous, b.Storage())
+	})
+
+	t.Run("target not empty", func(t *testing.T) {
+		// the emulator now runs on the storage in dir
+		status, _ := switchStorage(t, url.Values{
+			"provider": {"utils-switch-test"},
+			"dsn":      {dir},
+		})
+		assert.Equal(t, http.StatusConflict, status)
+	})
+}
+
+func TestCheckpointEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	checkpointRequest := func(t *testing.T, b *emulator.Blockchain) int {
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+		defer api.Close()
+
+		resp, err := http.Post(api.URL+"/emulator/checkpoint", "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("checkpoint storage", func(t *testing.T) {
+		t.Parallel()
+
+		location := filepath.Join(t.TempDir(), "state.sqlite")
+
+		store, err := checkpoint.New(location)
+		require.NoError(t, err)
+
+		b, err := emulator.New(emulator.WithStore(store))
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, checkpointRequest(t, b))
+		assert.FileExists(t, location)
+	})
+
+	t.Run("other storage", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New()
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, checkpointRequest(t, b))
+	})
+}
+
+func TestCompactStorageEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	compactRequest := func(t *testing.T, b *emulator.Blockchain) (int, utils.StorageCompaction) {
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+		defer api.Close()
+
+		resp, err := http.Post(api.URL+"/emulator/storage/compact", "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var compaction utils.StorageCompaction
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&compaction))
+		}
+		return resp.StatusCode, compaction
+	}
+
+	t.Run("sqlite storage", func(t *testing.T) {
+		t.Parallel()
+
+		store, err := sqlite.New(filepath.Join(t.TempDir(), "emulator.sqlite"))
+		require.NoError(t, err)
+
+		b, err := emulator.New(
+			emulator.WithStore(store),
+			emulator.WithStateHistory(1),
+		)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err := b.CommitBlock()
+			require.NoError(t, err)
+		}
+
+		status, compaction := compactRequest(t, b)
+		require.Equal(t, http.StatusOK, status)
+		assert.GreaterOrEqual(t, compaction.ReclaimedBytes, int64(0))
+
+		_, err = b.GetLatestBlock(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("other storage", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := emulator.New(emulator.WithStore(memstore.New()))
+		require.NoError(t, err)
+
+		status, _ := compactRequest(t, b)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
@@ -0,0 +1,239 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gorilla/mux"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+const InteractionTemplatesPath = "/emulator/templates"
+
+// templateNetworks are the network names FCL may resolve template
+// dependencies for when connected to the emulator.
+var templateNetworks = []string{"emulator", "local"}
+
+// InteractionTemplate is an interaction template (FLIX) document. It is kept
+// as generic JSON so fields unknown to the emulator are served unchanged.
+type InteractionTemplate map[string]any
+
+// LoadInteractionTemplates reads all interaction templates (*.json) in the
+// given directory, indexed by template ID.
+func LoadInteractionTemplates(dir string) (map[string]InteractionTemplate, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]InteractionTemplate, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var template InteractionTemplate
+		err = json.Unmarshal(content, &template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse interaction template %s: %w", file, err)
+		}
+
+		id, ok := template["id"].(string)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("interaction template %s has no id", file)
+		}
+
+		templates[id] = template
+	}
+
+	return templates, nil
+}
+
+// InteractionTemplateServer serves interaction templates with their contract
+// dependencies resolved to the contracts deployed on the emulator.
+type InteractionTemplateServer struct {
+	router    *mux.Router
+	emulator  emulator.Emulator
+	templates map[string]InteractionTemplate
+}
+
+func NewInteractionTemplateServer(
+	emulator emulator.Emulator,
+	templates map[string]InteractionTemplate,
+) *InteractionTemplateServer {
+	router := mux.NewRouter().StrictSlash(true)
+	s := &InteractionTemplateServer{
+		router:    router,
+		emulator:  emulator,
+		templates: templates,
+	}
+
+	router.HandleFunc(InteractionTemplatesPath, s.List).Methods("GET")
+	router.HandleFunc(InteractionTemplatesPath+"/{id}", s.Template).Methods("GET")
+
+	return s
+}
+
+func (s *InteractionTemplateServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *InteractionTemplateServer) List(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ids := make([]string, 0, len(s.templates))
+	for id := range s.templates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	err := json.NewEncoder(w).Encode(ids)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *InteractionTemplateServer) Template(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	template, ok := s.templates[vars["id"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	resolved, err := s.resolve(r.Context(), template)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(resolved)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// resolve returns a copy of the template in which every dependency on a
+// contract deployed on the emulator has an entry for the emulator networks.
+//
+// Dependencies are nested as placeholder -> contract name -> network.
+func (s *InteractionTemplateServer) resolve(
+	ctx context.Context,
+	template InteractionTemplate,
+) (InteractionTemplate, error) {
+	// deep copy, so the loaded template is not modified
+	encoded, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+	var resolved InteractionTemplate
+	err = json.Unmarshal(encoded, &resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _ := resolved["data"].(map[string]any)
+	dependencies, _ := data["dependencies"].(map[string]any)
+	if len(dependencies) == 0 {
+		return resolved, nil
+	}
+
+	contracts, err := s.deployedContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlock, err := s.emulator.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dependency := range dependencies {
+		dependencyContracts, _ := dependency.(map[string]any)
+		for name, networks := range dependencyContracts {
+			networks, ok := networks.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			address, ok := contracts[name]
+			if !ok {
+				continue
+			}
+
+			for _, network := range templateNetworks {
+				networks[network] = map[string]any{
+					"address":          address.HexWithPrefix(),
+					"fq_address":       fmt.Sprintf("A.%s.%s", address.HexWithPrefix(), name),
+					"contract":         name,
+					"pin":              "",
+					"pin_block_height": latestBlock.Header.Height,
+				}
+			}
+		}
+	}
+
+	return resolved, nil
+}
+
+// deployedContracts maps the names of the contracts deployed on the emulator
+// to their address. If several accounts deploy a contract with the same name,
+// the account created first wins.
+func (s *InteractionTemplateServer) deployedContracts(ctx context.Context) (map[string]flowgo.Address, error) {
+	chain := s.emulator.GetNetworkParameters().ChainID.Chain()
+
+	contracts := make(map[string]flowgo.Address)
+	for index := uint64(1); ; index++ {
+		address, err := chain.AddressAtIndex(index)
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := s.emulator.GetAccount(ctx, address)
+		var notFoundErr *types.AccountNotFoundError
+		if errors.As(err, &notFoundErr) || (err == nil && account == nil) {
+			return contracts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for name := range account.Contracts {
+			if _, ok := contracts[name]; !ok {
+				contracts[name] = address
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onflow/flow-go/fvm"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+const transferTemplate = `{
+	"f_type": "InteractionTemplate",
+	"f_version": "1.0.0",
+	"id": "290b6b6222b2a77b16db896a80ddf29ebd1fa3038c9e6625a933fa213fce51fa",
+	"data": {
+		"type": "transaction",
+		"interface": "",
+		"cadence": "import FungibleToken from 0xFUNGIBLETOKENADDRESS\n",
+		"dependencies": {
+			"0xFUNGIBLETOKENADDRESS": {
+				"FungibleToken": {
+					"mainnet": {
+						"address": "0xf233dcee88fe0abe",
+						"fq_address": "A.0xf233dcee88fe0abe.FungibleToken",
+						"contract": "FungibleToken",
+						"pin": "83c9e3d61d3b5ebf24356a9f17b5b57b12d6d56547abc73e05f820a0ae7d9cf5",
+						"pin_block_height": 34166296
+					}
+				}
+			},
+			"0xNOTDEPLOYEDADDRESS": {
+				"NotDeployed": {
+					"mainnet": {
+						"address": "0x0000000000000001",
+						"fq_address": "A.0x0000000000000001.NotDeployed",
+						"contract": "NotDeployed",
+						"pin": "",
+						"pin_block_height": 1
+					}
+				}
+			}
+		}
+	}
+}`
+
+func TestInteractionTemplates(t *testing.T) {
+
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "transfer.json"), []byte(transferTemplate), 0o644))
+
+	templates, err := utils.LoadInteractionTemplates(dir)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewInteractionTemplateServer(b, templates))
+	defer api.Close()
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := http.Get(api.URL + "/emulator/templates")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var ids []string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&ids))
+		assert.Equal(t, []string{"290b6b6222b2a77b16db896a80ddf29ebd1fa3038c9e6625a933fa213fce51fa"}, ids)
+	})
+
+	t.Run("dependencies are resolved to deployed contracts", func(t *testing.T) {
+		resp, err := http.Get(api.URL + "/emulator/templates/290b6b6222b2a77b16db896a80ddf29ebd1fa3038c9e6625a933fa213fce51fa")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var template struct {
+			Data struct {
+				Dependencies map[string]map[string]map[string]struct {
+					Address   string `json:"address"`
+					FQAddress string `json:"fq_address"`
+					Contract  string `json:"contract"`
+				} `json:"dependencies"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&template))
+
+		fungibleToken := template.Data.Dependencies["0xFUNGIBLETOKENADDRESS"]["FungibleToken"]
+		expectedAddress := fvm.FungibleTokenAddress(flowgo.Emulator.Chain()).HexWithPrefix()
+
+		for _, network := range []string{"emulator", "local"} {
+			require.Contains(t, fungibleToken, network)
+			assert.Equal(t, expectedAddress, fungibleToken[network].Address)
+			assert.Equal(t, "A."+expectedAddress+".FungibleToken", fungibleToken[network].FQAddress)
+			assert.Equal(t, "FungibleToken", fungibleToken[network].Contract)
+		}
+		assert.Equal(t, "0xf233dcee88fe0abe", fungibleToken["mainnet"].Address)
+
+		notDeployed := template.Data.Dependencies["0xNOTDEPLOYEDADDRESS"]["NotDeployed"]
+		assert.NotContains(t, notDeployed, "emulator")
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		resp, err := http.Get(api.URL + "/emulator/templates/unknown")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
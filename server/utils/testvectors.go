@@ -0,0 +1,208 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// DefaultTestVectorLimit is the number of test vectors kept by a recorder,
+// unless configured otherwise.
+const DefaultTestVectorLimit = 1000
+
+// TestVector is the canonical encoding of a transaction and its signatures,
+// to validate signing implementations against transactions accepted by the emulator.
+type TestVector struct {
+	TransactionID string `json:"transactionId"`
+	// DomainTag is prepended to the payload and the envelope before they are signed.
+	DomainTag string `json:"domainTag"`
+	// Payload is the RLP encoded payload, signed by the proposer and the authorizers.
+	Payload string `json:"payload"`
+	// Envelope is the RLP encoded payload and payload signatures, signed by the payer.
+	Envelope           string                `json:"envelope"`
+	PayloadSignatures  []TestVectorSignature `json:"payloadSignatures"`
+	EnvelopeSignatures []TestVectorSignature `json:"envelopeSignatures"`
+}
+
+// TestVectorSignature is a signature of a test vector, with the key which produced it.
+//
+// The key is looked up when the transaction is recorded, so it is missing
+// if the account or key did not exist then.
+type TestVectorSignature struct {
+	Address   string `json:"address"`
+	KeyIndex  uint64 `json:"keyIndex"`
+	Signature string `json:"signature"`
+	PublicKey string `json:"publicKey,omitempty"`
+	SigAlgo   string `json:"sigAlgo,omitempty"`
+	HashAlgo  string `json:"hashAlgo,omitempty"`
+}
+
+// TestVectorRecorder records a test vector for every successful transaction.
+//
+// Only the most recent test vectors are kept, older ones are discarded once
+// the limit is reached.
+type TestVectorRecorder struct {
+	blockchain emulator.Emulator
+	limit      int
+
+	mu      sync.Mutex
+	vectors []TestVector
+}
+
+// NewTestVectorRecorder returns a recorder looking up the keys of the signers
+// on the given blockchain, and keeping up to limit test vectors. If limit is
+// not positive, DefaultTestVectorLimit is used.
+func NewTestVectorRecorder(blockchain emulator.Emulator, limit int) *TestVectorRecorder {
+	if limit <= 0 {
+		limit = DefaultTestVectorLimit
+	}
+	return &TestVectorRecorder{
+		blockchain: blockchain,
+		limit:      limit,
+	}
+}
+
+// Record is an emulator.TransactionExecutedCallback recording the transaction
+// if it succeeded, as failed transactions may have invalid signatures.
+func (r *TestVectorRecorder) Record(tx *flowgo.TransactionBody, result *types.TransactionResult) {
+	if !result.Succeeded() {
+		return
+	}
+
+	vector := TestVector{
+		TransactionID:      tx.ID().String(),
+		DomainTag:          hex.EncodeToString(flowgo.TransactionDomainTag[:]),
+		Payload:            hex.EncodeToString(tx.PayloadMessage()),
+		Envelope:           hex.EncodeToString(tx.EnvelopeMessage()),
+		PayloadSignatures:  testVectorSignatures(tx.PayloadSignatures),
+		EnvelopeSignatures: testVectorSignatures(tx.EnvelopeSignatures),
+	}
+
+	// the keys are resolved now, as they may be rotated before the test vectors are exported
+	accounts := map[string]*flowgo.Account{}
+	for i := range vector.PayloadSignatures {
+		r.resolveKey(accounts, &vector.PayloadSignatures[i])
+	}
+	for i := range vector.EnvelopeSignatures {
+		r.resolveKey(accounts, &vector.EnvelopeSignatures[i])
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.vectors) >= r.limit {
+		dropped := len(r.vectors) - r.limit + 1
+		r.vectors = append(r.vectors[:0], r.vectors[dropped:]...)
+	}
+	r.vectors = append(r.vectors, vector)
+}
+
+// resolveKey sets the key which produced the given signature, looking up the
+// accounts of the signers once per transaction.
+func (r *TestVectorRecorder) resolveKey(accounts map[string]*flowgo.Account, signature *TestVectorSignature) {
+	account, ok := accounts[signature.Address]
+	if !ok {
+		account, _ = r.blockchain.GetAccount(context.Background(), flowgo.HexToAddress(signature.Address))
+		accounts[signature.Address] = account
+	}
+	if account == nil || signature.KeyIndex >= uint64(len(account.Keys)) {
+		return
+	}
+
+	key := account.Keys[signature.KeyIndex]
+	signature.PublicKey = hex.EncodeToString(key.PublicKey.Encode())
+	signature.SigAlgo = key.SignAlgo.String()
+	signature.HashAlgo = key.HashAlgo.String()
+}
+
+// Vectors returns the recorded test vectors, in execution order.
+func (r *TestVectorRecorder) Vectors() []TestVector {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vectors := make([]TestVector, len(r.vectors))
+	for i, vector := range r.vectors {
+		vector.PayloadSignatures = append([]TestVectorSignature(nil), vector.PayloadSignatures...)
+		vector.EnvelopeSignatures = append([]TestVectorSignature(nil), vector.EnvelopeSignatures...)
+		vectors[i] = vector
+	}
+	return vectors
+}
+
+// Reset discards the recorded test vectors.
+func (r *TestVectorRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.vectors = nil
+}
+
+func testVectorSignatures(signatures []flowgo.TransactionSignature) []TestVectorSignature {
+	result := make([]TestVectorSignature, len(signatures))
+	for i, signature := range signatures {
+		result[i] = TestVectorSignature{
+			Address:   signature.Address.HexWithPrefix(),
+			KeyIndex:  signature.KeyIndex,
+			Signature: hex.EncodeToString(signature.Signature),
+		}
+	}
+	return result
+}
+
+// WithTestVectors serves the test vectors recorded by the given recorder.
+func WithTestVectors(recorder *TestVectorRecorder) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		m.testVectors = recorder
+	}
+}
+
+// TestVectors returns the recorded test vectors, with the keys of the signers.
+func (m EmulatorAPIServer) TestVectors(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.testVectors == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err := json.NewEncoder(w).Encode(m.testVectors.Vectors())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// ResetTestVectors discards the recorded test vectors.
+func (m EmulatorAPIServer) ResetTestVectors(w http.ResponseWriter, _ *http.Request) {
+	if m.testVectors == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	m.testVectors.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
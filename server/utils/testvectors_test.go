@@ -0,0 +1,140 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestTestVectorsEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	recorder := utils.NewTestVectorRecorder(b, 0)
+	b.OnTransactionExecuted(recorder.Record)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil, utils.WithTestVectors(recorder)))
+	defer api.Close()
+
+	getVectors := func(t *testing.T) []utils.TestVector {
+		resp, err := http.Get(api.URL + "/emulator/testVectors")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var vectors []utils.TestVector
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&vectors))
+		return vectors
+	}
+
+	succeeded := sendTransaction(t, b, `transaction { execute { log("ok") } }`)
+	sendTransaction(t, b, `transaction { execute { panic("failed") } }`)
+
+	vectors := getVectors(t)
+	require.Len(t, vectors, 1)
+
+	vector := vectors[0]
+	assert.Equal(t, succeeded.String(), vector.TransactionID)
+	assert.Empty(t, vector.PayloadSignatures)
+	require.Len(t, vector.EnvelopeSignatures, 1)
+
+	signature := vector.EnvelopeSignatures[0]
+	assert.Equal(t, "0x"+b.ServiceKey().Address.Hex(), signature.Address)
+
+	// the envelope signature verifies with the exported key
+	publicKey, err := crypto.DecodePublicKeyHex(crypto.StringToSignatureAlgorithm(signature.SigAlgo), signature.PublicKey)
+	require.NoError(t, err)
+
+	hasher, err := crypto.NewHasher(crypto.StringToHashAlgorithm(signature.HashAlgo))
+	require.NoError(t, err)
+
+	domainTag, err := hex.DecodeString(vector.DomainTag)
+	require.NoError(t, err)
+	envelope, err := hex.DecodeString(vector.Envelope)
+	require.NoError(t, err)
+	sig, err := hex.DecodeString(signature.Signature)
+	require.NoError(t, err)
+
+	valid, err := publicKey.Verify(sig, append(domainTag, envelope...), hasher)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	t.Run("copies", func(t *testing.T) {
+		vectors := recorder.Vectors()
+		vectors[0].EnvelopeSignatures[0].PublicKey = ""
+
+		assert.Equal(t, signature.PublicKey, recorder.Vectors()[0].EnvelopeSignatures[0].PublicKey)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodDelete, api.URL+"/emulator/testVectors", nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		assert.Empty(t, getVectors(t))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+		defer api.Close()
+
+		resp, err := http.Get(api.URL + "/emulator/testVectors")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestTestVectorRecorderLimit(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	recorder := utils.NewTestVectorRecorder(b, 2)
+	b.OnTransactionExecuted(recorder.Record)
+
+	sendTransaction(t, b, `transaction { execute { log(1) } }`)
+	second := sendTransaction(t, b, `transaction { execute { log(2) } }`)
+	third := sendTransaction(t, b, `transaction { execute { log(3) } }`)
+
+	vectors := recorder.Vectors()
+	require.Len(t, vectors, 2)
+	assert.Equal(t, second.String(), vectors[0].TransactionID)
+	assert.Equal(t, third.String(), vectors[1].TransactionID)
+}
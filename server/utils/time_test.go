@@ -0,0 +1,173 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestAdvanceTimeEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	advance := func(t *testing.T, seconds string) (int, *utils.TimeResponse) {
+		resp, err := http.Post(api.URL+"/emulator/advanceTime?seconds="+seconds, "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var timeResponse utils.TimeResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&timeResponse))
+		return resp.StatusCode, &timeResponse
+	}
+
+	t.Run("advance", func(t *testing.T) {
+		status, first := advance(t, "3600")
+		require.Equal(t, http.StatusOK, status)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), first.Timestamp, time.Minute)
+
+		status, second := advance(t, "86400")
+		require.Equal(t, http.StatusOK, status)
+		assert.WithinDuration(t, first.Timestamp.Add(24*time.Hour), second.Timestamp, time.Minute)
+
+		block, err := b.CommitBlock()
+		require.NoError(t, err)
+		assert.WithinDuration(t, second.Timestamp, block.Header.Timestamp, time.Minute)
+	})
+
+	t.Run("invalid seconds", func(t *testing.T) {
+		status, _ := advance(t, "-1")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = advance(t, "")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestCommitBlockTimestampEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	commit := func(t *testing.T, timestamp string) int {
+		resp, err := http.Post(api.URL+"/emulator/newBlock?timestamp="+url.QueryEscape(timestamp), "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	auctionEnd := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+
+	t.Run("exact timestamp", func(t *testing.T) {
+		txID := addTransaction(t, b, `transaction { execute { log(getCurrentBlock().timestamp) } }`)
+
+		status := commit(t, auctionEnd.Format(time.RFC3339))
+		require.Equal(t, http.StatusOK, status)
+
+		block, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, auctionEnd, block.Header.Timestamp)
+
+		logs, err := b.GetTransactionLogs(context.Background(), txID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{fmt.Sprintf("%d.00000000", auctionEnd.Unix())}, logs)
+	})
+
+	t.Run("unix seconds", func(t *testing.T) {
+		timestamp := auctionEnd.Add(time.Minute)
+
+		status := commit(t, strconv.FormatInt(timestamp.Unix(), 10))
+		require.Equal(t, http.StatusOK, status)
+
+		block, err := b.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, timestamp, block.Header.Timestamp)
+	})
+
+	t.Run("following blocks", func(t *testing.T) {
+		// the clock reads the timestamp of the latest block, instead of adding up the offsets
+		assert.WithinDuration(t, auctionEnd.Add(time.Minute), b.PendingBlockTimestamp(), time.Minute)
+
+		block, err := b.CommitBlock()
+		require.NoError(t, err)
+		assert.False(t, block.Header.Timestamp.Before(auctionEnd.Add(time.Minute)))
+	})
+
+	t.Run("before parent", func(t *testing.T) {
+		status := commit(t, auctionEnd.Format(time.RFC3339))
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		status := commit(t, "tomorrow")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func addTransaction(t *testing.T, b *emulator.Blockchain, script string) flowgo.Identifier {
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(script)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	flowTx := convert.SDKTransactionToFlow(*tx)
+
+	err = b.AddTransaction(context.Background(), *flowTx)
+	require.NoError(t, err)
+
+	return flowTx.ID()
+}
@@ -0,0 +1,107 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/onflow/flow-emulator/types"
+	"github.com/onflow/flow-emulator/utils/timetravel"
+)
+
+// TimeTravelResponse reports the height at which the queries of a session are served.
+type TimeTravelResponse struct {
+	Session string `json:"session"`
+	Height  uint64 `json:"height"`
+	// Latest is true if the session queries the latest block.
+	Latest bool `json:"latest"`
+}
+
+// TimeTravel switches the read-only Access API queries of the session given by the
+// session header to the block height given by the height parameter (POST), reports
+// the height of the session (GET), or switches it back to the latest block (DELETE).
+// Queries of other clients are not affected.
+func (m EmulatorAPIServer) TimeTravel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.adapter == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	session := timetravel.FromHeaders(r.Header.Get)
+	if session == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("missing %s header", timetravel.Header),
+		})
+		return
+	}
+
+	sessions := m.adapter.Sessions()
+
+	switch r.Method {
+	case http.MethodPost:
+		height, err := strconv.ParseUint(r.FormValue("height"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid height"})
+			return
+		}
+
+		_, err = m.emulator.GetBlockByHeight(r.Context(), height)
+		if err != nil {
+			if _, ok := err.(types.BlockNotFoundError); ok {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		sessions.Set(session, height)
+
+	case http.MethodDelete:
+		sessions.Clear(session)
+	}
+
+	response := TimeTravelResponse{Session: session}
+	if height, ok := sessions.Height(session); ok {
+		response.Height = height
+	} else {
+		block, err := m.emulator.GetLatestBlock(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		response.Height = block.Header.Height
+		response.Latest = true
+	}
+
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
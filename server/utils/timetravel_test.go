@@ -0,0 +1,139 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/utils/timetravel"
+)
+
+func TestTimeTravelEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := b.CommitBlock()
+		require.NoError(t, err)
+	}
+
+	logger := zerolog.Nop()
+	adapter := adapters.NewAccessAdapter(&logger, b)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, adapter))
+	defer api.Close()
+
+	timeTravel := func(t *testing.T, method string, session string, query string) (int, *utils.TimeTravelResponse) {
+		req, err := http.NewRequest(method, api.URL+"/emulator/timetravel"+query, nil)
+		require.NoError(t, err)
+		if session != "" {
+			req.Header.Set(timetravel.Header, session)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var response utils.TimeTravelResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, &response
+	}
+
+	queryHeight := func(t *testing.T, session string) uint64 {
+		ctx := context.Background()
+		if session != "" {
+			ctx = timetravel.WithContext(ctx, session)
+		}
+
+		header, _, err := adapter.GetLatestBlockHeader(ctx, true)
+		require.NoError(t, err)
+
+		result, err := adapter.ExecuteScriptAtLatestBlock(
+			ctx,
+			[]byte(`pub fun main(): UInt64 { return getCurrentBlock().height }`),
+			nil,
+		)
+		require.NoError(t, err)
+
+		value, err := jsoncdc.Decode(nil, result)
+		require.NoError(t, err)
+		assert.Equal(t, header.Height, value.ToGoValue())
+
+		return header.Height
+	}
+
+	t.Run("travel", func(t *testing.T) {
+		status, response := timeTravel(t, http.MethodPost, "alice", "?height=1")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, utils.TimeTravelResponse{Session: "alice", Height: 1}, *response)
+
+		assert.Equal(t, uint64(1), queryHeight(t, "alice"))
+
+		// other clients still query the latest block
+		assert.Equal(t, uint64(3), queryHeight(t, "bob"))
+		assert.Equal(t, uint64(3), queryHeight(t, ""))
+
+		status, response = timeTravel(t, http.MethodGet, "alice", "")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, uint64(1), response.Height)
+		assert.False(t, response.Latest)
+	})
+
+	t.Run("return", func(t *testing.T) {
+		status, response := timeTravel(t, http.MethodDelete, "alice", "")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, utils.TimeTravelResponse{Session: "alice", Height: 3, Latest: true}, *response)
+
+		assert.Equal(t, uint64(3), queryHeight(t, "alice"))
+	})
+
+	t.Run("unknown height", func(t *testing.T) {
+		status, _ := timeTravel(t, http.MethodPost, "alice", "?height=100")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("invalid height", func(t *testing.T) {
+		status, _ := timeTravel(t, http.MethodPost, "alice", "?height=latest")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("missing session", func(t *testing.T) {
+		status, _ := timeTravel(t, http.MethodPost, "", "?height=1")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
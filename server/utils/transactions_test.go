@@ -0,0 +1,107 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestSendTransactionEndpoint(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	send := func(t *testing.T, request utils.SendTransactionRequest) (int, map[string]string) {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		resp, err := http.Post(api.URL+"/emulator/transactions", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var response map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, response
+	}
+
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(`transaction { execute { log("hello") } }`)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address)
+
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+
+	err = tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer)
+	require.NoError(t, err)
+
+	t.Run("submitted", func(t *testing.T) {
+		status, response := send(t, utils.SendTransactionRequest{
+			Transaction: hex.EncodeToString(tx.Encode()),
+			Priority:    5,
+		})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, tx.ID().String(), response["id"])
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, tx.ID().String(), results[0].TransactionID.String())
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		status, response := send(t, utils.SendTransactionRequest{
+			Transaction: hex.EncodeToString(tx.Encode()),
+		})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.NotEmpty(t, response["error"])
+	})
+
+	t.Run("invalid encoding", func(t *testing.T) {
+		status, _ := send(t, utils.SendTransactionRequest{Transaction: "zz"})
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = send(t, utils.SendTransactionRequest{Transaction: "c0ffee"})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	latest, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), latest.Header.Height)
+}
@@ -0,0 +1,291 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// WithTransactionMaxByteSize sets the maximum size of transactions sent to the admin API,
+// or uploaded in chunks.
+func WithTransactionMaxByteSize(size uint64) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		m.transactionMaxByteSize = size
+	}
+}
+
+// TransactionUpload is a transaction uploaded in chunks, e.g. because its arguments are too
+// large for a single request.
+type TransactionUpload struct {
+	ID string `json:"id"`
+	// Size is the number of bytes uploaded so far.
+	Size int `json:"size"`
+	// MaxSize is the maximum size of the transaction.
+	MaxSize uint64 `json:"maxSize"`
+}
+
+// DefaultTransactionUploadTTL is how long an upload is kept without being appended to,
+// and DefaultMaxTransactionUploads the number of uploads which can be in progress at once.
+const (
+	DefaultTransactionUploadTTL  = 10 * time.Minute
+	DefaultMaxTransactionUploads = 64
+)
+
+// WithTransactionUploadLimits sets the maximum number of uploads in progress at once,
+// and how long an upload is kept without being appended to.
+func WithTransactionUploadLimits(maxUploads int, ttl time.Duration) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		m.uploads.maxUploads = maxUploads
+		m.uploads.ttl = ttl
+	}
+}
+
+// transactionUploads holds the transactions being uploaded, by upload ID.
+// Uploads not appended to within the TTL are discarded.
+type transactionUploads struct {
+	mu         sync.Mutex
+	count      uint64
+	uploads    map[string]*transactionUpload
+	maxUploads int
+	ttl        time.Duration
+}
+
+// transactionUpload is the RLP encoding of a transaction being uploaded. Its lock
+// serializes the chunks appended to it, without blocking the other uploads.
+type transactionUpload struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+	// removed is set once the upload is submitted, cancelled or expired
+	removed bool
+	// lastActive is the time the upload was last appended to, in Unix nanoseconds
+	lastActive atomic.Int64
+}
+
+func (u *transactionUpload) touch() {
+	u.lastActive.Store(time.Now().UnixNano())
+}
+
+func newTransactionUploads() *transactionUploads {
+	return &transactionUploads{
+		uploads:    make(map[string]*transactionUpload),
+		maxUploads: DefaultMaxTransactionUploads,
+		ttl:        DefaultTransactionUploadTTL,
+	}
+}
+
+func (u *transactionUploads) start() (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.sweep()
+	if len(u.uploads) >= u.maxUploads {
+		return "", fmt.Errorf("too many transaction uploads in progress, at most %d are allowed", u.maxUploads)
+	}
+
+	u.count++
+	id := strconv.FormatUint(u.count, 10)
+	upload := &transactionUpload{}
+	upload.touch()
+	u.uploads[id] = upload
+
+	return id, nil
+}
+
+func (u *transactionUploads) get(id string) (*transactionUpload, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.sweep()
+	upload, ok := u.uploads[id]
+	return upload, ok
+}
+
+// remove ends an upload. Chunks being appended to it are waited for.
+func (u *transactionUploads) remove(id string) (*transactionUpload, bool) {
+	u.mu.Lock()
+	upload, ok := u.uploads[id]
+	delete(u.uploads, id)
+	u.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	upload.mu.Lock()
+	defer upload.mu.Unlock()
+
+	upload.removed = true
+	return upload, true
+}
+
+// sweep discards the uploads not appended to within the TTL. It must be called with the lock held.
+func (u *transactionUploads) sweep() {
+	expiry := time.Now().Add(-u.ttl).UnixNano()
+	for id, upload := range u.uploads {
+		if upload.lastActive.Load() < expiry {
+			delete(u.uploads, id)
+		}
+	}
+}
+
+// StartTransactionUpload starts uploading a transaction in chunks.
+func (m EmulatorAPIServer) StartTransactionUpload(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := m.uploads.start()
+	if err != nil {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	upload := TransactionUpload{
+		ID:      id,
+		MaxSize: m.transactionMaxByteSize,
+	}
+
+	err = json.NewEncoder(w).Encode(upload)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// AppendTransactionUpload appends the request body, a chunk of the RLP encoding of the
+// signed transaction, to an upload.
+//
+// If the offset query parameter is set, it must be the number of bytes uploaded so far,
+// so a chunk is not appended twice when a request is retried.
+func (m EmulatorAPIServer) AppendTransactionUpload(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := mux.Vars(r)["upload"]
+
+	upload, ok := m.uploads.get(id)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// chunks of an upload are appended one after the other
+	upload.mu.Lock()
+	defer upload.mu.Unlock()
+
+	if upload.removed {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	upload.touch()
+	defer upload.touch()
+
+	buffer := &upload.buffer
+
+	if value := r.URL.Query().Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("invalid offset: %s", value)})
+			return
+		}
+		if offset != buffer.Len() {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"error": fmt.Sprintf("offset %d does not match the %d bytes uploaded", offset, buffer.Len()),
+			})
+			return
+		}
+	}
+
+	remaining := int64(m.transactionMaxByteSize) - int64(buffer.Len())
+	chunk, err := io.ReadAll(http.MaxBytesReader(w, r.Body, remaining))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"error": fmt.Sprintf("transaction exceeds the maximum size of %d bytes", m.transactionMaxByteSize),
+			})
+			return
+		}
+		writeBodyError(w, err)
+		return
+	}
+	buffer.Write(chunk)
+
+	err = json.NewEncoder(w).Encode(TransactionUpload{
+		ID:      id,
+		Size:    buffer.Len(),
+		MaxSize: m.transactionMaxByteSize,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// SubmitTransactionUploadRequest is the optional body of a submit request.
+type SubmitTransactionUploadRequest struct {
+	// Priority orders the transaction in the pending block, highest first.
+	Priority int `json:"priority"`
+}
+
+// SubmitTransactionUpload submits an uploaded transaction, and ends the upload.
+func (m EmulatorAPIServer) SubmitTransactionUpload(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var request SubmitTransactionUploadRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	upload, ok := m.uploads.remove(mux.Vars(r)["upload"])
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	m.submitTransaction(w, r, upload.buffer.Bytes(), request.Priority)
+}
+
+// CancelTransactionUpload discards an upload.
+func (m EmulatorAPIServer) CancelTransactionUpload(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_, ok := m.uploads.remove(mux.Vars(r)["upload"])
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,251 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestTransactionUploads(t *testing.T) {
+
+	t.Parallel()
+
+	const maxSize = 4_000_000
+
+	b, err := emulator.New(emulator.WithTransactionMaxByteSize(maxSize))
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil, utils.WithTransactionMaxByteSize(maxSize)))
+	defer api.Close()
+
+	// transactions with a large argument, larger than the default maximum size
+	serviceKey := b.ServiceKey()
+	newTransaction := func(t *testing.T) []byte {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction(data: String) { execute { log(data.length) } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(serviceKey.Address, serviceKey.Index, serviceKey.SequenceNumber).
+			SetPayer(serviceKey.Address)
+
+		err := tx.AddArgument(cadence.String(strings.Repeat("a", 2_000_000)))
+		require.NoError(t, err)
+
+		signer, err := serviceKey.Signer()
+		require.NoError(t, err)
+		require.NoError(t, tx.SignEnvelope(serviceKey.Address, serviceKey.Index, signer))
+		serviceKey.SequenceNumber++
+
+		return tx.Encode()
+	}
+
+	request := func(t *testing.T, method string, path string, contentType string, body []byte) (int, map[string]any) {
+		req, err := http.NewRequest(method, api.URL+"/emulator"+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var response map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&response)
+		return resp.StatusCode, response
+	}
+
+	executeTransaction := func(t *testing.T, id any) {
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, id, results[0].TransactionID.String())
+		require.NoError(t, results[0].Error)
+		assert.Equal(t, []string{"2000000"}, results[0].Logs)
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		encoded := newTransaction(t)
+		require.Greater(t, len(encoded), flowgo.DefaultMaxTransactionByteSize)
+
+		status, response := request(t, http.MethodPost, "/transactions?priority=1", utils.OctetStreamContentType, encoded)
+		require.Equal(t, http.StatusOK, status, response)
+
+		executeTransaction(t, response["id"])
+	})
+
+	t.Run("upload in chunks", func(t *testing.T) {
+		encoded := newTransaction(t)
+
+		status, response := request(t, http.MethodPost, "/transactions/uploads", utils.JSONContentType, nil)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, float64(maxSize), response["maxSize"])
+		path := "/transactions/uploads/" + response["id"].(string)
+
+		const chunkSize = 1_000_000
+		for offset := 0; offset < len(encoded); offset += chunkSize {
+			end := offset + chunkSize
+			if end > len(encoded) {
+				end = len(encoded)
+			}
+
+			status, response := request(t, http.MethodPatch, fmt.Sprintf("%s?offset=%d", path, offset), utils.OctetStreamContentType, encoded[offset:end])
+			require.Equal(t, http.StatusOK, status, response)
+			assert.Equal(t, float64(end), response["size"])
+		}
+
+		// a retried chunk is not appended twice
+		status, _ = request(t, http.MethodPatch, path+"?offset=0", utils.OctetStreamContentType, encoded[:chunkSize])
+		assert.Equal(t, http.StatusConflict, status)
+
+		status, response = request(t, http.MethodPost, path+"/submit", utils.JSONContentType, []byte(`{"priority": 1}`))
+		require.Equal(t, http.StatusOK, status, response)
+
+		executeTransaction(t, response["id"])
+
+		// the upload ends when it is submitted
+		status, _ = request(t, http.MethodPost, path+"/submit", utils.JSONContentType, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("maximum size", func(t *testing.T) {
+		status, response := request(t, http.MethodPost, "/transactions", utils.OctetStreamContentType, make([]byte, maxSize+1))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status, response)
+
+		status, response = request(t, http.MethodPost, "/transactions/uploads", utils.JSONContentType, nil)
+		require.Equal(t, http.StatusOK, status)
+		path := "/transactions/uploads/" + response["id"].(string)
+
+		status, _ = request(t, http.MethodPatch, path, utils.OctetStreamContentType, make([]byte, maxSize))
+		require.Equal(t, http.StatusOK, status)
+
+		status, _ = request(t, http.MethodPatch, path, utils.OctetStreamContentType, []byte{1})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+
+		status, _ = request(t, http.MethodDelete, path, utils.JSONContentType, nil)
+		assert.Equal(t, http.StatusNoContent, status)
+		status, _ = request(t, http.MethodDelete, path, utils.JSONContentType, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
+
+func TestTransactionUploadLimits(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	start := func(t *testing.T, api *httptest.Server) (int, string) {
+		resp, err := http.Post(api.URL+"/emulator/transactions/uploads", utils.JSONContentType, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var upload utils.TransactionUpload
+		_ = json.NewDecoder(resp.Body).Decode(&upload)
+		return resp.StatusCode, upload.ID
+	}
+
+	t.Run("expiry and maximum uploads", func(t *testing.T) {
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(
+			b,
+			nil,
+			utils.WithTransactionUploadLimits(1, 100*time.Millisecond),
+		))
+		defer api.Close()
+
+		status, id := start(t, api)
+		require.Equal(t, http.StatusOK, status)
+
+		status, _ = start(t, api)
+		assert.Equal(t, http.StatusTooManyRequests, status)
+
+		// the abandoned upload expires
+		time.Sleep(200 * time.Millisecond)
+
+		status, _ = start(t, api)
+		assert.Equal(t, http.StatusOK, status)
+
+		req, err := http.NewRequest(http.MethodPatch, api.URL+"/emulator/transactions/uploads/"+id, bytes.NewReader([]byte{1}))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("slow chunk", func(t *testing.T) {
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+		defer api.Close()
+
+		status, id := start(t, api)
+		require.Equal(t, http.StatusOK, status)
+
+		// a chunk whose body is not sent yet
+		body, writer := io.Pipe()
+		appended := make(chan int, 1)
+		go func() {
+			req, err := http.NewRequest(http.MethodPatch, api.URL+"/emulator/transactions/uploads/"+id, body)
+			if err != nil {
+				appended <- 0
+				return
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				appended <- 0
+				return
+			}
+			resp.Body.Close()
+			appended <- resp.StatusCode
+		}()
+
+		// other uploads are not blocked by the slow chunk
+		started := make(chan int, 1)
+		go func() {
+			status, _ := start(t, api)
+			started <- status
+		}()
+
+		select {
+		case status := <-started:
+			assert.Equal(t, http.StatusOK, status)
+		case <-time.After(5 * time.Second):
+			t.Fatal("starting an upload is blocked by a slow chunk of another upload")
+		}
+
+		_, err := writer.Write([]byte{1})
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		assert.Equal(t, http.StatusOK, <-appended)
+	})
+}
@@ -0,0 +1,144 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+)
+
+func TestAccountVaultsEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+	defer api.Close()
+
+	serviceAddress := b.ServiceKey().Address
+
+	getVaults := func(t *testing.T, address string) (int, []emulator.Vault) {
+		resp, err := http.Get(api.URL + "/emulator/accounts/" + address + "/vaults")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var vaults []emulator.Vault
+		err = json.NewDecoder(resp.Body).Decode(&vaults)
+		require.NoError(t, err)
+
+		return resp.StatusCode, vaults
+	}
+
+	repairVaults := func(t *testing.T, address string) (int, *utils.VaultRepairResponse) {
+		resp, err := http.Post(api.URL+"/emulator/accounts/"+address+"/vaults/repair", "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var response utils.VaultRepairResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		require.NoError(t, err)
+
+		return resp.StatusCode, &response
+	}
+
+	t.Run("invalid address", func(t *testing.T) {
+		status, _ := getVaults(t, "zz")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = repairVaults(t, "zz")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		address, err := b.GetNetworkParameters().ChainID.Chain().AddressAtIndex(1000)
+		require.NoError(t, err)
+
+		status, _ := getVaults(t, address.Hex())
+		assert.Equal(t, http.StatusNotFound, status)
+
+		status, _ = repairVaults(t, address.Hex())
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		status, vaults := getVaults(t, serviceAddress.Hex())
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, vaults, 1)
+		assert.True(t, vaults[0].Healthy())
+
+		status, response := repairVaults(t, serviceAddress.Hex())
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, response.TransactionID)
+	})
+
+	t.Run("repair", func(t *testing.T) {
+		tx := flowsdk.NewTransaction().
+			SetScript([]byte(`transaction { prepare(signer: AuthAccount) { signer.unlink(/public/flowTokenReceiver) } }`)).
+			SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+			SetProposalKey(serviceAddress, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+			SetPayer(serviceAddress).
+			AddAuthorizer(serviceAddress)
+
+		signer, err := b.ServiceKey().Signer()
+		require.NoError(t, err)
+
+		err = tx.SignEnvelope(serviceAddress, b.ServiceKey().Index, signer)
+		require.NoError(t, err)
+
+		err = b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx))
+		require.NoError(t, err)
+
+		_, results, err := b.ExecuteAndCommitBlock()
+		require.NoError(t, err)
+		require.NoError(t, results[0].Error)
+
+		status, vaults := getVaults(t, serviceAddress.Hex())
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, vaults, 1)
+		assert.Equal(t, emulator.VaultLinkMissing, vaults[0].Receiver.Status)
+
+		status, response := repairVaults(t, serviceAddress.Hex())
+		require.Equal(t, http.StatusOK, status)
+		assert.NotEmpty(t, response.TransactionID)
+		require.Len(t, response.Vaults, 1)
+		assert.True(t, response.Vaults[0].Healthy())
+	})
+}
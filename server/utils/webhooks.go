@@ -0,0 +1,101 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/onflow/flow-emulator/server/webhook"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// WithWebhooks serves the webhooks of the given dispatcher.
+func WithWebhooks(dispatcher *webhook.Dispatcher) EmulatorAPIServerOption {
+	return func(m *EmulatorAPIServer) {
+		m.webhooks = dispatcher
+	}
+}
+
+// Webhooks returns the webhooks events are posted to, oldest first.
+func (m EmulatorAPIServer) Webhooks(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.webhooks == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err := json.NewEncoder(w).Encode(m.webhooks.Webhooks())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// AddWebhook registers an endpoint the events of a contract are posted to.
+func (m EmulatorAPIServer) AddWebhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.webhooks == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var spec webhook.Spec
+	err := json.NewDecoder(r.Body).Decode(&spec)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	hook, err := m.webhooks.Add(spec)
+	if err != nil {
+		var invalidArgumentErr *types.InvalidArgumentError
+		if errors.As(err, &invalidArgumentErr) {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(hook)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// RemoveWebhook stops posting events to a webhook.
+func (m EmulatorAPIServer) RemoveWebhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if m.webhooks == nil || !m.webhooks.Remove(mux.Vars(r)["id"]) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
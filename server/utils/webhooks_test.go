@@ -0,0 +1,107 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/utils"
+	"github.com/onflow/flow-emulator/server/webhook"
+)
+
+func TestWebhookEndpoints(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	t.Run("disabled", func(t *testing.T) {
+		api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil))
+		defer api.Close()
+
+		resp, err := http.Get(api.URL + "/emulator/webhooks")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	logger := zerolog.Nop()
+	dispatcher := webhook.New(&logger, b)
+
+	api := httptest.NewServer(utils.NewEmulatorAPIServer(b, nil, utils.WithWebhooks(dispatcher)))
+	defer api.Close()
+
+	add := func(t *testing.T, body string) *http.Response {
+		resp, err := http.Post(api.URL+"/emulator/webhooks", "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		return resp
+	}
+
+	address := b.ServiceKey().Address.Hex()
+
+	t.Run("invalid webhook", func(t *testing.T) {
+		resp := add(t, `{"url": "http://example.com"}`)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("add, list and remove", func(t *testing.T) {
+		resp := add(t, `{"url": "http://example.com", "address": "`+address+`", "event": "Minted"}`)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var hook webhook.Webhook
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&hook))
+		assert.Equal(t, "Minted", hook.Event)
+
+		listResp, err := http.Get(api.URL + "/emulator/webhooks")
+		require.NoError(t, err)
+		defer listResp.Body.Close()
+
+		var webhooks []webhook.Webhook
+		require.NoError(t, json.NewDecoder(listResp.Body).Decode(&webhooks))
+		require.Len(t, webhooks, 1)
+		assert.Equal(t, hook.ID, webhooks[0].ID)
+
+		remove := func() int {
+			req, err := http.NewRequest(http.MethodDelete, api.URL+"/emulator/webhooks/"+hook.ID, nil)
+			require.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		assert.Equal(t, http.StatusNoContent, remove())
+		assert.Equal(t, http.StatusNotFound, remove())
+		assert.Empty(t, dispatcher.Webhooks())
+	})
+}
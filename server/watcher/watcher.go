@@ -0,0 +1,337 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package watcher redeploys Cadence contracts to the emulator as their files change on disk.
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/onflow/cadence/runtime/parser"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/templates"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+// DefaultDebounce is the time a file must be left unchanged before it is redeployed,
+// so the several events emitted by editors saving a file trigger a single reload.
+const DefaultDebounce = 200 * time.Millisecond
+
+// maxReloads is the number of reloads kept for the admin API.
+const maxReloads = 100
+
+// Reload is the redeployment of a contract triggered by a change to its file.
+type Reload struct {
+	File          string `json:"file"`
+	Contract      string `json:"contract,omitempty"`
+	Address       string `json:"address"`
+	TransactionID string `json:"transactionId,omitempty"`
+	// Pending is true if the transaction was not committed yet, when blocks are
+	// not committed automatically. It is committed with the next block.
+	Pending bool      `json:"pending,omitempty"`
+	Time    time.Time `json:"time"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// ContractWatcher watches a directory of Cadence contracts, and redeploys
+// each changed contract to the service account of the emulator.
+//
+// Contracts not yet deployed are added to the service account,
+// contracts which are already deployed are updated.
+type ContractWatcher struct {
+	logger   *zerolog.Logger
+	emulator emulator.Emulator
+	path     string
+	debounce time.Duration
+	watcher  *fsnotify.Watcher
+	done     chan bool
+
+	// reloadMu serializes reloads, which all send transactions of the service key.
+	reloadMu sync.Mutex
+	// submitted is the code of the contracts redeployed by transactions which
+	// are not committed yet, by contract name.
+	submitted map[string]string
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+	reloads []Reload
+}
+
+func New(
+	logger *zerolog.Logger,
+	emulator emulator.Emulator,
+	path string,
+	debounce time.Duration,
+) (*ContractWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ContractWatcher{
+		logger:    logger,
+		emulator:  emulator,
+		path:      path,
+		debounce:  debounce,
+		watcher:   watcher,
+		done:      make(chan bool, 1),
+		submitted: make(map[string]string),
+		pending:   make(map[string]*time.Timer),
+	}
+
+	// fsnotify does not watch directories recursively
+	err = filepath.WalkDir(path, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+		return watcher.Add(dir)
+	})
+	if err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	return w, nil
+}
+
+func (w *ContractWatcher) Start() error {
+	defer w.watcher.Close()
+
+	for {
+		select {
+		case event := <-w.watcher.Events:
+			w.handle(event)
+		case err := <-w.watcher.Errors:
+			w.logger.Error().Err(err).Str("path", w.path).Msg("❗  Failed to watch contracts")
+		case <-w.done:
+			w.mu.Lock()
+			for _, timer := range w.pending {
+				timer.Stop()
+			}
+			w.mu.Unlock()
+			return nil
+		}
+	}
+}
+
+func (w *ContractWatcher) Stop() {
+	w.done <- true
+}
+
+func (w *ContractWatcher) handle(event fsnotify.Event) {
+	if event.Has(fsnotify.Create) {
+		info, err := os.Stat(event.Name)
+		if err == nil && info.IsDir() {
+			_ = w.watcher.Add(event.Name)
+			return
+		}
+	}
+
+	if !strings.HasSuffix(event.Name, ".cdc") {
+		return
+	}
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if timer, ok := w.pending[event.Name]; ok {
+		timer.Reset(w.debounce)
+		return
+	}
+
+	file := event.Name
+	w.pending[file] = time.AfterFunc(w.debounce, func() {
+		w.mu.Lock()
+		delete(w.pending, file)
+		w.mu.Unlock()
+
+		w.Reload(context.Background(), file)
+	})
+}
+
+// Reloads returns the most recent reloads, oldest first.
+func (w *ContractWatcher) Reloads() []Reload {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	reloads := make([]Reload, len(w.reloads))
+	copy(reloads, w.reloads)
+	return reloads
+}
+
+// Reload redeploys the contract in the given file, unless the deployed code is unchanged.
+// It returns nil if the contract was not redeployed.
+//
+// The transaction is committed right away only if blocks are committed automatically,
+// so pending transactions of the user are not committed by a reload.
+func (w *ContractWatcher) Reload(ctx context.Context, file string) *Reload {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	serviceAddress := w.emulator.ServiceKey().Address
+
+	reload := &Reload{
+		File:    file,
+		Address: "0x" + serviceAddress.Hex(),
+		Time:    time.Now(),
+	}
+
+	changed, err := w.deploy(ctx, reload)
+	if err != nil {
+		reload.Error = err.Error()
+		w.logger.Error().
+			Err(err).
+			Str("file", file).
+			Msg("❗  Failed to reload contract")
+	} else if !changed {
+		return nil
+	} else if reload.Pending {
+		w.logger.Info().
+			Str("file", file).
+			Str("contract", reload.Contract).
+			Str("txID", reload.TransactionID).
+			Msgf("♻️  Contract %s reload submitted, committed with the next block", reload.Contract)
+	} else {
+		w.logger.Info().
+			Str("file", file).
+			Str("contract", reload.Contract).
+			Str("txID", reload.TransactionID).
+			Msgf("♻️  Contract %s reloaded", reload.Contract)
+	}
+
+	w.mu.Lock()
+	w.reloads = append(w.reloads, *reload)
+	if len(w.reloads) > maxReloads {
+		w.reloads = w.reloads[len(w.reloads)-maxReloads:]
+	}
+	w.mu.Unlock()
+
+	return reload
+}
+
+// deploy adds or updates the contract of the reload,
+// and returns whether the deployed code changed.
+func (w *ContractWatcher) deploy(ctx context.Context, reload *Reload) (bool, error) {
+	code, err := os.ReadFile(reload.File)
+	if err != nil {
+		return false, err
+	}
+
+	name, err := contractName(code)
+	if err != nil {
+		return false, err
+	}
+	reload.Contract = name
+
+	serviceKey := w.emulator.ServiceKey()
+	serviceAddress := serviceKey.Address
+
+	if serviceKey.PrivateKey == nil {
+		return false, fmt.Errorf("not able to deploy contracts without set private key")
+	}
+
+	account, err := w.emulator.GetAccount(ctx, flowgo.Address(serviceAddress))
+	if err != nil {
+		return false, err
+	}
+
+	contract := templates.Contract{
+		Name:   name,
+		Source: string(code),
+	}
+
+	deployed, isDeployed := account.Contracts[name]
+	submitted, isSubmitted := w.submitted[name]
+	if isDeployed && string(deployed) == string(code) {
+		delete(w.submitted, name)
+		return false, nil
+	}
+	if isSubmitted && submitted == string(code) {
+		return false, nil
+	}
+
+	var tx *flowsdk.Transaction
+	if isDeployed || isSubmitted {
+		tx = templates.UpdateAccountContract(serviceAddress, contract)
+	} else {
+		tx = templates.AddAccountContract(serviceAddress, contract)
+	}
+
+	latestBlock, err := w.emulator.GetLatestBlock(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	tx.SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetReferenceBlockID(flowsdk.Identifier(latestBlock.ID()))
+
+	flowTx, err := emulator.SendServiceTransaction(ctx, w.emulator, tx, nil)
+	if err != nil {
+		return false, err
+	}
+	reload.TransactionID = flowTx.ID().String()
+
+	result, err := w.emulator.GetTransactionResult(ctx, flowTx.ID())
+	if err != nil {
+		return false, err
+	}
+	if result.Status == flowgo.TransactionStatusPending {
+		w.submitted[name] = string(code)
+		reload.Pending = true
+		return true, nil
+	}
+
+	delete(w.submitted, name)
+	if result.ErrorMessage != "" {
+		return false, fmt.Errorf("failed to deploy contract %s: %s", name, result.ErrorMessage)
+	}
+
+	return true, nil
+}
+
+// contractName returns the name of the contract or contract interface declared by the given code.
+func contractName(code []byte) (string, error) {
+	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	if err != nil {
+		return "", err
+	}
+
+	if declaration := program.SoleContractDeclaration(); declaration != nil {
+		return declaration.Identifier.Identifier, nil
+	}
+
+	if declaration := program.SoleContractInterfaceDeclaration(); declaration != nil {
+		return declaration.Identifier.Identifier, nil
+	}
+
+	return "", fmt.Errorf("file does not declare a single contract or contract interface")
+}
@@ -0,0 +1,240 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watcher_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/watcher"
+)
+
+func writeContract(t *testing.T, file string, code string) {
+	require.NoError(t, os.WriteFile(file, []byte(code), 0o644))
+}
+
+func TestReload(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+	b.EnableAutoMine()
+
+	dir := t.TempDir()
+	logger := zerolog.Nop()
+
+	w, err := watcher.New(&logger, b, dir, watcher.DefaultDebounce)
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, "Counter.cdc")
+	serviceAddress := flowgo.Address(b.ServiceKey().Address)
+
+	deployedCode := func(t *testing.T) string {
+		account, err := b.GetAccount(context.Background(), serviceAddress)
+		require.NoError(t, err)
+		return string(account.Contracts["Counter"])
+	}
+
+	t.Run("add", func(t *testing.T) {
+		code := `access(all) contract Counter { access(all) fun get(): Int { return 1 } }`
+		writeContract(t, file, code)
+
+		reload := w.Reload(context.Background(), file)
+		require.NotNil(t, reload)
+		assert.Empty(t, reload.Error)
+		assert.Equal(t, "Counter", reload.Contract)
+		assert.NotEmpty(t, reload.TransactionID)
+		assert.Equal(t, code, deployedCode(t))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		code := `access(all) contract Counter { access(all) fun get(): Int { return 2 } }`
+		writeContract(t, file, code)
+
+		reload := w.Reload(context.Background(), file)
+		require.NotNil(t, reload)
+		assert.Empty(t, reload.Error)
+		assert.Equal(t, code, deployedCode(t))
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		reload := w.Reload(context.Background(), file)
+		assert.Nil(t, reload)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := filepath.Join(dir, "Invalid.cdc")
+		writeContract(t, invalid, `access(all) fun main() {}`)
+
+		reload := w.Reload(context.Background(), invalid)
+		require.NotNil(t, reload)
+		assert.NotEmpty(t, reload.Error)
+	})
+
+	reloads := w.Reloads()
+	require.Len(t, reloads, 3)
+	assert.Equal(t, "Counter", reloads[0].Contract)
+	assert.Equal(t, "Counter", reloads[1].Contract)
+	assert.NotEmpty(t, reloads[2].Error)
+}
+
+func TestWatch(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+	b.EnableAutoMine()
+
+	dir := t.TempDir()
+	logger := zerolog.Nop()
+
+	w, err := watcher.New(&logger, b, dir, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	go func() {
+		_ = w.Start()
+	}()
+	defer w.Stop()
+
+	code := `access(all) contract Watched { access(all) fun get(): Int { return 1 } }`
+	writeContract(t, filepath.Join(dir, "Watched.cdc"), code)
+
+	require.Eventually(t, func() bool {
+		account, err := b.GetAccount(context.Background(), flowgo.Address(b.ServiceKey().Address))
+		require.NoError(t, err)
+		return string(account.Contracts["Watched"]) == code
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// other files are ignored
+	writeContract(t, filepath.Join(dir, "README.md"), "# contracts")
+
+	require.Eventually(t, func() bool {
+		return len(w.Reloads()) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, w.Reloads(), 1)
+}
+
+func TestConcurrentReloads(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+	b.EnableAutoMine()
+
+	dir := t.TempDir()
+	logger := zerolog.Nop()
+
+	w, err := watcher.New(&logger, b, dir, watcher.DefaultDebounce)
+	require.NoError(t, err)
+
+	names := []string{"First", "Second", "Third"}
+	reloads := make(chan *watcher.Reload, len(names))
+	for _, name := range names {
+		file := filepath.Join(dir, name+".cdc")
+		writeContract(t, file, "access(all) contract "+name+" {}")
+
+		go func() {
+			reloads <- w.Reload(context.Background(), file)
+		}()
+	}
+
+	for range names {
+		reload := <-reloads
+		require.NotNil(t, reload)
+		assert.Empty(t, reload.Error)
+	}
+
+	account, err := b.GetAccount(context.Background(), flowgo.Address(b.ServiceKey().Address))
+	require.NoError(t, err)
+	for _, name := range names {
+		assert.Contains(t, account.Contracts, name)
+	}
+}
+
+func TestReloadWithoutAutoMine(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	logger := zerolog.Nop()
+
+	w, err := watcher.New(&logger, b, dir, watcher.DefaultDebounce)
+	require.NoError(t, err)
+
+	// a transaction of the user is pending
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(`transaction { execute { log(1) } }`)).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(b.ServiceKey().Address, b.ServiceKey().Index, b.ServiceKey().SequenceNumber).
+		SetPayer(b.ServiceKey().Address)
+	signer, err := b.ServiceKey().Signer()
+	require.NoError(t, err)
+	require.NoError(t, tx.SignEnvelope(b.ServiceKey().Address, b.ServiceKey().Index, signer))
+	require.NoError(t, b.SendTransaction(context.Background(), convert.SDKTransactionToFlow(*tx)))
+
+	latestBlock, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, "Pending.cdc")
+	code := `access(all) contract Pending {}`
+	writeContract(t, file, code)
+
+	reload := w.Reload(context.Background(), file)
+	require.NotNil(t, reload)
+	assert.Empty(t, reload.Error)
+	assert.True(t, reload.Pending)
+
+	// saving the file again does not submit the contract twice
+	assert.Nil(t, w.Reload(context.Background(), file))
+
+	// no block was committed by the reload
+	block, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, latestBlock.ID(), block.ID())
+
+	_, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.True(t, result.Succeeded())
+	}
+
+	account, err := b.GetAccount(context.Background(), flowgo.Address(b.ServiceKey().Address))
+	require.NoError(t, err)
+	assert.Equal(t, code, string(account.Contracts["Pending"]))
+}
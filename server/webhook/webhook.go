@@ -0,0 +1,471 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package webhook posts the events emitted by contracts to HTTP endpoints,
+// e.g. to notify a chat channel of mints during a demo.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/onflow/cadence"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// DeliveryTimeout is the time a webhook endpoint has to respond.
+const DeliveryTimeout = 5 * time.Second
+
+// QueueSize is the number of events queued for a webhook. Events are dropped while
+// the queue of a webhook is full, so a slow endpoint does not hold up the others.
+const QueueSize = 100
+
+// Spec describes a webhook.
+type Spec struct {
+	// URL is the endpoint the events are posted to.
+	URL string `json:"url"`
+	// Address is the address of the account of the contract emitting the events.
+	Address string `json:"address"`
+	// Contract restricts the events to the ones of the contract with this name.
+	Contract string `json:"contract,omitempty"`
+	// Event restricts the events to the ones with this name, e.g. "Minted".
+	Event string `json:"event,omitempty"`
+	// Template is a text/template rendering the body posted for an event.
+	// The event is posted as JSON when it is empty.
+	Template string `json:"template,omitempty"`
+}
+
+// Webhook is an endpoint events are posted to.
+type Webhook struct {
+	ID string `json:"id"`
+	Spec
+	Deliveries int `json:"deliveries"`
+	// Dropped is the number of events not posted because the queue of the webhook was full.
+	Dropped      int       `json:"dropped"`
+	LastDelivery *Delivery `json:"lastDelivery,omitempty"`
+
+	// sequence orders the webhooks in the order they were added
+	sequence uint64
+	address  flowgo.Address
+	template *template.Template
+	// queue holds the events to post, in the order they were emitted
+	queue chan *Event
+	// removed is closed when the webhook is removed, which ends its worker
+	removed chan struct{}
+}
+
+// Delivery is the outcome of posting an event to a webhook.
+type Delivery struct {
+	Time       time.Time `json:"time"`
+	EventType  string    `json:"eventType"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Event is the data a webhook template is rendered with, and the body posted
+// for webhooks without template.
+type Event struct {
+	Type          string `json:"type"`
+	Address       string `json:"address"`
+	Contract      string `json:"contract"`
+	Name          string `json:"name"`
+	TransactionID string `json:"transactionId"`
+	BlockHeight   uint64 `json:"blockHeight"`
+	// Fields are the values of the event fields by name. Strings, addresses,
+	// booleans and optionals are plain values, other values are formatted
+	// like in Cadence.
+	Fields map[string]any `json:"fields"`
+}
+
+// templateFuncs are the functions available to webhook templates.
+var templateFuncs = template.FuncMap{
+	// json encodes a value, e.g. to embed a field in a JSON string
+	"json": func(value any) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+}
+
+// Dispatcher posts the events of committed blocks to the matching webhooks,
+// in the order they were emitted. Every webhook has its own worker posting its events.
+type Dispatcher struct {
+	logger   *zerolog.Logger
+	emulator emulator.Emulator
+	client   *http.Client
+	done     chan bool
+	// ctx is canceled when the dispatcher is stopped, which ends the workers
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	mu       sync.Mutex
+	webhooks map[string]*Webhook
+	nextID   uint64
+}
+
+func New(logger *zerolog.Logger, emulator emulator.Emulator) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		logger:   logger,
+		emulator: emulator,
+		client:   &http.Client{Timeout: DeliveryTimeout},
+		done:     make(chan bool, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+		webhooks: map[string]*Webhook{},
+	}
+}
+
+// Add validates and registers a webhook.
+func (d *Dispatcher) Add(spec Spec) (*Webhook, error) {
+	webhook := &Webhook{Spec: spec}
+
+	endpoint, err := url.Parse(spec.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, types.NewInvalidArgumentError(fmt.Sprintf("invalid url %q", spec.URL))
+	}
+
+	address := flowgo.HexToAddress(spec.Address)
+	if spec.Address == "" || address == flowgo.EmptyAddress {
+		return nil, types.NewInvalidArgumentError(fmt.Sprintf("invalid address %q", spec.Address))
+	}
+	webhook.address = address
+
+	if spec.Template != "" {
+		webhook.template, err = template.New("webhook").Funcs(templateFuncs).Parse(spec.Template)
+		if err != nil {
+			return nil, types.NewInvalidArgumentError(fmt.Sprintf("invalid template: %s", err))
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextID++
+	webhook.sequence = d.nextID
+	webhook.ID = strconv.FormatUint(d.nextID, 10)
+	webhook.queue = make(chan *Event, QueueSize)
+	webhook.removed = make(chan struct{})
+	d.webhooks[webhook.ID] = webhook
+
+	d.workers.Add(1)
+	go d.run(webhook)
+
+	return webhook.copy(), nil
+}
+
+// Remove unregisters a webhook, and returns false if there is no webhook with the given ID.
+func (d *Dispatcher) Remove(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	webhook, ok := d.webhooks[id]
+	if !ok {
+		return false
+	}
+
+	close(webhook.removed)
+	delete(d.webhooks, id)
+	return true
+}
+
+// Webhooks returns the registered webhooks, oldest first.
+func (d *Dispatcher) Webhooks() []Webhook {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	webhooks := make([]Webhook, 0, len(d.webhooks))
+	for _, webhook := range d.sortedWebhooks() {
+		webhooks = append(webhooks, *webhook.copy())
+	}
+
+	return webhooks
+}
+
+// Start posts events until the dispatcher is stopped. The events of every block committed
+// meanwhile are posted, even if blocks are committed faster than the events are posted.
+func (d *Dispatcher) Start() error {
+	ctx := context.Background()
+
+	blocks, err := emulator.NewBlockFeed(ctx, d.emulator)
+	if err != nil {
+		return err
+	}
+	defer blocks.Close()
+
+	for {
+		select {
+		case event := <-blocks.Commits():
+			err := blocks.Deliver(ctx, event, func(block *flowgo.Block) error {
+				events, err := d.emulator.GetEventsByHeight(ctx, block.Header.Height, "")
+				if err != nil {
+					return err
+				}
+				d.blockCommitted(block, events)
+				return nil
+			})
+			if err != nil {
+				d.logger.Warn().Err(err).Msg("❗  Failed to read the committed blocks for webhooks")
+			}
+		case <-d.done:
+			return nil
+		}
+	}
+}
+
+// Stop ends the dispatcher and the workers of the webhooks,
+// interrupting the events being posted.
+func (d *Dispatcher) Stop() {
+	d.done <- true
+	d.cancel()
+	d.workers.Wait()
+}
+
+// blockCommitted queues the events of the block for the matching webhooks.
+func (d *Dispatcher) blockCommitted(block *flowgo.Block, events []flowgo.Event) {
+	for _, flowEvent := range events {
+		address, contract, name, ok := parseEventType(flowEvent.Type)
+		if !ok {
+			continue
+		}
+
+		d.mu.Lock()
+		var webhooks []*Webhook
+		for _, webhook := range d.sortedWebhooks() {
+			if webhook.matches(address, contract, name) {
+				webhooks = append(webhooks, webhook)
+			}
+		}
+		d.mu.Unlock()
+
+		if len(webhooks) == 0 {
+			continue
+		}
+
+		payload, err := newEvent(flowEvent, block.Header.Height)
+		if err != nil {
+			d.logger.Warn().
+				Err(err).
+				Msgf("❗  Failed to decode event %s for webhooks", flowEvent.Type)
+			continue
+		}
+
+		for _, webhook := range webhooks {
+			select {
+			case webhook.queue <- payload:
+			default:
+				d.mu.Lock()
+				webhook.Dropped++
+				d.mu.Unlock()
+
+				d.logger.Warn().
+					Str("webhook", webhook.ID).
+					Str("eventType", payload.Type).
+					Msgf("❗  Webhook %s is too slow, dropped event %s", webhook.ID, payload.Type)
+			}
+		}
+	}
+}
+
+// run posts the queued events of the webhook until it is removed or the dispatcher is stopped.
+func (d *Dispatcher) run(webhook *Webhook) {
+	defer d.workers.Done()
+
+	for {
+		select {
+		case event := <-webhook.queue:
+			delivery := d.deliver(webhook, event)
+
+			d.mu.Lock()
+			webhook.Deliveries++
+			webhook.LastDelivery = delivery
+			d.mu.Unlock()
+		case <-webhook.removed:
+			return
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(webhook *Webhook, event *Event) *Delivery {
+	delivery := &Delivery{
+		Time:      time.Now(),
+		EventType: event.Type,
+	}
+
+	statusCode, err := d.post(webhook, event)
+	delivery.StatusCode = statusCode
+	if err != nil {
+		delivery.Error = err.Error()
+		d.logger.Warn().
+			Str("webhook", webhook.ID).
+			Str("eventType", event.Type).
+			Err(err).
+			Msgf("❗  Webhook %s failed", webhook.ID)
+		return delivery
+	}
+
+	d.logger.Debug().
+		Str("webhook", webhook.ID).
+		Str("eventType", event.Type).
+		Msgf("🪝  Event %s posted to webhook %s", event.Type, webhook.ID)
+
+	return delivery
+}
+
+// post sends the event to the webhook, and returns the status code of the response.
+func (d *Dispatcher) post(webhook *Webhook, event *Event) (int, error) {
+	var body bytes.Buffer
+	if webhook.template != nil {
+		err := webhook.template.Execute(&body, event)
+		if err != nil {
+			return 0, fmt.Errorf("failed to render template: %w", err)
+		}
+	} else {
+		err := json.NewEncoder(&body).Encode(event)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	contentType := "text/plain; charset=utf-8"
+	if json.Valid(body.Bytes()) {
+		contentType = "application/json"
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, DeliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, &body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// sortedWebhooks returns the webhooks in the order they were added.
+func (d *Dispatcher) sortedWebhooks() []*Webhook {
+	webhooks := make([]*Webhook, 0, len(d.webhooks))
+	for _, webhook := range d.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].sequence < webhooks[j].sequence
+	})
+
+	return webhooks
+}
+
+func (w *Webhook) matches(address flowgo.Address, contract, name string) bool {
+	return w.address == address &&
+		(w.Contract == "" || w.Contract == contract) &&
+		(w.Event == "" || w.Event == name)
+}
+
+func (w *Webhook) copy() *Webhook {
+	webhook := *w
+	if w.LastDelivery != nil {
+		lastDelivery := *w.LastDelivery
+		webhook.LastDelivery = &lastDelivery
+	}
+	return &webhook
+}
+
+// parseEventType splits the type of a contract event, e.g. A.f8d6e0586b0a20c7.Token.Minted.
+// It returns false for events which are not emitted by a contract.
+func parseEventType(eventType flowgo.EventType) (flowgo.Address, string, string, bool) {
+	parts := strings.Split(string(eventType), ".")
+	if len(parts) != 4 || parts[0] != "A" {
+		return flowgo.EmptyAddress, "", "", false
+	}
+
+	return flowgo.HexToAddress(parts[1]), parts[2], parts[3], true
+}
+
+func newEvent(flowEvent flowgo.Event, height uint64) (*Event, error) {
+	event, err := convert.FlowEventToSDK(flowEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	address, contract, name, _ := parseEventType(flowEvent.Type)
+
+	fields := make(map[string]any, len(event.Value.Fields))
+	for i, field := range event.Value.EventType.Fields {
+		fields[field.Identifier] = fieldValue(event.Value.Fields[i])
+	}
+
+	return &Event{
+		Type:          string(flowEvent.Type),
+		Address:       address.HexWithPrefix(),
+		Contract:      contract,
+		Name:          name,
+		TransactionID: flowEvent.TransactionID.String(),
+		BlockHeight:   height,
+		Fields:        fields,
+	}, nil
+}
+
+func fieldValue(value cadence.Value) any {
+	switch value := value.(type) {
+	case cadence.String:
+		return string(value)
+	case cadence.Address:
+		return flowgo.Address(value).HexWithPrefix()
+	case cadence.Bool:
+		return bool(value)
+	case cadence.Optional:
+		if value.Value == nil {
+			return nil
+		}
+		return fieldValue(value.Value)
+	case nil:
+		return nil
+	default:
+		return value.String()
+	}
+}
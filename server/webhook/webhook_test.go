@@ -0,0 +1,398 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/templates"
+	"github.com/onflow/flow-go/fvm"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server/webhook"
+)
+
+const tokenContract = `
+access(all) contract Token {
+	access(all) event Minted(amount: UFix64, to: Address, memo: String?)
+
+	init() {
+		emit Minted(amount: 10.0, to: self.account.address, memo: "demo")
+	}
+}
+`
+
+const burstContract = `
+access(all) contract Burst {
+	access(all) event Ping(index: Int)
+
+	access(all) fun ping() {
+		var i = 0
+		while i < 80 {
+			emit Ping(index: i)
+			i = i + 1
+		}
+	}
+
+	init() {
+		self.ping()
+	}
+}
+`
+
+type request struct {
+	contentType string
+	body        string
+}
+
+// startEndpoint starts an HTTP server recording the requests it receives.
+func startEndpoint(t *testing.T) (*httptest.Server, <-chan request) {
+	requests := make(chan request, 16)
+
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{contentType: r.Header.Get("Content-Type"), body: string(body)}
+	}))
+	t.Cleanup(endpoint.Close)
+
+	return endpoint, requests
+}
+
+func TestAdd(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	dispatcher := webhook.New(&logger, b)
+
+	address := b.ServiceKey().Address.Hex()
+
+	for name, spec := range map[string]webhook.Spec{
+		"no url":           {Address: address},
+		"invalid url":      {URL: "ftp://example.com", Address: address},
+		"no address":       {URL: "http://example.com"},
+		"invalid address":  {URL: "http://example.com", Address: "zz"},
+		"invalid template": {URL: "http://example.com", Address: address, Template: "{{.Fields"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := dispatcher.Add(spec)
+			assert.Error(t, err)
+		})
+	}
+
+	hook, err := dispatcher.Add(webhook.Spec{URL: "http://example.com", Address: address})
+	require.NoError(t, err)
+
+	webhooks := dispatcher.Webhooks()
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, hook.ID, webhooks[0].ID)
+
+	assert.True(t, dispatcher.Remove(hook.ID))
+	assert.False(t, dispatcher.Remove(hook.ID))
+	assert.Empty(t, dispatcher.Webhooks())
+}
+
+func TestDeliveries(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	dispatcher := webhook.New(&logger, b)
+	go func() {
+		_ = dispatcher.Start()
+	}()
+	t.Cleanup(dispatcher.Stop)
+
+	// wait until the dispatcher subscribed, events committed before are not posted
+	require.Eventually(t, func() bool {
+		status, err := b.Status(context.Background())
+		return err == nil && status.BlockSubscriptions == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	adapter := adapters.NewSDKAdapter(&logger, b)
+	contracts := []templates.Contract{{Name: "Token", Source: tokenContract}}
+
+	// the address of the account created next is known in advance
+	address, err := b.GetNetworkParameters().ChainID.Chain().AddressAtIndex(5)
+	require.NoError(t, err)
+
+	endpoint, requests := startEndpoint(t)
+
+	templated, err := dispatcher.Add(webhook.Spec{
+		URL:      endpoint.URL,
+		Address:  address.Hex(),
+		Event:    "Minted",
+		Template: `{"text": {{json (printf "Minted %s to %s" .Fields.amount .Fields.to)}}}`,
+	})
+	require.NoError(t, err)
+
+	_, err = dispatcher.Add(webhook.Spec{
+		URL:      endpoint.URL,
+		Address:  address.Hex(),
+		Contract: "Other",
+	})
+	require.NoError(t, err)
+
+	plain, err := dispatcher.Add(webhook.Spec{
+		URL:     endpoint.URL,
+		Address: address.Hex(),
+	})
+	require.NoError(t, err)
+
+	created, err := adapter.CreateAccount(context.Background(), nil, contracts, 0)
+	require.NoError(t, err)
+	require.Equal(t, address.Hex(), created.Hex())
+
+	receive := func(t *testing.T) request {
+		select {
+		case req := <-requests:
+			return req
+		case <-time.After(30 * time.Second):
+			require.FailNow(t, "no request received")
+			return request{}
+		}
+	}
+
+	// the webhooks are posted to by their own workers, in any order
+	req, other := receive(t), receive(t)
+	if strings.Contains(other.body, `"text"`) {
+		req, other = other, req
+	}
+
+	assert.Equal(t, "application/json", req.contentType)
+	assert.JSONEq(t, `{"text": "Minted 10.00000000 to `+address.HexWithPrefix()+`"}`, req.body)
+
+	req = other
+	var event webhook.Event
+	require.NoError(t, json.Unmarshal([]byte(req.body), &event))
+	assert.Equal(t, "A."+address.Hex()+".Token.Minted", event.Type)
+	assert.Equal(t, address.HexWithPrefix(), event.Address)
+	assert.Equal(t, "Token", event.Contract)
+	assert.Equal(t, "Minted", event.Name)
+	assert.NotEmpty(t, event.TransactionID)
+	assert.Equal(t, map[string]any{
+		"amount": "10.00000000",
+		"to":     address.HexWithPrefix(),
+		"memo":   "demo",
+	}, event.Fields)
+
+	// the webhook of the other contract is not called
+	select {
+	case req := <-requests:
+		assert.FailNow(t, "unexpected request", req.body)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// the deliveries are recorded once the endpoint responded
+	require.Eventually(t, func() bool {
+		return dispatcher.Webhooks()[2].Deliveries == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	webhooks := dispatcher.Webhooks()
+	require.Len(t, webhooks, 3)
+	assert.Equal(t, templated.ID, webhooks[0].ID)
+	assert.Equal(t, 1, webhooks[0].Deliveries)
+	require.NotNil(t, webhooks[0].LastDelivery)
+	assert.Equal(t, http.StatusOK, webhooks[0].LastDelivery.StatusCode)
+	assert.Empty(t, webhooks[0].LastDelivery.Error)
+	assert.Equal(t, 0, webhooks[1].Deliveries)
+	assert.Equal(t, plain.ID, webhooks[2].ID)
+}
+
+func TestDeliveriesUnderLoad(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	dispatcher := webhook.New(&logger, b)
+	go func() {
+		_ = dispatcher.Start()
+	}()
+	t.Cleanup(dispatcher.Stop)
+
+	require.Eventually(t, func() bool {
+		status, err := b.Status(context.Background())
+		return err == nil && status.BlockSubscriptions == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	latest, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	// the endpoint responds slower than blocks are committed
+	var received atomic.Int64
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		received.Add(1)
+	}))
+	t.Cleanup(endpoint.Close)
+
+	flowToken := fvm.FlowTokenAddress(b.GetChain())
+	_, err = dispatcher.Add(webhook.Spec{
+		URL:     endpoint.URL,
+		Address: flowToken.Hex(),
+		Event:   "TokensDeposited",
+	})
+	require.NoError(t, err)
+
+	// the storage of every account created is paid for with a deposit
+	adapter := adapters.NewSDKAdapter(&logger, b)
+	for i := 0; i < 20; i++ {
+		_, err := adapter.CreateAccount(context.Background(), nil, nil, 0)
+		require.NoError(t, err)
+	}
+
+	committed, err := b.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+
+	eventType := "A." + flowToken.Hex() + ".FlowToken.TokensDeposited"
+	blockEvents, err := b.GetEventsForHeightRange(
+		context.Background(),
+		eventType,
+		latest.Header.Height+1,
+		committed.Header.Height,
+	)
+	require.NoError(t, err)
+
+	var expected int64
+	for _, events := range blockEvents {
+		expected += int64(len(events.Events))
+	}
+	require.Greater(t, expected, int64(0))
+
+	// none of the events is skipped
+	require.Eventually(t, func() bool {
+		return received.Load() == expected
+	}, 30*time.Second, 10*time.Millisecond)
+}
+
+func TestSlowWebhook(t *testing.T) {
+
+	t.Parallel()
+
+	b, err := emulator.New()
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	dispatcher := webhook.New(&logger, b)
+	go func() {
+		_ = dispatcher.Start()
+	}()
+	t.Cleanup(dispatcher.Stop)
+
+	require.Eventually(t, func() bool {
+		status, err := b.Status(context.Background())
+		return err == nil && status.BlockSubscriptions == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	// the slow endpoint does not respond until the test ends
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+
+	var received atomic.Int64
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+	}))
+	t.Cleanup(fast.Close)
+
+	address, err := b.GetNetworkParameters().ChainID.Chain().AddressAtIndex(5)
+	require.NoError(t, err)
+
+	_, err = dispatcher.Add(webhook.Spec{URL: slow.URL, Address: address.Hex()})
+	require.NoError(t, err)
+	_, err = dispatcher.Add(webhook.Spec{URL: fast.URL, Address: address.Hex()})
+	require.NoError(t, err)
+
+	// the contract emits a burst of events when it is deployed, and when it is pinged
+	adapter := adapters.NewSDKAdapter(&logger, b)
+	contracts := []templates.Contract{{Name: "Burst", Source: burstContract}}
+	created, err := adapter.CreateAccount(context.Background(), nil, contracts, 0)
+	require.NoError(t, err)
+	require.Equal(t, address.Hex(), created.Hex())
+
+	require.Eventually(t, func() bool {
+		return received.Load() == 80
+	}, 30*time.Second, 10*time.Millisecond)
+
+	serviceKey := b.ServiceKey()
+	tx := flowsdk.NewTransaction().
+		SetScript([]byte(fmt.Sprintf(`
+			import Burst from 0x%s
+
+			transaction {
+				execute {
+					Burst.ping()
+				}
+			}
+		`, address.Hex()))).
+		SetGasLimit(flowgo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(serviceKey.Address, serviceKey.Index, serviceKey.SequenceNumber).
+		SetPayer(serviceKey.Address)
+
+	signer, err := serviceKey.Signer()
+	require.NoError(t, err)
+	require.NoError(t, tx.SignEnvelope(serviceKey.Address, serviceKey.Index, signer))
+
+	require.NoError(t, b.AddTransaction(context.Background(), *convert.SDKTransactionToFlow(*tx)))
+	_, results, err := b.ExecuteAndCommitBlock()
+	require.NoError(t, err)
+	require.NoError(t, results[0].Error)
+
+	// the slow endpoint only holds up its own queue
+	require.Eventually(t, func() bool {
+		return received.Load() == 160
+	}, 30*time.Second, 10*time.Millisecond)
+
+	webhooks := dispatcher.Webhooks()
+	require.Len(t, webhooks, 2)
+	assert.Equal(t, 0, webhooks[0].Deliveries)
+	assert.Greater(t, webhooks[0].Dropped, 0)
+	assert.Equal(t, 0, webhooks[1].Dropped)
+}
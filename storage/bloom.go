@@ -0,0 +1,127 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+
+	lru "github.com/hashicorp/golang-lru"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// eventTypeFilterCacheSize is the number of blocks for which event type filters are kept in memory.
+const eventTypeFilterCacheSize = 100_000
+
+// eventTypeFilterSize is the number of bits of an eventTypeFilter.
+const eventTypeFilterSize = 256
+
+// eventTypeFilterHashes is the number of bits set in an eventTypeFilter for each event type.
+const eventTypeFilterHashes = 4
+
+// eventTypeFilter is a bloom filter of the event types of a block. It may report that
+// a block contains an event type it does not, but never the opposite.
+type eventTypeFilter [eventTypeFilterSize / 64]uint64
+
+func newEventTypeFilter(types []flowgo.EventType) *eventTypeFilter {
+	filter := &eventTypeFilter{}
+	for _, eventType := range types {
+		filter.add(eventType)
+	}
+	return filter
+}
+
+func (f *eventTypeFilter) add(eventType flowgo.EventType) {
+	for _, bit := range eventTypeFilterBits(eventType) {
+		f[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// mayContain returns false if the block has no events of the given type.
+func (f *eventTypeFilter) mayContain(eventType flowgo.EventType) bool {
+	for _, bit := range eventTypeFilterBits(eventType) {
+		if f[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeEventTypeFilter(filter *eventTypeFilter) []byte {
+	encoded := make([]byte, 0, eventTypeFilterSize/8)
+	for _, word := range filter {
+		encoded = binary.BigEndian.AppendUint64(encoded, word)
+	}
+	return encoded
+}
+
+func decodeEventTypeFilter(from []byte) (*eventTypeFilter, error) {
+	if len(from) != eventTypeFilterSize/8 {
+		return nil, fmt.Errorf("invalid event type filter length: %d", len(from))
+	}
+	filter := &eventTypeFilter{}
+	for i := range filter {
+		filter[i] = binary.BigEndian.Uint64(from[i*8:])
+	}
+	return filter, nil
+}
+
+// eventTypeFilterBits derives the bits of an event type using double hashing.
+func eventTypeFilterBits(eventType flowgo.EventType) [eventTypeFilterHashes]uint {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(eventType))
+	sum := h.Sum64()
+
+	h1, h2 := uint32(sum), uint32(sum>>32)|1
+
+	var bits [eventTypeFilterHashes]uint
+	for i := range bits {
+		bits[i] = uint(h1+uint32(i)*h2) % eventTypeFilterSize
+	}
+	return bits
+}
+
+// eventTypeFilters caches the event type filters of recently accessed blocks, by height.
+type eventTypeFilters struct {
+	cache *lru.Cache
+}
+
+func newEventTypeFilters() *eventTypeFilters {
+	cache, err := lru.New(eventTypeFilterCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &eventTypeFilters{cache: cache}
+}
+
+func (f *eventTypeFilters) get(height uint64) (*eventTypeFilter, bool) {
+	filter, ok := f.cache.Get(height)
+	if !ok {
+		return nil, false
+	}
+	return filter.(*eventTypeFilter), true
+}
+
+func (f *eventTypeFilters) set(height uint64, filter *eventTypeFilter) {
+	f.cache.Add(height, filter)
+}
+
+func (f *eventTypeFilters) purge() {
+	f.cache.Purge()
+}
@@ -0,0 +1,47 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventTypeFilter(t *testing.T) {
+
+	t.Parallel()
+
+	types := make([]flowgo.EventType, 10)
+	for i := range types {
+		types[i] = flowgo.EventType(fmt.Sprintf("A.0000000000000001.Test.Event%d", i))
+	}
+
+	filter := newEventTypeFilter(types)
+	for _, eventType := range types {
+		assert.True(t, filter.mayContain(eventType))
+	}
+
+	empty := newEventTypeFilter(nil)
+	for _, eventType := range types {
+		assert.False(t, empty.mayContain(eventType))
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+// The optional capabilities of the wrapped store are forwarded without
+// injecting anything. Capabilities the wrapped store lacks are reported as
+// unsupported, like the store itself would.
+
+var _ storage.SnapshotProvider = &Store{}
+var _ storage.RollbackProvider = &Store{}
+var _ storage.LedgerDeltaProvider = &Store{}
+var _ storage.LedgerPruner = &Store{}
+var _ storage.Compactor = &Store{}
+var _ storage.Checkpointer = &Store{}
+var _ storage.CoverageReportStore = &Store{}
+var _ storage.HandleProvider = &Store{}
+
+var errSnapshotsNotSupported = errors.New("storage doesn't support snapshots")
+
+func (s *Store) snapshotProvider() (storage.SnapshotProvider, error) {
+	provider, ok := s.Store.(storage.SnapshotProvider)
+	if !ok {
+		return nil, errSnapshotsNotSupported
+	}
+	return provider, nil
+}
+
+func (s *Store) Snapshots() ([]string, error) {
+	provider, err := s.snapshotProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.Snapshots()
+}
+
+func (s *Store) SnapshotInfos() ([]storage.SnapshotInfo, error) {
+	provider, err := s.snapshotProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.SnapshotInfos()
+}
+
+func (s *Store) CreateSnapshot(snapshotName string, description string) error {
+	provider, err := s.snapshotProvider()
+	if err != nil {
+		return err
+	}
+	return provider.CreateSnapshot(snapshotName, description)
+}
+
+func (s *Store) LoadSnapshot(snapshotName string) error {
+	provider, err := s.snapshotProvider()
+	if err != nil {
+		return err
+	}
+	return provider.LoadSnapshot(snapshotName)
+}
+
+func (s *Store) DeleteSnapshot(snapshotName string) error {
+	provider, err := s.snapshotProvider()
+	if err != nil {
+		return err
+	}
+	return provider.DeleteSnapshot(snapshotName)
+}
+
+func (s *Store) SupportSnapshotsWithCurrentConfig() bool {
+	provider, err := s.snapshotProvider()
+	return err == nil && provider.SupportSnapshotsWithCurrentConfig()
+}
+
+func (s *Store) RollbackToBlockHeight(height uint64) error {
+	provider, ok := s.Store.(storage.RollbackProvider)
+	if !ok {
+		return fmt.Errorf("storage doesn't support rollback")
+	}
+	return provider.RollbackToBlockHeight(height)
+}
+
+func (s *Store) LedgerDeltaByHeight(ctx context.Context, blockHeight uint64) (*snapshot.ExecutionSnapshot, error) {
+	provider, ok := s.Store.(storage.LedgerDeltaProvider)
+	if !ok {
+		return nil, fmt.Errorf("storage doesn't record the registers written by blocks")
+	}
+	return provider.LedgerDeltaByHeight(ctx, blockHeight)
+}
+
+func (s *Store) PruneLedger(ctx context.Context, height uint64, pinned []uint64) error {
+	pruner, ok := s.Store.(storage.LedgerPruner)
+	if !ok {
+		return fmt.Errorf("storage doesn't support pruning the ledger")
+	}
+	return pruner.PruneLedger(ctx, height, pinned)
+}
+
+func (s *Store) Compact(ctx context.Context) (int64, error) {
+	compactor, ok := s.Store.(storage.Compactor)
+	if !ok {
+		return 0, storage.ErrCompactionNotSupported
+	}
+	return compactor.Compact(ctx)
+}
+
+func (s *Store) PrepareCheckpoint(ctx context.Context) (func(ctx context.Context) error, error) {
+	checkpointer, ok := s.Store.(storage.Checkpointer)
+	if !ok {
+		return nil, storage.ErrCheckpointNotSupported
+	}
+	return checkpointer.PrepareCheckpoint(ctx)
+}
+
+func (s *Store) CoverageReport(ctx context.Context) ([]byte, error) {
+	coverageStore, ok := s.Store.(storage.CoverageReportStore)
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return coverageStore.CoverageReport(ctx)
+}
+
+func (s *Store) SetCoverageReport(ctx context.Context, report []byte) error {
+	coverageStore, ok := s.Store.(storage.CoverageReportStore)
+	if !ok {
+		return nil
+	}
+	return coverageStore.SetCoverageReport(ctx, report)
+}
+
+func (s *Store) OpenHandles() int {
+	handles, ok := s.Store.(storage.HandleProvider)
+	if !ok {
+		return 0
+	}
+	return handles.OpenHandles()
+}
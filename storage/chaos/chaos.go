@@ -0,0 +1,264 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package chaos wraps a store to inject random latency and transient errors
+// into storage operations, to test how the emulator and its clients cope
+// with a slow or unreliable storage backend.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// ErrInjected is returned by storage operations failed on purpose.
+var ErrInjected = errors.New("chaos: injected transient storage error")
+
+// Config configures the latency and errors injected into storage operations.
+type Config struct {
+	// MaxLatency is the longest delay added to an operation. Delays are
+	// uniformly distributed between zero and MaxLatency.
+	MaxLatency time.Duration
+	// ErrorRate is the probability, between 0 and 1, that an operation fails
+	// with ErrInjected instead of reaching the wrapped store.
+	ErrorRate float64
+}
+
+// Enabled reports whether the configuration injects anything.
+func (c Config) Enabled() bool {
+	return c.MaxLatency > 0 || c.ErrorRate > 0
+}
+
+// ParseConfig parses a configuration of comma separated settings, for example
+// "latency=50ms,error-rate=0.05". An empty string injects nothing.
+func ParseConfig(value string) (Config, error) {
+	var conf Config
+	if value == "" {
+		return conf, nil
+	}
+
+	for _, setting := range strings.Split(value, ",") {
+		name, rawValue, ok := strings.Cut(strings.TrimSpace(setting), "=")
+		if !ok {
+			return Config{}, fmt.Errorf("invalid storage chaos setting %q, expected name=value", setting)
+		}
+
+		switch name {
+		case "latency":
+			latency, err := time.ParseDuration(rawValue)
+			if err != nil || latency < 0 {
+				return Config{}, fmt.Errorf("invalid storage chaos latency %q", rawValue)
+			}
+			conf.MaxLatency = latency
+		case "error-rate":
+			rate, err := strconv.ParseFloat(rawValue, 64)
+			if err != nil || rate < 0 || rate > 1 {
+				return Config{}, fmt.Errorf("invalid storage chaos error rate %q, expected a number between 0 and 1", rawValue)
+			}
+			conf.ErrorRate = rate
+		default:
+			return Config{}, fmt.Errorf("unknown storage chaos setting %q, expected \"latency\" or \"error-rate\"", name)
+		}
+	}
+
+	return conf, nil
+}
+
+// Store wraps a store and injects latency and errors into its block,
+// transaction, event, ledger and metadata operations.
+//
+// Nothing is injected until the store is started, so that the emulator can
+// bootstrap or load its state reliably. The optional storage capabilities,
+// such as snapshots, are forwarded to the wrapped store unchanged.
+type Store struct {
+	storage.Store
+	conf    Config
+	started atomic.Bool
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+var _ storage.Store = &Store{}
+
+// New returns a store injecting latency and errors into the operations of the given store.
+func New(store storage.Store, conf Config) *Store {
+	return &Store{
+		Store: store,
+		conf:  conf,
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Start starts injecting latency and errors, and starts the wrapped store.
+func (s *Store) Start() error {
+	s.started.Store(true)
+	return s.Store.Start()
+}
+
+// Stop stops injecting latency and errors, and stops the wrapped store.
+func (s *Store) Stop() {
+	s.started.Store(false)
+	s.Store.Stop()
+}
+
+// Unwrap returns the wrapped store.
+func (s *Store) Unwrap() storage.Store {
+	return s.Store
+}
+
+// inject delays the calling operation, and returns ErrInjected if it must fail.
+func (s *Store) inject(ctx context.Context) error {
+	if !s.started.Load() {
+		return nil
+	}
+
+	s.mu.Lock()
+	latency := time.Duration(0)
+	if s.conf.MaxLatency > 0 {
+		latency = time.Duration(s.rand.Int63n(int64(s.conf.MaxLatency) + 1))
+	}
+	fail := s.conf.ErrorRate > 0 && s.rand.Float64() < s.conf.ErrorRate
+	s.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	if fail {
+		return ErrInjected
+	}
+	return nil
+}
+
+func (s *Store) LatestBlockHeight(ctx context.Context) (uint64, error) {
+	if err := s.inject(ctx); err != nil {
+		return 0, err
+	}
+	return s.Store.LatestBlockHeight(ctx)
+}
+
+func (s *Store) LatestBlock(ctx context.Context) (flowgo.Block, error) {
+	if err := s.inject(ctx); err != nil {
+		return flowgo.Block{}, err
+	}
+	return s.Store.LatestBlock(ctx)
+}
+
+func (s *Store) StoreBlock(ctx context.Context, block *flowgo.Block) error {
+	if err := s.inject(ctx); err != nil {
+		return err
+	}
+	return s.Store.StoreBlock(ctx, block)
+}
+
+func (s *Store) BlockByID(ctx context.Context, blockID flowgo.Identifier) (*flowgo.Block, error) {
+	if err := s.inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Store.BlockByID(ctx, blockID)
+}
+
+func (s *Store) BlockByHeight(ctx context.Context, height uint64) (*flowgo.Block, error) {
+	if err := s.inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Store.BlockByHeight(ctx, height)
+}
+
+func (s *Store) GetBlocks(ctx context.Context, heights []uint64) ([]*flowgo.Block, error) {
+	if err := s.inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Store.GetBlocks(ctx, heights)
+}
+
+func (s *Store) CommitBlock(ctx context.Context, commit storage.BlockCommit) error {
+	if err := s.inject(ctx); err != nil {
+		return err
+	}
+	return s.Store.CommitBlock(ctx, commit)
+}
+
+func (s *Store) CollectionByID(ctx context.Context, collectionID flowgo.Identifier) (flowgo.LightCollection, error) {
+	if err := s.inject(ctx); err != nil {
+		return flowgo.LightCollection{}, err
+	}
+	return s.Store.CollectionByID(ctx, collectionID)
+}
+
+func (s *Store) TransactionByID(ctx context.Context, transactionID flowgo.Identifier) (flowgo.TransactionBody, error) {
+	if err := s.inject(ctx); err != nil {
+		return flowgo.TransactionBody{}, err
+	}
+	return s.Store.TransactionByID(ctx, transactionID)
+}
+
+func (s *Store) TransactionResultByID(ctx context.Context, transactionID flowgo.Identifier) (types.StorableTransactionResult, error) {
+	if err := s.inject(ctx); err != nil {
+		return types.StorableTransactionResult{}, err
+	}
+	return s.Store.TransactionResultByID(ctx, transactionID)
+}
+
+func (s *Store) LedgerByHeight(ctx context.Context, blockHeight uint64) (snapshot.StorageSnapshot, error) {
+	if err := s.inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Store.LedgerByHeight(ctx, blockHeight)
+}
+
+func (s *Store) EventsByHeight(ctx context.Context, blockHeight uint64, eventType string) ([]flowgo.Event, error) {
+	if err := s.inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Store.EventsByHeight(ctx, blockHeight, eventType)
+}
+
+func (s *Store) GetMeta(ctx context.Context, key string) ([]byte, error) {
+	if err := s.inject(ctx); err != nil {
+		return nil, err
+	}
+	return s.Store.GetMeta(ctx, key)
+}
+
+func (s *Store) PutMeta(ctx context.Context, key string, value []byte) error {
+	if err := s.inject(ctx); err != nil {
+		return err
+	}
+	return s.Store.PutMeta(ctx, key, value)
+}
@@ -0,0 +1,121 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaos_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/chaos"
+	"github.com/onflow/flow-emulator/storage/memstore"
+)
+
+func TestParseConfig(t *testing.T) {
+
+	t.Parallel()
+
+	conf, err := chaos.ParseConfig("")
+	require.NoError(t, err)
+	assert.False(t, conf.Enabled())
+
+	conf, err = chaos.ParseConfig("latency=50ms, error-rate=0.25")
+	require.NoError(t, err)
+	assert.Equal(t, chaos.Config{MaxLatency: 50 * time.Millisecond, ErrorRate: 0.25}, conf)
+	assert.True(t, conf.Enabled())
+
+	for _, invalid := range []string{
+		"latency",
+		"latency=fast",
+		"error-rate=2",
+		"error-rate=-0.1",
+		"jitter=1ms",
+	} {
+		_, err := chaos.ParseConfig(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestStore(t *testing.T) {
+
+	t.Parallel()
+
+	block := &flowgo.Block{
+		Header: &flowgo.Header{
+			Height: 1,
+		},
+	}
+
+	t.Run("injects nothing before the store is started", func(t *testing.T) {
+		t.Parallel()
+
+		store := chaos.New(memstore.New(), chaos.Config{ErrorRate: 1})
+
+		require.NoError(t, store.StoreBlock(context.Background(), block))
+
+		_, err := store.BlockByHeight(context.Background(), 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("injects errors once started", func(t *testing.T) {
+		t.Parallel()
+
+		store := chaos.New(memstore.New(), chaos.Config{ErrorRate: 1})
+		require.NoError(t, store.Start())
+
+		err := store.StoreBlock(context.Background(), block)
+		assert.ErrorIs(t, err, chaos.ErrInjected)
+
+		store.Stop()
+
+		_, err = store.BlockByHeight(context.Background(), 1)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("injects latency once started", func(t *testing.T) {
+		t.Parallel()
+
+		store := chaos.New(memstore.New(), chaos.Config{MaxLatency: time.Hour})
+		require.NoError(t, store.Start())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// the latency is random, but is cut short by the context
+		_, err := store.LatestBlock(ctx)
+		if err != nil {
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("forwards capabilities of the wrapped store", func(t *testing.T) {
+		t.Parallel()
+
+		store := chaos.New(memstore.New(), chaos.Config{ErrorRate: 1})
+
+		assert.True(t, store.SupportSnapshotsWithCurrentConfig())
+		_, err := store.PrepareCheckpoint(context.Background())
+		assert.ErrorIs(t, err, storage.ErrCheckpointNotSupported)
+	})
+}
@@ -0,0 +1,275 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package checkpoint implements a store which is bootstrapped from a
+// checkpoint of the emulator state, and can save its state back to it.
+//
+// A checkpoint is an sqlite database file. It is located by a local path,
+// a file:// URL, an http(s):// URL, or an object in a bucket, given as
+// s3://bucket/key or gs://bucket/key. Objects in buckets are read and written
+// over plain HTTPS, so private buckets must be accessed through pre-signed
+// http(s):// URLs.
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+// databaseFile is the name under which the sqlite store keeps its database in its directory.
+const databaseFile = "emulator.sqlite"
+
+var _ storage.Store = &Store{}
+var _ storage.Checkpointer = &Store{}
+
+// Store is an sqlite store working on a local copy of a checkpoint.
+type Store struct {
+	*sqlite.Store
+	location string
+	dir      string
+	client   *http.Client
+}
+
+// Option is a function that configures a Store instance.
+type Option func(*Store)
+
+// WithHTTPClient sets the client used to read and write remote checkpoints.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Store) {
+		s.client = client
+	}
+}
+
+// New returns a store holding the state of the checkpoint at the given
+// location, or an empty store if there is no checkpoint there yet.
+func New(location string, options ...Option) (*Store, error) {
+	if location == "" {
+		return nil, fmt.Errorf("checkpoint location is required")
+	}
+
+	store := &Store{
+		location: location,
+		client:   http.DefaultClient,
+	}
+
+	for _, opt := range options {
+		opt(store)
+	}
+
+	dir, err := os.MkdirTemp("", "emulator-checkpoint-")
+	if err != nil {
+		return nil, err
+	}
+	store.dir = dir
+
+	err = store.download(context.Background(), filepath.Join(dir, databaseFile))
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to load checkpoint %s: %w", location, err)
+	}
+
+	store.Store, err = sqlite.New(dir)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Checkpoint saves the current state to the checkpoint location.
+func (s *Store) Checkpoint(ctx context.Context) error {
+	save, err := s.PrepareCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	return save(ctx)
+}
+
+// PrepareCheckpoint backs the database up to a temporary file, which the
+// returned function uploads to the checkpoint location and removes.
+func (s *Store) PrepareCheckpoint(_ context.Context) (func(ctx context.Context) error, error) {
+	file, err := os.CreateTemp(s.dir, "checkpoint-")
+	if err != nil {
+		return nil, err
+	}
+	path := file.Name()
+	_ = file.Close()
+
+	// the backup must create the file itself
+	err = os.Remove(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Store.Backup(path)
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		defer os.Remove(path)
+
+		err := s.upload(ctx, path)
+		if err != nil {
+			return fmt.Errorf("failed to save checkpoint %s: %w", s.location, err)
+		}
+		return nil
+	}, nil
+}
+
+// Close closes the database and removes the local copy of the checkpoint.
+func (s *Store) Close() error {
+	err := s.Store.Close()
+	_ = os.RemoveAll(s.dir)
+	return err
+}
+
+// Stop closes the store when the emulator stops. The state is not saved to
+// the checkpoint, which only changes when Checkpoint is called.
+func (s *Store) Stop() {
+	_ = s.Close()
+}
+
+// objectURL returns the URL of a remote checkpoint, and false for local checkpoints.
+func objectURL(location string) (string, bool) {
+	switch {
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		return location, true
+	case strings.HasPrefix(location, "s3://"):
+		bucket, key, _ := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+		return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", bucket, key), true
+	case strings.HasPrefix(location, "gs://"):
+		return "https://storage.googleapis.com/" + strings.TrimPrefix(location, "gs://"), true
+	default:
+		return "", false
+	}
+}
+
+// download copies the checkpoint to the given path, if it exists.
+func (s *Store) download(ctx context.Context, path string) error {
+	var source io.ReadCloser
+
+	if url, remote := objectURL(s.location); remote {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		response, err := s.client.Do(request)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode == http.StatusNotFound {
+			response.Body.Close()
+			return nil
+		}
+		if response.StatusCode != http.StatusOK {
+			response.Body.Close()
+			return fmt.Errorf("unexpected response status %s", response.Status)
+		}
+		source = response.Body
+	} else {
+		file, err := os.Open(strings.TrimPrefix(s.location, "file://"))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		source = file
+	}
+	defer source.Close()
+
+	destination, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer destination.Close()
+
+	_, err = io.Copy(destination, source)
+	return err
+}
+
+// upload replaces the checkpoint with the file at the given path.
+func (s *Store) upload(ctx context.Context, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	url, remote := objectURL(s.location)
+	if !remote {
+		return replaceFile(strings.TrimPrefix(s.location, "file://"), file)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, url, file)
+	if err != nil {
+		return err
+	}
+	request.ContentLength = info.Size()
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", response.Status)
+	}
+
+	return nil
+}
+
+// replaceFile writes the content to a temporary file next to the given path,
+// then renames it, so an interrupted write does not corrupt the checkpoint.
+func replaceFile(path string, content io.Reader) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, content)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,130 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checkpoint_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/checkpoint"
+)
+
+func TestCheckpoint(t *testing.T) {
+
+	t.Parallel()
+
+	ctx := context.Background()
+
+	block := &flowgo.Block{
+		Header: &flowgo.Header{
+			Height: 1,
+		},
+	}
+
+	// checkpoints a block at the location and bootstraps a new store from it
+	roundTrip := func(t *testing.T, location string, options ...checkpoint.Option) {
+		store, err := checkpoint.New(location, options...)
+		require.NoError(t, err)
+
+		_, err = store.LatestBlock(ctx)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		require.NoError(t, store.StoreBlock(ctx, block))
+		require.NoError(t, store.Checkpoint(ctx))
+		require.NoError(t, store.Close())
+
+		restored, err := checkpoint.New(location, options...)
+		require.NoError(t, err)
+		defer restored.Close()
+
+		latest, err := restored.LatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, block.ID(), latest.ID())
+	}
+
+	t.Run("local file", func(t *testing.T) {
+		t.Parallel()
+
+		roundTrip(t, filepath.Join(t.TempDir(), "state.sqlite"))
+	})
+
+	t.Run("file URL", func(t *testing.T) {
+		t.Parallel()
+
+		roundTrip(t, "file://"+filepath.Join(t.TempDir(), "state.sqlite"))
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		var object []byte
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch r.Method {
+			case http.MethodGet:
+				if object == nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write(object)
+			case http.MethodPut:
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				object = body
+			}
+		}))
+		defer server.Close()
+
+		roundTrip(t, server.URL+"/bucket/state.sqlite", checkpoint.WithHTTPClient(server.Client()))
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.True(t, bytes.HasPrefix(object, []byte("SQLite format 3")))
+	})
+
+	t.Run("HTTP error", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		_, err := checkpoint.New(server.URL + "/state.sqlite")
+		require.Error(t, err)
+	})
+}
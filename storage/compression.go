@@ -0,0 +1,151 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the algorithm compressing the events and transaction results
+// stored by a backend, which hold the event payloads and transaction error messages.
+//
+// Values are decompressed transparently, whatever compression they were stored with,
+// so the compression of an existing store can be changed at any time.
+type Compression int
+
+const (
+	// CompressionNone stores values uncompressed.
+	CompressionNone Compression = iota
+	// CompressionZstd compresses values with zstd, for the smallest storage.
+	CompressionZstd
+	// CompressionSnappy compresses values with snappy, for the fastest commits.
+	CompressionSnappy
+)
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionZstd:
+		return "zstd"
+	case CompressionSnappy:
+		return "snappy"
+	default:
+		return "none"
+	}
+}
+
+// ParseCompression parses a compression algorithm name. An empty string selects
+// CompressionNone.
+func ParseCompression(name string) (Compression, error) {
+	switch name {
+	case "", "none":
+		return CompressionNone, nil
+	case "zstd":
+		return CompressionZstd, nil
+	case "snappy":
+		return CompressionSnappy, nil
+	default:
+		return CompressionNone, fmt.Errorf("invalid compression %q, expected \"none\", \"zstd\" or \"snappy\"", name)
+	}
+}
+
+// CompressionConfigurable is implemented by stores which can compress the values they store,
+// i.e. the stores built on DefaultStore, such as the SQLite and Redis stores. The memstore
+// keeps values decoded in memory, so it does not implement it.
+type CompressionConfigurable interface {
+	SetCompression(compression Compression)
+}
+
+// Compressed values are prefixed with a byte identifying the algorithm. The bytes are
+// reserved in CBOR, so they can not start the uncompressed, CBOR encoded values.
+const (
+	zstdPrefix   byte = 0x1c
+	snappyPrefix byte = 0x1d
+)
+
+var (
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+)
+
+func init() {
+	var err error
+	zstdEncoder, err = zstd.NewWriter(nil)
+	if err != nil {
+		panic(fmt.Sprintf("could not initialize zstd encoder: %s", err.Error()))
+	}
+	zstdDecoder, err = zstd.NewReader(nil)
+	if err != nil {
+		panic(fmt.Sprintf("could not initialize zstd decoder: %s", err.Error()))
+	}
+}
+
+// compress returns the compressed value, prefixed with the algorithm.
+func (c Compression) compress(value []byte) []byte {
+	switch c {
+	case CompressionZstd:
+		return zstdEncoder.EncodeAll(value, []byte{zstdPrefix})
+	case CompressionSnappy:
+		return append([]byte{snappyPrefix}, snappy.Encode(nil, value)...)
+	default:
+		return value
+	}
+}
+
+// compressionOf returns the compression a value was stored with.
+func compressionOf(value []byte) Compression {
+	if len(value) == 0 {
+		return CompressionNone
+	}
+
+	switch value[0] {
+	case zstdPrefix:
+		return CompressionZstd
+	case snappyPrefix:
+		return CompressionSnappy
+	default:
+		return CompressionNone
+	}
+}
+
+// decompress returns the value stored with any compression.
+func decompress(value []byte) ([]byte, error) {
+	if len(value) == 0 {
+		return value, nil
+	}
+
+	switch value[0] {
+	case zstdPrefix:
+		decompressed, err := zstdDecoder.DecodeAll(value[1:], nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress zstd value: %w", err)
+		}
+		return decompressed, nil
+	case snappyPrefix:
+		decompressed, err := snappy.Decode(nil, value[1:])
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress snappy value: %w", err)
+		}
+		return decompressed, nil
+	default:
+		return value, nil
+	}
+}
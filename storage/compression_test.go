@@ -0,0 +1,68 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestCompress(t *testing.T) {
+
+	t.Parallel()
+
+	value := bytes.Repeat([]byte("flow event payload "), 100)
+
+	for _, compression := range []Compression{
+		CompressionNone,
+		CompressionZstd,
+		CompressionSnappy,
+	} {
+		compressed := compression.compress(value)
+		if compression != CompressionNone {
+			assert.Less(t, len(compressed), len(value), compression.String())
+		}
+
+		decompressed, err := decompress(compressed)
+		require.NoError(t, err)
+		assert.Equal(t, value, decompressed, compression.String())
+	}
+
+	t.Run("uncompressed values are returned unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := encodeTransactionResult(types.StorableTransactionResult{ErrorMessage: "failed"})
+		require.NoError(t, err)
+
+		decoded, err := decompress(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, encoded, decoded)
+	})
+
+	t.Run("corrupt values are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := decompress([]byte{zstdPrefix, 0xff, 0xff})
+		assert.Error(t, err)
+	})
+}
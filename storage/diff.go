@@ -0,0 +1,144 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// RegisterDiff is a register whose value differs between two stores.
+// The value is nil in the store which does not hold the register.
+type RegisterDiff struct {
+	ID   flowgo.RegisterID
+	From flowgo.RegisterValue
+	To   flowgo.RegisterValue
+}
+
+// ContractName returns the name of the contract whose code is stored in the
+// register, or false if the register is not a contract code register.
+func (d RegisterDiff) ContractName() (string, bool) {
+	if d.ID.Owner == "" || !strings.HasPrefix(d.ID.Key, flowgo.CodeKeyPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(d.ID.Key, flowgo.CodeKeyPrefix), true
+}
+
+// StateDiff is the difference between the latest execution state of two stores.
+type StateDiff struct {
+	// Registers are the differing registers, sorted by owner then key.
+	Registers []RegisterDiff
+}
+
+// Empty returns true if both stores hold the same execution state.
+func (d *StateDiff) Empty() bool {
+	return len(d.Registers) == 0
+}
+
+// Accounts returns the addresses of the accounts owning differing registers, sorted.
+func (d *StateDiff) Accounts() []flowgo.Address {
+	var accounts []flowgo.Address
+	for _, register := range d.Registers {
+		if register.ID.Owner == "" {
+			continue
+		}
+
+		address := flowgo.BytesToAddress([]byte(register.ID.Owner))
+		if len(accounts) == 0 || accounts[len(accounts)-1] != address {
+			accounts = append(accounts, address)
+		}
+	}
+	return accounts
+}
+
+// DiffState compares the latest execution state of two stores.
+//
+// Both stores must implement LedgerDeltaProvider, as their state is rebuilt
+// from the registers written by each of their blocks.
+func DiffState(ctx context.Context, from Store, to Store) (*StateDiff, error) {
+	fromRegisters, err := LatestRegisters(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRegisters, err := LatestRegisters(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &StateDiff{}
+	for id, fromValue := range fromRegisters {
+		toValue := toRegisters[id]
+		if !bytes.Equal(fromValue, toValue) {
+			diff.Registers = append(diff.Registers, RegisterDiff{ID: id, From: fromValue, To: toValue})
+		}
+	}
+	for id, toValue := range toRegisters {
+		if _, ok := fromRegisters[id]; !ok {
+			diff.Registers = append(diff.Registers, RegisterDiff{ID: id, To: toValue})
+		}
+	}
+
+	sort.Slice(diff.Registers, func(i, j int) bool {
+		a, b := diff.Registers[i].ID, diff.Registers[j].ID
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return a.Key < b.Key
+	})
+
+	return diff, nil
+}
+
+// LatestRegisters returns the non-empty registers of the latest execution
+// state of a store, rebuilt from the registers written by each of its blocks.
+func LatestRegisters(ctx context.Context, store Store) (map[flowgo.RegisterID]flowgo.RegisterValue, error) {
+	deltaProvider, ok := store.(LedgerDeltaProvider)
+	if !ok {
+		return nil, fmt.Errorf("storage does not record the registers written by blocks")
+	}
+
+	latestHeight, err := store.LatestBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	registers := make(map[flowgo.RegisterID]flowgo.RegisterValue)
+	for height := uint64(0); height <= latestHeight; height++ {
+		delta, err := deltaProvider.LedgerDeltaByHeight(ctx, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the registers written at height %d: %w", height, err)
+		}
+
+		for id, value := range delta.WriteSet {
+			if len(value) == 0 {
+				delete(registers, id)
+				continue
+			}
+			registers[id] = value
+		}
+	}
+
+	return registers, nil
+}
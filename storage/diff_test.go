@@ -0,0 +1,111 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+func TestDiffState(t *testing.T) {
+
+	t.Parallel()
+
+	address := flowgo.HexToAddress("01")
+	owner := string(address.Bytes())
+
+	kept := flowgo.NewRegisterID(owner, "kept")
+	changed := flowgo.NewRegisterID(owner, "changed")
+	removed := flowgo.NewRegisterID(owner, "removed")
+	contract := flowgo.ContractRegisterID(address, "Hello")
+
+	commit := func(t *testing.T, store storage.Store, height uint64, writes map[flowgo.RegisterID]flowgo.RegisterValue) {
+		err := store.CommitBlock(context.Background(), storage.BlockCommit{
+			Block: flowgo.Block{
+				Header: &flowgo.Header{
+					Height: height,
+				},
+			},
+			ExecutionSnapshot: &snapshot.ExecutionSnapshot{WriteSet: writes},
+		})
+		require.NoError(t, err)
+	}
+
+	from, fromPath := setupStore(t)
+	to, toPath := setupStore(t)
+	defer func() {
+		require.NoError(t, from.Close())
+		require.NoError(t, to.Close())
+		require.NoError(t, os.RemoveAll(fromPath))
+		require.NoError(t, os.RemoveAll(toPath))
+	}()
+
+	genesis := map[flowgo.RegisterID]flowgo.RegisterValue{
+		kept:    []byte("kept"),
+		changed: []byte("before"),
+		removed: []byte("removed"),
+	}
+	commit(t, from, 0, genesis)
+	commit(t, to, 0, genesis)
+
+	t.Run("should report no differences between equal states", func(t *testing.T) {
+		diff, err := storage.DiffState(context.Background(), from, to)
+		require.NoError(t, err)
+		assert.True(t, diff.Empty())
+	})
+
+	t.Run("should report the differences of the latest states", func(t *testing.T) {
+		// a value written then restored is not a difference
+		commit(t, from, 1, map[flowgo.RegisterID]flowgo.RegisterValue{kept: []byte("other")})
+		commit(t, from, 2, map[flowgo.RegisterID]flowgo.RegisterValue{kept: []byte("kept")})
+
+		commit(t, to, 1, map[flowgo.RegisterID]flowgo.RegisterValue{
+			changed:  []byte("after"),
+			removed:  {},
+			contract: []byte("access(all) contract Hello {}"),
+		})
+
+		diff, err := storage.DiffState(context.Background(), from, to)
+		require.NoError(t, err)
+		require.False(t, diff.Empty())
+
+		assert.Equal(t, []storage.RegisterDiff{
+			{ID: changed, From: []byte("before"), To: []byte("after")},
+			{ID: contract, To: []byte("access(all) contract Hello {}")},
+			{ID: removed, From: []byte("removed")},
+		}, diff.Registers)
+
+		assert.Equal(t, []flowgo.Address{address}, diff.Accounts())
+
+		name, ok := diff.Registers[1].ContractName()
+		assert.True(t, ok)
+		assert.Equal(t, "Hello", name)
+
+		_, ok = diff.Registers[0].ContractName()
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,64 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import "fmt"
+
+// Durability controls how eagerly a backend flushes committed blocks to disk.
+//
+// Only the sqlite backend supports durability modes, other backends flush
+// according to their own configuration.
+type Durability int
+
+const (
+	// DurabilityDefault leaves the backend's own flushing behaviour untouched.
+	DurabilityDefault Durability = iota
+	// DurabilitySafe fsyncs every block commit, including the removal of its journal,
+	// before it is acknowledged.
+	DurabilitySafe
+	// DurabilityFast batches writes and leaves flushing to the operating system,
+	// trading crash safety for commit throughput.
+	DurabilityFast
+)
+
+func (d Durability) String() string {
+	switch d {
+	case DurabilitySafe:
+		return "safe"
+	case DurabilityFast:
+		return "fast"
+	default:
+		return ""
+	}
+}
+
+// ParseDurability parses a durability mode name. An empty string selects
+// DurabilityDefault.
+func ParseDurability(mode string) (Durability, error) {
+	switch mode {
+	case "":
+		return DurabilityDefault, nil
+	case "safe":
+		return DurabilitySafe, nil
+	case "fast":
+		return DurabilityFast, nil
+	default:
+		return DurabilityDefault, fmt.Errorf("invalid durability mode %q, expected \"safe\" or \"fast\"", mode)
+	}
+}
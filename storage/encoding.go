@@ -90,3 +90,131 @@ func encodeEvents(events []flowgo.Event) ([]byte, error) {
 func decodeEvents(events *[]flowgo.Event, from []byte) error {
 	return cbor.Unmarshal(from, events)
 }
+
+// blockEvents is the columnar layout of the events of a block. The event types and
+// transaction IDs are stored once per block, and each event references them by index,
+// so events can be filtered by type without decoding the events of other types.
+type blockEvents struct {
+	// Types are the distinct event types of the block.
+	Types []flowgo.EventType `cbor:"1,keyasint"`
+	// TransactionIDs are the distinct IDs of the transactions which emitted the events.
+	TransactionIDs []flowgo.Identifier `cbor:"2,keyasint"`
+	// TypeIndexes are the index in Types of the type of each event.
+	TypeIndexes []uint32 `cbor:"3,keyasint"`
+	// TransactionIDIndexes are the index in TransactionIDs of the transaction of each event.
+	TransactionIDIndexes []uint32 `cbor:"4,keyasint"`
+	TransactionIndexes   []uint32 `cbor:"5,keyasint"`
+	EventIndexes         []uint32 `cbor:"6,keyasint"`
+	Payloads             [][]byte `cbor:"7,keyasint"`
+}
+
+func newBlockEvents(events []flowgo.Event) blockEvents {
+	columns := blockEvents{
+		TypeIndexes:          make([]uint32, len(events)),
+		TransactionIDIndexes: make([]uint32, len(events)),
+		TransactionIndexes:   make([]uint32, len(events)),
+		EventIndexes:         make([]uint32, len(events)),
+		Payloads:             make([][]byte, len(events)),
+	}
+
+	types := make(map[flowgo.EventType]uint32)
+	transactionIDs := make(map[flowgo.Identifier]uint32)
+
+	for i, event := range events {
+		typeIndex, ok := types[event.Type]
+		if !ok {
+			typeIndex = uint32(len(columns.Types))
+			types[event.Type] = typeIndex
+			columns.Types = append(columns.Types, event.Type)
+		}
+
+		transactionIDIndex, ok := transactionIDs[event.TransactionID]
+		if !ok {
+			transactionIDIndex = uint32(len(columns.TransactionIDs))
+			transactionIDs[event.TransactionID] = transactionIDIndex
+			columns.TransactionIDs = append(columns.TransactionIDs, event.TransactionID)
+		}
+
+		columns.TypeIndexes[i] = typeIndex
+		columns.TransactionIDIndexes[i] = transactionIDIndex
+		columns.TransactionIndexes[i] = event.TransactionIndex
+		columns.EventIndexes[i] = event.EventIndex
+		columns.Payloads[i] = event.Payload
+	}
+
+	return columns
+}
+
+// events returns the events of the block, optionally filtered by type.
+func (c blockEvents) events(eventType flowgo.EventType) []flowgo.Event {
+	typeIndex := -1
+	if eventType != "" {
+		for i, t := range c.Types {
+			if t == eventType {
+				typeIndex = i
+				break
+			}
+		}
+		if typeIndex < 0 {
+			return nil
+		}
+	}
+
+	var events []flowgo.Event
+	for i, index := range c.TypeIndexes {
+		if typeIndex >= 0 && int(index) != typeIndex {
+			continue
+		}
+		events = append(events, flowgo.Event{
+			Type:             c.Types[index],
+			TransactionID:    c.TransactionIDs[c.TransactionIDIndexes[i]],
+			TransactionIndex: c.TransactionIndexes[i],
+			EventIndex:       c.EventIndexes[i],
+			Payload:          c.Payloads[i],
+		})
+	}
+	return events
+}
+
+func encodeBlockEvents(events blockEvents) ([]byte, error) {
+	return em.Marshal(events)
+}
+
+func decodeBlockEvents(events *blockEvents, from []byte) error {
+	return cbor.Unmarshal(from, events)
+}
+
+// isBlockEvents returns whether the encoded events of a block use the columnar layout,
+// which is encoded as a CBOR map, rather than the array of events stored by older versions.
+func isBlockEvents(from []byte) bool {
+	const cborMajorTypeMap = 5
+	return len(from) > 0 && from[0]>>5 == cborMajorTypeMap
+}
+
+// ColumnarEvents re-encodes the events of a block stored by older versions, as an
+// array of events, in the columnar layout, keeping their compression. Events already
+// in the columnar layout are returned unchanged, and converted is false.
+func ColumnarEvents(value []byte) (columnar []byte, converted bool, err error) {
+	decompressed, err := decompress(value)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(decompressed) == 0 || isBlockEvents(decompressed) {
+		return value, false, nil
+	}
+
+	var events []flowgo.Event
+	err = decodeEvents(&events, decompressed)
+	if err != nil {
+		return nil, false, err
+	}
+	// events persisted by older versions may not be in canonical order
+	SortEvents(events)
+
+	encoded, err := encodeBlockEvents(newBlockEvents(events))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return compressionOf(value).compress(encoded), true, nil
+}
@@ -19,6 +19,7 @@
 package storage
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/onflow/flow-go-sdk/test"
@@ -131,3 +132,39 @@ func TestEncodeEvents(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, events, decodedEvents)
 }
+
+func TestEncodeBlockEvents(t *testing.T) {
+
+	t.Parallel()
+
+	generator := test.EventGenerator()
+
+	events := make([]flowgo.Event, 6)
+	for i := range events {
+		event, err := convert.SDKEventToFlow(generator.New())
+		require.NoError(t, err)
+
+		event.Type = flowgo.EventType(fmt.Sprintf("A.0000000000000001.Test.Event%d", i%2))
+		event.TransactionIndex = uint32(i / 3)
+		event.EventIndex = uint32(i % 3)
+		events[i] = event
+	}
+
+	columns := newBlockEvents(events)
+	assert.Len(t, columns.Types, 2)
+
+	data, err := encodeBlockEvents(columns)
+	require.NoError(t, err)
+	assert.True(t, isBlockEvents(data))
+
+	var decoded blockEvents
+	err = decodeBlockEvents(&decoded, data)
+	require.NoError(t, err)
+	assert.Equal(t, events, decoded.events(""))
+	assert.Equal(t, []flowgo.Event{events[1], events[3], events[5]}, decoded.events(events[1].Type))
+	assert.Empty(t, decoded.events("A.0000000000000001.Test.Missing"))
+
+	legacy, err := encodeEvents(events)
+	require.NoError(t, err)
+	assert.False(t, isBlockEvents(legacy))
+}
@@ -18,7 +18,71 @@
 
 package storage
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // ErrNotFound is an error returned when an entity cannot be found.
 var ErrNotFound = errors.New("could not find entity")
+
+// ErrStoreNotEmpty is returned when copying blocks to a store which already holds blocks.
+var ErrStoreNotEmpty = errors.New("store is not empty")
+
+// ErrCheckpointNotSupported is returned when checkpointing a store which was not bootstrapped from a checkpoint.
+var ErrCheckpointNotSupported = errors.New("storage does not support checkpoints")
+
+// ErrCompactionNotSupported is returned when compacting a store which can not reclaim disk space.
+var ErrCompactionNotSupported = errors.New("storage does not support compaction")
+
+// ErrSnapshotLoaded is returned when deleting the snapshot the emulator runs on.
+var ErrSnapshotLoaded = errors.New("snapshot is loaded")
+
+// UnsupportedSchemaVersionError is returned when persisted storage was written
+// by a newer version of the emulator than the one reading it.
+type UnsupportedSchemaVersionError struct {
+	Version   uint64
+	Supported uint64
+}
+
+func (e *UnsupportedSchemaVersionError) Error() string {
+	return fmt.Sprintf(
+		"storage schema version %d is newer than the latest supported version %d, upgrade the emulator to use this storage",
+		e.Version,
+		e.Supported,
+	)
+}
+
+// IncompatibleStoreError is returned when opening a store created with a
+// configuration the emulator can not run on, such as another chain.
+type IncompatibleStoreError struct {
+	Setting    string
+	Stored     string
+	Configured string
+}
+
+func (e *IncompatibleStoreError) Error() string {
+	return fmt.Sprintf(
+		"storage was created with %s %s, but the emulator is configured with %s %s",
+		e.Setting,
+		e.Stored,
+		e.Setting,
+		e.Configured,
+	)
+}
+
+// UnknownProviderError is returned when opening a store with a storage
+// provider which is not registered.
+type UnknownProviderError struct {
+	Name       string
+	Registered []string
+}
+
+func (e *UnknownProviderError) Error() string {
+	return fmt.Sprintf(
+		"unknown storage provider %q, registered providers are: %s",
+		e.Name,
+		strings.Join(e.Registered, ", "),
+	)
+}
@@ -0,0 +1,226 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package fork implements a copy-on-write store continuing from the state of another
+// store at a block height. The blocks up to the height are read from the parent store,
+// the blocks committed after it are kept in memory and never reach the parent store.
+package fork
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	flowgo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/memstore"
+	"github.com/onflow/flow-emulator/types"
+)
+
+// Store reads the blocks up to the height it was forked at from the parent store,
+// and stores the blocks committed after it in memory.
+//
+// The parent store must not be rolled back below the height, or have its state at
+// the height pruned, while the fork is in use. Blocks committed to the parent store
+// after the height are not visible in the fork.
+type Store struct {
+	*memstore.Store
+	parent storage.Store
+	height uint64
+}
+
+var _ storage.Store = &Store{}
+var _ storage.SnapshotProvider = &Store{}
+var _ storage.RollbackProvider = &Store{}
+
+// New returns a store forked from the parent store at the given block height.
+func New(ctx context.Context, parent storage.Store, height uint64) (*Store, error) {
+	ledger, err := parent.LedgerByHeight(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the ledger at height %d: %w", height, err)
+	}
+
+	return &Store{
+		Store:  memstore.NewOnLedger(height, ledger),
+		parent: parent,
+		height: height,
+	}, nil
+}
+
+// Height returns the height of the latest block of the parent store the store includes.
+func (s *Store) Height() uint64 {
+	return s.height
+}
+
+func (s *Store) LatestBlockHeight(ctx context.Context) (uint64, error) {
+	block, err := s.LatestBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return block.Header.Height, nil
+}
+
+func (s *Store) LatestBlock(ctx context.Context) (flowgo.Block, error) {
+	block, err := s.Store.LatestBlock(ctx)
+	if errors.Is(err, storage.ErrNotFound) {
+		parentBlock, err := s.parent.BlockByHeight(ctx, s.height)
+		if err != nil {
+			return flowgo.Block{}, err
+		}
+		return *parentBlock, nil
+	}
+
+	return block, err
+}
+
+func (s *Store) BlockByID(ctx context.Context, blockID flowgo.Identifier) (*flowgo.Block, error) {
+	block, err := s.Store.BlockByID(ctx, blockID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		return block, err
+	}
+
+	block, err = s.parent.BlockByID(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	if block.Header.Height > s.height {
+		return nil, storage.ErrNotFound
+	}
+
+	return block, nil
+}
+
+func (s *Store) BlockByHeight(ctx context.Context, height uint64) (*flowgo.Block, error) {
+	if height <= s.height {
+		return s.parent.BlockByHeight(ctx, height)
+	}
+
+	return s.Store.BlockByHeight(ctx, height)
+}
+
+// GetBlocks reads the blocks of the parent store and the blocks committed after the
+// fork with a single call to each store.
+func (s *Store) GetBlocks(ctx context.Context, heights []uint64) ([]*flowgo.Block, error) {
+	var parentHeights, forkHeights []uint64
+	for _, height := range heights {
+		if height <= s.height {
+			parentHeights = append(parentHeights, height)
+		} else {
+			forkHeights = append(forkHeights, height)
+		}
+	}
+
+	parentBlocks, err := s.parent.GetBlocks(ctx, parentHeights)
+	if err != nil {
+		return nil, err
+	}
+	forkBlocks, err := s.Store.GetBlocks(ctx, forkHeights)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*flowgo.Block, len(heights))
+	for i, height := range heights {
+		if height <= s.height {
+			blocks[i], parentBlocks = parentBlocks[0], parentBlocks[1:]
+		} else {
+			blocks[i], forkBlocks = forkBlocks[0], forkBlocks[1:]
+		}
+	}
+
+	return blocks, nil
+}
+
+func (s *Store) CollectionByID(ctx context.Context, collectionID flowgo.Identifier) (flowgo.LightCollection, error) {
+	collection, err := s.Store.CollectionByID(ctx, collectionID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		return collection, err
+	}
+
+	return s.parent.CollectionByID(ctx, collectionID)
+}
+
+func (s *Store) TransactionByID(ctx context.Context, transactionID flowgo.Identifier) (flowgo.TransactionBody, error) {
+	tx, err := s.Store.TransactionByID(ctx, transactionID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		return tx, err
+	}
+
+	// the transactions of the parent store committed after the fork are not visible
+	_, err = s.TransactionResultByID(ctx, transactionID)
+	if err != nil {
+		return flowgo.TransactionBody{}, err
+	}
+
+	return s.parent.TransactionByID(ctx, transactionID)
+}
+
+func (s *Store) TransactionResultByID(ctx context.Context, transactionID flowgo.Identifier) (types.StorableTransactionResult, error) {
+	result, err := s.Store.TransactionResultByID(ctx, transactionID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		return result, err
+	}
+
+	result, err = s.parent.TransactionResultByID(ctx, transactionID)
+	if err != nil {
+		return types.StorableTransactionResult{}, err
+	}
+	if result.BlockHeight > s.height {
+		return types.StorableTransactionResult{}, storage.ErrNotFound
+	}
+
+	return result, nil
+}
+
+func (s *Store) LedgerByHeight(ctx context.Context, blockHeight uint64) (snapshot.StorageSnapshot, error) {
+	if blockHeight < s.height {
+		return s.parent.LedgerByHeight(ctx, blockHeight)
+	}
+
+	return s.Store.LedgerByHeight(ctx, blockHeight)
+}
+
+func (s *Store) EventsByHeight(ctx context.Context, blockHeight uint64, eventType string) ([]flowgo.Event, error) {
+	if blockHeight <= s.height {
+		return s.parent.EventsByHeight(ctx, blockHeight, eventType)
+	}
+
+	return s.Store.EventsByHeight(ctx, blockHeight, eventType)
+}
+
+func (s *Store) GetMeta(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.Store.GetMeta(ctx, key)
+	if !errors.Is(err, storage.ErrNotFound) {
+		return value, err
+	}
+
+	return s.parent.GetMeta(ctx, key)
+}
+
+// RollbackToBlockHeight removes the blocks committed to the fork above the given height.
+// The fork can not be rolled back below the height it was forked at.
+func (s *Store) RollbackToBlockHeight(height uint64) error {
+	if height < s.height {
+		return fmt.Errorf("cannot roll back to height %d, the store was forked at height %d", height, s.height)
+	}
+
+	return s.Store.RollbackToBlockHeight(height)
+}
@@ -0,0 +1,134 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fork_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/fork"
+	"github.com/onflow/flow-emulator/storage/memstore"
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestStore(t *testing.T) {
+
+	t.Parallel()
+
+	ctx := context.Background()
+	key := flowgo.NewRegisterID("", "foo")
+
+	commitBlock := func(t *testing.T, store storage.Store, height uint64, view uint64) flowgo.Block {
+		tx := flowgo.NewTransactionBody().SetScript([]byte{byte(height)})
+
+		block := flowgo.Block{
+			Header:  &flowgo.Header{Height: height, View: view},
+			Payload: &flowgo.Payload{},
+		}
+
+		err := store.CommitBlock(ctx, storage.BlockCommit{
+			Block:        block,
+			Transactions: map[flowgo.Identifier]*flowgo.TransactionBody{tx.ID(): tx},
+			TransactionResults: map[flowgo.Identifier]*types.StorableTransactionResult{
+				tx.ID(): {BlockID: block.ID(), BlockHeight: height},
+			},
+			ExecutionSnapshot: &snapshot.ExecutionSnapshot{
+				WriteSet: map[flowgo.RegisterID]flowgo.RegisterValue{
+					key: {byte(height)},
+				},
+			},
+			Events: []flowgo.Event{{Type: "A.Test", TransactionID: tx.ID()}},
+		})
+		require.NoError(t, err)
+
+		return block
+	}
+
+	register := func(t *testing.T, store storage.Store, height uint64) flowgo.RegisterValue {
+		ledger, err := store.LedgerByHeight(ctx, height)
+		require.NoError(t, err)
+		value, err := ledger.Get(key)
+		require.NoError(t, err)
+		return value
+	}
+
+	parent := memstore.New()
+	for height := uint64(0); height <= 2; height++ {
+		commitBlock(t, parent, height, height)
+	}
+
+	store, err := fork.New(ctx, parent, 2)
+	require.NoError(t, err)
+
+	// blocks committed to the parent after the fork are not visible
+	parentBlock := commitBlock(t, parent, 3, 3)
+	// blocks committed to the fork are not visible in the parent
+	forkBlock := commitBlock(t, store, 3, 4)
+
+	latestBlock, err := store.LatestBlock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, forkBlock.ID(), latestBlock.ID())
+
+	_, err = store.BlockByID(ctx, parentBlock.ID())
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = parent.BlockByID(ctx, forkBlock.ID())
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	block, err := store.BlockByHeight(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), block.Header.Height)
+
+	blocks, err := store.GetBlocks(ctx, []uint64{2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, forkBlock.ID(), blocks[1].ID())
+
+	assert.Equal(t, flowgo.RegisterValue{1}, register(t, store, 1))
+	assert.Equal(t, flowgo.RegisterValue{2}, register(t, store, 2))
+	assert.Equal(t, flowgo.RegisterValue{3}, register(t, store, 3))
+
+	events, err := store.EventsByHeight(ctx, 2, "")
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	parentTx := flowgo.NewTransactionBody().SetScript([]byte{1})
+	_, err = store.TransactionByID(ctx, parentTx.ID())
+	require.NoError(t, err)
+
+	// the transactions of both the parent and the fork at height 3 have the same ID,
+	// the fork's result is returned
+	result, err := store.TransactionResultByID(ctx, flowgo.NewTransactionBody().SetScript([]byte{3}).ID())
+	require.NoError(t, err)
+	assert.Equal(t, forkBlock.ID(), result.BlockID)
+
+	t.Run("rollback", func(t *testing.T) {
+		assert.Error(t, store.RollbackToBlockHeight(1))
+
+		require.NoError(t, store.RollbackToBlockHeight(2))
+
+		latestHeight, err := store.LatestBlockHeight(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), latestHeight)
+	})
+}
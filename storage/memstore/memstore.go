@@ -20,7 +20,6 @@ package memstore
 
 import (
 	"context"
-	"fmt"
 	"sync"
 
 	"github.com/onflow/flow-go/fvm/storage/snapshot"
@@ -49,6 +48,14 @@ type Store struct {
 	eventsByBlockHeight map[uint64][]flowgo.Event
 	// highest block height
 	blockHeight uint64
+	// metadata by key
+	meta map[string][]byte
+	// snapshots by name
+	snapshots map[string]*memorySnapshot
+	// snapshot names, oldest first
+	snapshotNames []string
+	// name of the last loaded snapshot, if any
+	loadedSnapshot string
 }
 
 // New returns a new in-memory Store implementation.
@@ -62,10 +69,26 @@ func New() *Store {
 		transactionResults:  make(map[flowgo.Identifier]types.StorableTransactionResult),
 		ledger:              make(map[uint64]snapshot.SnapshotTree),
 		eventsByBlockHeight: make(map[uint64][]flowgo.Event),
+		meta:                make(map[string][]byte),
+		snapshots:           make(map[string]*memorySnapshot),
 	}
 }
 
+// NewOnLedger returns a new in-memory Store continuing from the given ledger state
+// at the given block height, for the blocks committed after it.
+//
+// The blocks up to the height are not stored, the store's user must provide them.
+func NewOnLedger(height uint64, ledger snapshot.StorageSnapshot) *Store {
+	store := New()
+	store.ledger[height] = snapshot.NewSnapshotTree(ledger)
+	store.blockHeight = height
+	return store
+}
+
 var _ storage.Store = &Store{}
+var _ storage.LedgerPruner = &Store{}
+var _ storage.SnapshotProvider = &Store{}
+var _ storage.RollbackProvider = &Store{}
 
 func (s *Store) Start() error {
 	return nil
@@ -142,46 +165,51 @@ func (s *Store) BlockByHeight(ctx context.Context, height uint64) (*flowgo.Block
 	return &block, nil
 }
 
-func (s *Store) CommitBlock(
-	ctx context.Context,
-	block flowgo.Block,
-	collections []*flowgo.LightCollection,
-	transactions map[flowgo.Identifier]*flowgo.TransactionBody,
-	transactionResults map[flowgo.Identifier]*types.StorableTransactionResult,
-	executionSnapshot *snapshot.ExecutionSnapshot,
-	events []flowgo.Event,
-) error {
+func (s *Store) GetBlocks(ctx context.Context, heights []uint64) ([]*flowgo.Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blocks := make([]*flowgo.Block, len(heights))
+	for i, height := range heights {
+		block, ok := s.blocks[height]
+		if !ok {
+			return nil, storage.ErrNotFound
+		}
+		blocks[i] = &block
+	}
+
+	return blocks, nil
+}
+
+func (s *Store) CommitBlock(ctx context.Context, commit storage.BlockCommit) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if len(transactions) != len(transactionResults) {
-		return fmt.Errorf(
-			"transactions count (%d) does not match result count (%d)",
-			len(transactions),
-			len(transactionResults),
-		)
+	err := commit.Validate()
+	if err != nil {
+		return err
 	}
 
-	err := s.storeBlock(&block)
+	err = s.storeBlock(&commit.Block)
 	if err != nil {
 		return err
 	}
 
-	for _, col := range collections {
+	for _, col := range commit.Collections {
 		err := s.insertCollection(*col)
 		if err != nil {
 			return err
 		}
 	}
 
-	for _, tx := range transactions {
+	for _, tx := range commit.Transactions {
 		err := s.insertTransaction(tx.ID(), *tx)
 		if err != nil {
 			return err
 		}
 	}
 
-	for txID, result := range transactionResults {
+	for txID, result := range commit.TransactionResults {
 		err := s.insertTransactionResult(txID, *result)
 		if err != nil {
 			return err
@@ -189,13 +217,13 @@ func (s *Store) CommitBlock(
 	}
 
 	err = s.insertExecutionSnapshot(
-		block.Header.Height,
-		executionSnapshot)
+		commit.Block.Header.Height,
+		commit.ExecutionSnapshot)
 	if err != nil {
 		return err
 	}
 
-	err = s.insertEvents(block.Header.Height, events)
+	err = s.insertEvents(commit.Block.Header.Height, commit.Events)
 	if err != nil {
 		return err
 	}
@@ -251,9 +279,31 @@ func (s *Store) LedgerByHeight(
 	ctx context.Context,
 	blockHeight uint64,
 ) (snapshot.StorageSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.ledger[blockHeight], nil
 }
 
+// PruneLedger drops the ledger states of the blocks below the given height,
+// except the ones of the pinned heights.
+func (s *Store) PruneLedger(ctx context.Context, height uint64, pinned []uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := make(map[uint64]struct{}, len(pinned))
+	for _, blockHeight := range pinned {
+		kept[blockHeight] = struct{}{}
+	}
+
+	for blockHeight := range s.ledger {
+		if _, ok := kept[blockHeight]; blockHeight < height && !ok {
+			delete(s.ledger, blockHeight)
+		}
+	}
+	return nil
+}
+
 func (s *Store) EventsByHeight(
 	ctx context.Context,
 	blockHeight uint64,
@@ -279,6 +329,25 @@ func (s *Store) EventsByHeight(
 	return events, nil
 }
 
+func (s *Store) GetMeta(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.meta[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *Store) PutMeta(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.meta[key] = value
+	return nil
+}
+
 func (s *Store) insertCollection(col flowgo.LightCollection) error {
 	s.collections[col.ID()] = col
 	return nil
@@ -306,11 +375,14 @@ func (s *Store) insertExecutionSnapshot(
 }
 
 func (s *Store) insertEvents(blockHeight uint64, events []flowgo.Event) error {
-	if s.eventsByBlockHeight[blockHeight] == nil {
-		s.eventsByBlockHeight[blockHeight] = events
-	} else {
-		s.eventsByBlockHeight[blockHeight] = append(s.eventsByBlockHeight[blockHeight], events...)
-	}
+	blockEvents := append(
+		make([]flowgo.Event, 0, len(s.eventsByBlockHeight[blockHeight])+len(events)),
+		s.eventsByBlockHeight[blockHeight]...,
+	)
+	blockEvents = append(blockEvents, events...)
+	storage.SortEvents(blockEvents)
+
+	s.eventsByBlockHeight[blockHeight] = blockEvents
 
 	return nil
 }
@@ -28,6 +28,8 @@ import (
 	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/storage"
 )
 
 func TestMemstore(t *testing.T) {
@@ -114,3 +116,132 @@ func TestMemstoreSetValueToNil(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, string(nilValue), string(register))
 }
+
+func TestMemstoreEventOrdering(t *testing.T) {
+
+	t.Parallel()
+
+	const blockHeight = 1
+	store := New()
+
+	// events of a block inserted in two batches, out of order
+	require.NoError(t, store.insertEvents(blockHeight, []flowgo.Event{
+		{TransactionIndex: 1, EventIndex: 0},
+		{TransactionIndex: 0, EventIndex: 1},
+	}))
+	require.NoError(t, store.insertEvents(blockHeight, []flowgo.Event{
+		{TransactionIndex: 0, EventIndex: 0},
+	}))
+
+	events, err := store.EventsByHeight(context.Background(), blockHeight, "")
+	require.NoError(t, err)
+	assert.Equal(t, []flowgo.Event{
+		{TransactionIndex: 0, EventIndex: 0},
+		{TransactionIndex: 0, EventIndex: 1},
+		{TransactionIndex: 1, EventIndex: 0},
+	}, events)
+}
+
+func TestMemstorePruneLedger(t *testing.T) {
+
+	t.Parallel()
+
+	store := New()
+	key := flow.NewRegisterID("", "foo")
+
+	for height := uint64(0); height < 5; height++ {
+		err := store.insertExecutionSnapshot(
+			height,
+			&snapshot.ExecutionSnapshot{
+				WriteSet: map[flowgo.RegisterID]flowgo.RegisterValue{
+					key: {byte(height)},
+				},
+			})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, store.PruneLedger(context.Background(), 3, []uint64{1}))
+	assert.Len(t, store.ledger, 3)
+
+	ledger, err := store.LedgerByHeight(context.Background(), 3)
+	require.NoError(t, err)
+	register, err := ledger.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3}, register)
+
+	// the ledger of the pinned height is kept
+	ledger, err = store.LedgerByHeight(context.Background(), 1)
+	require.NoError(t, err)
+	register, err = ledger.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, register)
+}
+
+func TestMemstoreSnapshotsAndRollback(t *testing.T) {
+
+	t.Parallel()
+
+	ctx := context.Background()
+	store := New()
+	key := flow.NewRegisterID("", "foo")
+
+	storeBlocks := func(t *testing.T, from, to uint64) {
+		for height := from; height <= to; height++ {
+			err := store.StoreBlock(ctx, &flowgo.Block{Header: &flowgo.Header{Height: height}, Payload: &flowgo.Payload{}})
+			require.NoError(t, err)
+			err = store.insertExecutionSnapshot(
+				height,
+				&snapshot.ExecutionSnapshot{
+					WriteSet: map[flowgo.RegisterID]flowgo.RegisterValue{
+						key: {byte(height)},
+					},
+				})
+			require.NoError(t, err)
+		}
+	}
+
+	latestHeight := func(t *testing.T) uint64 {
+		height, err := store.LatestBlockHeight(ctx)
+		require.NoError(t, err)
+		return height
+	}
+
+	storeBlocks(t, 0, 3)
+	require.NoError(t, store.CreateSnapshot("at-3", "three blocks"))
+
+	require.NoError(t, store.RollbackToBlockHeight(1))
+	assert.Equal(t, uint64(1), latestHeight(t))
+	_, err := store.BlockByHeight(ctx, 2)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.Len(t, store.ledger, 2)
+
+	assert.Error(t, store.RollbackToBlockHeight(1))
+
+	// blocks committed after the rollback do not change the snapshot
+	storeBlocks(t, 2, 5)
+	assert.Equal(t, uint64(5), latestHeight(t))
+
+	require.NoError(t, store.LoadSnapshot("at-3"))
+	assert.Equal(t, uint64(3), latestHeight(t))
+
+	ledger, err := store.LedgerByHeight(ctx, 3)
+	require.NoError(t, err)
+	register, err := ledger.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3}, register)
+
+	infos, err := store.SnapshotInfos()
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "three blocks", infos[0].Description)
+	assert.Equal(t, uint64(3), infos[0].BlockHeight)
+
+	err = store.DeleteSnapshot("at-3")
+	assert.ErrorIs(t, err, storage.ErrSnapshotLoaded)
+
+	require.NoError(t, store.CreateSnapshot("other", ""))
+	require.NoError(t, store.DeleteSnapshot("other"))
+	names, err := store.Snapshots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"at-3"}, names)
+}
@@ -0,0 +1,196 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memstore
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+// memorySnapshot is a copy of the state of the store.
+//
+// The maps are copied, their values are not: blocks, ledger states and
+// events are never modified once stored, only replaced.
+type memorySnapshot struct {
+	info  storage.SnapshotInfo
+	state *Store
+}
+
+// copyState returns a store with a copy of the state of the store, without its snapshots.
+// The caller must hold the lock.
+func (s *Store) copyState() *Store {
+	return &Store{
+		blockIDToHeight:     maps.Clone(s.blockIDToHeight),
+		blocks:              maps.Clone(s.blocks),
+		collections:         maps.Clone(s.collections),
+		transactions:        maps.Clone(s.transactions),
+		transactionResults:  maps.Clone(s.transactionResults),
+		ledger:              maps.Clone(s.ledger),
+		eventsByBlockHeight: maps.Clone(s.eventsByBlockHeight),
+		blockHeight:         s.blockHeight,
+		meta:                maps.Clone(s.meta),
+	}
+}
+
+// restoreState replaces the state of the store with a copy of the given state.
+// The caller must hold the lock.
+func (s *Store) restoreState(state *Store) {
+	restored := state.copyState()
+
+	s.blockIDToHeight = restored.blockIDToHeight
+	s.blocks = restored.blocks
+	s.collections = restored.collections
+	s.transactions = restored.transactions
+	s.transactionResults = restored.transactionResults
+	s.ledger = restored.ledger
+	s.eventsByBlockHeight = restored.eventsByBlockHeight
+	s.blockHeight = restored.blockHeight
+	s.meta = restored.meta
+}
+
+// SupportSnapshotsWithCurrentConfig returns true, snapshots are always supported.
+func (s *Store) SupportSnapshotsWithCurrentConfig() bool {
+	return true
+}
+
+func (s *Store) Snapshots() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.snapshotNames), nil
+}
+
+// SnapshotInfos returns the metadata of the snapshots, oldest first.
+func (s *Store) SnapshotInfos() ([]storage.SnapshotInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]storage.SnapshotInfo, 0, len(s.snapshotNames))
+	for _, name := range s.snapshotNames {
+		infos = append(infos, s.snapshots[name].info)
+	}
+
+	return infos, nil
+}
+
+// CreateSnapshot saves a copy of the current state under the given name,
+// replacing the snapshot with the same name, if any.
+func (s *Store) CreateSnapshot(name string, description string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.snapshots[name]; exists {
+		index := slices.Index(s.snapshotNames, name)
+		s.snapshotNames = slices.Delete(s.snapshotNames, index, index+1)
+	}
+
+	s.snapshots[name] = &memorySnapshot{
+		info: storage.SnapshotInfo{
+			Name:        name,
+			CreatedAt:   time.Now(),
+			BlockHeight: s.blockHeight,
+			Description: description,
+		},
+		state: s.copyState(),
+	}
+	s.snapshotNames = append(s.snapshotNames, name)
+
+	return nil
+}
+
+// LoadSnapshot replaces the current state with a copy of the snapshot.
+// The snapshot is left unchanged, so it can be loaded again later.
+func (s *Store) LoadSnapshot(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.snapshots[name]
+	if !ok {
+		return fmt.Errorf("snapshot %s does not exist", name)
+	}
+
+	s.restoreState(snapshot.state)
+	s.loadedSnapshot = name
+
+	return nil
+}
+
+// DeleteSnapshot removes a snapshot. The last loaded snapshot can not be deleted.
+func (s *Store) DeleteSnapshot(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name == s.loadedSnapshot {
+		return fmt.Errorf("failed to delete snapshot %s: %w", name, storage.ErrSnapshotLoaded)
+	}
+
+	if _, ok := s.snapshots[name]; !ok {
+		return fmt.Errorf("snapshot %s does not exist", name)
+	}
+
+	delete(s.snapshots, name)
+	index := slices.Index(s.snapshotNames, name)
+	s.snapshotNames = slices.Delete(s.snapshotNames, index, index+1)
+
+	return nil
+}
+
+// RollbackToBlockHeight removes the blocks above the given height, along with
+// their collections, transactions, results, events and ledger states.
+func (s *Store) RollbackToBlockHeight(height uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.blockHeight <= height {
+		return fmt.Errorf("rollback height should be less then current height")
+	}
+
+	for blockHeight := height + 1; blockHeight <= s.blockHeight; blockHeight++ {
+		block, ok := s.blocks[blockHeight]
+		if !ok {
+			continue
+		}
+
+		for _, guarantee := range block.Payload.Guarantees {
+			collection, ok := s.collections[guarantee.CollectionID]
+			if !ok {
+				continue
+			}
+			for _, txID := range collection.Transactions {
+				delete(s.transactions, txID)
+				delete(s.transactionResults, txID)
+			}
+			delete(s.collections, guarantee.CollectionID)
+		}
+
+		delete(s.blockIDToHeight, block.ID())
+		delete(s.blocks, blockHeight)
+		delete(s.ledger, blockHeight)
+		delete(s.eventsByBlockHeight, blockHeight)
+	}
+
+	s.blockHeight = height
+
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package migration upgrades the persisted state of emulator stores, so that
+// state written by an older emulator can be opened by a newer one.
+//
+// A store versions the layout of its state, and registers one migration per
+// version: migrations[i] upgrades state from version i to version i+1.
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+// A Hook upgrades persisted state from version from to version to, which is
+// always the version following from.
+type Hook func(ctx context.Context, from, to uint64) error
+
+// A Migration upgrades persisted state by one version.
+type Migration struct {
+	// Description explains what the migration changes.
+	Description string
+	Migrate     Hook
+}
+
+// Migrations are the migrations of a store, Migrations[i] upgrading its state
+// from version i to version i+1.
+type Migrations []Migration
+
+// Latest returns the version state is at once every migration ran.
+func (m Migrations) Latest() uint64 {
+	return uint64(len(m))
+}
+
+// Migrate upgrades state from version from to version to, running the
+// migrations in order.
+//
+// State at a version newer than the target version is left untouched and an
+// UnsupportedSchemaVersionError is returned, rather than risking misreading it.
+func (m Migrations) Migrate(ctx context.Context, from, to uint64) error {
+	if from > to {
+		return &storage.UnsupportedSchemaVersionError{
+			Version:   from,
+			Supported: to,
+		}
+	}
+
+	if to > m.Latest() {
+		return fmt.Errorf("no migration to storage schema version %d, the latest version is %d", to, m.Latest())
+	}
+
+	for version := from; version < to; version++ {
+		migration := m[version]
+		if migration.Migrate == nil {
+			continue
+		}
+
+		err := migration.Migrate(ctx, version, version+1)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to migrate storage schema from version %d to %d (%s): %w",
+				version,
+				version+1,
+				migration.Description,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
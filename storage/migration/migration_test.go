@@ -0,0 +1,112 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migration_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/migration"
+)
+
+func TestMigrate(t *testing.T) {
+
+	t.Parallel()
+
+	newMigrations := func(ran *[]string) migration.Migrations {
+		hook := func(ctx context.Context, from, to uint64) error {
+			*ran = append(*ran, fmt.Sprintf("%d->%d", from, to))
+			return nil
+		}
+		return migration.Migrations{
+			{Description: "first", Migrate: hook},
+			{Description: "second", Migrate: hook},
+			{Description: "third", Migrate: hook},
+		}
+	}
+
+	t.Run("runs the migrations between the versions in order", func(t *testing.T) {
+		t.Parallel()
+
+		var ran []string
+		migrations := newMigrations(&ran)
+
+		assert.Equal(t, uint64(3), migrations.Latest())
+
+		err := migrations.Migrate(context.Background(), 1, migrations.Latest())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1->2", "2->3"}, ran)
+	})
+
+	t.Run("up to date state is not migrated", func(t *testing.T) {
+		t.Parallel()
+
+		var ran []string
+		migrations := newMigrations(&ran)
+
+		err := migrations.Migrate(context.Background(), 3, 3)
+		require.NoError(t, err)
+		assert.Empty(t, ran)
+	})
+
+	t.Run("newer state is refused", func(t *testing.T) {
+		t.Parallel()
+
+		var ran []string
+		migrations := newMigrations(&ran)
+
+		err := migrations.Migrate(context.Background(), 4, 3)
+
+		var versionErr *storage.UnsupportedSchemaVersionError
+		require.ErrorAs(t, err, &versionErr)
+		assert.Equal(t, uint64(4), versionErr.Version)
+		assert.Equal(t, uint64(3), versionErr.Supported)
+		assert.Empty(t, ran)
+	})
+
+	t.Run("unknown target version is refused", func(t *testing.T) {
+		t.Parallel()
+
+		var ran []string
+		migrations := newMigrations(&ran)
+
+		err := migrations.Migrate(context.Background(), 0, 4)
+		require.Error(t, err)
+		assert.Empty(t, ran)
+	})
+
+	t.Run("stops at the first failing migration", func(t *testing.T) {
+		t.Parallel()
+
+		var ran []string
+		migrations := newMigrations(&ran)
+		migrations[1].Migrate = func(context.Context, uint64, uint64) error {
+			return fmt.Errorf("boom")
+		}
+
+		err := migrations.Migrate(context.Background(), 0, migrations.Latest())
+		require.ErrorContains(t, err, "from version 1 to 2 (second): boom")
+		assert.Equal(t, []string{"0->1"}, ran)
+	})
+}
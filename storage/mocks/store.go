@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	storage "github.com/onflow/flow-emulator/storage"
 	types "github.com/onflow/flow-emulator/types"
 	snapshot "github.com/onflow/flow-go/fvm/storage/snapshot"
 	flow "github.com/onflow/flow-go/model/flow"
@@ -83,17 +84,17 @@ func (mr *MockStoreMockRecorder) CollectionByID(arg0, arg1 interface{}) *gomock.
 }
 
 // CommitBlock mocks base method.
-func (m *MockStore) CommitBlock(arg0 context.Context, arg1 flow.Block, arg2 []*flow.LightCollection, arg3 map[flow.Identifier]*flow.TransactionBody, arg4 map[flow.Identifier]*types.StorableTransactionResult, arg5 *snapshot.ExecutionSnapshot, arg6 []flow.Event) error {
+func (m *MockStore) CommitBlock(arg0 context.Context, arg1 storage.BlockCommit) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CommitBlock", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret := m.ctrl.Call(m, "CommitBlock", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CommitBlock indicates an expected call of CommitBlock.
-func (mr *MockStoreMockRecorder) CommitBlock(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) CommitBlock(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitBlock", reflect.TypeOf((*MockStore)(nil).CommitBlock), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitBlock", reflect.TypeOf((*MockStore)(nil).CommitBlock), arg0, arg1)
 }
 
 // EventsByHeight mocks base method.
@@ -111,6 +112,36 @@ func (mr *MockStoreMockRecorder) EventsByHeight(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsByHeight", reflect.TypeOf((*MockStore)(nil).EventsByHeight), arg0, arg1, arg2)
 }
 
+// GetBlocks mocks base method.
+func (m *MockStore) GetBlocks(arg0 context.Context, arg1 []uint64) ([]*flow.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocks", arg0, arg1)
+	ret0, _ := ret[0].([]*flow.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocks indicates an expected call of GetBlocks.
+func (mr *MockStoreMockRecorder) GetBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocks", reflect.TypeOf((*MockStore)(nil).GetBlocks), arg0, arg1)
+}
+
+// GetMeta mocks base method.
+func (m *MockStore) GetMeta(arg0 context.Context, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMeta", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMeta indicates an expected call of GetMeta.
+func (mr *MockStoreMockRecorder) GetMeta(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMeta", reflect.TypeOf((*MockStore)(nil).GetMeta), arg0, arg1)
+}
+
 // LatestBlock mocks base method.
 func (m *MockStore) LatestBlock(arg0 context.Context) (flow.Block, error) {
 	m.ctrl.T.Helper()
@@ -156,6 +187,20 @@ func (mr *MockStoreMockRecorder) LedgerByHeight(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LedgerByHeight", reflect.TypeOf((*MockStore)(nil).LedgerByHeight), arg0, arg1)
 }
 
+// PutMeta mocks base method.
+func (m *MockStore) PutMeta(arg0 context.Context, arg1 string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutMeta", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutMeta indicates an expected call of PutMeta.
+func (mr *MockStoreMockRecorder) PutMeta(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutMeta", reflect.TypeOf((*MockStore)(nil).PutMeta), arg0, arg1, arg2)
+}
+
 // Start mocks base method.
 func (m *MockStore) Start() error {
 	m.ctrl.T.Helper()
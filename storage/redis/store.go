@@ -66,6 +66,37 @@ func (s *Store) GetBytes(ctx context.Context, store string, key []byte) ([]byte,
 	return rawBytes, nil
 }
 
+// GetBytesBatch reads the given keys with a single MGET.
+func (s *Store) GetBytesBatch(ctx context.Context, store string, keys [][]byte) ([][]byte, error) {
+	if len(keys) == 0 {
+		return [][]byte{}, nil
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = fmt.Sprintf("%s_%s", store, hex.EncodeToString(key))
+	}
+
+	vals, err := s.rdb.MGet(ctx, redisKeys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]byte, len(vals))
+	for i, val := range vals {
+		encoded, ok := val.(string)
+		if !ok {
+			return nil, storage.ErrNotFound
+		}
+		rawBytes, err := hex.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = rawBytes
+	}
+	return values, nil
+}
+
 func (s *Store) SetBytes(ctx context.Context, store string, key []byte, value []byte) error {
 	err := s.rdb.Set(ctx, fmt.Sprintf("%s_%s", store, hex.EncodeToString(key)), hex.EncodeToString(value), 0).Err()
 	if err != nil {
@@ -121,4 +152,19 @@ func (s *Store) GetBytesAtVersion(ctx context.Context, store string, key []byte,
 	return rawBytes, nil
 }
 
+// SetBlockHeight sets the height of the latest block. Moving it back replaces the
+// blocks above it, so their cached event filters are discarded.
+func (s *Store) SetBlockHeight(height uint64) error {
+	if height < s.CurrentHeight {
+		s.ResetEventFilters()
+	}
+	return s.DefaultStore.SetBlockHeight(height)
+}
+
+// OpenHandles returns the number of open connections to the Redis server.
+func (s *Store) OpenHandles() int {
+	return int(s.rdb.PoolStats().TotalConns)
+}
+
 var _ storage.Store = &Store{}
+var _ storage.BatchDataGetter = &Store{}
@@ -0,0 +1,80 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory opens a store from a data source name, whose format is defined by
+// the storage provider.
+type Factory func(dsn string) (Store, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a storage provider available by name, so it can be selected
+// with the storage provider option of the emulator server.
+//
+// Register is meant to be called from the init function of the package
+// implementing the provider. It panics if the factory is nil or if a provider
+// with the same name is already registered.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("storage: Register factory is nil")
+	}
+	if _, exists := factories[name]; exists {
+		panic(fmt.Sprintf("storage: Register called twice for provider %s", name))
+	}
+	factories[name] = factory
+}
+
+// Providers returns the sorted names of the registered storage providers.
+func Providers() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Open opens a store with the registered storage provider of the given name.
+func Open(name string, dsn string) (Store, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, &UnknownProviderError{Name: name, Registered: Providers()}
+	}
+
+	return factory(dsn)
+}
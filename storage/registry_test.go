@@ -0,0 +1,74 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+)
+
+func TestRegister(t *testing.T) {
+
+	t.Parallel()
+
+	var opened string
+	storage.Register("registry-test", func(dsn string) (storage.Store, error) {
+		opened = dsn
+		return sqlite.New(sqlite.InMemory)
+	})
+
+	assert.Contains(t, storage.Providers(), "registry-test")
+
+	t.Run("open", func(t *testing.T) {
+		store, err := storage.Open("registry-test", "some,dsn")
+		require.NoError(t, err)
+		require.NotNil(t, store)
+
+		assert.Equal(t, "some,dsn", opened)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := storage.Open("registry-test-unknown", "")
+
+		var unknownErr *storage.UnknownProviderError
+		require.True(t, errors.As(err, &unknownErr))
+		assert.Equal(t, "registry-test-unknown", unknownErr.Name)
+		assert.Contains(t, unknownErr.Registered, "registry-test")
+	})
+
+	t.Run("registered twice", func(t *testing.T) {
+		assert.Panics(t, func() {
+			storage.Register("registry-test", func(string) (storage.Store, error) {
+				return nil, nil
+			})
+		})
+	})
+
+	t.Run("nil factory", func(t *testing.T) {
+		assert.Panics(t, func() {
+			storage.Register("registry-test-nil", nil)
+		})
+	})
+}
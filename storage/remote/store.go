@@ -173,6 +173,19 @@ func (s *Store) BlockByHeight(ctx context.Context, height uint64) (*flowgo.Block
 	}, nil
 }
 
+// GetBlocks reads the blocks one by one, as the blocks missing locally are read from the archive node.
+func (s *Store) GetBlocks(ctx context.Context, heights []uint64) ([]*flowgo.Block, error) {
+	blocks := make([]*flowgo.Block, len(heights))
+	for i, height := range heights {
+		block, err := s.BlockByHeight(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		blocks[i] = block
+	}
+	return blocks, nil
+}
+
 func (s *Store) LedgerByHeight(
 	ctx context.Context,
 	blockHeight uint64,
@@ -230,6 +243,13 @@ func (s *Store) LedgerByHeight(
 	}), nil
 }
 
+// LedgerDeltaByHeight always fails, as registers read from the archive node
+// are cached in the local ledger alongside the registers blocks wrote, and
+// the remaining state of the forked network is not stored locally.
+func (s *Store) LedgerDeltaByHeight(_ context.Context, _ uint64) (*snapshot.ExecutionSnapshot, error) {
+	return nil, fmt.Errorf("the state of a forked network cannot be exported")
+}
+
 func (s *Store) Stop() {
 	_ = s.grpcConn.Close()
 }
@@ -0,0 +1,264 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/migration"
+)
+
+// SchemaVersion is the version of the database layout written by this emulator.
+const SchemaVersion uint64 = 3
+
+// migrations returns the migrations of a database, run in the given
+// transaction. The latest version they migrate to must be SchemaVersion.
+func migrations(tx *sql.Tx) migration.Migrations {
+	return migration.Migrations{
+		{
+			// Databases created before schema versioning already use the version 1
+			// layout, they only lack the schemaVersion table.
+			Description: "add schema version",
+			Migrate: func(context.Context, uint64, uint64) error {
+				return nil
+			},
+		},
+		{
+			// Version 2 stores the events of a block in the columnar layout. Older
+			// emulators would misread it, so the events they stored are re-encoded.
+			Description: "columnar events",
+			Migrate: func(context.Context, uint64, uint64) error {
+				return migrateColumnarEvents(tx)
+			},
+		},
+		{
+			// Version 3 persists the event type filter of each block next to its
+			// events. Blocks committed before have none and are filtered by decoding.
+			Description: "event type filters",
+			Migrate: func(context.Context, uint64, uint64) error {
+				_, err := tx.Exec("CREATE TABLE IF NOT EXISTS eventTypeFilters(key TEXT, value TEXT, version INTEGER, height INTEGER, UNIQUE(key,version,height))")
+				return err
+			},
+		},
+	}
+}
+
+// migrateColumnarEvents re-encodes the events stored as an array of events
+// in the columnar layout.
+func migrateColumnarEvents(tx *sql.Tx) error {
+	exists, err := hasTable(tx, "events")
+	if err != nil || !exists {
+		return err
+	}
+
+	rows, err := tx.Query("SELECT rowid, value FROM events")
+	if err != nil {
+		return err
+	}
+
+	updates := make(map[int64]string)
+	for rows.Next() {
+		var rowID int64
+		var value string
+		err = rows.Scan(&rowID, &value)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		rawBytes, err := hex.DecodeString(value)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		columnar, converted, err := storage.ColumnarEvents(rawBytes)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to re-encode events: %w", err)
+		}
+		if converted {
+			updates[rowID] = hex.EncodeToString(columnar)
+		}
+	}
+	err = rows.Close()
+	if err != nil {
+		return err
+	}
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+
+	for rowID, value := range updates {
+		_, err = tx.Exec("UPDATE events SET value = ? WHERE rowid = ?", value, rowID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrate brings the schema of the given database up to SchemaVersion and
+// returns the version it was at before.
+//
+// A database written by a newer emulator is left untouched and an
+// UnsupportedSchemaVersionError is returned, rather than risking misreading it.
+func migrate(db *sql.DB) (from uint64, err error) {
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	from, fresh, err := schemaVersion(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	if fresh {
+		_, err = tx.Exec(createTablesSql)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = migrations(tx).Migrate(context.Background(), from, SchemaVersion)
+	if err != nil {
+		return from, err
+	}
+
+	_, err = tx.Exec(
+		`CREATE TABLE IF NOT EXISTS schemaVersion(version INTEGER);
+		DELETE FROM schemaVersion;`,
+	)
+	if err != nil {
+		return from, err
+	}
+	_, err = tx.Exec(`INSERT INTO schemaVersion (version) VALUES (?)`, SchemaVersion)
+	if err != nil {
+		return from, err
+	}
+
+	return from, tx.Commit()
+}
+
+// schemaVersion returns the schema version of the database.
+// A database without any tables is reported as fresh, at the current version.
+func schemaVersion(tx *sql.Tx) (version uint64, fresh bool, err error) {
+	versioned, err := hasTable(tx, "schemaVersion")
+	if err != nil {
+		return 0, false, err
+	}
+	if versioned {
+		err = tx.QueryRow("SELECT version FROM schemaVersion").Scan(&version)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to read storage schema version: %w", err)
+		}
+		return version, false, nil
+	}
+
+	legacy, err := hasTable(tx, "ledger")
+	if err != nil {
+		return 0, false, err
+	}
+	if legacy {
+		return 0, false, nil
+	}
+
+	return SchemaVersion, true, nil
+}
+
+// hasTable returns whether the database has a table with the given name.
+func hasTable(tx *sql.Tx, name string) (bool, error) {
+	var count int
+	err := tx.QueryRow(
+		"SELECT count(name) FROM sqlite_schema WHERE type='table' AND name = ?",
+		name,
+	).Scan(&count)
+	return count > 0, err
+}
+
+// Migration is the outcome of migrating a single database file.
+type Migration struct {
+	Path string
+	From uint64
+	To   uint64
+}
+
+// Migrate upgrades the database at the given url, and any snapshots stored
+// next to it, to the current schema version.
+func Migrate(url string) ([]Migration, error) {
+	if url == InMemory {
+		return nil, fmt.Errorf("in-memory storage does not need to be migrated")
+	}
+
+	paths := []string{databasePath(url)}
+
+	urlInfo, err := os.Stat(url)
+	if err != nil {
+		return nil, err
+	}
+	if urlInfo.IsDir() {
+		files, err := os.ReadDir(url)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if !file.IsDir() && strings.HasPrefix(file.Name(), "snapshot_") {
+				paths = append(paths, filepath.Join(url, file.Name()))
+			}
+		}
+	}
+
+	result := make([]Migration, 0, len(paths))
+	for _, path := range paths {
+		from, err := migrateFile(path)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", path, err)
+		}
+		result = append(result, Migration{
+			Path: path,
+			From: from,
+			To:   SchemaVersion,
+		})
+	}
+
+	return result, nil
+}
+
+func migrateFile(path string) (uint64, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	return migrate(db)
+}
@@ -25,14 +25,23 @@ import (
 	"context"
 	"database/sql"
 	_ "embed"
+	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	_ "github.com/glebarez/go-sqlite"
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"golang.org/x/exp/slices"
+
 	"github.com/onflow/flow-emulator/storage"
 )
 
@@ -41,6 +50,10 @@ const InMemory = ":memory:"
 var _ storage.SnapshotProvider = &Store{}
 var _ storage.Store = &Store{}
 var _ storage.RollbackProvider = &Store{}
+var _ storage.LedgerDeltaProvider = &Store{}
+var _ storage.LedgerPruner = &Store{}
+var _ storage.Compactor = &Store{}
+var _ storage.BatchDataGetter = &Store{}
 
 //go:embed createTables.sql
 var createTablesSql string
@@ -52,37 +65,58 @@ type Store struct {
 	url           string
 	mu            sync.RWMutex
 	snapshotNames []string
+	// snapshotDBs keep the in-memory snapshots alive, by name
+	snapshotDBs map[string]*sql.DB
+	// snapshotInfos is the metadata of the in-memory snapshots, by name
+	snapshotInfos  map[string]storage.SnapshotInfo
+	loadedSnapshot string
+	durability     storage.Durability
+}
+
+// Option is a function that configures a Store instance.
+type Option func(*Store)
+
+// WithDurability sets how eagerly block commits are flushed to disk.
+//
+// DurabilitySafe syncs the database directory after every commit, DurabilityFast
+// writes ahead to a log which is not synced. It has no effect on in-memory databases.
+func WithDurability(durability storage.Durability) Option {
+	return func(s *Store) {
+		s.durability = durability
+	}
+}
+
+// WithCompression sets the compression of the events and transaction results stored.
+func WithCompression(compression storage.Compression) Option {
+	return func(s *Store) {
+		s.SetCompression(compression)
+	}
 }
 
 // New returns a new in-memory Store implementation.
-func New(url string) (store *Store, err error) {
+func New(url string, options ...Option) (store *Store, err error) {
+	store = &Store{
+		url:           url,
+		snapshotDBs:   map[string]*sql.DB{},
+		snapshotInfos: map[string]storage.SnapshotInfo{},
+	}
 
-	dbUrl := url
-	if dbUrl != InMemory {
-		urlInfo, err := os.Stat(url)
-		if err == nil && urlInfo.IsDir() {
-			dbUrl = filepath.Join(url, "emulator.sqlite")
-			if err != nil {
-				return nil, err
-			}
-		}
+	for _, opt := range options {
+		opt(store)
 	}
 
-	db, err := sql.Open("sqlite", dbUrl)
+	db, err := sql.Open("sqlite", store.dsn(databasePath(url)))
 	if err != nil {
 		return nil, err
 	}
 
-	err = initDb(db)
+	_, err = migrate(db)
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	store = &Store{
-		db:  db,
-		url: url,
-	}
-
+	store.db = db
 	store.DataSetter = store
 	store.DataGetter = store
 	store.KeyGenerator = &storage.DefaultKeyGenerator{}
@@ -90,16 +124,45 @@ func New(url string) (store *Store, err error) {
 	return store, nil
 }
 
-func initDb(db *sql.DB) error {
-	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{})
-	if err != nil {
-		return err
+// dsn appends the pragmas for the configured durability mode to an on-disk
+// database path. Pragmas are passed through the DSN so that every pooled
+// connection is configured, not just the first one.
+func (s *Store) dsn(path string) string {
+	if path == InMemory {
+		return path
 	}
-	_, err = tx.Exec(createTablesSql)
-	if err != nil {
-		return err
+
+	var pragmas string
+	switch s.durability {
+	case storage.DurabilitySafe:
+		// the default FULL mode does not sync the directory once the rollback
+		// journal is deleted, so a commit can still be rolled back by a power loss
+		pragmas = "_pragma=synchronous(EXTRA)"
+	case storage.DurabilityFast:
+		pragmas = "_pragma=journal_mode(WAL)&_pragma=synchronous(OFF)"
+	default:
+		return path
+	}
+
+	if strings.Contains(path, "?") {
+		return path + "&" + pragmas
+	}
+	return path + "?" + pragmas
+}
+
+// databasePath returns the database file for the given url, which is either
+// a database file or a directory holding the database and its snapshots.
+func databasePath(url string) string {
+	if url == InMemory {
+		return url
+	}
+
+	urlInfo, err := os.Stat(url)
+	if err == nil && urlInfo.IsDir() {
+		return filepath.Join(url, "emulator.sqlite")
 	}
-	return tx.Commit()
+
+	return url
 }
 
 func (s *Store) RollbackToBlockHeight(height uint64) error {
@@ -112,7 +175,7 @@ func (s *Store) RollbackToBlockHeight(height uint64) error {
 		return err
 	}
 
-	for _, table := range []string{"ledger", "blocks", "blockIndex", "events", "transactions", "collections", "transactionResults"} {
+	for _, table := range []string{"ledger", "blocks", "blockIndex", "events", "eventTypeFilters", "transactions", "collections", "transactionResults"} {
 		_, err = tx.Exec(fmt.Sprintf(`DELETE from %s where height>%d`, table, height))
 		if err != nil {
 			return err
@@ -123,6 +186,7 @@ func (s *Store) RollbackToBlockHeight(height uint64) error {
 	if err != nil {
 		return err
 	}
+	s.ResetEventFilters()
 
 	return s.DefaultStore.SetBlockHeight(height)
 }
@@ -186,18 +250,30 @@ func (s *Store) LoadSnapshot(name string) error {
 		}
 	}
 
+	if s.url != InMemory {
+		dbfile = s.dsn(dbfile)
+	}
+
 	db, err := sql.Open("sqlite", dbfile)
 	if err != nil {
 		return err
 	}
 
+	_, err = migrate(db)
+	if err != nil {
+		db.Close()
+		return err
+	}
+
 	s.db.Close()
 	s.db = db
+	s.loadedSnapshot = name
+	s.ResetEventFilters()
 
 	return nil
 }
 
-func (s *Store) CreateSnapshot(name string) error {
+func (s *Store) CreateSnapshot(name string, description string) error {
 	if !s.SupportSnapshotsWithCurrentConfig() {
 		return fmt.Errorf("snapshot is not supported with current configuration")
 	}
@@ -218,6 +294,7 @@ func (s *Store) CreateSnapshot(name string) error {
 			return err
 		}
 
+		s.snapshotDBs[name] = db
 	} else {
 		dbfile = filepath.Join(s.url, fmt.Sprintf("snapshot_%s", name))
 	}
@@ -226,8 +303,140 @@ func (s *Store) CreateSnapshot(name string) error {
 	if err != nil {
 		return err
 	}
-	s.snapshotNames = append(s.snapshotNames, name)
-	return nil
+	if s.url == InMemory {
+		s.snapshotNames = append(s.snapshotNames, name)
+	}
+
+	infos, err := s.readSnapshotInfos()
+	if err != nil {
+		return err
+	}
+	infos[name] = storage.SnapshotInfo{
+		Name:        name,
+		CreatedAt:   time.Now(),
+		BlockHeight: s.CurrentHeight,
+		Description: description,
+	}
+	return s.writeSnapshotInfos(infos)
+}
+
+func (s *Store) DeleteSnapshot(name string) error {
+	if !s.SupportSnapshotsWithCurrentConfig() {
+		return fmt.Errorf("snapshot is not supported with current configuration")
+	}
+
+	if name == s.loadedSnapshot {
+		return fmt.Errorf("failed to delete snapshot %s: %w", name, storage.ErrSnapshotLoaded)
+	}
+
+	if s.url == InMemory {
+		db, ok := s.snapshotDBs[name]
+		if !ok {
+			return fmt.Errorf("snapshot %s does not exist", name)
+		}
+
+		// the in-memory database is freed once its last connection is closed
+		err := db.Close()
+		if err != nil {
+			return err
+		}
+		delete(s.snapshotDBs, name)
+
+		if index := slices.Index(s.snapshotNames, name); index >= 0 {
+			s.snapshotNames = slices.Delete(s.snapshotNames, index, index+1)
+		}
+	} else {
+		err := os.Remove(filepath.Join(s.url, fmt.Sprintf("snapshot_%s", name)))
+		if os.IsNotExist(err) {
+			return fmt.Errorf("snapshot %s does not exist", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	infos, err := s.readSnapshotInfos()
+	if err != nil {
+		return err
+	}
+	delete(infos, name)
+	return s.writeSnapshotInfos(infos)
+}
+
+// SnapshotInfos returns the metadata of the snapshots, oldest first.
+//
+// Snapshots created before metadata was recorded only have a name and,
+// if stored on disk, the modification time of their database file.
+func (s *Store) SnapshotInfos() ([]storage.SnapshotInfo, error) {
+	names, err := s.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := s.readSnapshotInfos()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]storage.SnapshotInfo, 0, len(names))
+	for _, name := range names {
+		info, ok := infos[name]
+		if !ok {
+			info = storage.SnapshotInfo{Name: name}
+			fileInfo, err := os.Stat(filepath.Join(s.url, fmt.Sprintf("snapshot_%s", name)))
+			if err == nil {
+				info.CreatedAt = fileInfo.ModTime()
+			}
+		}
+		result = append(result, info)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// snapshotInfosFile holds the metadata of the snapshots stored on disk,
+// next to the snapshot databases.
+const snapshotInfosFile = "snapshots.json"
+
+func (s *Store) readSnapshotInfos() (map[string]storage.SnapshotInfo, error) {
+	if s.url == InMemory {
+		return s.snapshotInfos, nil
+	}
+
+	infos := map[string]storage.SnapshotInfo{}
+
+	data, err := os.ReadFile(filepath.Join(s.url, snapshotInfosFile))
+	if os.IsNotExist(err) {
+		return infos, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &infos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", snapshotInfosFile, err)
+	}
+
+	return infos, nil
+}
+
+func (s *Store) writeSnapshotInfos(infos map[string]storage.SnapshotInfo) error {
+	if s.url == InMemory {
+		s.snapshotInfos = infos
+		return nil
+	}
+
+	data, err := json.MarshalIndent(infos, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(s.url, snapshotInfosFile), data, 0644)
 }
 
 func (s *Store) SupportSnapshotsWithCurrentConfig() bool {
@@ -305,7 +514,283 @@ func (s *Store) GetBytesAtVersion(ctx context.Context, store string, key []byte,
 	}
 	return nil, storage.ErrNotFound
 }
+
+// batchSize is the number of keys read with a single query, below the limit of
+// the number of parameters of a query.
+const batchSize = 500
+
+// GetBytesBatch reads the latest versions of the given keys, batchSize keys per query.
+func (s *Store) GetBytesBatch(ctx context.Context, store string, keys [][]byte) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[string][]byte, len(keys))
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		args := make([]any, 0, end-start)
+		for _, key := range keys[start:end] {
+			args = append(args, hex.EncodeToString(key))
+		}
+
+		// rows are ordered by version, so the latest version of a key is kept
+		rows, err := s.db.QueryContext(
+			ctx,
+			fmt.Sprintf(
+				"SELECT key, value FROM %s WHERE key IN (?%s) ORDER BY version",
+				store,
+				strings.Repeat(", ?", len(args)-1),
+			),
+			args...,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var key, value string
+			if err := rows.Scan(&key, &value); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			rawBytes, err := hex.DecodeString(value)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			values[key] = rawBytes
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, ok := values[hex.EncodeToString(key)]
+		if !ok {
+			return nil, storage.ErrNotFound
+		}
+		result[i] = value
+	}
+	return result, nil
+}
+
+// LedgerDeltaByHeight returns the registers written by the block at the given height.
+func (s *Store) LedgerDeltaByHeight(ctx context.Context, blockHeight uint64) (*snapshot.ExecutionSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		fmt.Sprintf("SELECT key, value FROM %s WHERE version = ?", storage.LedgerStoreName),
+		blockHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delta := &snapshot.ExecutionSnapshot{
+		WriteSet: make(map[flowgo.RegisterID]flowgo.RegisterValue),
+	}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+
+		rawKey, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, err
+		}
+		registerID, err := parseRegisterID(string(rawKey))
+		if err != nil {
+			return nil, err
+		}
+
+		rawValue, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+		delta.WriteSet[registerID] = rawValue
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return delta, nil
+}
+
+// pruneBatchSize is the number of registers whose versions are pruned at once.
+const pruneBatchSize = 1000
+
+// PruneLedger removes the register versions older than the latest version at or below
+// the given height, which are not needed to read the ledger at the height or above.
+// The latest versions at or below the pinned heights are kept, to read the ledger at them.
+//
+// The registers are pruned in batches by key, and the store is not locked between
+// batches, so pruning a large ledger does not hold up reads and commits.
+func (s *Store) PruneLedger(ctx context.Context, height uint64, pinned []uint64) error {
+	// the versions between two kept heights are only needed to read the ledger at the upper one
+	kept := make([]uint64, 0, len(pinned)+1)
+	for _, pinnedHeight := range pinned {
+		if pinnedHeight < height {
+			kept = append(kept, pinnedHeight)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i] < kept[j] })
+	kept = append(kept, height)
+
+	after := ""
+	for {
+		last, err := s.pruneLedgerBatch(ctx, kept, after)
+		if err != nil {
+			return err
+		}
+		if last == "" {
+			return nil
+		}
+		after = last
+	}
+}
+
+// pruneLedgerBatch prunes the versions of the next registers whose key follows the
+// given key, keeping the ledger at the given ascending heights, and returns the last
+// key pruned, or an empty key if none is left.
+func (s *Store) pruneLedgerBatch(ctx context.Context, kept []uint64, after string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var last sql.NullString
+	err := s.db.QueryRowContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT MAX(key) FROM (SELECT DISTINCT key FROM %s WHERE key > ? ORDER BY key LIMIT ?)`,
+			storage.LedgerStoreName,
+		),
+		after,
+		pruneBatchSize,
+	).Scan(&last)
+	if err != nil {
+		return "", err
+	}
+	if !last.Valid {
+		return "", nil
+	}
+
+	lower := int64(-1)
+	for _, height := range kept {
+		_, err = s.db.ExecContext(
+			ctx,
+			fmt.Sprintf(
+				`DELETE FROM %[1]s WHERE key > ? AND key <= ? AND version > ? AND version < ? AND EXISTS (
+					SELECT 1 FROM %[1]s AS newer
+					WHERE newer.key = %[1]s.key AND newer.version > %[1]s.version AND newer.version <= ?
+				)`,
+				storage.LedgerStoreName,
+			),
+			after,
+			last.String,
+			lower,
+			height,
+			height,
+		)
+		if err != nil {
+			return "", err
+		}
+		lower = int64(height)
+	}
+
+	return last.String, nil
+}
+
+// parseRegisterID reverses flowgo.RegisterID.String, which is used to key the ledger.
+func parseRegisterID(formatted string) (flowgo.RegisterID, error) {
+	owner, key, ok := strings.Cut(formatted, "/")
+	if !ok || len(key) == 0 {
+		return flowgo.RegisterID{}, fmt.Errorf("invalid register ID %q", formatted)
+	}
+
+	rawOwner, err := hex.DecodeString(owner)
+	if err != nil {
+		return flowgo.RegisterID{}, fmt.Errorf("invalid register owner in %q: %w", formatted, err)
+	}
+
+	switch key[0] {
+	case '#':
+		rawKey, err := hex.DecodeString(key[1:])
+		if err != nil {
+			return flowgo.RegisterID{}, fmt.Errorf("invalid register key in %q: %w", formatted, err)
+		}
+		return flowgo.RegisterID{Owner: string(rawOwner), Key: string(rawKey)}, nil
+	case '$':
+		index, err := strconv.ParseUint(key[1:], 10, 64)
+		if err != nil {
+			return flowgo.RegisterID{}, fmt.Errorf("invalid slab index in %q: %w", formatted, err)
+		}
+		slabKey := make([]byte, 9)
+		slabKey[0] = '$'
+		binary.BigEndian.PutUint64(slabKey[1:], index)
+		return flowgo.RegisterID{Owner: string(rawOwner), Key: string(slabKey)}, nil
+	default:
+		return flowgo.RegisterID{}, fmt.Errorf("invalid register key in %q", formatted)
+	}
+}
+
+// Compact rebuilds the database with VACUUM, and returns the number of bytes reclaimed.
+func (s *Store) Compact(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, err := s.size(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = s.db.ExecContext(ctx, "VACUUM")
+	if err != nil {
+		return 0, err
+	}
+
+	after, err := s.size(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return before - after, nil
+}
+
+// size returns the size of the database in bytes.
+func (s *Store) size(ctx context.Context) (int64, error) {
+	var size int64
+	err := s.db.QueryRowContext(
+		ctx,
+		"SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()",
+	).Scan(&size)
+	return size, err
+}
+
+// Backup writes a consistent copy of the database to a new file at the given path.
+func (s *Store) Backup(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec("VACUUM main INTO ?", path)
+	return err
+}
+
 func (s *Store) Close() error {
 	s.db.Close()
 	return nil
 }
+
+// OpenHandles returns the number of open database connections.
+func (s *Store) OpenHandles() int {
+	return s.db.Stats().OpenConnections
+}
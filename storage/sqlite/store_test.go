@@ -0,0 +1,54 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+func TestDSN(t *testing.T) {
+
+	t.Parallel()
+
+	for _, test := range []struct {
+		path       string
+		durability storage.Durability
+		dsn        string
+	}{
+		{"emulator.sqlite", storage.DurabilityDefault, "emulator.sqlite"},
+		{"emulator.sqlite", storage.DurabilitySafe, "emulator.sqlite?_pragma=synchronous(EXTRA)"},
+		{
+			"emulator.sqlite",
+			storage.DurabilityFast,
+			"emulator.sqlite?_pragma=journal_mode(WAL)&_pragma=synchronous(OFF)",
+		},
+		{
+			"file:emulator.sqlite?_txlock=immediate",
+			storage.DurabilitySafe,
+			"file:emulator.sqlite?_txlock=immediate&_pragma=synchronous(EXTRA)",
+		},
+		{InMemory, storage.DurabilityFast, InMemory},
+	} {
+		store := &Store{durability: test.durability}
+		assert.Equal(t, test.dsn, store.dsn(test.path))
+	}
+}
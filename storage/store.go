@@ -24,6 +24,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/onflow/flow-go/fvm/storage/snapshot"
 	flowgo "github.com/onflow/flow-go/model/flow"
@@ -40,7 +44,11 @@ const (
 	transactionStoreName       = "transactions"
 	transactionResultStoreName = "transactionResults"
 	eventStoreName             = "events"
+	eventTypeFilterStoreName   = "eventTypeFilters"
 	LedgerStoreName            = "ledger"
+
+	coverageReportKey = "coverage_report"
+	metaKeyPrefix     = "meta_"
 )
 
 // Store defines the storage layer for persistent chain state.
@@ -72,16 +80,12 @@ type Store interface {
 	// for finalized blocks.
 	BlockByHeight(ctx context.Context, height uint64) (*flowgo.Block, error)
 
+	// GetBlocks returns the blocks at the given heights, in the same order.
+	// It returns ErrNotFound if any of the blocks is not available.
+	GetBlocks(ctx context.Context, heights []uint64) ([]*flowgo.Block, error)
+
 	// CommitBlock atomically saves the execution results for a block.
-	CommitBlock(
-		ctx context.Context,
-		block flowgo.Block,
-		collections []*flowgo.LightCollection,
-		transactions map[flowgo.Identifier]*flowgo.TransactionBody,
-		transactionResults map[flowgo.Identifier]*types.StorableTransactionResult,
-		executionSnapshot *snapshot.ExecutionSnapshot,
-		events []flowgo.Event,
-	) error
+	CommitBlock(ctx context.Context, commit BlockCommit) error
 
 	// CollectionByID gets the collection (transaction IDs only) with the given ID.
 	CollectionByID(ctx context.Context, collectionID flowgo.Identifier) (flowgo.LightCollection, error)
@@ -100,13 +104,64 @@ type Store interface {
 	) (snapshot.StorageSnapshot, error)
 
 	// EventsByHeight returns the events in the block at the given height, optionally filtered by type.
+	//
+	// Events are returned in canonical order: by transaction index, then by
+	// event index within the transaction.
 	EventsByHeight(ctx context.Context, blockHeight uint64, eventType string) ([]flowgo.Event, error)
+
+	// GetMeta returns the metadata value stored with the given key, or
+	// ErrNotFound if none was stored.
+	//
+	// Metadata is persisted by the emulator about itself, such as the
+	// configuration the store was created with.
+	GetMeta(ctx context.Context, key string) ([]byte, error)
+
+	// PutMeta stores a metadata value with the given key.
+	PutMeta(ctx context.Context, key string, value []byte) error
+}
+
+// A BlockCommit holds a block and the results of its execution, which are
+// committed to storage together.
+type BlockCommit struct {
+	Block              flowgo.Block
+	Collections        []*flowgo.LightCollection
+	Transactions       map[flowgo.Identifier]*flowgo.TransactionBody
+	TransactionResults map[flowgo.Identifier]*types.StorableTransactionResult
+	ExecutionSnapshot  *snapshot.ExecutionSnapshot
+	Events             []flowgo.Event
+}
+
+// Validate checks that every transaction of the commit has a result.
+func (c BlockCommit) Validate() error {
+	if len(c.Transactions) != len(c.TransactionResults) {
+		return fmt.Errorf(
+			"transactions count (%d) does not match result count (%d)",
+			len(c.Transactions),
+			len(c.TransactionResults),
+		)
+	}
+	return nil
+}
+
+// SnapshotInfo describes a named snapshot of the emulator state.
+type SnapshotInfo struct {
+	Name string `json:"name"`
+	// CreatedAt is the time the snapshot was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// BlockHeight is the height of the latest block in the snapshot.
+	BlockHeight uint64 `json:"blockHeight"`
+	// Description is an optional note about what the snapshot contains.
+	Description string `json:"description,omitempty"`
 }
 
 type SnapshotProvider interface {
 	Snapshots() ([]string, error)
-	CreateSnapshot(snapshotName string) error
+	// SnapshotInfos returns the metadata of the snapshots, oldest first.
+	SnapshotInfos() ([]SnapshotInfo, error)
+	CreateSnapshot(snapshotName string, description string) error
 	LoadSnapshot(snapshotName string) error
+	// DeleteSnapshot removes a snapshot. The loaded snapshot can not be deleted.
+	DeleteSnapshot(snapshotName string) error
 	SupportSnapshotsWithCurrentConfig() bool
 }
 
@@ -114,6 +169,51 @@ type RollbackProvider interface {
 	RollbackToBlockHeight(height uint64) error
 }
 
+// HandleProvider is implemented by stores which hold connections or file
+// handles to an underlying database.
+type HandleProvider interface {
+	OpenHandles() int
+}
+
+// LedgerDeltaProvider is implemented by stores which can list the registers
+// written by the block at a given height.
+type LedgerDeltaProvider interface {
+	LedgerDeltaByHeight(ctx context.Context, blockHeight uint64) (*snapshot.ExecutionSnapshot, error)
+}
+
+// LedgerPruner is implemented by stores which can garbage-collect the register versions
+// only needed to read the ledger below a given height.
+type LedgerPruner interface {
+	// PruneLedger removes the register versions which are not needed to read the ledger
+	// at the given height or above, or at the pinned heights. The ledger at the other
+	// heights below the given height is no longer consistent.
+	PruneLedger(ctx context.Context, height uint64, pinned []uint64) error
+}
+
+// Compactor is implemented by stores which can reclaim the disk space of deleted data,
+// e.g. of pruned register versions.
+type Compactor interface {
+	// Compact rebuilds the store, and returns the number of bytes reclaimed.
+	Compact(ctx context.Context) (int64, error)
+}
+
+// Checkpointer is implemented by stores which can save their state to the
+// checkpoint they were bootstrapped from.
+type Checkpointer interface {
+	// PrepareCheckpoint copies the current state, which must not change while it
+	// is copied. The returned function saves the copy to the checkpoint, and can
+	// be called once the state changes again.
+	PrepareCheckpoint(ctx context.Context) (func(ctx context.Context) error, error)
+}
+
+// CoverageReportStore is implemented by stores which can persist the Cadence
+// code coverage report, so that coverage accumulates across restarts.
+type CoverageReportStore interface {
+	// CoverageReport returns the encoded coverage report, or ErrNotFound if none was stored.
+	CoverageReport(ctx context.Context) ([]byte, error)
+	SetCoverageReport(ctx context.Context, report []byte) error
+}
+
 type KeyGenerator interface {
 	Storage(key string) string
 	LatestBlock() []byte
@@ -126,6 +226,14 @@ type DataGetter interface {
 	GetBytesAtVersion(ctx context.Context, store string, key []byte, version uint64) ([]byte, error)
 }
 
+// BatchDataGetter is implemented by data getters which read several keys with a
+// single query.
+type BatchDataGetter interface {
+	// GetBytesBatch returns the values of the given keys, in the same order.
+	// It returns ErrNotFound if any of the keys is not set.
+	GetBytesBatch(ctx context.Context, store string, keys [][]byte) ([][]byte, error)
+}
+
 type DataSetter interface {
 	SetBytes(ctx context.Context, store string, key []byte, value []byte) error
 	SetBytesWithVersion(ctx context.Context, store string, key []byte, value []byte, version uint64) error
@@ -155,6 +263,38 @@ type DefaultStore struct {
 	DataSetter
 	DataGetter
 	CurrentHeight uint64
+	// compression is the Compression of the events and transaction results stored.
+	// It is atomic, as it may be changed while blocks are being committed.
+	compression atomic.Int32
+
+	filtersOnce  sync.Once
+	eventFilters *eventTypeFilters
+}
+
+// SetCompression sets the compression of the events and transaction results stored from now on.
+// It is safe to call while blocks are being committed.
+func (s *DefaultStore) SetCompression(compression Compression) {
+	s.compression.Store(int32(compression))
+}
+
+// Compression returns the compression of the events and transaction results stored.
+func (s *DefaultStore) Compression() Compression {
+	return Compression(s.compression.Load())
+}
+
+// typeFilters returns the event type filters of the blocks, creating them on first use.
+func (s *DefaultStore) typeFilters() *eventTypeFilters {
+	s.filtersOnce.Do(func() {
+		s.eventFilters = newEventTypeFilters()
+	})
+	return s.eventFilters
+}
+
+// ResetEventFilters discards the in-memory event type filters of the blocks. Stores
+// must call it when the events they hold are replaced without InsertEvents,
+// e.g. when loading a snapshot.
+func (s *DefaultStore) ResetEventFilters() {
+	s.typeFilters().purge()
 }
 
 func (s *DefaultStore) SetBlockHeight(height uint64) error {
@@ -168,6 +308,22 @@ func (s *DefaultStore) Start() error {
 
 func (s *DefaultStore) Stop() {}
 
+func (s *DefaultStore) CoverageReport(ctx context.Context) ([]byte, error) {
+	return s.DataGetter.GetBytes(ctx, s.KeyGenerator.Storage(globalStoreName), []byte(coverageReportKey))
+}
+
+func (s *DefaultStore) SetCoverageReport(ctx context.Context, report []byte) error {
+	return s.DataSetter.SetBytes(ctx, s.KeyGenerator.Storage(globalStoreName), []byte(coverageReportKey), report)
+}
+
+func (s *DefaultStore) GetMeta(ctx context.Context, key string) ([]byte, error) {
+	return s.DataGetter.GetBytes(ctx, s.KeyGenerator.Storage(globalStoreName), []byte(metaKeyPrefix+key))
+}
+
+func (s *DefaultStore) PutMeta(ctx context.Context, key string, value []byte) error {
+	return s.DataSetter.SetBytes(ctx, s.KeyGenerator.Storage(globalStoreName), []byte(metaKeyPrefix+key), value)
+}
+
 func (s *DefaultStore) LatestBlockHeight(ctx context.Context) (latestBlockHeight uint64, err error) {
 	latestBlockHeightEnc, err := s.DataGetter.GetBytes(ctx, s.KeyGenerator.Storage(globalStoreName), s.KeyGenerator.LatestBlock())
 	if err != nil {
@@ -227,6 +383,44 @@ func (s *DefaultStore) BlockByHeight(ctx context.Context, blockHeight uint64) (b
 	return
 }
 
+// GetBlocks reads the blocks with a single query if the data getter reads keys in
+// batches, and one by one otherwise.
+func (s *DefaultStore) GetBlocks(ctx context.Context, heights []uint64) ([]*flowgo.Block, error) {
+	blocks := make([]*flowgo.Block, len(heights))
+
+	batchGetter, ok := s.DataGetter.(BatchDataGetter)
+	if !ok {
+		for i, height := range heights {
+			block, err := s.BlockByHeight(ctx, height)
+			if err != nil {
+				return nil, err
+			}
+			blocks[i] = block
+		}
+		return blocks, nil
+	}
+
+	keys := make([][]byte, len(heights))
+	for i, height := range heights {
+		keys[i] = s.KeyGenerator.BlockHeight(height)
+	}
+
+	encBlocks, err := batchGetter.GetBytesBatch(ctx, s.KeyGenerator.Storage(blockStoreName), keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, encBlock := range encBlocks {
+		block := &flowgo.Block{}
+		err = decodeBlock(block, encBlock)
+		if err != nil {
+			return nil, err
+		}
+		blocks[i] = block
+	}
+	return blocks, nil
+}
+
 func (s *DefaultStore) BlockByID(ctx context.Context, blockID flowgo.Identifier) (block *flowgo.Block, err error) {
 	blockHeightEnc, err := s.DataGetter.GetBytes(ctx, s.KeyGenerator.Storage(blockIndexStoreName), s.KeyGenerator.Identifier(blockID))
 	if err != nil {
@@ -279,6 +473,10 @@ func (s *DefaultStore) TransactionResultByID(ctx context.Context, txID flowgo.Id
 	if err != nil {
 		return
 	}
+	encResult, err = decompress(encResult)
+	if err != nil {
+		return
+	}
 	err = decodeTransactionResult(&result, encResult)
 	return
 }
@@ -288,10 +486,20 @@ func (s *DefaultStore) InsertTransactionResult(ctx context.Context, txID flowgo.
 	if err != nil {
 		return err
 	}
-	return s.DataSetter.SetBytes(ctx, s.KeyGenerator.Storage(transactionResultStoreName), s.KeyGenerator.Identifier(txID), encResult)
+	return s.DataSetter.SetBytes(ctx, s.KeyGenerator.Storage(transactionResultStoreName), s.KeyGenerator.Identifier(txID), s.Compression().compress(encResult))
 }
 
 func (s *DefaultStore) EventsByHeight(ctx context.Context, blockHeight uint64, eventType string) (events []flowgo.Event, err error) {
+	if eventType != "" {
+		filter, err := s.eventTypeFilter(ctx, blockHeight)
+		if err != nil {
+			return nil, err
+		}
+		if filter != nil && !filter.mayContain(flowgo.EventType(eventType)) {
+			return nil, nil
+		}
+	}
+
 	eventsEnc, err := s.DataGetter.GetBytes(ctx, s.KeyGenerator.Storage(eventStoreName), s.KeyGenerator.BlockHeight(blockHeight))
 	if err != nil {
 		if err == ErrNotFound {
@@ -299,23 +507,93 @@ func (s *DefaultStore) EventsByHeight(ctx context.Context, blockHeight uint64, e
 		}
 		return
 	}
+	eventsEnc, err = decompress(eventsEnc)
+	if err != nil {
+		return
+	}
+
+	if !isBlockEvents(eventsEnc) {
+		return s.legacyEventsByHeight(blockHeight, eventType, eventsEnc)
+	}
+
+	var columns blockEvents
+	err = decodeBlockEvents(&columns, eventsEnc)
+	if err != nil {
+		return
+	}
+	s.typeFilters().set(blockHeight, newEventTypeFilter(columns.Types))
+
+	return columns.events(flowgo.EventType(eventType)), nil
+}
+
+// eventTypeFilter returns the event type filter of a block, persisted when its events
+// were inserted, or nil if it has none, e.g. because it was committed by an older version.
+func (s *DefaultStore) eventTypeFilter(ctx context.Context, blockHeight uint64) (*eventTypeFilter, error) {
+	filters := s.typeFilters()
+	if filter, ok := filters.get(blockHeight); ok {
+		return filter, nil
+	}
+
+	encFilter, err := s.DataGetter.GetBytes(ctx, s.KeyGenerator.Storage(eventTypeFilterStoreName), s.KeyGenerator.BlockHeight(blockHeight))
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	filter, err := decodeEventTypeFilter(encFilter)
+	if err != nil {
+		return nil, err
+	}
+	filters.set(blockHeight, filter)
+
+	return filter, nil
+}
+
+// legacyEventsByHeight decodes the events of a block stored by older versions,
+// as an array of events.
+func (s *DefaultStore) legacyEventsByHeight(blockHeight uint64, eventType string, eventsEnc []byte) (events []flowgo.Event, err error) {
 	var blockEvents []flowgo.Event
 	err = decodeEvents(&blockEvents, eventsEnc)
 	if err != nil {
 		return
 	}
+	// events persisted by older versions may not be in canonical order
+	SortEvents(blockEvents)
+
+	filter := &eventTypeFilter{}
 	for _, event := range blockEvents {
+		filter.add(event.Type)
 		if eventType != "" && event.Type != flowgo.EventType(eventType) {
 			continue
 		}
 		events = append(events, event)
 	}
+	s.typeFilters().set(blockHeight, filter)
+
 	return
 }
 
+// SortEvents sorts the events of a block into canonical order: by transaction
+// index, then by event index within the transaction.
+func SortEvents(events []flowgo.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].TransactionIndex != events[j].TransactionIndex {
+			return events[i].TransactionIndex < events[j].TransactionIndex
+		}
+		return events[i].EventIndex < events[j].EventIndex
+	})
+}
+
 func (s *DefaultStore) InsertEvents(ctx context.Context, blockHeight uint64, events []flowgo.Event) error {
+	sorted := make([]flowgo.Event, len(events))
+	copy(sorted, events)
+	SortEvents(sorted)
+
 	//bluesign: encodes all events instead of inserting one by one
-	b, err := encodeEvents(events)
+	columns := newBlockEvents(sorted)
+	b, err := encodeBlockEvents(columns)
 	if err != nil {
 		return err
 	}
@@ -323,12 +601,23 @@ func (s *DefaultStore) InsertEvents(ctx context.Context, blockHeight uint64, eve
 	err = s.DataSetter.SetBytes(ctx,
 		s.KeyGenerator.Storage(eventStoreName),
 		s.KeyGenerator.BlockHeight(blockHeight),
-		b)
+		s.Compression().compress(b))
+
+	if err != nil {
+		return err
+	}
 
+	filter := newEventTypeFilter(columns.Types)
+	err = s.DataSetter.SetBytes(ctx,
+		s.KeyGenerator.Storage(eventTypeFilterStoreName),
+		s.KeyGenerator.BlockHeight(blockHeight),
+		encodeEventTypeFilter(filter))
 	if err != nil {
 		return err
 	}
 
+	s.typeFilters().set(blockHeight, filter)
+
 	return nil
 }
 
@@ -351,44 +640,32 @@ func (s *DefaultStore) InsertExecutionSnapshot(
 	return nil
 }
 
-func (s *DefaultStore) CommitBlock(
-	ctx context.Context,
-	block flowgo.Block,
-	collections []*flowgo.LightCollection,
-	transactions map[flowgo.Identifier]*flowgo.TransactionBody,
-	transactionResults map[flowgo.Identifier]*types.StorableTransactionResult,
-	executionSnapshot *snapshot.ExecutionSnapshot,
-	events []flowgo.Event,
-) error {
-
-	if len(transactions) != len(transactionResults) {
-		return fmt.Errorf(
-			"transactions count (%d) does not match result count (%d)",
-			len(transactions),
-			len(transactionResults),
-		)
+func (s *DefaultStore) CommitBlock(ctx context.Context, commit BlockCommit) error {
+	err := commit.Validate()
+	if err != nil {
+		return err
 	}
 
-	err := s.StoreBlock(ctx, &block)
+	err = s.StoreBlock(ctx, &commit.Block)
 	if err != nil {
 		return err
 	}
 
-	for _, col := range collections {
+	for _, col := range commit.Collections {
 		err := s.InsertCollection(ctx, *col)
 		if err != nil {
 			return err
 		}
 	}
 
-	for _, tx := range transactions {
+	for _, tx := range commit.Transactions {
 		err := s.InsertTransaction(ctx, *tx)
 		if err != nil {
 			return err
 		}
 	}
 
-	for txID, result := range transactionResults {
+	for txID, result := range commit.TransactionResults {
 		err := s.InsertTransactionResult(ctx, txID, *result)
 		if err != nil {
 			return err
@@ -397,13 +674,13 @@ func (s *DefaultStore) CommitBlock(
 
 	err = s.InsertExecutionSnapshot(
 		ctx,
-		block.Header.Height,
-		executionSnapshot)
+		commit.Block.Header.Height,
+		commit.ExecutionSnapshot)
 	if err != nil {
 		return err
 	}
 
-	err = s.InsertEvents(ctx, block.Header.Height, events)
+	err = s.InsertEvents(ctx, commit.Block.Header.Height, commit.Events)
 	if err != nil {
 		return err
 	}
@@ -19,12 +19,16 @@
 package storage_test
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/onflow/flow-go-sdk/test"
 	"github.com/onflow/flow-go/fvm/storage/snapshot"
 	"github.com/onflow/flow-go/model/flow"
@@ -35,6 +39,7 @@ import (
 	"github.com/onflow/flow-emulator/convert"
 	"github.com/onflow/flow-emulator/storage"
 	"github.com/onflow/flow-emulator/storage/sqlite"
+	"github.com/onflow/flow-emulator/types"
 	"github.com/onflow/flow-emulator/utils/unittest"
 )
 
@@ -121,6 +126,78 @@ func TestBlocks(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, *block2, block)
 	})
+
+	t.Run("GetBlocks", func(t *testing.T) {
+		blocks, err := store.GetBlocks(context.Background(), []uint64{2, 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []*flowgo.Block{block2, block1}, blocks)
+
+		_, err = store.GetBlocks(context.Background(), []uint64{1, 3})
+		assert.Equal(t, storage.ErrNotFound, err)
+	})
+}
+
+func TestCommitBlock(t *testing.T) {
+
+	t.Parallel()
+
+	store, dir := setupStore(t)
+	defer func() {
+		require.NoError(t, store.Close())
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	block := flowgo.Block{
+		Header: &flowgo.Header{
+			Height: 1,
+		},
+	}
+
+	tx := unittest.TransactionFixture()
+	txID := tx.ID()
+
+	t.Run("should require a result for every transaction", func(t *testing.T) {
+		err := store.CommitBlock(context.Background(), storage.BlockCommit{
+			Block: block,
+			Transactions: map[flowgo.Identifier]*flowgo.TransactionBody{
+				txID: &tx,
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("should store the block and its results", func(t *testing.T) {
+		event, _ := convert.SDKEventToFlow(test.EventGenerator().New())
+
+		err := store.CommitBlock(context.Background(), storage.BlockCommit{
+			Block: block,
+			Transactions: map[flowgo.Identifier]*flowgo.TransactionBody{
+				txID: &tx,
+			},
+			TransactionResults: map[flowgo.Identifier]*types.StorableTransactionResult{
+				txID: {BlockHeight: block.Header.Height},
+			},
+			ExecutionSnapshot: &snapshot.ExecutionSnapshot{},
+			Events:            []flowgo.Event{event},
+		})
+		require.NoError(t, err)
+
+		latest, err := store.LatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, block, latest)
+
+		storedTx, err := store.TransactionByID(context.Background(), txID)
+		require.NoError(t, err)
+		assert.Equal(t, txID, storedTx.ID())
+
+		result, err := store.TransactionResultByID(context.Background(), txID)
+		require.NoError(t, err)
+		assert.Equal(t, block.Header.Height, result.BlockHeight)
+
+		events, err := store.EventsByHeight(context.Background(), block.Header.Height, "")
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
 }
 
 func TestCollections(t *testing.T) {
@@ -347,9 +424,171 @@ func TestLedger(t *testing.T) {
 				}
 			}
 		})
+
+		// Pruning below block 6 keeps the latest versions at or below it
+		t.Run("should keep the versions needed after pruning", func(t *testing.T) {
+			const prunedHeight = 6
+			require.NoError(t, store.PruneLedger(context.Background(), prunedHeight, nil))
+
+			for block := prunedHeight; block <= totalBlocks; block++ {
+				gotLedger, err := store.LedgerByHeight(context.Background(), uint64(block))
+				require.NoError(t, err)
+				for i := 1; i < block; i++ {
+					val, err := gotLedger.Get(flow.NewRegisterID(owner, fmt.Sprintf("%d", i)))
+					assert.NoError(t, err)
+					assert.Equal(t, []byte{byte(i)}, val)
+				}
+			}
+
+			// block 1 wrote keys 1, 2 and 3, of which only key 1 was not written again
+			delta, err := store.LedgerDeltaByHeight(context.Background(), 1)
+			require.NoError(t, err)
+			assert.Equal(t,
+				map[flow.RegisterID]flow.RegisterValue{flow.NewRegisterID(owner, "1"): {1}},
+				delta.WriteSet,
+			)
+
+			// block 5 wrote keys 5, 6 and 7, of which keys 6 and 7 were written by block 6
+			delta, err = store.LedgerDeltaByHeight(context.Background(), 5)
+			require.NoError(t, err)
+			assert.Equal(t,
+				map[flow.RegisterID]flow.RegisterValue{flow.NewRegisterID(owner, "5"): {5}},
+				delta.WriteSet,
+			)
+
+			delta, err = store.LedgerDeltaByHeight(context.Background(), prunedHeight)
+			require.NoError(t, err)
+			assert.Len(t, delta.WriteSet, 3)
+		})
 	})
 }
 
+func TestPruneLedgerInBatches(t *testing.T) {
+
+	t.Parallel()
+
+	store, dir := setupStore(t)
+	defer func() {
+		require.NoError(t, store.Close())
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	// more registers than are pruned in a batch are written by both blocks
+	const registers = 2500
+	for height := uint64(1); height <= 2; height++ {
+		writeSet := map[flow.RegisterID]flow.RegisterValue{}
+		for i := 0; i < registers; i++ {
+			writeSet[flow.NewRegisterID("", fmt.Sprintf("%d", i))] = []byte{byte(height)}
+		}
+		err := store.InsertExecutionSnapshot(
+			context.Background(),
+			height,
+			&snapshot.ExecutionSnapshot{WriteSet: writeSet})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, store.PruneLedger(context.Background(), 2, nil))
+
+	delta, err := store.LedgerDeltaByHeight(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Empty(t, delta.WriteSet)
+
+	ledger, err := store.LedgerByHeight(context.Background(), 2)
+	require.NoError(t, err)
+	for i := 0; i < registers; i++ {
+		value, err := ledger.Get(flow.NewRegisterID("", fmt.Sprintf("%d", i)))
+		require.NoError(t, err)
+		require.Equal(t, []byte{2}, value)
+	}
+}
+
+func TestPruneLedgerKeepsPinnedHeights(t *testing.T) {
+
+	t.Parallel()
+
+	store, dir := setupStore(t)
+	defer func() {
+		require.NoError(t, store.Close())
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	// register a is written by every block, register b by blocks 1 and 4 only
+	a := flow.NewRegisterID("", "a")
+	b := flow.NewRegisterID("", "b")
+	for height := uint64(1); height <= 5; height++ {
+		writeSet := map[flow.RegisterID]flow.RegisterValue{a: {byte(height)}}
+		if height == 1 || height == 4 {
+			writeSet[b] = []byte{byte(height)}
+		}
+		err := store.InsertExecutionSnapshot(
+			context.Background(),
+			height,
+			&snapshot.ExecutionSnapshot{WriteSet: writeSet})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, store.PruneLedger(context.Background(), 5, []uint64{2}))
+
+	for height, expected := range map[uint64]map[flow.RegisterID]byte{
+		2: {a: 2, b: 1},
+		5: {a: 5, b: 4},
+	} {
+		ledger, err := store.LedgerByHeight(context.Background(), height)
+		require.NoError(t, err)
+		for registerID, value := range expected {
+			got, err := ledger.Get(registerID)
+			require.NoError(t, err)
+			assert.Equal(t, []byte{value}, got, "register %s at height %d", registerID, height)
+		}
+	}
+
+	// the versions only needed between the pinned height and the pruned height are removed
+	delta, err := store.LedgerDeltaByHeight(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Empty(t, delta.WriteSet)
+
+	delta, err = store.LedgerDeltaByHeight(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[flow.RegisterID]flow.RegisterValue{b: {1}}, delta.WriteSet)
+}
+
+func TestCompact(t *testing.T) {
+
+	t.Parallel()
+
+	store, dir := setupStore(t)
+	defer func() {
+		require.NoError(t, store.Close())
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	// every block overwrites the same registers with large values
+	const totalBlocks = 10
+	for height := uint64(1); height <= totalBlocks; height++ {
+		writeSet := map[flow.RegisterID]flow.RegisterValue{}
+		for i := 0; i < 10; i++ {
+			writeSet[flow.NewRegisterID("", fmt.Sprintf("%d", i))] = bytes.Repeat([]byte{byte(height)}, 4096)
+		}
+		err := store.InsertExecutionSnapshot(
+			context.Background(),
+			height,
+			&snapshot.ExecutionSnapshot{WriteSet: writeSet})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, store.PruneLedger(context.Background(), totalBlocks, nil))
+
+	reclaimed, err := store.Compact(context.Background())
+	require.NoError(t, err)
+	assert.Greater(t, reclaimed, int64(0))
+
+	ledger, err := store.LedgerByHeight(context.Background(), totalBlocks)
+	require.NoError(t, err)
+	value, err := ledger.Get(flow.NewRegisterID("", "0"))
+	require.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte{totalBlocks}, 4096), value)
+}
+
 func TestInsertEvents(t *testing.T) {
 
 	t.Parallel()
@@ -459,12 +698,368 @@ func TestEventsByHeight(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, eventsB, events)
 		})
+
+		t.Run("type=C, block=1", func(t *testing.T) {
+			events, err := store.EventsByHeight(context.Background(), nonEmptyBlockHeight, "C")
+			assert.NoError(t, err)
+			assert.Empty(t, events)
+		})
+
+		t.Run("type=A, block=1, filters reset", func(t *testing.T) {
+			store.ResetEventFilters()
+
+			events, err := store.EventsByHeight(context.Background(), nonEmptyBlockHeight, "A")
+			assert.NoError(t, err)
+			assert.Equal(t, eventsA, events)
+		})
+	})
+
+	t.Run("should read events stored by older versions", func(t *testing.T) {
+		const blockHeight uint64 = 5
+
+		// older versions stored the events of a block as an array of events
+		legacy, err := cbor.Marshal(allEvents)
+		require.NoError(t, err)
+
+		err = store.SetBytes(
+			context.Background(),
+			store.KeyGenerator.Storage("events"),
+			store.KeyGenerator.BlockHeight(blockHeight),
+			legacy,
+		)
+		require.NoError(t, err)
+
+		events, err := store.EventsByHeight(context.Background(), blockHeight, "")
+		require.NoError(t, err)
+		assert.Equal(t, allEvents, events)
+
+		events, err = store.EventsByHeight(context.Background(), blockHeight, "B")
+		require.NoError(t, err)
+		assert.Equal(t, eventsB, events)
+
+		events, err = store.EventsByHeight(context.Background(), blockHeight, "C")
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("should return events in canonical order", func(t *testing.T) {
+		const blockHeight uint64 = 4
+
+		shuffled := make([]flowgo.Event, len(allEvents))
+		for i := range allEvents {
+			shuffled[i] = allEvents[len(allEvents)-1-i]
+		}
+
+		err := store.InsertEvents(context.Background(), blockHeight, shuffled)
+		require.NoError(t, err)
+
+		events, err := store.EventsByHeight(context.Background(), blockHeight, "")
+		require.NoError(t, err)
+		assert.Equal(t, allEvents, events)
+
+		// the caller's slice is left untouched
+		assert.Equal(t, allEvents[len(allEvents)-1], shuffled[0])
+	})
+
+	t.Run("should filter by type without decoding events", func(t *testing.T) {
+		const blockHeight uint64 = 6
+
+		err := store.InsertEvents(context.Background(), blockHeight, allEvents)
+		require.NoError(t, err)
+
+		// the filter persisted with the events is used once the in-memory filters are gone,
+		// so events which could not be decoded are never read for other types
+		err = store.SetBytes(
+			context.Background(),
+			store.KeyGenerator.Storage("events"),
+			store.KeyGenerator.BlockHeight(blockHeight),
+			[]byte{0xff},
+		)
+		require.NoError(t, err)
+		store.ResetEventFilters()
+
+		events, err := store.EventsByHeight(context.Background(), blockHeight, "flow.AccountCreated")
+		require.NoError(t, err)
+		assert.Empty(t, events)
+
+		_, err = store.EventsByHeight(context.Background(), blockHeight, "A")
+		assert.Error(t, err)
 	})
 }
 
 // setupStore creates a temporary file for the Sqlite and creates a
 // sqlite.Store instance. The caller is responsible for closing the store
 // and deleting the temporary directory.
+func TestDurability(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("parse", func(t *testing.T) {
+		t.Parallel()
+
+		for mode, expected := range map[string]storage.Durability{
+			"":     storage.DurabilityDefault,
+			"safe": storage.DurabilitySafe,
+			"fast": storage.DurabilityFast,
+		} {
+			durability, err := storage.ParseDurability(mode)
+			require.NoError(t, err)
+			assert.Equal(t, expected, durability)
+		}
+
+		_, err := storage.ParseDurability("eventually")
+		assert.Error(t, err)
+	})
+
+	t.Run("fast mode enables write-ahead log", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		store, err := sqlite.New(dir, sqlite.WithDurability(storage.DurabilityFast))
+		require.NoError(t, err)
+
+		block := flowgo.Block{Header: &flowgo.Header{Height: 1}}
+		require.NoError(t, store.StoreBlock(context.Background(), &block))
+
+		db, err := sql.Open("sqlite", filepath.Join(dir, "emulator.sqlite"))
+		require.NoError(t, err)
+		defer db.Close()
+
+		var journalMode string
+		require.NoError(t, db.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+		assert.Equal(t, "wal", journalMode)
+	})
+
+	t.Run("in-memory ignores durability", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := sqlite.New(sqlite.InMemory, sqlite.WithDurability(storage.DurabilitySafe))
+		require.NoError(t, err)
+	})
+}
+
+func TestCompression(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("parse", func(t *testing.T) {
+		t.Parallel()
+
+		for name, expected := range map[string]storage.Compression{
+			"":       storage.CompressionNone,
+			"none":   storage.CompressionNone,
+			"zstd":   storage.CompressionZstd,
+			"snappy": storage.CompressionSnappy,
+		} {
+			compression, err := storage.ParseCompression(name)
+			require.NoError(t, err)
+			assert.Equal(t, expected, compression)
+		}
+
+		_, err := storage.ParseCompression("gzip")
+		assert.Error(t, err)
+	})
+
+	for _, compression := range []storage.Compression{
+		storage.CompressionZstd,
+		storage.CompressionSnappy,
+	} {
+		compression := compression
+
+		t.Run(compression.String(), func(t *testing.T) {
+			t.Parallel()
+
+			store, err := sqlite.New(sqlite.InMemory, sqlite.WithCompression(compression))
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, store.Close())
+			}()
+
+			block := flowgo.Block{Header: &flowgo.Header{Height: 1}}
+
+			tx := unittest.TransactionFixture()
+			txID := tx.ID()
+
+			eventGenerator := test.EventGenerator()
+			events := make([]flowgo.Event, 0, 20)
+			for i := 0; i < 20; i++ {
+				event, err := convert.SDKEventToFlow(eventGenerator.New())
+				require.NoError(t, err)
+				events = append(events, event)
+			}
+
+			result := types.StorableTransactionResult{
+				ErrorCode:    1101,
+				ErrorMessage: strings.Repeat("execution failed: ", 100),
+				Events:       events,
+				BlockHeight:  block.Header.Height,
+			}
+
+			err = store.CommitBlock(context.Background(), storage.BlockCommit{
+				Block: block,
+				Transactions: map[flowgo.Identifier]*flowgo.TransactionBody{
+					txID: &tx,
+				},
+				TransactionResults: map[flowgo.Identifier]*types.StorableTransactionResult{
+					txID: &result,
+				},
+				ExecutionSnapshot: &snapshot.ExecutionSnapshot{},
+				Events:            events,
+			})
+			require.NoError(t, err)
+
+			assertStored := func() {
+				storedResult, err := store.TransactionResultByID(context.Background(), txID)
+				require.NoError(t, err)
+				assert.Equal(t, result, storedResult)
+
+				storedEvents, err := store.EventsByHeight(context.Background(), block.Header.Height, "")
+				require.NoError(t, err)
+				assert.ElementsMatch(t, events, storedEvents)
+			}
+
+			assertStored()
+
+			// values stored compressed remain readable after compression is disabled
+			store.SetCompression(storage.CompressionNone)
+			assertStored()
+		})
+	}
+
+	t.Run("set while committing", func(t *testing.T) {
+		t.Parallel()
+
+		store, err := sqlite.New(sqlite.InMemory)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, store.Close())
+		}()
+
+		event, err := convert.SDKEventToFlow(test.EventGenerator().New())
+		require.NoError(t, err)
+
+		const blocks = 50
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for height := uint64(1); height <= blocks; height++ {
+				assert.NoError(t, store.InsertEvents(context.Background(), height, []flowgo.Event{event}))
+			}
+		}()
+
+		for _, compression := range []storage.Compression{
+			storage.CompressionZstd,
+			storage.CompressionSnappy,
+			storage.CompressionNone,
+		} {
+			store.SetCompression(compression)
+		}
+		<-done
+
+		assert.Equal(t, storage.CompressionNone, store.Compression())
+		for height := uint64(1); height <= blocks; height++ {
+			events, err := store.EventsByHeight(context.Background(), height, "")
+			require.NoError(t, err)
+			assert.Equal(t, []flowgo.Event{event}, events)
+		}
+	})
+}
+
+func TestSnapshots(t *testing.T) {
+
+	t.Parallel()
+
+	for name, url := range map[string]string{
+		"memory":    sqlite.InMemory,
+		"directory": "",
+	} {
+		name, url := name, url
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if url == "" {
+				url = t.TempDir()
+			}
+
+			store, err := sqlite.New(url)
+			require.NoError(t, err)
+
+			// in-memory snapshots are shared by the process, so names must be unique
+			first := fmt.Sprintf("%s-first", name)
+			second := fmt.Sprintf("%s-second", name)
+
+			require.NoError(t, store.SetBlockHeight(3))
+			require.NoError(t, store.CreateSnapshot(first, "accounts created"))
+
+			require.NoError(t, store.SetBlockHeight(5))
+			require.NoError(t, store.CreateSnapshot(second, ""))
+
+			infos, err := store.SnapshotInfos()
+			require.NoError(t, err)
+			require.Len(t, infos, 2)
+
+			assert.Equal(t, first, infos[0].Name)
+			assert.Equal(t, uint64(3), infos[0].BlockHeight)
+			assert.Equal(t, "accounts created", infos[0].Description)
+			assert.False(t, infos[0].CreatedAt.IsZero())
+
+			assert.Equal(t, second, infos[1].Name)
+			assert.Equal(t, uint64(5), infos[1].BlockHeight)
+			assert.Empty(t, infos[1].Description)
+
+			// the loaded snapshot can not be deleted
+			require.NoError(t, store.LoadSnapshot(second))
+			err = store.DeleteSnapshot(second)
+			assert.ErrorIs(t, err, storage.ErrSnapshotLoaded)
+
+			require.NoError(t, store.DeleteSnapshot(first))
+			assert.Error(t, store.DeleteSnapshot(first))
+
+			snapshots, err := store.Snapshots()
+			require.NoError(t, err)
+			assert.Equal(t, []string{second}, snapshots)
+
+			infos, err = store.SnapshotInfos()
+			require.NoError(t, err)
+			require.Len(t, infos, 1)
+			assert.Equal(t, second, infos[0].Name)
+		})
+	}
+}
+
+func TestMeta(t *testing.T) {
+
+	t.Parallel()
+
+	store, dir := setupStore(t)
+	defer func() {
+		require.NoError(t, store.Close())
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	ctx := context.Background()
+
+	_, err := store.GetMeta(ctx, "config")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	require.NoError(t, store.PutMeta(ctx, "config", []byte("a")))
+	require.NoError(t, store.PutMeta(ctx, "config", []byte("b")))
+
+	value, err := store.GetMeta(ctx, "config")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("b"), value)
+
+	// metadata does not depend on the block height
+	require.NoError(t, store.SetBlockHeight(10))
+
+	value, err = store.GetMeta(ctx, "config")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("b"), value)
+}
+
 func setupStore(t *testing.T) (*sqlite.Store, string) {
 	file, err := os.CreateTemp("", "test.sqlite")
 	require.NoError(t, err)
@@ -489,3 +1084,126 @@ func dirSize(path string) (int64, error) {
 	})
 	return size, err
 }
+
+func TestSchemaVersion(t *testing.T) {
+
+	t.Parallel()
+
+	readVersion := func(t *testing.T, path string) uint64 {
+		db, err := sql.Open("sqlite", path)
+		require.NoError(t, err)
+		defer db.Close()
+
+		var version uint64
+		require.NoError(t, db.QueryRow("SELECT version FROM schemaVersion").Scan(&version))
+		return version
+	}
+
+	t.Run("new storage is stamped with current version", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		_, err := sqlite.New(dir)
+		require.NoError(t, err)
+
+		assert.Equal(t, sqlite.SchemaVersion, readVersion(t, filepath.Join(dir, "emulator.sqlite")))
+	})
+
+	t.Run("unversioned storage is migrated", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "emulator.sqlite")
+
+		db, err := sql.Open("sqlite", path)
+		require.NoError(t, err)
+		_, err = db.Exec("CREATE TABLE ledger(key TEXT, value TEXT, version INTEGER, height INTEGER, UNIQUE(key,version,height))")
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		migrations, err := sqlite.Migrate(dir)
+		require.NoError(t, err)
+		require.Len(t, migrations, 1)
+		assert.Equal(t, uint64(0), migrations[0].From)
+		assert.Equal(t, sqlite.SchemaVersion, migrations[0].To)
+
+		assert.Equal(t, sqlite.SchemaVersion, readVersion(t, path))
+	})
+
+	t.Run("newer storage is refused", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		db, err := sql.Open("sqlite", filepath.Join(dir, "emulator.sqlite"))
+		require.NoError(t, err)
+		_, err = db.Exec(fmt.Sprintf(
+			"CREATE TABLE schemaVersion(version INTEGER); INSERT INTO schemaVersion VALUES (%d)",
+			sqlite.SchemaVersion+1,
+		))
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		_, err = sqlite.New(dir)
+		var versionErr *storage.UnsupportedSchemaVersionError
+		require.ErrorAs(t, err, &versionErr)
+		assert.Equal(t, sqlite.SchemaVersion+1, versionErr.Version)
+	})
+
+	t.Run("version 1 events are re-encoded", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		dir := t.TempDir()
+		path := filepath.Join(dir, "emulator.sqlite")
+
+		store, err := sqlite.New(dir)
+		require.NoError(t, err)
+
+		generator := test.EventGenerator()
+		events := make([]flowgo.Event, 3)
+		for i := range events {
+			event, err := convert.SDKEventToFlow(generator.New())
+			require.NoError(t, err)
+			event.EventIndex = uint32(i)
+			events[i] = event
+		}
+
+		// version 1 stored the events of a block as an array of events
+		legacy, err := cbor.Marshal(events)
+		require.NoError(t, err)
+		err = store.SetBytes(ctx, store.KeyGenerator.Storage("events"), store.KeyGenerator.BlockHeight(1), legacy)
+		require.NoError(t, err)
+		require.NoError(t, store.Close())
+
+		db, err := sql.Open("sqlite", path)
+		require.NoError(t, err)
+		_, err = db.Exec("UPDATE schemaVersion SET version = 1")
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		migrations, err := sqlite.Migrate(dir)
+		require.NoError(t, err)
+		require.Len(t, migrations, 1)
+		assert.Equal(t, uint64(1), migrations[0].From)
+
+		store, err = sqlite.New(dir)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, store.Close())
+		}()
+
+		value, err := store.GetBytes(ctx, store.KeyGenerator.Storage("events"), store.KeyGenerator.BlockHeight(1))
+		require.NoError(t, err)
+		assert.NotEqual(t, legacy, value)
+
+		_, converted, err := storage.ColumnarEvents(value)
+		require.NoError(t, err)
+		assert.False(t, converted)
+
+		migrated, err := store.EventsByHeight(ctx, 1, "")
+		require.NoError(t, err)
+		assert.Equal(t, events, migrated)
+	})
+}
@@ -23,16 +23,28 @@ package util
 
 import (
 	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/storage/checkpoint"
 	"github.com/onflow/flow-emulator/storage/redis"
 	"github.com/onflow/flow-emulator/storage/sqlite"
 )
 
+// the built-in storage backends are available as storage providers too
+func init() {
+	storage.Register("sqlite", func(dsn string) (storage.Store, error) {
+		return NewSqliteStorage(dsn, storage.DurabilityDefault)
+	})
+	storage.Register("redis", NewRedisStorage)
+	storage.Register("checkpoint", func(dsn string) (storage.Store, error) {
+		return checkpoint.New(dsn)
+	})
+}
+
 func CreateDefaultStorage() (storage.Store, error) {
 	return sqlite.New(sqlite.InMemory)
 }
 
-func NewSqliteStorage(url string) (storage.Store, error) {
-	return sqlite.New(url)
+func NewSqliteStorage(url string, durability storage.Durability) (storage.Store, error) {
+	return sqlite.New(url, sqlite.WithDurability(durability))
 }
 
 func NewRedisStorage(url string) (storage.Store, error) {
@@ -27,11 +27,19 @@ import (
 	"github.com/onflow/flow-emulator/storage/redis"
 )
 
+// the built-in storage backends are available as storage providers too
+func init() {
+	storage.Register("sqlite", func(dsn string) (storage.Store, error) {
+		return NewSqliteStorage(dsn, storage.DurabilityDefault)
+	})
+	storage.Register("redis", NewRedisStorage)
+}
+
 func CreateDefaultStorage() (storage.Store, error) {
 	return memstore.New(), nil
 }
 
-func NewSqliteStorage(url string) (storage.Store, error) {
+func NewSqliteStorage(url string, _ storage.Durability) (storage.Store, error) {
 	return memstore.New(), nil
 }
 
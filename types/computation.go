@@ -0,0 +1,82 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/fvm/meter"
+)
+
+// fvmComputationKindNames names the computation kinds metered by the FVM,
+// which Cadence does not know about.
+var fvmComputationKindNames = map[common.ComputationKind]string{
+	environment.ComputationKindHash:                       "Hash",
+	environment.ComputationKindVerifySignature:            "VerifySignature",
+	environment.ComputationKindAddAccountKey:              "AddAccountKey",
+	environment.ComputationKindAddEncodedAccountKey:       "AddEncodedAccountKey",
+	environment.ComputationKindAllocateStorageIndex:       "AllocateStorageIndex",
+	environment.ComputationKindCreateAccount:              "CreateAccount",
+	environment.ComputationKindEmitEvent:                  "EmitEvent",
+	environment.ComputationKindGenerateUUID:               "GenerateUUID",
+	environment.ComputationKindGetAccountAvailableBalance: "GetAccountAvailableBalance",
+	environment.ComputationKindGetAccountBalance:          "GetAccountBalance",
+	environment.ComputationKindGetAccountContractCode:     "GetAccountContractCode",
+	environment.ComputationKindGetAccountContractNames:    "GetAccountContractNames",
+	environment.ComputationKindGetAccountKey:              "GetAccountKey",
+	environment.ComputationKindGetBlockAtHeight:           "GetBlockAtHeight",
+	environment.ComputationKindGetCode:                    "GetCode",
+	environment.ComputationKindGetCurrentBlockHeight:      "GetCurrentBlockHeight",
+	environment.ComputationKindGetStorageCapacity:         "GetStorageCapacity",
+	environment.ComputationKindGetStorageUsed:             "GetStorageUsed",
+	environment.ComputationKindGetValue:                   "GetValue",
+	environment.ComputationKindRemoveAccountContractCode:  "RemoveAccountContractCode",
+	environment.ComputationKindResolveLocation:            "ResolveLocation",
+	environment.ComputationKindRevokeAccountKey:           "RevokeAccountKey",
+	environment.ComputationKindRevokeEncodedAccountKey:    "RevokeEncodedAccountKey",
+	environment.ComputationKindSetValue:                   "SetValue",
+	environment.ComputationKindUpdateAccountContractCode:  "UpdateAccountContractCode",
+	environment.ComputationKindValidatePublicKey:          "ValidatePublicKey",
+	environment.ComputationKindValueExists:                "ValueExists",
+	environment.ComputationKindAccountKeysCount:           "AccountKeysCount",
+	environment.ComputationKindBLSVerifyPOP:               "BLSVerifyPOP",
+	environment.ComputationKindBLSAggregateSignatures:     "BLSAggregateSignatures",
+	environment.ComputationKindBLSAggregatePublicKeys:     "BLSAggregatePublicKeys",
+	environment.ComputationKindGetOrLoadProgram:           "GetOrLoadProgram",
+	environment.ComputationKindGenerateAccountLocalID:     "GenerateAccountLocalID",
+}
+
+// ComputationKindName returns the name of a computation kind metered by
+// Cadence, e.g. Statement, Loop or FunctionInvocation, or by the FVM, e.g.
+// GetValue or SetValue for storage reads and writes.
+func ComputationKindName(kind common.ComputationKind) string {
+	if name, ok := fvmComputationKindNames[kind]; ok {
+		return name
+	}
+	return kind.String()
+}
+
+// ComputationIntensities returns the metered intensities by computation kind name.
+func ComputationIntensities(intensities meter.MeteredComputationIntensities) map[string]uint {
+	result := make(map[string]uint, len(intensities))
+	for kind, intensity := range intensities {
+		result[ComputationKindName(kind)] += intensity
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types_test
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/fvm/meter"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestComputationIntensities(t *testing.T) {
+
+	t.Parallel()
+
+	intensities := types.ComputationIntensities(meter.MeteredComputationIntensities{
+		common.ComputationKindStatement:     5,
+		common.ComputationKindLoop:          2,
+		environment.ComputationKindGetValue: 3,
+		environment.ComputationKindSetValue: 1,
+	})
+
+	assert.Equal(t,
+		map[string]uint{
+			"Statement": 5,
+			"Loop":      2,
+			"GetValue":  3,
+			"SetValue":  1,
+		},
+		intensities,
+	)
+}
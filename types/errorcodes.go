@@ -0,0 +1,155 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
+)
+
+// An ErrorCode classifies a common execution failure. Codes are stable across
+// emulator versions, so that tools can present remediation hints for them.
+type ErrorCode string
+
+const (
+	ErrorCodeMissingCapability        ErrorCode = "missing_capability"
+	ErrorCodeForceNil                 ErrorCode = "force_nil"
+	ErrorCodeTypeMismatch             ErrorCode = "type_mismatch"
+	ErrorCodeInvalidArgument          ErrorCode = "invalid_argument"
+	ErrorCodeStorageCapacityExceeded  ErrorCode = "storage_capacity_exceeded"
+	ErrorCodeComputationLimitExceeded ErrorCode = "computation_limit_exceeded"
+	ErrorCodeInsufficientBalance      ErrorCode = "insufficient_balance"
+	ErrorCodeInvalidSequenceNumber    ErrorCode = "invalid_sequence_number"
+	ErrorCodeInvalidSignature         ErrorCode = "invalid_signature"
+	ErrorCodeAccountNotFound          ErrorCode = "account_not_found"
+	ErrorCodeCheckingFailed           ErrorCode = "checking_failed"
+)
+
+var errorCodeHints = map[ErrorCode]string{
+	ErrorCodeMissingCapability: "A capability or stored value could not be borrowed. " +
+		"Check that the capability is linked at the expected path, with the expected type, in the expected account.",
+	ErrorCodeForceNil: "An optional value was force-unwrapped with ! but is nil. " +
+		"Check why the value is nil, or handle the nil case with ?? or if let.",
+	ErrorCodeTypeMismatch: "A value does not have the expected type. " +
+		"Check casts, and that stored values and capabilities have the types the code expects.",
+	ErrorCodeInvalidArgument: "An argument could not be decoded as the parameter type. " +
+		"Check the number, order and JSON-Cadence encoding of the arguments.",
+	ErrorCodeStorageCapacityExceeded: "An account stores more than its FLOW balance allows. " +
+		"Fund the account, or start the emulator with --storage-limit=false.",
+	ErrorCodeComputationLimitExceeded: "The execution used more computation than its limit. " +
+		"Raise the gas limit of the transaction, up to --transaction-max-gas-limit, or raise --script-gas-limit for scripts.",
+	ErrorCodeInsufficientBalance: "The payer cannot pay the transaction fees. " +
+		"Fund the payer, or start the emulator with --transaction-fees=false.",
+	ErrorCodeInvalidSequenceNumber: "The proposal key sequence number is not the current one. " +
+		"Fetch the account right before signing, and do not send several transactions with the same proposal key concurrently.",
+	ErrorCodeInvalidSignature: "A signature does not match the key or the signed message. " +
+		"Check the key index, the signature and hashing algorithms, and that payer signatures cover the envelope.",
+	ErrorCodeAccountNotFound: "An address does not belong to an account. " +
+		"Check that the address is of the emulator network and that the account was created.",
+	ErrorCodeCheckingFailed: "The code does not type check. " +
+		"Check the errors reported for the code, and that imported contracts have the expected interfaces.",
+}
+
+// Hint returns a remediation hint for failures of the code.
+func (c ErrorCode) Hint() string {
+	return errorCodeHints[c]
+}
+
+// ClassifyError returns the code of the given execution error,
+// or an empty code if it is not one of the classified failures.
+func ClassifyError(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	switch {
+	case fvmerrors.IsStorageCapacityExceededError(err):
+		return ErrorCodeStorageCapacityExceeded
+	case fvmerrors.IsComputationLimitExceededError(err):
+		return ErrorCodeComputationLimitExceeded
+	case fvmerrors.IsInsufficientPayerBalanceError(err):
+		return ErrorCodeInsufficientBalance
+	case fvmerrors.HasErrorCode(err, fvmerrors.ErrCodeInvalidProposalSeqNumberError):
+		return ErrorCodeInvalidSequenceNumber
+	case fvmerrors.HasErrorCode(err, fvmerrors.ErrCodeInvalidProposalSignatureError),
+		fvmerrors.IsInvalidPayloadSignatureError(err),
+		fvmerrors.IsInvalidEnvelopeSignatureError(err):
+		return ErrorCodeInvalidSignature
+	case fvmerrors.IsAccountNotFoundError(err):
+		return ErrorCodeAccountNotFound
+	case fvmerrors.IsInvalidArgumentError(err):
+		return ErrorCodeInvalidArgument
+	}
+
+	var forceNilErr interpreter.ForceNilError
+	if errors.As(err, &forceNilErr) {
+		if isForcedBorrow(forceNilErr) {
+			return ErrorCodeMissingCapability
+		}
+		return ErrorCodeForceNil
+	}
+
+	// capabilities are usually borrowed with e.g. `?? panic("Could not borrow ...")`
+	var panicErr stdlib.PanicError
+	if errors.As(err, &panicErr) && strings.Contains(strings.ToLower(panicErr.Message), "borrow") {
+		return ErrorCodeMissingCapability
+	}
+
+	var forceCastErr interpreter.ForceCastTypeMismatchError
+	var typeMismatchErr interpreter.TypeMismatchError
+	if errors.As(err, &forceCastErr) || errors.As(err, &typeMismatchErr) {
+		return ErrorCodeTypeMismatch
+	}
+
+	var checkerErr *sema.CheckerError
+	if errors.As(err, &checkerErr) {
+		for _, childErr := range checkerErr.ChildErrors() {
+			var semaTypeMismatchErr *sema.TypeMismatchError
+			if errors.As(childErr, &semaTypeMismatchErr) {
+				return ErrorCodeTypeMismatch
+			}
+		}
+		return ErrorCodeCheckingFailed
+	}
+
+	return ""
+}
+
+// isForcedBorrow returns whether the force-unwrapped value is the result of a borrow,
+// e.g. `capability.borrow<&Vault>()!`.
+func isForcedBorrow(err interpreter.ForceNilError) bool {
+	force, ok := err.HasPosition.(*ast.ForceExpression)
+	if !ok {
+		return false
+	}
+
+	invocation, ok := force.Expression.(*ast.InvocationExpression)
+	if !ok {
+		return false
+	}
+
+	member, ok := invocation.InvokedExpression.(*ast.MemberExpression)
+	return ok && member.Identifier.Identifier == "borrow"
+}
@@ -0,0 +1,112 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/stdlib"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-emulator/types"
+)
+
+func TestClassifyError(t *testing.T) {
+
+	t.Parallel()
+
+	cadenceError := func(err error) error {
+		return fvmerrors.NewCadenceRuntimeError(runtime.Error{
+			Err: interpreter.Error{Err: err},
+		})
+	}
+
+	for name, test := range map[string]struct {
+		err  error
+		code types.ErrorCode
+	}{
+		"no error": {
+			err:  nil,
+			code: "",
+		},
+		"force unwrap of nil": {
+			err:  cadenceError(interpreter.ForceNilError{}),
+			code: types.ErrorCodeForceNil,
+		},
+		"force unwrap of a borrow": {
+			err: cadenceError(interpreter.ForceNilError{
+				LocationRange: interpreter.LocationRange{
+					HasPosition: &ast.ForceExpression{
+						Expression: &ast.InvocationExpression{
+							InvokedExpression: &ast.MemberExpression{
+								Identifier: ast.Identifier{Identifier: "borrow"},
+							},
+						},
+					},
+				},
+			}),
+			code: types.ErrorCodeMissingCapability,
+		},
+		"failed borrow": {
+			err:  cadenceError(stdlib.PanicError{Message: "Could not borrow a reference to the vault"}),
+			code: types.ErrorCodeMissingCapability,
+		},
+		"other panic": {
+			err:  cadenceError(stdlib.PanicError{Message: "amount must be positive"}),
+			code: "",
+		},
+		"force cast": {
+			err:  cadenceError(interpreter.ForceCastTypeMismatchError{}),
+			code: types.ErrorCodeTypeMismatch,
+		},
+		"storage capacity exceeded": {
+			err:  fvmerrors.NewStorageCapacityExceededError(flowgo.EmptyAddress, 2, 1),
+			code: types.ErrorCodeStorageCapacityExceeded,
+		},
+		"computation limit exceeded": {
+			err:  fvmerrors.NewComputationLimitExceededError(9999),
+			code: types.ErrorCodeComputationLimitExceeded,
+		},
+		"sequence number": {
+			err:  fvmerrors.NewInvalidProposalSeqNumberError(flowgo.ProposalKey{}, 1),
+			code: types.ErrorCodeInvalidSequenceNumber,
+		},
+		"unclassified": {
+			err:  errors.New("unknown"),
+			code: "",
+		},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			code := types.ClassifyError(test.err)
+			assert.Equal(t, test.code, code)
+			if code != "" {
+				assert.NotEmpty(t, code.Hint())
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 
 	fvmerrors "github.com/onflow/flow-go/fvm/errors"
@@ -28,6 +29,18 @@ import (
 	flowgo "github.com/onflow/flow-go/model/flow"
 )
 
+// Kinds of errors, matching all the errors of the kind with errors.Is.
+var (
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrInternal           = errors.New("internal error")
+	ErrNotFound           = errors.New("not found")
+	ErrBlockNotFound      = errors.New("block not found")
+	ErrInvalidTransaction = errors.New("invalid transaction")
+	ErrPendingBlock       = errors.New("pending block can not be processed")
+	ErrReadOnly           = errors.New("read-only")
+	ErrStorage            = errors.New("storage failure")
+)
+
 type InvalidArgumentError struct {
 	msg string
 }
@@ -40,6 +53,10 @@ func NewInvalidArgumentError(msg string) *InvalidArgumentError {
 	return &InvalidArgumentError{msg: msg}
 }
 
+func (e InvalidArgumentError) Is(target error) bool {
+	return target == ErrInvalidArgument
+}
+
 type InternalError struct {
 	msg string
 }
@@ -52,6 +69,10 @@ func NewInternalError(msg string) *InternalError {
 	return &InternalError{msg: msg}
 }
 
+func (e InternalError) Is(target error) bool {
+	return target == ErrInternal
+}
+
 // A NotFoundError indicates that an entity could not be found.
 type NotFoundError interface {
 	isNotFoundError()
@@ -74,6 +95,10 @@ func (e *BlockNotFoundByHeightError) Error() string {
 	return fmt.Sprintf("could not find block at height %d", e.Height)
 }
 
+func (e *BlockNotFoundByHeightError) Is(target error) bool {
+	return target == ErrNotFound || target == ErrBlockNotFound
+}
+
 // A BlockNotFoundByIDError indicates that a block with the specified ID could not be found.
 type BlockNotFoundByIDError struct {
 	ID flowgo.Identifier
@@ -86,6 +111,64 @@ func (e *BlockNotFoundByIDError) Error() string {
 	return fmt.Sprintf("could not find block with ID %s", e.ID)
 }
 
+func (e *BlockNotFoundByIDError) Is(target error) bool {
+	return target == ErrNotFound || target == ErrBlockNotFound
+}
+
+// A StatePrunedError indicates that the ledger state at a block height was pruned,
+// because only the state of the latest blocks is kept.
+type StatePrunedError struct {
+	Height       uint64
+	OldestHeight uint64
+}
+
+func (e *StatePrunedError) isNotFoundError() {}
+
+func (e *StatePrunedError) Error() string {
+	return fmt.Sprintf(
+		"the state at block height %d is pruned, the oldest height with state is %d",
+		e.Height,
+		e.OldestHeight,
+	)
+}
+
+func (e *StatePrunedError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// A BlockDependenciesNotFoundError indicates that no dependency graph is recorded
+// for a block, e.g. because it was committed before the emulator was started,
+// or too many blocks were committed since.
+type BlockDependenciesNotFoundError struct {
+	BlockID flowgo.Identifier
+}
+
+func (e *BlockDependenciesNotFoundError) isNotFoundError() {}
+
+func (e *BlockDependenciesNotFoundError) Error() string {
+	return fmt.Sprintf("no dependency graph recorded for block with ID %s", e.BlockID)
+}
+
+func (e *BlockDependenciesNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// A ComputationReportNotFoundError indicates that no computation report was
+// recorded for a transaction, e.g. because computation reporting is disabled.
+type ComputationReportNotFoundError struct {
+	TransactionID flowgo.Identifier
+}
+
+func (e *ComputationReportNotFoundError) isNotFoundError() {}
+
+func (e *ComputationReportNotFoundError) Error() string {
+	return fmt.Sprintf("no computation report recorded for transaction with ID %s", e.TransactionID)
+}
+
+func (e *ComputationReportNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // A CollectionNotFoundError indicates that a collection could not be found.
 type CollectionNotFoundError struct {
 	ID flowgo.Identifier
@@ -97,6 +180,10 @@ func (e *CollectionNotFoundError) Error() string {
 	return fmt.Sprintf("could not find collection with ID %s", e.ID)
 }
 
+func (e *CollectionNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // A TransactionNotFoundError indicates that a transaction could not be found.
 type TransactionNotFoundError struct {
 	ID flowgo.Identifier
@@ -108,6 +195,10 @@ func (e *TransactionNotFoundError) Error() string {
 	return fmt.Sprintf("could not find transaction with ID %s", e.ID)
 }
 
+func (e *TransactionNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // An AccountNotFoundError indicates that an account could not be found.
 type AccountNotFoundError struct {
 	Address flowgo.Address
@@ -119,6 +210,10 @@ func (e *AccountNotFoundError) Error() string {
 	return fmt.Sprintf("could not find account with address %s", e.Address)
 }
 
+func (e *AccountNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // A TransactionValidationError indicates that a submitted transaction is invalid.
 type TransactionValidationError interface {
 	isTransactionValidationError()
@@ -135,6 +230,10 @@ func (e *DuplicateTransactionError) Error() string {
 	return fmt.Sprintf("transaction with ID %s has already been submitted", e.TxID)
 }
 
+func (e *DuplicateTransactionError) Is(target error) bool {
+	return target == ErrInvalidTransaction
+}
+
 // IncompleteTransactionError indicates that a transaction is missing one or more required fields.
 type IncompleteTransactionError struct {
 	MissingFields []string
@@ -146,6 +245,10 @@ func (e *IncompleteTransactionError) Error() string {
 	return fmt.Sprintf("transaction is missing required fields: %s", e.MissingFields)
 }
 
+func (e *IncompleteTransactionError) Is(target error) bool {
+	return target == ErrInvalidTransaction
+}
+
 // ExpiredTransactionError indicates that a transaction has expired.
 type ExpiredTransactionError struct {
 	RefHeight, FinalHeight uint64
@@ -157,6 +260,10 @@ func (e *ExpiredTransactionError) Error() string {
 	return fmt.Sprintf("transaction is expired: ref_height=%d final_height=%d", e.RefHeight, e.FinalHeight)
 }
 
+func (e *ExpiredTransactionError) Is(target error) bool {
+	return target == ErrInvalidTransaction
+}
+
 // InvalidTransactionScriptError indicates that a transaction contains an invalid Cadence script.
 type InvalidTransactionScriptError struct {
 	ParserErr error
@@ -168,6 +275,10 @@ func (e *InvalidTransactionScriptError) Error() string {
 	return fmt.Sprintf("failed to parse transaction Cadence script: %s", e.ParserErr)
 }
 
+func (e *InvalidTransactionScriptError) Is(target error) bool {
+	return target == ErrInvalidTransaction
+}
+
 func (e *InvalidTransactionScriptError) Unwrap() error {
 	return e.ParserErr
 }
@@ -184,6 +295,35 @@ func (e *InvalidTransactionGasLimitError) Error() string {
 	return fmt.Sprintf("transaction gas limit (%d) exceeds the maximum gas limit (%d)", e.Actual, e.Maximum)
 }
 
+func (e *InvalidTransactionGasLimitError) Is(target error) bool {
+	return target == ErrInvalidTransaction
+}
+
+// An InvalidSequenceNumberError indicates that the sequence number of a transaction's
+// proposal key is not the one the next transaction proposed with the key must use.
+type InvalidSequenceNumberError struct {
+	Address  flowgo.Address
+	KeyIndex uint64
+	Expected uint64
+	Provided uint64
+}
+
+func (e *InvalidSequenceNumberError) isTransactionValidationError() {}
+
+func (e *InvalidSequenceNumberError) Error() string {
+	return fmt.Sprintf(
+		"invalid proposal key %d of account %s: expected sequence number %d, provided %d",
+		e.KeyIndex,
+		e.Address.HexWithPrefix(),
+		e.Expected,
+		e.Provided,
+	)
+}
+
+func (e *InvalidSequenceNumberError) Is(target error) bool {
+	return target == ErrInvalidTransaction
+}
+
 // An InvalidStateVersionError indicates that a state version hash provided is invalid.
 type InvalidStateVersionError struct {
 	Version crypto.Hash
@@ -202,6 +342,10 @@ func (e *PendingBlockCommitBeforeExecutionError) Error() string {
 	return fmt.Sprintf("pending block with ID %s cannot be committed before execution", e.BlockID)
 }
 
+func (e *PendingBlockCommitBeforeExecutionError) Is(target error) bool {
+	return target == ErrPendingBlock
+}
+
 // A PendingBlockMidExecutionError indicates that the current pending block is mid-execution.
 type PendingBlockMidExecutionError struct {
 	BlockID flowgo.Identifier
@@ -211,6 +355,23 @@ func (e *PendingBlockMidExecutionError) Error() string {
 	return fmt.Sprintf("pending block with ID %s is currently being executed", e.BlockID)
 }
 
+func (e *PendingBlockMidExecutionError) Is(target error) bool {
+	return target == ErrPendingBlock
+}
+
+// A PendingBlockNotEmptyError indicates that the current pending block holds transactions.
+type PendingBlockNotEmptyError struct {
+	BlockID flowgo.Identifier
+}
+
+func (e *PendingBlockNotEmptyError) Error() string {
+	return fmt.Sprintf("pending block with ID %s contains transactions, commit it first", e.BlockID)
+}
+
+func (e *PendingBlockNotEmptyError) Is(target error) bool {
+	return target == ErrPendingBlock
+}
+
 // A PendingBlockTransactionsExhaustedError indicates that the current pending block has finished executing (no more transactions to execute).
 type PendingBlockTransactionsExhaustedError struct {
 	BlockID flowgo.Identifier
@@ -220,11 +381,30 @@ func (e *PendingBlockTransactionsExhaustedError) Error() string {
 	return fmt.Sprintf("pending block with ID %s contains no more transactions to execute", e.BlockID)
 }
 
+func (e *PendingBlockTransactionsExhaustedError) Is(target error) bool {
+	return target == ErrPendingBlock
+}
+
+// A ReadOnlyError indicates that a write was attempted on a read-only emulator.
+type ReadOnlyError struct{}
+
+func (e *ReadOnlyError) Error() string {
+	return "emulator is read-only: transactions must be sent to the primary instance"
+}
+
+func (e *ReadOnlyError) Is(target error) bool {
+	return target == ErrReadOnly
+}
+
 // A StorageError indicates that an error occurred in the storage provider.
 type StorageError struct {
 	inner error
 }
 
+func NewStorageError(err error) *StorageError {
+	return &StorageError{inner: err}
+}
+
 func (e *StorageError) Error() string {
 	return fmt.Sprintf("storage failure: %v", e.inner)
 }
@@ -233,6 +413,25 @@ func (e *StorageError) Unwrap() error {
 	return e.inner
 }
 
+func (e *StorageError) Is(target error) bool {
+	return target == ErrStorage
+}
+
+// A ScriptInterruptedError indicates that a script was aborted before it completed,
+// either because it exceeded the script timeout or because the caller cancelled it.
+type ScriptInterruptedError struct {
+	ScriptID flowgo.Identifier
+	Err      error
+}
+
+func (e *ScriptInterruptedError) Error() string {
+	return fmt.Sprintf("script %s was interrupted: %v", e.ScriptID, e.Err)
+}
+
+func (e *ScriptInterruptedError) Unwrap() error {
+	return e.Err
+}
+
 // An ExecutionError occurs when a transaction fails to execute.
 type ExecutionError struct {
 	Code    int
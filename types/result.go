@@ -20,6 +20,7 @@ package types
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/onflow/cadence"
 	flowsdk "github.com/onflow/flow-go-sdk"
@@ -34,17 +35,31 @@ type StorableTransactionResult struct {
 	Events       []flowgo.Event
 	BlockID      flowgo.Identifier
 	BlockHeight  uint64
+	// CollectionID is the ID of the collection which includes the transaction.
+	CollectionID flowgo.Identifier
+	// TransactionIndex is the index of the transaction in its block.
+	TransactionIndex uint32
+	// EmulatorErrorCode classifies the error of the transaction, if any.
+	EmulatorErrorCode ErrorCode
+	// RequestID is the ID of the request which submitted the transaction, if any.
+	RequestID string
 }
 
 // A TransactionResult is the result of executing a transaction.
 type TransactionResult struct {
 	TransactionID   flowsdk.Identifier
 	ComputationUsed uint64
-	MemoryEstimate  uint64
-	Error           error
-	Logs            []string
-	Events          []flowsdk.Event
-	Debug           *TransactionResultDebug
+	// ComputationIntensities breaks the computation used down by computation kind name.
+	ComputationIntensities map[string]uint
+	MemoryEstimate         uint64
+	Error                  error
+	Logs                   []string
+	Events                 []flowsdk.Event
+	Debug                  *TransactionResultDebug
+	// ErrorCode classifies the error, if any.
+	ErrorCode ErrorCode
+	// RequestID is the ID of the request which submitted the transaction, if any.
+	RequestID string
 }
 
 // Succeeded returns true if the transaction executed without errors.
@@ -105,6 +120,8 @@ type ScriptResult struct {
 	Events          []flowsdk.Event
 	ComputationUsed uint64
 	MemoryEstimate  uint64
+	// ErrorCode classifies the error, if any.
+	ErrorCode ErrorCode
 }
 
 // Succeeded returns true if the script executed without errors.
@@ -116,3 +133,49 @@ func (r ScriptResult) Succeeded() bool {
 func (r ScriptResult) Reverted() bool {
 	return !r.Succeeded()
 }
+
+// TruncatedMarker replaces the middle of error messages longer than their limit.
+const TruncatedMarker = " ...[truncated]... "
+
+// TruncateErrorMessage shortens messages longer than maxLength bytes the way
+// Flow nodes do, keeping their start and end around TruncatedMarker.
+// Messages are cut between runes, so they may be slightly shorter than maxLength.
+// Messages are not truncated if maxLength is zero.
+func TruncateErrorMessage(message string, maxLength int) string {
+	if maxLength <= 0 || len(message) <= maxLength {
+		return message
+	}
+	if maxLength <= len(TruncatedMarker) {
+		return message[:runeStartBefore(message, maxLength)]
+	}
+
+	tail := (maxLength - len(TruncatedMarker)) / 2
+	head := maxLength - len(TruncatedMarker) - tail
+	return message[:runeStartBefore(message, head)] +
+		TruncatedMarker +
+		message[runeStartAfter(message, len(message)-tail):]
+}
+
+// runeStartBefore returns the start of the rune at the given byte offset,
+// moving back over the continuation bytes of a multi-byte rune.
+func runeStartBefore(message string, offset int) int {
+	for offset > 0 && !utf8.RuneStart(message[offset]) {
+		offset--
+	}
+	return offset
+}
+
+// runeStartAfter returns the start of the first rune at or after the given byte offset.
+func runeStartAfter(message string, offset int) int {
+	for offset < len(message) && !utf8.RuneStart(message[offset]) {
+		offset++
+	}
+	return offset
+}
+
+// TransactionError describes the error of a committed transaction.
+type TransactionError struct {
+	Code    ErrorCode `json:"code,omitempty"`
+	Hint    string    `json:"hint,omitempty"`
+	Message string    `json:"message"`
+}
@@ -20,7 +20,9 @@ package types_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/onflow/cadence"
 	flowsdk "github.com/onflow/flow-go-sdk"
@@ -83,3 +85,34 @@ func TestResult(t *testing.T) {
 		assert.False(t, srReverted.Succeeded())
 	})
 }
+
+func TestTruncateErrorMessage(t *testing.T) {
+
+	t.Parallel()
+
+	message := strings.Repeat("a", 50) + strings.Repeat("b", 50)
+
+	assert.Equal(t, message, types.TruncateErrorMessage(message, 0))
+	assert.Equal(t, message, types.TruncateErrorMessage(message, 100))
+
+	truncated := types.TruncateErrorMessage(message, 40)
+	assert.Len(t, truncated, 40)
+	assert.True(t, strings.HasPrefix(truncated, "aaaaaaaaaaa"+types.TruncatedMarker))
+	assert.True(t, strings.HasSuffix(truncated, types.TruncatedMarker+"bbbbbbbbbb"))
+
+	assert.Equal(t, "aaaaa", types.TruncateErrorMessage(message, 5))
+
+	t.Run("multi-byte runes", func(t *testing.T) {
+		// three bytes per rune
+		message := strings.Repeat("€", 100)
+
+		for maxLength := 1; maxLength < 60; maxLength++ {
+			truncated := types.TruncateErrorMessage(message, maxLength)
+			assert.True(t, utf8.ValidString(truncated), "max length %d", maxLength)
+			assert.LessOrEqual(t, len(truncated), maxLength)
+		}
+
+		truncated := types.TruncateErrorMessage(message, 40)
+		assert.Equal(t, "€€€"+types.TruncatedMarker+"€€€", truncated)
+	})
+}
@@ -0,0 +1,102 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package eventencoding lets a client choose the encoding of the event payloads
+// returned by the Access API, like the event_encoding_version of newer Access APIs.
+package eventencoding
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Version is an encoding of event payloads.
+type Version int
+
+const (
+	// JSONCDCV0 is the JSON-Cadence Data Interchange Format, the default.
+	JSONCDCV0 Version = iota
+	// CCFV0 is the Cadence Compact Format, the encoding events are stored in.
+	CCFV0
+)
+
+const (
+	// QueryParameter is the query parameter of HTTP requests choosing the event encoding.
+	QueryParameter = "event_encoding_version"
+
+	// Header is the header of requests choosing the event encoding, e.g. in gRPC metadata.
+	Header = "X-Emulator-Event-Encoding-Version"
+)
+
+func (v Version) String() string {
+	switch v {
+	case CCFV0:
+		return "CCF_V0"
+	default:
+		return "JSON_CDC_V0"
+	}
+}
+
+type contextKey struct{}
+
+// WithContext returns a copy of the context choosing the given event encoding.
+func WithContext(ctx context.Context, version Version) context.Context {
+	return context.WithValue(ctx, contextKey{}, version)
+}
+
+// FromContext returns the event encoding chosen by the context, JSON-CDC by default.
+func FromContext(ctx context.Context) Version {
+	version, _ := ctx.Value(contextKey{}).(Version)
+	return version
+}
+
+// FromHeaders returns the event encoding chosen by a request with the given headers,
+// and false if it chooses none.
+func FromHeaders(header func(name string) string) (Version, bool) {
+	return Parse(header(Header))
+}
+
+// Handler serves HTTP requests with the given handler, choosing the event encoding
+// of the requests with the query parameter or the header.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, ok := Parse(r.URL.Query().Get(QueryParameter))
+		if !ok {
+			version, ok = FromHeaders(r.Header.Get)
+		}
+		if ok {
+			r = r.WithContext(WithContext(r.Context(), version))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Parse returns the event encoding of a value of the parameter: the name of the
+// version, as in the EventEncodingVersion enum of the Access API, or its number.
+// It returns false for an empty or invalid value.
+func Parse(value string) (Version, bool) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "JSON_CDC_V0", "JSON_CDC", "0":
+		return JSONCDCV0, true
+	case "CCF_V0", "CCF", "1":
+		return CCFV0, true
+	default:
+		return JSONCDCV0, false
+	}
+}
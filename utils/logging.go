@@ -23,6 +23,7 @@ import (
 
 	"github.com/logrusorgru/aurora"
 	"github.com/onflow/flow-emulator/types"
+	"github.com/onflow/flow-emulator/utils/requestid"
 	sdk "github.com/onflow/flow-go-sdk"
 	"github.com/rs/zerolog"
 )
@@ -48,10 +49,25 @@ func PrintScriptResult(logger *zerolog.Logger, result *types.ScriptResult) {
 			logPrefix("ERR", result.ScriptID, aurora.RedFg),
 			result.Error.Error(),
 		)
+
+		printErrorHint(logger, result.ErrorCode)
+	}
+}
+
+// printErrorHint logs the remediation hint of classified errors.
+func printErrorHint(logger *zerolog.Logger, code types.ErrorCode) {
+	if code == "" {
+		return
 	}
+	logger.Warn().Str("errorCode", string(code)).Msgf("💡  %s", code.Hint())
 }
 
 func PrintTransactionResult(logger *zerolog.Logger, result *types.TransactionResult) {
+	if result.RequestID != "" {
+		requestLogger := logger.With().Str(requestid.LogField, result.RequestID).Logger()
+		logger = &requestLogger
+	}
+
 	if result.Succeeded() {
 		logger.Debug().
 			Str("txID", result.TransactionID.String()).
@@ -81,6 +97,8 @@ func PrintTransactionResult(logger *zerolog.Logger, result *types.TransactionRes
 			result.Error.Error(),
 		)
 
+		printErrorHint(logger, result.ErrorCode)
+
 		if result.Debug != nil {
 			logger.Debug().Fields(result.Debug.Meta).Msgf("%s %s", "❗  Transaction Signature Error", result.Debug.Message)
 		}
@@ -0,0 +1,72 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pending lets a client query the state of the emulator as it is once
+// the transactions of the pending block are executed, before they are committed.
+package pending
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// QueryParameter is the query parameter of HTTP requests querying the pending state.
+	QueryParameter = "pending"
+
+	// Header is the header of requests querying the pending state, e.g. in gRPC metadata.
+	Header = "X-Emulator-Pending"
+)
+
+type contextKey struct{}
+
+// WithContext returns a copy of the context querying the pending state.
+func WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, true)
+}
+
+// FromContext returns true if the context queries the pending state.
+func FromContext(ctx context.Context) bool {
+	pending, _ := ctx.Value(contextKey{}).(bool)
+	return pending
+}
+
+// FromHeaders returns true if a request with the given headers queries the pending state.
+func FromHeaders(header func(name string) string) bool {
+	return parse(header(Header))
+}
+
+// Handler serves HTTP requests with the given handler, querying the pending
+// state for the requests with the query parameter or the header set to true.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if parse(r.URL.Query().Get(QueryParameter)) || FromHeaders(r.Header.Get) {
+			r = r.WithContext(WithContext(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parse returns true for the values of the parameter enabling pending queries.
+// Invalid values are ignored.
+func parse(value string) bool {
+	pending, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err == nil && pending
+}
@@ -0,0 +1,109 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package requestid identifies client requests, so that the logs of a request
+// and of the executions it caused can be correlated.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// Header is the header clients set to choose the ID of a request.
+	// The ID is returned in the same header of the response.
+	Header = "X-Request-Id"
+	// TraceParentHeader is the W3C trace context header. Its trace ID is used as
+	// the request ID if no request ID header is set.
+	TraceParentHeader = "Traceparent"
+
+	// LogField is the log field of request IDs.
+	LogField = "requestID"
+
+	// maxLength bounds client provided IDs, which end up in logs and storage.
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// WithContext returns a copy of the context carrying the request ID.
+func WithContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, requestID)
+}
+
+// FromContext returns the request ID carried by the context, or an empty string.
+func FromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(contextKey{}).(string)
+	return requestID
+}
+
+// FromHeaders returns the ID of a request with the given headers: the request ID
+// header if set, else the trace ID of the trace parent header, else a new ID.
+func FromHeaders(header func(name string) string) string {
+	if requestID := strings.TrimSpace(header(Header)); requestID != "" {
+		if len(requestID) > maxLength {
+			requestID = requestID[:maxLength]
+		}
+		return requestID
+	}
+
+	// version-traceid-parentid-flags, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+	parts := strings.Split(strings.TrimSpace(header(TraceParentHeader)), "-")
+	if len(parts) == 4 && len(parts[1]) == 32 {
+		if _, err := hex.DecodeString(parts[1]); err == nil {
+			return strings.ToLower(parts[1])
+		}
+	}
+
+	return New()
+}
+
+// New returns a random request ID.
+func New() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// Logger returns the logger with the request ID of the context as a field, if any.
+func Logger(ctx context.Context, logger *zerolog.Logger) *zerolog.Logger {
+	requestID := FromContext(ctx)
+	if requestID == "" {
+		return logger
+	}
+	requestLogger := logger.With().Str(LogField, requestID).Logger()
+	return &requestLogger
+}
+
+// Handler serves HTTP requests with the given handler, identifying each request
+// in its context and in the response header. The request header is set as well,
+// so that wrapped gRPC-Web servers use the same ID.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := FromHeaders(r.Header.Get)
+		r.Header.Set(Header, requestID)
+		w.Header().Set(Header, requestID)
+		next.ServeHTTP(w, r.WithContext(WithContext(r.Context(), requestID)))
+	})
+}
@@ -0,0 +1,116 @@
+/*
+ * Flow Emulator
+ *
+ * Copyright Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package timetravel lets a client session query the state of the emulator at
+// a past block height, without affecting the queries of other clients.
+package timetravel
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// Header is the header identifying the session of a client.
+	Header = "X-Emulator-Session"
+
+	// maxLength bounds client provided session IDs, which are kept in memory.
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// WithContext returns a copy of the context carrying the session ID.
+func WithContext(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, contextKey{}, session)
+}
+
+// FromContext returns the session ID carried by the context, or an empty string.
+func FromContext(ctx context.Context) string {
+	session, _ := ctx.Value(contextKey{}).(string)
+	return session
+}
+
+// FromHeaders returns the session ID of a request with the given headers,
+// or an empty string if the request is not part of a session.
+func FromHeaders(header func(name string) string) string {
+	session := strings.TrimSpace(header(Header))
+	if len(session) > maxLength {
+		session = session[:maxLength]
+	}
+	return session
+}
+
+// Handler serves HTTP requests with the given handler, carrying the session ID
+// of each request, if any, in its context.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := FromHeaders(r.Header.Get)
+		if session != "" {
+			r = r.WithContext(WithContext(r.Context(), session))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Sessions are the block heights chosen by client sessions.
+//
+// Sessions are safe for concurrent use.
+type Sessions struct {
+	mu      sync.RWMutex
+	heights map[string]uint64
+}
+
+// NewSessions returns sessions without any chosen height.
+func NewSessions() *Sessions {
+	return &Sessions{
+		heights: map[string]uint64{},
+	}
+}
+
+// Set switches the read-only queries of the session to the given height.
+func (s *Sessions) Set(session string, height uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heights[session] = height
+}
+
+// Clear switches the read-only queries of the session back to the latest block.
+func (s *Sessions) Clear(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.heights, session)
+}
+
+// Height returns the height chosen by the session, if any.
+func (s *Sessions) Height(session string) (uint64, bool) {
+	if session == "" {
+		return 0, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	height, ok := s.heights[session]
+	return height, ok
+}
+
+// HeightFromContext returns the height chosen by the session of the context, if any.
+func (s *Sessions) HeightFromContext(ctx context.Context) (uint64, bool) {
+	return s.Height(FromContext(ctx))
+}
@@ -44,5 +44,7 @@ func StorableTransactionResultFixture() types.StorableTransactionResult {
 			eventA,
 			eventB,
 		},
+		BlockHeight:      5,
+		TransactionIndex: 2,
 	}
 }